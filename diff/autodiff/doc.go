@@ -0,0 +1,21 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package autodiff provides functions to compute derivatives of functions
+// exactly, up to floating point round-off, by automatic differentiation.
+//
+// Reverse mode differentiation records the operations of a function written
+// in terms of Var values on a Tape, and then propagates derivatives back
+// through the recorded operations. A single reverse sweep computes the
+// gradient of a scalar function, whatever the number of variables.
+//
+// Forward mode differentiation evaluates a function written in terms of
+// the dual and hyperdual numbers of the num/dual and num/hyperdual packages
+// with one input direction seeded at a time. It needs one evaluation per
+// variable for a gradient or Jacobian, and one per pair of variables for
+// a Hessian.
+//
+// Problem and HyperdualProblem construct an optimize.Problem whose Grad,
+// and Hess for HyperdualProblem, are computed by automatic differentiation.
+package autodiff // import "gonum.org/v1/gonum/diff/autodiff"
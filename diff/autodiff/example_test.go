@@ -0,0 +1,44 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/diff/autodiff"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleGradient() {
+	// f(x, y) = x² y + sin(y)
+	f := func(x []autodiff.Var) autodiff.Var {
+		return autodiff.Add(autodiff.Mul(autodiff.Mul(x[0], x[0]), x[1]), autodiff.Sin(x[1]))
+	}
+	grad := make([]float64, 2)
+	fx := autodiff.Gradient(grad, f, []float64{3, 0})
+	fmt.Printf("f = %.4f, ∇f = [%.4f %.4f]\n", fx, grad[0], grad[1])
+
+	// Output:
+	// f = 0.0000, ∇f = [0.0000 10.0000]
+}
+
+func ExampleProblem() {
+	// Minimize the Rosenbrock function with the gradient computed by
+	// reverse mode automatic differentiation.
+	f := func(x []autodiff.Var) autodiff.Var {
+		a := autodiff.Sub(x[1], autodiff.Mul(x[0], x[0]))
+		b := autodiff.Sub(autodiff.Const(1), x[0])
+		return autodiff.Add(autodiff.Scale(100, autodiff.Mul(a, a)), autodiff.Mul(b, b))
+	}
+	result, err := optimize.Minimize(autodiff.Problem(f), []float64{-1.2, 1}, nil, &optimize.BFGS{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = [%.6f %.6f]\n", result.X[0], result.X[1])
+
+	// Output:
+	// x = [1.000000 1.000000]
+}
@@ -0,0 +1,104 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+)
+
+// GradientForward computes the gradient of the scalar function f at x by
+// forward mode automatic differentiation, stores the result in dst and
+// returns f(x). f is evaluated once for each element of x, with the
+// infinitesimal part of that element set to one. GradientForward panics if
+// len(dst) != len(x).
+func GradientForward(dst []float64, f func(x []dual.Number) dual.Number, x []float64) (fx float64) {
+	if len(dst) != len(x) {
+		panic(badLength)
+	}
+	xd := make([]dual.Number, len(x))
+	for i, v := range x {
+		xd[i].Real = v
+	}
+	if len(x) == 0 {
+		return f(xd).Real
+	}
+	for i := range x {
+		xd[i].Emag = 1
+		y := f(xd)
+		xd[i].Emag = 0
+		dst[i] = y.Emag
+		fx = y.Real
+	}
+	return fx
+}
+
+// JacobianForward computes the Jacobian of the vector function f at x by
+// forward mode automatic differentiation and stores the result in dst. f
+// must store its m outputs in y. f is evaluated once for each element of x,
+// computing one column of the Jacobian, so JacobianForward is efficient
+// when len(x) is smaller than m.
+//
+// dst must be non-nil and have len(x) columns, and the number of rows of
+// dst is the number of outputs of f, otherwise JacobianForward will panic.
+func JacobianForward(dst *mat.Dense, f func(y, x []dual.Number), x []float64) {
+	m, n := dst.Dims()
+	if n != len(x) {
+		panic(badLength)
+	}
+	xd := make([]dual.Number, n)
+	for i, v := range x {
+		xd[i].Real = v
+	}
+	y := make([]dual.Number, m)
+	for j := range x {
+		xd[j].Emag = 1
+		f(y, xd)
+		xd[j].Emag = 0
+		for i, v := range y {
+			dst.Set(i, j, v.Emag)
+		}
+	}
+}
+
+// Hessian computes the Hessian of the scalar function f at x by forward
+// mode automatic differentiation with hyperdual numbers, stores the result
+// in dst and returns f(x). f is evaluated once for each pair of elements
+// of x, that is n(n+1)/2 times. If grad is not nil, the gradient of f is
+// also stored in grad, which must then have length len(x).
+//
+// If dst is empty it will be resized to the correct dimensions, otherwise
+// the dimensions of dst must match the length of x or Hessian will panic.
+func Hessian(dst *mat.SymDense, grad []float64, f func(x []hyperdual.Number) hyperdual.Number, x []float64) (fx float64) {
+	n := len(x)
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(n)
+	} else if dst.Symmetric() != n {
+		panic(badLength)
+	}
+	if grad != nil && len(grad) != n {
+		panic(badLength)
+	}
+	xh := make([]hyperdual.Number, n)
+	for i, v := range x {
+		xh[i].Real = v
+	}
+	for i := 0; i < n; i++ {
+		xh[i].E1mag = 1
+		for j := i; j < n; j++ {
+			xh[j].E2mag = 1
+			y := f(xh)
+			xh[j].E2mag = 0
+			dst.SetSym(i, j, y.E1E2mag)
+			if i == j && grad != nil {
+				grad[i] = y.E1mag
+			}
+			fx = y.Real
+		}
+		xh[i].E1mag = 0
+	}
+	return fx
+}
@@ -0,0 +1,118 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+)
+
+func rosenbrockDual(x []dual.Number) dual.Number {
+	var sum dual.Number
+	for i := 0; i < len(x)-1; i++ {
+		a := dual.Sub(x[i+1], dual.Mul(x[i], x[i]))
+		b := dual.Sub(dual.Number{Real: 1}, x[i])
+		sum = dual.Add(sum, dual.Add(dual.Scale(100, dual.Mul(a, a)), dual.Mul(b, b)))
+	}
+	return sum
+}
+
+func rosenbrockHyperdual(x []hyperdual.Number) hyperdual.Number {
+	var sum hyperdual.Number
+	for i := 0; i < len(x)-1; i++ {
+		a := hyperdual.Sub(x[i+1], hyperdual.Mul(x[i], x[i]))
+		b := hyperdual.Sub(hyperdual.Number{Real: 1}, x[i])
+		sum = hyperdual.Add(sum, hyperdual.Add(hyperdual.Scale(100, hyperdual.Mul(a, a)), hyperdual.Mul(b, b)))
+	}
+	return sum
+}
+
+func rosenbrockHess(dst *mat.SymDense, x []float64) {
+	n := len(x)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			dst.SetSym(i, j, 0)
+		}
+	}
+	for i := 0; i < n-1; i++ {
+		dst.SetSym(i, i, dst.At(i, i)+1200*x[i]*x[i]-400*x[i+1]+2)
+		dst.SetSym(i, i+1, -400*x[i])
+		dst.SetSym(i+1, i+1, dst.At(i+1, i+1)+200)
+	}
+}
+
+func polarDual(y, x []dual.Number) {
+	r := dual.Mul(x[3], x[0])
+	st := dual.Sin(x[1])
+	y[0] = dual.Mul(r, dual.Mul(st, dual.Cos(x[2])))
+	y[1] = dual.Mul(r, dual.Mul(st, dual.Sin(x[2])))
+	y[2] = dual.Mul(r, dual.Cos(x[1]))
+	y[3] = dual.Mul(dual.Log(x[0]), dual.Exp(dual.Scale(-1, x[2])))
+}
+
+func TestGradientForward(t *testing.T) {
+	t.Parallel()
+	for _, x := range [][]float64{
+		{-1.2, 1},
+		{0.3, -0.7, 2.1, 1.4, -0.2},
+	} {
+		got := make([]float64, len(x))
+		fx := GradientForward(got, rosenbrockDual, x)
+		want := make([]float64, len(x))
+		wantF := Gradient(want, rosenbrock, x)
+		if !floats.EqualApprox(got, want, 1e-12) {
+			t.Errorf("unexpected gradient at %v: got %v, want %v", x, got, want)
+		}
+		if fx != wantF {
+			t.Errorf("unexpected function value at %v: got %v, want %v", x, fx, wantF)
+		}
+	}
+}
+
+func TestJacobianForward(t *testing.T) {
+	t.Parallel()
+	for _, x := range [][]float64{
+		{1, 0.5, 0.2, 2},
+		{2.5, 1.2, -0.7, 0.5},
+	} {
+		got := mat.NewDense(4, 4, nil)
+		JacobianForward(got, polarDual, x)
+		want := mat.NewDense(4, 4, nil)
+		Jacobian(want, polar, x)
+		if !mat.EqualApprox(got, want, 1e-12) {
+			t.Errorf("unexpected Jacobian at %v:\ngot  %v\nwant %v", x, mat.Formatted(got), mat.Formatted(want))
+		}
+	}
+}
+
+func TestHessian(t *testing.T) {
+	t.Parallel()
+	for _, x := range [][]float64{
+		{-1.2, 1},
+		{0.3, -0.7, 2.1, 1.4, -0.2},
+	} {
+		n := len(x)
+		var got mat.SymDense
+		grad := make([]float64, n)
+		fx := Hessian(&got, grad, rosenbrockHyperdual, x)
+		want := mat.NewSymDense(n, nil)
+		rosenbrockHess(want, x)
+		if !mat.EqualApprox(&got, want, 1e-12) {
+			t.Errorf("unexpected Hessian at %v:\ngot  %v\nwant %v", x, mat.Formatted(&got), mat.Formatted(want))
+		}
+		wantGrad := make([]float64, n)
+		wantF := Gradient(wantGrad, rosenbrock, x)
+		if !floats.EqualApprox(grad, wantGrad, 1e-12) {
+			t.Errorf("unexpected gradient at %v: got %v, want %v", x, grad, wantGrad)
+		}
+		if fx != wantF {
+			t.Errorf("unexpected function value at %v: got %v, want %v", x, fx, wantF)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import "math"
+
+// Add returns the sum of x and y.
+func Add(x, y Var) Var {
+	return binary(x, y, x.value+y.value, 1, 1)
+}
+
+// Sub returns the difference of x and y, x-y.
+func Sub(x, y Var) Var {
+	return binary(x, y, x.value-y.value, 1, -1)
+}
+
+// Mul returns the product of x and y.
+func Mul(x, y Var) Var {
+	return binary(x, y, x.value*y.value, y.value, x.value)
+}
+
+// Div returns the quotient of x and y, x/y.
+func Div(x, y Var) Var {
+	r := x.value / y.value
+	return binary(x, y, r, 1/y.value, -r/y.value)
+}
+
+// Sum returns the sum of the values in x.
+func Sum(x ...Var) Var {
+	var s Var
+	for _, v := range x {
+		s = Add(s, v)
+	}
+	return s
+}
+
+// Neg returns -x.
+func Neg(x Var) Var {
+	return unary(x, -x.value, -1)
+}
+
+// Scale returns x scaled by f.
+func Scale(f float64, x Var) Var {
+	return unary(x, f*x.value, f)
+}
+
+// Inv returns the inverse of x, 1/x.
+func Inv(x Var) Var {
+	r := 1 / x.value
+	return unary(x, r, -r*r)
+}
+
+// Abs returns the absolute value of x. The derivative of Abs at zero is
+// taken to be zero.
+func Abs(x Var) Var {
+	var d float64
+	switch {
+	case x.value > 0:
+		d = 1
+	case x.value < 0:
+		d = -1
+	}
+	return unary(x, math.Abs(x.value), d)
+}
+
+// Sqrt returns the square root of x.
+func Sqrt(x Var) Var {
+	r := math.Sqrt(x.value)
+	return unary(x, r, 0.5/r)
+}
+
+// Exp returns e**x, the base-e exponential of x.
+func Exp(x Var) Var {
+	r := math.Exp(x.value)
+	return unary(x, r, r)
+}
+
+// Log returns the natural logarithm of x.
+func Log(x Var) Var {
+	return unary(x, math.Log(x.value), 1/x.value)
+}
+
+// PowReal returns x**p, the base-x exponential of the constant p.
+func PowReal(x Var, p float64) Var {
+	if p == 0 {
+		return Const(1)
+	}
+	return unary(x, math.Pow(x.value, p), p*math.Pow(x.value, p-1))
+}
+
+// Pow returns x**p, the base-x exponential of p. The derivative with
+// respect to p is only defined for positive x.
+func Pow(x, p Var) Var {
+	r := math.Pow(x.value, p.value)
+	dx := p.value * math.Pow(x.value, p.value-1)
+	var dp float64
+	if p.tape != nil {
+		dp = r * math.Log(x.value)
+	}
+	return binary(x, p, r, dx, dp)
+}
+
+// Sin returns the sine of x.
+func Sin(x Var) Var {
+	s, c := math.Sincos(x.value)
+	return unary(x, s, c)
+}
+
+// Cos returns the cosine of x.
+func Cos(x Var) Var {
+	s, c := math.Sincos(x.value)
+	return unary(x, c, -s)
+}
+
+// Tan returns the tangent of x.
+func Tan(x Var) Var {
+	r := math.Tan(x.value)
+	return unary(x, r, 1+r*r)
+}
+
+// Asin returns the inverse sine of x.
+func Asin(x Var) Var {
+	return unary(x, math.Asin(x.value), 1/math.Sqrt(1-x.value*x.value))
+}
+
+// Acos returns the inverse cosine of x.
+func Acos(x Var) Var {
+	return unary(x, math.Acos(x.value), -1/math.Sqrt(1-x.value*x.value))
+}
+
+// Atan returns the inverse tangent of x.
+func Atan(x Var) Var {
+	return unary(x, math.Atan(x.value), 1/(1+x.value*x.value))
+}
+
+// Sinh returns the hyperbolic sine of x.
+func Sinh(x Var) Var {
+	return unary(x, math.Sinh(x.value), math.Cosh(x.value))
+}
+
+// Cosh returns the hyperbolic cosine of x.
+func Cosh(x Var) Var {
+	return unary(x, math.Cosh(x.value), math.Sinh(x.value))
+}
+
+// Tanh returns the hyperbolic tangent of x.
+func Tanh(x Var) Var {
+	r := math.Tanh(x.value)
+	return unary(x, r, 1-r*r)
+}
@@ -0,0 +1,146 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+)
+
+var unaryTests = []struct {
+	name string
+	f    func(Var) Var
+	fn   func(float64) float64
+	x    []float64
+}{
+	{name: "Neg", f: Neg, fn: func(x float64) float64 { return -x }, x: []float64{-2, 0.5, 3}},
+	{name: "Scale", f: func(x Var) Var { return Scale(2.5, x) }, fn: func(x float64) float64 { return 2.5 * x }, x: []float64{-2, 0.5}},
+	{name: "Inv", f: Inv, fn: func(x float64) float64 { return 1 / x }, x: []float64{-2, 0.5, 3}},
+	{name: "Abs", f: Abs, fn: math.Abs, x: []float64{-2, 0.5}},
+	{name: "Sqrt", f: Sqrt, fn: math.Sqrt, x: []float64{0.5, 3}},
+	{name: "Exp", f: Exp, fn: math.Exp, x: []float64{-2, 0.5, 3}},
+	{name: "Log", f: Log, fn: math.Log, x: []float64{0.5, 3}},
+	{name: "PowReal", f: func(x Var) Var { return PowReal(x, 2.5) }, fn: func(x float64) float64 { return math.Pow(x, 2.5) }, x: []float64{0.5, 3}},
+	{name: "Sin", f: Sin, fn: math.Sin, x: []float64{-2, 0.5, 3}},
+	{name: "Cos", f: Cos, fn: math.Cos, x: []float64{-2, 0.5, 3}},
+	{name: "Tan", f: Tan, fn: math.Tan, x: []float64{-1, 0.5}},
+	{name: "Asin", f: Asin, fn: math.Asin, x: []float64{-0.5, 0.7}},
+	{name: "Acos", f: Acos, fn: math.Acos, x: []float64{-0.5, 0.7}},
+	{name: "Atan", f: Atan, fn: math.Atan, x: []float64{-2, 0.5, 3}},
+	{name: "Sinh", f: Sinh, fn: math.Sinh, x: []float64{-2, 0.5}},
+	{name: "Cosh", f: Cosh, fn: math.Cosh, x: []float64{-2, 0.5}},
+	{name: "Tanh", f: Tanh, fn: math.Tanh, x: []float64{-2, 0.5}},
+}
+
+func TestUnary(t *testing.T) {
+	t.Parallel()
+	for _, test := range unaryTests {
+		for _, x := range test.x {
+			tape := NewTape()
+			v := tape.Var(x)
+			y := test.f(v)
+			if got, want := y.Value(), test.fn(x); !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+				t.Errorf("%s(%v): unexpected value: got %v, want %v", test.name, x, got, want)
+			}
+			var d [1]float64
+			tape.Gradient(d[:], y, []Var{v})
+			want := fd.Derivative(test.fn, x, &fd.Settings{Formula: fd.Central})
+			if !floats.EqualWithinAbsOrRel(d[0], want, 1e-7, 1e-7) {
+				t.Errorf("%s'(%v): unexpected derivative: got %v, want %v", test.name, x, d[0], want)
+			}
+
+			c := test.f(Const(x))
+			if c.tape != nil || c.Value() != y.Value() {
+				t.Errorf("%s(%v): unexpected result for constant argument", test.name, x)
+			}
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		f    func(x, y Var) Var
+		fn   func(x, y float64) float64
+	}{
+		{name: "Add", f: Add, fn: func(x, y float64) float64 { return x + y }},
+		{name: "Sub", f: Sub, fn: func(x, y float64) float64 { return x - y }},
+		{name: "Mul", f: Mul, fn: func(x, y float64) float64 { return x * y }},
+		{name: "Div", f: Div, fn: func(x, y float64) float64 { return x / y }},
+		{name: "Pow", f: Pow, fn: math.Pow},
+	} {
+		for _, xy := range [][2]float64{{1.5, 2}, {0.3, -1.2}, {2, 0.5}} {
+			x, y := xy[0], xy[1]
+			wantX := fd.Derivative(func(x float64) float64 { return test.fn(x, y) }, x, &fd.Settings{Formula: fd.Central})
+			wantY := fd.Derivative(func(y float64) float64 { return test.fn(x, y) }, y, &fd.Settings{Formula: fd.Central})
+
+			tape := NewTape()
+			vx, vy := tape.Var(x), tape.Var(y)
+			z := test.f(vx, vy)
+			if got, want := z.Value(), test.fn(x, y); !floats.EqualWithinAbsOrRel(got, want, 1e-14, 1e-14) {
+				t.Errorf("%s(%v, %v): unexpected value: got %v, want %v", test.name, x, y, got, want)
+			}
+			var d [2]float64
+			tape.Gradient(d[:], z, []Var{vx, vy})
+			if !floats.EqualWithinAbsOrRel(d[0], wantX, 1e-7, 1e-7) || !floats.EqualWithinAbsOrRel(d[1], wantY, 1e-7, 1e-7) {
+				t.Errorf("%s(%v, %v): unexpected derivatives: got %v, want [%v %v]", test.name, x, y, d, wantX, wantY)
+			}
+
+			// Mixing a variable with a constant.
+			tape.Reset()
+			vx = tape.Var(x)
+			z = test.f(vx, Const(y))
+			tape.Gradient(d[:1], z, []Var{vx})
+			if !floats.EqualWithinAbsOrRel(d[0], wantX, 1e-7, 1e-7) {
+				t.Errorf("%s(%v, const): unexpected derivative: got %v, want %v", test.name, x, d[0], wantX)
+			}
+			if tape.Len() != 2 {
+				t.Errorf("%s(%v, const): unexpected tape length: got %d, want 2", test.name, x, tape.Len())
+			}
+		}
+	}
+}
+
+func TestTape(t *testing.T) {
+	t.Parallel()
+	tape := NewTape()
+	x := tape.Var(2)
+	y := tape.Var(3)
+	// z uses x twice, so the adjoints must be accumulated.
+	z := Add(Mul(x, x), Mul(x, Sin(y)))
+	var d [3]float64
+	unused := tape.Var(1)
+	tape.Gradient(d[:], z, []Var{x, y, unused})
+	want := [3]float64{2*2 + math.Sin(3), 2 * math.Cos(3), 0}
+	if d != want {
+		t.Errorf("unexpected gradient: got %v, want %v", d, want)
+	}
+
+	if s := Sum(); s.Value() != 0 || s.tape != nil {
+		t.Errorf("unexpected empty sum: %v", s)
+	}
+
+	panics := func(fn func()) (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		fn()
+		return false
+	}
+	other := NewTape().Var(1)
+	if !panics(func() { Add(x, other) }) {
+		t.Errorf("expected panic for variables on different tapes")
+	}
+	if !panics(func() { tape.Gradient(d[:1], other, []Var{x}) }) {
+		t.Errorf("expected panic for gradient of a variable on a different tape")
+	}
+	if !panics(func() { tape.Gradient(d[:2], z, []Var{x}) }) {
+		t.Errorf("expected panic for mismatched lengths")
+	}
+}
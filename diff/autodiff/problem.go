@@ -0,0 +1,61 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/num/hyperdual"
+	"gonum.org/v1/gonum/optimize"
+)
+
+// Problem returns an optimize.Problem for minimizing the function f, with
+// Func evaluating f directly on constants and Grad computing the gradient
+// of f by reverse mode automatic differentiation. Each call to Grad records
+// the operations of f on a new Tape, so the returned Problem is safe for
+// concurrent use if f is.
+func Problem(f func(x []Var) Var) optimize.Problem {
+	return optimize.Problem{
+		Func: func(x []float64) float64 {
+			vars := make([]Var, len(x))
+			for i, v := range x {
+				vars[i] = Const(v)
+			}
+			return f(vars).value
+		},
+		Grad: func(grad, x []float64) {
+			Gradient(grad, f, x)
+		},
+	}
+}
+
+// HyperdualProblem returns an optimize.Problem for minimizing the function
+// f, with Func evaluating f directly, Grad computing the gradient of f with
+// one evaluation of f for each variable, and Hess computing the Hessian of f
+// by forward mode automatic differentiation with hyperdual numbers.
+func HyperdualProblem(f func(x []hyperdual.Number) hyperdual.Number) optimize.Problem {
+	return optimize.Problem{
+		Func: func(x []float64) float64 {
+			xh := make([]hyperdual.Number, len(x))
+			for i, v := range x {
+				xh[i].Real = v
+			}
+			return f(xh).Real
+		},
+		Grad: func(grad, x []float64) {
+			xh := make([]hyperdual.Number, len(x))
+			for i, v := range x {
+				xh[i].Real = v
+			}
+			for i := range x {
+				xh[i].E1mag = 1
+				grad[i] = f(xh).E1mag
+				xh[i].E1mag = 0
+			}
+		},
+		Hess: func(hess *mat.SymDense, x []float64) {
+			Hessian(hess, nil, f, x)
+		},
+	}
+}
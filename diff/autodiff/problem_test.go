@@ -0,0 +1,62 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func TestProblem(t *testing.T) {
+	t.Parallel()
+	x := []float64{0.3, -0.7, 2.1, 1.4}
+	want := []float64{1, 1, 1, 1}
+
+	p := Problem(rosenbrock)
+	var tape Tape
+	if got, want := p.Func(x), rosenbrock(tape.Vars(x)).Value(); got != want {
+		t.Errorf("Problem: unexpected function value: got %v, want %v", got, want)
+	}
+	grad := make([]float64, len(x))
+	p.Grad(grad, x)
+	wantGrad := make([]float64, len(x))
+	rosenbrockGrad(wantGrad, x)
+	if !floats.EqualApprox(grad, wantGrad, 1e-12) {
+		t.Errorf("Problem: unexpected gradient: got %v, want %v", grad, wantGrad)
+	}
+	res, err := optimize.Minimize(p, x, nil, &optimize.BFGS{})
+	if err != nil {
+		t.Fatalf("Problem: unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(res.X, want, 1e-6) {
+		t.Errorf("Problem: unexpected minimum: got %v, want %v", res.X, want)
+	}
+
+	hp := HyperdualProblem(rosenbrockHyperdual)
+	if got, want := hp.Func(x), p.Func(x); got != want {
+		t.Errorf("HyperdualProblem: unexpected function value: got %v, want %v", got, want)
+	}
+	hp.Grad(grad, x)
+	if !floats.EqualApprox(grad, wantGrad, 1e-12) {
+		t.Errorf("HyperdualProblem: unexpected gradient: got %v, want %v", grad, wantGrad)
+	}
+	hess := mat.NewSymDense(len(x), nil)
+	hp.Hess(hess, x)
+	wantHess := mat.NewSymDense(len(x), nil)
+	rosenbrockHess(wantHess, x)
+	if !mat.EqualApprox(hess, wantHess, 1e-12) {
+		t.Errorf("HyperdualProblem: unexpected Hessian:\ngot  %v\nwant %v", mat.Formatted(hess), mat.Formatted(wantHess))
+	}
+	res, err = optimize.Minimize(hp, x, nil, &optimize.Newton{})
+	if err != nil {
+		t.Fatalf("HyperdualProblem: unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(res.X, want, 1e-6) {
+		t.Errorf("HyperdualProblem: unexpected minimum: got %v, want %v", res.X, want)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import "gonum.org/v1/gonum/mat"
+
+// Gradient computes the gradient of the scalar function f at x by reverse
+// mode automatic differentiation, stores the result in dst and returns
+// f(x). Gradient panics if len(dst) != len(x).
+func Gradient(dst []float64, f func(x []Var) Var, x []float64) (fx float64) {
+	if len(dst) != len(x) {
+		panic(badLength)
+	}
+	t := NewTape()
+	vars := t.Vars(x)
+	y := f(vars)
+	t.Gradient(dst, y, vars)
+	return y.value
+}
+
+// Jacobian computes the Jacobian of the vector function f at x by reverse
+// mode automatic differentiation and stores the result in dst. f must store
+// its m outputs in y. The operations of f are recorded once and the rows of
+// the Jacobian are computed by one reverse sweep each, so Jacobian is
+// efficient when m is smaller than len(x).
+//
+// dst must be non-nil and have len(x) columns, and the number of rows of
+// dst is the number of outputs of f, otherwise Jacobian will panic.
+func Jacobian(dst *mat.Dense, f func(y, x []Var), x []float64) {
+	m, n := dst.Dims()
+	if n != len(x) {
+		panic(badLength)
+	}
+	t := NewTape()
+	vars := t.Vars(x)
+	y := make([]Var, m)
+	f(y, vars)
+	var adj []float64
+	for i, yi := range y {
+		adj = t.adjoints(adj, yi)
+		for j, v := range vars {
+			var d float64
+			if v.index < len(adj) {
+				d = adj[v.index]
+			}
+			dst.Set(i, j, d)
+		}
+	}
+}
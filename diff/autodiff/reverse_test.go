@@ -0,0 +1,108 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// rosenbrock is the extended Rosenbrock function
+//  f(x) = \sum_i 100 (x_{i+1} - x_i^2)^2 + (1 - x_i)^2.
+func rosenbrock(x []Var) Var {
+	var sum Var
+	for i := 0; i < len(x)-1; i++ {
+		a := Sub(x[i+1], Mul(x[i], x[i]))
+		b := Sub(Const(1), x[i])
+		sum = Add(sum, Add(Scale(100, Mul(a, a)), Mul(b, b)))
+	}
+	return sum
+}
+
+func rosenbrockGrad(dst, x []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for i := 0; i < len(x)-1; i++ {
+		a := x[i+1] - x[i]*x[i]
+		dst[i] += -400*a*x[i] - 2*(1-x[i])
+		dst[i+1] += 200 * a
+	}
+}
+
+// polar maps polar coordinates (r, θ, φ) and a scale s to the vector
+//  (s r sin θ cos φ, s r sin θ sin φ, s r cos θ, log(r) exp(-φ)).
+func polar(y, x []Var) {
+	r := Mul(x[3], x[0])
+	st := Sin(x[1])
+	y[0] = Mul(r, Mul(st, Cos(x[2])))
+	y[1] = Mul(r, Mul(st, Sin(x[2])))
+	y[2] = Mul(r, Cos(x[1]))
+	y[3] = Mul(Log(x[0]), Exp(Neg(x[2])))
+}
+
+func polarFloat(y, x []float64) {
+	vars := make([]Var, len(x))
+	for i, v := range x {
+		vars[i] = Const(v)
+	}
+	yv := make([]Var, len(y))
+	polar(yv, vars)
+	for i, v := range yv {
+		y[i] = v.Value()
+	}
+}
+
+func TestGradient(t *testing.T) {
+	t.Parallel()
+	for _, x := range [][]float64{
+		{-1.2, 1},
+		{1, 1, 1, 1},
+		{0.3, -0.7, 2.1, 1.4, -0.2},
+	} {
+		got := make([]float64, len(x))
+		fx := Gradient(got, rosenbrock, x)
+		want := make([]float64, len(x))
+		rosenbrockGrad(want, x)
+		if !floats.EqualApprox(got, want, 1e-12) {
+			t.Errorf("unexpected gradient at %v: got %v, want %v", x, got, want)
+		}
+		var tape Tape
+		if fx != rosenbrock(tape.Vars(x)).Value() {
+			t.Errorf("unexpected function value at %v", x)
+		}
+	}
+}
+
+func TestJacobian(t *testing.T) {
+	t.Parallel()
+	for _, x := range [][]float64{
+		{1, 0.5, 0.2, 2},
+		{2.5, 1.2, -0.7, 0.5},
+	} {
+		got := mat.NewDense(4, 4, nil)
+		Jacobian(got, polar, x)
+		want := mat.NewDense(4, 4, nil)
+		fd.Jacobian(want, polarFloat, x, &fd.JacobianSettings{Formula: fd.Central})
+		if !mat.EqualApprox(got, want, 1e-8) {
+			t.Errorf("unexpected Jacobian at %v:\ngot  %v\nwant %v", x, mat.Formatted(got), mat.Formatted(want))
+		}
+	}
+
+	// A constant output has a zero row.
+	got := mat.NewDense(2, 2, nil)
+	got.Set(1, 1, math.NaN())
+	Jacobian(got, func(y, x []Var) {
+		y[0] = Mul(x[0], x[1])
+		y[1] = Const(3)
+	}, []float64{2, 3})
+	if want := mat.NewDense(2, 2, []float64{3, 2, 0, 0}); !mat.Equal(got, want) {
+		t.Errorf("unexpected Jacobian with a constant output:\ngot  %v\nwant %v", mat.Formatted(got), mat.Formatted(want))
+	}
+}
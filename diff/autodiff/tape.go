@@ -0,0 +1,160 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package autodiff
+
+// Tape records the operations performed on variables for reverse mode
+// automatic differentiation. A Tape is not safe for concurrent use.
+type Tape struct {
+	nodes []node
+}
+
+// node is an operation recorded on a Tape. a and b are the positions on
+// the tape of the arguments of the operation, or -1 if the argument is a
+// constant or absent, and da and db are the partial derivatives of the
+// result with respect to the arguments.
+type node struct {
+	a, b   int
+	da, db float64
+}
+
+// NewTape returns a new empty Tape.
+func NewTape() *Tape {
+	return &Tape{}
+}
+
+// Var returns a new independent variable with the value v recorded on the
+// tape.
+func (t *Tape) Var(v float64) Var {
+	t.nodes = append(t.nodes, node{a: -1, b: -1})
+	return Var{tape: t, index: len(t.nodes) - 1, value: v}
+}
+
+// Vars returns new independent variables with the values in v recorded on
+// the tape.
+func (t *Tape) Vars(v []float64) []Var {
+	vars := make([]Var, len(v))
+	for i, x := range v {
+		vars[i] = t.Var(x)
+	}
+	return vars
+}
+
+// Len returns the number of operations recorded on the tape.
+func (t *Tape) Len() int {
+	return len(t.nodes)
+}
+
+// Reset clears the tape so that it can be reused. Variables recorded on
+// the tape before the call to Reset must not be used afterwards.
+func (t *Tape) Reset() {
+	t.nodes = t.nodes[:0]
+}
+
+// Gradient stores into dst the partial derivatives of y with respect to
+// each of the variables in x, computed by a single reverse sweep of the
+// tape. Variables that y does not depend on have zero derivative.
+// Gradient panics if len(dst) != len(x) or if y or any of x is recorded
+// on a different tape.
+func (t *Tape) Gradient(dst []float64, y Var, x []Var) {
+	if len(dst) != len(x) {
+		panic(badLength)
+	}
+	adj := t.adjoints(nil, y)
+	for i, v := range x {
+		if v.tape != nil && v.tape != t {
+			panic(differentTapes)
+		}
+		dst[i] = 0
+		if v.tape != nil && v.index < len(adj) {
+			dst[i] = adj[v.index]
+		}
+	}
+}
+
+// adjoints returns the adjoints of all operations on the tape up to y with
+// respect to y, using the storage in dst if it is large enough.
+func (t *Tape) adjoints(dst []float64, y Var) []float64 {
+	if y.tape == nil {
+		return dst[:0]
+	}
+	if y.tape != t {
+		panic(differentTapes)
+	}
+	n := y.index + 1
+	if cap(dst) < n {
+		dst = make([]float64, n)
+	}
+	dst = dst[:n]
+	for i := range dst {
+		dst[i] = 0
+	}
+	dst[y.index] = 1
+	for i := y.index; i >= 0; i-- {
+		adj := dst[i]
+		if adj == 0 {
+			continue
+		}
+		nd := t.nodes[i]
+		if nd.a >= 0 {
+			dst[nd.a] += adj * nd.da
+		}
+		if nd.b >= 0 {
+			dst[nd.b] += adj * nd.db
+		}
+	}
+	return dst
+}
+
+// Var is a value computed by operations recorded on a Tape. The zero Var
+// is the constant zero. Operations on Var values are performed by the
+// functions of this package, such as Add, Mul and Sin.
+type Var struct {
+	tape  *Tape
+	index int
+	value float64
+}
+
+// Const returns a constant with the value v. Constants are not recorded
+// on any tape and have zero derivative.
+func Const(v float64) Var {
+	return Var{value: v}
+}
+
+// Value returns the value of v.
+func (v Var) Value() float64 {
+	return v.value
+}
+
+// unary records an operation with the single argument x, the result r and
+// the derivative dx of r with respect to x.
+func unary(x Var, r, dx float64) Var {
+	if x.tape == nil {
+		return Const(r)
+	}
+	t := x.tape
+	t.nodes = append(t.nodes, node{a: x.index, b: -1, da: dx})
+	return Var{tape: t, index: len(t.nodes) - 1, value: r}
+}
+
+// binary records an operation with the arguments x and y, the result r and
+// the derivatives dx and dy of r with respect to x and y.
+func binary(x, y Var, r, dx, dy float64) Var {
+	switch {
+	case x.tape == nil:
+		return unary(y, r, dy)
+	case y.tape == nil:
+		return unary(x, r, dx)
+	case x.tape != y.tape:
+		panic(differentTapes)
+	}
+	t := x.tape
+	t.nodes = append(t.nodes, node{a: x.index, b: y.index, da: dx, db: dy})
+	return Var{tape: t, index: len(t.nodes) - 1, value: r}
+}
+
+const (
+	badLength      = "autodiff: slice length mismatch"
+	differentTapes = "autodiff: variables recorded on different tapes"
+)
@@ -0,0 +1,184 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// CZT implements the chirp-z transform of complex sequences using
+// Bluestein's algorithm.
+//
+// The chirp-z transform evaluates the z-transform of a sequence x of
+// length n at the m points z_k = a*w^-k on a spiral contour,
+//  X[k] = \sum_{j=0}^{n-1} x[j] a^-j w^(j*k), k = 0, 1, ..., m-1.
+// With a = 1 and w = exp(-2πi/n) and m = n, the transform is the
+// discrete Fourier transform. See https://en.wikipedia.org/wiki/Chirp_Z-transform
+// for details.
+type CZT struct {
+	n, m int
+	fft  *CmplxFFT
+
+	// pre holds a^-j w^(j²/2) for the input samples,
+	// post holds w^(k²/2) for the output samples and
+	// filter holds the Fourier coefficients of the
+	// chirp w^-(l²/2) used in the convolution.
+	pre, post []complex128
+	filter    []complex128
+
+	work []complex128
+}
+
+// NewCZT returns a CZT initialized for transforming sequences of length n
+// into m values with the contour parameters a and w.
+//
+// NewCZT will panic if n or m is less than 1, or a or w is zero.
+func NewCZT(n, m int, a, w complex128) *CZT {
+	if n < 1 || m < 1 {
+		panic("fourier: invalid transform length")
+	}
+	if a == 0 || w == 0 {
+		panic("fourier: zero contour parameter")
+	}
+	l := fastLen(n + m - 1)
+	t := CZT{
+		n:      n,
+		m:      m,
+		fft:    NewCmplxFFT(l),
+		pre:    make([]complex128, n),
+		post:   make([]complex128, m),
+		filter: make([]complex128, l),
+		work:   make([]complex128, l),
+	}
+
+	// The logarithms of a and w are taken once so that the
+	// quadratic exponents combine consistently whatever
+	// the branch of the complex logarithm.
+	logA := cmplx.Log(a)
+	logW := cmplx.Log(w)
+	for j := range t.pre {
+		fj := float64(j)
+		t.pre[j] = cmplx.Exp(-complex(fj, 0)*logA + complex(fj*fj/2, 0)*logW)
+	}
+	for k := range t.post {
+		fk := float64(k)
+		t.post[k] = cmplx.Exp(complex(fk*fk/2, 0) * logW)
+	}
+	for i := 0; i < m; i++ {
+		fi := float64(i)
+		t.filter[i] = cmplx.Exp(-complex(fi*fi/2, 0) * logW)
+	}
+	for i := 1; i < n; i++ {
+		fi := float64(i)
+		t.filter[l-i] = cmplx.Exp(-complex(fi*fi/2, 0) * logW)
+	}
+	t.fft.Coefficients(t.filter, t.filter)
+	return &t
+}
+
+// Len returns the length of the acceptable input.
+func (t *CZT) Len() int { return t.n }
+
+// Points returns the number of points at which the transform is evaluated.
+func (t *CZT) Points() int { return t.m }
+
+// Coefficients computes the chirp-z transform of the input sequence,
+// placing the result in dst and returning it.
+//
+// If the length of seq is not t.Len(), Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Points(), Coefficients will panic.
+func (t *CZT) Coefficients(dst, seq []complex128) []complex128 {
+	if len(seq) != t.n {
+		panic("fourier: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, t.m)
+	} else if len(dst) != t.m {
+		panic("fourier: destination length mismatch")
+	}
+	for i, v := range seq {
+		t.work[i] = v * t.pre[i]
+	}
+	for i := t.n; i < len(t.work); i++ {
+		t.work[i] = 0
+	}
+	t.fft.Coefficients(t.work, t.work)
+	for i, v := range t.filter {
+		t.work[i] *= v
+	}
+	t.fft.Sequence(t.work, t.work)
+	f := complex(1/float64(len(t.work)), 0)
+	for k := range dst {
+		dst[k] = t.work[k] * t.post[k] * f
+	}
+	return dst
+}
+
+// ZoomFFT implements a high resolution discrete Fourier transform over a
+// band of frequencies using the chirp-z transform.
+type ZoomFFT struct {
+	czt      *CZT
+	f0, step float64
+}
+
+// NewZoomFFT returns a ZoomFFT initialized for transforming sequences of
+// length n into m spectral coefficients evaluated at the frequencies
+//  f0 + k*(f1-f0)/m, k = 0, 1, ..., m-1,
+// expressed in cycles per sample. The endpoint f1 is excluded so that
+// NewZoomFFT(n, n, 0, 1) computes the same coefficients as a CmplxFFT.
+//
+// NewZoomFFT will panic if n or m is less than 1.
+func NewZoomFFT(n, m int, f0, f1 float64) *ZoomFFT {
+	step := (f1 - f0) / float64(m)
+	a := cmplx.Rect(1, 2*math.Pi*f0)
+	w := cmplx.Rect(1, -2*math.Pi*step)
+	return &ZoomFFT{czt: NewCZT(n, m, a, w), f0: f0, step: step}
+}
+
+// Len returns the length of the acceptable input.
+func (t *ZoomFFT) Len() int { return t.czt.Len() }
+
+// Points returns the number of frequencies at which the transform is
+// evaluated.
+func (t *ZoomFFT) Points() int { return t.czt.Points() }
+
+// Coefficients computes the Fourier coefficients of the input sequence over
+// the frequency band of the receiver, placing the result in dst and returning
+// it. The transform is unnormalized and uses the same sign convention as
+// CmplxFFT.
+//
+// If the length of seq is not t.Len(), Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Points(), Coefficients will panic.
+func (t *ZoomFFT) Coefficients(dst, seq []complex128) []complex128 {
+	return t.czt.Coefficients(dst, seq)
+}
+
+// Freq returns the relative frequency for coefficient i.
+// Freq will panic if i is negative or greater than or equal to t.Points().
+func (t *ZoomFFT) Freq(i int) float64 {
+	if i < 0 || t.Points() <= i {
+		panic("fourier: index out of range")
+	}
+	return t.f0 + float64(i)*t.step
+}
+
+// fastLen returns the smallest integer greater than or equal to n
+// with no prime factors other than 2, 3 and 5.
+func fastLen(n int) int {
+	for ; ; n++ {
+		m := n
+		for _, p := range []int{2, 3, 5} {
+			for m%p == 0 {
+				m /= p
+			}
+		}
+		if m == 1 {
+			return n
+		}
+	}
+}
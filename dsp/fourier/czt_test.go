@@ -0,0 +1,117 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestCZT(t *testing.T) {
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, m int
+		a, w complex128
+	}{
+		{n: 1, m: 1, a: 1, w: cmplx.Rect(1, -2*math.Pi)},
+		{n: 8, m: 8, a: 1, w: cmplx.Rect(1, -2*math.Pi/8)},
+		{n: 13, m: 7, a: cmplx.Rect(1, 0.3), w: cmplx.Rect(1, -0.05)},
+		{n: 20, m: 35, a: cmplx.Rect(1.1, 0.3), w: cmplx.Rect(0.995, -0.05)},
+		{n: 64, m: 3, a: 0.95, w: cmplx.Rect(1.002, 0.2)},
+	} {
+		t.Run(fmt.Sprintf("n=%d m=%d", test.n, test.m), func(t *testing.T) {
+			seq := make([]complex128, test.n)
+			for i := range seq {
+				seq[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+			}
+			czt := NewCZT(test.n, test.m, test.a, test.w)
+			got := czt.Coefficients(nil, seq)
+			for k := range got {
+				z := test.a * cmplx.Pow(test.w, complex(-float64(k), 0))
+				var want complex128
+				for j, v := range seq {
+					want += v * cmplx.Pow(z, complex(-float64(j), 0))
+				}
+				if cmplx.Abs(got[k]-want) > tol*(1+cmplx.Abs(want)) {
+					t.Errorf("unexpected coefficient %d: got:%v want:%v", k, got[k], want)
+				}
+			}
+		})
+	}
+}
+
+func TestZoomFFT(t *testing.T) {
+	const tol = 1e-9
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 5, 16, 37} {
+		seq := make([]complex128, n)
+		for i := range seq {
+			seq[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		want := NewCmplxFFT(n).Coefficients(nil, seq)
+		got := NewZoomFFT(n, n, 0, 1).Coefficients(nil, seq)
+		if !equalApprox(got, want, tol) {
+			t.Errorf("zoom FFT over full band does not match FFT for n=%d:\ngot: %v\nwant:%v", n, got, want)
+		}
+	}
+
+	// Resolve two tones closer than the FFT bin spacing.
+	const n = 64
+	seq := make([]complex128, n)
+	for i := range seq {
+		x := float64(i)
+		seq[i] = complex(math.Cos(2*math.Pi*0.2*x)+math.Cos(2*math.Pi*0.21*x), 0)
+	}
+	zoom := NewZoomFFT(n, 200, 0.19, 0.22)
+	coeff := zoom.Coefficients(nil, seq)
+	for i, c := range coeff {
+		var want complex128
+		for j, v := range seq {
+			want += v * cmplx.Rect(1, -2*math.Pi*zoom.Freq(i)*float64(j))
+		}
+		if cmplx.Abs(c-want) > tol*n {
+			t.Fatalf("unexpected coefficient at %v: got:%v want:%v", zoom.Freq(i), c, want)
+		}
+	}
+	if got := zoom.Freq(100); math.Abs(got-0.205) > 1e-15 {
+		t.Errorf("unexpected frequency: got:%v want:0.205", got)
+	}
+}
+
+func TestGoertzel(t *testing.T) {
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{0, 1, 2, 7, 64, 101} {
+		seq := make([]float64, n)
+		for i := range seq {
+			seq[i] = rnd.NormFloat64()
+		}
+		if n > 0 {
+			fft := NewFFT(n)
+			coeff := fft.Coefficients(nil, seq)
+			for k, want := range coeff {
+				got := Goertzel(seq, fft.Freq(k))
+				if cmplx.Abs(got-want) > tol {
+					t.Errorf("unexpected Goertzel value for n=%d k=%d: got:%v want:%v", n, k, got, want)
+				}
+			}
+		}
+		for _, f := range []float64{0.013, 0.25, 0.37, 0.5} {
+			var want complex128
+			for j, v := range seq {
+				want += complex(v, 0) * cmplx.Rect(1, -2*math.Pi*f*float64(j))
+			}
+			got := Goertzel(seq, f)
+			if cmplx.Abs(got-want) > tol {
+				t.Errorf("unexpected Goertzel value for n=%d f=%v: got:%v want:%v", n, f, got, want)
+			}
+		}
+	}
+}
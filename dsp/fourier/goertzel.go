@@ -0,0 +1,37 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Goertzel returns the Fourier coefficient of the real sequence seq at
+// the frequency freq, in cycles per sample, computed using the Goertzel
+// algorithm,
+//  X(freq) = \sum_{j=0}^{n-1} seq[j] exp(-2πi*freq*j).
+// The result is unnormalized and uses the same sign convention as FFT,
+// so that for freq = k/n it equals the kth coefficient returned by
+// FFT.Coefficients. The frequency need not be a multiple of 1/n.
+//
+// The Goertzel algorithm requires O(n) operations and is more efficient
+// than a full transform when only a small number of frequencies are
+// required, for example in tone detection.
+func Goertzel(seq []float64, freq float64) complex128 {
+	if len(seq) == 0 {
+		return 0
+	}
+	w := 2 * math.Pi * freq
+	coeff := 2 * math.Cos(w)
+	var s1, s2 float64
+	for _, v := range seq {
+		s1, s2 = v+coeff*s1-s2, s1
+	}
+	// The recurrence computes y = \sum seq[j] exp(iw(n-1-j)),
+	// which must be rotated back to the origin.
+	y := complex(s1, 0) - cmplx.Rect(s2, -w)
+	return y * cmplx.Rect(1, -w*float64(len(seq)-1))
+}
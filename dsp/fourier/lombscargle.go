@@ -0,0 +1,80 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import "math"
+
+// LombScargle computes the Lomb–Scargle periodogram of the irregularly
+// sampled series y observed at times t for each of the frequencies in
+// freqs, placing the result in dst and returning it. Frequencies are in
+// cycles per unit of t. The mean of y is removed before the periodogram
+// is computed.
+//
+// The power at angular frequency ω = 2π*f is
+//  P(f) = 1/2 * [ (\sum_j y_j cos(ω(t_j-τ)))² / \sum_j cos²(ω(t_j-τ))
+//               + (\sum_j y_j sin(ω(t_j-τ)))² / \sum_j sin²(ω(t_j-τ)) ]
+// where τ is chosen such that tan(2ωτ) = \sum_j sin(2ωt_j) / \sum_j cos(2ωt_j),
+// making the periodogram invariant to shifts in time. Dividing P by the
+// variance of y gives the normalization of Scargle (1982) under which a
+// pure noise series has an exponentially distributed periodogram. For
+// evenly sampled data, P reduces to the squared magnitude of the Fourier
+// coefficients divided by the number of samples at the Fourier frequencies
+// strictly between zero and the Nyquist frequency.
+// See https://en.wikipedia.org/wiki/Least-squares_spectral_analysis for details.
+//
+// If the lengths of t and y differ, LombScargle will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of freqs, LombScargle will panic.
+func LombScargle(dst, t, y, freqs []float64) []float64 {
+	if len(t) != len(y) {
+		panic("fourier: length mismatch")
+	}
+	if dst == nil {
+		dst = make([]float64, len(freqs))
+	} else if len(dst) != len(freqs) {
+		panic("fourier: destination length mismatch")
+	}
+
+	var mean float64
+	for _, v := range y {
+		mean += v
+	}
+	if len(y) != 0 {
+		mean /= float64(len(y))
+	}
+
+	for i, f := range freqs {
+		w := 2 * math.Pi * f
+		var s2, c2 float64
+		for _, tj := range t {
+			s, c := math.Sincos(2 * w * tj)
+			s2 += s
+			c2 += c
+		}
+		tau := math.Atan2(s2, c2) / (2 * w)
+		if w == 0 {
+			tau = 0
+		}
+
+		var yc, ys, cc, ss float64
+		for j, tj := range t {
+			s, c := math.Sincos(w * (tj - tau))
+			v := y[j] - mean
+			yc += v * c
+			ys += v * s
+			cc += c * c
+			ss += s * s
+		}
+		var p float64
+		if cc != 0 {
+			p += yc * yc / cc
+		}
+		if ss > 1e-15*cc {
+			p += ys * ys / ss
+		}
+		dst[i] = p / 2
+	}
+	return dst
+}
@@ -0,0 +1,78 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestLombScargle(t *testing.T) {
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+
+	// Evenly sampled data reduces to the periodogram.
+	const n = 32
+	ts := make([]float64, n)
+	y := make([]float64, n)
+	for i := range y {
+		ts[i] = float64(i)
+		y[i] = rnd.NormFloat64()
+	}
+	fft := NewFFT(n)
+	coeff := fft.Coefficients(nil, y)
+	var freqs []float64
+	for k := 1; k < n/2; k++ {
+		freqs = append(freqs, fft.Freq(k))
+	}
+	got := LombScargle(nil, ts, y, freqs)
+	for i, p := range got {
+		want := math.Pow(cmplx.Abs(coeff[i+1]), 2) / n
+		if math.Abs(p-want) > tol {
+			t.Errorf("unexpected power for even sampling at %v: got:%v want:%v", freqs[i], p, want)
+		}
+	}
+
+	// The peak of an irregularly sampled sinusoid is at its frequency
+	// and the periodogram is invariant to a shift in time.
+	const (
+		m    = 200
+		freq = 0.37
+	)
+	ts = make([]float64, m)
+	y = make([]float64, m)
+	shifted := make([]float64, m)
+	for i := range ts {
+		ts[i] = 100 * rnd.Float64()
+		shifted[i] = ts[i] + 12.3
+		y[i] = 2*math.Sin(2*math.Pi*freq*ts[i]+1) + 0.1*rnd.NormFloat64() + 5
+	}
+	freqs = make([]float64, 1000)
+	for i := range freqs {
+		freqs[i] = 0.001 * float64(i+1)
+	}
+	p := LombScargle(nil, ts, y, freqs)
+	ps := LombScargle(nil, shifted, y, freqs)
+	var peak int
+	for i, v := range p {
+		if v > p[peak] {
+			peak = i
+		}
+		if math.Abs(v-ps[i]) > 1e-8*(1+v) {
+			t.Errorf("periodogram not shift invariant at %v: %v != %v", freqs[i], v, ps[i])
+		}
+	}
+	if math.Abs(freqs[peak]-freq) > 0.002 {
+		t.Errorf("unexpected peak frequency: got:%v want:%v", freqs[peak], freq)
+	}
+	// The peak height is approximately N*A²/4, subject to the
+	// irregularity of the sampling.
+	if want := m * 4.0 / 4; math.Abs(p[peak]-want)/want > 0.25 {
+		t.Errorf("unexpected peak power: got:%v want:%v", p[peak], want)
+	}
+}
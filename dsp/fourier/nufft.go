@@ -0,0 +1,203 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"math"
+)
+
+// NUFFT implements the one-dimensional non-uniform Fast Fourier Transform
+// of types 1 and 2 using Gaussian gridding.
+//
+// The transforms relate m uniformly spaced Fourier modes with integer
+// wave numbers k to the values at an arbitrary set of points x in the
+// unit period. The type 1 transform computes
+//  F[k] = \sum_j c[j] exp(-2πi*k*x[j])
+// from values c at non-uniform points, and the type 2 transform computes
+//  c[j] = \sum_k F[k] exp(2πi*k*x[j])
+// from the modes F. The modes are ordered as the coefficients of a
+// CmplxFFT of length m, so that with x[j] = j/m the type 1 transform
+// equals CmplxFFT.Coefficients and the type 2 transform equals
+// CmplxFFT.Sequence.
+//
+// The values are spread onto an oversampled uniform grid with a Gaussian
+// kernel, transformed using a CmplxFFT, and the effect of the kernel is
+// then removed in the frequency domain. See Greengard and Lee,
+// "Accelerating the Nonuniform Fast Fourier Transform",
+// SIAM Review 46(3):443-454 (2004) for details.
+type NUFFT struct {
+	m int
+
+	// grid is the oversampled grid of length r*m
+	// and spread is the half width of the Gaussian
+	// kernel in grid points. tau is the kernel
+	// variance parameter and deconv holds the
+	// per-mode kernel correction.
+	fft    *CmplxFFT
+	grid   []complex128
+	spread int
+	tau    float64
+	deconv []float64
+}
+
+// oversample is the ratio of the length of the spreading
+// grid to the number of modes.
+const oversample = 2
+
+// NewNUFFT returns a NUFFT initialized for m Fourier modes with a relative
+// accuracy of approximately tol. If tol is not positive, the transform is
+// computed to near machine precision.
+//
+// NewNUFFT will panic if m is less than 1.
+func NewNUFFT(m int, tol float64) *NUFFT {
+	if m < 1 {
+		panic("fourier: invalid number of modes")
+	}
+	// The Gaussian gridding error decreases by roughly
+	// an order of magnitude per grid point of half width
+	// with two-fold oversampling.
+	spread := 12
+	if tol > 0 {
+		spread = int(math.Ceil(-math.Log10(tol))) + 1
+		if spread < 2 {
+			spread = 2
+		}
+		if spread > 12 {
+			spread = 12
+		}
+	}
+	r := oversample * m
+	if r < 2*spread {
+		r = 2 * spread
+	}
+	ratio := float64(r) / float64(m)
+	tau := math.Pi * float64(spread) / (float64(m*m) * ratio * (ratio - 0.5))
+
+	t := NUFFT{
+		m:      m,
+		fft:    NewCmplxFFT(r),
+		grid:   make([]complex128, r),
+		spread: spread,
+		tau:    tau,
+		deconv: make([]float64, m),
+	}
+	for i := range t.deconv {
+		k := float64(t.mode(i))
+		t.deconv[i] = math.Sqrt(math.Pi/tau) * math.Exp(k*k*tau) / float64(r)
+	}
+	return &t
+}
+
+// Len returns the number of Fourier modes.
+func (t *NUFFT) Len() int { return t.m }
+
+// Mode returns the integer wave number of coefficient i.
+// Mode will panic if i is negative or greater than or equal to t.Len().
+func (t *NUFFT) Mode(i int) int {
+	if i < 0 || t.m <= i {
+		panic("fourier: index out of range")
+	}
+	return t.mode(i)
+}
+
+func (t *NUFFT) mode(i int) int {
+	if i < (t.m-1)/2+1 {
+		return i
+	}
+	return i - t.m
+}
+
+// gridIdx returns the index into the oversampled grid for mode i.
+func (t *NUFFT) gridIdx(i int) int {
+	k := t.mode(i)
+	if k < 0 {
+		k += len(t.grid)
+	}
+	return k
+}
+
+// Coefficients computes the type 1 non-uniform Fourier transform of the
+// values in c at the points in x, placing the m Fourier modes in dst and
+// returning it. The points are taken modulo one.
+//
+// If the lengths of x and c differ, Coefficients will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len(), Coefficients will panic.
+func (t *NUFFT) Coefficients(dst []complex128, x []float64, c []complex128) []complex128 {
+	if len(x) != len(c) {
+		panic("fourier: length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, t.m)
+	} else if len(dst) != t.m {
+		panic("fourier: destination length mismatch")
+	}
+
+	for i := range t.grid {
+		t.grid[i] = 0
+	}
+	for j, xj := range x {
+		t.kernel(xj, func(idx int, w float64) {
+			t.grid[idx] += c[j] * complex(w, 0)
+		})
+	}
+	t.fft.Coefficients(t.grid, t.grid)
+	for i := range dst {
+		dst[i] = t.grid[t.gridIdx(i)] * complex(t.deconv[i], 0)
+	}
+	return dst
+}
+
+// Sequence computes the type 2 non-uniform Fourier transform of the m
+// Fourier modes in coeff, evaluating the sum at the points in x, placing
+// the result in dst and returning it. The points are taken modulo one.
+//
+// If the length of coeff is not t.Len(), Sequence will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of x, Sequence will panic.
+func (t *NUFFT) Sequence(dst []complex128, x []float64, coeff []complex128) []complex128 {
+	if len(coeff) != t.m {
+		panic("fourier: coefficients length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, len(x))
+	} else if len(dst) != len(x) {
+		panic("fourier: destination length mismatch")
+	}
+
+	for i := range t.grid {
+		t.grid[i] = 0
+	}
+	for i, v := range coeff {
+		t.grid[t.gridIdx(i)] = v * complex(t.deconv[i], 0)
+	}
+	t.fft.Sequence(t.grid, t.grid)
+	for j, xj := range x {
+		var sum complex128
+		t.kernel(xj, func(idx int, w float64) {
+			sum += t.grid[idx] * complex(w, 0)
+		})
+		dst[j] = sum
+	}
+	return dst
+}
+
+// kernel calls fn with the grid index and Gaussian kernel weight of each
+// grid point within the spreading width of the point x.
+func (t *NUFFT) kernel(x float64, fn func(idx int, w float64)) {
+	r := len(t.grid)
+	h := 2 * math.Pi / float64(r)
+	x = 2 * math.Pi * (x - math.Floor(x))
+	m0 := int(math.Floor(x / h))
+	for l := -t.spread + 1; l <= t.spread; l++ {
+		m := m0 + l
+		d := x - float64(m)*h
+		idx := m % r
+		if idx < 0 {
+			idx += r
+		}
+		fn(idx, math.Exp(-d*d/(4*t.tau)))
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fourier
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestNUFFT(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		m, n int
+		tol  float64
+	}{
+		{m: 1, n: 5, tol: 0},
+		{m: 8, n: 20, tol: 0},
+		{m: 33, n: 100, tol: 0},
+		{m: 64, n: 50, tol: 1e-6},
+		{m: 100, n: 300, tol: 1e-9},
+	} {
+		t.Run(fmt.Sprintf("m=%d n=%d tol=%g", test.m, test.n, test.tol), func(t *testing.T) {
+			tol := test.tol
+			if tol == 0 {
+				tol = 1e-11
+			}
+			x := make([]float64, test.n)
+			c := make([]complex128, test.n)
+			var norm float64
+			for j := range x {
+				x[j] = 2*rnd.Float64() - 0.5
+				c[j] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+				norm += cmplx.Abs(c[j])
+			}
+			nufft := NewNUFFT(test.m, test.tol)
+
+			got := nufft.Coefficients(nil, x, c)
+			for i, v := range got {
+				k := float64(nufft.Mode(i))
+				var want complex128
+				for j, xj := range x {
+					want += c[j] * cmplx.Rect(1, -2*math.Pi*k*xj)
+				}
+				if cmplx.Abs(v-want) > 10*tol*norm {
+					t.Errorf("unexpected type 1 coefficient for mode %v: got:%v want:%v", k, v, want)
+				}
+			}
+
+			coeff := make([]complex128, test.m)
+			norm = 0
+			for i := range coeff {
+				coeff[i] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+				norm += cmplx.Abs(coeff[i])
+			}
+			seq := nufft.Sequence(nil, x, coeff)
+			for j, v := range seq {
+				var want complex128
+				for i, f := range coeff {
+					want += f * cmplx.Rect(1, 2*math.Pi*float64(nufft.Mode(i))*x[j])
+				}
+				if cmplx.Abs(v-want) > 10*tol*norm {
+					t.Errorf("unexpected type 2 value at %v: got:%v want:%v", x[j], v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNUFFTUniform(t *testing.T) {
+	const tol = 1e-10
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 4, 15, 32} {
+		x := make([]float64, n)
+		seq := make([]complex128, n)
+		for j := range x {
+			x[j] = float64(j) / float64(n)
+			seq[j] = complex(rnd.NormFloat64(), rnd.NormFloat64())
+		}
+		fft := NewCmplxFFT(n)
+		nufft := NewNUFFT(n, 0)
+		for i := 0; i < n; i++ {
+			if got, want := float64(nufft.Mode(i)), fft.Freq(i)*float64(n); math.Abs(got-want) > 1e-12 {
+				t.Errorf("mode mismatch for n=%d at %d: got:%v want:%v", n, i, got, want)
+			}
+		}
+		if got, want := nufft.Coefficients(nil, x, seq), fft.Coefficients(nil, seq); !equalApprox(got, want, tol) {
+			t.Errorf("type 1 transform does not match FFT for n=%d", n)
+		}
+		if got, want := nufft.Sequence(nil, x, seq), fft.Sequence(nil, seq); !equalApprox(got, want, tol) {
+			t.Errorf("type 2 transform does not match inverse FFT for n=%d", n)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package hilbert provides the discrete Hilbert transform and functions
+// for the analysis of the analytic signal.
+//
+// The analytic signal of a real sequence x is the complex sequence
+//  z = x + i*H(x)
+// where H(x) is the Hilbert transform of x. Its magnitude is the
+// instantaneous amplitude, or envelope, of x and its argument is the
+// instantaneous phase. The transforms in this package are computed in
+// the frequency domain and so treat the input as one period of a periodic
+// sequence. See https://en.wikipedia.org/wiki/Analytic_signal for details.
+package hilbert // import "gonum.org/v1/gonum/dsp/hilbert"
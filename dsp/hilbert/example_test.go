@@ -0,0 +1,45 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hilbert_test
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/hilbert"
+)
+
+func ExampleTransform_EnvelopeSpectrum() {
+	// A 3 kHz resonance excited by impacts from a bearing defect
+	// repeating at 120 Hz, sampled at 20 kHz for 0.1 s.
+	const (
+		rate      = 20000.0
+		resonance = 3000.0
+		defect    = 120.0
+		n         = 2000
+	)
+	seq := make([]float64, n)
+	for i := range seq {
+		t := float64(i) / rate
+		phase := math.Mod(t*defect, 1) / defect
+		seq[i] = math.Exp(-phase*800) * math.Sin(2*math.Pi*resonance*t)
+	}
+
+	tr := hilbert.NewTransform(n)
+	spec := tr.EnvelopeSpectrum(nil, seq)
+
+	var peak int
+	for i, c := range spec {
+		if cmplx.Abs(c) > cmplx.Abs(spec[peak]) {
+			peak = i
+		}
+	}
+	fmt.Printf("envelope spectrum peak at %v Hz\n", float64(peak)*rate/n)
+
+	// Output:
+	//
+	// envelope spectrum peak at 120 Hz
+}
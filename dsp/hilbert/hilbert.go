@@ -0,0 +1,235 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hilbert
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+)
+
+// Transform implements the discrete Hilbert transform and the analytic
+// signal for real sequences.
+type Transform struct {
+	fft  *fourier.FFT
+	cfft *fourier.CmplxFFT
+
+	// coeff holds the one-sided spectrum
+	// of the input sequence.
+	coeff []complex128
+}
+
+// NewTransform returns a Transform initialized for work on sequences of
+// length n.
+func NewTransform(n int) *Transform {
+	var t Transform
+	t.Reset(n)
+	return &t
+}
+
+// Len returns the length of the acceptable input.
+func (t *Transform) Len() int { return t.fft.Len() }
+
+// Reset reinitializes the Transform for work on sequences of length n.
+func (t *Transform) Reset(n int) {
+	if t.fft == nil {
+		t.fft = fourier.NewFFT(n)
+		t.cfft = fourier.NewCmplxFFT(n)
+	} else {
+		t.fft.Reset(n)
+		t.cfft.Reset(n)
+	}
+	if n/2+1 <= cap(t.coeff) {
+		t.coeff = t.coeff[:n/2+1]
+	} else {
+		t.coeff = make([]complex128, n/2+1)
+	}
+}
+
+// AnalyticSignal computes the analytic signal of the real sequence in seq,
+// placing the result in dst and returning it. The real part of the result
+// is seq and the imaginary part is the Hilbert transform of seq.
+//
+// The analytic signal is obtained by suppressing the negative frequency
+// components of the spectrum of seq and doubling the positive frequency
+// components, leaving the zero and Nyquist frequency components unaltered.
+//
+// If the length of seq is not t.Len(), AnalyticSignal will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len(), AnalyticSignal will panic.
+func (t *Transform) AnalyticSignal(dst []complex128, seq []float64) []complex128 {
+	n := t.Len()
+	if len(seq) != n {
+		panic("hilbert: sequence length mismatch")
+	}
+	if dst == nil {
+		dst = make([]complex128, n)
+	} else if len(dst) != n {
+		panic("hilbert: destination length mismatch")
+	}
+	if n == 0 {
+		return dst
+	}
+
+	t.fft.Coefficients(t.coeff, seq)
+	dst[0] = t.coeff[0]
+	for i := 1; i < len(t.coeff); i++ {
+		dst[i] = 2 * t.coeff[i]
+	}
+	if n%2 == 0 {
+		// The Nyquist frequency is shared by the positive
+		// and negative halves of the spectrum.
+		dst[n/2] = t.coeff[n/2]
+	}
+	for i := len(t.coeff); i < n; i++ {
+		dst[i] = 0
+	}
+	t.cfft.Sequence(dst, dst)
+
+	f := complex(1/float64(n), 0)
+	for i := range dst {
+		dst[i] *= f
+	}
+	return dst
+}
+
+// Sequence computes the Hilbert transform of the real sequence in seq,
+// placing the result in dst and returning it.
+//
+// If the length of seq is not t.Len(), Sequence will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len(), Sequence will panic.
+func (t *Transform) Sequence(dst, seq []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, t.Len())
+	} else if len(dst) != t.Len() {
+		panic("hilbert: destination length mismatch")
+	}
+	z := t.AnalyticSignal(nil, seq)
+	for i, v := range z {
+		dst[i] = imag(v)
+	}
+	return dst
+}
+
+// Envelope computes the instantaneous amplitude of the real sequence in seq,
+// the magnitude of its analytic signal, placing the result in dst and
+// returning it.
+//
+// If the length of seq is not t.Len(), Envelope will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len(), Envelope will panic.
+func (t *Transform) Envelope(dst, seq []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, t.Len())
+	} else if len(dst) != t.Len() {
+		panic("hilbert: destination length mismatch")
+	}
+	z := t.AnalyticSignal(nil, seq)
+	for i, v := range z {
+		dst[i] = cmplx.Abs(v)
+	}
+	return dst
+}
+
+// EnvelopeSpectrum computes the Fourier coefficients of the envelope of
+// the real sequence in seq after removal of the mean of the envelope,
+// placing the result in dst and returning it. Peaks in the envelope
+// spectrum identify the frequencies at which the amplitude of seq is
+// modulated, as used in the analysis of bearing and gear faults. The
+// coefficients are unnormalized and indexed as for fourier.FFT.
+//
+// If the length of seq is not t.Len(), EnvelopeSpectrum will panic.
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal t.Len()/2+1, EnvelopeSpectrum will panic.
+func (t *Transform) EnvelopeSpectrum(dst []complex128, seq []float64) []complex128 {
+	env := t.Envelope(nil, seq)
+	if len(env) == 0 {
+		panic("hilbert: zero length sequence")
+	}
+	var mean float64
+	for _, v := range env {
+		mean += v
+	}
+	mean /= float64(len(env))
+	for i := range env {
+		env[i] -= mean
+	}
+	return t.fft.Coefficients(dst, env)
+}
+
+// Unwrap corrects the phase angles in src by adding multiples of 2π when
+// the absolute difference between consecutive elements exceeds π, placing
+// the result in dst and returning it. It is safe to use the same slice for
+// dst and src.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of src, Unwrap will panic.
+func Unwrap(dst, src []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(src))
+	} else if len(dst) != len(src) {
+		panic("hilbert: destination length mismatch")
+	}
+	if len(src) == 0 {
+		return dst
+	}
+	var offset float64
+	prev := src[0]
+	dst[0] = src[0]
+	for i := 1; i < len(src); i++ {
+		v := src[i]
+		d := v - prev
+		prev = v
+		if math.Abs(d) > math.Pi {
+			offset -= 2 * math.Pi * math.Round(d/(2*math.Pi))
+		}
+		dst[i] = v + offset
+	}
+	return dst
+}
+
+// Phase computes the unwrapped instantaneous phase of the analytic signal
+// in z, placing the result in dst and returning it.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of z, Phase will panic.
+func Phase(dst []float64, z []complex128) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(z))
+	} else if len(dst) != len(z) {
+		panic("hilbert: destination length mismatch")
+	}
+	for i, v := range z {
+		dst[i] = cmplx.Phase(v)
+	}
+	return Unwrap(dst, dst)
+}
+
+// Frequency computes the instantaneous frequency in cycles per sample of
+// the analytic signal in z from the first difference of its unwrapped phase,
+// placing the result in dst and returning it. Element i of the result is
+// the mean frequency between samples i and i+1.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal len(z)-1, Frequency will panic.
+// Frequency will panic if z is empty.
+func Frequency(dst []float64, z []complex128) []float64 {
+	if len(z) == 0 {
+		panic("hilbert: zero length sequence")
+	}
+	if dst == nil {
+		dst = make([]float64, len(z)-1)
+	} else if len(dst) != len(z)-1 {
+		panic("hilbert: destination length mismatch")
+	}
+	for i := range dst {
+		// The phase of z[i+1]/z[i] is the wrapped phase
+		// difference, avoiding the need to unwrap.
+		dst[i] = cmplx.Phase(z[i+1]*cmplx.Conj(z[i])) / (2 * math.Pi)
+	}
+	return dst
+}
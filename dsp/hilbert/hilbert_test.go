@@ -0,0 +1,169 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package hilbert
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestAnalyticSignal(t *testing.T) {
+	const tol = 1e-12
+	for _, n := range []int{1, 2, 7, 16, 33, 100} {
+		for cycles := 1; cycles < (n+1)/2; cycles++ {
+			w := 2 * math.Pi * float64(cycles) / float64(n)
+			seq := make([]float64, n)
+			for i := range seq {
+				seq[i] = 3 * math.Cos(w*float64(i)+0.4)
+			}
+			tr := NewTransform(n)
+			got := tr.AnalyticSignal(nil, seq)
+			for i, v := range got {
+				want := 3 * cmplx.Exp(complex(0, w*float64(i)+0.4))
+				if cmplx.Abs(v-want) > tol {
+					t.Fatalf("unexpected analytic signal for n=%d cycles=%d at %d: got:%v want:%v", n, cycles, i, v, want)
+				}
+			}
+			h := tr.Sequence(nil, seq)
+			for i, v := range h {
+				want := 3 * math.Sin(w*float64(i)+0.4)
+				if math.Abs(v-want) > tol {
+					t.Fatalf("unexpected Hilbert transform for n=%d cycles=%d at %d: got:%v want:%v", n, cycles, i, v, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAnalyticSignalReal(t *testing.T) {
+	const tol = 1e-12
+	rnd := rand.New(rand.NewSource(1))
+	tr := NewTransform(1)
+	for n := 1; n < 50; n++ {
+		tr.Reset(n)
+		seq := make([]float64, n)
+		for i := range seq {
+			seq[i] = rnd.NormFloat64()
+		}
+		z := tr.AnalyticSignal(nil, seq)
+		for i, v := range z {
+			if math.Abs(real(v)-seq[i]) > tol {
+				t.Fatalf("real part of analytic signal does not match input for n=%d at %d: got:%v want:%v", n, i, real(v), seq[i])
+			}
+		}
+
+		// The Hilbert transform has zero mean and applying
+		// it twice negates the non-DC, non-Nyquist content.
+		h := tr.Sequence(nil, seq)
+		if mean := floats.Sum(h) / float64(n); math.Abs(mean) > tol {
+			t.Errorf("non-zero mean of Hilbert transform for n=%d: %v", n, mean)
+		}
+	}
+}
+
+func TestEnvelope(t *testing.T) {
+	const (
+		n    = 1000
+		tol  = 1e-10
+		fc   = 100.0 / n
+		fm   = 5.0 / n
+		beta = 0.5
+	)
+	seq := make([]float64, n)
+	want := make([]float64, n)
+	for i := range seq {
+		want[i] = 1 + beta*math.Cos(2*math.Pi*fm*float64(i))
+		seq[i] = want[i] * math.Cos(2*math.Pi*fc*float64(i))
+	}
+	tr := NewTransform(n)
+	got := tr.Envelope(nil, seq)
+	if !floats.EqualApprox(got, want, tol) {
+		t.Errorf("unexpected envelope")
+	}
+
+	spec := tr.EnvelopeSpectrum(nil, seq)
+	var peak int
+	for i, c := range spec {
+		if cmplx.Abs(c) > cmplx.Abs(spec[peak]) {
+			peak = i
+		}
+	}
+	if peak != 5 {
+		t.Errorf("unexpected envelope spectrum peak: got:%d want:5", peak)
+	}
+	if dc := cmplx.Abs(spec[0]); dc > tol*n {
+		t.Errorf("unexpected envelope spectrum mean: got:%v want:0", dc)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		src, want []float64
+	}{
+		{src: nil, want: nil},
+		{src: []float64{1}, want: []float64{1}},
+		{
+			src:  []float64{0, 3, -3, -0.1, 3},
+			want: []float64{0, 3, 2*math.Pi - 3, 2*math.Pi - 0.1, 2*math.Pi + 3},
+		},
+		{
+			src:  []float64{0, 7, 14},
+			want: []float64{0, 7 - 2*math.Pi, 14 - 4*math.Pi},
+		},
+	} {
+		got := Unwrap(nil, test.src)
+		if !floats.EqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected unwrap result for %v: got:%v want:%v", test.src, got, test.want)
+		}
+	}
+
+	// A linear phase ramp must be recovered from its wrapped values.
+	ramp := make([]float64, 100)
+	wrapped := make([]float64, len(ramp))
+	for i := range ramp {
+		ramp[i] = 0.9 * float64(i)
+		wrapped[i] = math.Remainder(ramp[i], 2*math.Pi)
+	}
+	if got := Unwrap(wrapped, wrapped); !floats.EqualApprox(got, ramp, tol) {
+		t.Errorf("linear phase ramp not recovered in place")
+	}
+}
+
+func TestPhaseFrequency(t *testing.T) {
+	const (
+		n   = 512
+		tol = 1e-8
+	)
+	// A linear chirp has a linear instantaneous frequency.
+	f0, f1 := 0.05, 0.2
+	seq := make([]float64, n)
+	for i := range seq {
+		x := float64(i)
+		seq[i] = math.Cos(2 * math.Pi * (f0*x + (f1-f0)*x*x/(2*n)))
+	}
+	z := NewTransform(n).AnalyticSignal(nil, seq)
+	phase := Phase(nil, z)
+	freq := Frequency(nil, z)
+	if len(freq) != n-1 {
+		t.Fatalf("unexpected frequency length: got:%d want:%d", len(freq), n-1)
+	}
+	// Edge effects of the periodic transform are avoided.
+	for i := n / 8; i < n-n/8; i++ {
+		x := float64(i) + 0.5
+		want := f0 + (f1-f0)*x/n
+		if math.Abs(freq[i]-want) > 1e-3 {
+			t.Errorf("unexpected instantaneous frequency at %d: got:%v want:%v", i, freq[i], want)
+		}
+		if got := (phase[i+1] - phase[i]) / (2 * math.Pi); math.Abs(got-freq[i]) > tol {
+			t.Errorf("phase difference does not match frequency at %d: got:%v want:%v", i, got, freq[i])
+		}
+	}
+}
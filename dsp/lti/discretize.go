@@ -0,0 +1,140 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"gonum.org/v1/gonum/mat"
+)
+
+// ZOH returns the discrete-time equivalent with sample time dt of the
+// continuous-time state-space system ss, assuming the input is held
+// constant between samples by a zero-order hold. The discrete system
+// matrices are
+//  Ad = exp(A*dt)
+//  Bd = \int_0^dt exp(A*τ) dτ B
+// with C and D unchanged; they are obtained from the exponential of an
+// augmented matrix so A need not be invertible.
+//
+// ZOH will panic if ss is not a continuous-time system or dt is not positive.
+func ZOH(ss StateSpace, dt float64) StateSpace {
+	checkDiscretize(ss, dt)
+	d := StateSpace{D: mat.DenseCopyOf(ss.D), Dt: dt}
+	n := ss.Order()
+	if n == 0 {
+		return d
+	}
+	m := mat.NewDense(n+1, n+1, nil)
+	m.Slice(0, n, 0, n).(*mat.Dense).Copy(ss.A)
+	m.Slice(0, n, n, n+1).(*mat.Dense).Copy(ss.B)
+	m.Scale(dt, m)
+	var e mat.Dense
+	e.Exp(m)
+	d.A = mat.DenseCopyOf(e.Slice(0, n, 0, n))
+	d.B = mat.DenseCopyOf(e.Slice(0, n, n, n+1))
+	d.C = mat.DenseCopyOf(ss.C)
+	return d
+}
+
+// Bilinear returns the discrete-time equivalent with sample time dt of the
+// continuous-time state-space system ss using the bilinear (Tustin)
+// transform
+//  s = 2/dt * (z - 1)/(z + 1).
+// The transform maps the stable left half plane onto the interior of the
+// unit circle and preserves the frequency response with the frequency axis
+// warped by ω_d = 2/dt * atan(ω_c*dt/2).
+//
+// Bilinear will panic if ss is not a continuous-time system, dt is not
+// positive, or if 2/dt is an eigenvalue of A.
+func Bilinear(ss StateSpace, dt float64) StateSpace {
+	checkDiscretize(ss, dt)
+	n := ss.Order()
+	if n == 0 {
+		return StateSpace{D: mat.DenseCopyOf(ss.D), Dt: dt}
+	}
+	// With M = I - A*dt/2,
+	//  Ad = M^-1 (I + A*dt/2)
+	//  Bd = M^-1 B dt
+	//  Cd = C M^-1
+	//  Dd = D + C M^-1 B dt/2.
+	var lu mat.LU
+	m := mat.NewDense(n, n, nil)
+	m.Scale(-dt/2, ss.A)
+	p := mat.NewDense(n, n, nil)
+	p.Scale(dt/2, ss.A)
+	for i := 0; i < n; i++ {
+		m.Set(i, i, m.At(i, i)+1)
+		p.Set(i, i, p.At(i, i)+1)
+	}
+	lu.Factorize(m)
+
+	var a, b, c mat.Dense
+	if err := lu.SolveTo(&a, false, p); err != nil {
+		panic("lti: singular bilinear transform")
+	}
+	if err := lu.SolveTo(&b, false, ss.B); err != nil {
+		panic("lti: singular bilinear transform")
+	}
+	var ct mat.Dense
+	if err := lu.SolveTo(&ct, true, ss.C.T()); err != nil {
+		panic("lti: singular bilinear transform")
+	}
+	c.CloneFrom(ct.T())
+
+	var cb mat.Dense
+	cb.Mul(&c, ss.B)
+	dd := mat.NewDense(1, 1, []float64{ss.D.At(0, 0) + cb.At(0, 0)*dt/2})
+	b.Scale(dt, &b)
+	return StateSpace{A: &a, B: &b, C: &c, D: dd, Dt: dt}
+}
+
+// BilinearZPK returns the discrete-time equivalent with sample time dt of
+// the continuous-time zero-pole-gain system z using the bilinear transform
+// described for Bilinear. Each zero and pole s is mapped to
+//  (1 + s*dt/2)/(1 - s*dt/2),
+// and zeros at infinity are mapped to z = -1.
+//
+// BilinearZPK will panic if z is not a continuous-time system, dt is not
+// positive, or z has more zeros than poles.
+func BilinearZPK(z ZPK, dt float64) ZPK {
+	if z.Dt != 0 {
+		panic("lti: system not continuous-time")
+	}
+	if dt <= 0 {
+		panic("lti: invalid sample time")
+	}
+	if len(z.Zeros) > len(z.Poles) {
+		panic("lti: improper transfer function")
+	}
+	fs2 := complex(2/dt, 0)
+	gain := complex(z.Gain, 0)
+	d := ZPK{
+		Zeros: make([]complex128, len(z.Poles)),
+		Poles: make([]complex128, len(z.Poles)),
+		Dt:    dt,
+	}
+	for i, v := range z.Zeros {
+		d.Zeros[i] = (fs2 + v) / (fs2 - v)
+		gain *= fs2 - v
+	}
+	for i := len(z.Zeros); i < len(d.Zeros); i++ {
+		d.Zeros[i] = -1
+	}
+	for i, v := range z.Poles {
+		d.Poles[i] = (fs2 + v) / (fs2 - v)
+		gain /= fs2 - v
+	}
+	d.Gain = real(gain)
+	return d
+}
+
+func checkDiscretize(ss StateSpace, dt float64) {
+	ss.check()
+	if ss.Dt != 0 {
+		panic("lti: system not continuous-time")
+	}
+	if dt <= 0 {
+		panic("lti: invalid sample time")
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestZOH(t *testing.T) {
+	const (
+		dt  = 0.1
+		tol = 1e-10
+	)
+	// The zero-order hold equivalent reproduces the continuous
+	// step response exactly at the sample times.
+	for _, tf := range systemTests {
+		if tf.Dt != 0 || len(trimLeading(tf.Num)) >= len(trimLeading(tf.Den)) {
+			continue
+		}
+		ss := tf.StateSpace()
+		d := ZOH(ss, dt)
+		if d.Dt != dt {
+			t.Errorf("unexpected sample time: got:%v want:%v", d.Dt, dt)
+		}
+		ts := make([]float64, 30)
+		for i := range ts {
+			ts[i] = float64(i) * dt
+		}
+		want := ss.Step(nil, ts)
+		got := d.Step(nil, ts)
+		for i := range ts {
+			if math.Abs(got[i]-want[i]) > tol {
+				t.Errorf("unexpected step response for %v/%v at %v: got:%v want:%v", tf.Num, tf.Den, ts[i], got[i], want[i])
+				break
+			}
+		}
+
+		// Poles map to exp(p*dt).
+		poles := ss.Poles()
+		dpoles := d.Poles()
+		for i := range poles {
+			poles[i] = cmplx.Exp(poles[i] * complex(dt, 0))
+		}
+		sortComplex(poles)
+		sortComplex(dpoles)
+		if !equalApprox(dpoles, poles, tol) {
+			t.Errorf("unexpected discrete poles for %v/%v: got:%v want:%v", tf.Num, tf.Den, dpoles, poles)
+		}
+	}
+}
+
+func TestBilinear(t *testing.T) {
+	const (
+		dt  = 0.2
+		tol = 1e-10
+	)
+	for _, tf := range systemTests {
+		if tf.Dt != 0 {
+			continue
+		}
+		d := Bilinear(tf.StateSpace(), dt)
+		dz := BilinearZPK(tf.ZPK(), dt)
+		if !d.IsStable() || !dz.IsStable() {
+			t.Errorf("stable system %v/%v not mapped to stable system", tf.Num, tf.Den)
+		}
+
+		// The discrete response at ω_d matches the continuous
+		// response at the prewarped frequency.
+		wd := []float64{0, 0.5, 1, 3, 10}
+		wc := make([]float64, len(wd))
+		for i, w := range wd {
+			wc[i] = 2 / dt * math.Tan(w*dt/2)
+		}
+		want := tf.Response(nil, wc)
+		if got := d.Response(nil, wd); !equalApprox(got, want, tol) {
+			t.Errorf("unexpected state-space bilinear response for %v/%v:\ngot: %v\nwant:%v", tf.Num, tf.Den, got, want)
+		}
+		if got := dz.Response(nil, wd); !equalApprox(got, want, tol) {
+			t.Errorf("unexpected ZPK bilinear response for %v/%v:\ngot: %v\nwant:%v", tf.Num, tf.Den, got, want)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package lti provides representations and analysis of single-input
+// single-output linear time-invariant systems.
+//
+// A system may be represented as a rational transfer function, by the
+// zeros, poles and gain of its transfer function, or in state-space form
+//  x' = A x + B u
+//  y  = C x + D u
+// where x' is the time derivative of the state for continuous-time systems
+// or the state at the next sample for discrete-time systems. Each
+// representation records the sample time of the system, Dt, which is zero
+// for continuous-time systems.
+//
+// Polynomial coefficients are held in order of descending powers of s for
+// continuous-time systems and of z for discrete-time systems. Frequencies
+// are angular frequencies in radians per unit time, so that for a
+// discrete-time system with unit sample time they are in radians per sample.
+package lti // import "gonum.org/v1/gonum/dsp/lti"
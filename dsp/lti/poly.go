@@ -0,0 +1,133 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Roots returns the roots of the polynomial with coefficients p in
+// descending powers. The roots are the eigenvalues of the companion
+// matrix of the polynomial, computed using mat.Eigen. Leading zero
+// coefficients are ignored and trailing zero coefficients give roots
+// at zero.
+//
+// Roots will panic if the eigenvalue decomposition fails.
+func Roots(p []float64) []complex128 {
+	p = trimLeading(p)
+	if len(p) < 2 {
+		return nil
+	}
+	var zeros int
+	for len(p) > 1 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+		zeros++
+	}
+	n := len(p) - 1
+	roots := make([]complex128, 0, n+zeros)
+	switch n {
+	case 0:
+	case 1:
+		roots = append(roots, complex(-p[1]/p[0], 0))
+	default:
+		c := mat.NewDense(n, n, nil)
+		for j := 0; j < n; j++ {
+			c.Set(0, j, -p[j+1]/p[0])
+		}
+		for i := 1; i < n; i++ {
+			c.Set(i, i-1, 1)
+		}
+		var eig mat.Eigen
+		ok := eig.Factorize(c, mat.EigenNone)
+		if !ok {
+			panic("lti: eigendecomposition failed")
+		}
+		roots = eig.Values(roots[:n])
+	}
+	for i := 0; i < zeros; i++ {
+		roots = append(roots, 0)
+	}
+	return roots
+}
+
+// Poly returns the coefficients in descending powers of the monic
+// polynomial with the given roots. Complex roots must appear in conjugate
+// pairs for the result to be real; the imaginary part of the expanded
+// coefficients is discarded.
+func Poly(roots []complex128) []float64 {
+	c := make([]complex128, 1, len(roots)+1)
+	c[0] = 1
+	for _, r := range roots {
+		c = append(c, 0)
+		for i := len(c) - 1; i > 0; i-- {
+			c[i] -= r * c[i-1]
+		}
+	}
+	p := make([]float64, len(c))
+	for i, v := range c {
+		p[i] = real(v)
+	}
+	return p
+}
+
+// polyval evaluates the polynomial with coefficients p in descending
+// powers at x using Horner's method.
+func polyval(p []float64, x complex128) complex128 {
+	var v complex128
+	for _, c := range p {
+		v = v*x + complex(c, 0)
+	}
+	return v
+}
+
+// polyder returns the coefficients of the derivative of the polynomial p.
+func polyder(p []float64) []float64 {
+	if len(p) < 2 {
+		return nil
+	}
+	n := len(p) - 1
+	d := make([]float64, n)
+	for i := range d {
+		d[i] = p[i] * float64(n-i)
+	}
+	return d
+}
+
+// polyadd returns the sum of the polynomials a and b.
+func polyadd(a, b []float64) []float64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	s := make([]float64, len(a))
+	copy(s, a)
+	off := len(a) - len(b)
+	for i, v := range b {
+		s[off+i] += v
+	}
+	return s
+}
+
+// trimLeading returns p with leading zero coefficients removed.
+func trimLeading(p []float64) []float64 {
+	for len(p) > 0 && p[0] == 0 {
+		p = p[1:]
+	}
+	return p
+}
+
+// trimSmall returns p with leading coefficients smaller than tol times
+// the largest coefficient removed.
+func trimSmall(p []float64, tol float64) []float64 {
+	var max float64
+	for _, v := range p {
+		max = math.Max(max, math.Abs(v))
+	}
+	for len(p) > 1 && math.Abs(p[0]) <= tol*max {
+		p = p[1:]
+	}
+	return p
+}
@@ -0,0 +1,73 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math/cmplx"
+	"sort"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestRoots(t *testing.T) {
+	const tol = 1e-10
+	for _, test := range []struct {
+		p    []float64
+		want []complex128
+	}{
+		{p: nil, want: nil},
+		{p: []float64{3}, want: nil},
+		{p: []float64{0, 0, 2, -4}, want: []complex128{2}},
+		{p: []float64{1, -3, 2}, want: []complex128{1, 2}},
+		{p: []float64{1, 0, 1}, want: []complex128{-1i, 1i}},
+		{p: []float64{2, -2, 0, 0}, want: []complex128{0, 0, 1}},
+		{p: []float64{1, -6, 11, -6}, want: []complex128{1, 2, 3}},
+		{p: []float64{1, 2, 2}, want: []complex128{-1 - 1i, -1 + 1i}},
+	} {
+		got := Roots(test.p)
+		sortComplex(got)
+		if len(got) != len(test.want) {
+			t.Errorf("unexpected number of roots for %v: got:%v want:%v", test.p, got, test.want)
+			continue
+		}
+		for i := range got {
+			if cmplx.Abs(got[i]-test.want[i]) > tol {
+				t.Errorf("unexpected roots for %v: got:%v want:%v", test.p, got, test.want)
+				break
+			}
+		}
+	}
+}
+
+func TestPoly(t *testing.T) {
+	const tol = 1e-12
+	for _, test := range []struct {
+		roots []complex128
+		want  []float64
+	}{
+		{roots: nil, want: []float64{1}},
+		{roots: []complex128{2}, want: []float64{1, -2}},
+		{roots: []complex128{1, 2, 3}, want: []float64{1, -6, 11, -6}},
+		{roots: []complex128{-1 + 1i, -1 - 1i}, want: []float64{1, 2, 2}},
+		{roots: []complex128{0, 1i, -1i}, want: []float64{1, 0, 1, 0}},
+	} {
+		got := Poly(test.roots)
+		if !floats.EqualApprox(got, test.want, tol) {
+			t.Errorf("unexpected polynomial for roots %v: got:%v want:%v", test.roots, got, test.want)
+		}
+	}
+}
+
+// sortComplex sorts s by real and then imaginary part.
+func sortComplex(s []complex128) {
+	sort.Slice(s, func(i, j int) bool {
+		const tol = 1e-9
+		if d := real(s[i]) - real(s[j]); d < -tol || tol < d {
+			return d < 0
+		}
+		return imag(s[i]) < imag(s[j])
+	})
+}
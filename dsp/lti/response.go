@@ -0,0 +1,278 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Response computes the frequency response of the transfer function at the
+// angular frequencies in w, placing the result in dst and returning it. For
+// continuous-time systems the transfer function is evaluated at s = iω and
+// for discrete-time systems at z = exp(iω*Dt).
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of w, Response will panic.
+func (tf TransferFunction) Response(dst []complex128, w []float64) []complex128 {
+	if dst == nil {
+		dst = make([]complex128, len(w))
+	} else if len(dst) != len(w) {
+		panic("lti: destination length mismatch")
+	}
+	for i, f := range w {
+		x := tf.point(f)
+		dst[i] = polyval(tf.Num, x) / polyval(tf.Den, x)
+	}
+	return dst
+}
+
+// GroupDelay computes the group delay of the transfer function, the negative
+// derivative of the phase of the frequency response with respect to angular
+// frequency, at the frequencies in w, placing the result in dst and
+// returning it. The delay is in units of time; for a discrete-time system
+// with unit sample time it is in samples. The group delay is not defined at
+// zeros of the frequency response and the result there is not finite.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of w, GroupDelay will panic.
+func (tf TransferFunction) GroupDelay(dst, w []float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, len(w))
+	} else if len(dst) != len(w) {
+		panic("lti: destination length mismatch")
+	}
+	dnum := polyder(tf.Num)
+	dden := polyder(tf.Den)
+	for i, f := range w {
+		x := tf.point(f)
+		// The derivative of log H with respect to the evaluation
+		// point gives the derivative of the phase by the chain rule.
+		dlog := polyval(dnum, x)/polyval(tf.Num, x) - polyval(dden, x)/polyval(tf.Den, x)
+		if tf.Dt == 0 {
+			// dx/dω = i.
+			dst[i] = -real(dlog)
+		} else {
+			// dx/dω = i*Dt*x.
+			dst[i] = -tf.Dt * real(x*dlog)
+		}
+	}
+	return dst
+}
+
+// point returns the point of evaluation of the transfer function for the
+// angular frequency w.
+func (tf TransferFunction) point(w float64) complex128 {
+	if tf.Dt == 0 {
+		return complex(0, w)
+	}
+	return cmplx.Rect(1, w*tf.Dt)
+}
+
+// Response computes the frequency response of the zero-pole-gain system at
+// the angular frequencies in w, placing the result in dst and returning it.
+// The response is evaluated as described for TransferFunction.Response.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of w, Response will panic.
+func (z ZPK) Response(dst []complex128, w []float64) []complex128 {
+	if dst == nil {
+		dst = make([]complex128, len(w))
+	} else if len(dst) != len(w) {
+		panic("lti: destination length mismatch")
+	}
+	tf := TransferFunction{Dt: z.Dt}
+	for i, f := range w {
+		x := tf.point(f)
+		h := complex(z.Gain, 0)
+		for _, v := range z.Zeros {
+			h *= x - v
+		}
+		for _, v := range z.Poles {
+			h /= x - v
+		}
+		dst[i] = h
+	}
+	return dst
+}
+
+// Response computes the frequency response of the state-space system at the
+// angular frequencies in w, placing the result in dst and returning it.
+// The response is evaluated as described for TransferFunction.Response.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of w, Response will panic.
+func (ss StateSpace) Response(dst []complex128, w []float64) []complex128 {
+	return ss.TransferFunction().Response(dst, w)
+}
+
+// Impulse computes the impulse response of the state-space system at the
+// times in t, placing the result in dst and returning it. For continuous-time
+// systems the response is C*exp(A*t)*B, excluding the impulse D*δ(t) at zero.
+// For discrete-time systems the response to a unit sample at time zero is
+// evaluated at the sample nearest to each time, and is zero for times
+// before zero.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of t, Impulse will panic.
+// Impulse will panic for continuous-time systems if any time is negative.
+func (ss StateSpace) Impulse(dst, t []float64) []float64 {
+	return ss.timeResponse(dst, t, false)
+}
+
+// Step computes the response of the state-space system to a unit step at
+// time zero at the times in t, placing the result in dst and returning it.
+// For discrete-time systems the response is evaluated at the sample nearest
+// to each time, and is zero for times before zero.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of t, Step will panic.
+// Step will panic for continuous-time systems if any time is negative.
+func (ss StateSpace) Step(dst, t []float64) []float64 {
+	return ss.timeResponse(dst, t, true)
+}
+
+func (ss StateSpace) timeResponse(dst, t []float64, step bool) []float64 {
+	ss.check()
+	if dst == nil {
+		dst = make([]float64, len(t))
+	} else if len(dst) != len(t) {
+		panic("lti: destination length mismatch")
+	}
+	n := ss.Order()
+	d := ss.D.At(0, 0)
+
+	if ss.Dt != 0 {
+		// Simulate the difference equation up to the last
+		// sample required.
+		var last int
+		for _, v := range t {
+			if k := int(math.Round(v / ss.Dt)); k > last {
+				last = k
+			}
+		}
+		y := make([]float64, last+1)
+		var x, next mat.VecDense
+		if n != 0 {
+			x.ReuseAsVec(n)
+		}
+		for k := range y {
+			u := 0.0
+			if step || k == 0 {
+				u = 1
+			}
+			y[k] = d * u
+			if n == 0 {
+				continue
+			}
+			y[k] += mat.Dot(ss.C.RowView(0), &x)
+			next.MulVec(ss.A, &x)
+			next.AddScaledVec(&next, u, ss.B.ColView(0))
+			x.CopyVec(&next)
+		}
+		for i, v := range t {
+			k := int(math.Round(v / ss.Dt))
+			if k < 0 {
+				dst[i] = 0
+				continue
+			}
+			dst[i] = y[k]
+		}
+		return dst
+	}
+
+	for _, v := range t {
+		if v < 0 {
+			panic("lti: negative time")
+		}
+	}
+	if n == 0 {
+		for i := range dst {
+			if step {
+				dst[i] = d
+			} else {
+				dst[i] = 0
+			}
+		}
+		return dst
+	}
+
+	// The exponential of the augmented matrix
+	//  [A B]
+	//  [0 0] t
+	// contains exp(A*t) and the integral of exp(A*τ) over [0, t]
+	// applied to B, giving the impulse and step responses.
+	m := mat.NewDense(n+1, n+1, nil)
+	m.Slice(0, n, 0, n).(*mat.Dense).Copy(ss.A)
+	m.Slice(0, n, n, n+1).(*mat.Dense).Copy(ss.B)
+	var at, e mat.Dense
+	var y mat.VecDense
+	for i, v := range t {
+		at.Scale(v, m)
+		e.Exp(&at)
+		if step {
+			dst[i] = mat.Dot(ss.C.RowView(0), e.ColView(n).(*mat.VecDense).SliceVec(0, n)) + d
+		} else {
+			y.MulVec(e.Slice(0, n, 0, n), ss.B.ColView(0))
+			dst[i] = mat.Dot(ss.C.RowView(0), &y)
+		}
+	}
+	return dst
+}
+
+// Impulse computes the impulse response of the transfer function at the
+// times in t, as described for StateSpace.Impulse.
+func (tf TransferFunction) Impulse(dst, t []float64) []float64 {
+	return tf.StateSpace().Impulse(dst, t)
+}
+
+// Step computes the step response of the transfer function at the times in
+// t, as described for StateSpace.Step.
+func (tf TransferFunction) Step(dst, t []float64) []float64 {
+	return tf.StateSpace().Step(dst, t)
+}
+
+// Filter filters the sequence in x with the discrete-time transfer function
+//  H(z) = (b[0] + b[1] z^-1 + ... + b[m] z^-m) / (a[0] + a[1] z^-1 + ... + a[n] z^-n)
+// using the direct form II transposed difference equation, placing the
+// result in dst and returning it. The initial state of the filter is zero.
+// It is safe to use the same slice for dst and x.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil and
+// the length of dst does not equal the length of x, Filter will panic.
+// Filter will panic if a is empty or a[0] is zero.
+func Filter(dst, b, a, x []float64) []float64 {
+	if len(a) == 0 || a[0] == 0 {
+		panic("lti: invalid denominator")
+	}
+	if dst == nil {
+		dst = make([]float64, len(x))
+	} else if len(dst) != len(x) {
+		panic("lti: destination length mismatch")
+	}
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	bn := make([]float64, n)
+	an := make([]float64, n)
+	for i, v := range b {
+		bn[i] = v / a[0]
+	}
+	for i, v := range a {
+		an[i] = v / a[0]
+	}
+	z := make([]float64, n)
+	for i, v := range x {
+		y := bn[0]*v + z[0]
+		for k := 1; k < n; k++ {
+			z[k-1] = bn[k]*v - an[k]*y + z[k]
+		}
+		dst[i] = y
+	}
+	return dst
+}
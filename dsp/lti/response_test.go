@@ -0,0 +1,176 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestResponse(t *testing.T) {
+	const tol = 1e-12
+	// A first order low-pass filter has unit gain at zero frequency
+	// and a gain of 1/√2 with a phase of -π/4 at its corner frequency.
+	tf := TransferFunction{Num: []float64{2}, Den: []float64{1, 2}}
+	h := tf.Response(nil, []float64{0, 2, 1e6})
+	if cmplx.Abs(h[0]-1) > tol {
+		t.Errorf("unexpected DC response: got:%v want:1", h[0])
+	}
+	if math.Abs(cmplx.Abs(h[1])-1/math.Sqrt2) > tol || math.Abs(cmplx.Phase(h[1])+math.Pi/4) > tol {
+		t.Errorf("unexpected corner response: got:%v", h[1])
+	}
+	if cmplx.Abs(h[2]) > 1e-5 {
+		t.Errorf("unexpected high frequency response: got:%v", h[2])
+	}
+
+	// A discrete moving average of length 4 has zeros at multiples
+	// of a quarter of the sampling frequency.
+	ma := TransferFunction{Num: []float64{0.25, 0.25, 0.25, 0.25}, Den: []float64{1, 0, 0, 0}, Dt: 1}
+	h = ma.Response(nil, []float64{0, math.Pi / 2, math.Pi})
+	if cmplx.Abs(h[0]-1) > tol || cmplx.Abs(h[1]) > tol || cmplx.Abs(h[2]) > tol {
+		t.Errorf("unexpected moving average response: got:%v", h)
+	}
+}
+
+func TestGroupDelay(t *testing.T) {
+	const tol = 1e-10
+	w := []float64{0.1, 0.5, 1, 2, 3}
+
+	// A symmetric FIR filter of length n has a constant
+	// delay of (n-1)/2 samples.
+	fir := TransferFunction{Num: []float64{1, 3, 5, 3, 1}, Den: []float64{1, 0, 0, 0, 0}, Dt: 1}
+	for i, gd := range fir.GroupDelay(nil, w) {
+		if math.Abs(gd-2) > tol {
+			t.Errorf("unexpected FIR group delay at %v: got:%v want:2", w[i], gd)
+		}
+	}
+	// The delay scales with the sample time.
+	fir.Dt = 0.5
+	for i, gd := range fir.GroupDelay(nil, w) {
+		if math.Abs(gd-1) > tol {
+			t.Errorf("unexpected FIR group delay at %v with Dt=0.5: got:%v want:1", w[i], gd)
+		}
+	}
+
+	// The group delay of a/(s+a) is a/(a²+ω²).
+	const a = 3
+	tf := TransferFunction{Num: []float64{a}, Den: []float64{1, a}}
+	for i, gd := range tf.GroupDelay(nil, w) {
+		want := a / (a*a + w[i]*w[i])
+		if math.Abs(gd-want) > tol {
+			t.Errorf("unexpected group delay at %v: got:%v want:%v", w[i], gd, want)
+		}
+	}
+
+	// Compare with a numerical derivative of the phase.
+	tf = TransferFunction{Num: []float64{1, -0.3, 0.2}, Den: []float64{1, -0.5, 0.3, 0.1}, Dt: 1}
+	const h = 1e-6
+	gd := tf.GroupDelay(nil, w)
+	for i, f := range w {
+		r := tf.Response(nil, []float64{f - h, f + h})
+		want := -(cmplx.Phase(r[1] / r[0])) / (2 * h)
+		if math.Abs(gd[i]-want) > 1e-6 {
+			t.Errorf("unexpected group delay at %v: got:%v want:%v", f, gd[i], want)
+		}
+	}
+}
+
+func TestImpulseStep(t *testing.T) {
+	const tol = 1e-10
+	ts := []float64{0, 0.1, 0.5, 1, 2, 5}
+
+	// The impulse and step responses of 1/(s+1)
+	// are exp(-t) and 1-exp(-t).
+	tf := TransferFunction{Num: []float64{1}, Den: []float64{1, 1}}
+	imp := tf.Impulse(nil, ts)
+	step := tf.Step(nil, ts)
+	for i, v := range ts {
+		if math.Abs(imp[i]-math.Exp(-v)) > tol {
+			t.Errorf("unexpected impulse response at %v: got:%v want:%v", v, imp[i], math.Exp(-v))
+		}
+		if math.Abs(step[i]-(1-math.Exp(-v))) > tol {
+			t.Errorf("unexpected step response at %v: got:%v want:%v", v, step[i], 1-math.Exp(-v))
+		}
+	}
+
+	// An underdamped second order system, ω²/(s²+2ζωs+ω²).
+	const (
+		omega = 2.0
+		zeta  = 0.3
+	)
+	tf = TransferFunction{Num: []float64{omega * omega}, Den: []float64{1, 2 * zeta * omega, omega * omega}}
+	step = tf.Step(nil, ts)
+	wd := omega * math.Sqrt(1-zeta*zeta)
+	phi := math.Acos(zeta)
+	for i, v := range ts {
+		want := 1 - math.Exp(-zeta*omega*v)*math.Sin(wd*v+phi)/math.Sqrt(1-zeta*zeta)
+		if math.Abs(step[i]-want) > tol {
+			t.Errorf("unexpected second order step response at %v: got:%v want:%v", v, step[i], want)
+		}
+	}
+
+	// Static gain.
+	tf = TransferFunction{Num: []float64{3}, Den: []float64{2}}
+	if got := tf.Step(nil, ts); !floats.EqualApprox(got, []float64{1.5, 1.5, 1.5, 1.5, 1.5, 1.5}, tol) {
+		t.Errorf("unexpected static gain step response: got:%v", got)
+	}
+}
+
+func TestDiscreteImpulseStep(t *testing.T) {
+	const tol = 1e-12
+	b := []float64{0.5, 0.2, -0.1}
+	a := []float64{1, -0.6, 0.25}
+	tf := TransferFunction{Num: b, Den: a, Dt: 0.5}
+
+	const n = 20
+	ts := make([]float64, n+2)
+	unit := make([]float64, n)
+	ones := make([]float64, n)
+	for i := range unit {
+		ts[i] = float64(i) * tf.Dt
+		ones[i] = 1
+	}
+	ts[n] = -1
+	ts[n+1] = 3.1 * tf.Dt
+	unit[0] = 1
+
+	wantImp := Filter(nil, b, a, unit)
+	wantStep := Filter(nil, b, a, ones)
+	imp := tf.Impulse(nil, ts)
+	step := tf.Step(nil, ts)
+	if !floats.EqualApprox(imp[:n], wantImp, tol) {
+		t.Errorf("unexpected impulse response:\ngot: %v\nwant:%v", imp[:n], wantImp)
+	}
+	if !floats.EqualApprox(step[:n], wantStep, tol) {
+		t.Errorf("unexpected step response:\ngot: %v\nwant:%v", step[:n], wantStep)
+	}
+	if imp[n] != 0 || step[n] != 0 {
+		t.Errorf("unexpected response before time zero: got:%v %v", imp[n], step[n])
+	}
+	if imp[n+1] != imp[3] || step[n+1] != step[3] {
+		t.Errorf("unexpected response between samples")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	const tol = 1e-12
+	// y[k] = x[k] + 0.5*y[k-1].
+	x := []float64{1, 0, 0, 0, 2}
+	got := Filter(nil, []float64{2}, []float64{2, -1}, x)
+	want := []float64{1, 0.5, 0.25, 0.125, 2.0625}
+	if !floats.EqualApprox(got, want, tol) {
+		t.Errorf("unexpected IIR filter output: got:%v want:%v", got, want)
+	}
+	// A moving average FIR filter, filtered in place.
+	x = []float64{1, 2, 3, 4, 5}
+	got = Filter(x, []float64{0.5, 0.5}, []float64{1}, x)
+	want = []float64{0.5, 1.5, 2.5, 3.5, 4.5}
+	if !floats.EqualApprox(got, want, tol) {
+		t.Errorf("unexpected FIR filter output: got:%v want:%v", got, want)
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"math"
+	"math/cmplx"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// TransferFunction is a rational transfer function
+//  H(s) = (Num[0]*s^m + ... + Num[m]) / (Den[0]*s^n + ... + Den[n]),
+// with s replaced by z for discrete-time systems.
+type TransferFunction struct {
+	// Num and Den are the coefficients
+	// of the numerator and denominator
+	// polynomials in descending powers.
+	Num, Den []float64
+
+	// Dt is the sample time of the system.
+	// Dt is zero for continuous-time systems.
+	Dt float64
+}
+
+// ZPK is a transfer function represented by its zeros, poles and gain,
+//  H(s) = Gain * \prod_i (s - Zeros[i]) / \prod_i (s - Poles[i]),
+// with s replaced by z for discrete-time systems. Complex zeros and poles
+// must appear in conjugate pairs.
+type ZPK struct {
+	Zeros, Poles []complex128
+	Gain         float64
+
+	// Dt is the sample time of the system.
+	// Dt is zero for continuous-time systems.
+	Dt float64
+}
+
+// StateSpace is a single-input single-output state-space system
+//  x' = A x + B u
+//  y  = C x + D u
+// where A is n×n, B is n×1, C is 1×n and D is 1×1. A system without state
+// has nil A, B and C.
+type StateSpace struct {
+	A, B, C, D *mat.Dense
+
+	// Dt is the sample time of the system.
+	// Dt is zero for continuous-time systems.
+	Dt float64
+}
+
+// Order returns the number of states of the system.
+func (ss StateSpace) Order() int {
+	if ss.A == nil {
+		return 0
+	}
+	r, _ := ss.A.Dims()
+	return r
+}
+
+// check panics if the dimensions of the state-space matrices are not
+// consistent with a single-input single-output system.
+func (ss StateSpace) check() {
+	if ss.D == nil {
+		panic("lti: nil feedthrough matrix")
+	}
+	if r, c := ss.D.Dims(); r != 1 || c != 1 {
+		panic("lti: system not SISO")
+	}
+	if ss.A == nil {
+		if ss.B != nil || ss.C != nil {
+			panic(mat.ErrShape)
+		}
+		return
+	}
+	if ss.B == nil || ss.C == nil {
+		panic(mat.ErrShape)
+	}
+	n, c := ss.A.Dims()
+	if n != c {
+		panic(mat.ErrSquare)
+	}
+	if r, c := ss.B.Dims(); r != n || c != 1 {
+		panic(mat.ErrShape)
+	}
+	if r, c := ss.C.Dims(); r != 1 || c != n {
+		panic(mat.ErrShape)
+	}
+}
+
+// ZPK returns the zero-pole-gain representation of the transfer function.
+//
+// ZPK will panic if the denominator is zero.
+func (tf TransferFunction) ZPK() ZPK {
+	num, den := normalize(tf.Num, tf.Den)
+	z := ZPK{Zeros: Roots(num), Poles: Roots(den), Dt: tf.Dt}
+	if len(num) != 0 {
+		z.Gain = num[0]
+	}
+	return z
+}
+
+// StateSpace returns the state-space realization of the transfer function
+// in controller canonical form.
+//
+// StateSpace will panic if the denominator is zero or if the transfer
+// function is improper, with a numerator of higher degree than the
+// denominator.
+func (tf TransferFunction) StateSpace() StateSpace {
+	num, den := normalize(tf.Num, tf.Den)
+	if len(num) > len(den) {
+		panic("lti: improper transfer function")
+	}
+	n := len(den) - 1
+	// Pad the numerator to the length of the denominator.
+	b := make([]float64, len(den))
+	copy(b[len(b)-len(num):], num)
+
+	ss := StateSpace{D: mat.NewDense(1, 1, []float64{b[0]}), Dt: tf.Dt}
+	if n == 0 {
+		return ss
+	}
+	ss.A = mat.NewDense(n, n, nil)
+	ss.B = mat.NewDense(n, 1, nil)
+	ss.C = mat.NewDense(1, n, nil)
+	for j := 0; j < n; j++ {
+		ss.A.Set(0, j, -den[j+1])
+		ss.C.Set(0, j, b[j+1]-b[0]*den[j+1])
+	}
+	for i := 1; i < n; i++ {
+		ss.A.Set(i, i-1, 1)
+	}
+	ss.B.Set(0, 0, 1)
+	return ss
+}
+
+// Poles returns the poles of the transfer function, the roots of its
+// denominator.
+func (tf TransferFunction) Poles() []complex128 { return Roots(tf.Den) }
+
+// Zeros returns the zeros of the transfer function, the roots of its
+// numerator.
+func (tf TransferFunction) Zeros() []complex128 { return Roots(tf.Num) }
+
+// IsStable returns whether the system is asymptotically stable.
+func (tf TransferFunction) IsStable() bool { return Stable(tf.Poles(), tf.Dt) }
+
+// TransferFunction returns the transfer function represented by z.
+func (z ZPK) TransferFunction() TransferFunction {
+	num := Poly(z.Zeros)
+	for i := range num {
+		num[i] *= z.Gain
+	}
+	return TransferFunction{Num: num, Den: Poly(z.Poles), Dt: z.Dt}
+}
+
+// StateSpace returns a state-space realization of the system represented
+// by z in controller canonical form.
+//
+// StateSpace will panic if z has more zeros than poles.
+func (z ZPK) StateSpace() StateSpace { return z.TransferFunction().StateSpace() }
+
+// IsStable returns whether the system is asymptotically stable.
+func (z ZPK) IsStable() bool { return Stable(z.Poles, z.Dt) }
+
+// TransferFunction returns the transfer function of the state-space system.
+// The denominator is the characteristic polynomial of A and the numerator
+// is obtained from the characteristic polynomial of A - B*C, both computed
+// from the eigenvalues of the matrices.
+//
+// TransferFunction will panic if the dimensions of the system matrices are
+// not consistent.
+func (ss StateSpace) TransferFunction() TransferFunction {
+	ss.check()
+	d := ss.D.At(0, 0)
+	if ss.A == nil {
+		return TransferFunction{Num: []float64{d}, Den: []float64{1}, Dt: ss.Dt}
+	}
+	den := Poly(eigenvalues(ss.A))
+
+	// For a single-input single-output system
+	//  det(sI - A + B*C) = det(sI - A) * (1 + C(sI - A)^-1 B),
+	// so the numerator of C(sI - A)^-1 B + D is
+	//  det(sI - A + B*C) + (D - 1) det(sI - A).
+	var abc mat.Dense
+	abc.Mul(ss.B, ss.C)
+	abc.Sub(ss.A, &abc)
+	num := Poly(eigenvalues(&abc))
+	for i := range num {
+		num[i] += (d - 1) * den[i]
+	}
+	return TransferFunction{Num: trimSmall(num, 1e-14), Den: den, Dt: ss.Dt}
+}
+
+// ZPK returns the zero-pole-gain representation of the state-space system.
+func (ss StateSpace) ZPK() ZPK { return ss.TransferFunction().ZPK() }
+
+// Poles returns the poles of the state-space system, the eigenvalues of A.
+func (ss StateSpace) Poles() []complex128 {
+	ss.check()
+	if ss.A == nil {
+		return nil
+	}
+	return eigenvalues(ss.A)
+}
+
+// IsStable returns whether the system is asymptotically stable.
+func (ss StateSpace) IsStable() bool { return Stable(ss.Poles(), ss.Dt) }
+
+// Stable returns whether all the given poles lie in the stable region for
+// a system with the sample time dt. For continuous-time systems, with dt
+// equal to zero, the poles must have negative real parts. For discrete-time
+// systems the poles must lie strictly within the unit circle.
+func Stable(poles []complex128, dt float64) bool {
+	for _, p := range poles {
+		if dt == 0 {
+			if real(p) >= 0 {
+				return false
+			}
+		} else if cmplx.Abs(p) >= 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize returns the numerator and denominator with leading zeros
+// removed, scaled so that the denominator is monic.
+func normalize(num, den []float64) (n, d []float64) {
+	den = trimLeading(den)
+	if len(den) == 0 {
+		panic("lti: zero denominator")
+	}
+	num = trimLeading(num)
+	n = make([]float64, len(num))
+	d = make([]float64, len(den))
+	for i, v := range num {
+		n[i] = v / den[0]
+	}
+	for i, v := range den {
+		d[i] = v / den[0]
+	}
+	return n, d
+}
+
+// eigenvalues returns the eigenvalues of the square matrix a.
+func eigenvalues(a mat.Matrix) []complex128 {
+	var eig mat.Eigen
+	ok := eig.Factorize(a, mat.EigenNone)
+	if !ok {
+		panic("lti: eigendecomposition failed")
+	}
+	v := eig.Values(nil)
+	// Remove rounding noise from real eigenvalues so
+	// that the expanded polynomials are exactly real.
+	for i, e := range v {
+		if math.Abs(imag(e)) <= 1e-14*cmplx.Abs(e) {
+			v[i] = complex(real(e), 0)
+		}
+	}
+	return v
+}
@@ -0,0 +1,121 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lti
+
+import (
+	"fmt"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var systemTests = []TransferFunction{
+	{Num: []float64{1}, Den: []float64{1, 1}},
+	{Num: []float64{2}, Den: []float64{4}},
+	{Num: []float64{1, 3}, Den: []float64{1, 2, 5}},
+	{Num: []float64{2, 0, 1}, Den: []float64{1, 4, 6, 4}},
+	{Num: []float64{1, 0.5, 0.25}, Den: []float64{2, 1, 0.5}},
+	{Num: []float64{0.2, 0.3}, Den: []float64{1, -0.5}, Dt: 0.1},
+	{Num: []float64{1, -1, 0.5}, Den: []float64{1, 0.1, -0.2, 0.05}, Dt: 1},
+}
+
+func TestConversions(t *testing.T) {
+	const tol = 1e-10
+	w := []float64{0, 0.1, 0.5, 1, 2, 3, 10}
+	for _, tf := range systemTests {
+		t.Run(fmt.Sprintf("%v/%v", tf.Num, tf.Den), func(t *testing.T) {
+			want := tf.Response(nil, w)
+
+			zpk := tf.ZPK()
+			if got := zpk.Response(nil, w); !equalApprox(got, want, tol) {
+				t.Errorf("unexpected ZPK response:\ngot: %v\nwant:%v", got, want)
+			}
+			if got := zpk.TransferFunction().Response(nil, w); !equalApprox(got, want, tol) {
+				t.Errorf("unexpected ZPK round trip response:\ngot: %v\nwant:%v", got, want)
+			}
+
+			ss := tf.StateSpace()
+			if got := ss.Response(nil, w); !equalApprox(got, want, tol) {
+				t.Errorf("unexpected state-space response:\ngot: %v\nwant:%v", got, want)
+			}
+			if got := ss.ZPK().Response(nil, w); !equalApprox(got, want, tol) {
+				t.Errorf("unexpected state-space to ZPK response:\ngot: %v\nwant:%v", got, want)
+			}
+			if got := zpk.StateSpace().Response(nil, w); !equalApprox(got, want, tol) {
+				t.Errorf("unexpected ZPK to state-space response:\ngot: %v\nwant:%v", got, want)
+			}
+		})
+	}
+}
+
+func TestStateSpaceTransferFunction(t *testing.T) {
+	const tol = 1e-10
+	// A modal realization of 1/(s+1) + 2/(s+3) + 0.5.
+	ss := StateSpace{
+		A: mat.NewDense(2, 2, []float64{-1, 0, 0, -3}),
+		B: mat.NewDense(2, 1, []float64{1, 1}),
+		C: mat.NewDense(1, 2, []float64{1, 2}),
+		D: mat.NewDense(1, 1, []float64{0.5}),
+	}
+	tf := ss.TransferFunction()
+	// (s+3) + 2(s+1) + 0.5(s+1)(s+3) = 0.5s² + 5s + 6.5.
+	wantNum := []float64{0.5, 5, 6.5}
+	wantDen := []float64{1, 4, 3}
+	if !floats.EqualApprox(tf.Num, wantNum, tol) || !floats.EqualApprox(tf.Den, wantDen, tol) {
+		t.Errorf("unexpected transfer function: got:%v/%v want:%v/%v", tf.Num, tf.Den, wantNum, wantDen)
+	}
+}
+
+func TestStable(t *testing.T) {
+	for _, test := range []struct {
+		sys  interface{ IsStable() bool }
+		want bool
+	}{
+		{sys: TransferFunction{Num: []float64{1}, Den: []float64{1, 3, 2}}, want: true},
+		{sys: TransferFunction{Num: []float64{1}, Den: []float64{1, -1, 2}}, want: false},
+		{sys: TransferFunction{Num: []float64{1}, Den: []float64{1, 0, 1}}, want: false},
+		{sys: TransferFunction{Num: []float64{1}, Den: []float64{1, -0.5}, Dt: 1}, want: true},
+		{sys: TransferFunction{Num: []float64{1}, Den: []float64{1, -1.5}, Dt: 1}, want: false},
+		{sys: ZPK{Poles: []complex128{-1 + 2i, -1 - 2i}, Gain: 1}, want: true},
+		{sys: ZPK{Poles: []complex128{0.9i, -0.9i}, Gain: 1, Dt: 0.5}, want: true},
+		{sys: ZPK{Poles: []complex128{1}, Gain: 1, Dt: 0.5}, want: false},
+		{
+			sys: StateSpace{
+				A: mat.NewDense(2, 2, []float64{0, 1, -2, -3}),
+				B: mat.NewDense(2, 1, []float64{0, 1}),
+				C: mat.NewDense(1, 2, []float64{1, 0}),
+				D: mat.NewDense(1, 1, nil),
+			},
+			want: true,
+		},
+		{
+			sys: StateSpace{
+				A: mat.NewDense(2, 2, []float64{0, 1, 2, -1}),
+				B: mat.NewDense(2, 1, []float64{0, 1}),
+				C: mat.NewDense(1, 2, []float64{1, 0}),
+				D: mat.NewDense(1, 1, nil),
+			},
+			want: false,
+		},
+	} {
+		if got := test.sys.IsStable(); got != test.want {
+			t.Errorf("unexpected stability for %+v: got:%t want:%t", test.sys, got, test.want)
+		}
+	}
+}
+
+func equalApprox(a, b []complex128, tol float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if cmplx.Abs(a[i]-b[i]) > tol*(1+cmplx.Abs(b[i])) {
+			return false
+		}
+	}
+	return true
+}
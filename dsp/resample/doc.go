@@ -0,0 +1,16 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package resample provides functions to convert sequences between
+// sample rates.
+//
+// Rational rate conversion by a factor of up/down is performed with a
+// polyphase FIR filter that combines upsampling, anti-aliasing low-pass
+// filtering and downsampling without computing the discarded samples.
+// Band-limited periodic sequences may also be resampled exactly in the
+// frequency domain using FFT.
+//
+// Frequencies in this package are expressed in cycles per sample, so the
+// Nyquist frequency of a sequence is 0.5.
+package resample // import "gonum.org/v1/gonum/dsp/resample"
@@ -0,0 +1,40 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/dsp/resample"
+)
+
+func ExamplePoly() {
+	// Convert a 5 Hz tone sampled at 48 kHz to 44.1 kHz.
+	const (
+		from = 48000
+		to   = 44100
+		tone = 5.0 // Hz
+	)
+	src := make([]float64, from/10)
+	for i := range src {
+		src[i] = math.Sin(2 * math.Pi * tone * float64(i) / from)
+	}
+
+	dst := resample.Poly(nil, src, to, from, nil)
+	fmt.Printf("input samples:  %d\n", len(src))
+	fmt.Printf("output samples: %d\n", len(dst))
+
+	// Compare a sample away from the edges with the tone at the new rate.
+	i := len(dst) / 2
+	err := math.Abs(dst[i] - math.Sin(2*math.Pi*tone*float64(i)/to))
+	fmt.Printf("error at sample %d is below 1e-4: %t\n", i, err < 1e-4)
+
+	// Output:
+	//
+	// input samples:  4800
+	// output samples: 4410
+	// error at sample 2205 is below 1e-4: true
+}
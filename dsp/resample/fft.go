@@ -0,0 +1,53 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import "gonum.org/v1/gonum/dsp/fourier"
+
+// FFT resamples src to len(dst) samples in the frequency domain, placing
+// the result in dst and returning it. The sequence in src is assumed to
+// be one period of a periodic signal; its spectrum is truncated or zero
+// padded to the length of dst and transformed back using fourier.FFT.
+// When upsampling, or when downsampling a signal whose content is below
+// the new Nyquist frequency, the result is exact to within rounding.
+//
+// When the shorter of the two lengths is even, the Nyquist coefficient is
+// split between the positive and negative frequencies when upsampling and
+// folded from them when downsampling, so the real part of the spectrum is
+// preserved.
+//
+// FFT will panic if the length of dst or src is zero.
+func FFT(dst, src []float64) []float64 {
+	n, m := len(src), len(dst)
+	if n == 0 {
+		panic("resample: empty source")
+	}
+	if m == 0 {
+		panic("resample: empty destination")
+	}
+
+	coeff := fourier.NewFFT(n).Coefficients(nil, src)
+	res := make([]complex128, m/2+1)
+	short := n
+	if m < short {
+		short = m
+	}
+	copy(res, coeff[:short/2+1])
+	if short%2 == 0 {
+		switch {
+		case m < n:
+			res[short/2] *= 2
+		case n < m:
+			res[short/2] *= 0.5
+		}
+	}
+
+	fourier.NewFFT(m).Sequence(dst, res)
+	f := 1 / float64(n)
+	for i := range dst {
+		dst[i] *= f
+	}
+	return dst
+}
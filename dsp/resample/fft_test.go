@@ -0,0 +1,56 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestFFT(t *testing.T) {
+	const tol = 1e-12
+	// signal is band limited to 3 cycles per period so it can be
+	// represented exactly by every length tested here.
+	signal := func(x float64) float64 {
+		return 1 + math.Cos(2*math.Pi*x) - 0.5*math.Sin(2*math.Pi*2*x) + 0.25*math.Cos(2*math.Pi*3*x+0.3)
+	}
+	for _, n := range []int{7, 8, 16, 21} {
+		src := make([]float64, n)
+		for i := range src {
+			src[i] = signal(float64(i) / float64(n))
+		}
+		for _, m := range []int{7, 8, 9, 10, 16, 33, 64, 100} {
+			t.Run(fmt.Sprintf("%d->%d", n, m), func(t *testing.T) {
+				got := FFT(make([]float64, m), src)
+				for i, v := range got {
+					want := signal(float64(i) / float64(m))
+					if math.Abs(v-want) > tol {
+						t.Fatalf("unexpected value at %d: got:%v want:%v", i, v, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestFFTNyquist(t *testing.T) {
+	const tol = 1e-12
+	// An alternating sequence lies at the Nyquist frequency. Doubling the
+	// length must split that energy evenly so the result stays real and
+	// interpolates the original samples.
+	src := []float64{1, -1, 1, -1, 1, -1}
+	got := FFT(make([]float64, 12), src)
+	for i, v := range src {
+		if math.Abs(got[2*i]-v) > tol {
+			t.Errorf("unexpected value at %d: got:%v want:%v", 2*i, got[2*i], v)
+		}
+	}
+	for i := 1; i < len(got); i += 2 {
+		if math.Abs(got[i]) > tol {
+			t.Errorf("unexpected value at %d: got:%v want:0", i, got[i])
+		}
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/window"
+)
+
+// halfLenFactor is the number of zero crossings of the low-pass filter
+// kernel on each side of its centre used by the default filter design.
+const halfLenFactor = 10
+
+// LowPass returns an n-tap linear phase low-pass FIR filter with the
+// given cutoff frequency in cycles per sample designed by the windowed
+// sinc method. The ideal sinc kernel is tapered by the provided window
+// function; if window is nil, window.Blackman is used. The returned
+// filter coefficients are normalized to have unit gain at zero frequency.
+//
+// LowPass will panic if n is less than 1 or cutoff is not in (0, 0.5].
+func LowPass(n int, cutoff float64, win func([]float64) []float64) []float64 {
+	if n < 1 {
+		panic("resample: invalid filter length")
+	}
+	if !(0 < cutoff && cutoff <= 0.5) {
+		panic("resample: cutoff out of range")
+	}
+	if win == nil {
+		win = window.Blackman
+	}
+	h := make([]float64, n)
+	mid := float64(n-1) / 2
+	for i := range h {
+		h[i] = 2 * cutoff * sinc(2*cutoff*(float64(i)-mid))
+	}
+	win(h)
+	var sum float64
+	for _, v := range h {
+		sum += v
+	}
+	for i := range h {
+		h[i] /= sum
+	}
+	return h
+}
+
+// sinc returns the normalized sinc function, sin(πx)/(πx).
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	x *= math.Pi
+	return math.Sin(x) / x
+}
+
+// Design returns the default anti-aliasing filter used for rational
+// resampling by a factor of up/down. The filter has 2*10*max(up, down)+1
+// taps and a cutoff of 1/(2*max(up, down)) cycles per sample at the
+// upsampled rate, and is tapered by window.Blackman.
+//
+// Design will panic if up or down is less than 1.
+func Design(up, down int) []float64 {
+	up, down = reduce(up, down)
+	m := up
+	if down > m {
+		m = down
+	}
+	return LowPass(2*halfLenFactor*m+1, 0.5/float64(m), nil)
+}
+
+// Len returns the number of samples produced by rational resampling of a
+// sequence of length n by a factor of up/down, ⌈n*up/down⌉.
+//
+// Len will panic if n is negative or up or down is less than 1.
+func Len(n, up, down int) int {
+	if n < 0 {
+		panic("resample: negative length")
+	}
+	up, down = reduce(up, down)
+	return (n*up + down - 1) / down
+}
+
+// Poly resamples src by the rational factor up/down using a polyphase
+// FIR filter, placing the result in dst and returning it. The filter
+// coefficients are given at the upsampled rate; if filter is nil, the
+// filter returned by Design(up, down) is used. The group delay of the
+// filter, (len(filter)-1)/2 samples at the upsampled rate, is removed
+// so that the output is aligned with the input, and src is treated as
+// zero outside its bounds.
+//
+// The common factors of up and down are removed before resampling, so
+// a filter provided by the caller must be designed for the reduced rates.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal Len(len(src), up, down), Poly will
+// panic. Poly will also panic if up or down is less than 1.
+func Poly(dst, src []float64, up, down int, filter []float64) []float64 {
+	up, down = reduce(up, down)
+	n := Len(len(src), up, down)
+	if dst == nil {
+		dst = make([]float64, n)
+	} else if len(dst) != n {
+		panic("resample: destination length mismatch")
+	}
+	if filter == nil {
+		filter = Design(up, down)
+	}
+	if len(filter) == 0 {
+		panic("resample: empty filter")
+	}
+	delay := (len(filter) - 1) / 2
+	for i := range dst {
+		dst[i] = float64(up) * polyAt(filter, src, i*down+delay, up)
+	}
+	return dst
+}
+
+// Decimate reduces the sample rate of src by the integer factor q after
+// low-pass filtering to prevent aliasing, placing the result in dst and
+// returning it. The filter is as described for Poly.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal Len(len(src), 1, q), Decimate will
+// panic. Decimate will also panic if q is less than 1.
+func Decimate(dst, src []float64, q int, filter []float64) []float64 {
+	return Poly(dst, src, 1, q, filter)
+}
+
+// Interpolate increases the sample rate of src by the integer factor p,
+// filtering the zero-stuffed sequence to remove spectral images, placing
+// the result in dst and returning it. The filter is as described for Poly.
+//
+// If dst is nil, a new slice is allocated and returned. If dst is not nil
+// and the length of dst does not equal p*len(src), Interpolate will panic.
+// Interpolate will also panic if p is less than 1.
+func Interpolate(dst, src []float64, p int, filter []float64) []float64 {
+	return Poly(dst, src, p, 1, filter)
+}
+
+// polyAt returns the value of the filtered sequence obtained by upsampling
+// x by a factor of up and convolving with h at upsampled index t. Only the
+// filter phase aligned with the non-zero upsampled samples is evaluated.
+func polyAt(h, x []float64, t, up int) float64 {
+	// The input samples contributing to t are those with index j such
+	// that 0 <= t - j*up < len(h).
+	jhi := floorDiv(t, up)
+	jlo := floorDiv(t-len(h), up) + 1
+	if jlo < 0 {
+		jlo = 0
+	}
+	if jhi >= len(x) {
+		jhi = len(x) - 1
+	}
+	var sum float64
+	for j := jlo; j <= jhi; j++ {
+		sum += h[t-j*up] * x[j]
+	}
+	return sum
+}
+
+// reduce returns p and q divided by their greatest common divisor.
+// It panics if either p or q is less than 1.
+func reduce(p, q int) (int, int) {
+	if p < 1 || q < 1 {
+		panic("resample: invalid rate factor")
+	}
+	a, b := p, q
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return p / a, q / a
+}
+
+// floorDiv returns ⌊a/b⌋ for b > 0.
+func floorDiv(a, b int) int {
+	d := a / b
+	if a%b != 0 && a < 0 {
+		d--
+	}
+	return d
+}
@@ -0,0 +1,171 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestLowPass(t *testing.T) {
+	const tol = 1e-14
+	for _, n := range []int{1, 2, 11, 64, 101} {
+		for _, cutoff := range []float64{0.05, 0.125, 0.25, 0.5} {
+			h := LowPass(n, cutoff, nil)
+			if sum := floats.Sum(h); math.Abs(sum-1) > tol {
+				t.Errorf("unexpected DC gain for n=%d cutoff=%v: got:%v want:1", n, cutoff, sum)
+			}
+			for i := 0; i < n/2; i++ {
+				if math.Abs(h[i]-h[n-1-i]) > tol {
+					t.Errorf("filter not symmetric for n=%d cutoff=%v at %d: %v != %v", n, cutoff, i, h[i], h[n-1-i])
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestLowPassAttenuation(t *testing.T) {
+	const (
+		n      = 201
+		cutoff = 0.1
+	)
+	h := LowPass(n, cutoff, nil)
+	for _, test := range []struct {
+		freq float64
+		pass bool
+	}{
+		{freq: 0.01, pass: true},
+		{freq: 0.05, pass: true},
+		{freq: 0.15, pass: false},
+		{freq: 0.3, pass: false},
+		{freq: 0.45, pass: false},
+	} {
+		var re, im float64
+		for k, v := range h {
+			re += v * math.Cos(2*math.Pi*test.freq*float64(k))
+			im -= v * math.Sin(2*math.Pi*test.freq*float64(k))
+		}
+		gain := math.Hypot(re, im)
+		if test.pass && math.Abs(gain-1) > 1e-3 {
+			t.Errorf("unexpected pass band gain at %v: got:%v want:1", test.freq, gain)
+		}
+		if !test.pass && gain > 1e-3 {
+			t.Errorf("unexpected stop band gain at %v: got:%v want:<1e-3", test.freq, gain)
+		}
+	}
+}
+
+func TestLen(t *testing.T) {
+	for _, test := range []struct {
+		n, up, down int
+		want        int
+	}{
+		{n: 0, up: 3, down: 2, want: 0},
+		{n: 10, up: 1, down: 1, want: 10},
+		{n: 10, up: 3, down: 2, want: 15},
+		{n: 11, up: 3, down: 2, want: 17},
+		{n: 10, up: 6, down: 4, want: 15},
+		{n: 10, up: 1, down: 3, want: 4},
+		{n: 10, up: 4, down: 1, want: 40},
+	} {
+		got := Len(test.n, test.up, test.down)
+		if got != test.want {
+			t.Errorf("unexpected length for n=%d up=%d down=%d: got:%d want:%d",
+				test.n, test.up, test.down, got, test.want)
+		}
+	}
+}
+
+func TestPoly(t *testing.T) {
+	const (
+		n    = 600
+		freq = 0.01 // Cycles per input sample.
+		tol  = 1e-3
+	)
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = math.Sin(2 * math.Pi * freq * float64(i))
+	}
+	for _, test := range []struct {
+		up, down int
+	}{
+		{up: 1, down: 1},
+		{up: 3, down: 2},
+		{up: 2, down: 3},
+		{up: 4, down: 1},
+		{up: 1, down: 4},
+		{up: 160, down: 147},
+		{up: 6, down: 4},
+	} {
+		t.Run(fmt.Sprintf("%d/%d", test.up, test.down), func(t *testing.T) {
+			got := Poly(nil, src, test.up, test.down, nil)
+			if len(got) != Len(n, test.up, test.down) {
+				t.Fatalf("unexpected length: got:%d want:%d", len(got), Len(n, test.up, test.down))
+			}
+			ratio := float64(test.up) / float64(test.down)
+			// Avoid the edges where the filter sees the implicit zeros.
+			margin := int(math.Ceil(50 * ratio))
+			for i := margin; i < len(got)-margin; i++ {
+				want := math.Sin(2 * math.Pi * freq * float64(i) / ratio)
+				if math.Abs(got[i]-want) > tol {
+					t.Fatalf("unexpected value at %d: got:%v want:%v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecimateAlias(t *testing.T) {
+	const (
+		n   = 1000
+		q   = 4
+		tol = 1e-3
+	)
+	// A tone above the decimated Nyquist frequency must be removed
+	// rather than aliased into the output.
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = math.Cos(2*math.Pi*0.02*float64(i)) + math.Cos(2*math.Pi*0.3*float64(i))
+	}
+	got := Decimate(nil, src, q, nil)
+	for i := 20; i < len(got)-20; i++ {
+		want := math.Cos(2 * math.Pi * 0.02 * q * float64(i))
+		if math.Abs(got[i]-want) > tol {
+			t.Fatalf("unexpected value at %d: got:%v want:%v", i, got[i], want)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	const (
+		n   = 200
+		p   = 5
+		tol = 1e-3
+	)
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = math.Cos(2 * math.Pi * 0.03 * float64(i))
+	}
+	got := Interpolate(nil, src, p, nil)
+	if len(got) != p*n {
+		t.Fatalf("unexpected length: got:%d want:%d", len(got), p*n)
+	}
+	for i := 20 * p; i < len(got)-20*p; i++ {
+		want := math.Cos(2 * math.Pi * 0.03 * float64(i) / p)
+		if math.Abs(got[i]-want) > tol {
+			t.Fatalf("unexpected value at %d: got:%v want:%v", i, got[i], want)
+		}
+	}
+	// The original samples should be retained.
+	for i := 20; i < n-20; i++ {
+		if math.Abs(got[i*p]-src[i]) > tol {
+			t.Fatalf("original sample %d not retained: got:%v want:%v", i, got[i*p], src[i])
+		}
+	}
+}
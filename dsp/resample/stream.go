@@ -0,0 +1,82 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+// Resampler is a streaming rational resampler. It converts a sequence
+// presented in consecutive blocks by a factor of up/down using a polyphase
+// FIR filter, retaining the input history needed between calls so that
+// the output is independent of how the sequence is split into blocks.
+//
+// The Resampler is causal: unlike Poly, the group delay of the filter is
+// not removed, so output sample i corresponds to the filtered upsampled
+// sequence at index i*down.
+type Resampler struct {
+	up, down int
+	filter   []float64
+
+	// buf holds the input samples that may still
+	// contribute to future output. next is the
+	// upsampled index of the next output sample
+	// relative to the first element of buf.
+	buf  []float64
+	next int
+}
+
+// NewResampler returns a Resampler for conversion by the rational factor
+// up/down. If filter is nil, the filter returned by Design(up, down) is
+// used, otherwise filter must be designed for the reduced rate factors
+// as described for Poly.
+//
+// NewResampler will panic if up or down is less than 1 or filter is
+// empty and not nil.
+func NewResampler(up, down int, filter []float64) *Resampler {
+	up, down = reduce(up, down)
+	if filter == nil {
+		filter = Design(up, down)
+	} else if len(filter) == 0 {
+		panic("resample: empty filter")
+	}
+	h := make([]float64, len(filter))
+	copy(h, filter)
+	return &Resampler{up: up, down: down, filter: h}
+}
+
+// Delay returns the group delay of the Resampler's filter in samples at
+// the output rate.
+func (r *Resampler) Delay() float64 {
+	return float64(len(r.filter)-1) / 2 / float64(r.down)
+}
+
+// Reset clears the input history of the Resampler, returning it to its
+// initial state.
+func (r *Resampler) Reset() {
+	r.buf = r.buf[:0]
+	r.next = 0
+}
+
+// Resample consumes the samples in src and appends the output samples that
+// can be computed from the input seen so far to dst, returning the extended
+// slice. Samples preceding the first call to Resample after construction
+// or Reset are treated as zero.
+func (r *Resampler) Resample(dst, src []float64) []float64 {
+	r.buf = append(r.buf, src...)
+	// An output sample at upsampled index t can be computed
+	// once every input with index j <= t/up is available.
+	for floorDiv(r.next, r.up) < len(r.buf) {
+		dst = append(dst, float64(r.up)*polyAt(r.filter, r.buf, r.next, r.up))
+		r.next += r.down
+	}
+
+	// Discard input that can no longer contribute.
+	drop := floorDiv(r.next-len(r.filter), r.up) + 1
+	if drop > 0 {
+		if drop > len(r.buf) {
+			drop = len(r.buf)
+		}
+		r.buf = r.buf[:copy(r.buf, r.buf[drop:])]
+		r.next -= drop * r.up
+	}
+	return dst
+}
@@ -0,0 +1,74 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package resample
+
+import (
+	"fmt"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestResampler(t *testing.T) {
+	const (
+		n   = 1000
+		tol = 1e-12
+	)
+	rnd := rand.New(rand.NewSource(1))
+	src := make([]float64, n)
+	for i := range src {
+		src[i] = rnd.NormFloat64()
+	}
+	for _, test := range []struct {
+		up, down int
+	}{
+		{up: 1, down: 1},
+		{up: 3, down: 2},
+		{up: 2, down: 3},
+		{up: 5, down: 1},
+		{up: 1, down: 7},
+		{up: 147, down: 160},
+	} {
+		t.Run(fmt.Sprintf("%d/%d", test.up, test.down), func(t *testing.T) {
+			r := NewResampler(test.up, test.down, nil)
+			whole := r.Resample(nil, src)
+			if len(whole) != Len(n, test.up, test.down) {
+				t.Errorf("unexpected output length: got:%d want:%d", len(whole), Len(n, test.up, test.down))
+			}
+
+			// The causal output is the aligned output of Poly
+			// delayed by the filter's group delay.
+			h := Design(test.up, test.down)
+			padded := make([]float64, n)
+			copy(padded, src)
+			aligned := Poly(nil, padded, test.up, test.down, h)
+			delay := (len(h) - 1) / 2
+			if delay%test.down == 0 {
+				shift := delay / test.down
+				for i := shift; i < len(whole); i++ {
+					if !floats.EqualWithinAbsOrRel(whole[i], aligned[i-shift], tol, tol) {
+						t.Fatalf("mismatch with Poly at %d: got:%v want:%v", i, whole[i], aligned[i-shift])
+					}
+				}
+			}
+
+			r.Reset()
+			var blocks []float64
+			for i := 0; i < n; {
+				size := rnd.Intn(50)
+				if i+size > n {
+					size = n - i
+				}
+				blocks = r.Resample(blocks, src[i:i+size])
+				i += size
+			}
+			if !floats.EqualApprox(blocks, whole, tol) {
+				t.Errorf("block processing does not match whole sequence processing")
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/dsp/fourier"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// DPSSTapers returns the first k discrete prolate spheroidal sequences of
+// length n with time half bandwidth product nw, for use as tapers in
+// multitaper spectral estimation, and the fraction of the energy of each
+// taper that is concentrated within the frequency band |f| < nw/n.
+// See https://en.wikipedia.org/wiki/Multitaper for details.
+//
+// Each taper has unit energy. Symmetric tapers are signed to have a positive
+// sum and antisymmetric tapers to be positive in their first half. Tapers
+// with concentration ratios close to one, typically the first 2*nw-1, are
+// useful for spectral estimation.
+//
+// DPSSTapers will panic if n is less than 1, k is not in [1, n] or nw is
+// not in (0, n/2).
+func DPSSTapers(n, k int, nw float64) (tapers [][]float64, ratios []float64) {
+	if n < 1 {
+		panic("window: invalid length")
+	}
+	if k < 1 || n < k {
+		panic("window: invalid number of tapers")
+	}
+	if !(0 < nw && nw < float64(n)/2) {
+		panic("window: invalid time half bandwidth product")
+	}
+	tapers = dpss(n, nw, k)
+	w := nw / float64(n)
+	ratios = make([]float64, k)
+	for i, taper := range tapers {
+		// The concentration ratio is the quadratic form of the
+		// taper with the sinc kernel, written in terms of the
+		// taper's autocorrelation.
+		lambda := 2 * w * floats.Dot(taper, taper)
+		for m := 1; m < n; m++ {
+			lambda += 2 * floats.Dot(taper[:n-m], taper[m:]) * math.Sin(2*math.Pi*w*float64(m)) / (math.Pi * float64(m))
+		}
+		ratios[i] = lambda
+	}
+	return tapers, ratios
+}
+
+// dpss returns the first k discrete prolate spheroidal sequences of length n
+// with time half bandwidth product nw, normalized to unit energy. The sequences
+// are the eigenvectors corresponding to the largest eigenvalues of a symmetric
+// tridiagonal matrix that commutes with the sinc kernel matrix. See Percival
+// and Walden, "Spectral Analysis for Physical Applications", section 8.3.
+func dpss(n int, nw float64, k int) [][]float64 {
+	if n == 1 {
+		return [][]float64{{1}}
+	}
+	w := nw / float64(n)
+	c := math.Cos(2 * math.Pi * w)
+	t := mat.NewSymBandDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		d := (float64(n-1) - 2*float64(i)) / 2
+		t.SetSymBand(i, i, d*d*c)
+		if i > 0 {
+			t.SetSymBand(i-1, i, float64(i*(n-i))/2)
+		}
+	}
+	var eig mat.EigenSym
+	ok := eig.Factorize(t, true)
+	if !ok {
+		panic("window: eigendecomposition failed")
+	}
+	var vecs mat.Dense
+	eig.VectorsTo(&vecs)
+
+	tapers := make([][]float64, k)
+	mid := float64(n-1) / 2
+	for j := range tapers {
+		// Eigenvalues are in ascending order.
+		taper := mat.Col(nil, n-1-j, &vecs)
+		var s float64
+		for i, v := range taper {
+			if j%2 == 0 {
+				s += v
+			} else {
+				s += (mid - float64(i)) * v
+			}
+		}
+		if s < 0 {
+			for i := range taper {
+				taper[i] = -taper[i]
+			}
+		}
+		tapers[j] = taper
+	}
+	return tapers
+}
+
+// dpssWindow returns the zeroth order discrete prolate spheroidal sequence
+// of length n with time half bandwidth product nw scaled to a maximum of one.
+// dpssWindow will panic if n is positive and nw is not in (0, n/2).
+func dpssWindow(n int, nw float64) []float64 {
+	if n == 0 {
+		return nil
+	}
+	if !(0 < nw && nw < float64(n)/2) {
+		panic("window: invalid time half bandwidth product")
+	}
+	taper := dpss(n, nw, 1)[0]
+	var max float64
+	for _, v := range taper {
+		max = math.Max(max, v)
+	}
+	for i := range taper {
+		taper[i] /= max
+	}
+	return taper
+}
+
+// chebwin returns the Dolph-Chebyshev window of length n with side lobe
+// attenuation at dB, scaled to a maximum of one. chebwin will panic if at
+// is not positive.
+func chebwin(n int, at float64) []float64 {
+	if !(at > 0) {
+		panic("window: invalid attenuation")
+	}
+	switch n {
+	case 0:
+		return nil
+	case 1:
+		return []float64{1}
+	}
+	order := float64(n - 1)
+	beta := math.Cosh(math.Acosh(math.Pow(10, at/20)) / order)
+
+	p := make([]complex128, n)
+	for k := range p {
+		x := beta * math.Cos(math.Pi*float64(k)/float64(n))
+		var v float64
+		switch {
+		case x > 1:
+			v = math.Cosh(order * math.Acosh(x))
+		case x < -1:
+			v = math.Cosh(order * math.Acosh(-x))
+			if n%2 == 0 {
+				v = -v
+			}
+		default:
+			v = math.Cos(order * math.Acos(x))
+		}
+		if n%2 == 0 {
+			// Shift by half a sample to centre an even length window.
+			s, c := math.Sincos(math.Pi * float64(k) / float64(n))
+			p[k] = complex(v*c, v*s)
+		} else {
+			p[k] = complex(v, 0)
+		}
+	}
+	fourier.NewCmplxFFT(n).Coefficients(p, p)
+
+	w := make([]float64, n)
+	h := n / 2
+	if n%2 == 1 {
+		// w = [p[h] ... p[1] p[0] p[1] ... p[h]].
+		for i := 0; i <= h; i++ {
+			w[h+i] = real(p[i])
+			w[h-i] = real(p[i])
+		}
+	} else {
+		// w = [p[h] ... p[1] p[1] ... p[h]].
+		for i := 1; i <= h; i++ {
+			w[h-1+i] = real(p[i])
+			w[h-i] = real(p[i])
+		}
+	}
+	var max float64
+	for _, v := range w {
+		max = math.Max(max, v)
+	}
+	for i := range w {
+		w[i] /= max
+	}
+	return w
+}
+
+// besselI0 returns the modified Bessel function of the first kind of order
+// zero evaluated at x, computed from its power series.
+func besselI0(x float64) float64 {
+	q := x * x / 4
+	sum := 1.0
+	term := 1.0
+	for k := 1; ; k++ {
+		term *= q / float64(k*k)
+		sum += term
+		if term < sum*1e-17 {
+			return sum
+		}
+	}
+}
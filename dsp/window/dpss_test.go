@@ -0,0 +1,190 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestDPSSTapers(t *testing.T) {
+	const tol = 1e-10
+	for _, test := range []struct {
+		n, k int
+		nw   float64
+	}{
+		{n: 1, k: 1, nw: 0.25},
+		{n: 16, k: 3, nw: 2},
+		{n: 64, k: 7, nw: 4},
+		{n: 101, k: 5, nw: 3.5},
+	} {
+		t.Run(fmt.Sprintf("n=%d nw=%v", test.n, test.nw), func(t *testing.T) {
+			tapers, ratios := DPSSTapers(test.n, test.k, test.nw)
+			if len(tapers) != test.k || len(ratios) != test.k {
+				t.Fatalf("unexpected number of tapers: got:%d want:%d", len(tapers), test.k)
+			}
+			w := test.nw / float64(test.n)
+			mid := float64(test.n-1) / 2
+			for i, a := range tapers {
+				for j, b := range tapers[:i+1] {
+					want := 0.0
+					if i == j {
+						want = 1
+					}
+					if got := floats.Dot(a, b); math.Abs(got-want) > tol {
+						t.Errorf("tapers %d and %d not orthonormal: got:%v want:%v", i, j, got, want)
+					}
+				}
+
+				// Each taper is an eigenvector of the sinc kernel
+				// with its concentration ratio as the eigenvalue.
+				for p := range a {
+					var got float64
+					for q, v := range a {
+						s := 2 * w
+						if p != q {
+							d := float64(p - q)
+							s = math.Sin(2*math.Pi*w*d) / (math.Pi * d)
+						}
+						got += s * v
+					}
+					if math.Abs(got-ratios[i]*a[p]) > 1e-8 {
+						t.Errorf("taper %d not an eigenvector of the sinc kernel at %d: got:%v want:%v", i, p, got, ratios[i]*a[p])
+						break
+					}
+				}
+				if i > 0 && ratios[i] > ratios[i-1] {
+					t.Errorf("concentration ratios not decreasing: %v", ratios)
+				}
+
+				var s float64
+				for p, v := range a {
+					if i%2 == 0 {
+						s += v
+					} else {
+						s += (mid - float64(p)) * v
+					}
+				}
+				if s <= 0 {
+					t.Errorf("unexpected sign of taper %d", i)
+				}
+			}
+			if test.n > 1 && test.nw >= 2 && ratios[0] < 0.999 {
+				t.Errorf("poor concentration of first taper: %v", ratios[0])
+			}
+		})
+	}
+}
+
+func TestDPSSWindow(t *testing.T) {
+	const tol = 1e-12
+	for _, n := range []int{1, 2, 20, 21} {
+		for _, nw := range []float64{0.25, 0.5, 2, 4} {
+			if nw >= float64(n)/2 {
+				continue
+			}
+			w := NewValues(DPSS{NW: nw}.Transform, n)
+			if max := floats.Max(w); math.Abs(max-1) > tol {
+				t.Errorf("unexpected maximum for n=%d nw=%v: got:%v want:1", n, nw, max)
+			}
+			for i := 0; i < n/2; i++ {
+				if math.Abs(w[i]-w[n-1-i]) > tol {
+					t.Errorf("window not symmetric for n=%d nw=%v", n, nw)
+					break
+				}
+			}
+			wc := DPSSComplex{NW: nw}.Transform(ones(n))
+			if !equalApprox(wc, w, tol) {
+				t.Errorf("complex window does not match real window for n=%d nw=%v", n, nw)
+			}
+		}
+	}
+}
+
+func TestDolphChebyshev(t *testing.T) {
+	const tol = 1e-12
+	for _, n := range []int{1, 2, 11, 20, 31, 64} {
+		for _, at := range []float64{40, 60, 100} {
+			w := NewValues(DolphChebyshev{Attenuation: at}.Transform, n)
+			if max := floats.Max(w); math.Abs(max-1) > tol {
+				t.Errorf("unexpected maximum for n=%d at=%v: got:%v want:1", n, at, max)
+			}
+			for i := 0; i < n/2; i++ {
+				if math.Abs(w[i]-w[n-1-i]) > 1e-10 {
+					t.Errorf("window not symmetric for n=%d at=%v: %v", n, at, w)
+					break
+				}
+			}
+			wc := DolphChebyshevComplex{Attenuation: at}.Transform(ones(n))
+			if !equalApprox(wc, w, tol) {
+				t.Errorf("complex window does not match real window for n=%d at=%v", n, at)
+			}
+			if n < 8 {
+				continue
+			}
+
+			// All side lobes are at the specified attenuation.
+			const samples = 4096
+			mag := make([]float64, samples)
+			for i := range mag {
+				f := 0.5 * float64(i) / samples
+				var s complex128
+				for k, v := range w {
+					s += complex(v, 0) * cmplx.Rect(1, -2*math.Pi*f*float64(k))
+				}
+				mag[i] = cmplx.Abs(s)
+			}
+			first := 1
+			for first < samples-1 && mag[first+1] < mag[first] {
+				first++
+			}
+			side := 20 * math.Log10(floats.Max(mag[first:])/mag[0])
+			if math.Abs(side+at) > 0.1 {
+				t.Errorf("unexpected side lobe level for n=%d: got:%v want:%v", n, side, -at)
+			}
+		}
+	}
+}
+
+// ones returns a complex slice of length n filled with 1+1i.
+func ones(n int) []complex128 {
+	s := make([]complex128, n)
+	for i := range s {
+		s[i] = complex(1, 1)
+	}
+	return s
+}
+
+func TestAdjustableWindowPanics(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "DPSS zero NW", fn: func() { DPSS{}.Transform(make([]float64, 8)) }},
+		{name: "DPSS negative NW", fn: func() { DPSS{NW: -1}.Transform(make([]float64, 8)) }},
+		{name: "DPSS NW at N/2", fn: func() { DPSS{NW: 4}.Transform(make([]float64, 8)) }},
+		{name: "DPSSComplex NW above N/2", fn: func() { DPSSComplex{NW: 5}.Transform(ones(8)) }},
+		{name: "DolphChebyshev zero attenuation", fn: func() { DolphChebyshev{}.Transform(make([]float64, 8)) }},
+		{name: "DolphChebyshev negative attenuation", fn: func() { DolphChebyshev{Attenuation: -60}.Transform(make([]float64, 8)) }},
+		{name: "DolphChebyshevComplex NaN attenuation", fn: func() { DolphChebyshevComplex{Attenuation: math.NaN()}.Transform(ones(8)) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("expected panic for %s", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		r := recover()
+		panicked = r != nil
+	}()
+	fn()
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// EquivalentNoiseBandwidth returns the equivalent noise bandwidth of the
+// window with weights w, in frequency bins,
+//  ENBW = N * \sum w[k]² / (\sum w[k])²,
+// where N is the length of the window. The equivalent noise bandwidth is the
+// width of the rectangular filter that would accumulate the same noise power
+// as the window. The weights of any window function may be obtained from
+// NewValues.
+//
+// EquivalentNoiseBandwidth will panic if w is empty.
+func EquivalentNoiseBandwidth(w []float64) float64 {
+	if len(w) == 0 {
+		panic("window: zero length window")
+	}
+	var sum, sumSq float64
+	for _, v := range w {
+		sum += v
+		sumSq += v * v
+	}
+	return float64(len(w)) * sumSq / (sum * sum)
+}
+
+// CoherentGain returns the coherent gain of the window with weights w,
+//  CG = \sum w[k] / N,
+// where N is the length of the window. The coherent gain is the factor by
+// which the amplitude of a tone at the centre of a frequency bin is reduced
+// by the window.
+//
+// CoherentGain will panic if w is empty.
+func CoherentGain(w []float64) float64 {
+	if len(w) == 0 {
+		panic("window: zero length window")
+	}
+	var sum float64
+	for _, v := range w {
+		sum += v
+	}
+	return sum / float64(len(w))
+}
+
+// ScallopingLoss returns the scalloping loss of the window with weights w in
+// decibels,
+//  SL = -20 log10(|\sum w[k] exp(-iπk/N)| / \sum w[k]),
+// where N is the length of the window. The scalloping loss is the maximum
+// reduction in the amplitude of a tone, which occurs when its frequency lies
+// midway between two frequency bins.
+//
+// ScallopingLoss will panic if w is empty.
+func ScallopingLoss(w []float64) float64 {
+	if len(w) == 0 {
+		panic("window: zero length window")
+	}
+	var sum float64
+	var half complex128
+	for k, v := range w {
+		sum += v
+		half += complex(v, 0) * cmplx.Rect(1, -math.Pi*float64(k)/float64(len(w)))
+	}
+	return -20 * math.Log10(cmplx.Abs(half)/sum)
+}
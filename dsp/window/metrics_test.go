@@ -0,0 +1,43 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package window
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	const n = 4096
+	for _, test := range []struct {
+		name   string
+		window func([]float64) []float64
+		enbw   float64
+		cg     float64
+		sl     float64
+		tol    float64
+	}{
+		// Values from Harris, "On the use of windows for harmonic
+		// analysis with the discrete Fourier transform", Proc. IEEE
+		// 66(1):51-83 (1978).
+		{name: "Rectangular", window: Rectangular, enbw: 1, cg: 1, sl: 3.92, tol: 5e-3},
+		{name: "Hann", window: Hann, enbw: 1.5, cg: 0.5, sl: 1.42, tol: 5e-3},
+		// The Hamming window here uses a0=25/46 rather than 0.54.
+		{name: "Hamming", window: Hamming, enbw: 1.35, cg: 0.54, sl: 1.78, tol: 5e-3},
+		{name: "BlackmanHarris", window: BlackmanHarris, enbw: 2.00, cg: 0.36, sl: 0.83, tol: 5e-3},
+		{name: "Kaiser (beta=3π)", window: Kaiser{Beta: 3 * math.Pi}.Transform, enbw: 1.80, cg: 0.40, sl: 1.02, tol: 1e-2},
+	} {
+		w := NewValues(test.window, n)
+		if got := EquivalentNoiseBandwidth(w); math.Abs(got-test.enbw) > test.tol {
+			t.Errorf("unexpected equivalent noise bandwidth for %s: got:%v want:%v", test.name, got, test.enbw)
+		}
+		if got := CoherentGain(w); math.Abs(got-test.cg) > test.tol {
+			t.Errorf("unexpected coherent gain for %s: got:%v want:%v", test.name, got, test.cg)
+		}
+		if got := ScallopingLoss(w); math.Abs(got-test.sl) > test.tol {
+			t.Errorf("unexpected scalloping loss for %s: got:%v want:%v", test.name, got, test.sl)
+		}
+	}
+}
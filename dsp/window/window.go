@@ -330,6 +330,196 @@ func (g Gaussian) Transform(seq []float64) []float64 {
 	return seq
 }
 
+// Kaiser can modify a sequence by the Kaiser window and return the result.
+// See https://en.wikipedia.org/wiki/Kaiser_window for details.
+//
+// The Kaiser window is an adjustable window.
+//
+// The sequence weights are
+//  w[k] = I_0(β * sqrt(1 - (2*(k+1/2)/N - 1)²)) / I_0(β),
+// for k=0,1,...,N-1 where N is the length of the window and I_0 is the
+// modified Bessel function of the first kind of order zero.
+//
+// The properties of the window depend on the value of β (Beta). β=0 gives
+// the rectangular window and increasing β widens the main lobe and lowers
+// the side lobes. The side lobe attenuation A in dB for a filter design is
+// approximately achieved with β = 0.1102*(A-8.7) for A > 50.
+type Kaiser struct {
+	Beta float64
+}
+
+// Transform applies the Kaiser transformation to seq in place, using the value
+// of the receiver as the beta parameter, and returning the result.
+func (w Kaiser) Transform(seq []float64) []float64 {
+	den := besselI0(w.Beta)
+	k := 2 / float64(len(seq))
+	for i := range seq {
+		x := k*(float64(i)+0.5) - 1
+		seq[i] *= besselI0(w.Beta*math.Sqrt(1-x*x)) / den
+	}
+	return seq
+}
+
+// Tukey can modify a sequence by the Tukey (tapered cosine) window and return
+// the result.
+// See https://en.wikipedia.org/wiki/Window_function#Tukey_window for details.
+//
+// The Tukey window is an adjustable window.
+//
+// The sequence weights are
+//  w[k] = 0.5 * (1 - cos(2*π*x/α)),  x < α/2,
+//  w[k] = 1,                         α/2 <= x <= 1 - α/2,
+//  w[k] = 0.5 * (1 - cos(2*π*(1-x)/α)), x > 1 - α/2,
+// with x = (k+1/2)/N for k=0,1,...,N-1 where N is the length of the window.
+//
+// The α (Alpha) parameter is the fraction of the window inside the cosine
+// tapers. α=0 gives the rectangular window and α=1 gives the Hann window.
+type Tukey struct {
+	Alpha float64
+}
+
+// Transform applies the Tukey transformation to seq in place, using the value
+// of the receiver as the alpha parameter, and returning the result.
+func (w Tukey) Transform(seq []float64) []float64 {
+	if w.Alpha <= 0 {
+		return seq
+	}
+	alpha := math.Min(w.Alpha, 1)
+	k := 1 / float64(len(seq))
+	for i := range seq {
+		x := k * (float64(i) + 0.5)
+		x = math.Min(x, 1-x)
+		if x < alpha/2 {
+			seq[i] *= 0.5 * (1 - math.Cos(2*math.Pi*x/alpha))
+		}
+	}
+	return seq
+}
+
+// PlanckTaper can modify a sequence by the Planck-taper window and return
+// the result.
+// See https://en.wikipedia.org/wiki/Window_function#Planck-taper_window for details.
+//
+// The Planck-taper window is an adjustable window.
+//
+// The sequence weights are
+//  w[k] = 1 / (exp(ε/x - ε/(ε-x)) + 1), x < ε,
+//  w[k] = 1,                            ε <= x <= 1 - ε,
+// and symmetrically for x > 1 - ε, with x = (k+1/2)/N for k=0,1,...,N-1
+// where N is the length of the window.
+//
+// The ε (Epsilon) parameter is the fraction of the window on each side
+// occupied by the smooth taper, and must be no greater than 0.5.
+// ε=0 gives the rectangular window.
+type PlanckTaper struct {
+	Epsilon float64
+}
+
+// Transform applies the Planck-taper transformation to seq in place, using the
+// value of the receiver as the epsilon parameter, and returning the result.
+func (w PlanckTaper) Transform(seq []float64) []float64 {
+	if w.Epsilon <= 0 {
+		return seq
+	}
+	eps := math.Min(w.Epsilon, 0.5)
+	k := 1 / float64(len(seq))
+	for i := range seq {
+		x := k * (float64(i) + 0.5)
+		x = math.Min(x, 1-x)
+		if x < eps {
+			seq[i] *= 1 / (math.Exp(eps/x-eps/(eps-x)) + 1)
+		}
+	}
+	return seq
+}
+
+// DPSS can modify a sequence by the discrete prolate spheroidal sequence
+// (Slepian) window and return the result.
+// See https://en.wikipedia.org/wiki/Window_function#DPSS_or_Slepian_window for details.
+//
+// The DPSS window is an adjustable window.
+//
+// The sequence weights are the zeroth order discrete prolate spheroidal
+// sequence of length N with time half bandwidth product NW, scaled to a
+// maximum of one. The sequence maximizes the fraction of its energy within
+// the frequency band |f| < NW/N. Unlike the other windows in this package,
+// the sequence is defined on the sample indices k=0,1,...,N-1 rather than
+// the offset interval.
+//
+// The properties of the window depend on the value of NW. Larger values
+// widen the main lobe and lower the side lobes. The tapers for multitaper
+// spectral estimation are available from DPSSTapers.
+//
+// Transform will panic if NW is not in (0, N/2) for a non-empty sequence.
+type DPSS struct {
+	NW float64
+}
+
+// Transform applies the DPSS transformation to seq in place, using the value
+// of the receiver as the time half bandwidth product, and returning the result.
+func (w DPSS) Transform(seq []float64) []float64 {
+	for i, v := range dpssWindow(len(seq), w.NW) {
+		seq[i] *= v
+	}
+	return seq
+}
+
+// DolphChebyshev can modify a sequence by the Dolph-Chebyshev window and
+// return the result.
+// See https://en.wikipedia.org/wiki/Window_function#Dolph%E2%80%93Chebyshev_window for details.
+//
+// The Dolph-Chebyshev window is an adjustable window.
+//
+// The window minimizes the width of the main lobe for a given side lobe level,
+// and all side lobes have the same height. The weights are obtained from the
+// inverse Fourier transform of the Chebyshev polynomial of degree N-1,
+//  W(k) = T_{N-1}(β*cos(π*k/N)), β = cosh(acosh(10^(A/20))/(N-1)),
+// for k=0,1,...,N-1 where N is the length of the window, and are scaled to
+// a maximum of one. As for DPSS, the sequence is defined on the sample
+// indices rather than the offset interval.
+//
+// The Attenuation parameter, A, is the side lobe attenuation in dB and must
+// be positive. Transform will panic if it is not.
+type DolphChebyshev struct {
+	Attenuation float64
+}
+
+// Transform applies the Dolph-Chebyshev transformation to seq in place, using
+// the value of the receiver as the attenuation parameter, and returning the
+// result.
+func (w DolphChebyshev) Transform(seq []float64) []float64 {
+	for i, v := range chebwin(len(seq), w.Attenuation) {
+		seq[i] *= v
+	}
+	return seq
+}
+
+// Exponential can modify a sequence by the Exponential (Poisson) window and
+// return the result.
+// See https://en.wikipedia.org/wiki/Window_function#Exponential_or_Poisson_window for details.
+//
+// The Exponential window is an adjustable window.
+//
+// The sequence weights are
+//  w[k] = exp(-|k + 1/2 - M|/(τ*M)), M = N/2,
+// for k=0,1,...,N-1 where N is the length of the window.
+//
+// The τ (Tau) parameter is the decay constant relative to the half length
+// of the window, so that the weights at the edges are approximately exp(-1/τ).
+type Exponential struct {
+	Tau float64
+}
+
+// Transform applies the Exponential transformation to seq in place, using the
+// value of the receiver as the tau parameter, and returning the result.
+func (w Exponential) Transform(seq []float64) []float64 {
+	a := float64(len(seq)) / 2
+	for i := range seq {
+		seq[i] *= math.Exp(-math.Abs(float64(i)+0.5-a) / (w.Tau * a))
+	}
+	return seq
+}
+
 // Values is an arbitrary real window function.
 type Values []float64
 
@@ -139,6 +139,78 @@ var gausWindowTests = []struct {
 	},
 }
 
+var paramWindowTests = []struct {
+	name    string
+	fn      func([]float64) []float64
+	fnCmplx func([]complex128) []complex128
+	want    []float64
+}{
+	{
+		name: "Kaiser (beta=0)", fn: Kaiser{Beta: 0}.Transform, fnCmplx: KaiserComplex{Beta: 0}.Transform,
+		want: []float64{
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+			1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+		},
+	},
+	{
+		name: "Kaiser (beta=2)", fn: Kaiser{Beta: 2}.Transform, fnCmplx: KaiserComplex{Beta: 2}.Transform,
+		want: []float64{
+			0.482501, 0.569119, 0.652637, 0.731021, 0.802328, 0.864770, 0.916762, 0.956976, 0.984376, 0.998257,
+			0.998257, 0.984376, 0.956976, 0.916762, 0.864770, 0.802328, 0.731021, 0.652637, 0.569119, 0.482501,
+		},
+	},
+	{
+		name: "Kaiser (beta=5)", fn: Kaiser{Beta: 5}.Transform, fnCmplx: KaiserComplex{Beta: 5}.Transform,
+		want: []float64{
+			0.062729, 0.133927, 0.230544, 0.349275, 0.483209, 0.622367, 0.754789, 0.868017, 0.950753, 0.994429,
+			0.994429, 0.950753, 0.868017, 0.754789, 0.622367, 0.483209, 0.349275, 0.230544, 0.133927, 0.062729,
+		},
+	},
+	{
+		name: "Tukey (alpha=0.5)", fn: Tukey{Alpha: 0.5}.Transform, fnCmplx: TukeyComplex{Alpha: 0.5}.Transform,
+		want: []float64{
+			0.024472, 0.206107, 0.500000, 0.793893, 0.975528, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000,
+			1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 0.975528, 0.793893, 0.500000, 0.206107, 0.024472,
+		},
+	},
+	// Tukey with alpha=1 is the Hann window.
+	{
+		name: "Tukey (alpha=1)", fn: Tukey{Alpha: 1}.Transform, fnCmplx: TukeyComplex{Alpha: 1}.Transform,
+		want: []float64{
+			0.006155, 0.054496, 0.146447, 0.273005, 0.421783, 0.578217, 0.726995, 0.853553, 0.945503, 0.993844,
+			0.993844, 0.945503, 0.853553, 0.726995, 0.578217, 0.421783, 0.273005, 0.146447, 0.054496, 0.006155,
+		},
+	},
+	{
+		name: "PlanckTaper (epsilon=0.1)", fn: PlanckTaper{Epsilon: 0.1}.Transform, fnCmplx: PlanckTaperComplex{Epsilon: 0.1}.Transform,
+		want: []float64{
+			0.064969, 0.935031, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000,
+			1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 1.000000, 0.935031, 0.064969,
+		},
+	},
+	{
+		name: "PlanckTaper (epsilon=0.3)", fn: PlanckTaper{Epsilon: 0.3}.Transform, fnCmplx: PlanckTaperComplex{Epsilon: 0.3}.Transform,
+		want: []float64{
+			0.000018, 0.064969, 0.334987, 0.665013, 0.935031, 0.999982, 1.000000, 1.000000, 1.000000, 1.000000,
+			1.000000, 1.000000, 1.000000, 1.000000, 0.999982, 0.935031, 0.665013, 0.334987, 0.064969, 0.000018,
+		},
+	},
+	{
+		name: "Exponential (tau=0.5)", fn: Exponential{Tau: 0.5}.Transform, fnCmplx: ExponentialComplex{Tau: 0.5}.Transform,
+		want: []float64{
+			0.149569, 0.182684, 0.223130, 0.272532, 0.332871, 0.406570, 0.496585, 0.606531, 0.740818, 0.904837,
+			0.904837, 0.740818, 0.606531, 0.496585, 0.406570, 0.332871, 0.272532, 0.223130, 0.182684, 0.149569,
+		},
+	},
+	{
+		name: "Exponential (tau=1)", fn: Exponential{Tau: 1}.Transform, fnCmplx: ExponentialComplex{Tau: 1}.Transform,
+		want: []float64{
+			0.386741, 0.427415, 0.472367, 0.522046, 0.576950, 0.637628, 0.704688, 0.778801, 0.860708, 0.951229,
+			0.951229, 0.860708, 0.778801, 0.704688, 0.637628, 0.576950, 0.522046, 0.472367, 0.427415, 0.386741,
+		},
+	},
+}
+
 func TestWindows(t *testing.T) {
 	const tol = 1e-6
 
@@ -251,6 +323,60 @@ func TestGausWindowComplex(t *testing.T) {
 	}
 }
 
+func TestParamWindows(t *testing.T) {
+	const tol = 1e-6
+
+	for _, test := range paramWindowTests {
+		t.Run(test.name, func(t *testing.T) {
+			src := make([]float64, len(test.want))
+			for i := range src {
+				src[i] = 1
+			}
+
+			dst := test.fn(src)
+			if !floats.EqualApprox(dst, test.want, tol) {
+				t.Errorf("unexpected result for window function %q:\ngot:%#.6v\nwant:%#v", test.name, dst, test.want)
+			}
+
+			for i := range src {
+				src[i] = 1
+			}
+
+			dst = NewValues(test.fn, len(src)).Transform(src)
+			if !floats.EqualApprox(dst, test.want, tol) {
+				t.Errorf("unexpected result for lookup window function %q:\ngot:%#.6v\nwant:%#.6v", test.name, dst, test.want)
+			}
+		})
+	}
+}
+
+func TestParamWindowsComplex(t *testing.T) {
+	const tol = 1e-6
+
+	for _, test := range paramWindowTests {
+		t.Run(test.name+"Complex", func(t *testing.T) {
+			src := make([]complex128, len(test.want))
+			for i := range src {
+				src[i] = complex(1, 1)
+			}
+
+			dst := test.fnCmplx(src)
+			if !equalApprox(dst, test.want, tol) {
+				t.Errorf("unexpected result for window function %q:\ngot:%#.6v\nwant:%#.6v", test.name, dst, test.want)
+			}
+
+			for i := range src {
+				src[i] = complex(1, 1)
+			}
+
+			dst = NewValuesComplex(test.fnCmplx, len(src)).Transform(src)
+			if !equalApprox(dst, test.want, tol) {
+				t.Errorf("unexpected result for lookup window function %q:\ngot:%#.6v\nwant:%#.6v", test.name, dst, test.want)
+			}
+		})
+	}
+}
+
 func equalApprox(seq1 []complex128, seq2 []float64, tol float64) bool {
 	if len(seq1) != len(seq2) {
 		return false
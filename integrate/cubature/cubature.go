@@ -0,0 +1,84 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+	"sync"
+
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+// Result holds the result of a multidimensional integration.
+type Result struct {
+	// Value is the approximation to the integral.
+	Value float64
+	// Error is an estimate of the absolute error in Value.
+	Error float64
+	// FuncEvaluations is the number of evaluations of the integrand.
+	FuncEvaluations int
+	// Status describes how the integration terminated.
+	Status quad.Status
+}
+
+// checkBounds panics if min and max do not describe a non-empty
+// finite hyperrectangle.
+func checkBounds(min, max []float64) {
+	if len(min) != len(max) {
+		panic("cubature: bound length mismatch")
+	}
+	if len(min) == 0 {
+		panic("cubature: zero dimension")
+	}
+	for i, v := range min {
+		if math.IsInf(v, 0) || math.IsInf(max[i], 0) {
+			panic("cubature: infinite bound")
+		}
+		if v > max[i] {
+			panic("cubature: min > max")
+		}
+	}
+}
+
+// volume returns the volume of the hyperrectangle described by min and max.
+func volume(min, max []float64) float64 {
+	v := 1.0
+	for i, m := range min {
+		v *= max[i] - m
+	}
+	return v
+}
+
+// evaluate places f evaluated at the rows of x into fx. If concurrent is
+// positive, at most concurrent evaluations are performed simultaneously.
+func evaluate(f func([]float64) float64, x [][]float64, fx []float64, concurrent int) {
+	if concurrent > len(x) {
+		concurrent = len(x)
+	}
+	if concurrent <= 1 {
+		for i, v := range x {
+			fx[i] = f(v)
+		}
+		return
+	}
+	tasks := make(chan int)
+	go func() {
+		for i := range x {
+			tasks <- i
+		}
+		close(tasks)
+	}()
+	var wg sync.WaitGroup
+	wg.Add(concurrent)
+	for i := 0; i < concurrent; i++ {
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				fx[k] = f(x[k])
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,7 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cubature provides numerical evaluation of definite integrals of
+// multivariate functions over hyperrectangles.
+package cubature // import "gonum.org/v1/gonum/integrate/cubature"
@@ -0,0 +1,35 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/cubature"
+)
+
+func Example() {
+	// Integrate a Gaussian over the cube [-1, 1]^3.
+	f := func(x []float64) float64 {
+		return math.Exp(-floats.Dot(x, x))
+	}
+	min := []float64{-1, -1, -1}
+	max := []float64{1, 1, 1}
+	want := math.Pow(math.Sqrt(math.Pi)*math.Erf(1), 3)
+	fmt.Printf("exact    = %.8f\n", want)
+
+	res := cubature.Adaptive(f, min, max, &cubature.Settings{AbsTol: 1e-8}, 0)
+	fmt.Printf("adaptive = %.8f (%v)\n", res.Value, res.Status)
+
+	res = cubature.Smolyak(f, min, max, 8, nil, 0)
+	fmt.Printf("smolyak  = %.8f (error < 1e-8: %t)\n", res.Value, res.Error < 1e-8)
+
+	// Output:
+	// exact    = 3.33230709
+	// adaptive = 3.33230709 (Success)
+	// smolyak  = 3.33230709 (error < 1e-8: true)
+}
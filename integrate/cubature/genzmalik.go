@@ -0,0 +1,309 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"container/heap"
+	"math"
+
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+const (
+	defaultAbsTol   = 1e-8
+	defaultRelTol   = 1e-8
+	defaultMaxEvals = 1000000
+)
+
+// Settings holds the convergence settings for adaptive cubature.
+type Settings struct {
+	// AbsTol and RelTol are the requested absolute and relative accuracy
+	// of the integral. Integration terminates when the estimated error is
+	// less than max(AbsTol, RelTol*|integral|). If both are zero, both
+	// are set to 1e-8.
+	AbsTol, RelTol float64
+
+	// MaxEvals is the maximum number of integrand evaluations. If
+	// MaxEvals is zero, a default of 1e6 is used.
+	MaxEvals int
+}
+
+func (s *Settings) tolerances() (absTol, relTol float64, maxEvals int) {
+	if s == nil {
+		return defaultAbsTol, defaultRelTol, defaultMaxEvals
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 {
+		panic("cubature: negative tolerance")
+	}
+	if s.MaxEvals < 0 {
+		panic("cubature: negative evaluation limit")
+	}
+	absTol, relTol, maxEvals = s.AbsTol, s.RelTol, s.MaxEvals
+	if absTol == 0 && relTol == 0 {
+		absTol, relTol = defaultAbsTol, defaultRelTol
+	}
+	if maxEvals == 0 {
+		maxEvals = defaultMaxEvals
+	}
+	return absTol, relTol, maxEvals
+}
+
+// Adaptive approximates the integral of the function f over the
+// hyperrectangle with lower corner min and upper corner max to a
+// requested accuracy using globally adaptive subdivision with the
+// degree 7 Genz–Malik rule. The error in each subregion is estimated
+// from the embedded degree 5 rule, and the subregion with the largest
+// error is bisected along the coordinate in which the integrand has the
+// largest fourth divided difference until the total estimated error
+// satisfies the tolerances in settings or the evaluation limit is reached.
+// If settings is nil, the defaults described for Settings are used.
+//
+// The Genz–Malik rule uses 2^d + 2d^2 + 2d + 1 evaluations in d dimensions,
+// so Adaptive is best suited to problems of low to moderate dimension.
+// For higher dimensions see Smolyak and QuasiMonteCarlo.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations. f must
+// not modify or retain the slice passed to it.
+//
+// Adaptive panics if min and max do not have the same non-zero length,
+// if any bound is infinite or if any element of min is greater than the
+// corresponding element of max.
+//
+// References:
+//  A. C. Genz and A. A. Malik, "Remarks on algorithm 006: An adaptive
+//  algorithm for numerical integration over an N-dimensional rectangular
+//  region", J. Comput. Appl. Math. 6(4):295-302, 1980.
+//  J. Berntsen, T. O. Espelid and A. Genz, "An adaptive algorithm for the
+//  approximate calculation of multiple integrals", ACM Trans. Math. Softw.
+//  17(4):437-451, 1991.
+func Adaptive(f func(x []float64) float64, min, max []float64, settings *Settings, concurrent int) Result {
+	checkBounds(min, max)
+	absTol, relTol, maxEvals := settings.tolerances()
+	if volume(min, max) == 0 {
+		return Result{Status: quad.Success}
+	}
+
+	g := newGenzMalik(len(min))
+	r := g.integrate(f, min, max, concurrent)
+	evals := g.len()
+	regions := regionHeap{r}
+	value, errsum := r.value, r.err
+	for {
+		if errsum <= math.Max(absTol, relTol*math.Abs(value)) {
+			return Result{Value: value, Error: errsum, FuncEvaluations: evals, Status: quad.Success}
+		}
+		if evals+2*g.len() > maxEvals {
+			return Result{Value: value, Error: errsum, FuncEvaluations: evals, Status: quad.IterationLimit}
+		}
+
+		r := heap.Pop(&regions).(*region)
+		lo, hi := r.split()
+		g.integratePair(f, lo, hi, concurrent)
+		evals += 2 * g.len()
+		heap.Push(&regions, lo)
+		heap.Push(&regions, hi)
+
+		// Recompute the totals from scratch to avoid accumulating
+		// cancellation error.
+		value, errsum = 0, 0
+		for _, r := range regions {
+			value += r.value
+			errsum += r.err
+		}
+	}
+}
+
+// region is a subregion of the integration domain with its integral and
+// error estimates, and the coordinate along which it should be split.
+type region struct {
+	min, max []float64
+	value    float64
+	err      float64
+	axis     int
+}
+
+// split bisects r along its split axis.
+func (r *region) split() (lo, hi *region) {
+	mid := 0.5 * (r.min[r.axis] + r.max[r.axis])
+	lo = &region{min: r.min, max: append([]float64(nil), r.max...)}
+	lo.max[r.axis] = mid
+	hi = &region{min: append([]float64(nil), r.min...), max: r.max}
+	hi.min[r.axis] = mid
+	return lo, hi
+}
+
+// regionHeap is a max-heap of regions ordered by error.
+type regionHeap []*region
+
+func (h regionHeap) Len() int            { return len(h) }
+func (h regionHeap) Less(i, j int) bool  { return h[i].err > h[j].err }
+func (h regionHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *regionHeap) Push(x interface{}) { *h = append(*h, x.(*region)) }
+func (h *regionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	r := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return r
+}
+
+// Genz–Malik generator parameters.
+var (
+	lambda2 = math.Sqrt(9.0 / 70)
+	lambda3 = math.Sqrt(9.0 / 10)
+	lambda4 = math.Sqrt(9.0 / 10)
+	lambda5 = math.Sqrt(9.0 / 19)
+)
+
+// genzMalik holds the workspace for evaluating the Genz–Malik rule.
+type genzMalik struct {
+	dim int
+
+	// w7 and w5 are the weights of the degree 7 and degree 5 rules for
+	// each class of generator, normalized to unit volume.
+	w7 [5]float64
+	w5 [4]float64
+
+	x  [][]float64
+	fx []float64
+}
+
+func newGenzMalik(dim int) *genzMalik {
+	n := float64(dim)
+	g := &genzMalik{
+		dim: dim,
+		w7: [5]float64{
+			(12824 - 9120*n + 400*n*n) / 19683,
+			980.0 / 6561,
+			(1820 - 400*n) / 19683,
+			200.0 / 19683,
+			6859.0 / 19683 / math.Pow(2, n),
+		},
+		w5: [4]float64{
+			(729 - 950*n + 50*n*n) / 729,
+			245.0 / 486,
+			(265 - 100*n) / 1458,
+			25.0 / 729,
+		},
+	}
+	m := g.len()
+	g.x = make([][]float64, 2*m)
+	data := make([]float64, 2*m*dim)
+	for i := range g.x {
+		g.x[i] = data[i*dim : (i+1)*dim : (i+1)*dim]
+	}
+	g.fx = make([]float64, 2*m)
+	return g
+}
+
+// len returns the number of points in the rule.
+func (g *genzMalik) len() int {
+	return 1<<uint(g.dim) + 2*g.dim*g.dim + 2*g.dim + 1
+}
+
+// integrate returns the region min, max with its estimates.
+func (g *genzMalik) integrate(f func([]float64) float64, min, max []float64, concurrent int) *region {
+	r := &region{min: min, max: max}
+	m := g.len()
+	g.points(g.x[:m], r)
+	evaluate(f, g.x[:m], g.fx[:m], concurrent)
+	g.estimate(r, g.fx[:m])
+	return r
+}
+
+// integratePair computes the estimates for both regions, evaluating f
+// at the points of both together.
+func (g *genzMalik) integratePair(f func([]float64) float64, lo, hi *region, concurrent int) {
+	m := g.len()
+	g.points(g.x[:m], lo)
+	g.points(g.x[m:], hi)
+	evaluate(f, g.x, g.fx, concurrent)
+	g.estimate(lo, g.fx[:m])
+	g.estimate(hi, g.fx[m:])
+}
+
+// points places the rule points for r in x. The points are ordered
+// as the centre, then ±λ2 and ±λ3 along each axis, then the points
+// ±λ4 along each pair of axes, and finally the 2^d corners scaled
+// by λ5.
+func (g *genzMalik) points(x [][]float64, r *region) {
+	d := g.dim
+	c := make([]float64, d)
+	h := make([]float64, d)
+	for i := range c {
+		c[i] = 0.5 * (r.min[i] + r.max[i])
+		h[i] = 0.5 * (r.max[i] - r.min[i])
+	}
+	k := 0
+	next := func() []float64 {
+		p := x[k]
+		copy(p, c)
+		k++
+		return p
+	}
+	next()
+	for i := 0; i < d; i++ {
+		next()[i] -= lambda2 * h[i]
+		next()[i] += lambda2 * h[i]
+		next()[i] -= lambda3 * h[i]
+		next()[i] += lambda3 * h[i]
+	}
+	for i := 0; i < d; i++ {
+		for j := i + 1; j < d; j++ {
+			for _, s := range [4][2]float64{{-1, -1}, {-1, 1}, {1, -1}, {1, 1}} {
+				p := next()
+				p[i] += s[0] * lambda4 * h[i]
+				p[j] += s[1] * lambda4 * h[j]
+			}
+		}
+	}
+	for mask := 0; mask < 1<<uint(d); mask++ {
+		p := next()
+		for i := 0; i < d; i++ {
+			if mask&(1<<uint(i)) != 0 {
+				p[i] += lambda5 * h[i]
+			} else {
+				p[i] -= lambda5 * h[i]
+			}
+		}
+	}
+}
+
+// estimate sets the integral and error estimates and the split axis of r
+// from the function values fx at the points of the rule.
+func (g *genzMalik) estimate(r *region, fx []float64) {
+	d := g.dim
+	f1 := fx[0]
+	var f2, f3, f4, f5 float64
+	maxDiff := -1.0
+	for i := 0; i < d; i++ {
+		s2 := fx[1+4*i] + fx[2+4*i]
+		s3 := fx[3+4*i] + fx[4+4*i]
+		f2 += s2
+		f3 += s3
+		// The fourth divided difference along axis i.
+		diff := math.Abs(s2 - 2*f1 - (lambda2*lambda2)/(lambda3*lambda3)*(s3-2*f1))
+		width := r.max[i] - r.min[i]
+		if diff > maxDiff || (diff == maxDiff && width > r.max[r.axis]-r.min[r.axis]) {
+			maxDiff = diff
+			r.axis = i
+		}
+	}
+	k := 1 + 4*d
+	for n := 2 * d * (d - 1); n > 0; n-- {
+		f4 += fx[k]
+		k++
+	}
+	for ; k < len(fx); k++ {
+		f5 += fx[k]
+	}
+	vol := volume(r.min, r.max)
+	i7 := vol * (g.w7[0]*f1 + g.w7[1]*f2 + g.w7[2]*f3 + g.w7[3]*f4 + g.w7[4]*f5)
+	i5 := vol * (g.w5[0]*f1 + g.w5[1]*f2 + g.w5[2]*f3 + g.w5[3]*f4)
+	r.value = i7
+	r.err = math.Abs(i7 - i5)
+}
@@ -0,0 +1,135 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+// cubatureTest is a multidimensional integral with a known value.
+type cubatureTest struct {
+	name     string
+	f        func([]float64) float64
+	min, max []float64
+	value    float64
+}
+
+func cubatureTests() []cubatureTest {
+	return []cubatureTest{
+		{
+			name:  "constant",
+			f:     func([]float64) float64 { return 2 },
+			min:   []float64{-1, 0, 2},
+			max:   []float64{1, 3, 2.5},
+			value: 2 * 2 * 3 * 0.5,
+		},
+		{
+			name:  "product of exponentials",
+			f:     func(x []float64) float64 { return math.Exp(x[0] + x[1]) },
+			min:   []float64{0, -1},
+			max:   []float64{1, 2},
+			value: (math.E - 1) * (math.Exp(2) - math.Exp(-1)),
+		},
+		{
+			name: "gaussian",
+			f: func(x []float64) float64 {
+				return math.Exp(-floats.Dot(x, x))
+			},
+			min:   []float64{-1, -1, -1},
+			max:   []float64{1, 1, 1},
+			value: math.Pow(math.Sqrt(math.Pi)*math.Erf(1), 3),
+		},
+		{
+			name: "cosine of sum",
+			f: func(x []float64) float64 {
+				return math.Cos(floats.Sum(x))
+			},
+			min: []float64{0, 0, 0, 0},
+			max: []float64{1, 1, 1, 1},
+			// Re ∏(e^i - 1)/i.
+			value: real(cmplxPow((complex(math.Cos(1), math.Sin(1))-1)/1i, 4)),
+		},
+	}
+}
+
+func cmplxPow(z complex128, n int) complex128 {
+	p := complex(1, 0)
+	for i := 0; i < n; i++ {
+		p *= z
+	}
+	return p
+}
+
+func TestAdaptive(t *testing.T) {
+	t.Parallel()
+	for _, test := range cubatureTests() {
+		for _, concurrent := range []int{0, 4} {
+			res := Adaptive(test.f, test.min, test.max, nil, concurrent)
+			if res.Status != quad.Success {
+				t.Errorf("%s: unexpected status: %v", test.name, res.Status)
+			}
+			if !floats.EqualWithinAbsOrRel(res.Value, test.value, 1e-8, 1e-8) {
+				t.Errorf("%s, concurrent=%d: mismatch: got %v, want %v", test.name, concurrent, res.Value, test.value)
+			}
+		}
+	}
+}
+
+func TestAdaptiveLimit(t *testing.T) {
+	t.Parallel()
+	f := func(x []float64) float64 { return 1 / math.Sqrt(x[0]+x[1]) }
+	res := Adaptive(f, []float64{0, 0}, []float64{1, 1}, &Settings{AbsTol: 1e-14, MaxEvals: 1000}, 0)
+	if res.Status != quad.IterationLimit {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, quad.IterationLimit)
+	}
+	if res.FuncEvaluations > 1000 {
+		t.Errorf("evaluation limit exceeded: %d", res.FuncEvaluations)
+	}
+	// ∫_0^1∫_0^1 1/sqrt(x+y) dx dy = 4/3 (2√2 - 2).
+	want := 4.0 / 3 * (2*math.Sqrt2 - 2)
+	if math.Abs(res.Value-want) > 1e-2 {
+		t.Errorf("unexpected value: got %v, want %v", res.Value, want)
+	}
+}
+
+func TestGenzMalikExact(t *testing.T) {
+	t.Parallel()
+	// The degree 7 rule integrates monomials of total degree up to 7
+	// exactly and the embedded degree 5 rule those up to degree 5.
+	for d := 2; d <= 5; d++ {
+		g := newGenzMalik(d)
+		min := make([]float64, d)
+		max := make([]float64, d)
+		for i := range min {
+			min[i] = -1
+			max[i] = 1 + float64(i)
+		}
+		for _, pow := range [][2]int{{0, 0}, {1, 0}, {2, 0}, {4, 0}, {6, 0}, {2, 2}, {4, 2}, {3, 3}, {2, 4}, {7, 0}} {
+			f := func(x []float64) float64 {
+				return math.Pow(x[0], float64(pow[0])) * math.Pow(x[1], float64(pow[1]))
+			}
+			r := g.integrate(f, min, max, 0)
+			want := monomialIntegral(min[0], max[0], pow[0]) * monomialIntegral(min[1], max[1], pow[1])
+			for i := 2; i < d; i++ {
+				want *= max[i] - min[i]
+			}
+			if !floats.EqualWithinAbsOrRel(r.value, want, 1e-12, 1e-12) {
+				t.Errorf("d=%d, x^%d y^%d: mismatch: got %v, want %v", d, pow[0], pow[1], r.value, want)
+			}
+			if pow[0]+pow[1] <= 5 && r.err > 1e-12*math.Max(1, math.Abs(want)) {
+				t.Errorf("d=%d, x^%d y^%d: non-zero error for degree 5: %v", d, pow[0], pow[1], r.err)
+			}
+		}
+	}
+}
+
+func monomialIntegral(a, b float64, p int) float64 {
+	fp := float64(p + 1)
+	return (math.Pow(b, fp) - math.Pow(a, fp)) / fp
+}
@@ -0,0 +1,100 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distmv"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+// QuasiMonteCarlo approximates the integral of the function f over the
+// hyperrectangle with lower corner min and upper corner max using
+// randomized quasi-Monte Carlo integration. The estimate is the mean of
+// replicates independent estimates, each of which is the average of f
+// at n points. The error is estimated as the standard error of the mean
+// of the replicate estimates.
+//
+// For each replicate, sampler generates n points in the unit hypercube,
+// which are randomized by a uniformly distributed shift modulo one
+// (a Cranley–Patterson rotation) and mapped onto the integration domain.
+// The sampler should sample from distmv.NewUnitUniform, for example
+// samplemv.Halton or samplemv.LatinHypercube; its samples are written into
+// a zeroed matrix. If sampler is nil, a scrambled Halton sequence using src
+// is used. If src is nil, the global rand source is used for the shifts.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations. f must
+// not modify or retain the slice passed to it.
+//
+// QuasiMonteCarlo panics if n is not positive, if replicates is less than
+// two, if min and max do not have the same non-zero length, if any bound is
+// infinite or if any element of min is greater than the corresponding
+// element of max.
+//
+// References:
+//  R. Cranley and T. N. L. Patterson, "Randomization of number theoretic
+//  methods for multiple integration", SIAM J. Numer. Anal. 13(6):904-914, 1976.
+//  A. B. Owen, "A randomized Halton algorithm in R", arXiv:1706.02808, 2017.
+func QuasiMonteCarlo(f func(x []float64) float64, min, max []float64, n, replicates int, sampler samplemv.Sampler, src rand.Source, concurrent int) Result {
+	checkBounds(min, max)
+	if n <= 0 {
+		panic("cubature: non-positive number of samples")
+	}
+	if replicates < 2 {
+		panic("cubature: fewer than two replicates")
+	}
+	d := len(min)
+	if sampler == nil {
+		sampler = samplemv.Halton{
+			Kind: samplemv.Owen,
+			Q:    distmv.NewUnitUniform(d, src),
+			Src:  src,
+		}
+	}
+	uniform := rand.Float64
+	if src != nil {
+		uniform = rand.New(src).Float64
+	}
+
+	vol := volume(min, max)
+	batch := mat.NewDense(n, d, nil)
+	shift := make([]float64, d)
+	x := make([][]float64, n)
+	for i := range x {
+		x[i] = make([]float64, d)
+	}
+	fx := make([]float64, n)
+	estimates := make([]float64, replicates)
+	for r := range estimates {
+		batch.Zero()
+		sampler.Sample(batch)
+		for j := range shift {
+			shift[j] = uniform()
+		}
+		for i, p := range x {
+			for j := range p {
+				u := batch.At(i, j) + shift[j]
+				u -= math.Floor(u)
+				p[j] = min[j] + u*(max[j]-min[j])
+			}
+		}
+		evaluate(f, x, fx, concurrent)
+		estimates[r] = vol * stat.Mean(fx, nil)
+	}
+	mean, std := stat.MeanStdDev(estimates, nil)
+	return Result{
+		Value:           mean,
+		Error:           std / math.Sqrt(float64(replicates)),
+		FuncEvaluations: n * replicates,
+		Status:          quad.Success,
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat/distmv"
+	"gonum.org/v1/gonum/stat/samplemv"
+)
+
+func TestQuasiMonteCarlo(t *testing.T) {
+	t.Parallel()
+	for _, test := range cubatureTests() {
+		d := len(test.min)
+		src := rand.NewSource(1)
+		for _, sampler := range []samplemv.Sampler{
+			nil,
+			samplemv.LatinHypercube{Q: distmv.NewUnitUniform(d, src), Src: src},
+		} {
+			for _, concurrent := range []int{0, 3} {
+				res := QuasiMonteCarlo(test.f, test.min, test.max, 4096, 16, sampler, src, concurrent)
+				if res.FuncEvaluations != 4096*16 {
+					t.Errorf("%s: unexpected evaluation count: %d", test.name, res.FuncEvaluations)
+				}
+				// The estimate should lie within a few standard errors.
+				if diff := math.Abs(res.Value - test.value); diff > 5*res.Error+1e-12 {
+					t.Errorf("%s, %T: error exceeds estimate: got %v, want %v, estimated error %v",
+						test.name, sampler, res.Value, test.value, res.Error)
+				}
+				if res.Error > 1e-2*math.Max(1, math.Abs(test.value)) {
+					t.Errorf("%s, %T: error estimate too large: %v", test.name, sampler, res.Error)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"gonum.org/v1/gonum/integrate/quad"
+	"gonum.org/v1/gonum/stat/combin"
+)
+
+// Smolyak approximates the integral of the function f over the
+// hyperrectangle with lower corner min and upper corner max using a
+// Smolyak sparse grid built from a one-dimensional quadrature rule.
+// The sparse grid is the combination
+//  A(q, d) = \sum_{q-d+1 <= |i| <= q} (-1)^(q-|i|) binom(d-1, q-|i|) U^i_1 ⊗ ... ⊗ U^i_d
+// of tensor products of the one-dimensional rules U^l, where q = level+d-1
+// and U^l is the (2l-1)-point rule. Points shared between the tensor grids
+// are evaluated only once. If rule is nil, quad.Legendre is used.
+//
+// A sparse grid of the Gauss–Legendre rule at a given level integrates
+// polynomials of total degree 2*level-1 exactly while using far fewer
+// points than the equivalent tensor product rule, making Smolyak
+// suitable for smooth integrands in moderately high dimensions.
+//
+// The error is estimated as the difference between the sparse grid
+// approximations at level and level-1, so the integrand is also evaluated
+// on the grid of level-1, and FuncEvaluations counts the points of both
+// grids. If level is one, the error is not estimated and Error is +∞.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations. f must
+// not modify or retain the slice passed to it.
+//
+// Smolyak panics if level is not positive, if min and max do not have the
+// same non-zero length, if any bound is infinite or if any element of min
+// is greater than the corresponding element of max.
+//
+// References:
+//  S. A. Smolyak, "Quadrature and interpolation formulas for tensor products
+//  of certain classes of functions", Soviet Math. Dokl. 4:240-243, 1963.
+//  G. W. Wasilkowski and H. Woźniakowski, "Explicit cost bounds of algorithms
+//  for multivariate tensor product problems", J. Complexity 11:1-56, 1995.
+func Smolyak(f func(x []float64) float64, min, max []float64, level int, rule quad.FixedLocationer, concurrent int) Result {
+	checkBounds(min, max)
+	if level <= 0 {
+		panic("cubature: non-positive level")
+	}
+	if volume(min, max) == 0 {
+		return Result{Status: quad.Success}
+	}
+	if rule == nil {
+		rule = quad.Legendre{}
+	}
+	value, evals := smolyakSum(f, min, max, level, rule, concurrent)
+	res := Result{
+		Value:           value,
+		Error:           math.Inf(1),
+		FuncEvaluations: evals,
+		Status:          quad.Success,
+	}
+	if level > 1 {
+		coarse, n := smolyakSum(f, min, max, level-1, rule, concurrent)
+		res.Error = math.Abs(value - coarse)
+		res.FuncEvaluations += n
+	}
+	return res
+}
+
+// smolyakSum returns the sparse grid approximation of the integral of f at
+// the given level and the number of points of the grid.
+func smolyakSum(f func(x []float64) float64, min, max []float64, level int, rule quad.FixedLocationer, concurrent int) (value float64, evals int) {
+	x, w := smolyakGrid(min, max, level, rule)
+	fx := make([]float64, len(x))
+	evaluate(f, x, fx, concurrent)
+	for i, v := range fx {
+		value += w[i] * v
+	}
+	return value, len(x)
+}
+
+// smolyakGrid returns the points and combined weights of the sparse grid
+// for the given bounds, level and rule.
+func smolyakGrid(min, max []float64, level int, rule quad.FixedLocationer) (x [][]float64, weight []float64) {
+	d := len(min)
+
+	// Compute the one-dimensional rules for each dimension and level.
+	type rule1 struct{ x, w []float64 }
+	rules := make([][]rule1, d)
+	for j := range rules {
+		rules[j] = make([]rule1, level+1)
+		for l := 1; l <= level; l++ {
+			n := 2*l - 1
+			r := rule1{x: make([]float64, n), w: make([]float64, n)}
+			rule.FixedLocations(r.x, r.w, min[j], max[j])
+			rules[j][l] = r
+		}
+	}
+
+	index := make(map[string]int)
+	var key strings.Builder
+	add := func(p []float64, wt float64) {
+		key.Reset()
+		for _, v := range p {
+			bits := math.Float64bits(v)
+			for k := 0; k < 8; k++ {
+				key.WriteByte(byte(bits >> (8 * uint(k))))
+			}
+		}
+		if i, ok := index[key.String()]; ok {
+			weight[i] += wt
+			return
+		}
+		index[key.String()] = len(x)
+		x = append(x, append([]float64(nil), p...))
+		weight = append(weight, wt)
+	}
+
+	q := level + d - 1
+	levels := make([]int, d)
+	p := make([]float64, d)
+	pos := make([]int, d)
+	// Iterate over all multi-indices with components at least 1
+	// and q-d+1 <= |i| <= q.
+	var visit func(j, sum int)
+	visit = func(j, sum int) {
+		if j == d {
+			if sum < q-d+1 {
+				return
+			}
+			coef := float64(combin.Binomial(d-1, q-sum))
+			if (q-sum)%2 == 1 {
+				coef = -coef
+			}
+			// Add the tensor product rule for levels.
+			for i := range pos {
+				pos[i] = 0
+			}
+			for {
+				wt := coef
+				for i, l := range levels {
+					r := rules[i][l]
+					p[i] = r.x[pos[i]]
+					wt *= r.w[pos[i]]
+				}
+				add(p, wt)
+				i := 0
+				for ; i < d; i++ {
+					pos[i]++
+					if pos[i] < len(rules[i][levels[i]].x) {
+						break
+					}
+					pos[i] = 0
+				}
+				if i == d {
+					break
+				}
+			}
+			return
+		}
+		for l := 1; sum+l+(d-j-1) <= q; l++ {
+			levels[j] = l
+			visit(j+1, sum+l)
+		}
+	}
+	visit(0, 0)
+
+	// Drop points whose combined weights cancel exactly and order the
+	// remaining points for reproducible summation.
+	order := make([]int, 0, len(x))
+	for i, w := range weight {
+		if w != 0 {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(a, b int) bool {
+		pa, pb := x[order[a]], x[order[b]]
+		for k := range pa {
+			if pa[k] != pb[k] {
+				return pa[k] < pb[k]
+			}
+		}
+		return false
+	})
+	xs := make([][]float64, len(order))
+	ws := make([]float64, len(order))
+	for i, k := range order {
+		xs[i] = x[k]
+		ws[i] = weight[k]
+	}
+	return xs, ws
+}
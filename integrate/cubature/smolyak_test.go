@@ -0,0 +1,121 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cubature
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/quad"
+)
+
+func TestSmolyak(t *testing.T) {
+	t.Parallel()
+	for _, test := range cubatureTests() {
+		for _, concurrent := range []int{0, 3} {
+			res := Smolyak(test.f, test.min, test.max, 8, nil, concurrent)
+			if res.Status != quad.Success {
+				t.Errorf("%s, concurrent=%d: unexpected status: %v", test.name, concurrent, res.Status)
+			}
+			if !floats.EqualWithinAbsOrRel(res.Value, test.value, 1e-10, 1e-10) {
+				t.Errorf("%s, concurrent=%d: mismatch: got %v, want %v", test.name, concurrent, res.Value, test.value)
+			}
+			if err := math.Abs(res.Value - test.value); err > 10*res.Error+1e-14 {
+				t.Errorf("%s, concurrent=%d: error %v underestimated by %v", test.name, concurrent, err, res.Error)
+			}
+		}
+	}
+}
+
+func TestSmolyakExact(t *testing.T) {
+	t.Parallel()
+	// A sparse grid of Gauss–Legendre rules at a given level integrates
+	// polynomials of total degree 2*level-1 exactly.
+	const d = 4
+	min := []float64{-1, 0, -2, 1}
+	max := []float64{1, 1, 0.5, 3}
+	for level := 1; level <= 4; level++ {
+		deg := 2*level - 1
+		for _, pows := range [][d]int{
+			{deg, 0, 0, 0},
+			{0, 0, 0, deg},
+			{deg - 1, 1, 0, 0},
+			{deg / 2, 0, deg - deg/2, 0},
+			{1, 1, 1, deg - 3},
+		} {
+			if pows[3] < 0 {
+				continue
+			}
+			f := func(x []float64) float64 {
+				v := 1.0
+				for i, p := range pows {
+					v *= math.Pow(x[i], float64(p))
+				}
+				return v
+			}
+			want := 1.0
+			for i, p := range pows {
+				want *= monomialIntegral(min[i], max[i], p)
+			}
+			got := Smolyak(f, min, max, level, quad.Legendre{}, 0).Value
+			if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("level=%d, powers %v: mismatch: got %v, want %v", level, pows, got, want)
+			}
+		}
+	}
+}
+
+func TestSmolyakGridSize(t *testing.T) {
+	t.Parallel()
+	// The sparse grid grows much more slowly than the tensor product grid.
+	const d, level = 6, 4
+	min := make([]float64, d)
+	max := make([]float64, d)
+	for i := range max {
+		max[i] = 1
+	}
+	x, w := smolyakGrid(min, max, level, quad.Legendre{})
+	tensor := math.Pow(2*level-1, d)
+	if float64(len(x)) >= tensor/10 {
+		t.Errorf("sparse grid too large: %d points, tensor grid has %v", len(x), tensor)
+	}
+	if sum := floats.Sum(w); math.Abs(sum-1) > 1e-12 {
+		t.Errorf("weights do not sum to volume: got %v", sum)
+	}
+}
+
+func TestSmolyakResult(t *testing.T) {
+	t.Parallel()
+	f := func(x []float64) float64 { return math.Exp(x[0] + 2*x[1]) }
+	min := []float64{0, 0}
+	max := []float64{1, 1}
+	want := (math.E - 1) * (math.Exp(2) - 1) / 2
+
+	// At level one there is no coarser grid to estimate the error.
+	res := Smolyak(f, min, max, 1, nil, 0)
+	if !math.IsInf(res.Error, 1) || res.FuncEvaluations != 1 {
+		t.Errorf("unexpected level one result: %+v", res)
+	}
+
+	// The error estimate is the difference to the coarser grid, and both
+	// grids are evaluated.
+	for level := 2; level <= 5; level++ {
+		res := Smolyak(f, min, max, level, nil, 0)
+		x, _ := smolyakGrid(min, max, level, quad.Legendre{})
+		xc, _ := smolyakGrid(min, max, level-1, quad.Legendre{})
+		if res.FuncEvaluations != len(x)+len(xc) {
+			t.Errorf("level=%d: unexpected number of evaluations: got %d, want %d", level, res.FuncEvaluations, len(x)+len(xc))
+		}
+		if err := math.Abs(res.Value - want); err > res.Error {
+			t.Errorf("level=%d: error %v underestimated by %v", level, err, res.Error)
+		}
+	}
+
+	res = Smolyak(f, min, []float64{0, 1}, 3, nil, 0)
+	if res.Value != 0 || res.Error != 0 || res.Status != quad.Success {
+		t.Errorf("unexpected result for empty domain: %+v", res)
+	}
+}
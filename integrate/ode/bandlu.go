@@ -0,0 +1,131 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import "math"
+
+// bandLU is the LU factorization with partial pivoting of an n×n band
+// matrix with kl sub-diagonals and ku super-diagonals. Row interchanges
+// fill in up to kl additional super-diagonals, so each row stores the
+// columns from i-kl to i+kl+ku.
+type bandLU struct {
+	n, kl, ku int
+	width     int
+	data      []float64
+	piv       []int
+	singular  bool
+}
+
+// reset sets the receiver to a zero n×n band matrix with the given
+// bandwidths, ready to be filled by set.
+func (lu *bandLU) reset(n, kl, ku int) {
+	lu.n, lu.kl, lu.ku = n, kl, ku
+	lu.width = 2*kl + ku + 1
+	size := n * lu.width
+	if cap(lu.data) < size {
+		lu.data = make([]float64, size)
+	}
+	lu.data = lu.data[:size]
+	for i := range lu.data {
+		lu.data[i] = 0
+	}
+	if cap(lu.piv) < n {
+		lu.piv = make([]int, n)
+	}
+	lu.piv = lu.piv[:n]
+	lu.singular = false
+}
+
+func (lu *bandLU) index(i, j int) int {
+	return i*lu.width + j - i + lu.kl
+}
+
+// set sets the element at row i and column j, which must be within the
+// band, to v.
+func (lu *bandLU) set(i, j int, v float64) {
+	if j < i-lu.kl || i+lu.ku < j {
+		panic("ode: element outside band")
+	}
+	lu.data[lu.index(i, j)] = v
+}
+
+// factorize computes the LU factorization of the matrix in place.
+// factorize returns false if the matrix is singular.
+func (lu *bandLU) factorize() bool {
+	n, kl, ku := lu.n, lu.kl, lu.ku
+	d := lu.data
+	for j := 0; j < n; j++ {
+		// Find the pivot in column j.
+		last := min(n-1, j+kl)
+		p := j
+		max := math.Abs(d[lu.index(j, j)])
+		for i := j + 1; i <= last; i++ {
+			if v := math.Abs(d[lu.index(i, j)]); v > max {
+				p, max = i, v
+			}
+		}
+		lu.piv[j] = p
+		if max == 0 {
+			lu.singular = true
+			return false
+		}
+		end := min(n-1, j+kl+ku)
+		if p != j {
+			for c := j; c <= end; c++ {
+				a, b := lu.index(j, c), lu.index(p, c)
+				d[a], d[b] = d[b], d[a]
+			}
+		}
+		pivot := d[lu.index(j, j)]
+		for i := j + 1; i <= last; i++ {
+			l := d[lu.index(i, j)] / pivot
+			d[lu.index(i, j)] = l
+			if l == 0 {
+				continue
+			}
+			for c := j + 1; c <= end; c++ {
+				d[lu.index(i, c)] -= l * d[lu.index(j, c)]
+			}
+		}
+	}
+	return true
+}
+
+// solve solves A x = b in place, with b holding x on return.
+func (lu *bandLU) solve(b []float64) {
+	n, kl, ku := lu.n, lu.kl, lu.ku
+	d := lu.data
+	// Apply the row interchanges and the unit lower triangular factor.
+	for j := 0; j < n; j++ {
+		if p := lu.piv[j]; p != j {
+			b[j], b[p] = b[p], b[j]
+		}
+		for i := j + 1; i <= min(n-1, j+kl); i++ {
+			b[i] -= d[lu.index(i, j)] * b[j]
+		}
+	}
+	// Solve with the upper triangular factor.
+	for i := n - 1; i >= 0; i-- {
+		v := b[i]
+		for c := i + 1; c <= min(n-1, i+kl+ku); c++ {
+			v -= d[lu.index(i, c)] * b[c]
+		}
+		b[i] = v / d[lu.index(i, i)]
+	}
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
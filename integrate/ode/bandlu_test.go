@@ -0,0 +1,70 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestBandLU(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		n, kl, ku int
+	}{
+		{n: 1, kl: 0, ku: 0},
+		{n: 5, kl: 0, ku: 0},
+		{n: 6, kl: 1, ku: 1},
+		{n: 10, kl: 3, ku: 1},
+		{n: 10, kl: 1, ku: 4},
+		{n: 20, kl: 5, ku: 3},
+		{n: 7, kl: 6, ku: 6},
+	} {
+		var lu bandLU
+		lu.reset(test.n, test.kl, test.ku)
+		a := mat.NewDense(test.n, test.n, nil)
+		for i := 0; i < test.n; i++ {
+			for j := max(0, i-test.kl); j < min(test.n, i+test.ku+1); j++ {
+				v := rnd.NormFloat64()
+				a.Set(i, j, v)
+				lu.set(i, j, v)
+			}
+		}
+		b := make([]float64, test.n)
+		for i := range b {
+			b[i] = rnd.NormFloat64()
+		}
+		var want mat.VecDense
+		err := want.SolveVec(a, mat.NewVecDense(test.n, b))
+		if err != nil {
+			t.Fatalf("unexpected error for n=%d kl=%d ku=%d: %v", test.n, test.kl, test.ku, err)
+		}
+		if !lu.factorize() {
+			t.Errorf("unexpected singular matrix for n=%d kl=%d ku=%d", test.n, test.kl, test.ku)
+			continue
+		}
+		got := append([]float64(nil), b...)
+		lu.solve(got)
+		if !floats.EqualApprox(got, want.RawVector().Data, 1e-10) {
+			t.Errorf("solution mismatch for n=%d kl=%d ku=%d:\ngot  %v\nwant %v",
+				test.n, test.kl, test.ku, got, want.RawVector().Data)
+		}
+	}
+
+	// A matrix with a zero column is singular.
+	var lu bandLU
+	lu.reset(3, 1, 1)
+	lu.set(0, 0, 1)
+	lu.set(1, 0, 2)
+	lu.set(2, 2, 1)
+	if lu.factorize() {
+		t.Errorf("singular matrix not detected")
+	}
+}
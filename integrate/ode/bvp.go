@@ -0,0 +1,663 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultBVPTol       = 1e-3
+	defaultMaxNodes     = 1000
+	defaultBVPMaxIter   = 8
+	defaultShootMaxIter = 20
+
+	// Newton iteration parameters.
+	maxJacEvals = 4
+	armijo      = 0.2
+	backtrack   = 0.5
+	maxTrials   = 4
+)
+
+// BVP describes a two-point boundary value problem
+//  dy/dx = f(x, y),  a <= x <= b,
+//  bc(y(a), y(b)) = 0,
+// where y has n components and bc has n components.
+type BVP struct {
+	// Func evaluates the right-hand side f(x, y) of the system and stores
+	// the result in-place into dy. Func must not modify y. Func must not
+	// be nil.
+	Func func(dy []float64, x float64, y []float64)
+
+	// Jac evaluates the Jacobian matrix ∂f/∂y at x and y and stores the
+	// result in-place into the n×n matrix dst. If Jac is nil, the Jacobian
+	// is approximated by forward finite differences using fd.Jacobian.
+	Jac func(dst *mat.Dense, x float64, y []float64)
+
+	// BC evaluates the boundary condition residuals at ya = y(a) and
+	// yb = y(b) and stores the result in-place into res. BC must not
+	// modify ya or yb. BC must not be nil.
+	BC func(res, ya, yb []float64)
+
+	// BCJac evaluates the Jacobian matrices of the boundary conditions
+	// with respect to ya and yb and stores the results in-place into the
+	// n×n matrices dya and dyb. If BCJac is nil, the Jacobians are
+	// approximated by forward finite differences using fd.Jacobian.
+	BCJac func(dya, dyb *mat.Dense, ya, yb []float64)
+}
+
+// BVPSettings holds the settings for solving a boundary value problem.
+type BVPSettings struct {
+	// Tol is the tolerance for the root mean square of the relative
+	// residuals of the collocation solution on each mesh interval.
+	// If Tol is zero, a default of 1e-3 is used. For shooting, Tol is
+	// the tolerance for the continuity and boundary condition residuals.
+	Tol float64
+
+	// BCTol is the tolerance for the absolute boundary condition
+	// residuals. If BCTol is zero, Tol is used.
+	BCTol float64
+
+	// MaxNodes is the maximum number of mesh nodes used by
+	// SolveBVP. If MaxNodes is zero, a default of 1000 is used.
+	MaxNodes int
+
+	// MaxIterations is the maximum number of Newton iterations. For
+	// SolveBVP, this is the number of iterations on each mesh and the
+	// default is 8. For Shoot, the default is 20.
+	MaxIterations int
+
+	// IVP holds the settings for the integrations of Shoot. If IVP is
+	// nil, the absolute and relative tolerances are set to 1e-3*Tol.
+	IVP *Settings
+}
+
+// BVPResult holds the result of solving a boundary value problem.
+type BVPResult struct {
+	// X holds the final mesh or shooting nodes.
+	X []float64
+	// Y holds the solution, with the ith row holding y(X[i]).
+	Y *mat.Dense
+	// Solution is the continuous solution over the interval.
+	Solution *Solution
+	// Residuals holds the root mean square relative residuals of the
+	// collocation solution on each mesh interval. Residuals is nil for
+	// solutions computed by Shoot.
+	Residuals []float64
+
+	FuncEvaluations int // Number of evaluations of BVP.Func.
+	JacEvaluations  int // Number of evaluations of the Jacobians.
+	Iterations      int // Total number of Newton iterations.
+	Status          Status
+}
+
+func (s *BVPSettings) tolerances(defaultMaxIter int) (tol, bcTol float64, maxNodes, maxIter int) {
+	if s == nil {
+		s = &BVPSettings{}
+	}
+	if s.Tol < 0 || s.BCTol < 0 || s.MaxNodes < 0 || s.MaxIterations < 0 {
+		panic("ode: negative BVP setting")
+	}
+	tol, bcTol, maxNodes, maxIter = s.Tol, s.BCTol, s.MaxNodes, s.MaxIterations
+	if tol == 0 {
+		tol = defaultBVPTol
+	}
+	if tol < 100*eps {
+		tol = 100 * eps
+	}
+	if bcTol == 0 {
+		bcTol = tol
+	}
+	if maxNodes == 0 {
+		maxNodes = defaultMaxNodes
+	}
+	if maxIter == 0 {
+		maxIter = defaultMaxIter
+	}
+	return tol, bcTol, maxNodes, maxIter
+}
+
+// SolveBVP solves the two-point boundary value problem p by collocation
+// with mesh refinement. The solution is a continuously differentiable
+// piecewise cubic polynomial that satisfies the differential equations at
+// the mesh nodes and interval midpoints, which is equivalent to the
+// fourth order Lobatto IIIA method. The collocation equations are solved
+// by damped Newton iterations, using a banded LU factorization when the
+// boundary conditions are separated and mat.LU otherwise.
+//
+// After the collocation equations are solved, the root mean square of the
+// residual of the differential equations, relative to 1+|f|, is estimated
+// on each interval and nodes are added to intervals where it exceeds the
+// tolerance. This is repeated until the tolerances are satisfied or the
+// maximum number of nodes is reached.
+//
+// x holds the initial mesh, which must be strictly increasing with at least
+// two nodes, and the rows of y hold an initial guess for the solution at the
+// nodes of x. If settings is nil, the defaults described for BVPSettings are
+// used. SolveBVP returns the result and an error if the tolerances could not
+// be satisfied.
+//
+// Reference:
+//  J. Kierzenka and L. F. Shampine, "A BVP solver based on residual control
+//  and the Matlab PSE", ACM Trans. Math. Softw. 27(3):299-316, 2001.
+func SolveBVP(p BVP, x []float64, y mat.Matrix, settings *BVPSettings) (*BVPResult, error) {
+	n := checkBVP(p, x, y)
+	tol, bcTol, maxNodes, maxIter := settings.tolerances(defaultBVPMaxIter)
+	if len(x) > maxNodes {
+		panic("ode: initial mesh exceeds node limit")
+	}
+
+	c := &collocation{p: p, n: n, tol: tol, bcTol: bcTol}
+	x = append([]float64(nil), x...)
+	yv := make([]float64, len(x)*n)
+	for i := range x {
+		for j := 0; j < n; j++ {
+			yv[i*n+j] = y.At(i, j)
+		}
+	}
+
+	var (
+		status Status
+		iter   int
+		rms    []float64
+		f      []float64
+	)
+	for {
+		var it int
+		it, status = c.newton(x, yv, maxIter)
+		iter += it
+		if status == SingularJacobian {
+			break
+		}
+
+		var colRes, bcRes, fMid []float64
+		colRes, bcRes, f, fMid, _ = c.residuals(x, yv)
+		rms = c.rmsResiduals(x, yv, f, colRes, fMid)
+
+		bcOK := floats.Norm(bcRes, math.Inf(1)) <= bcTol
+		var insert1, insert2 int
+		for _, r := range rms {
+			switch {
+			case r > 100*tol:
+				insert2++
+			case r > tol:
+				insert1++
+			}
+		}
+		if insert1+insert2 == 0 {
+			if bcOK {
+				status = Success
+			} else {
+				status = NoConvergence
+			}
+			break
+		}
+		if len(x)+insert1+2*insert2 > maxNodes {
+			status = NodeLimit
+			break
+		}
+		x, yv = c.refine(x, yv, f, rms)
+	}
+	if f == nil || len(f) != len(yv) {
+		f = make([]float64, len(yv))
+		for i, xi := range x {
+			c.fun(f[i*n:(i+1)*n], xi, yv[i*n:(i+1)*n])
+		}
+	}
+
+	res := &BVPResult{
+		X:               x,
+		Y:               mat.NewDense(len(x), n, yv),
+		Solution:        hermiteSolution(x, yv, f, n),
+		Residuals:       rms,
+		FuncEvaluations: c.evals,
+		JacEvaluations:  c.jacEvals,
+		Iterations:      iter,
+		Status:          status,
+	}
+	return res, status.Err()
+}
+
+// checkBVP panics if the problem, mesh or initial guess are invalid and
+// returns the dimension of the problem.
+func checkBVP(p BVP, x []float64, y mat.Matrix) int {
+	if p.Func == nil || p.BC == nil {
+		panic("ode: nil function")
+	}
+	if len(x) < 2 {
+		panic("ode: fewer than two nodes")
+	}
+	for i := 1; i < len(x); i++ {
+		if !(x[i] > x[i-1]) {
+			panic("ode: nodes not strictly increasing")
+		}
+	}
+	r, n := y.Dims()
+	if r != len(x) {
+		panic("ode: initial guess length mismatch")
+	}
+	return n
+}
+
+// hermiteSolution returns the piecewise cubic Hermite interpolant of the
+// values y and derivatives f at the nodes x.
+func hermiteSolution(x, y, f []float64, n int) *Solution {
+	sol := &Solution{t0: x[0], t1: x[len(x)-1], dim: n}
+	sol.steps = make([]segment, len(x)-1)
+	for i := range sol.steps {
+		h := x[i+1] - x[i]
+		sol.steps[i] = segment{
+			t0: x[i],
+			h:  h,
+			coef: hermite(nil, h,
+				y[i*n:(i+1)*n], f[i*n:(i+1)*n],
+				y[(i+1)*n:(i+2)*n], f[(i+1)*n:(i+2)*n]),
+		}
+	}
+	return sol
+}
+
+// collocation holds the state of the collocation solver.
+type collocation struct {
+	p          BVP
+	n          int
+	tol, bcTol float64
+
+	evals, jacEvals int
+
+	sys linearSystem
+}
+
+func (c *collocation) fun(dy []float64, x float64, y []float64) {
+	c.evals++
+	c.p.Func(dy, x, y)
+}
+
+// jac evaluates the Jacobian of f at x and y, where f(x, y) = dy.
+func (c *collocation) jac(dst *mat.Dense, x float64, y, dy []float64) {
+	c.jacEvals++
+	if c.p.Jac != nil {
+		c.p.Jac(dst, x, y)
+		return
+	}
+	fd.Jacobian(dst, func(dy, y []float64) {
+		c.fun(dy, x, y)
+	}, y, &fd.JacobianSettings{OriginValue: dy})
+}
+
+// bcJac evaluates the Jacobians of the boundary conditions, where
+// bc(ya, yb) = res.
+func (c *collocation) bcJac(dya, dyb *mat.Dense, ya, yb, res []float64) {
+	c.jacEvals++
+	if c.p.BCJac != nil {
+		c.p.BCJac(dya, dyb, ya, yb)
+		return
+	}
+	bcJacobian(c.p.BC, dya, dyb, ya, yb, res)
+}
+
+// bcJacobian approximates the Jacobians of the boundary conditions bc
+// by finite differences.
+func bcJacobian(bc func(res, ya, yb []float64), dya, dyb *mat.Dense, ya, yb, res []float64) {
+	settings := &fd.JacobianSettings{OriginValue: res}
+	fd.Jacobian(dya, func(r, ya []float64) {
+		bc(r, ya, yb)
+	}, ya, settings)
+	fd.Jacobian(dyb, func(r, yb []float64) {
+		bc(r, ya, yb)
+	}, yb, settings)
+}
+
+// residuals returns the collocation residuals
+//  r_i = y_{i+1} - y_i - h_i/6 (f_i + 4 f_{i+1/2} + f_{i+1}),
+// the boundary condition residuals, the values of f at the nodes and
+// the midpoints, and the values of the cubic interpolant at the midpoints.
+func (c *collocation) residuals(x, y []float64) (colRes, bcRes, f, fMid, yMid []float64) {
+	n := c.n
+	m := len(x) - 1
+	f = make([]float64, (m+1)*n)
+	for i, xi := range x {
+		c.fun(f[i*n:(i+1)*n], xi, y[i*n:(i+1)*n])
+	}
+	colRes = make([]float64, m*n)
+	fMid = make([]float64, m*n)
+	yMid = make([]float64, m*n)
+	for i := 0; i < m; i++ {
+		h := x[i+1] - x[i]
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			yMid[k] = 0.5*(y[k]+y[k+n]) - h/8*(f[k+n]-f[k])
+		}
+		c.fun(fMid[i*n:(i+1)*n], x[i]+h/2, yMid[i*n:(i+1)*n])
+		for j := 0; j < n; j++ {
+			k := i*n + j
+			colRes[k] = y[k+n] - y[k] - h/6*(f[k]+4*fMid[k]+f[k+n])
+		}
+	}
+	bcRes = make([]float64, n)
+	c.p.BC(bcRes, y[:n], y[m*n:])
+	return colRes, bcRes, f, fMid, yMid
+}
+
+// converged returns whether the collocation and boundary condition
+// residuals are small enough to stop the Newton iterations.
+func (c *collocation) converged(x, colRes, bcRes, fMid []float64) bool {
+	n := c.n
+	for i, r := range colRes {
+		h := x[i/n+1] - x[i/n]
+		if math.Abs(r) >= 2.0/3*h*0.05*c.tol*(1+math.Abs(fMid[i])) {
+			return false
+		}
+	}
+	return floats.Norm(bcRes, math.Inf(1)) < c.bcTol
+}
+
+// newton solves the collocation equations on the mesh x by damped Newton
+// iterations starting from y, which is updated in place. It returns the
+// number of iterations and SingularJacobian if the Jacobian was singular,
+// NoConvergence if the iterations did not converge and Success otherwise.
+func (c *collocation) newton(x, y []float64, maxIter int) (int, Status) {
+	size := len(y)
+	colRes, bcRes, f, fMid, yMid := c.residuals(x, y)
+	res := append(colRes, bcRes...)
+	step := make([]float64, size)
+	stepNew := make([]float64, size)
+	yNew := make([]float64, size)
+
+	recompute := true
+	njev := 0
+	var cost float64
+	for iter := 1; iter <= maxIter; iter++ {
+		if recompute {
+			if !c.jacobian(x, y, f, yMid, fMid, bcRes) {
+				return iter, SingularJacobian
+			}
+			njev++
+			copy(step, res)
+			if err := c.sys.solve(step); err != nil {
+				return iter, SingularJacobian
+			}
+			cost = floats.Dot(step, step)
+		}
+
+		alpha := 1.0
+		var costNew float64
+		for trial := 0; trial <= maxTrials; trial++ {
+			floats.AddScaledTo(yNew, y, -alpha, step)
+			colRes, bcRes, f, fMid, yMid = c.residuals(x, yNew)
+			res = append(colRes, bcRes...)
+			copy(stepNew, res)
+			if err := c.sys.solve(stepNew); err != nil {
+				return iter, SingularJacobian
+			}
+			costNew = floats.Dot(stepNew, stepNew)
+			if costNew < (1-2*alpha*armijo)*cost {
+				break
+			}
+			if trial < maxTrials {
+				alpha *= backtrack
+			}
+		}
+		copy(y, yNew)
+
+		if c.converged(x, colRes, bcRes, fMid) {
+			return iter, Success
+		}
+		if njev == maxJacEvals {
+			return iter, NoConvergence
+		}
+		if alpha == 1 {
+			step, stepNew = stepNew, step
+			cost = costNew
+			recompute = false
+		} else {
+			recompute = true
+		}
+	}
+	return maxIter, NoConvergence
+}
+
+// jacobian forms and factorizes the Jacobian of the collocation equations.
+// It returns false if the Jacobian is singular.
+func (c *collocation) jacobian(x, y, f, yMid, fMid, bcRes []float64) bool {
+	n := c.n
+	m := len(x) - 1
+	ja := mat.NewDense(n, n, nil)
+	jb := mat.NewDense(n, n, nil)
+	jm := mat.NewDense(n, n, nil)
+	var tmp, blk mat.Dense
+
+	// The Jacobian of the boundary conditions determines the ordering
+	// of the equations.
+	dya := mat.NewDense(n, n, nil)
+	dyb := mat.NewDense(n, n, nil)
+	c.bcJac(dya, dyb, y[:n], y[m*n:], bcRes)
+	c.sys.init(dya, dyb, n, m)
+
+	c.jac(ja, x[0], y[:n], f[:n])
+	for i := 0; i < m; i++ {
+		h := x[i+1] - x[i]
+		c.jac(jb, x[i+1], y[(i+1)*n:(i+2)*n], f[(i+1)*n:(i+2)*n])
+		c.jac(jm, x[i]+h/2, yMid[i*n:(i+1)*n], fMid[i*n:(i+1)*n])
+
+		// ∂r_i/∂y_i = -I - h/6 (J_i + 4 J_m (I/2 + h/8 J_i)).
+		tmp.Mul(jm, ja)
+		tmp.Scale(h/8, &tmp)
+		tmp.Add(&tmp, scaled(0.5, jm))
+		blk.Scale(4, &tmp)
+		blk.Add(&blk, ja)
+		blk.Scale(-h/6, &blk)
+		for j := 0; j < n; j++ {
+			blk.Set(j, j, blk.At(j, j)-1)
+		}
+		c.sys.setBlock(i, i, &blk)
+
+		// ∂r_i/∂y_{i+1} = I - h/6 (J_{i+1} + 4 J_m (I/2 - h/8 J_{i+1})).
+		tmp.Mul(jm, jb)
+		tmp.Scale(-h/8, &tmp)
+		tmp.Add(&tmp, scaled(0.5, jm))
+		blk.Scale(4, &tmp)
+		blk.Add(&blk, jb)
+		blk.Scale(-h/6, &blk)
+		for j := 0; j < n; j++ {
+			blk.Set(j, j, blk.At(j, j)+1)
+		}
+		c.sys.setBlock(i, i+1, &blk)
+
+		ja, jb = jb, ja
+	}
+	return c.sys.factorize()
+}
+
+func scaled(alpha float64, a mat.Matrix) *mat.Dense {
+	var d mat.Dense
+	d.Scale(alpha, a)
+	return &d
+}
+
+// rmsResiduals returns the root mean square of the relative residuals
+//  (S'(x) - f(x, S(x))) / (1 + |f(x, S(x))|)
+// of the cubic interpolant S on each interval, estimated by five-point
+// Lobatto quadrature.
+func (c *collocation) rmsResiduals(x, y, f, colRes, fMid []float64) []float64 {
+	n := c.n
+	m := len(x) - 1
+	sol := hermiteSolution(x, y, f, n)
+	rms := make([]float64, m)
+	ys := make([]float64, n)
+	ds := make([]float64, n)
+	fs := make([]float64, n)
+	for i := 0; i < m; i++ {
+		h := x[i+1] - x[i]
+		var rMid float64
+		for j := 0; j < n; j++ {
+			r := 1.5 / h * colRes[i*n+j] / (1 + math.Abs(fMid[i*n+j]))
+			rMid += r * r
+		}
+		var r12 float64
+		s := 0.5 * h * math.Sqrt(3.0/7)
+		for _, xs := range [2]float64{x[i] + h/2 - s, x[i] + h/2 + s} {
+			seg := &sol.steps[i]
+			seg.at(ys, xs)
+			seg.derivative(ds, xs)
+			c.fun(fs, xs, ys)
+			for j := 0; j < n; j++ {
+				r := (ds[j] - fs[j]) / (1 + math.Abs(fs[j]))
+				r12 += r * r
+			}
+		}
+		rms[i] = math.Sqrt(0.5 * (32.0/45*rMid + 49.0/90*r12))
+	}
+	return rms
+}
+
+// refine returns a new mesh with one node inserted into intervals whose
+// residual exceeds the tolerance and two nodes into those whose residual
+// exceeds 100 times the tolerance, with the solution interpolated onto it.
+func (c *collocation) refine(x, y, f, rms []float64) (xNew, yNew []float64) {
+	n := c.n
+	sol := hermiteSolution(x, y, f, n)
+	xNew = []float64{x[0]}
+	for i, r := range rms {
+		h := x[i+1] - x[i]
+		switch {
+		case r > 100*c.tol:
+			xNew = append(xNew, x[i]+h/3, x[i]+2*h/3)
+		case r > c.tol:
+			xNew = append(xNew, x[i]+h/2)
+		}
+		xNew = append(xNew, x[i+1])
+	}
+	yNew = make([]float64, len(xNew)*n)
+	for i, xi := range xNew {
+		sol.At(yNew[i*n:(i+1)*n], xi)
+	}
+	return xNew, yNew
+}
+
+// linearSystem is the Newton system of the collocation equations. When
+// the boundary conditions are separated, the equations are ordered so that
+// the matrix is banded and a band LU factorization is used. Otherwise the
+// matrix is factorized with mat.LU.
+type linearSystem struct {
+	n, m int
+
+	// rows maps the boundary conditions to matrix rows.
+	rows []int
+	// left is the number of boundary conditions that depend only on y(a).
+	left int
+
+	banded bool
+	band   bandLU
+	dense  *mat.Dense
+	lu     mat.LU
+	rhs    []float64
+}
+
+// init sets the structure of the system from the Jacobians of the
+// boundary conditions and fills the boundary condition rows.
+func (s *linearSystem) init(dya, dyb *mat.Dense, n, m int) {
+	s.n, s.m = n, m
+	s.rows = make([]int, n)
+	var left, right []int
+	s.banded = true
+	for i := 0; i < n; i++ {
+		da := floats.Norm(dya.RawRowView(i), math.Inf(1))
+		db := floats.Norm(dyb.RawRowView(i), math.Inf(1))
+		switch {
+		case db == 0:
+			left = append(left, i)
+		case da == 0:
+			right = append(right, i)
+		default:
+			s.banded = false
+		}
+	}
+	size := (m + 1) * n
+	if s.banded {
+		s.left = len(left)
+		for k, i := range left {
+			s.rows[i] = k
+		}
+		for k, i := range right {
+			s.rows[i] = s.left + m*n + k
+		}
+		s.band.reset(size, s.left+n-1, max(n-1, 2*n-1-s.left))
+	} else {
+		s.left = 0
+		for i := range s.rows {
+			s.rows[i] = m*n + i
+		}
+		s.dense = mat.NewDense(size, size, nil)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if v := dya.At(i, j); v != 0 || !s.banded {
+				s.set(s.rows[i], j, v)
+			}
+			if v := dyb.At(i, j); v != 0 || !s.banded {
+				s.set(s.rows[i], m*n+j, v)
+			}
+		}
+	}
+	if cap(s.rhs) < size {
+		s.rhs = make([]float64, size)
+	}
+	s.rhs = s.rhs[:size]
+}
+
+func (s *linearSystem) set(i, j int, v float64) {
+	if s.banded {
+		s.band.set(i, j, v)
+		return
+	}
+	s.dense.Set(i, j, v)
+}
+
+// setBlock sets the derivative of the collocation residual of interval i
+// with respect to the solution at node j.
+func (s *linearSystem) setBlock(i, j int, blk *mat.Dense) {
+	n := s.n
+	for r := 0; r < n; r++ {
+		for c := 0; c < n; c++ {
+			s.set(s.left+i*n+r, j*n+c, blk.At(r, c))
+		}
+	}
+}
+
+// factorize factorizes the system matrix and returns false if it is
+// singular. The determinant of the large Jacobian over- or underflows, so
+// with mat.LU the matrix is deemed singular if its estimated condition
+// number exceeds mat.ConditionTolerance.
+func (s *linearSystem) factorize() bool {
+	if s.banded {
+		return s.band.factorize()
+	}
+	s.lu.Factorize(s.dense)
+	return s.lu.Cond() <= mat.ConditionTolerance
+}
+
+// solve solves the system in place for the residuals b ordered as the
+// collocation residuals followed by the boundary condition residuals.
+// solve returns a mat.Condition error if the matrix is ill-conditioned.
+func (s *linearSystem) solve(b []float64) error {
+	n, m := s.n, s.m
+	rhs := s.rhs
+	copy(rhs[s.left:], b[:m*n])
+	for i, r := range s.rows {
+		rhs[r] = b[m*n+i]
+	}
+	if s.banded {
+		s.band.solve(rhs)
+		copy(b, rhs)
+		return nil
+	}
+	v := mat.NewVecDense(len(b), b)
+	return s.lu.SolveVecTo(v, false, mat.NewVecDense(len(rhs), rhs))
+}
@@ -0,0 +1,288 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+type bvpTest struct {
+	name  string
+	p     BVP
+	a, b  float64
+	guess []float64
+	want  func(x float64) []float64
+}
+
+func bvpTests() []bvpTest {
+	// theta solves theta = sqrt(2) cosh(theta/4) for the lower branch
+	// of the Bratu problem.
+	theta := 1.0
+	for i := 0; i < 100; i++ {
+		theta = math.Sqrt2 * math.Cosh(theta/4)
+	}
+
+	return []bvpTest{
+		{
+			// y'' = -y, y(0) = 0, y(π/2) = 1.
+			name: "sine",
+			p: BVP{
+				Func: func(dy []float64, _ float64, y []float64) {
+					dy[0] = y[1]
+					dy[1] = -y[0]
+				},
+				Jac: func(dst *mat.Dense, _ float64, _ []float64) {
+					dst.Set(0, 0, 0)
+					dst.Set(0, 1, 1)
+					dst.Set(1, 0, -1)
+					dst.Set(1, 1, 0)
+				},
+				BC: func(res, ya, yb []float64) {
+					res[0] = ya[0]
+					res[1] = yb[0] - 1
+				},
+			},
+			a:     0,
+			b:     math.Pi / 2,
+			guess: []float64{0.5, 0},
+			want: func(x float64) []float64 {
+				return []float64{math.Sin(x), math.Cos(x)}
+			},
+		},
+		{
+			// The Bratu problem y'' + exp(y) = 0, y(0) = y(1) = 0.
+			name: "bratu",
+			p: BVP{
+				Func: func(dy []float64, _ float64, y []float64) {
+					dy[0] = y[1]
+					dy[1] = -math.Exp(y[0])
+				},
+				BC: func(res, ya, yb []float64) {
+					res[0] = ya[0]
+					res[1] = yb[0]
+				},
+			},
+			a:     0,
+			b:     1,
+			guess: []float64{0, 0},
+			want: func(x float64) []float64 {
+				c := math.Cosh(theta / 4)
+				u := (x - 0.5) * theta / 2
+				return []float64{
+					-2 * math.Log(math.Cosh(u)/c),
+					-theta * math.Tanh(u),
+				}
+			},
+		},
+		{
+			// Deflection of a simply supported beam under a uniform load,
+			// y'''' = 1, y(0) = y''(0) = y(1) = y''(1) = 0.
+			name: "beam",
+			p: BVP{
+				Func: func(dy []float64, _ float64, y []float64) {
+					dy[0] = y[1]
+					dy[1] = y[2]
+					dy[2] = y[3]
+					dy[3] = 1
+				},
+				BC: func(res, ya, yb []float64) {
+					res[0] = ya[0]
+					res[1] = ya[2]
+					res[2] = yb[0]
+					res[3] = yb[2]
+				},
+			},
+			a:     0,
+			b:     1,
+			guess: []float64{0, 0, 0, 0},
+			want: func(x float64) []float64 {
+				return []float64{
+					(x*x*x*x - 2*x*x*x + x) / 24,
+					(4*x*x*x - 6*x*x + 1) / 24,
+					(x*x - x) / 2,
+					x - 0.5,
+				}
+			},
+		},
+		{
+			// y'' = y - 2 cos(x) with periodic boundary conditions, which
+			// are not separated.
+			name: "periodic",
+			p: BVP{
+				Func: func(dy []float64, x float64, y []float64) {
+					dy[0] = y[1]
+					dy[1] = y[0] - 2*math.Cos(x)
+				},
+				BC: func(res, ya, yb []float64) {
+					res[0] = ya[0] - yb[0]
+					res[1] = ya[1] - yb[1]
+				},
+			},
+			a:     0,
+			b:     2 * math.Pi,
+			guess: []float64{0, 0},
+			want: func(x float64) []float64 {
+				return []float64{math.Cos(x), -math.Sin(x)}
+			},
+		},
+	}
+}
+
+// initialGuess returns a uniform mesh of m nodes on [a, b] and a constant
+// initial guess.
+func initialGuess(a, b float64, m int, guess []float64) ([]float64, *mat.Dense) {
+	x := make([]float64, m)
+	floats.Span(x, a, b)
+	y := mat.NewDense(m, len(guess), nil)
+	for i := 0; i < m; i++ {
+		y.SetRow(i, guess)
+	}
+	return x, y
+}
+
+func checkBVPResult(t *testing.T, name string, res *BVPResult, want func(float64) []float64, tol float64) {
+	t.Helper()
+	for i, x := range res.X {
+		if !floats.EqualApprox(res.Y.RawRowView(i), want(x), tol) {
+			t.Errorf("%s: mismatch at node x=%v: got %v, want %v", name, x, res.Y.RawRowView(i), want(x))
+			break
+		}
+	}
+	a, b := res.Solution.Interval()
+	if a != res.X[0] || b != res.X[len(res.X)-1] {
+		t.Errorf("%s: unexpected solution interval: [%v, %v]", name, a, b)
+	}
+	y := make([]float64, res.Y.RawMatrix().Cols)
+	for i := 0; i <= 50; i++ {
+		x := a + float64(i)/50*(b-a)
+		res.Solution.At(y, x)
+		if !floats.EqualApprox(y, want(x), tol) {
+			t.Errorf("%s: mismatch in solution at x=%v: got %v, want %v", name, x, y, want(x))
+			break
+		}
+	}
+}
+
+func TestSolveBVP(t *testing.T) {
+	t.Parallel()
+	for _, test := range bvpTests() {
+		for _, jac := range []bool{true, false} {
+			p := test.p
+			if !jac {
+				p.Jac = nil
+			}
+			x, y := initialGuess(test.a, test.b, 5, test.guess)
+			res, err := SolveBVP(p, x, y, &BVPSettings{Tol: 1e-6})
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+				continue
+			}
+			if res.Status != Success {
+				t.Errorf("%s: unexpected status: %v", test.name, res.Status)
+			}
+			for i, r := range res.Residuals {
+				if r > 1e-6 {
+					t.Errorf("%s: residual on interval %d exceeds tolerance: %v", test.name, i, r)
+					break
+				}
+			}
+			if len(res.Residuals) != len(res.X)-1 {
+				t.Errorf("%s: unexpected number of residuals: %d for %d nodes", test.name, len(res.Residuals), len(res.X))
+			}
+			if res.FuncEvaluations == 0 || res.JacEvaluations == 0 || res.Iterations == 0 {
+				t.Errorf("%s: unexpected counts: %+v", test.name, res)
+			}
+			checkBVPResult(t, test.name, res, test.want, 1e-5)
+		}
+	}
+}
+
+func TestSolveBVPNodeLimit(t *testing.T) {
+	t.Parallel()
+	test := bvpTests()[1]
+	x, y := initialGuess(test.a, test.b, 3, test.guess)
+	res, err := SolveBVP(test.p, x, y, &BVPSettings{Tol: 1e-12, MaxNodes: 10})
+	if err == nil {
+		t.Fatal("expected error for node limit")
+	}
+	if res.Status != NodeLimit {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, NodeLimit)
+	}
+	if len(res.X) > 10 {
+		t.Errorf("node limit exceeded: %d nodes", len(res.X))
+	}
+}
+
+func TestSolveBVPSingular(t *testing.T) {
+	t.Parallel()
+	// Giving the boundary condition y(0) = 0 twice leaves y'(0)
+	// undetermined.
+	p := bvpTests()[0].p
+	p.BC = func(res, ya, _ []float64) {
+		res[0] = ya[0]
+		res[1] = ya[0]
+	}
+	x, y := initialGuess(0, 1, 5, []float64{1, 1})
+	res, err := SolveBVP(p, x, y, nil)
+	if err == nil {
+		t.Fatal("expected error for singular Jacobian")
+	}
+	if res.Status != SingularJacobian {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, SingularJacobian)
+	}
+}
+
+func TestLinearSystemDense(t *testing.T) {
+	t.Parallel()
+	// A periodic boundary condition couples y(a) and y(b), so the system
+	// is factorized with mat.LU. Its determinant underflows although it
+	// is well-conditioned.
+	const m = 400
+	dya := mat.NewDense(1, 1, []float64{1})
+	dyb := mat.NewDense(1, 1, []float64{-1})
+	var s linearSystem
+	s.init(dya, dyb, 1, m)
+	if s.banded {
+		t.Fatal("unexpected banded system")
+	}
+	diag := mat.NewDense(1, 1, []float64{-0.02})
+	super := mat.NewDense(1, 1, []float64{0.01})
+	for i := 0; i < m; i++ {
+		s.setBlock(i, i, diag)
+		s.setBlock(i, i+1, super)
+	}
+	if !s.factorize() {
+		t.Fatalf("well-conditioned system reported as singular: cond=%v", s.lu.Cond())
+	}
+	want := make([]float64, m+1)
+	for i := range want {
+		want[i] = float64(i%3) - 1
+	}
+	b := make([]float64, m+1)
+	mat.NewVecDense(m+1, b).MulVec(s.dense, mat.NewVecDense(m+1, want))
+	if err := s.solve(b); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !floats.EqualApprox(b, want, 1e-12) {
+		t.Errorf("unexpected solution")
+	}
+
+	// With the collocation rows y_i - y_{i+1}, the boundary condition row
+	// is their sum and the system is singular.
+	s.init(dya, dyb, 1, m)
+	diag.Set(0, 0, 1)
+	super.Set(0, 0, -1)
+	for i := 0; i < m; i++ {
+		s.setBlock(i, i, diag)
+		s.setBlock(i, i+1, super)
+	}
+	if s.factorize() {
+		t.Errorf("singular system not detected: cond=%v", s.lu.Cond())
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import "sort"
+
+// Solution is the dense output of an integration, a piecewise polynomial
+// interpolant of the solution over the integration interval.
+type Solution struct {
+	t0, t1 float64
+	dim    int
+	steps  []segment
+}
+
+// segment is the interpolating polynomial of a single step,
+//  y(t0 + θh) = \sum_k θ^k coef[k].
+type segment struct {
+	t0, h float64
+	coef  [][]float64
+}
+
+// at stores the interpolated solution at t into dst.
+func (s *segment) at(dst []float64, t float64) {
+	theta := (t - s.t0) / s.h
+	k := len(s.coef) - 1
+	copy(dst, s.coef[k])
+	for k--; k >= 0; k-- {
+		for i, c := range s.coef[k] {
+			dst[i] = dst[i]*theta + c
+		}
+	}
+}
+
+// Interval returns the interval of integration of the solution, in the
+// direction of integration.
+func (s *Solution) Interval() (t0, t1 float64) {
+	return s.t0, s.t1
+}
+
+// At evaluates the solution at t and stores the result in dst, which is
+// returned. If dst is nil, a new slice is allocated. At panics if t is
+// outside the interval of integration or if dst is not nil and its length
+// is not equal to the dimension of the system.
+func (s *Solution) At(dst []float64, t float64) []float64 {
+	if dst == nil {
+		dst = make([]float64, s.dim)
+	}
+	if len(dst) != s.dim {
+		panic("ode: slice length mismatch")
+	}
+	lo, hi := s.t0, s.t1
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if t < lo || hi < t {
+		panic("ode: t outside interval of integration")
+	}
+	forward := s.t1 >= s.t0
+	// Find the first step that ends at or beyond t.
+	i := sort.Search(len(s.steps), func(i int) bool {
+		end := s.steps[i].t0 + s.steps[i].h
+		if forward {
+			return end >= t
+		}
+		return end <= t
+	})
+	if i == len(s.steps) {
+		i--
+	}
+	s.steps[i].at(dst, t)
+	return dst
+}
+
+// hermite stores into dst the coefficients of the cubic Hermite interpolant
+// of a step of size h from y0 to y1 with derivatives dy0 and dy1, and
+// returns it.
+func hermite(dst [][]float64, h float64, y0, dy0, y1, dy1 []float64) [][]float64 {
+	dst = reuseCoef(dst, 4, len(y0))
+	for i := range y0 {
+		delta := y1[i] - y0[i]
+		f0 := h * dy0[i]
+		f1 := h * dy1[i]
+		dst[0][i] = y0[i]
+		dst[1][i] = f0
+		dst[2][i] = 3*delta - 2*f0 - f1
+		dst[3][i] = f0 + f1 - 2*delta
+	}
+	return dst
+}
+
+// reuseCoef returns a k×n coefficient slice, reusing dst if possible.
+func reuseCoef(dst [][]float64, k, n int) [][]float64 {
+	if len(dst) != k {
+		dst = make([][]float64, k)
+	}
+	for i := range dst {
+		if len(dst[i]) != n {
+			dst[i] = make([]float64, n)
+		}
+	}
+	return dst
+}
+
+// derivative stores the derivative of the interpolant at t into dst.
+func (s *segment) derivative(dst []float64, t float64) {
+	theta := (t - s.t0) / s.h
+	k := len(s.coef) - 1
+	for i := range dst {
+		dst[i] = float64(k) * s.coef[k][i]
+	}
+	for k--; k >= 1; k-- {
+		for i, c := range s.coef[k] {
+			dst[i] = dst[i]*theta + float64(k)*c
+		}
+	}
+	for i := range dst {
+		dst[i] /= s.h
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ode provides methods for the numerical solution of initial value
+// problems for systems of ordinary differential equations
+//  dy/dt = f(t, y),  y(t0) = y0.
+//
+// Non-stiff problems are best solved with the adaptive explicit Runge–Kutta
+// methods DormandPrince5, Tsitouras5 and Verner6, while stiff problems should
+// be solved with the implicit Radau5 method, which uses the Jacobian of f.
+//
+// Two-point boundary value problems
+//  dy/dx = f(x, y),  bc(y(a), y(b)) = 0
+// are solved by collocation with mesh refinement using SolveBVP, or by
+// multiple shooting with any of the initial value methods using Shoot.
+package ode // import "gonum.org/v1/gonum/integrate/ode"
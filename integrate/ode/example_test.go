@@ -0,0 +1,81 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/integrate/ode"
+	"gonum.org/v1/gonum/mat"
+)
+
+func ExampleSolve() {
+	// Integrate the motion of a ball thrown upwards at 10 m/s
+	// until it hits the ground.
+	const g = 9.81
+	p := ode.Problem{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = y[1]
+			dy[1] = -g
+		},
+		Events: []ode.Event{
+			{
+				Func:      func(_ float64, y []float64) float64 { return y[0] },
+				Direction: -1,
+				Terminal:  true,
+			},
+		},
+	}
+	res, err := ode.Solve(p, 0, 10, []float64{0, 10}, &ode.Tsitouras5{}, &ode.Settings{
+		AbsTol: 1e-10,
+		RelTol: 1e-10,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("status:", res.Status)
+	ev := res.Events[0]
+	fmt.Printf("landed at t = %.6f with velocity %.6f\n", ev.T, ev.Y[1])
+	// Output:
+	// status: TerminalEvent
+	// landed at t = 2.038736 with velocity -10.000000
+}
+
+func ExampleSolveBVP() {
+	// Find the deflection of a simply supported beam of unit length and
+	// unit flexural rigidity under a uniform load, y'''' = 1 with
+	// y(0) = y''(0) = y(1) = y''(1) = 0.
+	p := ode.BVP{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = y[1]
+			dy[1] = y[2]
+			dy[2] = y[3]
+			dy[3] = 1
+		},
+		BC: func(res, ya, yb []float64) {
+			res[0] = ya[0]
+			res[1] = ya[2]
+			res[2] = yb[0]
+			res[3] = yb[2]
+		},
+	}
+	// Start from a zero initial guess on a coarse mesh.
+	x := []float64{0, 0.25, 0.5, 0.75, 1}
+	y := mat.NewDense(len(x), 4, nil)
+
+	res, err := ode.SolveBVP(p, x, y, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mid := make([]float64, 4)
+	res.Solution.At(mid, 0.5)
+	fmt.Printf("deflection at midpoint = %.6f\n", mid[0])
+	fmt.Printf("exact value = %.6f\n", 5.0/384)
+
+	// Output:
+	// deflection at midpoint = 0.013021
+	// exact value = 0.013021
+}
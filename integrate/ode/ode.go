@@ -0,0 +1,236 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultAbsTol   = 1e-6
+	defaultRelTol   = 1e-3
+	defaultMaxSteps = 100000
+
+	// Step size control parameters.
+	safety    = 0.9
+	minFactor = 0.2
+	maxFactor = 10
+
+	eps = 0x1p-52
+)
+
+// Problem describes an initial value problem for a system of ordinary
+// differential equations.
+type Problem struct {
+	// Func evaluates the right-hand side of the system
+	//  dy/dt = f(t, y)
+	// at t and y and stores the result in-place into dy. Func must not
+	// modify y. Func must not be nil.
+	Func func(dy []float64, t float64, y []float64)
+
+	// Jac evaluates the Jacobian matrix ∂f/∂y at t and y and stores the
+	// result in-place into the n×n matrix dst. Jac is used only by
+	// implicit methods. If Jac is nil, the Jacobian is approximated
+	// by forward finite differences using fd.Jacobian.
+	Jac func(dst *mat.Dense, t float64, y []float64)
+
+	// Events are the events to detect during the integration.
+	Events []Event
+}
+
+// Event describes an event that occurs when the event function crosses zero.
+type Event struct {
+	// Func is the event function. Func must not modify y.
+	Func func(t float64, y []float64) float64
+
+	// Direction restricts the zero crossings that are detected. If
+	// Direction is positive, only crossings where Func increases are
+	// detected, if it is negative only crossings where Func decreases,
+	// and if it is zero, all crossings are detected.
+	Direction int
+
+	// Terminal specifies whether the integration stops at the event.
+	Terminal bool
+}
+
+// EventRecord is an occurrence of an event.
+type EventRecord struct {
+	// Index is the index of the event in Problem.Events.
+	Index int
+	// T and Y are the location of the event.
+	T float64
+	Y []float64
+}
+
+// Settings holds the settings for an integration.
+type Settings struct {
+	// AbsTol and RelTol are the absolute and relative tolerances for the
+	// local error. A step is accepted if the root mean square of the
+	// local error estimates, each scaled by AbsTol + RelTol*|y_i|, is at
+	// most one. If both are zero, AbsTol is set to 1e-6 and RelTol to 1e-3.
+	AbsTol, RelTol float64
+
+	// InitialStep is the magnitude of the first step attempted. If
+	// InitialStep is zero, it is chosen automatically.
+	InitialStep float64
+
+	// MaxStep is the maximum step size magnitude. If MaxStep is zero,
+	// the step size is not bounded.
+	MaxStep float64
+
+	// MaxSteps is the maximum number of accepted and rejected steps.
+	// If MaxSteps is zero, a default of 1e5 is used.
+	MaxSteps int
+
+	// Output specifies the times at which the solution is reported in the
+	// Result. Output must be sorted in the direction of integration and
+	// lie within the integration interval. If Output is nil, the solution
+	// is reported at the end of every step.
+	Output []float64
+
+	// Dense specifies whether the dense output of every step is retained
+	// in the Solution field of the Result.
+	Dense bool
+
+	// Recorder, if not nil, records the progress of the integration
+	// after every accepted step.
+	Recorder Recorder
+}
+
+// Stats contains the statistics of an integration.
+type Stats struct {
+	Steps            int           // Number of accepted steps.
+	Rejected         int           // Number of rejected steps.
+	FuncEvaluations  int           // Number of evaluations of Problem.Func.
+	JacEvaluations   int           // Number of evaluations of the Jacobian.
+	LUDecompositions int           // Number of LU decompositions.
+	Runtime          time.Duration // Total runtime of the integration.
+}
+
+// Result holds the result of an integration.
+type Result struct {
+	// T holds the times at which the solution is reported.
+	T []float64
+	// Y holds the solution, with the ith row holding y(T[i]).
+	Y *mat.Dense
+	// Events holds the detected events in the order they occurred.
+	Events []EventRecord
+	// Solution is the dense output over the integration interval
+	// if Settings.Dense is true, and nil otherwise.
+	Solution *Solution
+
+	Stats
+	Status Status
+}
+
+// Method is a single-step method for integrating a system of ordinary
+// differential equations with local error estimation.
+type Method interface {
+	// Init initializes the method to integrate sys.
+	Init(sys *System)
+
+	// Order returns the order of the local error estimate, that is,
+	// the local error estimate is O(h^Order()).
+	Order() int
+
+	// Step attempts a step of size h from t, where y is the solution at
+	// t and dy holds f(t, y). It stores the solution at t+h into yNew,
+	// f(t+h, yNew) into dyNew and an estimate of the local error into
+	// errEst. Step returns false if the step could not be computed,
+	// for example if the nonlinear equations of an implicit method
+	// could not be solved, in which case the step is retried with a
+	// smaller step size.
+	Step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) (ok bool)
+
+	// DenseOutput stores the coefficients of the polynomial that
+	// interpolates the solution over the last successful step into dst
+	// and returns it. The solution at t+θh is
+	//  \sum_k θ^k dst[k].
+	// If dst is nil, a new slice is allocated.
+	DenseOutput(dst [][]float64) [][]float64
+}
+
+// System provides evaluations of the right-hand side of an ODE system and
+// its Jacobian to a Method, and counts the evaluations.
+type System struct {
+	problem Problem
+	dim     int
+	stats   *Stats
+
+	absTol, relTol float64
+}
+
+// Dim returns the number of equations in the system.
+func (s *System) Dim() int {
+	return s.dim
+}
+
+// Func evaluates f(t, y) and stores the result in dy.
+func (s *System) Func(dy []float64, t float64, y []float64) {
+	s.stats.FuncEvaluations++
+	s.problem.Func(dy, t, y)
+}
+
+// Jacobian evaluates the Jacobian ∂f/∂y at t and y, where dy holds f(t, y),
+// and stores the result in-place into the n×n matrix dst.
+func (s *System) Jacobian(dst *mat.Dense, t float64, y, dy []float64) {
+	s.stats.JacEvaluations++
+	if s.problem.Jac != nil {
+		s.problem.Jac(dst, t, y)
+		return
+	}
+	fd.Jacobian(dst, func(dy, y []float64) {
+		s.Func(dy, t, y)
+	}, y, &fd.JacobianSettings{OriginValue: dy})
+}
+
+// norm returns the root mean square norm of v scaled by the
+// tolerances at y0 and y1.
+func (s *System) norm(v, y0, y1 []float64) float64 {
+	var sum float64
+	for i, e := range v {
+		sc := s.absTol + s.relTol*math.Max(math.Abs(y0[i]), math.Abs(y1[i]))
+		e /= sc
+		sum += e * e
+	}
+	return math.Sqrt(sum / float64(len(v)))
+}
+
+// Solve integrates the initial value problem p from t0 to t1 starting at
+// y0 using the given method. t1 may be less than t0, in which case the
+// integration proceeds backwards. If method is nil, DormandPrince5 is used.
+// If settings is nil, the defaults described for Settings are used.
+//
+// The step size is adapted to keep the local error estimate within the
+// tolerances. At the end of each accepted step, the events in p are
+// checked for zero crossings, which are located by root finding on the
+// dense output.
+//
+// Solve returns the result of the integration and an error if the
+// integration failed to reach t1 for a reason other than a terminal event,
+// or if the recorder returned an error.
+func Solve(p Problem, t0, t1 float64, y0 []float64, method Method, settings *Settings) (*Result, error) {
+	if p.Func == nil {
+		panic("ode: nil function")
+	}
+	if len(y0) == 0 {
+		panic("ode: zero dimension")
+	}
+	if math.IsInf(t0, 0) || math.IsInf(t1, 0) || math.IsNaN(t0) || math.IsNaN(t1) {
+		panic("ode: non-finite integration bound")
+	}
+	if method == nil {
+		method = &DormandPrince5{}
+	}
+	if settings == nil {
+		settings = &Settings{}
+	}
+	s := newSolver(p, t0, t1, y0, method, settings)
+	return s.solve()
+}
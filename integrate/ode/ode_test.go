@@ -0,0 +1,314 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// oscillator is the harmonic oscillator y'' = -y with y(0) = 1, y'(0) = 0.
+var oscillator = Problem{
+	Func: func(dy []float64, _ float64, y []float64) {
+		dy[0] = y[1]
+		dy[1] = -y[0]
+	},
+	Jac: func(dst *mat.Dense, _ float64, _ []float64) {
+		dst.Set(0, 0, 0)
+		dst.Set(0, 1, 1)
+		dst.Set(1, 0, -1)
+		dst.Set(1, 1, 0)
+	},
+}
+
+func methods() []Method {
+	return []Method{&DormandPrince5{}, &Tsitouras5{}, &Verner6{}, &Radau5{}}
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+	for _, m := range methods() {
+		for _, test := range []struct {
+			t0, t1 float64
+		}{
+			{t0: 0, t1: 10},
+			{t0: 2, t1: -3},
+		} {
+			y0 := []float64{math.Cos(test.t0), -math.Sin(test.t0)}
+			res, err := Solve(oscillator, test.t0, test.t1, y0, m, &Settings{AbsTol: 1e-10, RelTol: 1e-10})
+			if err != nil {
+				t.Fatalf("%T: unexpected error: %v", m, err)
+			}
+			if res.Status != Success {
+				t.Errorf("%T: unexpected status: %v", m, res.Status)
+			}
+			if res.T[0] != test.t0 || res.T[len(res.T)-1] != test.t1 {
+				t.Errorf("%T: unexpected output interval: [%v, %v]", m, res.T[0], res.T[len(res.T)-1])
+			}
+			for i, ti := range res.T {
+				want := []float64{math.Cos(ti), -math.Sin(ti)}
+				if !floats.EqualApprox(res.Y.RawRowView(i), want, 1e-7) {
+					t.Errorf("%T: mismatch at t=%v: got %v, want %v", m, ti, res.Y.RawRowView(i), want)
+					break
+				}
+			}
+			if res.Steps != len(res.T)-1 {
+				t.Errorf("%T: step count mismatch: %d steps, %d outputs", m, res.Steps, len(res.T))
+			}
+		}
+	}
+}
+
+func TestSolveOutputAndDense(t *testing.T) {
+	t.Parallel()
+	decay := Problem{
+		Func: func(dy []float64, t float64, y []float64) {
+			dy[0] = -2 * y[0]
+			dy[1] = math.Cos(t)
+		},
+	}
+	want := func(t float64) []float64 { return []float64{math.Exp(-2 * t), math.Sin(t)} }
+	output := []float64{0, 0.25, 0.5, 1, 2.5, 3}
+	for _, m := range methods() {
+		res, err := Solve(decay, 0, 3, []float64{1, 0}, m, &Settings{
+			AbsTol: 1e-9,
+			RelTol: 1e-9,
+			Output: output,
+			Dense:  true,
+		})
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", m, err)
+		}
+		if !floats.Equal(res.T, output) {
+			t.Errorf("%T: unexpected output times: %v", m, res.T)
+		}
+		for i, ti := range res.T {
+			if !floats.EqualApprox(res.Y.RawRowView(i), want(ti), 1e-6) {
+				t.Errorf("%T: output mismatch at t=%v: got %v, want %v", m, ti, res.Y.RawRowView(i), want(ti))
+			}
+		}
+		t0, t1 := res.Solution.Interval()
+		if t0 != 0 || t1 != 3 {
+			t.Errorf("%T: unexpected dense interval: [%v, %v]", m, t0, t1)
+		}
+		for _, ti := range []float64{0, 0.1, 0.77, 1.234, 2.9999, 3} {
+			got := res.Solution.At(nil, ti)
+			if !floats.EqualApprox(got, want(ti), 1e-6) {
+				t.Errorf("%T: dense mismatch at t=%v: got %v, want %v", m, ti, got, want(ti))
+			}
+		}
+	}
+}
+
+func TestSolveDenseOutputAccuracy(t *testing.T) {
+	t.Parallel()
+	// The dense output of the explicit methods must be about as accurate
+	// as the solution at the ends of the steps.
+	output := make([]float64, 101)
+	for i := range output {
+		output[i] = float64(i) / 10
+	}
+	maxErr := func(res *Result) float64 {
+		var e float64
+		for i, ti := range res.T {
+			e = math.Max(e, math.Abs(res.Y.At(i, 0)-math.Cos(ti)))
+			e = math.Max(e, math.Abs(res.Y.At(i, 1)+math.Sin(ti)))
+		}
+		return e
+	}
+	for _, m := range []Method{&DormandPrince5{}, &Tsitouras5{}, &Verner6{}} {
+		for _, tol := range []float64{1e-9, 1e-12} {
+			res, err := Solve(oscillator, 0, 10, []float64{1, 0}, m, &Settings{AbsTol: tol, RelTol: tol})
+			if err != nil {
+				t.Fatalf("%T: unexpected error: %v", m, err)
+			}
+			stepErr := maxErr(res)
+			res, err = Solve(oscillator, 0, 10, []float64{1, 0}, m, &Settings{AbsTol: tol, RelTol: tol, Output: output})
+			if err != nil {
+				t.Fatalf("%T: unexpected error: %v", m, err)
+			}
+			if outErr := maxErr(res); outErr > 10*stepErr {
+				t.Errorf("%T: inaccurate dense output for tol=%v: error %v at output times, %v at step ends", m, tol, outErr, stepErr)
+			}
+		}
+	}
+}
+
+func TestSolveEvents(t *testing.T) {
+	t.Parallel()
+	// A ball thrown upwards at 10 m/s, falling under gravity.
+	const g = 9.81
+	ball := Problem{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = y[1]
+			dy[1] = -g
+		},
+		Events: []Event{
+			// Apex, where the velocity crosses zero downwards.
+			{Func: func(_ float64, y []float64) float64 { return y[1] }, Direction: -1},
+			// Height of 1, in either direction.
+			{Func: func(_ float64, y []float64) float64 { return y[0] - 1 }},
+			// Ground, stopping the integration.
+			{Func: func(_ float64, y []float64) float64 { return y[0] }, Direction: -1, Terminal: true},
+		},
+	}
+	apex := 10 / g
+	ground := 20 / g
+	d := math.Sqrt(100 - 2*g)
+	up, down := (10-d)/g, (10+d)/g
+	for _, m := range methods() {
+		res, err := Solve(ball, 0, 10, []float64{0, 10}, m, &Settings{AbsTol: 1e-10, RelTol: 1e-10})
+		if err != nil {
+			t.Fatalf("%T: unexpected error: %v", m, err)
+		}
+		if res.Status != TerminalEvent {
+			t.Errorf("%T: unexpected status: %v", m, res.Status)
+		}
+		want := []struct {
+			index int
+			t     float64
+		}{{1, up}, {0, apex}, {1, down}, {2, ground}}
+		if len(res.Events) != len(want) {
+			t.Fatalf("%T: unexpected number of events: got %d, want %d: %v", m, len(res.Events), len(want), res.Events)
+		}
+		for i, w := range want {
+			ev := res.Events[i]
+			if ev.Index != w.index || math.Abs(ev.T-w.t) > 1e-8 {
+				t.Errorf("%T: unexpected event %d: got index %d at %v, want index %d at %v", m, i, ev.Index, ev.T, w.index, w.t)
+			}
+		}
+		last := res.T[len(res.T)-1]
+		if math.Abs(last-ground) > 1e-8 {
+			t.Errorf("%T: integration did not stop at terminal event: %v", m, last)
+		}
+	}
+}
+
+func TestSolveStiff(t *testing.T) {
+	t.Parallel()
+	// The Robertson chemical kinetics problem.
+	robertson := Problem{
+		Func: func(dy []float64, _ float64, y []float64) {
+			dy[0] = -0.04*y[0] + 1e4*y[1]*y[2]
+			dy[2] = 3e7 * y[1] * y[1]
+			dy[1] = -dy[0] - dy[2]
+		},
+	}
+	settings := &Settings{AbsTol: 1e-8, RelTol: 1e-6}
+	res, err := Solve(robertson, 0, 40, []float64{1, 0, 0}, &Radau5{}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := res.Y.RawRowView(len(res.T) - 1)
+	// Reference solution at t = 40.
+	want := []float64{0.7158270687193941, 9.185534764557e-6, 0.2841637457458413}
+	for i, v := range got {
+		if !floats.EqualWithinRel(v, want[i], 1e-5) {
+			t.Errorf("unexpected solution: got %v, want %v", got, want)
+			break
+		}
+	}
+	if math.Abs(floats.Sum(got)-1) > 1e-8 {
+		t.Errorf("mass not conserved: %v", floats.Sum(got))
+	}
+	if res.Steps > 200 {
+		t.Errorf("too many steps for stiff solver: %d", res.Steps)
+	}
+	if res.JacEvaluations == 0 || res.LUDecompositions == 0 {
+		t.Errorf("unexpected stats: %+v", res.Stats)
+	}
+
+	// An explicit method needs many more steps.
+	res, err = Solve(robertson, 0, 40, []float64{1, 0, 0}, &DormandPrince5{}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Steps < 1000 {
+		t.Errorf("unexpectedly few steps for explicit solver: %d", res.Steps)
+	}
+}
+
+func TestSolveLimits(t *testing.T) {
+	t.Parallel()
+	res, err := Solve(oscillator, 0, 100, []float64{1, 0}, nil, &Settings{MaxSteps: 10})
+	if res.Status != StepLimit || err == nil {
+		t.Errorf("unexpected result with step limit: status %v, err %v", res.Status, err)
+	}
+
+	// y' = y^2 has a singularity at t = 1 for y(0) = 1.
+	blowup := Problem{Func: func(dy []float64, _ float64, y []float64) { dy[0] = y[0] * y[0] }}
+	res, err = Solve(blowup, 0, 2, []float64{1}, nil, nil)
+	if res.Status != StepSizeTooSmall || err == nil {
+		t.Errorf("unexpected result at singularity: status %v, err %v", res.Status, err)
+	}
+	if last := res.T[len(res.T)-1]; math.Abs(last-1) > 1e-3 {
+		t.Errorf("unexpected end of integration: %v", last)
+	}
+}
+
+type countRecorder struct {
+	inits, records int
+	lastT          float64
+	stop           int
+}
+
+var errStop = errors.New("stop")
+
+func (r *countRecorder) Init() error {
+	r.inits++
+	return nil
+}
+
+func (r *countRecorder) Record(t float64, _ []float64, _ *Stats) error {
+	r.records++
+	r.lastT = t
+	if r.records == r.stop {
+		return errStop
+	}
+	return nil
+}
+
+func TestSolveRecorder(t *testing.T) {
+	t.Parallel()
+	rec := &countRecorder{}
+	res, err := Solve(oscillator, 0, 5, []float64{1, 0}, nil, &Settings{Recorder: rec})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.inits != 1 || rec.records != res.Steps+1 || rec.lastT != 5 {
+		t.Errorf("unexpected recorder calls: %+v for %d steps", rec, res.Steps)
+	}
+
+	rec = &countRecorder{stop: 3}
+	res, err = Solve(oscillator, 0, 5, []float64{1, 0}, nil, &Settings{Recorder: rec})
+	if err != errStop {
+		t.Errorf("recorder error not returned: %v", err)
+	}
+	if res.Steps != 2 {
+		t.Errorf("integration not stopped by recorder: %d steps", res.Steps)
+	}
+}
+
+func TestJacobianFiniteDifference(t *testing.T) {
+	t.Parallel()
+	p := oscillator
+	p.Jac = nil
+	res, err := Solve(p, 0, 10, []float64{1, 0}, &Radau5{}, &Settings{AbsTol: 1e-10, RelTol: 1e-10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := res.Y.RawRowView(len(res.T) - 1)
+	want := []float64{math.Cos(10), -math.Sin(10)}
+	if !floats.EqualApprox(got, want, 1e-7) {
+		t.Errorf("unexpected solution: got %v, want %v", got, want)
+	}
+	if res.JacEvaluations == 0 {
+		t.Errorf("Jacobian not evaluated")
+	}
+}
@@ -0,0 +1,286 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Radau5 is the three-stage implicit Radau IIA method of order 5 for stiff
+// problems. The nonlinear stage equations are solved by simplified Newton
+// iterations using the Jacobian of the system, which is evaluated once per
+// step. The local error estimate is the embedded estimate of RADAU5, and
+// the dense output is the collocation polynomial.
+//
+// Reference:
+//  E. Hairer and G. Wanner, "Solving Ordinary Differential Equations II:
+//  Stiff and Differential-Algebraic Problems", Sec. IV.8, 2nd edition,
+//  Springer, 1996.
+type Radau5 struct {
+	sys *System
+	n   int
+
+	jac    *mat.Dense
+	jacT   float64
+	jacOK  bool
+	iter   *mat.Dense
+	iterLU mat.LU
+	errLU  mat.LU
+	errMat *mat.Dense
+
+	lastT  float64
+	called bool
+
+	z, dz, g []float64
+	f        [3][]float64
+	tmp      []float64
+	rhs, sol *mat.VecDense
+	rhsN     *mat.VecDense
+	solN     *mat.VecDense
+
+	y  []float64
+	vi *mat.Dense
+}
+
+// Radau IIA coefficients.
+var (
+	sqrt6  = math.Sqrt(6)
+	radauC = [3]float64{(4 - sqrt6) / 10, (4 + sqrt6) / 10, 1}
+	radauA = [3][3]float64{
+		{(88 - 7*sqrt6) / 360, (296 - 169*sqrt6) / 1800, (-2 + 3*sqrt6) / 225},
+		{(296 + 169*sqrt6) / 1800, (88 + 7*sqrt6) / 360, (-2 - 3*sqrt6) / 225},
+		{(16 - sqrt6) / 36, (16 + sqrt6) / 36, 1.0 / 9},
+	}
+	// Coefficients of the embedded error estimate.
+	radauD  = [3]float64{-(13 + 7*sqrt6) / 3, (-13 + 7*sqrt6) / 3, -1.0 / 3}
+	radauG0 = (6 + math.Cbrt(81) - math.Cbrt(9)) / 30
+)
+
+const (
+	radauMaxIter = 7
+	radauKappa   = 0.03
+)
+
+func (r *Radau5) Init(sys *System) {
+	n := sys.Dim()
+	r.sys = sys
+	r.n = n
+	r.jac = mat.NewDense(n, n, nil)
+	r.jacOK = false
+	r.called = false
+	r.iter = mat.NewDense(3*n, 3*n, nil)
+	r.errMat = mat.NewDense(n, n, nil)
+	r.z = make([]float64, 3*n)
+	r.dz = make([]float64, 3*n)
+	r.g = make([]float64, 3*n)
+	for i := range r.f {
+		r.f[i] = make([]float64, n)
+	}
+	r.tmp = make([]float64, n)
+	r.rhs = mat.NewVecDense(3*n, r.g)
+	r.sol = mat.NewVecDense(3*n, r.dz)
+	r.rhsN = mat.NewVecDense(n, nil)
+	r.solN = mat.NewVecDense(n, nil)
+
+	// The inverse of the matrix V_{ik} = c_i^(k+1) maps the stage
+	// increments to the coefficients of the collocation polynomial.
+	v := mat.NewDense(3, 3, nil)
+	for i, c := range radauC {
+		v.Set(i, 0, c)
+		v.Set(i, 1, c*c)
+		v.Set(i, 2, c*c*c)
+	}
+	r.vi = &mat.Dense{}
+	err := r.vi.Inverse(v)
+	if err != nil {
+		panic("ode: singular collocation matrix")
+	}
+}
+
+func (*Radau5) Order() int { return 4 }
+
+func (r *Radau5) Step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) bool {
+	n := r.n
+	sys := r.sys
+	retry := r.called && t == r.lastT
+	r.called = true
+	r.lastT = t
+
+	if !r.jacOK || r.jacT != t {
+		sys.Jacobian(r.jac, t, y, dy)
+		r.jacT = t
+		r.jacOK = true
+	}
+
+	// Form and factorize the iteration matrix I - h A⊗J.
+	for bi := 0; bi < 3; bi++ {
+		for bj := 0; bj < 3; bj++ {
+			a := h * radauA[bi][bj]
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					v := -a * r.jac.At(i, j)
+					if bi == bj && i == j {
+						v++
+					}
+					r.iter.Set(bi*n+i, bj*n+j, v)
+				}
+			}
+		}
+	}
+	r.iterLU.Factorize(r.iter)
+	sys.stats.LUDecompositions++
+	if r.iterLU.Det() == 0 {
+		return false
+	}
+
+	// Solve the stage equations
+	//  z_i = h \sum_j a_ij f(t + c_j h, y + z_j)
+	// by simplified Newton iteration.
+	for i := range r.z {
+		r.z[i] = 0
+	}
+	var prevNorm float64
+	converged := false
+	for k := 0; k < radauMaxIter; k++ {
+		for s := 0; s < 3; s++ {
+			zs := r.z[s*n : (s+1)*n]
+			for i := range r.tmp {
+				r.tmp[i] = y[i] + zs[i]
+			}
+			sys.Func(r.f[s], t+radauC[s]*h, r.tmp)
+		}
+		for s := 0; s < 3; s++ {
+			for i := 0; i < n; i++ {
+				v := r.z[s*n+i]
+				for j := 0; j < 3; j++ {
+					v -= h * radauA[s][j] * r.f[j][i]
+				}
+				r.g[s*n+i] = -v
+			}
+		}
+		if !solved(r.iterLU.SolveVecTo(r.sol, false, r.rhs)) {
+			return false
+		}
+		for i, v := range r.dz {
+			r.z[i] += v
+		}
+
+		norm := r.scaledNorm(r.dz, y)
+		if math.IsNaN(norm) || math.IsInf(norm, 0) {
+			return false
+		}
+		if norm == 0 {
+			converged = true
+			break
+		}
+		if k > 0 {
+			theta := norm / prevNorm
+			if theta >= 0.99 {
+				return false
+			}
+			if theta/(1-theta)*norm <= radauKappa {
+				converged = true
+				break
+			}
+		} else if norm <= 1e-3*radauKappa {
+			converged = true
+			break
+		}
+		prevNorm = norm
+	}
+	if !converged {
+		return false
+	}
+
+	z3 := r.z[2*n:]
+	for i := range yNew {
+		yNew[i] = y[i] + z3[i]
+	}
+	sys.Func(dyNew, t+h, yNew)
+
+	// Compute the error estimate
+	//  err = (I/(γ0 h) - J)^-1 (f(t, y) + \sum_i d_i z_i / h).
+	fac := 1 / (radauG0 * h)
+	r.errMat.Scale(-1, r.jac)
+	for i := 0; i < n; i++ {
+		r.errMat.Set(i, i, r.errMat.At(i, i)+fac)
+	}
+	r.errLU.Factorize(r.errMat)
+	sys.stats.LUDecompositions++
+	f2 := r.tmp
+	for i := 0; i < n; i++ {
+		f2[i] = (radauD[0]*r.z[i] + radauD[1]*r.z[n+i] + radauD[2]*r.z[2*n+i]) / h
+		r.rhsN.SetVec(i, dy[i]+f2[i])
+	}
+	if !solved(r.errLU.SolveVecTo(r.solN, false, r.rhsN)) {
+		return false
+	}
+	for i := range errEst {
+		errEst[i] = r.solN.AtVec(i)
+	}
+	if (retry || sys.stats.Steps == 0) && sys.norm(errEst, y, yNew) > 1 {
+		// Improve the estimate for stiff components on the first step
+		// and after rejected steps.
+		fy := make([]float64, n)
+		yErr := make([]float64, n)
+		for i := range yErr {
+			yErr[i] = y[i] + errEst[i]
+		}
+		sys.Func(fy, t, yErr)
+		for i := 0; i < n; i++ {
+			r.rhsN.SetVec(i, fy[i]+f2[i])
+		}
+		if !solved(r.errLU.SolveVecTo(r.solN, false, r.rhsN)) {
+			return false
+		}
+		for i := range errEst {
+			errEst[i] = r.solN.AtVec(i)
+		}
+	}
+
+	r.y = y
+	return true
+}
+
+// solved returns whether a linear solve returning err succeeded. Ill
+// conditioning is not treated as a failure since the Newton iterations
+// and error estimates are robust to inaccurate solutions.
+func solved(err error) bool {
+	if err == nil {
+		return true
+	}
+	_, ok := err.(mat.Condition)
+	return ok
+}
+
+// scaledNorm returns the root mean square norm of the stage increments v
+// scaled by the tolerances at y.
+func (r *Radau5) scaledNorm(v, y []float64) float64 {
+	var sum float64
+	for i, e := range v {
+		yi := y[i%r.n]
+		e /= r.sys.absTol + r.sys.relTol*math.Abs(yi)
+		sum += e * e
+	}
+	return math.Sqrt(sum / float64(len(v)))
+}
+
+func (r *Radau5) DenseOutput(dst [][]float64) [][]float64 {
+	n := r.n
+	dst = reuseCoef(dst, 4, n)
+	copy(dst[0], r.y)
+	for k := 0; k < 3; k++ {
+		for i := 0; i < n; i++ {
+			var v float64
+			for s := 0; s < 3; s++ {
+				v += r.vi.At(k, s) * r.z[s*n+i]
+			}
+			dst[k+1][i] = v
+		}
+	}
+	return dst
+}
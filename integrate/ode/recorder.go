@@ -0,0 +1,88 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// Recorder records the progress of an integration. Init is called before
+// the integration starts, and Record is called at the initial point and
+// after every accepted step with the current time and solution. If Init
+// or Record return an error, the integration is stopped and the error
+// is returned by Solve. Record must not modify y.
+type Recorder interface {
+	Init() error
+	Record(t float64, y []float64, stats *Stats) error
+}
+
+var printerHeadings = [...]string{
+	"Step",
+	"Runtime",
+	"FuncEvals",
+	"T",
+	"|Y|∞",
+}
+
+const printerTmpl = "%9v  %16v  %9v  %22v  %22v"
+
+var _ Recorder = (*Printer)(nil)
+
+// Printer writes column-format output to the specified writer as the
+// integration progresses. By default, it writes to os.Stdout.
+type Printer struct {
+	Writer          io.Writer
+	HeadingInterval int
+	ValueInterval   time.Duration
+
+	start       time.Time
+	lastHeading int
+	lastValue   time.Time
+}
+
+func NewPrinter() *Printer {
+	return &Printer{
+		Writer:          os.Stdout,
+		HeadingInterval: 30,
+		ValueInterval:   500 * time.Millisecond,
+	}
+}
+
+func (p *Printer) Init() error {
+	p.start = time.Now()
+	p.lastHeading = p.HeadingInterval              // So the headings are printed the first time.
+	p.lastValue = time.Now().Add(-p.ValueInterval) // So the values are printed the first time.
+	return nil
+}
+
+func (p *Printer) Record(t float64, y []float64, stats *Stats) error {
+	if time.Since(p.lastValue) <= p.ValueInterval {
+		return nil
+	}
+
+	if p.lastHeading >= p.HeadingInterval {
+		p.lastHeading = 1
+		headings := "\n" + fmt.Sprintf(printerTmpl, printerHeadings[0], printerHeadings[1], printerHeadings[2], printerHeadings[3], printerHeadings[4])
+		_, err := fmt.Fprintln(p.Writer, headings)
+		if err != nil {
+			return err
+		}
+	} else {
+		p.lastHeading++
+	}
+
+	_, err := fmt.Fprintln(p.Writer, fmt.Sprintf(printerTmpl, stats.Steps, time.Since(p.start), stats.FuncEvaluations, t, floats.Norm(y, math.Inf(1))))
+	if err != nil {
+		return err
+	}
+	p.lastValue = time.Now()
+	return nil
+}
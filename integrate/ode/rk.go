@@ -0,0 +1,354 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+// tableau is the Butcher tableau of an embedded explicit Runge–Kutta pair.
+type tableau struct {
+	c []float64
+	a [][]float64
+	b []float64
+	// e holds the weights of the local error estimate, the difference
+	// between the weights of the propagating and embedded methods.
+	e []float64
+	// fsal indicates that the last stage is evaluated at the
+	// new solution, so it provides f(t+h, yNew).
+	fsal bool
+	// order is the order of the local error estimate.
+	order int
+}
+
+// explicitRK implements an embedded explicit Runge–Kutta method.
+type explicitRK struct {
+	tab *tableau
+	sys *System
+
+	k    [][]float64
+	tmp  []float64
+	t, h float64
+	y    []float64
+	dy   []float64
+	yNew []float64
+	dyN  []float64
+}
+
+func (r *explicitRK) init(sys *System, tab *tableau) {
+	r.tab = tab
+	r.sys = sys
+	n := sys.Dim()
+	r.k = make([][]float64, len(tab.c))
+	for i := range r.k {
+		r.k[i] = make([]float64, n)
+	}
+	r.tmp = make([]float64, n)
+}
+
+func (r *explicitRK) step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) bool {
+	tab := r.tab
+	k := r.k
+	copy(k[0], dy)
+	s := len(tab.c)
+	if tab.fsal {
+		s--
+	}
+	for i := 1; i < s; i++ {
+		copy(r.tmp, y)
+		for j, a := range tab.a[i] {
+			if a == 0 {
+				continue
+			}
+			for l, v := range k[j] {
+				r.tmp[l] += h * a * v
+			}
+		}
+		r.sys.Func(k[i], t+tab.c[i]*h, r.tmp)
+	}
+	copy(yNew, y)
+	for j, b := range tab.b {
+		if b == 0 {
+			continue
+		}
+		for l, v := range k[j] {
+			yNew[l] += h * b * v
+		}
+	}
+	if tab.fsal {
+		r.sys.Func(k[s], t+h, yNew)
+		copy(dyNew, k[s])
+	} else {
+		r.sys.Func(dyNew, t+h, yNew)
+	}
+	for l := range errEst {
+		errEst[l] = 0
+	}
+	for j, e := range tab.e {
+		if e == 0 {
+			continue
+		}
+		for l, v := range k[j] {
+			errEst[l] += h * e * v
+		}
+	}
+	r.t, r.h, r.y, r.dy, r.yNew, r.dyN = t, h, y, dy, yNew, dyNew
+	return true
+}
+
+// continuousExtension stores into dst the coefficients of the continuous
+// extension
+//  y(θ) = y + h \sum_j b_j(θ) k_j
+// of a step of size h from y with the stages k, where
+//  b_j(θ) = \sum_p w[j][p-1] θ^p,
+// and returns it.
+func continuousExtension(dst [][]float64, h float64, y []float64, k, w [][]float64) [][]float64 {
+	deg := len(w[0])
+	dst = reuseCoef(dst, deg+1, len(y))
+	copy(dst[0], y)
+	for p := 1; p <= deg; p++ {
+		c := dst[p]
+		for i := range c {
+			c[i] = 0
+		}
+		for j, kj := range k {
+			b := h * w[j][p-1]
+			if b == 0 {
+				continue
+			}
+			for i, v := range kj {
+				c[i] += b * v
+			}
+		}
+	}
+	return dst
+}
+
+// DormandPrince5 is the Dormand–Prince 5(4) explicit Runge–Kutta method
+// with local extrapolation. Its dense output is the fourth order continuous
+// extension of Shampine.
+//
+// References:
+//  J. R. Dormand and P. J. Prince, "A family of embedded Runge-Kutta
+//  formulae", J. Comp. Appl. Math. 6(1):19-26, 1980.
+//  L. F. Shampine, "Some practical Runge-Kutta formulas", Math. Comp.
+//  46(173):135-150, 1986.
+type DormandPrince5 struct {
+	rk explicitRK
+}
+
+func (d *DormandPrince5) Init(sys *System) { d.rk.init(sys, dormandPrince5) }
+
+func (*DormandPrince5) Order() int { return dormandPrince5.order }
+
+func (d *DormandPrince5) Step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) bool {
+	return d.rk.step(yNew, dyNew, errEst, t, h, y, dy)
+}
+
+func (d *DormandPrince5) DenseOutput(dst [][]float64) [][]float64 {
+	// The continuous extension in the form used by DOPRI5,
+	//  y(θ) = r1 + θ(r2 + (1-θ)(r3 + θ(r4 + (1-θ)r5))),
+	// expanded in powers of θ.
+	r := &d.rk
+	dst = reuseCoef(dst, 5, len(r.y))
+	k := r.k
+	h := r.h
+	for i := range r.y {
+		r2 := r.yNew[i] - r.y[i]
+		r3 := h*k[0][i] - r2
+		r4 := r2 - h*k[6][i] - r3
+		r5 := h * (dp5Dense[0]*k[0][i] + dp5Dense[2]*k[2][i] + dp5Dense[3]*k[3][i] +
+			dp5Dense[4]*k[4][i] + dp5Dense[5]*k[5][i] + dp5Dense[6]*k[6][i])
+		dst[0][i] = r.y[i]
+		dst[1][i] = r2 + r3
+		dst[2][i] = r4 + r5 - r3
+		dst[3][i] = -(r4 + 2*r5)
+		dst[4][i] = r5
+	}
+	return dst
+}
+
+var dormandPrince5 = &tableau{
+	c: []float64{0, 1.0 / 5, 3.0 / 10, 4.0 / 5, 8.0 / 9, 1, 1},
+	a: [][]float64{
+		{},
+		{1.0 / 5},
+		{3.0 / 40, 9.0 / 40},
+		{44.0 / 45, -56.0 / 15, 32.0 / 9},
+		{19372.0 / 6561, -25360.0 / 2187, 64448.0 / 6561, -212.0 / 729},
+		{9017.0 / 3168, -355.0 / 33, 46732.0 / 5247, 49.0 / 176, -5103.0 / 18656},
+		{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	},
+	b: []float64{35.0 / 384, 0, 500.0 / 1113, 125.0 / 192, -2187.0 / 6784, 11.0 / 84},
+	e: []float64{71.0 / 57600, 0, -71.0 / 16695, 71.0 / 1920, -17253.0 / 339200, 22.0 / 525, -1.0 / 40},
+
+	fsal:  true,
+	order: 5,
+}
+
+var dp5Dense = [7]float64{
+	-12715105075.0 / 11282082432,
+	0,
+	87487479700.0 / 32700410799,
+	-10690763975.0 / 1880347072,
+	701980252875.0 / 199316789632,
+	-1453857185.0 / 822651844,
+	69997945.0 / 29380423,
+}
+
+// Tsitouras5 is the Tsitouras 5(4) explicit Runge–Kutta method with local
+// extrapolation. It is generally more efficient than DormandPrince5. Its
+// dense output is the fourth order continuous extension given with the
+// method, which needs no additional function evaluations.
+//
+// Reference:
+//  Ch. Tsitouras, "Runge–Kutta pairs of order 5(4) satisfying only the first
+//  column simplifying assumption", Comput. Math. Appl. 62(2):770-775, 2011.
+type Tsitouras5 struct {
+	rk explicitRK
+}
+
+func (m *Tsitouras5) Init(sys *System) { m.rk.init(sys, tsitouras5) }
+
+func (*Tsitouras5) Order() int { return tsitouras5.order }
+
+func (m *Tsitouras5) Step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) bool {
+	return m.rk.step(yNew, dyNew, errEst, t, h, y, dy)
+}
+
+func (m *Tsitouras5) DenseOutput(dst [][]float64) [][]float64 {
+	r := &m.rk
+	return continuousExtension(dst, r.h, r.y, r.k, tsit5Dense)
+}
+
+var tsitouras5 = &tableau{
+	c: []float64{0, 0.161, 0.327, 0.9, 0.9800255409045097, 1, 1},
+	a: [][]float64{
+		{},
+		{0.161},
+		{-0.008480655492356989, 0.335480655492357},
+		{2.897153057105493, -6.359448489975075, 4.3622954328695815},
+		{5.325864828439257, -11.748883564062828, 7.4955393428898365, -0.09249506636175525},
+		{5.86145544294642, -12.92096931784711, 8.159367898576159, -0.071584973281401, -0.028269050394068383},
+		{0.09646076681806523, 0.01, 0.4798896504144996, 1.379008574103742, -3.290069515436081, 2.324710524099774},
+	},
+	b: []float64{0.09646076681806523, 0.01, 0.4798896504144996, 1.379008574103742, -3.290069515436081, 2.324710524099774},
+	e: []float64{
+		-0.00178001105222577714,
+		-0.0008164344596567469,
+		0.007880878010261995,
+		-0.1447110071732629,
+		0.5823571654525552,
+		-0.45808210592918697,
+		1.0 / 66,
+	},
+
+	fsal:  true,
+	order: 5,
+}
+
+// tsit5Dense holds the coefficients of θ, θ², θ³ and θ⁴ in the weights
+// b_j(θ) of the continuous extension of Tsitouras5.
+var tsit5Dense = [][]float64{
+	{1, -2.763706197274826, 2.9132554618219126, -1.0530884977290216},
+	{0, 0.13169999999999998, -0.2234, 0.1017},
+	{0, 3.9302962368947516, -5.941033872131505, 2.490627285651253},
+	{0, -12.411077166933676, 30.33818863028232, -16.548102889244902},
+	{0, 37.50931341651104, -88.1789048947664, 47.37952196281928},
+	{0, -27.896526289197286, 65.09189467479366, -34.87065786149661},
+	{0, 1.5, -4, 2.5},
+}
+
+// Verner6 is the Verner 6(5) explicit Runge–Kutta method with local
+// extrapolation. It is more efficient than the fifth order methods for
+// tight tolerances. Its dense output is a fifth order continuous extension
+// that uses f(t+h, y(t+h)) and one additional stage at t+h/2, so it costs
+// one function evaluation for every step whose dense output is used. The
+// additional stage and the weights of the extension satisfy the order
+// conditions as described by Enright et al.
+//
+// References:
+//  J. H. Verner, "Explicit Runge-Kutta methods with estimates of the local
+//  truncation error", SIAM J. Numer. Anal. 15(4):772-790, 1978.
+//  W. H. Enright, K. R. Jackson, S. P. Nørsett and P. G. Thomsen,
+//  "Interpolants for Runge-Kutta formulas", ACM Trans. Math. Softw.
+//  12(3):193-218, 1986.
+type Verner6 struct {
+	rk explicitRK
+
+	// stages holds the stages of the step followed by f(t+h, y(t+h))
+	// and the additional stage of the dense output.
+	stages [][]float64
+}
+
+func (v *Verner6) Init(sys *System) {
+	v.rk.init(sys, verner6)
+	v.stages = append(v.rk.k, nil, make([]float64, sys.Dim()))
+}
+
+func (*Verner6) Order() int { return verner6.order }
+
+func (v *Verner6) Step(yNew, dyNew, errEst []float64, t, h float64, y, dy []float64) bool {
+	return v.rk.step(yNew, dyNew, errEst, t, h, y, dy)
+}
+
+func (v *Verner6) DenseOutput(dst [][]float64) [][]float64 {
+	r := &v.rk
+	v.stages[len(r.k)] = r.dyN
+	copy(r.tmp, r.y)
+	for j, a := range verner6Extra {
+		if a == 0 {
+			continue
+		}
+		for i, k := range v.stages[j] {
+			r.tmp[i] += r.h * a * k
+		}
+	}
+	r.sys.Func(v.stages[len(v.stages)-1], r.t+r.h/2, r.tmp)
+	return continuousExtension(dst, r.h, r.y, v.stages, verner6Dense)
+}
+
+var verner6 = &tableau{
+	c: []float64{0, 1.0 / 6, 4.0 / 15, 2.0 / 3, 5.0 / 6, 1, 1.0 / 15, 1},
+	a: [][]float64{
+		{},
+		{1.0 / 6},
+		{4.0 / 75, 16.0 / 75},
+		{5.0 / 6, -8.0 / 3, 5.0 / 2},
+		{-165.0 / 64, 55.0 / 6, -425.0 / 64, 85.0 / 96},
+		{12.0 / 5, -8, 4015.0 / 612, -11.0 / 36, 88.0 / 255},
+		{-8263.0 / 15000, 124.0 / 75, -643.0 / 680, -81.0 / 250, 2484.0 / 10625, 0},
+		{3501.0 / 1720, -300.0 / 43, 297275.0 / 52632, -319.0 / 2322, 24068.0 / 84065, 0, 3850.0 / 26703},
+	},
+	b: []float64{3.0 / 40, 0, 875.0 / 2244, 23.0 / 72, 264.0 / 1955, 0, 125.0 / 11592, 43.0 / 616},
+	e: []float64{
+		3.0/40 - 13.0/160,
+		0,
+		875.0/2244 - 2375.0/5984,
+		23.0/72 - 5.0/16,
+		264.0/1955 - 12.0/85,
+		-3.0 / 44,
+		125.0 / 11592,
+		43.0 / 616,
+	},
+
+	order: 6,
+}
+
+// verner6Extra is the row of the Runge–Kutta matrix of the additional
+// stage at c = 1/2 of the dense output of Verner6. The stage has stage
+// order five.
+var verner6Extra = []float64{189.0 / 2560, 0, 6125.0 / 16896, 145.0 / 2304, -9.0 / 460, -21.0 / 704, 125.0 / 6624, 0, 1.0 / 32}
+
+// verner6Dense holds the coefficients of θ, ..., θ⁵ in the weights b_j(θ)
+// of the continuous extension of Verner6.
+var verner6Dense = [][]float64{
+	{79.0 / 80, -679.0 / 160, 63.0 / 8, -211.0 / 32, 41.0 / 20},
+	{0, 0, 0, 0, 0},
+	{-125.0 / 8976, 51375.0 / 5984, -6625.0 / 264, 474625.0 / 17952, -7125.0 / 748},
+	{1.0 / 72, 51.0 / 16, -455.0 / 36, 2485.0 / 144, -15.0 / 2},
+	{-24.0 / 1955, 1332.0 / 1955, -96.0 / 23, 2748.0 / 391, -288.0 / 85},
+	{-3.0 / 22, 3.0 / 44, -15.0 / 11, 135.0 / 44, -18.0 / 11},
+	{125.0 / 5796, 375.0 / 1288, -125.0 / 207, 125.0 / 414, 0},
+	{43.0 / 308, -43.0 / 616, 0, 0, 0},
+	{0, -1.0 / 2, 4, -15.0 / 2, 4},
+	{0, -8, 32, -40, 16},
+}
@@ -0,0 +1,278 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// Shoot solves the two-point boundary value problem p by multiple shooting.
+// The interval is divided into segments by the shooting nodes x, which must
+// be strictly increasing with at least two nodes, and the rows of y hold an
+// initial guess for the solution at the nodes. The unknowns are the values
+// s_k of the solution at the nodes, which are found by damped Newton
+// iterations on the continuity conditions
+//  φ_k(x_{k+1}; s_k) - s_{k+1} = 0,  k = 0, …, K-2,
+// and the boundary conditions bc(s_0, s_{K-1}) = 0, where φ_k is the
+// solution of the initial value problem on the kth segment computed with
+// method. The sensitivities of φ_k are found by integrating the variational
+// equations alongside the solution, and the Jacobian of the continuity and
+// boundary conditions is factorized with mat.LU. Simple shooting is the
+// special case of two nodes.
+//
+// The Output and Dense fields of settings.IVP are ignored. If method is nil,
+// DormandPrince5 is used. If settings is nil, the defaults described for
+// BVPSettings are used; the MaxNodes field is not used. Shoot returns the
+// result and an error if the iterations did not converge or an integration
+// failed.
+func Shoot(p BVP, x []float64, y mat.Matrix, method Method, settings *BVPSettings) (*BVPResult, error) {
+	n := checkBVP(p, x, y)
+	tol, bcTol, _, maxIter := settings.tolerances(defaultShootMaxIter)
+	if method == nil {
+		method = &DormandPrince5{}
+	}
+	var ivp Settings
+	if settings != nil && settings.IVP != nil {
+		ivp = *settings.IVP
+	} else {
+		ivp.AbsTol = 1e-3 * tol
+		ivp.RelTol = 1e-3 * tol
+	}
+	ivp.Dense = false
+
+	sh := &shooting{p: p, n: n, x: x, method: method, settings: ivp}
+	k := len(x)
+	size := k * n
+	s := make([]float64, size)
+	for i := 0; i < k; i++ {
+		for j := 0; j < n; j++ {
+			s[i*n+j] = y.At(i, j)
+		}
+	}
+
+	jac := mat.NewDense(size, size, nil)
+	res := make([]float64, size)
+	resNew := make([]float64, size)
+	sNew := make([]float64, size)
+	step := mat.NewVecDense(size, nil)
+	var lu mat.LU
+
+	var iter int
+	status, err := sh.residuals(res, jac, s)
+	if err != nil {
+		return sh.result(s, iter, status, err)
+	}
+	status = NoConvergence
+	for iter < maxIter {
+		if sh.converged(res, tol, bcTol) {
+			status = Success
+			break
+		}
+		iter++
+		lu.Factorize(jac)
+		if lu.Det() == 0 {
+			status = SingularJacobian
+			break
+		}
+		if !solved(lu.SolveVecTo(step, false, mat.NewVecDense(size, res))) {
+			status = SingularJacobian
+			break
+		}
+
+		// Backtrack until the residual norm decreases sufficiently.
+		cost := floats.Dot(res, res)
+		alpha := 1.0
+		for trial := 0; ; trial++ {
+			floats.AddScaledTo(sNew, s, -alpha, step.RawVector().Data)
+			status, err = sh.residuals(resNew, jac, sNew)
+			if err == nil && floats.Dot(resNew, resNew) <= (1-2*alpha*armijo)*cost {
+				break
+			}
+			if trial == 2*maxTrials {
+				if err != nil {
+					return sh.result(s, iter, status, err)
+				}
+				break
+			}
+			alpha *= backtrack
+		}
+		copy(s, sNew)
+		copy(res, resNew)
+		status = NoConvergence
+	}
+	return sh.result(s, iter, status, status.Err())
+}
+
+// shooting holds the state of the multiple shooting solver.
+type shooting struct {
+	p        BVP
+	n        int
+	x        []float64
+	method   Method
+	settings Settings
+
+	evals, jacEvals int
+}
+
+// converged returns whether the continuity residuals are within tol and
+// the boundary condition residuals within bcTol.
+func (sh *shooting) converged(res []float64, tol, bcTol float64) bool {
+	m := len(res) - sh.n
+	return floats.Norm(res[:m], math.Inf(1)) <= tol &&
+		floats.Norm(res[m:], math.Inf(1)) <= bcTol
+}
+
+// residuals computes the continuity and boundary condition residuals and
+// their Jacobian at the node values s.
+func (sh *shooting) residuals(res []float64, jac *mat.Dense, s []float64) (Status, error) {
+	n := sh.n
+	k := len(sh.x)
+	jac.Zero()
+	for i := 0; i < k-1; i++ {
+		phi, sens, status, err := sh.integrate(sh.x[i], sh.x[i+1], s[i*n:(i+1)*n])
+		if err != nil {
+			return status, err
+		}
+		for j := 0; j < n; j++ {
+			res[i*n+j] = phi[j] - s[(i+1)*n+j]
+			jac.Set(i*n+j, (i+1)*n+j, -1)
+		}
+		jac.Slice(i*n, (i+1)*n, i*n, (i+1)*n).(*mat.Dense).Copy(sens)
+	}
+
+	m := (k - 1) * n
+	ya := s[:n]
+	yb := s[m:]
+	bc := res[m:]
+	sh.p.BC(bc, ya, yb)
+	dya := jac.Slice(m, m+n, 0, n).(*mat.Dense)
+	dyb := jac.Slice(m, m+n, m, m+n).(*mat.Dense)
+	if k == 2 {
+		// The Jacobians with respect to ya and yb are summed into the
+		// blocks after evaluation when there is a single segment.
+		dya = mat.NewDense(n, n, nil)
+		dyb = mat.NewDense(n, n, nil)
+	}
+	sh.jacEvals++
+	if sh.p.BCJac != nil {
+		sh.p.BCJac(dya, dyb, ya, yb)
+	} else {
+		bcJacobian(sh.p.BC, dya, dyb, ya, yb, bc)
+	}
+	if k == 2 {
+		jac.Slice(m, m+n, 0, n).(*mat.Dense).Copy(dya)
+		jac.Slice(m, m+n, m, m+n).(*mat.Dense).Copy(dyb)
+	}
+	return NotTerminated, nil
+}
+
+// integrate solves the initial value problem y(x0) = y0 together with the
+// variational equations and returns y(x1) and ∂y(x1)/∂y0.
+func (sh *shooting) integrate(x0, x1 float64, y0 []float64) (phi []float64, sens *mat.Dense, status Status, err error) {
+	n := sh.n
+	jy := mat.NewDense(n, n, nil)
+	dy := make([]float64, n)
+	prob := Problem{
+		Func: func(dz []float64, x float64, z []float64) {
+			y := z[:n]
+			sh.evals++
+			sh.p.Func(dz[:n], x, y)
+			sh.jac(jy, x, y, dz[:n])
+			// d/dx ∂y/∂y0 = J ∂y/∂y0.
+			dphi := mat.NewDense(n, n, dz[n:])
+			dphi.Mul(jy, mat.NewDense(n, n, z[n:]))
+		},
+		Jac: func(dst *mat.Dense, x float64, z []float64) {
+			// The dependence of J on y is neglected in the rows of the
+			// sensitivities, which is sufficient for the Newton iterations
+			// of implicit methods.
+			y := z[:n]
+			sh.evals++
+			sh.p.Func(dy, x, y)
+			sh.jac(jy, x, y, dy)
+			dst.Zero()
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					v := jy.At(i, j)
+					dst.Set(i, j, v)
+					// ∂(JΦ)_{ik}/∂Φ_{jk} = J_{ij}.
+					for k := 0; k < n; k++ {
+						dst.Set(n+i*n+k, n+j*n+k, v)
+					}
+				}
+			}
+		},
+	}
+
+	z0 := make([]float64, n*(n+1))
+	copy(z0, y0)
+	for i := 0; i < n; i++ {
+		z0[n+i*n+i] = 1
+	}
+	settings := sh.settings
+	settings.Output = nil
+	r, err := Solve(prob, x0, x1, z0, sh.method, &settings)
+	if err != nil {
+		return nil, nil, r.Status, err
+	}
+	z := r.Y.RawRowView(r.Y.RawMatrix().Rows - 1)
+	phi = append([]float64(nil), z[:n]...)
+	sens = mat.NewDense(n, n, append([]float64(nil), z[n:]...))
+	return phi, sens, r.Status, nil
+}
+
+// jac evaluates the Jacobian of the right-hand side at x and y, where
+// f(x, y) = dy.
+func (sh *shooting) jac(dst *mat.Dense, x float64, y, dy []float64) {
+	sh.jacEvals++
+	if sh.p.Jac != nil {
+		sh.p.Jac(dst, x, y)
+		return
+	}
+	fd.Jacobian(dst, func(dy, y []float64) {
+		sh.evals++
+		sh.p.Func(dy, x, y)
+	}, y, &fd.JacobianSettings{OriginValue: dy})
+}
+
+// result returns the result for the node values s, with the continuous
+// solution formed from the dense output of each segment.
+func (sh *shooting) result(s []float64, iter int, status Status, err error) (*BVPResult, error) {
+	n := sh.n
+	k := len(sh.x)
+	res := &BVPResult{
+		X:          append([]float64(nil), sh.x...),
+		Y:          mat.NewDense(k, n, append([]float64(nil), s...)),
+		Iterations: iter,
+		Status:     status,
+	}
+	if status == Success {
+		sol := &Solution{t0: sh.x[0], t1: sh.x[k-1], dim: n}
+		settings := sh.settings
+		settings.Output = nil
+		settings.Dense = true
+		for i := 0; i < k-1; i++ {
+			r, err := Solve(Problem{Func: func(dy []float64, x float64, y []float64) {
+				sh.evals++
+				sh.p.Func(dy, x, y)
+			}, Jac: sh.p.Jac}, sh.x[i], sh.x[i+1], s[i*n:(i+1)*n], sh.method, &settings)
+			if err != nil {
+				res.Status = r.Status
+				res.FuncEvaluations = sh.evals
+				res.JacEvaluations = sh.jacEvals
+				return res, err
+			}
+			sol.steps = append(sol.steps, r.Solution.steps...)
+		}
+		res.Solution = sol
+	}
+	res.FuncEvaluations = sh.evals
+	res.JacEvaluations = sh.jacEvals
+	return res, err
+}
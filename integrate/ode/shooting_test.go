@@ -0,0 +1,54 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"testing"
+)
+
+func TestShoot(t *testing.T) {
+	t.Parallel()
+	for _, test := range bvpTests() {
+		for _, nodes := range []int{2, 4} {
+			for _, m := range []Method{&DormandPrince5{}, &Radau5{}} {
+				x, y := initialGuess(test.a, test.b, nodes, test.guess)
+				res, err := Shoot(test.p, x, y, m, &BVPSettings{Tol: 1e-8})
+				if err != nil {
+					t.Errorf("%s with %d nodes and %T: unexpected error: %v", test.name, nodes, m, err)
+					continue
+				}
+				if res.Status != Success {
+					t.Errorf("%s with %d nodes and %T: unexpected status: %v", test.name, nodes, m, res.Status)
+				}
+				if res.Residuals != nil {
+					t.Errorf("%s with %d nodes and %T: unexpected residuals", test.name, nodes, m)
+				}
+				if len(res.X) != nodes {
+					t.Errorf("%s with %d nodes and %T: unexpected number of nodes: %d", test.name, nodes, m, len(res.X))
+				}
+				checkBVPResult(t, test.name, res, test.want, 1e-6)
+			}
+		}
+	}
+}
+
+func TestShootNoConvergence(t *testing.T) {
+	t.Parallel()
+	test := bvpTests()[1]
+	x, y := initialGuess(test.a, test.b, 3, test.guess)
+	res, err := Shoot(test.p, x, y, nil, &BVPSettings{Tol: 1e-8, MaxIterations: 1})
+	if err == nil {
+		t.Fatal("expected error for iteration limit")
+	}
+	if res.Status != NoConvergence {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, NoConvergence)
+	}
+	if res.Iterations != 1 {
+		t.Errorf("unexpected number of iterations: %d", res.Iterations)
+	}
+	if res.Solution != nil {
+		t.Errorf("unexpected solution for unconverged result")
+	}
+}
@@ -0,0 +1,410 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// solver holds the state of an integration.
+type solver struct {
+	problem  Problem
+	method   Method
+	settings *Settings
+	sys      *System
+	stats    Stats
+
+	t0, t1 float64
+	dir    float64
+
+	t         float64
+	y, dy     []float64
+	yNew, dyN []float64
+	errEst    []float64
+	coef      [][]float64
+	g         []float64
+
+	ts     []float64
+	ys     [][]float64
+	out    int
+	events []EventRecord
+	sol    *Solution
+}
+
+func newSolver(p Problem, t0, t1 float64, y0 []float64, method Method, settings *Settings) *solver {
+	absTol, relTol := settings.AbsTol, settings.RelTol
+	if absTol < 0 || relTol < 0 {
+		panic("ode: negative tolerance")
+	}
+	if absTol == 0 && relTol == 0 {
+		absTol, relTol = defaultAbsTol, defaultRelTol
+	}
+	if settings.InitialStep < 0 || settings.MaxStep < 0 || settings.MaxSteps < 0 {
+		panic("ode: negative step setting")
+	}
+	dir := 1.0
+	if t1 < t0 {
+		dir = -1
+	}
+	for i, v := range settings.Output {
+		if dir*(v-t0) < 0 || dir*(t1-v) < 0 {
+			panic("ode: output time outside integration interval")
+		}
+		if i > 0 && dir*(v-settings.Output[i-1]) < 0 {
+			panic("ode: output times not sorted")
+		}
+	}
+
+	n := len(y0)
+	s := &solver{
+		problem:  p,
+		method:   method,
+		settings: settings,
+		t0:       t0,
+		t1:       t1,
+		dir:      dir,
+		t:        t0,
+		y:        append([]float64(nil), y0...),
+		dy:       make([]float64, n),
+		yNew:     make([]float64, n),
+		dyN:      make([]float64, n),
+		errEst:   make([]float64, n),
+	}
+	s.sys = &System{
+		problem: p,
+		dim:     n,
+		stats:   &s.stats,
+		absTol:  absTol,
+		relTol:  relTol,
+	}
+	if settings.Dense {
+		s.sol = &Solution{t0: t0, t1: t0, dim: n}
+	}
+	return s
+}
+
+func (s *solver) solve() (*Result, error) {
+	start := time.Now()
+	status, err := s.run()
+	s.stats.Runtime = time.Since(start)
+
+	res := &Result{
+		T:        s.ts,
+		Events:   s.events,
+		Solution: s.sol,
+		Stats:    s.stats,
+		Status:   status,
+	}
+	if len(s.ys) != 0 {
+		res.Y = mat.NewDense(len(s.ys), s.sys.dim, nil)
+		for i, row := range s.ys {
+			res.Y.SetRow(i, row)
+		}
+	}
+	if err == nil {
+		err = status.Err()
+	}
+	return res, err
+}
+
+func (s *solver) run() (Status, error) {
+	s.method.Init(s.sys)
+	s.sys.Func(s.dy, s.t, s.y)
+
+	rec := s.settings.Recorder
+	if rec != nil {
+		if err := rec.Init(); err != nil {
+			return Failure, err
+		}
+	}
+
+	// Report the initial point.
+	if s.settings.Output == nil {
+		s.report(s.t, s.y)
+	} else {
+		for ; s.out < len(s.settings.Output) && s.settings.Output[s.out] == s.t0; s.out++ {
+			s.report(s.t, s.y)
+		}
+	}
+	if len(s.problem.Events) != 0 {
+		s.g = make([]float64, len(s.problem.Events))
+		for i, e := range s.problem.Events {
+			s.g[i] = e.Func(s.t, s.y)
+		}
+	}
+	if rec != nil {
+		if err := rec.Record(s.t, s.y, &s.stats); err != nil {
+			return Failure, err
+		}
+	}
+	if s.t0 == s.t1 {
+		return Success, nil
+	}
+
+	maxSteps := s.settings.MaxSteps
+	if maxSteps == 0 {
+		maxSteps = defaultMaxSteps
+	}
+	maxStep := s.settings.MaxStep
+	if maxStep == 0 {
+		maxStep = math.Inf(1)
+	}
+	q := float64(s.method.Order())
+	hAbs := s.settings.InitialStep
+	if hAbs == 0 {
+		hAbs = s.initialStep(q)
+	}
+	hAbs = math.Min(hAbs, maxStep)
+
+	rejectedLast := false
+	for {
+		if s.stats.Steps+s.stats.Rejected >= maxSteps {
+			return StepLimit, nil
+		}
+		minStep := 10 * eps * math.Max(math.Abs(s.t), math.Abs(s.t1-s.t0)*eps)
+		if hAbs < minStep || math.IsNaN(hAbs) {
+			return StepSizeTooSmall, nil
+		}
+		hAbs = math.Min(hAbs, maxStep)
+		last := false
+		if remain := math.Abs(s.t1 - s.t); hAbs >= remain {
+			hAbs = remain
+			last = true
+		}
+		h := s.dir * hAbs
+		tNew := s.t + h
+		if last {
+			tNew = s.t1
+			h = tNew - s.t
+		}
+
+		if !s.method.Step(s.yNew, s.dyN, s.errEst, s.t, h, s.y, s.dy) {
+			s.stats.Rejected++
+			rejectedLast = true
+			hAbs *= 0.5
+			continue
+		}
+		errNorm := s.sys.norm(s.errEst, s.y, s.yNew)
+		if !(errNorm <= 1) {
+			s.stats.Rejected++
+			rejectedLast = true
+			factor := minFactor
+			if !math.IsNaN(errNorm) {
+				factor = math.Max(minFactor, safety*math.Pow(errNorm, -1/q))
+			}
+			hAbs *= factor
+			continue
+		}
+
+		// The step is accepted.
+		s.stats.Steps++
+		status := s.accept(h, tNew)
+		factor := float64(maxFactor)
+		if errNorm > 0 {
+			factor = math.Min(maxFactor, safety*math.Pow(errNorm, -1/q))
+		}
+		if rejectedLast {
+			factor = math.Min(1, factor)
+		}
+		rejectedLast = false
+		hAbs *= math.Max(minFactor, factor)
+
+		if rec != nil {
+			if err := rec.Record(s.t, s.y, &s.stats); err != nil {
+				return Failure, err
+			}
+		}
+		if status != NotTerminated {
+			return status, nil
+		}
+		if s.t == s.t1 {
+			return Success, nil
+		}
+	}
+}
+
+// accept processes an accepted step of size h ending at tNew, handling
+// events, output and dense output, and advances the solver state. It
+// returns TerminalEvent if a terminal event occurred during the step
+// and NotTerminated otherwise.
+func (s *solver) accept(h, tNew float64) Status {
+	needDense := s.sol != nil || s.settings.Output != nil || len(s.problem.Events) != 0
+	var seg segment
+	if needDense {
+		s.coef = s.method.DenseOutput(s.coef)
+		seg = segment{t0: s.t, h: h, coef: s.coef}
+	}
+
+	tEnd := tNew
+	yEnd := s.yNew
+	status := NotTerminated
+	if len(s.problem.Events) != 0 {
+		terminal := s.detectEvents(&seg, tNew)
+		if terminal >= 0 {
+			status = TerminalEvent
+			tEnd = s.events[terminal].T
+			yEnd = s.events[terminal].Y
+		}
+	}
+
+	if s.settings.Output == nil {
+		s.report(tEnd, yEnd)
+	} else {
+		out := s.settings.Output
+		for ; s.out < len(out) && s.dir*(out[s.out]-tEnd) <= 0; s.out++ {
+			if out[s.out] == tEnd {
+				s.report(tEnd, yEnd)
+				continue
+			}
+			y := make([]float64, len(s.y))
+			seg.at(y, out[s.out])
+			s.report(out[s.out], y)
+		}
+	}
+
+	if s.sol != nil {
+		coef := make([][]float64, len(s.coef))
+		for i, c := range s.coef {
+			coef[i] = append([]float64(nil), c...)
+		}
+		s.sol.steps = append(s.sol.steps, segment{t0: s.t, h: h, coef: coef})
+		s.sol.t1 = tEnd
+	}
+
+	s.t = tEnd
+	if status == TerminalEvent {
+		copy(s.y, yEnd)
+		s.sys.Func(s.dy, s.t, s.y)
+	} else {
+		s.y, s.yNew = s.yNew, s.y
+		s.dy, s.dyN = s.dyN, s.dy
+	}
+	return status
+}
+
+// detectEvents locates the zero crossings of the event functions during
+// the step described by seg ending at tNew and appends them to the events
+// in time order. Events after the first terminal event are discarded.
+// detectEvents returns the index into s.events of the terminal event,
+// or -1 if no terminal event occurred.
+func (s *solver) detectEvents(seg *segment, tNew float64) int {
+	var found []EventRecord
+	for i, e := range s.problem.Events {
+		ga := s.g[i]
+		gb := e.Func(tNew, s.yNew)
+		s.g[i] = gb
+		up := ga < 0 && gb >= 0
+		down := ga > 0 && gb <= 0
+		if !(up && e.Direction >= 0) && !(down && e.Direction <= 0) {
+			continue
+		}
+		y := make([]float64, len(s.y))
+		te := s.locate(e.Func, seg, s.t, tNew, ga, gb, y)
+		found = append(found, EventRecord{Index: i, T: te, Y: y})
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return s.dir*(found[i].T-found[j].T) < 0
+	})
+	for _, ev := range found {
+		s.events = append(s.events, ev)
+		if s.problem.Events[ev.Index].Terminal {
+			// Reset the event values to the state at the terminal event.
+			for k, e := range s.problem.Events {
+				s.g[k] = e.Func(ev.T, ev.Y)
+			}
+			return len(s.events) - 1
+		}
+	}
+	return -1
+}
+
+// locate finds the zero crossing of g along the dense output seg between
+// ta and tb, where g(ta) = ga and g(tb) = gb have opposite signs, using
+// the Illinois variant of regula falsi. The solution at the returned time
+// is stored in y. The returned time is on the same side of the crossing
+// as tb.
+func (s *solver) locate(g func(float64, []float64) float64, seg *segment, ta, tb, ga, gb float64, y []float64) float64 {
+	if gb == 0 {
+		copy(y, s.yNew)
+		return tb
+	}
+	const maxIter = 100
+	side := 0
+	for i := 0; i < maxIter; i++ {
+		if math.Abs(tb-ta) <= 4*eps*math.Max(math.Abs(ta), math.Abs(tb)) {
+			break
+		}
+		t := (ta*gb - tb*ga) / (gb - ga)
+		if !(math.Min(ta, tb) < t && t < math.Max(ta, tb)) {
+			t = 0.5 * (ta + tb)
+		}
+		seg.at(y, t)
+		gt := g(t, y)
+		if gt == 0 {
+			return t
+		}
+		if math.Signbit(gt) == math.Signbit(gb) {
+			tb, gb = t, gt
+			if side == 1 {
+				ga *= 0.5
+			}
+			side = 1
+		} else {
+			ta, ga = t, gt
+			if side == -1 {
+				gb *= 0.5
+			}
+			side = -1
+		}
+	}
+	seg.at(y, tb)
+	return tb
+}
+
+// report appends the solution y at t to the output.
+func (s *solver) report(t float64, y []float64) {
+	s.ts = append(s.ts, t)
+	s.ys = append(s.ys, append([]float64(nil), y...))
+}
+
+// initialStep returns an initial step size magnitude using the algorithm
+// of Hairer, Nørsett and Wanner for a method with local error estimate of
+// order q.
+//
+// Reference:
+//  E. Hairer, S. P. Nørsett and G. Wanner, "Solving Ordinary Differential
+//  Equations I: Nonstiff Problems", Sec. II.4, 2nd edition, Springer, 1993.
+func (s *solver) initialStep(q float64) float64 {
+	n := len(s.y)
+	sys := s.sys
+	zero := make([]float64, n)
+	d0 := sys.norm(s.y, s.y, zero)
+	d1 := sys.norm(s.dy, s.y, zero)
+	h0 := 1e-6
+	if d0 >= 1e-5 && d1 >= 1e-5 {
+		h0 = 0.01 * d0 / d1
+	}
+	h0 = math.Min(h0, math.Abs(s.t1-s.t0))
+
+	y1 := make([]float64, n)
+	floats.AddScaledTo(y1, s.y, s.dir*h0, s.dy)
+	f1 := make([]float64, n)
+	sys.Func(f1, s.t+s.dir*h0, y1)
+	floats.Sub(f1, s.dy)
+	d2 := sys.norm(f1, s.y, zero) / h0
+
+	var h1 float64
+	if dmax := math.Max(d1, d2); dmax <= 1e-15 {
+		h1 = math.Max(1e-6, h0*1e-3)
+	} else {
+		h1 = math.Pow(0.01/dmax, 1/q)
+	}
+	return math.Min(100*h0, h1)
+}
@@ -0,0 +1,91 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ode
+
+import "errors"
+
+// Status represents the status of an integration. Programs should not
+// rely on the underlying numeric value of the Status being constant.
+type Status int
+
+const (
+	// NotTerminated indicates that the integration has not finished.
+	NotTerminated Status = iota
+	// Success indicates that the end of the integration interval
+	// was reached.
+	Success
+	// TerminalEvent indicates that the integration was stopped by
+	// a terminal event.
+	TerminalEvent
+	// StepLimit indicates that the maximum number of steps was
+	// reached.
+	StepLimit
+	// StepSizeTooSmall indicates that the step size became too small
+	// relative to the integration variable, which usually indicates
+	// a singularity in the solution or a very stiff problem.
+	StepSizeTooSmall
+	// Failure indicates that the method failed to compute a step.
+	Failure
+	// NodeLimit indicates that the maximum number of mesh nodes of a
+	// boundary value problem solver was reached before the requested
+	// accuracy was achieved.
+	NodeLimit
+	// SingularJacobian indicates that the Jacobian of the nonlinear
+	// equations of a boundary value problem was singular.
+	SingularJacobian
+	// NoConvergence indicates that the Newton iterations for the
+	// nonlinear equations of a boundary value problem did not converge.
+	NoConvergence
+)
+
+func (s Status) String() string {
+	return statuses[s].name
+}
+
+// Err returns the error associated with an integration that ended before
+// the end of the integration interval or a terminal event. If the
+// integration was successful, Err returns nil.
+func (s Status) Err() error {
+	return statuses[s].err
+}
+
+var statuses = []struct {
+	name string
+	err  error
+}{
+	{
+		name: "NotTerminated",
+	},
+	{
+		name: "Success",
+	},
+	{
+		name: "TerminalEvent",
+	},
+	{
+		name: "StepLimit",
+		err:  errors.New("ode: step limit reached"),
+	},
+	{
+		name: "StepSizeTooSmall",
+		err:  errors.New("ode: step size too small"),
+	},
+	{
+		name: "Failure",
+		err:  errors.New("ode: method failed"),
+	},
+	{
+		name: "NodeLimit",
+		err:  errors.New("ode: mesh node limit reached"),
+	},
+	{
+		name: "SingularJacobian",
+		err:  errors.New("ode: singular Jacobian"),
+	},
+	{
+		name: "NoConvergence",
+		err:  errors.New("ode: Newton iteration did not converge"),
+	},
+}
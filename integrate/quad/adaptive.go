@@ -0,0 +1,565 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultAbsTol = 1e-10
+	defaultRelTol = 1e-10
+
+	defaultSubintervals = 100
+	defaultLevels       = 10
+)
+
+// Status represents the status of an adaptive integration. Programs
+// should not rely on the underlying numeric value of the Status being constant.
+type Status int
+
+const (
+	// Success indicates that the requested accuracy was achieved.
+	Success Status = iota
+	// IterationLimit indicates that the maximum number of subdivisions
+	// or refinement levels was reached before the requested accuracy
+	// was achieved.
+	IterationLimit
+	// RoundoffError indicates that roundoff error prevented the requested
+	// accuracy from being achieved.
+	RoundoffError
+	// BadIntegrand indicates that the integrand behaves extremely badly
+	// at some point of the integration interval.
+	BadIntegrand
+	// NoConvergence indicates that extrapolation of the sequence of
+	// approximations did not converge.
+	NoConvergence
+	// Divergent indicates that the integral is probably divergent or
+	// converges too slowly to be computed.
+	Divergent
+)
+
+func (s Status) String() string {
+	if s < 0 || int(s) >= len(statuses) {
+		return "Status(unknown)"
+	}
+	return statuses[s].name
+}
+
+// Err returns the error associated with an integration that did not achieve
+// the requested accuracy. If s is Success, Err returns nil.
+func (s Status) Err() error {
+	if s < 0 || int(s) >= len(statuses) {
+		return errors.New("quad: unknown status")
+	}
+	return statuses[s].err
+}
+
+var statuses = []struct {
+	name string
+	err  error
+}{
+	{name: "Success"},
+	{name: "IterationLimit", err: errors.New("quad: iteration limit reached")},
+	{name: "RoundoffError", err: errors.New("quad: roundoff error prevents requested accuracy")},
+	{name: "BadIntegrand", err: errors.New("quad: bad integrand behavior")},
+	{name: "NoConvergence", err: errors.New("quad: extrapolation did not converge")},
+	{name: "Divergent", err: errors.New("quad: integral is probably divergent")},
+}
+
+// Result holds the result of an adaptive integration.
+type Result struct {
+	// Value is the approximation to the integral.
+	Value float64
+	// Error is an estimate of the absolute error in Value.
+	Error float64
+	// FuncEvaluations is the number of evaluations of the integrand.
+	FuncEvaluations int
+	// Status describes how the integration terminated.
+	Status Status
+}
+
+// Settings holds the convergence settings for adaptive integration.
+type Settings struct {
+	// AbsTol and RelTol are the requested absolute and relative accuracy
+	// of the integral. Integration terminates when the estimated error is
+	// less than max(AbsTol, RelTol*|integral|). If both are zero, both are
+	// set to 1e-10. A non-zero RelTol is increased to at least 50 times
+	// machine epsilon.
+	AbsTol, RelTol float64
+
+	// Limit is the maximum number of subintervals used by Adaptive,
+	// or the maximum number of levels of refinement used by TanhSinh.
+	// If Limit is zero, a default of 100 subintervals or 10 levels is used.
+	Limit int
+}
+
+// tolerances returns the absolute and relative tolerances and the limit
+// specified by s, using defaultLimit if s does not specify a limit.
+func (s *Settings) tolerances(defaultLimit int) (absTol, relTol float64, limit int) {
+	limit = defaultLimit
+	if s == nil {
+		return defaultAbsTol, defaultRelTol, limit
+	}
+	if s.Limit < 0 {
+		panic("quad: negative limit")
+	}
+	if s.Limit > 0 {
+		limit = s.Limit
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 {
+		panic("quad: negative tolerance")
+	}
+	absTol, relTol = s.AbsTol, s.RelTol
+	if absTol == 0 && relTol == 0 {
+		return defaultAbsTol, defaultRelTol, limit
+	}
+	if relTol != 0 {
+		relTol = math.Max(relTol, 50*epmach)
+	}
+	return absTol, relTol, limit
+}
+
+const (
+	epmach = 0x1p-52
+	uflow  = 0x1p-1022
+	oflow  = math.MaxFloat64
+)
+
+// Adaptive approximates the integral of the function f from min to max to
+// a requested accuracy using globally adaptive 21-point Gauss–Kronrod
+// quadrature with extrapolation by the Wynn epsilon algorithm. The
+// algorithm is that of the QUADPACK routine QAGS, and is able to integrate
+// many functions with integrable singularities at the end points. If either
+// bound is infinite, the interval is mapped onto (0, 1] by the transformation
+// x = a ± (1-t)/t, as in the QUADPACK routine QAGI.
+//
+// The interval with the largest estimated error is bisected at each step
+// until the total estimated error satisfies the tolerances in settings, or
+// the maximum number of subintervals is reached. If settings is nil, the
+// defaults described for Settings are used. The Status field of the returned
+// Result indicates whether the requested accuracy was achieved.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations.
+//
+// min must be less than or equal to max, otherwise Adaptive will panic.
+func Adaptive(f func(float64) float64, min, max float64, settings *Settings, concurrent int) Result {
+	if min > max {
+		panic("quad: min > max")
+	}
+	absTol, relTol, limit := settings.tolerances(defaultSubintervals)
+	if min == max {
+		return Result{Status: Success}
+	}
+	f, min, max = infiniteTransform(f, min, max)
+	q := qags{f: f, concurrent: concurrent}
+	return q.integrate(min, max, absTol, relTol, limit)
+}
+
+// infiniteTransform returns an integrand and bounds over a finite interval
+// equivalent to the integral of f from min to max when either bound is
+// infinite. Otherwise f, min and max are returned unaltered.
+func infiniteTransform(f func(float64) float64, min, max float64) (func(float64) float64, float64, float64) {
+	switch {
+	case math.IsInf(min, -1) && math.IsInf(max, 1):
+		// x = ±(1-t)/t.
+		return func(t float64) float64 {
+			x := (1 - t) / t
+			return (f(x) + f(-x)) / (t * t)
+		}, 0, 1
+	case math.IsInf(max, 1):
+		// x = a + (1-t)/t.
+		a := min
+		return func(t float64) float64 {
+			return f(a+(1-t)/t) / (t * t)
+		}, 0, 1
+	case math.IsInf(min, -1):
+		// x = b - (1-t)/t.
+		b := max
+		return func(t float64) float64 {
+			return f(b-(1-t)/t) / (t * t)
+		}, 0, 1
+	}
+	return f, min, max
+}
+
+// interval is a subinterval of the integration range with its
+// integral and error estimates.
+type interval struct {
+	a, b     float64
+	integral float64
+	err      float64
+}
+
+// qags implements the QUADPACK QAGS algorithm.
+type qags struct {
+	f          func(float64) float64
+	concurrent int
+	evals      int
+
+	x, fx []float64
+}
+
+func (q *qags) integrate(a, b, absTol, relTol float64, limit int) Result {
+	var status Status
+	roundoff := false
+
+	result, abserr, defabs, resabs := q.gk21(a, b)
+	dres := math.Abs(result)
+	errbnd := math.Max(absTol, relTol*dres)
+	if abserr <= 100*epmach*defabs && abserr > errbnd {
+		status = RoundoffError
+	}
+	if limit == 1 {
+		status = IterationLimit
+	}
+	if status != Success || (abserr <= errbnd && abserr != resabs) || abserr == 0 {
+		return Result{Value: result, Error: abserr, FuncEvaluations: q.evals, Status: status}
+	}
+
+	ivs := []interval{{a: a, b: b, integral: result, err: abserr}}
+	var eps epsilonTable
+	eps.push(result)
+
+	area := result
+	errsum := abserr
+	abserr = oflow
+	next := 0
+	var (
+		small, erlarg, ertest, correc float64
+		ktmin                         int
+		extrap, noext                 bool
+		iroff1, iroff2, iroff3        int
+	)
+	ksgn := -1
+	if dres >= (1-50*epmach)*defabs {
+		ksgn = 1
+	}
+
+	for last := 2; last <= limit; last++ {
+		// Bisect the selected subinterval.
+		iv := ivs[next]
+		ivs = append(ivs[:next], ivs[next+1:]...)
+		a1, b2 := iv.a, iv.b
+		b1 := 0.5 * (iv.a + iv.b)
+		a2 := b1
+		erlast := iv.err
+		area1, error1, defab1, defab2, area2, error2 := q.bisect(a1, b1, b2)
+
+		// Improve the previous approximations to the integral
+		// and error and test for accuracy.
+		area12 := area1 + area2
+		erro12 := error1 + error2
+		errsum += erro12 - iv.err
+		area += area12 - iv.integral
+		if defab1 != error1 && defab2 != error2 {
+			if math.Abs(iv.integral-area12) <= 1e-5*math.Abs(area12) && erro12 >= 0.99*iv.err {
+				if extrap {
+					iroff2++
+				} else {
+					iroff1++
+				}
+			}
+			if last > 10 && erro12 > iv.err {
+				iroff3++
+			}
+		}
+		errbnd = math.Max(absTol, relTol*math.Abs(area))
+
+		if iroff1+iroff2 >= 10 || iroff3 >= 20 {
+			status = RoundoffError
+		}
+		if iroff2 >= 5 {
+			roundoff = true
+		}
+		if last == limit {
+			status = IterationLimit
+		}
+		// Test for bad behavior of the integrand at a point
+		// of the integration range.
+		if math.Max(math.Abs(a1), math.Abs(b2)) <= (1+100*epmach)*(math.Abs(a2)+1000*uflow) {
+			status = BadIntegrand
+		}
+
+		ivs = append(ivs,
+			interval{a: a1, b: b1, integral: area1, err: error1},
+			interval{a: a2, b: b2, integral: area2, err: error2},
+		)
+		sort.SliceStable(ivs, func(i, j int) bool { return ivs[i].err > ivs[j].err })
+		next = 0
+
+		if errsum <= errbnd {
+			return Result{Value: sum(ivs), Error: errsum, FuncEvaluations: q.evals, Status: Success}
+		}
+		if status != Success {
+			break
+		}
+		if last == 2 {
+			small = math.Abs(b-a) * 0.375
+			erlarg = errsum
+			ertest = errbnd
+			eps.push(area)
+			continue
+		}
+		if noext {
+			continue
+		}
+
+		erlarg -= erlast
+		if math.Abs(b1-a1) > small {
+			erlarg += erro12
+		}
+		if !extrap {
+			// Test whether the interval to be bisected
+			// next is the smallest interval.
+			if ivs[0].b-ivs[0].a > small {
+				continue
+			}
+			extrap = true
+		}
+		if !roundoff && erlarg > ertest {
+			// The smallest interval has the largest error. Before
+			// bisecting, decrease the sum of the errors over the
+			// larger intervals and perform extrapolation.
+			found := false
+			for i, v := range ivs {
+				if v.b-v.a > small {
+					next = i
+					found = true
+					break
+				}
+			}
+			if found {
+				continue
+			}
+		}
+
+		// Perform extrapolation.
+		eps.push(area)
+		reseps, abseps := eps.extrapolate()
+		ktmin++
+		if ktmin > 5 && abserr < 1e-3*errsum {
+			status = NoConvergence
+		}
+		if abseps < abserr {
+			ktmin = 0
+			abserr = abseps
+			result = reseps
+			correc = erlarg
+			ertest = math.Max(absTol, relTol*math.Abs(reseps))
+			if abserr <= ertest {
+				break
+			}
+		}
+
+		// Prepare bisection of the smallest interval.
+		if eps.len() == 1 {
+			noext = true
+		}
+		if status == NoConvergence {
+			break
+		}
+		next = 0
+		extrap = false
+		small *= 0.5
+		erlarg = errsum
+	}
+
+	// Set the final result and error estimate.
+	if abserr == oflow {
+		return Result{Value: sum(ivs), Error: errsum, FuncEvaluations: q.evals, Status: status}
+	}
+	if status != Success || roundoff {
+		if roundoff {
+			abserr += correc
+		}
+		if status == Success {
+			status = RoundoffError
+		}
+		if result == 0 || area == 0 {
+			if abserr > errsum {
+				return Result{Value: sum(ivs), Error: errsum, FuncEvaluations: q.evals, Status: status}
+			}
+			if area == 0 {
+				return Result{Value: result, Error: abserr, FuncEvaluations: q.evals, Status: status}
+			}
+		} else if abserr/math.Abs(result) > errsum/math.Abs(area) {
+			return Result{Value: sum(ivs), Error: errsum, FuncEvaluations: q.evals, Status: status}
+		}
+	}
+
+	// Test on divergence.
+	if ksgn == -1 && math.Max(math.Abs(result), math.Abs(area)) <= defabs*0.01 {
+		return Result{Value: result, Error: abserr, FuncEvaluations: q.evals, Status: status}
+	}
+	if r := result / area; r < 0.01 || 100 < r || errsum > math.Abs(area) {
+		status = Divergent
+	}
+	return Result{Value: result, Error: abserr, FuncEvaluations: q.evals, Status: status}
+}
+
+func sum(ivs []interval) float64 {
+	var s float64
+	for _, v := range ivs {
+		s += v.integral
+	}
+	return s
+}
+
+// Nodes and weights of the 21-point Kronrod rule and the embedded
+// 10-point Gauss rule from QUADPACK. The Gauss nodes are the Kronrod
+// nodes with odd index.
+var (
+	xgk = [11]float64{
+		0.995657163025808080735527280689003,
+		0.973906528517171720077964012084452,
+		0.930157491355708226001207180059508,
+		0.865063366688984510732096688423493,
+		0.780817726586416897063717578345042,
+		0.679409568299024406234327365114874,
+		0.562757134668604683339000099272694,
+		0.433395394129247190799265943165784,
+		0.294392862701460198131126603103866,
+		0.148874338981631210884826001129720,
+		0,
+	}
+	wgk = [11]float64{
+		0.011694638867371874278064396062192,
+		0.032558162307964727478818972459390,
+		0.054755896574351996031381300244580,
+		0.075039674810919952767043140916190,
+		0.093125454583697605535065465083366,
+		0.109387158802297641899210590325805,
+		0.123491976262065851077208931232902,
+		0.134709217311473325928054001771707,
+		0.142775938577060080797094273138717,
+		0.147739104901338491374841515972068,
+		0.149445554002916905664936468389821,
+	}
+	wg = [5]float64{
+		0.066671344308688137593568809893332,
+		0.149451349150580593145776339657697,
+		0.219086362515982043995534934228163,
+		0.269266719309996355091226921569469,
+		0.295524224714752870173892994651338,
+	}
+)
+
+// gk21 returns the 21-point Gauss–Kronrod approximation to the integral over
+// [a, b] with its error estimate, and the approximations to the integrals
+// of |f| and |f - mean(f)| over the interval.
+func (q *qags) gk21(a, b float64) (result, abserr, resabs, resasc float64) {
+	q.points(a, b, 0)
+	q.eval(21)
+	return gk21Sums(q.fx[:21], a, b)
+}
+
+// bisect evaluates the 21-point rule on [a, m] and [m, b].
+func (q *qags) bisect(a, m, b float64) (area1, error1, defab1, defab2, area2, error2 float64) {
+	q.points(a, m, 0)
+	q.points(m, b, 21)
+	q.eval(42)
+	area1, error1, _, defab1 = gk21Sums(q.fx[:21], a, m)
+	area2, error2, _, defab2 = gk21Sums(q.fx[21:42], m, b)
+	return area1, error1, defab1, defab2, area2, error2
+}
+
+// points places the 21 Kronrod nodes for [a, b] in q.x starting at offset.
+// The centre is first, followed by pairs of nodes symmetric about it.
+func (q *qags) points(a, b float64, offset int) {
+	if len(q.x) < 42 {
+		q.x = make([]float64, 42)
+		q.fx = make([]float64, 42)
+	}
+	centr := 0.5 * (a + b)
+	hlgth := 0.5 * (b - a)
+	x := q.x[offset : offset+21]
+	x[0] = centr
+	for j := 0; j < 10; j++ {
+		absc := hlgth * xgk[j]
+		x[2*j+1] = centr - absc
+		x[2*j+2] = centr + absc
+	}
+}
+
+// eval evaluates the integrand at the first n points of q.x.
+func (q *qags) eval(n int) {
+	evaluate(q.f, q.x[:n], q.fx[:n], q.concurrent)
+	q.evals += n
+}
+
+// gk21Sums computes the 21-point Gauss–Kronrod sums for the function values
+// fx ordered as by points.
+func gk21Sums(fx []float64, a, b float64) (result, abserr, resabs, resasc float64) {
+	hlgth := 0.5 * (b - a)
+	dhlgth := math.Abs(hlgth)
+
+	fc := fx[0]
+	resg := 0.0
+	resk := wgk[10] * fc
+	resabs = math.Abs(resk)
+	for j := 0; j < 10; j++ {
+		fval1 := fx[2*j+1]
+		fval2 := fx[2*j+2]
+		fsum := fval1 + fval2
+		resk += wgk[j] * fsum
+		resabs += wgk[j] * (math.Abs(fval1) + math.Abs(fval2))
+		if j%2 == 1 {
+			resg += wg[j/2] * fsum
+		}
+	}
+	reskh := resk * 0.5
+	resasc = wgk[10] * math.Abs(fc-reskh)
+	for j := 0; j < 10; j++ {
+		resasc += wgk[j] * (math.Abs(fx[2*j+1]-reskh) + math.Abs(fx[2*j+2]-reskh))
+	}
+	result = resk * hlgth
+	resabs *= dhlgth
+	resasc *= dhlgth
+	abserr = math.Abs((resk - resg) * hlgth)
+	if resasc != 0 && abserr != 0 {
+		abserr = resasc * math.Min(1, math.Pow(200*abserr/resasc, 1.5))
+	}
+	if resabs > uflow/(50*epmach) {
+		abserr = math.Max(50*epmach*resabs, abserr)
+	}
+	return result, abserr, resabs, resasc
+}
+
+// evaluate places f(x[i]) into fx[i] for each element of x. If concurrent
+// is positive, at most concurrent evaluations are performed simultaneously.
+func evaluate(f func(float64) float64, x, fx []float64, concurrent int) {
+	if concurrent > len(x) {
+		concurrent = len(x)
+	}
+	if concurrent <= 1 {
+		for i, v := range x {
+			fx[i] = f(v)
+		}
+		return
+	}
+	tasks := make(chan int)
+	go func() {
+		for i := range x {
+			tasks <- i
+		}
+		close(tasks)
+	}()
+	var wg sync.WaitGroup
+	wg.Add(concurrent)
+	for i := 0; i < concurrent; i++ {
+		go func() {
+			defer wg.Done()
+			for k := range tasks {
+				fx[k] = f(x[k])
+			}
+		}()
+	}
+	wg.Wait()
+}
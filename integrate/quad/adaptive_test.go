@@ -0,0 +1,211 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/testquad"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// adaptiveIntegrals returns integrals for testing adaptive quadrature,
+// including integrands with end point singularities and infinite bounds.
+func adaptiveIntegrals() []testquad.Integral {
+	return []testquad.Integral{
+		testquad.Constant(0),
+		testquad.Constant(2),
+		testquad.Poly(0),
+		testquad.Poly(1),
+		testquad.Poly(5),
+		testquad.Poly(31),
+		testquad.Sin(),
+		testquad.XExpMinusX(),
+		testquad.Sqrt(),
+		testquad.ExpOverX2Plus1(),
+		{
+			Name:  "∫_0^1 1/sqrt(x)dx",
+			A:     0,
+			B:     1,
+			F:     func(x float64) float64 { return 1 / math.Sqrt(x) },
+			Value: 2,
+		},
+		{
+			Name:  "∫_0^1 log(x)dx",
+			A:     0,
+			B:     1,
+			F:     math.Log,
+			Value: -1,
+		},
+		{
+			Name:  "∫_0^1 log(x)/sqrt(x)dx",
+			A:     0,
+			B:     1,
+			F:     func(x float64) float64 { return math.Log(x) / math.Sqrt(x) },
+			Value: -4,
+		},
+		{
+			Name:  "∫_0^∞ exp(-x)dx",
+			A:     0,
+			B:     math.Inf(1),
+			F:     func(x float64) float64 { return math.Exp(-x) },
+			Value: 1,
+		},
+		{
+			Name:  "∫_-∞^-5 exp(x)dx",
+			A:     math.Inf(-1),
+			B:     -5,
+			F:     math.Exp,
+			Value: math.Exp(-5),
+		},
+		{
+			Name:  "∫_-∞^∞ φ(x)dx",
+			A:     math.Inf(-1),
+			B:     math.Inf(1),
+			F:     distuv.UnitNormal.Prob,
+			Value: 1,
+		},
+		{
+			Name:  "∫_0^∞ 1/(1+x^2)dx",
+			A:     0,
+			B:     math.Inf(1),
+			F:     func(x float64) float64 { return 1 / (1 + x*x) },
+			Value: math.Pi / 2,
+		},
+		{
+			Name:  "∫_3^3 exp(x)dx",
+			A:     3,
+			B:     3,
+			F:     math.Exp,
+			Value: 0,
+		},
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-10
+	for _, test := range adaptiveIntegrals() {
+		for _, concurrent := range []int{0, 3} {
+			res := Adaptive(test.F, test.A, test.B, nil, concurrent)
+			if res.Status != Success {
+				t.Errorf("%s, concurrent=%d: unexpected status: %v", test.Name, concurrent, res.Status)
+			}
+			if res.Status.Err() != nil {
+				t.Errorf("%s, concurrent=%d: unexpected error: %v", test.Name, concurrent, res.Status.Err())
+			}
+			if !floats.EqualWithinAbsOrRel(res.Value, test.Value, tol, tol) {
+				t.Errorf("%s, concurrent=%d: mismatch: want %v, got %v", test.Name, concurrent, test.Value, res.Value)
+			}
+			if math.Abs(res.Value-test.Value) > math.Max(res.Error, 1e-14*math.Abs(test.Value)) {
+				t.Errorf("%s, concurrent=%d: error estimate too small: estimate %v, actual %v",
+					test.Name, concurrent, res.Error, math.Abs(res.Value-test.Value))
+			}
+		}
+	}
+}
+
+func TestAdaptiveSettings(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+
+	loose := Adaptive(f, 0, 1, &Settings{AbsTol: 1e-4}, 0)
+	tight := Adaptive(f, 0, 1, &Settings{AbsTol: 1e-12, RelTol: 1e-12}, 0)
+	if loose.Status != Success || tight.Status != Success {
+		t.Fatalf("unexpected status: loose %v, tight %v", loose.Status, tight.Status)
+	}
+	if loose.FuncEvaluations > tight.FuncEvaluations {
+		t.Errorf("loose tolerance used more evaluations than tight: %d > %d",
+			loose.FuncEvaluations, tight.FuncEvaluations)
+	}
+	if math.Abs(loose.Value+4) > 1e-4 {
+		t.Errorf("loose tolerance not satisfied: got %v", loose.Value)
+	}
+
+	res := Adaptive(f, 0, 1, &Settings{AbsTol: 1e-14, Limit: 2}, 0)
+	if res.Status != IterationLimit {
+		t.Errorf("unexpected status with subinterval limit: got %v, want %v", res.Status, IterationLimit)
+	}
+	if res.Status.Err() == nil {
+		t.Errorf("expected error with subinterval limit")
+	}
+	if res.FuncEvaluations != 3*21 {
+		t.Errorf("unexpected number of evaluations: got %d, want %d", res.FuncEvaluations, 3*21)
+	}
+}
+
+func TestAdaptiveDivergent(t *testing.T) {
+	t.Parallel()
+	res := Adaptive(func(x float64) float64 { return 1 / x }, 0, 1, nil, 0)
+	if res.Status == Success {
+		t.Errorf("unexpected success for divergent integral: got %v", res.Value)
+	}
+}
+
+func TestGaussKronrod21(t *testing.T) {
+	t.Parallel()
+	// The 21-point Kronrod rule integrates polynomials of degree up to
+	// 31 exactly, and the embedded 10-point Gauss rule up to degree 19.
+	for deg := 0; deg <= 31; deg++ {
+		want := (1 - math.Pow(-1, float64(deg+1))) / float64(deg+1)
+		var q qags
+		q.f = func(x float64) float64 { return math.Pow(x, float64(deg)) }
+		q.points(-1, 1, 0)
+		q.eval(21)
+		var resk, resg float64
+		resk = wgk[10] * q.fx[0]
+		for j := 0; j < 10; j++ {
+			fsum := q.fx[2*j+1] + q.fx[2*j+2]
+			resk += wgk[j] * fsum
+			if j%2 == 1 {
+				resg += wg[j/2] * fsum
+			}
+		}
+		if math.Abs(resk-want) > 1e-14 {
+			t.Errorf("Kronrod rule not exact for degree %d: got %v, want %v", deg, resk, want)
+		}
+		if deg <= 19 && math.Abs(resg-want) > 1e-14 {
+			t.Errorf("Gauss rule not exact for degree %d: got %v, want %v", deg, resg, want)
+		}
+	}
+}
+
+func TestEpsilonTable(t *testing.T) {
+	t.Parallel()
+	// The partial sums of the alternating harmonic series converge
+	// slowly to log(2), but are accelerated by the epsilon algorithm.
+	var e epsilonTable
+	var s, result, abserr float64
+	for k := 1; k <= 20; k++ {
+		s += math.Pow(-1, float64(k+1)) / float64(k)
+		e.push(s)
+		if k >= 3 {
+			result, abserr = e.extrapolate()
+		}
+	}
+	if math.Abs(result-math.Ln2) > 1e-12 {
+		t.Errorf("unexpected extrapolated limit: got %v, want %v", result, math.Ln2)
+	}
+	if abserr > 1e-10 {
+		t.Errorf("unexpected error estimate: got %v", abserr)
+	}
+}
+
+func TestStatusString(t *testing.T) {
+	t.Parallel()
+	for s := Success; s <= Divergent; s++ {
+		if s.String() == "Status(unknown)" {
+			t.Errorf("missing name for status %d", int(s))
+		}
+		if (s.Err() == nil) != (s == Success) {
+			t.Errorf("unexpected error for status %v: %v", s, s.Err())
+		}
+	}
+	if Status(-1).String() != "Status(unknown)" {
+		t.Errorf("unexpected name for unknown status")
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// ChebyshevFirst generates sample locations and weights for performing
+// quadrature with the weight of the Chebyshev polynomials of the first kind
+// over finite bounds
+//  int_min^max f(x) / sqrt((max-x)(x-min)) dx .
+type ChebyshevFirst struct{}
+
+func (c ChebyshevFirst) FixedLocations(x, weight []float64, min, max float64) {
+	if len(x) != len(weight) {
+		panic("chebyshev: slice length mismatch")
+	}
+	checkChebyshevBounds(min, max)
+	for i := range x {
+		x[i], weight[i] = c.location(len(x), i, min, max)
+	}
+}
+
+func (c ChebyshevFirst) FixedLocationSingle(n, k int, min, max float64) (x, weight float64) {
+	checkChebyshevBounds(min, max)
+	return c.location(n, k, min, max)
+}
+
+func (ChebyshevFirst) location(n, k int, min, max float64) (x, weight float64) {
+	// The nodes are the zeros of T_n, cos((2j+1)π/(2n)), and the weights
+	// are all π/n. The weight function is invariant under the change of
+	// interval so the weights do not need to be scaled.
+	t := -math.Cos(float64(2*k+1) * math.Pi / float64(2*n))
+	return (t+1)/2*(max-min) + min, math.Pi / float64(n)
+}
+
+// ChebyshevSecond generates sample locations and weights for performing
+// quadrature with the weight of the Chebyshev polynomials of the second kind
+// over finite bounds
+//  int_min^max f(x) sqrt((max-x)(x-min)) dx .
+type ChebyshevSecond struct{}
+
+func (c ChebyshevSecond) FixedLocations(x, weight []float64, min, max float64) {
+	if len(x) != len(weight) {
+		panic("chebyshev: slice length mismatch")
+	}
+	checkChebyshevBounds(min, max)
+	for i := range x {
+		x[i], weight[i] = c.location(len(x), i, min, max)
+	}
+}
+
+func (c ChebyshevSecond) FixedLocationSingle(n, k int, min, max float64) (x, weight float64) {
+	checkChebyshevBounds(min, max)
+	return c.location(n, k, min, max)
+}
+
+func (ChebyshevSecond) location(n, k int, min, max float64) (x, weight float64) {
+	// The nodes are the zeros of U_n, cos((j+1)π/(n+1)), with weights
+	// π/(n+1) sin^2((j+1)π/(n+1)).
+	theta := float64(k+1) * math.Pi / float64(n+1)
+	t := -math.Cos(theta)
+	s := math.Sin(theta)
+	h := (max - min) / 2
+	return (t+1)*h + min, math.Pi / float64(n+1) * s * s * h * h
+}
+
+func checkChebyshevBounds(min, max float64) {
+	if min >= max {
+		panic("chebyshev: min >= max")
+	}
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		panic("chebyshev: infinite bound")
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestChebyshev(t *testing.T) {
+	t.Parallel()
+	for i, test := range []struct {
+		rule     FixedLocationer
+		f        func(float64) float64
+		min, max float64
+		n        int
+		want     float64
+	}{
+		{rule: ChebyshevFirst{}, f: func(float64) float64 { return 1 }, min: -1, max: 1, n: 1, want: math.Pi},
+		{rule: ChebyshevFirst{}, f: func(x float64) float64 { return x * x }, min: -1, max: 1, n: 2, want: math.Pi / 2},
+		{rule: ChebyshevFirst{}, f: func(x float64) float64 { return x * x }, min: 0, max: 4, n: 3, want: 6 * math.Pi},
+		{rule: ChebyshevFirst{}, f: math.Exp, min: -1, max: 1, n: 20, want: math.Pi * 1.2660658777520082},
+		{rule: ChebyshevSecond{}, f: func(float64) float64 { return 1 }, min: -1, max: 1, n: 1, want: math.Pi / 2},
+		{rule: ChebyshevSecond{}, f: func(x float64) float64 { return x * x }, min: -1, max: 1, n: 2, want: math.Pi / 8},
+		{rule: ChebyshevSecond{}, f: func(float64) float64 { return 1 }, min: 0, max: 4, n: 3, want: 2 * math.Pi},
+		{rule: ChebyshevSecond{}, f: math.Exp, min: -1, max: 1, n: 20, want: math.Pi * 0.5651591039924851},
+	} {
+		for _, concurrent := range []int{0, 2} {
+			got := Fixed(test.f, test.min, test.max, test.n, test.rule, concurrent)
+			if !floats.EqualWithinAbsOrRel(got, test.want, 1e-14, 1e-14) {
+				t.Errorf("Case %d: mismatch: got %v, want %v", i, got, test.want)
+			}
+		}
+	}
+}
+
+func TestChebyshevLocations(t *testing.T) {
+	t.Parallel()
+	for _, rule := range []interface {
+		FixedLocationer
+		FixedLocationSingler
+	}{ChebyshevFirst{}, ChebyshevSecond{}} {
+		const n = 7
+		x := make([]float64, n)
+		w := make([]float64, n)
+		rule.FixedLocations(x, w, -2, 3)
+		for i := 1; i < n; i++ {
+			if x[i] <= x[i-1] {
+				t.Errorf("%T: locations not ascending: %v", rule, x)
+				break
+			}
+		}
+		for i := range x {
+			xs, ws := rule.FixedLocationSingle(n, i, -2, 3)
+			if xs != x[i] || ws != w[i] {
+				t.Errorf("%T: single location mismatch at %d", rule, i)
+			}
+		}
+	}
+}
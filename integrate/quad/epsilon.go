@@ -0,0 +1,135 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// limexp is the maximum number of elements the epsilon table can contain.
+// If this number is reached, the upper diagonal of the table is deleted.
+const limexp = 50
+
+// epsilonTable implements the Wynn epsilon algorithm for the extrapolation
+// of a sequence of approximations to its limit. It is a port of the QUADPACK
+// routine DQELG.
+type epsilonTable struct {
+	// tab holds the lower diagonal of the triangular epsilon table.
+	// It uses one-based indexing to match the reference implementation
+	// and includes two elements of scratch space.
+	tab [limexp + 3]float64
+	n   int
+
+	// last3 holds the last three extrapolated values.
+	last3 [3]float64
+	nres  int
+}
+
+// push appends v to the sequence being extrapolated.
+func (e *epsilonTable) push(v float64) {
+	e.n++
+	e.tab[e.n] = v
+}
+
+// len returns the number of elements in the table.
+func (e *epsilonTable) len() int {
+	return e.n
+}
+
+// extrapolate returns the extrapolated limit of the sequence and an
+// estimate of its absolute error, determined from the last three
+// extrapolated values.
+func (e *epsilonTable) extrapolate() (result, abserr float64) {
+	e.nres++
+	abserr = oflow
+	n := e.n
+	result = e.tab[n]
+	if n < 3 {
+		return result, math.Max(abserr, 5*epmach*math.Abs(result))
+	}
+
+	e.tab[n+2] = e.tab[n]
+	newelm := (n - 1) / 2
+	e.tab[n] = oflow
+	num := n
+	k1 := n
+	for i := 1; i <= newelm; i++ {
+		k2 := k1 - 1
+		k3 := k1 - 2
+		res := e.tab[k1+2]
+		e0 := e.tab[k3]
+		e1 := e.tab[k2]
+		e2 := res
+		e1abs := math.Abs(e1)
+		delta2 := e2 - e1
+		err2 := math.Abs(delta2)
+		tol2 := math.Max(math.Abs(e2), e1abs) * epmach
+		delta3 := e1 - e0
+		err3 := math.Abs(delta3)
+		tol3 := math.Max(e1abs, math.Abs(e0)) * epmach
+		if err2 <= tol2 && err3 <= tol3 {
+			// e0, e1 and e2 are equal to within machine
+			// accuracy, so convergence is assumed.
+			e.n = n
+			return res, math.Max(err2+err3, 5*epmach*math.Abs(res))
+		}
+
+		e3 := e.tab[k1]
+		e.tab[k1] = e1
+		delta1 := e1 - e3
+		err1 := math.Abs(delta1)
+		tol1 := math.Max(e1abs, math.Abs(e3)) * epmach
+		if err1 <= tol1 || err2 <= tol2 || err3 <= tol3 {
+			// Two elements are very close to each other, so
+			// omit a part of the table by adjusting its size.
+			n = 2*i - 1
+			break
+		}
+		ss := 1/delta1 + 1/delta2 - 1/delta3
+		if math.Abs(ss*e1) <= 1e-4 {
+			// The table is irregular.
+			n = 2*i - 1
+			break
+		}
+		res = e1 + 1/ss
+		e.tab[k1] = res
+		k1 -= 2
+		error := err2 + math.Abs(res-e2) + err3
+		if error <= abserr {
+			abserr = error
+			result = res
+		}
+	}
+
+	// Shift the table.
+	if n == limexp {
+		n = 2*(limexp/2) - 1
+	}
+	ib := 1
+	if num%2 == 0 {
+		ib = 2
+	}
+	for i := 1; i <= newelm+1; i++ {
+		e.tab[ib] = e.tab[ib+2]
+		ib += 2
+	}
+	if num != n {
+		indx := num - n + 1
+		for i := 1; i <= n; i++ {
+			e.tab[i] = e.tab[indx]
+			indx++
+		}
+	}
+	e.n = n
+
+	if e.nres < 4 {
+		e.last3[e.nres-1] = result
+		abserr = oflow
+	} else {
+		abserr = math.Abs(result-e.last3[2]) + math.Abs(result-e.last3[1]) + math.Abs(result-e.last3[0])
+		e.last3[0] = e.last3[1]
+		e.last3[1] = e.last3[2]
+		e.last3[2] = result
+	}
+	return result, math.Max(abserr, 5*epmach*math.Abs(result))
+}
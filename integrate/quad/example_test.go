@@ -45,3 +45,16 @@ func Example() {
 	// Estimate using parallel evaluations of f.
 	// EV = 4.19064
 }
+
+func ExampleAdaptive() {
+	// The integrand has an integrable singularity at zero.
+	f := func(x float64) float64 { return math.Log(x) / math.Sqrt(x) }
+	res := quad.Adaptive(f, 0, 1, &quad.Settings{AbsTol: 1e-10}, 0)
+	fmt.Printf("∫_0^1 log(x)/sqrt(x)dx = %.10f, status %v\n", res.Value, res.Status)
+
+	res = quad.TanhSinh(f, 0, 1, &quad.Settings{AbsTol: 1e-10}, 0)
+	fmt.Printf("tanh-sinh estimate = %.10f, status %v\n", res.Value, res.Status)
+	// Output:
+	// ∫_0^1 log(x)/sqrt(x)dx = -4.0000000000, status Success
+	// tanh-sinh estimate = -4.0000000000, status Success
+}
@@ -0,0 +1,94 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// golubWelsch computes the nodes and weights of the len(x)-point Gauss rule
+// for the weight function whose monic orthogonal polynomials satisfy the
+// three-term recurrence
+//  p_{k+1}(x) = (x - a_k) p_k(x) - b_k p_{k-1}(x),
+// where mu0 is the integral of the weight function. The nodes are the
+// eigenvalues of the symmetric tridiagonal Jacobi matrix with diagonal a and
+// off-diagonal sqrt(b[1:]), and the weights are mu0 times the squared first
+// components of the normalized eigenvectors. The nodes are returned in
+// ascending order.
+//
+// References:
+//  G. H. Golub and J. A. Welsch, "Calculation of Gauss quadrature rules",
+//  Math. Comp. 23:221-230, 1969.
+func golubWelsch(x, weight, a, b []float64, mu0 float64) {
+	n := len(x)
+	if n == 0 {
+		return
+	}
+	if n == 1 {
+		x[0] = a[0]
+		weight[0] = mu0
+		return
+	}
+	jacobi := mat.NewSymBandDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		jacobi.SetSymBand(i, i, a[i])
+		if i > 0 {
+			jacobi.SetSymBand(i-1, i, math.Sqrt(b[i]))
+		}
+	}
+	var eig mat.EigenSym
+	ok := eig.Factorize(jacobi, true)
+	if !ok {
+		panic("quad: eigendecomposition of Jacobi matrix failed")
+	}
+	eig.Values(x)
+	var vecs mat.Dense
+	eig.VectorsTo(&vecs)
+	for i := range weight {
+		v := vecs.At(0, i)
+		weight[i] = mu0 * v * v
+	}
+}
+
+// jacobiRecurrence returns the recurrence coefficients of the first n monic
+// Jacobi polynomials orthogonal with respect to (1-x)^alpha (1+x)^beta on
+// [-1, 1] and the integral of the weight function.
+func jacobiRecurrence(n int, alpha, beta float64) (a, b []float64, mu0 float64) {
+	a = make([]float64, n)
+	b = make([]float64, n)
+	ab := alpha + beta
+	if n > 0 {
+		a[0] = (beta - alpha) / (ab + 2)
+	}
+	if n > 1 {
+		b[1] = 4 * (1 + alpha) * (1 + beta) / ((2 + ab) * (2 + ab) * (3 + ab))
+	}
+	for k := 1; k < n; k++ {
+		fk := float64(k)
+		s := 2*fk + ab
+		a[k] = (beta*beta - alpha*alpha) / (s * (s + 2))
+		if k > 1 {
+			b[k] = 4 * fk * (fk + alpha) * (fk + beta) * (fk + ab) / (s * s * (s + 1) * (s - 1))
+		}
+	}
+	lg1, _ := math.Lgamma(alpha + 1)
+	lg2, _ := math.Lgamma(beta + 1)
+	lg3, _ := math.Lgamma(ab + 2)
+	mu0 = math.Exp((ab+1)*math.Ln2 + lg1 + lg2 - lg3)
+	return a, b, mu0
+}
+
+// legendrePoly returns the values of the Legendre polynomials of
+// degree n and n-1 at x.
+func legendrePoly(n int, x float64) (p, pm1 float64) {
+	p, pm1 = 1, 0
+	for k := 1; k <= n; k++ {
+		fk := float64(k)
+		p, pm1 = ((2*fk-1)*x*p-(fk-1)*pm1)/fk, p
+	}
+	return p, pm1
+}
@@ -0,0 +1,40 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// Jacobi generates sample locations and weights for performing quadrature
+// with a Jacobi weight over finite bounds
+//  int_min^max (max-x)^Alpha (x-min)^Beta f(x) dx .
+// Alpha and Beta must be greater than -1.
+type Jacobi struct {
+	Alpha, Beta float64
+}
+
+func (j Jacobi) FixedLocations(x, weight []float64, min, max float64) {
+	if len(x) != len(weight) {
+		panic("jacobi: slice length mismatch")
+	}
+	if min >= max {
+		panic("jacobi: min >= max")
+	}
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		panic("jacobi: infinite bound")
+	}
+	if !(j.Alpha > -1) || !(j.Beta > -1) {
+		panic("jacobi: parameter <= -1")
+	}
+	a, b, mu0 := jacobiRecurrence(len(x), j.Alpha, j.Beta)
+	golubWelsch(x, weight, a, b, mu0)
+
+	// Map from [-1, 1] to [min, max].
+	h := (max - min) / 2
+	scale := math.Pow(h, j.Alpha+j.Beta+1)
+	for i := range x {
+		x[i] = (x[i]+1)*h + min
+		weight[i] *= scale
+	}
+}
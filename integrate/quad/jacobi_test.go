@@ -0,0 +1,54 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mathext"
+)
+
+func TestJacobi(t *testing.T) {
+	t.Parallel()
+	const min, max = -1.5, 2.5
+	for _, test := range []struct{ alpha, beta float64 }{
+		{0, 0},
+		{0.5, 0.5},
+		{-0.5, -0.5},
+		{1, 0},
+		{-0.5, 0.5},
+		{2.5, -0.7},
+	} {
+		for _, n := range []int{1, 2, 5, 12} {
+			rule := Jacobi{Alpha: test.alpha, Beta: test.beta}
+			// ∫_min^max (max-x)^α (x-min)^(β+k) dx = L^(α+β+k+1) B(α+1, β+k+1).
+			for k := 0; k < 2*n; k++ {
+				f := func(x float64) float64 { return math.Pow(x-min, float64(k)) }
+				got := Fixed(f, min, max, n, rule, 0)
+				want := math.Pow(max-min, test.alpha+test.beta+float64(k)+1) *
+					mathext.Beta(test.alpha+1, test.beta+float64(k)+1)
+				if !floats.EqualWithinRel(got, want, 1e-11) {
+					t.Errorf("alpha=%v, beta=%v, n=%d, k=%d: mismatch: got %v, want %v",
+						test.alpha, test.beta, n, k, got, want)
+				}
+			}
+		}
+	}
+
+	// With α = β = 0 the rule is the Gauss–Legendre rule. Legendre
+	// returns the locations in descending order.
+	const n = 15
+	x := make([]float64, n)
+	w := make([]float64, n)
+	Jacobi{}.FixedLocations(x, w, min, max)
+	for i := range x {
+		xl, wl := Legendre{}.FixedLocationSingle(n, n-1-i, min, max)
+		if math.Abs(x[i]-xl) > 1e-13 || math.Abs(w[i]-wl) > 1e-12 {
+			t.Errorf("mismatch with Legendre at %d: got (%v, %v), want (%v, %v)", i, x[i], w[i], xl, wl)
+		}
+	}
+}
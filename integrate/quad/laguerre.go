@@ -0,0 +1,43 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// Laguerre generates sample locations and weights for performing quadrature
+// with a generalized Laguerre weight
+//  int_min^inf (x-min)^Alpha e^(-(x-min)) f(x) dx .
+// Alpha must be greater than -1, min must be finite and max must be +inf.
+type Laguerre struct {
+	Alpha float64
+}
+
+func (l Laguerre) FixedLocations(x, weight []float64, min, max float64) {
+	// The monic generalized Laguerre polynomials satisfy the recurrence
+	// with a_k = 2k+α+1 and b_k = k(k+α), and the integral of the weight
+	// is Γ(α+1).
+	if len(x) != len(weight) {
+		panic("laguerre: slice length mismatch")
+	}
+	if math.IsInf(min, 0) || !math.IsInf(max, 1) {
+		panic("laguerre: bad bounds")
+	}
+	if !(l.Alpha > -1) {
+		panic("laguerre: alpha <= -1")
+	}
+	n := len(x)
+	a := make([]float64, n)
+	b := make([]float64, n)
+	for k := range a {
+		fk := float64(k)
+		a[k] = 2*fk + l.Alpha + 1
+		b[k] = fk * (fk + l.Alpha)
+	}
+	mu0 := math.Gamma(l.Alpha + 1)
+	golubWelsch(x, weight, a, b, mu0)
+	for i := range x {
+		x[i] += min
+	}
+}
@@ -0,0 +1,43 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestLaguerre(t *testing.T) {
+	t.Parallel()
+	const min = 2
+	for _, alpha := range []float64{0, 0.5, -0.5, 2.3} {
+		for _, n := range []int{1, 2, 5, 10, 20} {
+			// The n-point rule is exact for polynomials of degree 2n-1.
+			for k := 0; k < 2*n; k++ {
+				f := func(x float64) float64 { return math.Pow(x-min, float64(k)) }
+				got := Fixed(f, min, math.Inf(1), n, Laguerre{Alpha: alpha}, 0)
+				want := math.Gamma(alpha + float64(k) + 1)
+				if !floats.EqualWithinRel(got, want, 1e-10) {
+					t.Errorf("alpha=%v, n=%d, k=%d: mismatch: got %v, want %v", alpha, n, k, got, want)
+				}
+			}
+		}
+	}
+
+	// Known nodes of the 3-point rule, the roots of L_3.
+	x := make([]float64, 3)
+	w := make([]float64, 3)
+	Laguerre{}.FixedLocations(x, w, 0, math.Inf(1))
+	wantX := []float64{0.4157745567834791, 2.294280360279042, 6.289945082937479}
+	wantW := []float64{0.7110930099291730, 0.2785177335692408, 0.0103892565015861}
+	if !floats.EqualApprox(x, wantX, 1e-13) {
+		t.Errorf("unexpected locations: got %v, want %v", x, wantX)
+	}
+	if !floats.EqualApprox(w, wantW, 1e-13) {
+		t.Errorf("unexpected weights: got %v, want %v", w, wantW)
+	}
+}
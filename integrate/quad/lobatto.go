@@ -0,0 +1,101 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// Lobatto integrates an unweighted function over finite bounds
+//  int_min^max f(x) dx
+// using Gauss–Lobatto quadrature, which includes min and max as quadrature
+// locations. An n-point Lobatto rule integrates polynomials of degree up to
+// 2n-3 exactly. At least two locations are required.
+type Lobatto struct{}
+
+func (Lobatto) FixedLocations(x, weight []float64, min, max float64) {
+	// The interior nodes are the zeros of P'_{n-1}, which are the nodes of
+	// the (n-2)-point Gauss–Jacobi rule with α = β = 1. The weights are
+	//  w_i = 2 / (n(n-1) P_{n-1}(x_i)^2).
+	if len(x) != len(weight) {
+		panic("lobatto: slice length mismatch")
+	}
+	if min >= max {
+		panic("lobatto: min >= max")
+	}
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		panic("lobatto: infinite bound")
+	}
+	n := len(x)
+	if n < 2 {
+		panic("lobatto: fewer than two locations")
+	}
+	a, b, mu0 := jacobiRecurrence(n-2, 1, 1)
+	golubWelsch(x[1:n-1], weight[1:n-1], a, b, mu0)
+	x[0] = -1
+	x[n-1] = 1
+	fn := float64(n)
+	for i, v := range x {
+		p, _ := legendrePoly(n-1, v)
+		weight[i] = 2 / (fn * (fn - 1) * p * p)
+	}
+	scaleLegendre(x, weight, min, max)
+}
+
+// Radau integrates an unweighted function over finite bounds
+//  int_min^max f(x) dx
+// using Gauss–Radau quadrature, which includes min as a quadrature location,
+// or max if Right is true. An n-point Radau rule integrates polynomials of
+// degree up to 2n-2 exactly.
+type Radau struct {
+	Right bool
+}
+
+func (r Radau) FixedLocations(x, weight []float64, min, max float64) {
+	// With the fixed node at -1, the remaining nodes are the nodes of the
+	// (n-1)-point Gauss–Jacobi rule with α = 0 and β = 1. The weights are
+	//  w_0 = 2/n^2,  w_i = (1-x_i) / (n^2 P_{n-1}(x_i)^2).
+	if len(x) != len(weight) {
+		panic("radau: slice length mismatch")
+	}
+	if min >= max {
+		panic("radau: min >= max")
+	}
+	if math.IsInf(min, 0) || math.IsInf(max, 0) {
+		panic("radau: infinite bound")
+	}
+	n := len(x)
+	if n == 0 {
+		return
+	}
+	a, b, mu0 := jacobiRecurrence(n-1, 0, 1)
+	golubWelsch(x[1:], weight[1:], a, b, mu0)
+	x[0] = -1
+	fn := float64(n)
+	weight[0] = 2 / (fn * fn)
+	for i := 1; i < n; i++ {
+		p, _ := legendrePoly(n-1, x[i])
+		weight[i] = (1 - x[i]) / (fn * fn * p * p)
+	}
+	if r.Right {
+		// Reflect the rule to fix the node at 1, keeping the
+		// locations in ascending order.
+		for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
+			x[i], x[j] = x[j], x[i]
+			weight[i], weight[j] = weight[j], weight[i]
+		}
+		for i := range x {
+			x[i] = -x[i]
+		}
+	}
+	scaleLegendre(x, weight, min, max)
+}
+
+// scaleLegendre maps the locations and weights of an unweighted rule
+// on [-1, 1] to [min, max].
+func scaleLegendre(x, weight []float64, min, max float64) {
+	for i := range x {
+		x[i] = (x[i]+1)/2*(max-min) + min
+		weight[i] *= (max - min) / 2
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/integrate/testquad"
+)
+
+func TestLobatto(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{2, 3, 4, 7, 16} {
+		x := make([]float64, n)
+		w := make([]float64, n)
+		Lobatto{}.FixedLocations(x, w, -1, 2)
+		if x[0] != -1 || x[n-1] != 2 {
+			t.Errorf("n=%d: end points not included: %v", n, x)
+		}
+		for deg := 0; deg <= 2*n-3; deg++ {
+			test := testquad.Poly(deg)
+			got := Fixed(test.F, test.A, test.B, n, Lobatto{}, 0)
+			if !floats.EqualWithinAbsOrRel(got, test.Value, 1e-12, 1e-12) {
+				t.Errorf("n=%d, %s: mismatch: got %v, want %v", n, test.Name, got, test.Value)
+			}
+		}
+	}
+
+	x := make([]float64, 3)
+	w := make([]float64, 3)
+	Lobatto{}.FixedLocations(x, w, -1, 1)
+	if !floats.EqualApprox(x, []float64{-1, 0, 1}, 1e-15) || !floats.EqualApprox(w, []float64{1.0 / 3, 4.0 / 3, 1.0 / 3}, 1e-15) {
+		t.Errorf("unexpected 3-point rule: x=%v, w=%v", x, w)
+	}
+}
+
+func TestRadau(t *testing.T) {
+	t.Parallel()
+	for _, right := range []bool{false, true} {
+		rule := Radau{Right: right}
+		for _, n := range []int{1, 2, 3, 6, 15} {
+			x := make([]float64, n)
+			w := make([]float64, n)
+			rule.FixedLocations(x, w, -1, 2)
+			if !right && x[0] != -1 {
+				t.Errorf("n=%d: min not included: %v", n, x)
+			}
+			if right && x[n-1] != 2 {
+				t.Errorf("n=%d: max not included: %v", n, x)
+			}
+			for deg := 0; deg <= 2*n-2; deg++ {
+				test := testquad.Poly(deg)
+				got := Fixed(test.F, test.A, test.B, n, rule, 0)
+				if !floats.EqualWithinAbsOrRel(got, test.Value, 1e-12, 1e-12) {
+					t.Errorf("right=%t, n=%d, %s: mismatch: got %v, want %v", right, n, test.Name, got, test.Value)
+				}
+			}
+		}
+	}
+
+	// Known 2-point rule on [-1, 1]: nodes -1 and 1/3 with weights 1/2 and 3/2.
+	x := make([]float64, 2)
+	w := make([]float64, 2)
+	Radau{}.FixedLocations(x, w, -1, 1)
+	if math.Abs(x[1]-1.0/3) > 1e-15 || math.Abs(w[0]-0.5) > 1e-15 || math.Abs(w[1]-1.5) > 1e-15 {
+		t.Errorf("unexpected 2-point rule: x=%v, w=%v", x, w)
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import "math"
+
+// tanhSinhMax is the largest abscissa of the tanh-sinh rule in the
+// transformed variable. Beyond this, the weights underflow relative to
+// any integrand with an integrable end point singularity.
+const tanhSinhMax = 4
+
+// TanhSinh approximates the integral of the function f from min to max to
+// a requested accuracy using tanh-sinh (double exponential) quadrature.
+// The change of variables
+//  x = c + h tanh(π/2 sinh(t)),
+// where c and h are the centre and half-width of the interval, makes
+// the transformed integrand decay double exponentially, so the trapezoidal
+// rule in t converges rapidly even when f has integrable singularities at
+// the end points. f is never evaluated at min or max.
+//
+// The step size in t is halved at each level of refinement, reusing the
+// previous evaluations, until the difference between successive estimates
+// satisfies the tolerances in settings or the maximum number of levels is
+// reached. If settings is nil, the defaults described for Settings are used.
+// If either bound is infinite, the interval is mapped onto (0, 1] as
+// described for Adaptive.
+//
+// If concurrent <= 0, f is evaluated serially, while if concurrent > 0, f
+// may be evaluated with at most concurrent simultaneous evaluations.
+//
+// min must be less than or equal to max, otherwise TanhSinh will panic.
+func TanhSinh(f func(float64) float64, min, max float64, settings *Settings, concurrent int) Result {
+	if min > max {
+		panic("quad: min > max")
+	}
+	absTol, relTol, limit := settings.tolerances(defaultLevels)
+	if min == max {
+		return Result{Status: Success}
+	}
+	f, min, max = infiniteTransform(f, min, max)
+	hl := 0.5 * (max - min)
+	c := 0.5 * (min + max)
+
+	var (
+		x, fx, w []float64
+		evals    int
+	)
+	// sum returns the weighted sum of f at the abscissae t = k*h for
+	// k = start, start+step, ... up to tanhSinhMax, and their reflections.
+	sum := func(h float64, start, step int) float64 {
+		x, w = x[:0], w[:0]
+		for k := start; ; k += step {
+			t := float64(k) * h
+			if t > tanhSinhMax {
+				break
+			}
+			u := 0.5 * math.Pi * math.Sinh(t)
+			cu := math.Cosh(u)
+			wt := hl * 0.5 * math.Pi * math.Cosh(t) / (cu * cu)
+			if k == 0 {
+				x = append(x, c)
+				w = append(w, wt)
+				continue
+			}
+			// Compute the distance to the end points directly
+			// to avoid cancellation close to min and max.
+			d := hl * 2 / (1 + math.Exp(2*u))
+			if xl := min + d; xl != min {
+				x = append(x, xl)
+				w = append(w, wt)
+			}
+			if xr := max - d; xr != max {
+				x = append(x, xr)
+				w = append(w, wt)
+			}
+		}
+		if cap(fx) < len(x) {
+			fx = make([]float64, len(x))
+		}
+		fx = fx[:len(x)]
+		evaluate(f, x, fx, concurrent)
+		evals += len(x)
+		var s float64
+		for i, v := range fx {
+			s += w[i] * v
+		}
+		return s
+	}
+
+	h := 1.0
+	value := h * sum(h, 0, 1)
+	for level := 1; ; level++ {
+		h *= 0.5
+		next := 0.5*value + h*sum(h, 1, 2)
+		err := math.Abs(next - value)
+		value = next
+		if err <= math.Max(absTol, relTol*math.Abs(value)) {
+			return Result{Value: value, Error: err, FuncEvaluations: evals, Status: Success}
+		}
+		if level == limit {
+			return Result{Value: value, Error: err, FuncEvaluations: evals, Status: IterationLimit}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package quad
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestTanhSinh(t *testing.T) {
+	t.Parallel()
+	const tol = 1e-9
+	for _, test := range adaptiveIntegrals() {
+		for _, concurrent := range []int{0, 3} {
+			res := TanhSinh(test.F, test.A, test.B, nil, concurrent)
+			if res.Status != Success {
+				t.Errorf("%s, concurrent=%d: unexpected status: %v", test.Name, concurrent, res.Status)
+			}
+			if !floats.EqualWithinAbsOrRel(res.Value, test.Value, tol, tol) {
+				t.Errorf("%s, concurrent=%d: mismatch: want %v, got %v", test.Name, concurrent, test.Value, res.Value)
+			}
+		}
+	}
+}
+
+func TestTanhSinhLimit(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return math.Sin(50 * x) }
+	res := TanhSinh(f, 0, 10, &Settings{RelTol: 1e-12, Limit: 2}, 0)
+	if res.Status != IterationLimit {
+		t.Errorf("unexpected status: got %v, want %v", res.Status, IterationLimit)
+	}
+	res = TanhSinh(f, 0, 10, &Settings{RelTol: 1e-12, Limit: 20}, 0)
+	want := (1 - math.Cos(500)) / 50
+	if res.Status != Success || math.Abs(res.Value-want) > 1e-10 {
+		t.Errorf("unexpected result: got %v (%v), want %v", res.Value, res.Status, want)
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import "math"
+
+var (
+	_ FittablePredictor   = (*BSpline)(nil)
+	_ DerivativePredictor = (*BSpline)(nil)
+	_ Integrator          = (*BSpline)(nil)
+)
+
+// BSpline is a spline of arbitrary degree represented in the B-spline basis
+//  s(x) = \sum_i c_i B_{i,k}(x),
+// where B_{i,k} are the B-splines of degree k on a non-decreasing knot
+// sequence t. The spline is defined on [t_k, t_{n}], where n is the number
+// of coefficients.
+//
+// A BSpline may be constructed with NewBSpline, or by Fit with the Degree
+// field set, which interpolates the data with knots placed at averages of
+// the data abscissae.
+type BSpline struct {
+	// Degree is the degree of the interpolating spline computed by Fit.
+	// Degree must be at least one.
+	Degree int
+
+	degree int
+	knots  []float64
+	coeffs []float64
+}
+
+// NewBSpline returns a B-spline of the given degree with the given knots and
+// coefficients. The knots must be non-decreasing with len(knots) equal to
+// len(coeffs)+degree+1, and the interval of definition [knots[degree],
+// knots[len(coeffs)]] must not be empty. NewBSpline panics if these
+// conditions are not met. The knots and coefficients are copied.
+func NewBSpline(degree int, knots, coeffs []float64) *BSpline {
+	if degree < 0 {
+		panic("interp: negative degree")
+	}
+	if len(coeffs) < degree+1 {
+		panic(tooFewPoints)
+	}
+	if len(knots) != len(coeffs)+degree+1 {
+		panic("interp: knot and coefficient length mismatch")
+	}
+	for i := 1; i < len(knots); i++ {
+		if knots[i] < knots[i-1] {
+			panic("interp: knots not non-decreasing")
+		}
+	}
+	if !(knots[len(coeffs)] > knots[degree]) {
+		panic("interp: empty B-spline interval")
+	}
+	return &BSpline{
+		Degree: degree,
+		degree: degree,
+		knots:  append([]float64(nil), knots...),
+		coeffs: append([]float64(nil), coeffs...),
+	}
+}
+
+// Knots returns the knots of the spline. The returned slice must not be
+// modified.
+func (b *BSpline) Knots() []float64 {
+	return b.knots
+}
+
+// Coefficients returns the B-spline coefficients of the spline. The returned
+// slice must not be modified.
+func (b *BSpline) Coefficients() []float64 {
+	return b.coeffs
+}
+
+// Fit fits an interpolating spline of degree b.Degree to (X, Y) value pairs
+// provided as two slices. The knots are placed at
+//  t_{j+k+1} = (xs[j+1] + … + xs[j+k]) / k,  j = 0, …, n-k-2,
+// with k+1 knots at each of the first and last points, so that the
+// interpolation problem is well-posed. Fit panics if b.Degree is less than
+// one, len(xs) < b.Degree+1, elements of xs are not strictly increasing or
+// len(xs) != len(ys). Returns an error if solving the required system of
+// linear equations fails.
+//
+// References:
+//  C. de Boor, "A Practical Guide to Splines", revised edition, Springer, 2001.
+func (b *BSpline) Fit(xs, ys []float64) error {
+	k := b.Degree
+	if k < 1 {
+		panic("interp: B-spline degree less than one")
+	}
+	checkXs(xs, ys, k+1)
+	n := len(xs)
+
+	knots := make([]float64, n+k+1)
+	for i := 0; i <= k; i++ {
+		knots[i] = xs[0]
+		knots[n+i] = xs[n-1]
+	}
+	for j := 0; j < n-k-1; j++ {
+		var sum float64
+		for _, x := range xs[j+1 : j+k+1] {
+			sum += x
+		}
+		knots[j+k+1] = sum / float64(k)
+	}
+
+	// The collocation matrix is banded with k sub- and super-diagonals
+	// and totally positive, so Gaussian elimination without pivoting is
+	// stable.
+	w := 2*k + 1
+	band := make([]float64, n*w)
+	basis := make([]float64, k+1)
+	for i, x := range xs {
+		l := findInterval(knots, k, n, x)
+		bsplineBasis(basis, knots, k, l, x)
+		for r, v := range basis {
+			j := l - k + r
+			if v == 0 {
+				continue
+			}
+			if j < i-k || i+k < j {
+				panic("interp: collocation matrix outside band")
+			}
+			band[i*w+j-i+k] = v
+		}
+	}
+	coeffs := append([]float64(nil), ys...)
+	if !solveBand(band, n, k, coeffs) {
+		return errSingular
+	}
+
+	b.degree = k
+	b.knots = knots
+	b.coeffs = coeffs
+	return nil
+}
+
+// Predict returns the value of the spline at x.
+func (b *BSpline) Predict(x float64) float64 {
+	return b.predict(b.clamp(x))
+}
+
+// PredictDerivative returns the first derivative of the spline at x.
+func (b *BSpline) PredictDerivative(x float64) float64 {
+	k := b.degree
+	if k == 0 || x < b.lo() || x > b.hi() {
+		return 0
+	}
+	// The derivative is a spline of degree k-1 on the knots without the
+	// first and the last, whose coefficients are scaled differences of
+	// the coefficients of b. Only the k coefficients that are non-zero
+	// at x are computed.
+	t := b.knots
+	l := findInterval(t, k, len(b.coeffs), x)
+	d := make([]float64, k)
+	for j := range d {
+		i := l - k + j
+		if dt := t[i+k+1] - t[i+1]; dt > 0 {
+			d[j] = float64(k) * (b.coeffs[i+1] - b.coeffs[i]) / dt
+		}
+	}
+	return deBoor(d, t[1:], k-1, l-1, x)
+}
+
+// Derivative returns the spline of degree k-order that is the derivative of
+// the given order of the receiver. Derivative panics if order is negative.
+// If order exceeds the degree of the spline, the zero spline is returned.
+func (b *BSpline) Derivative(order int) *BSpline {
+	if order < 0 {
+		panic("interp: negative derivative order")
+	}
+	knots := b.knots
+	coeffs := b.coeffs
+	k := b.degree
+	for ; order > 0; order-- {
+		if k == 0 {
+			// The derivative of a piecewise constant spline is zero.
+			zero := make([]float64, len(coeffs))
+			return NewBSpline(0, knots, zero)
+		}
+		d := make([]float64, len(coeffs)-1)
+		for i := range d {
+			dt := knots[i+k+1] - knots[i+1]
+			if dt > 0 {
+				d[i] = float64(k) * (coeffs[i+1] - coeffs[i]) / dt
+			}
+		}
+		knots = knots[1 : len(knots)-1]
+		coeffs = d
+		k--
+	}
+	return NewBSpline(k, knots, coeffs)
+}
+
+// Integrate returns the integral of the spline from a to b.
+func (b *BSpline) Integrate(a, c float64) float64 {
+	// The antiderivative is a spline of degree k+1 on the knots extended
+	// by one at each end.
+	k := b.degree
+	n := len(b.coeffs)
+	knots := make([]float64, len(b.knots)+2)
+	copy(knots[1:], b.knots)
+	knots[0] = b.knots[0]
+	knots[len(knots)-1] = b.knots[len(b.knots)-1]
+	coeffs := make([]float64, n+1)
+	for i := 0; i < n; i++ {
+		coeffs[i+1] = coeffs[i] + b.coeffs[i]*(b.knots[i+k+1]-b.knots[i])/float64(k+1)
+	}
+	anti := BSpline{degree: k + 1, knots: knots, coeffs: coeffs}
+	lo, hi := b.lo(), b.hi()
+	return integrateExtended(anti.predict, lo, hi, b.predict(lo), b.predict(hi), a, c)
+}
+
+func (b *BSpline) lo() float64 { return b.knots[b.degree] }
+func (b *BSpline) hi() float64 { return b.knots[len(b.coeffs)] }
+
+// clamp returns x clamped to the interval of definition of the spline.
+func (b *BSpline) clamp(x float64) float64 {
+	return math.Max(b.lo(), math.Min(x, b.hi()))
+}
+
+// predict returns the value of the spline at x within the interval of
+// definition.
+func (b *BSpline) predict(x float64) float64 {
+	k := b.degree
+	l := findInterval(b.knots, k, len(b.coeffs), x)
+	d := make([]float64, k+1)
+	copy(d, b.coeffs[l-k:l+1])
+	return deBoor(d, b.knots, k, l, x)
+}
+
+// deBoor returns the value at x in the knot interval [t[l], t[l+1]] of the
+// spline of degree k with the knots t, using de Boor's algorithm. d holds the
+// k+1 coefficients l-k, …, l of the spline and is overwritten.
+func deBoor(d, t []float64, k, l int, x float64) float64 {
+	for r := 1; r <= k; r++ {
+		for j := k; j >= r; j-- {
+			i := l - k + j
+			den := t[i+k+1-r] - t[i]
+			var alpha float64
+			if den > 0 {
+				alpha = (x - t[i]) / den
+			}
+			d[j] = (1-alpha)*d[j-1] + alpha*d[j]
+		}
+	}
+	return d[k]
+}
+
+// findInterval returns the index l with k <= l < n such that
+// t[l] <= x < t[l+1], with x equal to t[n] in the last non-empty interval.
+func findInterval(t []float64, k, n int, x float64) int {
+	if x >= t[n] {
+		l := n - 1
+		for t[l] == t[l+1] {
+			l--
+		}
+		return l
+	}
+	l := findSegment(t[:n], x)
+	if l < k {
+		l = k
+	}
+	return l
+}
+
+// bsplineBasis stores the values of the k+1 B-splines of degree k that
+// are non-zero on the knot interval [t[l], t[l+1]) at x into dst.
+func bsplineBasis(dst, t []float64, k, l int, x float64) {
+	dst[0] = 1
+	for j := 1; j <= k; j++ {
+		var saved float64
+		for r := 0; r < j; r++ {
+			tr := t[l+r+1]
+			tl := t[l+r+1-j]
+			var term float64
+			if tr > tl {
+				term = dst[r] / (tr - tl)
+			}
+			dst[r] = saved + (tr-x)*term
+			saved = (x - tl) * term
+		}
+		dst[j] = saved
+	}
+}
+
+// solveBand solves the n×n band system with k sub- and super-diagonals by
+// Gaussian elimination without pivoting, storing the solution in-place
+// into b. Row i of the matrix holds the columns i-k to i+k in
+// a[i*(2k+1):(i+1)*(2k+1)]. The contents of a are overwritten. solveBand
+// returns false if a zero pivot is encountered.
+func solveBand(a []float64, n, k int, b []float64) bool {
+	w := 2*k + 1
+	at := func(i, j int) *float64 { return &a[i*w+j-i+k] }
+	for p := 0; p < n; p++ {
+		piv := *at(p, p)
+		if piv == 0 {
+			return false
+		}
+		for i := p + 1; i <= min(n-1, p+k); i++ {
+			f := *at(i, p) / piv
+			if f == 0 {
+				continue
+			}
+			for j := p; j <= min(n-1, p+k); j++ {
+				*at(i, j) -= f * *at(p, j)
+			}
+			b[i] -= f * b[p]
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		v := b[i]
+		for j := i + 1; j <= min(n-1, i+k); j++ {
+			v -= *at(i, j) * b[j]
+		}
+		b[i] = v / *at(i, i)
+	}
+	return true
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,159 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestBSplineFit(t *testing.T) {
+	t.Parallel()
+	xs := []float64{-1, -0.6, -0.2, 0.5, 1, 1.7, 2.5, 3}
+	for _, test := range []struct {
+		degree int
+		f, df  func(float64) float64
+		F      func(float64) float64
+	}{
+		{degree: 1, f: linearPoly, df: linearDer, F: linearInt},
+		{degree: 2, f: linearPoly, df: linearDer, F: linearInt},
+		{degree: 3, f: cubicPoly, df: cubicDer, F: cubicInt},
+		{degree: 4, f: cubicPoly, df: cubicDer, F: cubicInt},
+		{degree: 5, f: cubicPoly, df: cubicDer, F: cubicInt},
+	} {
+		for n := test.degree + 1; n <= len(xs); n++ {
+			x := xs[:n]
+			b := BSpline{Degree: test.degree}
+			if err := b.Fit(x, sample(test.f, x)); err != nil {
+				t.Errorf("degree %d: unexpected error for %d points: %v", test.degree, n, err)
+				continue
+			}
+			if len(b.Knots()) != n+test.degree+1 || len(b.Coefficients()) != n {
+				t.Errorf("degree %d: unexpected spline size: %d knots, %d coefficients",
+					test.degree, len(b.Knots()), len(b.Coefficients()))
+			}
+			testPredictor(t, "BSpline", &b, test.f, test.df, test.F, x[0], x[n-1], 1e-10)
+		}
+	}
+}
+
+func TestBSplineLinear(t *testing.T) {
+	t.Parallel()
+	// A B-spline of degree one interpolates linearly.
+	xs := []float64{0, 1, 3, 4, 7}
+	ys := []float64{1, -2, 0.5, 3, 2}
+	b := BSpline{Degree: 1}
+	if err := b.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var pl PiecewiseLinear
+	if err := pl.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i <= 100; i++ {
+		x := -1 + 9*float64(i)/100
+		if got, want := b.Predict(x), pl.Predict(x); math.Abs(got-want) > 1e-14 {
+			t.Errorf("unexpected value at x=%v: got %v, want %v", x, got, want)
+		}
+		if got, want := b.Integrate(0, x), pl.Integrate(0, x); math.Abs(got-want) > 1e-13 {
+			t.Errorf("unexpected integral at x=%v: got %v, want %v", x, got, want)
+		}
+	}
+}
+
+func TestBSplineBezier(t *testing.T) {
+	t.Parallel()
+	// With knots clamped at 0 and 1 and no interior knots, a B-spline
+	// is a Bézier curve with the coefficients as control points.
+	coeffs := []float64{1, -1, 3, 2}
+	b := NewBSpline(3, []float64{0, 0, 0, 0, 1, 1, 1, 1}, coeffs)
+	bezier := func(x float64) float64 {
+		u := 1 - x
+		return coeffs[0]*u*u*u + 3*coeffs[1]*u*u*x + 3*coeffs[2]*u*x*x + coeffs[3]*x*x*x
+	}
+	dBezier := func(x float64) float64 {
+		u := 1 - x
+		return 3 * ((coeffs[1]-coeffs[0])*u*u + 2*(coeffs[2]-coeffs[1])*u*x + (coeffs[3]-coeffs[2])*x*x)
+	}
+	// The integral of each Bernstein polynomial of degree 3 over [0, 1]
+	// is 1/4.
+	if got, want := b.Integrate(0, 1), floats.Sum(coeffs)/4; math.Abs(got-want) > 1e-14 {
+		t.Errorf("unexpected integral: got %v, want %v", got, want)
+	}
+	testPredictor(t, "Bézier", b, bezier, dBezier, nil, 0, 1, 1e-14)
+
+	// Second and third derivatives.
+	d2 := b.Derivative(2)
+	d3 := b.Derivative(3)
+	for _, x := range []float64{0, 0.3, 0.7, 1} {
+		u := 1 - x
+		want2 := 6 * ((coeffs[2]-2*coeffs[1]+coeffs[0])*u + (coeffs[3]-2*coeffs[2]+coeffs[1])*x)
+		if got := d2.Predict(x); math.Abs(got-want2) > 1e-13 {
+			t.Errorf("unexpected second derivative at x=%v: got %v, want %v", x, got, want2)
+		}
+		want3 := 6 * (coeffs[3] - 3*coeffs[2] + 3*coeffs[1] - coeffs[0])
+		if got := d3.Predict(x); math.Abs(got-want3) > 1e-13 {
+			t.Errorf("unexpected third derivative at x=%v: got %v, want %v", x, got, want3)
+		}
+	}
+	if d4 := b.Derivative(4); d4.Predict(0.5) != 0 {
+		t.Errorf("unexpected non-zero fourth derivative: %v", d4.Predict(0.5))
+	}
+}
+
+func TestBSplinePredictDerivative(t *testing.T) {
+	t.Parallel()
+	// PredictDerivative must agree with the derivative spline, including at
+	// the knots, and must be safe for concurrent use.
+	xs := []float64{-1, -0.6, -0.2, 0.5, 1, 1.7, 2.5, 3}
+	for degree := 1; degree <= 5; degree++ {
+		b := BSpline{Degree: degree}
+		if err := b.Fit(xs, sample(cubicPoly, xs)); err != nil {
+			t.Fatalf("degree %d: unexpected error: %v", degree, err)
+		}
+		d := b.Derivative(1)
+		var x []float64
+		for i := 0; i <= 100; i++ {
+			x = append(x, -1+4*float64(i)/100)
+		}
+		x = append(x, b.Knots()...)
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, v := range x {
+					if got, want := b.PredictDerivative(v), d.Predict(v); math.Abs(got-want) > 1e-12 {
+						t.Errorf("degree %d: unexpected derivative at x=%v: got %v, want %v", degree, v, got, want)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
+
+func TestBSplinePanics(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "zero degree fit", fn: func() { (&BSpline{}).Fit([]float64{0, 1}, []float64{0, 1}) }},
+		{name: "too few points", fn: func() { (&BSpline{Degree: 3}).Fit([]float64{0, 1, 2}, []float64{0, 1, 2}) }},
+		{name: "negative degree", fn: func() { NewBSpline(-1, []float64{0}, nil) }},
+		{name: "knot length", fn: func() { NewBSpline(1, []float64{0, 0, 1}, []float64{1, 2}) }},
+		{name: "decreasing knots", fn: func() { NewBSpline(1, []float64{0, 0, 2, 1}, []float64{1, 2}) }},
+		{name: "empty interval", fn: func() { NewBSpline(1, []float64{0, 1, 1, 2}, []float64{1, 2}) }},
+		{name: "negative order", fn: func() { NewBSpline(1, []float64{0, 0, 1, 1}, []float64{1, 2}).Derivative(-1) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"errors"
+	"math"
+)
+
+var (
+	_ DerivativePredictor = (*PiecewiseCubic)(nil)
+	_ Integrator          = (*PiecewiseCubic)(nil)
+
+	_ FittablePredictor   = (*NaturalCubic)(nil)
+	_ DerivativePredictor = (*NaturalCubic)(nil)
+	_ Integrator          = (*NaturalCubic)(nil)
+
+	_ FittablePredictor   = (*ClampedCubic)(nil)
+	_ DerivativePredictor = (*ClampedCubic)(nil)
+	_ Integrator          = (*ClampedCubic)(nil)
+
+	_ FittablePredictor   = (*NotAKnotCubic)(nil)
+	_ DerivativePredictor = (*NotAKnotCubic)(nil)
+	_ Integrator          = (*NotAKnotCubic)(nil)
+
+	_ FittablePredictor   = (*AkimaSpline)(nil)
+	_ DerivativePredictor = (*AkimaSpline)(nil)
+	_ Integrator          = (*AkimaSpline)(nil)
+
+	_ FittablePredictor   = (*PCHIP)(nil)
+	_ DerivativePredictor = (*PCHIP)(nil)
+	_ Integrator          = (*PCHIP)(nil)
+)
+
+var errSingular = errors.New("interp: singular spline system")
+
+// PiecewiseCubic is a piecewise cubic 1-dimensional interpolator with
+// continuous value and first derivative.
+type PiecewiseCubic struct {
+	xs []float64
+	// coeffs[i] holds the coefficients of the polynomial
+	//  coeffs[i][0] + coeffs[i][1]*t + coeffs[i][2]*t^2 + coeffs[i][3]*t^3,
+	// with t = x - xs[i], on the interval [xs[i], xs[i+1]].
+	coeffs [][4]float64
+	// cum[i] is the integral from xs[0] to xs[i].
+	cum []float64
+	// lastY is the value at the last node.
+	lastY float64
+}
+
+// FitWithDerivatives fits a piecewise cubic predictor to (X, Y, dY/dX) value
+// triples provided as three slices. It panics if len(xs) < 2, elements of xs
+// are not strictly increasing, len(xs) != len(ys) or len(xs) != len(dydxs).
+func (pc *PiecewiseCubic) FitWithDerivatives(xs, ys, dydxs []float64) {
+	checkXs(xs, ys, 2)
+	if len(dydxs) != len(xs) {
+		panic(differentLengths)
+	}
+	n := len(xs)
+	pc.xs = append(pc.xs[:0], xs...)
+	pc.coeffs = pc.coeffs[:0]
+	pc.cum = append(pc.cum[:0], 0)
+	for i := 0; i < n-1; i++ {
+		h := xs[i+1] - xs[i]
+		m := (ys[i+1] - ys[i]) / h
+		c := [4]float64{
+			ys[i],
+			dydxs[i],
+			(3*m - 2*dydxs[i] - dydxs[i+1]) / h,
+			(dydxs[i] + dydxs[i+1] - 2*m) / (h * h),
+		}
+		pc.coeffs = append(pc.coeffs, c)
+		pc.cum = append(pc.cum, pc.cum[i]+h*(c[0]+h*(c[1]/2+h*(c[2]/3+h*c[3]/4))))
+	}
+	pc.lastY = ys[n-1]
+}
+
+// fitWithSecondDerivatives fits a piecewise cubic predictor to (X, Y,
+// d^2Y/dX^2) value triples provided as three slices. The slices are assumed
+// to be valid.
+func (pc *PiecewiseCubic) fitWithSecondDerivatives(xs, ys, d2ydx2s []float64) {
+	n := len(xs)
+	dydxs := make([]float64, n)
+	for i := 0; i < n-1; i++ {
+		h := xs[i+1] - xs[i]
+		dydxs[i] = (ys[i+1]-ys[i])/h - h*(2*d2ydx2s[i]+d2ydx2s[i+1])/6
+	}
+	h := xs[n-1] - xs[n-2]
+	dydxs[n-1] = (ys[n-1]-ys[n-2])/h + h*(d2ydx2s[n-2]+2*d2ydx2s[n-1])/6
+	pc.FitWithDerivatives(xs, ys, dydxs)
+}
+
+// Predict returns the interpolation value at x.
+func (pc *PiecewiseCubic) Predict(x float64) float64 {
+	i := findSegment(pc.xs, x)
+	switch {
+	case i < 0:
+		return pc.coeffs[0][0]
+	case i == len(pc.xs)-1:
+		return pc.lastY
+	}
+	c := &pc.coeffs[i]
+	t := x - pc.xs[i]
+	return c[0] + t*(c[1]+t*(c[2]+t*c[3]))
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (pc *PiecewiseCubic) PredictDerivative(x float64) float64 {
+	i := findSegment(pc.xs, x)
+	if i < 0 || i == len(pc.xs)-1 {
+		return 0
+	}
+	c := &pc.coeffs[i]
+	t := x - pc.xs[i]
+	return c[1] + t*(2*c[2]+t*3*c[3])
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (pc *PiecewiseCubic) Integrate(a, b float64) float64 {
+	n := len(pc.xs)
+	return integrateExtended(func(x float64) float64 {
+		i := findSegment(pc.xs, x)
+		if i == n-1 {
+			return pc.cum[i]
+		}
+		c := &pc.coeffs[i]
+		t := x - pc.xs[i]
+		return pc.cum[i] + t*(c[0]+t*(c[1]/2+t*(c[2]/3+t*c[3]/4)))
+	}, pc.xs[0], pc.xs[n-1], pc.coeffs[0][0], pc.lastY, a, b)
+}
+
+// splineSlopes returns the slopes of the cubic spline interpolating (xs, ys)
+// with the end conditions set by the first and last rows of the tridiagonal
+// system for the slopes, given by fn. fn receives the interval lengths and
+// the secant slopes and returns the diagonal, super-diagonal and right-hand
+// side of the first row, followed by the sub-diagonal, diagonal and
+// right-hand side of the last row.
+func splineSlopes(xs, ys []float64, ends func(dx, m []float64) (d0, du0, b0, dln, dn, bn float64)) ([]float64, error) {
+	n := len(xs)
+	dx := make([]float64, n-1)
+	m := make([]float64, n-1)
+	for i := range dx {
+		dx[i] = xs[i+1] - xs[i]
+		m[i] = (ys[i+1] - ys[i]) / dx[i]
+	}
+	dl := make([]float64, n-1)
+	d := make([]float64, n)
+	du := make([]float64, n-1)
+	b := make([]float64, n)
+	for i := 1; i < n-1; i++ {
+		dl[i-1] = dx[i]
+		d[i] = 2 * (dx[i-1] + dx[i])
+		du[i] = dx[i-1]
+		b[i] = 3 * (dx[i]*m[i-1] + dx[i-1]*m[i])
+	}
+	d[0], du[0], b[0], dl[n-2], d[n-1], b[n-1] = ends(dx, m)
+	if !solveTridiagonal(dl, d, du, b) {
+		return nil, errSingular
+	}
+	return b, nil
+}
+
+// NaturalCubic is a piecewise cubic 1-dimensional interpolator with
+// continuous value, first and second derivatives, which fits the data
+// exactly, and whose second derivative is zero at the end points.
+type NaturalCubic struct {
+	cubic PiecewiseCubic
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Returns an error if solving the required system
+// of linear equations fails.
+func (nc *NaturalCubic) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	dydxs, err := splineSlopes(xs, ys, func(dx, m []float64) (d0, du0, b0, dln, dn, bn float64) {
+		k := len(m) - 1
+		return 2, 1, 3 * m[0], 1, 2, 3 * m[k]
+	})
+	if err != nil {
+		return err
+	}
+	nc.cubic.FitWithDerivatives(xs, ys, dydxs)
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (nc *NaturalCubic) Predict(x float64) float64 {
+	return nc.cubic.Predict(x)
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (nc *NaturalCubic) PredictDerivative(x float64) float64 {
+	return nc.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (nc *NaturalCubic) Integrate(a, b float64) float64 {
+	return nc.cubic.Integrate(a, b)
+}
+
+// ClampedCubic is a piecewise cubic 1-dimensional interpolator with
+// continuous value, first and second derivatives, which fits the data
+// exactly, and whose first derivatives at the end points are given by
+// Left and Right.
+type ClampedCubic struct {
+	// Left and Right are the first derivatives at the first
+	// and last points.
+	Left, Right float64
+
+	cubic PiecewiseCubic
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Returns an error if solving the required system
+// of linear equations fails.
+func (cc *ClampedCubic) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	dydxs, err := splineSlopes(xs, ys, func(_, _ []float64) (d0, du0, b0, dln, dn, bn float64) {
+		return 1, 0, cc.Left, 0, 1, cc.Right
+	})
+	if err != nil {
+		return err
+	}
+	cc.cubic.FitWithDerivatives(xs, ys, dydxs)
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (cc *ClampedCubic) Predict(x float64) float64 {
+	return cc.cubic.Predict(x)
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (cc *ClampedCubic) PredictDerivative(x float64) float64 {
+	return cc.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (cc *ClampedCubic) Integrate(a, b float64) float64 {
+	return cc.cubic.Integrate(a, b)
+}
+
+// NotAKnotCubic is a piecewise cubic 1-dimensional interpolator with
+// continuous value, first and second derivatives, which fits the data
+// exactly, and whose third derivative is continuous at the second and
+// second-to-last points. With three points, the interpolant is the
+// interpolating parabola, and with two points it is a straight line.
+type NotAKnotCubic struct {
+	cubic PiecewiseCubic
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Returns an error if solving the required system
+// of linear equations fails.
+func (nak *NotAKnotCubic) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	n := len(xs)
+	var dydxs []float64
+	switch n {
+	case 2:
+		m := (ys[1] - ys[0]) / (xs[1] - xs[0])
+		dydxs = []float64{m, m}
+	case 3:
+		// The derivatives of the interpolating parabola.
+		h0 := xs[1] - xs[0]
+		h1 := xs[2] - xs[1]
+		m0 := (ys[1] - ys[0]) / h0
+		m1 := (ys[2] - ys[1]) / h1
+		c := (m1 - m0) / (h0 + h1)
+		dydxs = []float64{m0 - c*h0, m0 + c*h0, m1 + c*h1}
+	default:
+		var err error
+		dydxs, err = splineSlopes(xs, ys, func(dx, m []float64) (d0, du0, b0, dln, dn, bn float64) {
+			k := len(m) - 1
+			w0 := dx[0] + dx[1]
+			wn := dx[k] + dx[k-1]
+			return dx[1], w0, ((dx[0]+2*w0)*dx[1]*m[0] + dx[0]*dx[0]*m[1]) / w0,
+				wn, dx[k-1], (dx[k]*dx[k]*m[k-1] + (2*wn+dx[k])*dx[k-1]*m[k]) / wn
+		})
+		if err != nil {
+			return err
+		}
+	}
+	nak.cubic.FitWithDerivatives(xs, ys, dydxs)
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (nak *NotAKnotCubic) Predict(x float64) float64 {
+	return nak.cubic.Predict(x)
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (nak *NotAKnotCubic) PredictDerivative(x float64) float64 {
+	return nak.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (nak *NotAKnotCubic) Integrate(a, b float64) float64 {
+	return nak.cubic.Integrate(a, b)
+}
+
+// AkimaSpline is a piecewise cubic 1-dimensional interpolator with
+// continuous value and first derivative, which fits the data exactly.
+// The derivatives are computed from local secant slopes with weights that
+// reduce oscillations near outliers.
+//
+// References:
+//  H. Akima, "A new method of interpolation and smooth curve fitting based
+//  on local procedures", J. ACM 17(4):589-602, 1970.
+type AkimaSpline struct {
+	cubic PiecewiseCubic
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Always returns nil.
+func (as *AkimaSpline) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	n := len(xs)
+	dydxs := make([]float64, n)
+	if n == 2 {
+		m := (ys[1] - ys[0]) / (xs[1] - xs[0])
+		dydxs[0], dydxs[1] = m, m
+		as.cubic.FitWithDerivatives(xs, ys, dydxs)
+		return nil
+	}
+
+	// m[i+2] holds the secant slope of the ith interval, with two slopes
+	// extrapolated linearly at each end.
+	m := make([]float64, n+3)
+	for i := 0; i < n-1; i++ {
+		m[i+2] = (ys[i+1] - ys[i]) / (xs[i+1] - xs[i])
+	}
+	m[1] = 2*m[2] - m[3]
+	m[0] = 2*m[1] - m[2]
+	m[n+1] = 2*m[n] - m[n-1]
+	m[n+2] = 2*m[n+1] - m[n]
+	for i := range dydxs {
+		wl := math.Abs(m[i+1] - m[i])
+		wr := math.Abs(m[i+3] - m[i+2])
+		if wl+wr == 0 {
+			dydxs[i] = (m[i+1] + m[i+2]) / 2
+		} else {
+			dydxs[i] = (wr*m[i+1] + wl*m[i+2]) / (wl + wr)
+		}
+	}
+	as.cubic.FitWithDerivatives(xs, ys, dydxs)
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (as *AkimaSpline) Predict(x float64) float64 {
+	return as.cubic.Predict(x)
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (as *AkimaSpline) PredictDerivative(x float64) float64 {
+	return as.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (as *AkimaSpline) Integrate(a, b float64) float64 {
+	return as.cubic.Integrate(a, b)
+}
+
+// PCHIP is a piecewise cubic Hermite 1-dimensional interpolator with
+// continuous value and first derivative, which fits the data exactly and
+// preserves monotonicity: the interpolant is monotone on every interval on
+// which the data are monotone. The derivatives are initialized to the
+// averages of the adjacent secant slopes and then limited as described by
+// Fritsch and Carlson.
+//
+// References:
+//  F. N. Fritsch and R. E. Carlson, "Monotone piecewise cubic interpolation",
+//  SIAM J. Numer. Anal. 17(2):238-246, 1980.
+type PCHIP struct {
+	cubic PiecewiseCubic
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Always returns nil.
+func (p *PCHIP) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	n := len(xs)
+	m := make([]float64, n-1)
+	for i := range m {
+		m[i] = (ys[i+1] - ys[i]) / (xs[i+1] - xs[i])
+	}
+	dydxs := make([]float64, n)
+	dydxs[0] = m[0]
+	dydxs[n-1] = m[n-2]
+	for i := 1; i < n-1; i++ {
+		if m[i-1]*m[i] > 0 {
+			dydxs[i] = (m[i-1] + m[i]) / 2
+		}
+	}
+	for i, mi := range m {
+		if mi == 0 {
+			dydxs[i] = 0
+			dydxs[i+1] = 0
+			continue
+		}
+		alpha := dydxs[i] / mi
+		beta := dydxs[i+1] / mi
+		if alpha < 0 {
+			dydxs[i] = 0
+			alpha = 0
+		}
+		if beta < 0 {
+			dydxs[i+1] = 0
+			beta = 0
+		}
+		if s := alpha*alpha + beta*beta; s > 9 {
+			tau := 3 / math.Sqrt(s)
+			dydxs[i] = tau * alpha * mi
+			dydxs[i+1] = tau * beta * mi
+		}
+	}
+	p.cubic.FitWithDerivatives(xs, ys, dydxs)
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (p *PCHIP) Predict(x float64) float64 {
+	return p.cubic.Predict(x)
+}
+
+// PredictDerivative returns the predicted derivative at x.
+func (p *PCHIP) PredictDerivative(x float64) float64 {
+	return p.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (p *PCHIP) Integrate(a, b float64) float64 {
+	return p.cubic.Integrate(a, b)
+}
+
+// solveTridiagonal solves the tridiagonal system of linear equations with
+// sub-diagonal dl, diagonal d and super-diagonal du by Gaussian elimination
+// with partial pivoting, storing the solution in-place into b. The contents
+// of dl, d and du are overwritten. solveTridiagonal returns false if the
+// system is singular.
+func solveTridiagonal(dl, d, du, b []float64) bool {
+	n := len(d)
+	for i := 0; i < n-1; i++ {
+		if math.Abs(d[i]) >= math.Abs(dl[i]) {
+			// No row interchange.
+			if d[i] == 0 {
+				return false
+			}
+			f := dl[i] / d[i]
+			d[i+1] -= f * du[i]
+			b[i+1] -= f * b[i]
+			// dl[i] holds the second super-diagonal of the factor.
+			dl[i] = 0
+			continue
+		}
+		// Interchange rows i and i+1.
+		f := d[i] / dl[i]
+		d[i] = dl[i]
+		tmp := d[i+1]
+		d[i+1] = du[i] - f*tmp
+		if i < n-2 {
+			dl[i] = du[i+1]
+			du[i+1] = -f * dl[i]
+		} else {
+			dl[i] = 0
+		}
+		du[i] = tmp
+		b[i], b[i+1] = b[i+1], b[i]-f*b[i+1]
+	}
+	if d[n-1] == 0 {
+		return false
+	}
+	b[n-1] /= d[n-1]
+	if n > 1 {
+		b[n-2] = (b[n-2] - du[n-2]*b[n-1]) / d[n-2]
+	}
+	for i := n - 3; i >= 0; i-- {
+		b[i] = (b[i] - du[i]*b[i+1] - dl[i]*b[i+2]) / d[i]
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// cubicPoly is the cubic polynomial 1 - 2x + 0.5x^2 + 0.3x^3.
+var (
+	cubicPoly = func(x float64) float64 { return 1 + x*(-2+x*(0.5+0.3*x)) }
+	cubicDer  = func(x float64) float64 { return -2 + x*(1+0.9*x) }
+	cubicInt  = func(x float64) float64 { return x * (1 + x*(-1+x*(0.5/3+0.3/4*x))) }
+
+	linearPoly = func(x float64) float64 { return 0.5 - 2*x }
+	linearDer  = func(float64) float64 { return -2 }
+	linearInt  = func(x float64) float64 { return 0.5*x - x*x }
+)
+
+func TestPiecewiseCubic(t *testing.T) {
+	t.Parallel()
+	xs := []float64{-1, -0.2, 0.5, 1, 2.5}
+	var pc PiecewiseCubic
+	pc.FitWithDerivatives(xs, sample(cubicPoly, xs), sample(cubicDer, xs))
+	testPredictor(t, "PiecewiseCubic", &pc, cubicPoly, cubicDer, cubicInt, -1, 2.5, 1e-12)
+
+	// Fitting with second derivatives reproduces the cubic.
+	d2 := func(x float64) float64 { return 1 + 1.8*x }
+	pc.fitWithSecondDerivatives(xs, sample(cubicPoly, xs), sample(d2, xs))
+	testPredictor(t, "PiecewiseCubic second derivatives", &pc, cubicPoly, cubicDer, cubicInt, -1, 2.5, 1e-12)
+}
+
+func TestCubicSplinesReproduce(t *testing.T) {
+	t.Parallel()
+	xs := []float64{-1, -0.6, -0.2, 0.5, 1, 1.7, 2.5}
+	for _, test := range []struct {
+		name    string
+		p       interpolator
+		f, df   func(float64) float64
+		F       func(float64) float64
+		minSize int
+	}{
+		{name: "NaturalCubic", p: &NaturalCubic{}, f: linearPoly, df: linearDer, F: linearInt, minSize: 2},
+		{name: "ClampedCubic", p: &ClampedCubic{Left: cubicDer(-1), Right: cubicDer(2.5)}, f: cubicPoly, df: cubicDer, F: cubicInt, minSize: 2},
+		{name: "NotAKnotCubic", p: &NotAKnotCubic{}, f: cubicPoly, df: cubicDer, F: cubicInt, minSize: 4},
+		{name: "NotAKnotCubic linear", p: &NotAKnotCubic{}, f: linearPoly, df: linearDer, F: linearInt, minSize: 2},
+		{name: "AkimaSpline", p: &AkimaSpline{}, f: linearPoly, df: linearDer, F: linearInt, minSize: 2},
+		{name: "PCHIP", p: &PCHIP{}, f: linearPoly, df: linearDer, F: linearInt, minSize: 2},
+	} {
+		for n := test.minSize; n <= len(xs); n++ {
+			x := xs[:n]
+			if test.name == "ClampedCubic" {
+				cc := test.p.(*ClampedCubic)
+				cc.Right = cubicDer(x[n-1])
+			}
+			if err := test.p.Fit(x, sample(test.f, x)); err != nil {
+				t.Errorf("%s: unexpected error for %d points: %v", test.name, n, err)
+				continue
+			}
+			testPredictor(t, test.name, test.p, test.f, test.df, test.F, x[0], x[n-1], 1e-12)
+		}
+	}
+}
+
+func TestNotAKnotParabola(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return 2 - x + 3*x*x }
+	df := func(x float64) float64 { return -1 + 6*x }
+	F := func(x float64) float64 { return 2*x - x*x/2 + x*x*x }
+	xs := []float64{-0.5, 0.1, 1.2}
+	var nak NotAKnotCubic
+	if err := nak.Fit(xs, sample(f, xs)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testPredictor(t, "NotAKnotCubic parabola", &nak, f, df, F, -0.5, 1.2, 1e-12)
+}
+
+func TestCubicSplinesConvergence(t *testing.T) {
+	t.Parallel()
+	f := math.Sin
+	for _, test := range []struct {
+		name  string
+		p     FittablePredictor
+		order float64
+	}{
+		{name: "NaturalCubic", p: &NaturalCubic{}, order: 2},
+		{name: "ClampedCubic", p: &ClampedCubic{Left: 1, Right: math.Cos(3)}, order: 4},
+		{name: "NotAKnotCubic", p: &NotAKnotCubic{}, order: 4},
+		{name: "AkimaSpline", p: &AkimaSpline{}, order: 2},
+		// The derivatives are limited at extrema, which reduces the
+		// order of accuracy there.
+		{name: "PCHIP", p: &PCHIP{}, order: 1},
+	} {
+		var prev float64
+		for k, n := range []int{20, 40, 80} {
+			xs := make([]float64, n+1)
+			floats.Span(xs, 0, 3)
+			if err := test.p.Fit(xs, sample(f, xs)); err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			var maxErr float64
+			for i := 0; i <= 1000; i++ {
+				x := 3 * float64(i) / 1000
+				maxErr = math.Max(maxErr, math.Abs(test.p.Predict(x)-f(x)))
+			}
+			if k > 0 {
+				// The error should decrease at least at the expected rate,
+				// allowing for some slack.
+				if rate := math.Log2(prev / maxErr); rate < test.order-0.5 {
+					t.Errorf("%s: unexpected convergence rate for n=%d: %v", test.name, n, rate)
+				}
+			}
+			prev = maxErr
+		}
+	}
+}
+
+func TestNaturalCubicEnds(t *testing.T) {
+	t.Parallel()
+	xs := []float64{0, 0.5, 1.5, 2, 4}
+	ys := []float64{1, -1, 2, 0.5, 3}
+	var nc NaturalCubic
+	if err := nc.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d2 := 2 * nc.cubic.coeffs[0][2]; math.Abs(d2) > 1e-13 {
+		t.Errorf("unexpected second derivative at first point: %v", d2)
+	}
+	last := nc.cubic.coeffs[len(nc.cubic.coeffs)-1]
+	h := xs[4] - xs[3]
+	if d2 := 2*last[2] + 6*last[3]*h; math.Abs(d2) > 1e-13 {
+		t.Errorf("unexpected second derivative at last point: %v", d2)
+	}
+	// The second derivative is continuous at the interior points.
+	for i := 1; i < len(xs)-1; i++ {
+		l := nc.cubic.coeffs[i-1]
+		r := nc.cubic.coeffs[i]
+		h := xs[i] - xs[i-1]
+		if d := 2*l[2] + 6*l[3]*h - 2*r[2]; math.Abs(d) > 1e-12 {
+			t.Errorf("discontinuous second derivative at x=%v: jump %v", xs[i], d)
+		}
+	}
+}
+
+func TestPCHIPMonotone(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		n := 3 + rnd.Intn(10)
+		xs := make([]float64, n)
+		ys := make([]float64, n)
+		for i := 1; i < n; i++ {
+			xs[i] = xs[i-1] + 0.1 + rnd.Float64()
+			// Non-decreasing data with flat sections and jumps.
+			switch rnd.Intn(3) {
+			case 0:
+				ys[i] = ys[i-1]
+			case 1:
+				ys[i] = ys[i-1] + rnd.Float64()
+			case 2:
+				ys[i] = ys[i-1] + 10*rnd.Float64()
+			}
+		}
+		var p PCHIP
+		if err := p.Fit(xs, ys); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		prev := p.Predict(xs[0])
+		for i := 1; i <= 1000; i++ {
+			x := xs[0] + float64(i)/1000*(xs[n-1]-xs[0])
+			v := p.Predict(x)
+			if v < prev-1e-12 {
+				t.Errorf("trial %d: interpolant not monotone at x=%v: %v < %v", trial, x, v, prev)
+				break
+			}
+			prev = v
+		}
+		for i, x := range xs {
+			if got := p.Predict(x); math.Abs(got-ys[i]) > 1e-12 {
+				t.Errorf("trial %d: interpolant does not match data at x=%v: got %v, want %v", trial, x, got, ys[i])
+			}
+		}
+	}
+}
+
+func TestAkimaSpline(t *testing.T) {
+	t.Parallel()
+	// The Akima spline is not affected by a step beyond the neighbouring
+	// intervals, unlike global cubic splines.
+	xs := []float64{0, 1, 2, 3, 4, 5, 6, 7, 8}
+	ys := []float64{0, 0, 0, 0, 1, 1, 1, 1, 1}
+	var as AkimaSpline
+	if err := as.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, x := range []float64{0.5, 1.5, 2.5, 5.5, 6.5, 7.5} {
+		want := 0.0
+		if x > 4 {
+			want = 1
+		}
+		if got := as.Predict(x); got != want {
+			t.Errorf("unexpected value at x=%v: got %v, want %v", x, got, want)
+		}
+	}
+	var nc NaturalCubic
+	if err := nc.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nc.Predict(1.5) == 0 {
+		t.Errorf("expected overshoot for natural cubic spline")
+	}
+}
+
+func TestSolveTridiagonal(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 3, 10} {
+		dl := make([]float64, n-1)
+		du := make([]float64, n-1)
+		d := make([]float64, n)
+		for i := range d {
+			d[i] = rnd.NormFloat64()
+		}
+		for i := range dl {
+			dl[i] = rnd.NormFloat64()
+			du[i] = rnd.NormFloat64()
+		}
+		want := make([]float64, n)
+		for i := range want {
+			want[i] = rnd.NormFloat64()
+		}
+		b := make([]float64, n)
+		for i := range b {
+			b[i] = d[i] * want[i]
+			if i > 0 {
+				b[i] += dl[i-1] * want[i-1]
+			}
+			if i < n-1 {
+				b[i] += du[i] * want[i+1]
+			}
+		}
+		if !solveTridiagonal(dl, d, du, b) {
+			t.Errorf("n=%d: unexpected singular system", n)
+			continue
+		}
+		if !floats.EqualApprox(b, want, 1e-10) {
+			t.Errorf("n=%d: unexpected solution: got %v, want %v", n, b, want)
+		}
+	}
+	if solveTridiagonal([]float64{0}, []float64{0, 1}, []float64{1}, []float64{1, 1}) {
+		t.Errorf("singular system not detected")
+	}
+}
@@ -0,0 +1,611 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ FittableMultiPredictor = (*DelaunayLinear)(nil)
+	_ FittableMultiPredictor = (*NaturalNeighbor)(nil)
+)
+
+var errCollinear = errors.New("interp: data points are collinear")
+
+// Delaunay is a Delaunay triangulation of a set of points in the plane.
+// The triangulation is computed by the Bowyer–Watson algorithm.
+type Delaunay struct {
+	// points holds the points translated and scaled to the unit square.
+	points [][2]float64
+
+	// triangles holds the vertex indices of the triangles in
+	// counter-clockwise order. neighbors[t][k] is the index of the
+	// triangle sharing the edge opposite vertex k of triangle t, or -1
+	// if that edge is on the convex hull.
+	triangles [][3]int
+	neighbors [][3]int
+
+	// center and scale map the input coordinates to the unit square.
+	center [2]float64
+	scale  float64
+}
+
+// NewDelaunay returns the Delaunay triangulation of the points given by the
+// rows of the n×2 matrix x. Repeated points are included in the
+// triangulation only once, at the index of their first occurrence. If all
+// points are collinear, the triangulation has no triangles. NewDelaunay
+// panics if x does not have two columns.
+func NewDelaunay(x mat.Matrix) *Delaunay {
+	n, c := x.Dims()
+	if c != 2 {
+		panic("interp: Delaunay triangulation requires two dimensions")
+	}
+	d := &Delaunay{points: make([][2]float64, n)}
+	lo := [2]float64{math.Inf(1), math.Inf(1)}
+	hi := [2]float64{math.Inf(-1), math.Inf(-1)}
+	for i := 0; i < n; i++ {
+		for j := 0; j < 2; j++ {
+			v := x.At(i, j)
+			lo[j] = math.Min(lo[j], v)
+			hi[j] = math.Max(hi[j], v)
+		}
+	}
+	d.center = [2]float64{(lo[0] + hi[0]) / 2, (lo[1] + hi[1]) / 2}
+	d.scale = math.Max(hi[0]-lo[0], hi[1]-lo[1])
+	if d.scale == 0 {
+		d.scale = 1
+	}
+	for i := 0; i < n; i++ {
+		d.points[i] = d.toUnit(x.At(i, 0), x.At(i, 1))
+	}
+
+	b := newTriangulation(d.points)
+	if b == nil {
+		return d
+	}
+
+	// Remove the triangles connected to the ghost vertex and renumber
+	// the remaining triangles.
+	index := make([]int, len(b.tris))
+	for t, tr := range b.tris {
+		index[t] = -1
+		if tr.alive && !tr.isGhost() {
+			index[t] = len(d.triangles)
+			d.triangles = append(d.triangles, tr.v)
+		}
+	}
+	for t, tr := range b.tris {
+		if index[t] < 0 {
+			continue
+		}
+		var nb [3]int
+		for k, o := range tr.nb {
+			nb[k] = index[o]
+		}
+		d.neighbors = append(d.neighbors, nb)
+	}
+	return d
+}
+
+// ghost is the vertex at infinity that is joined to every edge of the convex
+// hull during the construction of a triangulation.
+const ghost = -1
+
+// buildTriangle is a triangle of a triangulation under construction. Ghost
+// triangles have the ghost vertex in last position, so that the first two
+// vertices are a convex hull edge with the outside of the hull to its left.
+type buildTriangle struct {
+	v     [3]int
+	nb    [3]int
+	alive bool
+}
+
+func (t *buildTriangle) isGhost() bool { return t.v[2] == ghost }
+
+// triangulation is a Delaunay triangulation under construction.
+type triangulation struct {
+	points [][2]float64
+	tris   []buildTriangle
+	free   []int
+	last   int
+}
+
+// newTriangulation returns the Delaunay triangulation of pts including
+// ghost triangles, or nil if the points are collinear.
+func newTriangulation(pts [][2]float64) *triangulation {
+	n := len(pts)
+	seen := make(map[[2]float64]bool, n)
+	var order []int
+	for i, p := range pts {
+		if !seen[p] {
+			seen[p] = true
+			order = append(order, i)
+		}
+	}
+	// Start from the first three unique points that are not collinear.
+	if len(order) < 3 {
+		return nil
+	}
+	a, b := order[0], order[1]
+	third := -1
+	for k := 2; k < len(order); k++ {
+		if orient(pts[a], pts[b], pts[order[k]]) != 0 {
+			third = k
+			break
+		}
+	}
+	if third < 0 {
+		return nil
+	}
+	c := order[third]
+	if orient(pts[a], pts[b], pts[c]) < 0 {
+		a, b = b, a
+	}
+	tr := &triangulation{points: pts}
+	tr.tris = []buildTriangle{
+		{v: [3]int{a, b, c}, nb: [3]int{2, 3, 1}, alive: true},
+		{v: [3]int{b, a, ghost}, nb: [3]int{3, 2, 0}, alive: true},
+		{v: [3]int{c, b, ghost}, nb: [3]int{1, 3, 0}, alive: true},
+		{v: [3]int{a, c, ghost}, nb: [3]int{2, 1, 0}, alive: true},
+	}
+	for k, i := range order {
+		if k < 2 || k == third {
+			continue
+		}
+		tr.insert(i)
+	}
+	return tr
+}
+
+// conflicts returns whether the point p is strictly inside the
+// circumcircle of triangle t. The circumcircle of a ghost triangle is the
+// open half-plane to the left of its hull edge, together with the open
+// hull edge itself.
+func (tr *triangulation) conflicts(t int, p [2]float64) bool {
+	v := tr.tris[t].v
+	a, b := tr.points[v[0]], tr.points[v[1]]
+	if v[2] != ghost {
+		return inCircle(a, b, tr.points[v[2]], p) > 0
+	}
+	o := orient(a, b, p)
+	if o != 0 {
+		return o > 0
+	}
+	return (p[0]-a[0])*(p[0]-b[0])+(p[1]-a[1])*(p[1]-b[1]) < 0
+}
+
+// locate returns a triangle that conflicts with the point p.
+func (tr *triangulation) locate(p [2]float64) int {
+	t := tr.last
+	for steps := 0; steps <= len(tr.tris); steps++ {
+		v := tr.tris[t].v
+		next := -1
+		for k := 0; k < 3; k++ {
+			if orient(tr.points[v[(k+1)%3]], tr.points[v[(k+2)%3]], p) < 0 {
+				next = tr.tris[t].nb[k]
+				break
+			}
+		}
+		if next < 0 {
+			return t
+		}
+		if tr.tris[next].isGhost() {
+			return next
+		}
+		t = next
+	}
+	for t := range tr.tris {
+		if tr.tris[t].alive && tr.conflicts(t, p) {
+			return t
+		}
+	}
+	return tr.last
+}
+
+// insert adds point i to the triangulation.
+func (tr *triangulation) insert(i int) {
+	p := tr.points[i]
+
+	// Find the cavity of triangles in conflict with p by a search
+	// from a conflicting triangle.
+	start := tr.locate(p)
+	cavity := []int{start}
+	inCavity := map[int]bool{start: true}
+	for k := 0; k < len(cavity); k++ {
+		for _, o := range tr.tris[cavity[k]].nb {
+			if !inCavity[o] && tr.conflicts(o, p) {
+				inCavity[o] = true
+				cavity = append(cavity, o)
+			}
+		}
+	}
+
+	// Join p to the edges on the boundary of the cavity, reusing the
+	// slots of the cavity triangles.
+	old := make([]buildTriangle, len(cavity))
+	for k, t := range cavity {
+		old[k] = tr.tris[t]
+		tr.tris[t].alive = false
+	}
+	tr.free = append(tr.free[:0], cavity...)
+	startAt := make(map[int]int)
+	endAt := make(map[int]int)
+	type edge struct{ t, a, b int }
+	var created []edge
+	for _, old := range old {
+		for k := 0; k < 3; k++ {
+			o := old.nb[k]
+			if inCavity[o] {
+				continue
+			}
+			a, b := old.v[(k+1)%3], old.v[(k+2)%3]
+
+			// Rotate the vertices so that a ghost vertex is last.
+			tri := buildTriangle{alive: true}
+			switch ghost {
+			case a:
+				tri.v = [3]int{b, i, ghost}
+			case b:
+				tri.v = [3]int{i, a, ghost}
+			default:
+				tri.v = [3]int{a, b, i}
+			}
+			var nt int
+			if len(tr.free) > 0 {
+				nt = tr.free[len(tr.free)-1]
+				tr.free = tr.free[:len(tr.free)-1]
+				tr.tris[nt] = tri
+			} else {
+				nt = len(tr.tris)
+				tr.tris = append(tr.tris, tri)
+			}
+			startAt[a] = nt
+			endAt[b] = nt
+			created = append(created, edge{t: nt, a: a, b: b})
+
+			tr.setNeighbor(nt, a, b, o)
+			tr.setNeighbor(o, b, a, nt)
+		}
+	}
+	for _, e := range created {
+		// The edge from b to p is shared with the triangle starting at b,
+		// and the edge from p to a with the triangle ending at a.
+		tr.setNeighbor(e.t, e.b, i, startAt[e.b])
+		tr.setNeighbor(e.t, i, e.a, endAt[e.a])
+		if !tr.tris[e.t].isGhost() {
+			tr.last = e.t
+		}
+	}
+}
+
+// setNeighbor sets the neighbor of triangle t across its edge from a to b.
+func (tr *triangulation) setNeighbor(t, a, b, o int) {
+	v := tr.tris[t].v
+	for k := 0; k < 3; k++ {
+		if v[(k+1)%3] == a && v[(k+2)%3] == b {
+			tr.tris[t].nb[k] = o
+			return
+		}
+	}
+	panic("interp: inconsistent triangulation")
+}
+
+// toUnit maps a point to the internal coordinates.
+func (d *Delaunay) toUnit(x, y float64) [2]float64 {
+	return [2]float64{(x - d.center[0]) / d.scale, (y - d.center[1]) / d.scale}
+}
+
+// Triangles returns the triangles of the triangulation as triples of
+// indices into the rows of the matrix used to construct the triangulation.
+// The vertices of each triangle are in counter-clockwise order.
+func (d *Delaunay) Triangles() [][3]int {
+	tris := make([][3]int, len(d.triangles))
+	copy(tris, d.triangles)
+	return tris
+}
+
+// Locate returns the index of a triangle containing the point (x, y) and
+// the barycentric coordinates of the point with respect to the vertices of
+// the triangle. If the point is outside the convex hull of the points,
+// Locate returns -1.
+func (d *Delaunay) Locate(x, y float64) (t int, bary [3]float64) {
+	return d.locate(d.toUnit(x, y))
+}
+
+func (d *Delaunay) locate(p [2]float64) (t int, bary [3]float64) {
+	if len(d.triangles) == 0 {
+		return -1, bary
+	}
+	// Walk towards p from the first triangle. The walk terminates on a
+	// Delaunay triangulation, but limit the number of steps to guard
+	// against rounding errors.
+	t = 0
+	for steps := 0; steps <= len(d.triangles); steps++ {
+		v := d.triangles[t]
+		next := -2
+		for k := 0; k < 3; k++ {
+			a := d.points[v[(k+1)%3]]
+			b := d.points[v[(k+2)%3]]
+			if orient(a, b, p) < 0 {
+				next = d.neighbors[t][k]
+				break
+			}
+		}
+		switch next {
+		case -2:
+			return t, d.barycentric(t, p)
+		case -1:
+			return -1, bary
+		}
+		t = next
+	}
+	for t := range d.triangles {
+		if b := d.barycentric(t, p); b[0] >= 0 && b[1] >= 0 && b[2] >= 0 {
+			return t, b
+		}
+	}
+	return -1, bary
+}
+
+// barycentric returns the barycentric coordinates of p with respect to
+// triangle t.
+func (d *Delaunay) barycentric(t int, p [2]float64) [3]float64 {
+	v := d.triangles[t]
+	a, b, c := d.points[v[0]], d.points[v[1]], d.points[v[2]]
+	area := orient(a, b, c)
+	return [3]float64{
+		orient(b, c, p) / area,
+		orient(c, a, p) / area,
+		orient(a, b, p) / area,
+	}
+}
+
+// orient returns twice the signed area of the triangle abc, which is
+// positive if a, b and c are in counter-clockwise order.
+func orient(a, b, c [2]float64) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+// inCircle returns a value that is positive if d is inside the circumcircle
+// of the counter-clockwise triangle abc, negative if it is outside and zero
+// if it is on the circle.
+func inCircle(a, b, c, d [2]float64) float64 {
+	adx, ady := a[0]-d[0], a[1]-d[1]
+	bdx, bdy := b[0]-d[0], b[1]-d[1]
+	cdx, cdy := c[0]-d[0], c[1]-d[1]
+	ad := adx*adx + ady*ady
+	bd := bdx*bdx + bdy*bdy
+	cd := cdx*cdx + cdy*cdy
+	return adx*(bdy*cd-bd*cdy) - ady*(bdx*cd-bd*cdx) + ad*(bdx*cdy-bdy*cdx)
+}
+
+// circumcenter returns the center of the circle through a, b and c.
+func circumcenter(a, b, c [2]float64) (x, y float64) {
+	bx, by := b[0]-a[0], b[1]-a[1]
+	cx, cy := c[0]-a[0], c[1]-a[1]
+	den := 2 * (bx*cy - by*cx)
+	b2 := bx*bx + by*by
+	c2 := cx*cx + cy*cy
+	return a[0] + (cy*b2-by*c2)/den, a[1] + (bx*c2-cx*b2)/den
+}
+
+// DelaunayLinear is a piecewise linear interpolator of scattered data in
+// the plane on the Delaunay triangulation of the data points.
+type DelaunayLinear struct {
+	tri    *Delaunay
+	values []float64
+}
+
+// Fit fits the interpolator to the data points given by the rows of the
+// n×2 matrix x with values y. Repeated points are used once, with the value
+// at their first occurrence. It panics if the number of rows of x is not
+// equal to len(y), x does not have two columns or there are fewer than
+// three points. Returns an error if all points are collinear, so that the
+// triangulation has no triangles.
+func (dl *DelaunayLinear) Fit(x mat.Matrix, y []float64) error {
+	checkDelaunay(x, y)
+	tri := NewDelaunay(x)
+	if len(tri.triangles) == 0 {
+		return errCollinear
+	}
+	dl.tri = tri
+	dl.values = append(dl.values[:0], y...)
+	return nil
+}
+
+// Predict returns the interpolated value at x. If x is outside the convex
+// hull of the data points, Predict returns NaN. It panics if len(x) != 2.
+func (dl *DelaunayLinear) Predict(x []float64) float64 {
+	if len(x) != 2 {
+		panic(differentLengths)
+	}
+	t, b := dl.tri.Locate(x[0], x[1])
+	if t < 0 {
+		return math.NaN()
+	}
+	v := dl.tri.triangles[t]
+	return b[0]*dl.values[v[0]] + b[1]*dl.values[v[1]] + b[2]*dl.values[v[2]]
+}
+
+// NaturalNeighbor is Sibson's natural neighbor interpolator of scattered
+// data in the plane. The value at a point is the weighted mean of the
+// values at its natural neighbors, the data points whose Voronoi cells
+// would shrink if the point were added to the data, weighted by the areas
+// taken from their cells. The interpolant is continuous with continuous
+// first derivatives except at the data points, and reproduces linear
+// functions exactly.
+//
+// The areas are computed from the circumcenters of the Delaunay triangles
+// whose circumcircles contain the point, following Watson.
+//
+// References:
+//  R. Sibson, "A brief description of natural neighbour interpolation",
+//  in Interpreting Multivariate Data, pp. 21-36, Wiley, 1981.
+//  D. F. Watson, "Contouring: A Guide to the Analysis and Display of
+//  Spatial Data", Pergamon, 1992.
+type NaturalNeighbor struct {
+	tri    *Delaunay
+	values []float64
+}
+
+// Fit fits the interpolator to the data points given by the rows of the
+// n×2 matrix x with values y. Repeated points are used once, with the value
+// at their first occurrence. It panics if the number of rows of x is not
+// equal to len(y), x does not have two columns or there are fewer than
+// three points. Returns an error if all points are collinear, so that the
+// triangulation has no triangles.
+func (nn *NaturalNeighbor) Fit(x mat.Matrix, y []float64) error {
+	checkDelaunay(x, y)
+	tri := NewDelaunay(x)
+	if len(tri.triangles) == 0 {
+		return errCollinear
+	}
+	nn.tri = tri
+	nn.values = append(nn.values[:0], y...)
+	return nil
+}
+
+// Predict returns the interpolated value at x. If x is outside the convex
+// hull of the data points, Predict returns NaN. It panics if len(x) != 2.
+func (nn *NaturalNeighbor) Predict(x []float64) float64 {
+	if len(x) != 2 {
+		panic(differentLengths)
+	}
+	d := nn.tri
+	p := d.toUnit(x[0], x[1])
+	t, b := d.locate(p)
+	if t < 0 {
+		return math.NaN()
+	}
+	for _, v := range d.triangles[t] {
+		if d.points[v] == p {
+			return nn.values[v]
+		}
+	}
+
+	// Find the cavity of triangles whose circumcircles contain p by
+	// a search from the containing triangle.
+	cavity := []int{t}
+	inCavity := map[int]bool{t: true}
+	for i := 0; i < len(cavity); i++ {
+		for _, nb := range d.neighbors[cavity[i]] {
+			if nb < 0 || inCavity[nb] {
+				continue
+			}
+			v := d.triangles[nb]
+			if inCircle(d.points[v[0]], d.points[v[1]], d.points[v[2]], p) > 0 {
+				inCavity[nb] = true
+				cavity = append(cavity, nb)
+			}
+		}
+	}
+
+	// The area taken from the Voronoi cell of a natural neighbor v is the
+	// polygon formed by the circumcenters of the cavity triangles around v,
+	// closed by the circumcenters of the two new triangles formed by p and
+	// the cavity boundary edges at v. Coordinates are taken relative to p.
+	//
+	// fans[v] maps the vertex preceding v in each cavity triangle to the
+	// circumcenter of that triangle and the vertex following v.
+	fans := make(map[int]map[int]fanStep)
+	rel := func(v int) [2]float64 {
+		return [2]float64{d.points[v][0] - p[0], d.points[v][1] - p[1]}
+	}
+	for _, t := range cavity {
+		v := d.triangles[t]
+		cx, cy := circumcenter(rel(v[0]), rel(v[1]), rel(v[2]))
+		for k := 0; k < 3; k++ {
+			f, ok := fans[v[k]]
+			if !ok {
+				f = make(map[int]fanStep)
+				fans[v[k]] = f
+			}
+			f[v[(k+1)%3]] = fanStep{center: [2]float64{cx, cy}, to: v[(k+2)%3]}
+		}
+	}
+	var sum, total float64
+	for v, f := range fans {
+		// The fan starts at the vertex that does not follow v in any
+		// cavity triangle.
+		start := -1
+		follows := make(map[int]bool, len(f))
+		for _, st := range f {
+			follows[st.to] = true
+		}
+		for u := range f {
+			if !follows[u] {
+				start = u
+				break
+			}
+		}
+		if start < 0 {
+			// v is enclosed by the cavity, which happens only through
+			// rounding error.
+			return nn.linear(t, b)
+		}
+		poly := [][2]float64{circumcenterOrigin(rel(start), rel(v))}
+		u := start
+		for range f {
+			st, ok := f[u]
+			if !ok {
+				break
+			}
+			poly = append(poly, st.center)
+			u = st.to
+		}
+		poly = append(poly, circumcenterOrigin(rel(v), rel(u)))
+		var a float64
+		for i := range poly {
+			a += orient([2]float64{}, poly[i], poly[(i+1)%len(poly)]) / 2
+		}
+		sum += a * nn.values[v]
+		total += a
+	}
+	if total == 0 || math.IsNaN(total) || math.IsInf(total, 0) || math.IsNaN(sum) {
+		// p is on the convex hull, where the natural neighbor interpolant
+		// is linear.
+		return nn.linear(t, b)
+	}
+	return sum / total
+}
+
+// fanStep is a step around a vertex through a triangle.
+type fanStep struct {
+	center [2]float64
+	to     int
+}
+
+// linear returns the linear interpolant in triangle t at the point with
+// barycentric coordinates b.
+func (nn *NaturalNeighbor) linear(t int, b [3]float64) float64 {
+	v := nn.tri.triangles[t]
+	return b[0]*nn.values[v[0]] + b[1]*nn.values[v[1]] + b[2]*nn.values[v[2]]
+}
+
+// circumcenterOrigin returns the center of the circle through the origin,
+// a and b.
+func circumcenterOrigin(a, b [2]float64) [2]float64 {
+	den := 2 * (a[0]*b[1] - a[1]*b[0])
+	a2 := a[0]*a[0] + a[1]*a[1]
+	b2 := b[0]*b[0] + b[1]*b[1]
+	return [2]float64{(b[1]*a2 - a[1]*b2) / den, (a[0]*b2 - b[0]*a2) / den}
+}
+
+func checkDelaunay(x mat.Matrix, y []float64) {
+	r, c := x.Dims()
+	if r != len(y) {
+		panic(differentLengths)
+	}
+	if c != 2 {
+		panic("interp: Delaunay triangulation requires two dimensions")
+	}
+	if r < 3 {
+		panic(tooFewPoints)
+	}
+}
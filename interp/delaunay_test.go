@@ -0,0 +1,230 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestDelaunay(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, n := range []int{3, 4, 10, 50, 200} {
+		x := scatteredPoints(rnd, n, 2)
+		d := NewDelaunay(x)
+		pts := make([][2]float64, n)
+		for i := range pts {
+			pts[i] = [2]float64{x.At(i, 0), x.At(i, 1)}
+		}
+
+		// A triangulation of n points with h on the convex hull has
+		// 2n-h-2 triangles, and the areas of the triangles sum to the
+		// area of the hull.
+		hull := convexHull(pts)
+		tris := d.Triangles()
+		if got, want := len(tris), 2*n-len(hull)-2; got != want {
+			t.Errorf("n=%d: unexpected number of triangles: got %d, want %d", n, got, want)
+		}
+		var area float64
+		for _, tr := range tris {
+			a := orient(pts[tr[0]], pts[tr[1]], pts[tr[2]]) / 2
+			if a <= 0 {
+				t.Errorf("n=%d: triangle %v not counter-clockwise", n, tr)
+			}
+			area += a
+		}
+		var hullArea float64
+		for i := range hull {
+			hullArea += orient([2]float64{}, hull[i], hull[(i+1)%len(hull)]) / 2
+		}
+		if math.Abs(area-hullArea) > 1e-12 {
+			t.Errorf("n=%d: triangle areas sum to %v, want hull area %v", n, area, hullArea)
+		}
+
+		// No point lies strictly inside the circumcircle of a triangle.
+		for _, tr := range tris {
+			for i, p := range pts {
+				if i == tr[0] || i == tr[1] || i == tr[2] {
+					continue
+				}
+				if inCircle(pts[tr[0]], pts[tr[1]], pts[tr[2]], p) > 1e-12 {
+					t.Errorf("n=%d: point %d inside circumcircle of triangle %v", n, i, tr)
+				}
+			}
+		}
+
+		// Located points are reconstructed from their barycentric
+		// coordinates.
+		for k := 0; k < 50; k++ {
+			q := [2]float64{rnd.Float64(), rnd.Float64()}
+			tri, b := d.Locate(q[0], q[1])
+			if tri < 0 {
+				if insideHull(hull, q) {
+					t.Errorf("n=%d: point %v inside hull not located", n, q)
+				}
+				continue
+			}
+			var got [2]float64
+			for j, v := range tris[tri] {
+				if b[j] < -1e-12 {
+					t.Errorf("n=%d: negative barycentric coordinate for %v: %v", n, q, b)
+				}
+				got[0] += b[j] * pts[v][0]
+				got[1] += b[j] * pts[v][1]
+			}
+			if math.Abs(got[0]-q[0]) > 1e-12 || math.Abs(got[1]-q[1]) > 1e-12 {
+				t.Errorf("n=%d: unexpected barycentric reconstruction of %v: got %v", n, q, got)
+			}
+		}
+	}
+}
+
+func TestDelaunayDegenerate(t *testing.T) {
+	t.Parallel()
+	// Collinear points have no triangles.
+	d := NewDelaunay(mat.NewDense(4, 2, []float64{0, 0, 1, 1, 2, 2, 3, 3}))
+	if n := len(d.Triangles()); n != 0 {
+		t.Errorf("unexpected number of triangles for collinear points: %d", n)
+	}
+	if tri, _ := d.Locate(1, 1); tri != -1 {
+		t.Errorf("unexpected triangle for collinear points: %d", tri)
+	}
+
+	// A square grid has cocircular points and repeated points are ignored.
+	x := mat.NewDense(10, 2, []float64{
+		0, 0, 1, 0, 2, 0,
+		0, 1, 1, 1, 2, 1,
+		0, 2, 1, 2, 2, 2,
+		1, 1,
+	})
+	if n := len(NewDelaunay(x).Triangles()); n != 8 {
+		t.Errorf("unexpected number of triangles for grid: got %d, want 8", n)
+	}
+
+	// The interpolators cannot be fitted to collinear or repeated points.
+	for _, x := range []*mat.Dense{
+		mat.NewDense(4, 2, []float64{0, 0, 1, 1, 2, 2, 3, 3}),
+		mat.NewDense(3, 2, []float64{1, 2, 1, 2, 1, 2}),
+		mat.NewDense(4, 2, []float64{0, 1, 2, 1, 0, 1, 1, 1}),
+	} {
+		r, _ := x.Dims()
+		y := make([]float64, r)
+		for _, p := range []FittableMultiPredictor{&DelaunayLinear{}, &NaturalNeighbor{}} {
+			if err := p.Fit(x, y); err == nil {
+				t.Errorf("%T: expected error for degenerate points %v", p, mat.Formatted(x))
+			}
+		}
+	}
+}
+
+func TestDelaunayInterpolators(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	linear := func(x []float64) float64 { return 2 - 3*x[0] + 0.5*x[1] }
+	for _, test := range []struct {
+		name string
+		p    FittableMultiPredictor
+	}{
+		{name: "linear", p: &DelaunayLinear{}},
+		{name: "natural neighbor", p: &NaturalNeighbor{}},
+	} {
+		x := scatteredPoints(rnd, 60, 2)
+		y := values(x, linear)
+		if err := test.p.Fit(x, y); err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		// Both interpolants reproduce linear functions and the data.
+		for i, want := range y {
+			if got := test.p.Predict(mat.Row(nil, i, x)); math.Abs(got-want) > 1e-12 {
+				t.Errorf("%s: unexpected value at data point %d: got %v, want %v", test.name, i, got, want)
+			}
+		}
+		for k := 0; k < 100; k++ {
+			q := []float64{rnd.Float64(), rnd.Float64()}
+			got := test.p.Predict(q)
+			if math.IsNaN(got) {
+				continue
+			}
+			if want := linear(q); math.Abs(got-want) > 1e-10 {
+				t.Errorf("%s: unexpected value at %v: got %v, want %v", test.name, q, got, want)
+			}
+		}
+		if got := test.p.Predict([]float64{2, 2}); !math.IsNaN(got) {
+			t.Errorf("%s: expected NaN outside hull, got %v", test.name, got)
+		}
+	}
+}
+
+func TestNaturalNeighborSymmetric(t *testing.T) {
+	t.Parallel()
+	// At the center of a regular hexagon with a central point, the center
+	// is a data point; at the midpoint between the center and a vertex the
+	// natural neighbor coordinates are symmetric about that axis.
+	x := mat.NewDense(7, 2, nil)
+	for i := 0; i < 6; i++ {
+		a := float64(i) * math.Pi / 3
+		x.Set(i, 0, math.Cos(a))
+		x.Set(i, 1, math.Sin(a))
+	}
+	y := []float64{0, 1, 0, 0, 0, 1, 0}
+	var nn NaturalNeighbor
+	if err := nn.Fit(x, y); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := nn.Predict([]float64{0, 0}); got != 0 {
+		t.Errorf("unexpected value at data point: got %v, want 0", got)
+	}
+	// Vertices 1 and 5 are mirror images in the x axis.
+	if got := nn.Predict([]float64{0.5, 0}); !(got > 0 && got < 1) {
+		t.Errorf("unexpected value on symmetry axis: %v", got)
+	}
+	up := nn.Predict([]float64{0.3, 0.2})
+	down := nn.Predict([]float64{0.3, -0.2})
+	if math.Abs(up-down) > 1e-12 {
+		t.Errorf("natural neighbor interpolant not symmetric: %v != %v", up, down)
+	}
+}
+
+// convexHull returns the vertices of the convex hull of pts in
+// counter-clockwise order by Andrew's monotone chain algorithm.
+func convexHull(pts [][2]float64) [][2]float64 {
+	p := append([][2]float64(nil), pts...)
+	for i := 1; i < len(p); i++ {
+		for j := i; j > 0 && (p[j][0] < p[j-1][0] || p[j][0] == p[j-1][0] && p[j][1] < p[j-1][1]); j-- {
+			p[j], p[j-1] = p[j-1], p[j]
+		}
+	}
+	var hull [][2]float64
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, q := range p {
+			for len(hull) >= start+2 && orient(hull[len(hull)-2], hull[len(hull)-1], q) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, q)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+			p[i], p[j] = p[j], p[i]
+		}
+	}
+	return hull
+}
+
+// insideHull returns whether q is strictly inside the counter-clockwise
+// convex polygon hull.
+func insideHull(hull [][2]float64, q [2]float64) bool {
+	for i := range hull {
+		if orient(hull[i], hull[(i+1)%len(hull)], q) <= 1e-12 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,24 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package interp provides interpolation of functions from their values at
+// a set of points.
+//
+// The interpolants are piecewise polynomials: piecewise constant and linear
+// interpolants, cubic splines with natural, clamped and not-a-knot end
+// conditions, the Akima spline, the monotone piecewise cubic Hermite
+// interpolant PCHIP and B-splines of arbitrary degree. Each supports
+// evaluation, differentiation and definite integration. SmoothingSpline fits
+// a cubic spline that balances fidelity to noisy data against smoothness.
+//
+// Outside the range of the fitted points all 1-D interpolants are extended
+// by the constant values at the nearest end point.
+//
+// Multivariate data are interpolated by the MultiPredictor types. Scattered
+// data in any number of dimensions are interpolated by radial basis
+// functions, RBF, and by inverse distance weighting, IDW. Scattered data in
+// the plane are interpolated linearly or by natural neighbor interpolation
+// on a Delaunay triangulation. Data on rectilinear grids are interpolated by
+// Bilinear, Bicubic, Trilinear and Tricubic.
+package interp // import "gonum.org/v1/gonum/interp"
@@ -0,0 +1,111 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp_test
+
+import (
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/interp"
+	"gonum.org/v1/gonum/mat"
+)
+
+func Example() {
+	// Interpolate a step, which makes global cubic splines overshoot.
+	xs := []float64{0, 1, 2, 3, 4, 5}
+	ys := []float64{0, 0, 0, 1, 1, 1}
+
+	predictors := []struct {
+		name string
+		p    interp.FittablePredictor
+	}{
+		{name: "linear", p: &interp.PiecewiseLinear{}},
+		{name: "natural", p: &interp.NaturalCubic{}},
+		{name: "akima", p: &interp.AkimaSpline{}},
+		{name: "pchip", p: &interp.PCHIP{}},
+	}
+	fmt.Println("x     linear   natural  akima    pchip")
+	for _, pr := range predictors {
+		if err := pr.p.Fit(xs, ys); err != nil {
+			panic(err)
+		}
+	}
+	for _, x := range []float64{1.5, 2.5, 3.5} {
+		fmt.Printf("%.1f", x)
+		for _, pr := range predictors {
+			fmt.Printf("  % .4f", pr.p.Predict(x))
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	// x     linear   natural  akima    pchip
+	// 1.5   0.0000  -0.1023   0.0000   0.0000
+	// 2.5   0.5000   0.5000   0.5000   0.5000
+	// 3.5   1.0000   1.1023   1.0000   1.0000
+}
+
+func ExampleBSpline() {
+	// Interpolate the exponential function by a quintic spline and
+	// compute its integral and derivative.
+	xs := make([]float64, 11)
+	ys := make([]float64, len(xs))
+	for i := range xs {
+		xs[i] = float64(i) / 10
+		ys[i] = math.Exp(xs[i])
+	}
+	b := interp.BSpline{Degree: 5}
+	if err := b.Fit(xs, ys); err != nil {
+		panic(err)
+	}
+	fmt.Printf("value at 0.55:      %.8f (exact %.8f)\n", b.Predict(0.55), math.Exp(0.55))
+	fmt.Printf("derivative at 0.55: %.8f (exact %.8f)\n", b.PredictDerivative(0.55), math.Exp(0.55))
+	fmt.Printf("integral on [0, 1]: %.8f (exact %.8f)\n", b.Integrate(0, 1), math.E-1)
+
+	// Output:
+	// value at 0.55:      1.73325302 (exact 1.73325302)
+	// derivative at 0.55: 1.73325302 (exact 1.73325302)
+	// integral on [0, 1]: 1.71828183 (exact 1.71828183)
+}
+
+func ExampleRBF() {
+	// Interpolate a bump sampled at scattered points, comparing radial basis function, natural neighbor and inverse
+	// distance weighted interpolation.
+	x := mat.NewDense(9, 2, []float64{
+		0, 0, 1, 0, 2, 0.2,
+		0.1, 1, 1.2, 1.1, 2, 1,
+		0, 2, 0.9, 2, 2, 1.8,
+	})
+	f := func(x, y float64) float64 { return math.Exp(-(x-1)*(x-1) - (y-1)*(y-1)) }
+	y := make([]float64, 9)
+	for i := range y {
+		y[i] = f(x.At(i, 0), x.At(i, 1))
+	}
+
+	predictors := []struct {
+		name string
+		p    interp.FittableMultiPredictor
+	}{
+		{name: "thin plate", p: &interp.RBF{}},
+		{name: "gaussian", p: &interp.RBF{Kernel: interp.Gaussian{Epsilon: 1}}},
+		{name: "natural neighbor", p: &interp.NaturalNeighbor{}},
+		{name: "idw", p: &interp.IDW{Neighbors: 4}},
+	}
+	q := []float64{0.6, 1.4}
+	fmt.Printf("%-16s %.4f\n", "exact", f(q[0], q[1]))
+	for _, pr := range predictors {
+		if err := pr.p.Fit(x, y); err != nil {
+			panic(err)
+		}
+		fmt.Printf("%-16s %.4f\n", pr.name, pr.p.Predict(q))
+	}
+
+	// Output:
+	// exact            0.7261
+	// thin plate       0.6604
+	// gaussian         0.6783
+	// natural neighbor 0.5273
+	// idw              0.5073
+}
@@ -0,0 +1,266 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import "gonum.org/v1/gonum/mat"
+
+// Bilinear is a bilinear interpolator of values on a rectilinear grid in
+// the plane. Outside the grid, the interpolant is extended by the values
+// on its boundary.
+type Bilinear struct {
+	xs, ys gridAxis
+	z      []float64
+}
+
+// NewBilinear returns a bilinear interpolator of the values z on the grid
+// with coordinates xs and ys, where z.At(i, j) is the value at (xs[i], ys[j]).
+// NewBilinear panics if xs or ys have fewer than two elements or are not
+// strictly increasing, or if the dimensions of z are not len(xs)×len(ys).
+func NewBilinear(xs, ys []float64, z mat.Matrix) *Bilinear {
+	return &Bilinear{xs: newGridAxis(xs), ys: newGridAxis(ys), z: gridValues(xs, ys, z)}
+}
+
+// Predict returns the interpolated value at (x, y).
+func (b *Bilinear) Predict(x, y float64) float64 {
+	i, wx := b.xs.linear(x)
+	j, wy := b.ys.linear(y)
+	ny := len(b.ys)
+	var v float64
+	for p, u := range wx {
+		for q, w := range wy {
+			v += u * w * b.z[(i+p)*ny+j+q]
+		}
+	}
+	return v
+}
+
+// Bicubic is a bicubic interpolator of values on a rectilinear grid in the
+// plane. The interpolant is the tensor product of piecewise cubic Hermite
+// interpolants whose derivatives at the grid points are estimated by
+// three-point finite differences, so it is continuously differentiable
+// and reproduces quadratic functions exactly. Outside the grid, the
+// interpolant is extended by the values on its boundary.
+type Bicubic struct {
+	xs, ys gridAxis
+	z      []float64
+}
+
+// NewBicubic returns a bicubic interpolator of the values z on the grid
+// with coordinates xs and ys, where z.At(i, j) is the value at (xs[i], ys[j]).
+// NewBicubic panics if xs or ys have fewer than two elements or are not
+// strictly increasing, or if the dimensions of z are not len(xs)×len(ys).
+func NewBicubic(xs, ys []float64, z mat.Matrix) *Bicubic {
+	return &Bicubic{xs: newGridAxis(xs), ys: newGridAxis(ys), z: gridValues(xs, ys, z)}
+}
+
+// Predict returns the interpolated value at (x, y).
+func (b *Bicubic) Predict(x, y float64) float64 {
+	i, wx, nx := b.xs.cubic(x)
+	j, wy, nyw := b.ys.cubic(y)
+	ny := len(b.ys)
+	var v float64
+	for p := 0; p < nx; p++ {
+		for q := 0; q < nyw; q++ {
+			v += wx[p] * wy[q] * b.z[(i+p)*ny+j+q]
+		}
+	}
+	return v
+}
+
+// Trilinear is a trilinear interpolator of values on a rectilinear grid in
+// three dimensions. Outside the grid, the interpolant is extended by the
+// values on its boundary.
+type Trilinear struct {
+	xs, ys, zs gridAxis
+	v          []float64
+}
+
+// NewTrilinear returns a trilinear interpolator of the values v on the grid
+// with coordinates xs, ys and zs, where v[(i*len(ys)+j)*len(zs)+k] is the
+// value at (xs[i], ys[j], zs[k]). NewTrilinear panics if any of xs, ys and
+// zs have fewer than two elements or are not strictly increasing, or if
+// len(v) is not len(xs)*len(ys)*len(zs).
+func NewTrilinear(xs, ys, zs, v []float64) *Trilinear {
+	return &Trilinear{xs: newGridAxis(xs), ys: newGridAxis(ys), zs: newGridAxis(zs), v: gridValues3(xs, ys, zs, v)}
+}
+
+// Predict returns the interpolated value at (x, y, z).
+func (t *Trilinear) Predict(x, y, z float64) float64 {
+	i, wx := t.xs.linear(x)
+	j, wy := t.ys.linear(y)
+	k, wz := t.zs.linear(z)
+	ny, nz := len(t.ys), len(t.zs)
+	var v float64
+	for p, u := range wx {
+		for q, w := range wy {
+			for r, s := range wz {
+				v += u * w * s * t.v[((i+p)*ny+j+q)*nz+k+r]
+			}
+		}
+	}
+	return v
+}
+
+// Tricubic is a tricubic interpolator of values on a rectilinear grid in
+// three dimensions. The interpolant is the tensor product of the piecewise
+// cubic Hermite interpolants used by Bicubic, so it is continuously
+// differentiable and reproduces quadratic functions exactly. Outside the
+// grid, the interpolant is extended by the values on its boundary.
+type Tricubic struct {
+	xs, ys, zs gridAxis
+	v          []float64
+}
+
+// NewTricubic returns a tricubic interpolator of the values v on the grid
+// with coordinates xs, ys and zs, where v[(i*len(ys)+j)*len(zs)+k] is the
+// value at (xs[i], ys[j], zs[k]). NewTricubic panics if any of xs, ys and
+// zs have fewer than two elements or are not strictly increasing, or if
+// len(v) is not len(xs)*len(ys)*len(zs).
+func NewTricubic(xs, ys, zs, v []float64) *Tricubic {
+	return &Tricubic{xs: newGridAxis(xs), ys: newGridAxis(ys), zs: newGridAxis(zs), v: gridValues3(xs, ys, zs, v)}
+}
+
+// Predict returns the interpolated value at (x, y, z).
+func (t *Tricubic) Predict(x, y, z float64) float64 {
+	i, wx, nx := t.xs.cubic(x)
+	j, wy, nyw := t.ys.cubic(y)
+	k, wz, nzw := t.zs.cubic(z)
+	ny, nz := len(t.ys), len(t.zs)
+	var v float64
+	for p := 0; p < nx; p++ {
+		for q := 0; q < nyw; q++ {
+			for r := 0; r < nzw; r++ {
+				v += wx[p] * wy[q] * wz[r] * t.v[((i+p)*ny+j+q)*nz+k+r]
+			}
+		}
+	}
+	return v
+}
+
+// gridAxis is the strictly increasing coordinates of a grid along one axis.
+type gridAxis []float64
+
+func newGridAxis(xs []float64) gridAxis {
+	if len(xs) < 2 {
+		panic(tooFewPoints)
+	}
+	for i := 1; i < len(xs); i++ {
+		if !(xs[i] > xs[i-1]) {
+			panic(xsNotIncreasing)
+		}
+	}
+	return append(gridAxis(nil), xs...)
+}
+
+// segment returns the index i of the grid interval containing x, with
+// x clamped to the grid, and the position 0 <= t <= 1 of x within it.
+func (a gridAxis) segment(x float64) (i int, t float64) {
+	n := len(a)
+	switch {
+	case x <= a[0]:
+		return 0, 0
+	case x >= a[n-1]:
+		return n - 2, 1
+	}
+	i = findSegment(a, x)
+	return i, (x - a[i]) / (a[i+1] - a[i])
+}
+
+// linear returns the index of the first of the two grid points that
+// contribute to the linear interpolant at x, and their weights.
+func (a gridAxis) linear(x float64) (i int, w [2]float64) {
+	i, t := a.segment(x)
+	return i, [2]float64{1 - t, t}
+}
+
+// cubic returns the index of the first of the n grid points that
+// contribute to the cubic Hermite interpolant at x, and their weights.
+func (a gridAxis) cubic(x float64) (lo int, w [4]float64, n int) {
+	i, t := a.segment(x)
+	n = len(a)
+	if n > 4 {
+		lo = i - 1
+		if lo < 0 {
+			lo = 0
+		}
+		if lo > n-4 {
+			lo = n - 4
+		}
+		n = 4
+	}
+	h := a[i+1] - a[i]
+	t2 := t * t
+	t3 := t2 * t
+	w[i-lo] += 2*t3 - 3*t2 + 1
+	w[i+1-lo] += -2*t3 + 3*t2
+	a.addDerivative(&w, lo, i, h*(t3-2*t2+t))
+	a.addDerivative(&w, lo, i+1, h*(t3-t2))
+	return lo, w, n
+}
+
+// addDerivative adds to w the weights of the estimate of the derivative at
+// grid point j, scaled by s. The estimate is the derivative of the quadratic
+// through j and its neighbors, or of the line through both points if the
+// axis has only two.
+func (a gridAxis) addDerivative(w *[4]float64, lo, j int, s float64) {
+	n := len(a)
+	if n == 2 {
+		h := a[1] - a[0]
+		w[0-lo] -= s / h
+		w[1-lo] += s / h
+		return
+	}
+	var c0, c2 float64
+	switch j {
+	case 0:
+		h0 := a[1] - a[0]
+		h1 := a[2] - a[1]
+		c1 := (2*h0+h1)/(h0*(h0+h1)) + h0/(h1*(h0+h1))
+		c2 = -h0 / (h1 * (h0 + h1))
+		w[0-lo] += -s * (c1 + c2)
+		w[1-lo] += s * c1
+		w[2-lo] += s * c2
+		return
+	case n - 1:
+		h0 := a[n-2] - a[n-3]
+		h1 := a[n-1] - a[n-2]
+		c0 = h1 / (h0 * (h0 + h1))
+		c2 = (2*h1 + h0) / (h1 * (h0 + h1))
+		j--
+	default:
+		h0 := a[j] - a[j-1]
+		h1 := a[j+1] - a[j]
+		c0 = -h1 / (h0 * (h0 + h1))
+		c2 = h0 / (h1 * (h0 + h1))
+	}
+	w[j-1-lo] += s * c0
+	w[j-lo] -= s * (c0 + c2)
+	w[j+1-lo] += s * c2
+}
+
+// gridValues returns the values of z in row-major order, panicking if the
+// dimensions of z do not match the grid.
+func gridValues(xs, ys []float64, z mat.Matrix) []float64 {
+	r, c := z.Dims()
+	if r != len(xs) || c != len(ys) {
+		panic(differentLengths)
+	}
+	v := make([]float64, r*c)
+	for i := 0; i < r; i++ {
+		for j := 0; j < c; j++ {
+			v[i*c+j] = z.At(i, j)
+		}
+	}
+	return v
+}
+
+// gridValues3 returns a copy of v, panicking if its length does not
+// match the grid.
+func gridValues3(xs, ys, zs, v []float64) []float64 {
+	if len(v) != len(xs)*len(ys)*len(zs) {
+		panic(differentLengths)
+	}
+	return append([]float64(nil), v...)
+}
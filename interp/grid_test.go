@@ -0,0 +1,192 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// gridAxes returns irregularly spaced grid coordinates of the given
+// lengths on [0, 1].
+func gridAxes(rnd *rand.Rand, lens ...int) [][]float64 {
+	axes := make([][]float64, len(lens))
+	for i, n := range lens {
+		xs := make([]float64, n)
+		for j := range xs {
+			xs[j] = float64(j)
+			if j > 0 && j < n-1 {
+				xs[j] += 0.4 * (rnd.Float64() - 0.5)
+			}
+			xs[j] /= float64(n - 1)
+		}
+		axes[i] = xs
+	}
+	return axes
+}
+
+func TestGrid2D(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	bilinear := func(x, y float64) float64 { return 1 + 2*x - y + 3*x*y }
+	quadratic := func(x, y float64) float64 { return 1 + 2*x - y + 3*x*y - x*x + 2*y*y + x*x*y*y }
+	for _, test := range []struct {
+		name  string
+		newFn func(xs, ys []float64, z mat.Matrix) func(x, y float64) float64
+		f     func(x, y float64) float64
+		minN  int
+	}{
+		{
+			name: "bilinear",
+			newFn: func(xs, ys []float64, z mat.Matrix) func(x, y float64) float64 {
+				return NewBilinear(xs, ys, z).Predict
+			},
+			f: bilinear, minN: 2,
+		},
+		{
+			name: "bicubic",
+			newFn: func(xs, ys []float64, z mat.Matrix) func(x, y float64) float64 {
+				return NewBicubic(xs, ys, z).Predict
+			},
+			f: quadratic, minN: 3,
+		},
+		{
+			name: "bicubic",
+			newFn: func(xs, ys []float64, z mat.Matrix) func(x, y float64) float64 {
+				return NewBicubic(xs, ys, z).Predict
+			},
+			f: bilinear, minN: 2,
+		},
+	} {
+		for _, n := range [][2]int{{test.minN, test.minN}, {3, 4}, {4, 5}, {7, 6}, {12, 10}} {
+			axes := gridAxes(rnd, n[0], n[1])
+			xs, ys := axes[0], axes[1]
+			z := mat.NewDense(len(xs), len(ys), nil)
+			for i, x := range xs {
+				for j, y := range ys {
+					z.Set(i, j, test.f(x, y))
+				}
+			}
+			predict := test.newFn(xs, ys, z)
+			for k := 0; k < 100; k++ {
+				x, y := rnd.Float64(), rnd.Float64()
+				if got, want := predict(x, y), test.f(x, y); math.Abs(got-want) > 1e-12 {
+					t.Errorf("%s %v: unexpected value at (%v, %v): got %v, want %v", test.name, n, x, y, got, want)
+				}
+			}
+			// Outside the grid, the value at the boundary is used.
+			if got, want := predict(-1, 2), test.f(0, 1); math.Abs(got-want) > 1e-12 {
+				t.Errorf("%s %v: unexpected extrapolated value: got %v, want %v", test.name, n, got, want)
+			}
+		}
+	}
+}
+
+func TestGrid3D(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	trilinear := func(x, y, z float64) float64 { return 1 + x - 2*y + z + x*y*z - 3*y*z }
+	quadratic := func(x, y, z float64) float64 { return trilinear(x, y, z) + x*x - z*z + x*y*y*z*z }
+	for _, test := range []struct {
+		name  string
+		newFn func(xs, ys, zs, v []float64) func(x, y, z float64) float64
+		f     func(x, y, z float64) float64
+	}{
+		{
+			name: "trilinear",
+			newFn: func(xs, ys, zs, v []float64) func(x, y, z float64) float64 {
+				return NewTrilinear(xs, ys, zs, v).Predict
+			},
+			f: trilinear,
+		},
+		{
+			name: "tricubic",
+			newFn: func(xs, ys, zs, v []float64) func(x, y, z float64) float64 {
+				return NewTricubic(xs, ys, zs, v).Predict
+			},
+			f: quadratic,
+		},
+	} {
+		for _, n := range [][3]int{{3, 3, 3}, {4, 5, 3}, {8, 6, 7}} {
+			axes := gridAxes(rnd, n[0], n[1], n[2])
+			xs, ys, zs := axes[0], axes[1], axes[2]
+			v := make([]float64, 0, len(xs)*len(ys)*len(zs))
+			for _, x := range xs {
+				for _, y := range ys {
+					for _, z := range zs {
+						v = append(v, test.f(x, y, z))
+					}
+				}
+			}
+			predict := test.newFn(xs, ys, zs, v)
+			for k := 0; k < 100; k++ {
+				x, y, z := rnd.Float64(), rnd.Float64(), rnd.Float64()
+				if got, want := predict(x, y, z), test.f(x, y, z); math.Abs(got-want) > 1e-12 {
+					t.Errorf("%s %v: unexpected value at (%v, %v, %v): got %v, want %v", test.name, n, x, y, z, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestBicubicSmooth(t *testing.T) {
+	t.Parallel()
+	// The bicubic interpolant of a smooth function converges faster than
+	// the bilinear interpolant.
+	f := func(x, y float64) float64 { return math.Sin(3*x) * math.Cos(2*y) }
+	maxErr := func(n int) (lin, cub float64) {
+		xs := make([]float64, n)
+		for i := range xs {
+			xs[i] = float64(i) / float64(n-1)
+		}
+		z := mat.NewDense(n, n, nil)
+		for i, x := range xs {
+			for j, y := range xs {
+				z.Set(i, j, f(x, y))
+			}
+		}
+		bl := NewBilinear(xs, xs, z)
+		bc := NewBicubic(xs, xs, z)
+		for i := 0; i <= 50; i++ {
+			for j := 0; j <= 50; j++ {
+				x, y := float64(i)/50, float64(j)/50
+				lin = math.Max(lin, math.Abs(bl.Predict(x, y)-f(x, y)))
+				cub = math.Max(cub, math.Abs(bc.Predict(x, y)-f(x, y)))
+			}
+		}
+		return lin, cub
+	}
+	lin1, cub1 := maxErr(11)
+	lin2, cub2 := maxErr(21)
+	if rate := math.Log2(lin1 / lin2); rate < 1.8 {
+		t.Errorf("bilinear convergence rate too low: %v", rate)
+	}
+	if rate := math.Log2(cub1 / cub2); rate < 2.8 {
+		t.Errorf("bicubic convergence rate too low: %v", rate)
+	}
+}
+
+func TestGridPanics(t *testing.T) {
+	t.Parallel()
+	xs := []float64{0, 1, 2}
+	z := mat.NewDense(3, 3, nil)
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{name: "short axis", fn: func() { NewBilinear([]float64{0}, xs, mat.NewDense(1, 3, nil)) }},
+		{name: "decreasing axis", fn: func() { NewBicubic([]float64{0, 2, 1}, xs, z) }},
+		{name: "bad dimensions", fn: func() { NewBicubic(xs, xs[:2], z) }},
+		{name: "bad length", fn: func() { NewTricubic(xs, xs, xs, make([]float64, 26)) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/spatial/kdtree"
+)
+
+var _ FittableMultiPredictor = (*IDW)(nil)
+
+// IDW is an inverse distance weighting interpolator of scattered data in
+// any number of dimensions, also known as Shepard's method. The predicted
+// value is the weighted mean
+//  s(x) = \sum_i w_i y_i / \sum_i w_i,  w_i = 1 / |x - x_i|^p,
+// over the data points x_i near x. Neighbouring points are found with
+// a k-d tree.
+type IDW struct {
+	// Power is the power p of the distance in the weights. If Power is
+	// zero, a default of 2 is used.
+	Power float64
+
+	// Neighbors is the number of nearest data points used for each
+	// prediction. If Neighbors is zero, all points within Radius are used.
+	Neighbors int
+
+	// Radius, if positive and Neighbors is zero, is the distance within
+	// which data points are used for prediction. If both Neighbors and
+	// Radius are zero, all data points are used.
+	Radius float64
+
+	tree   *kdtree.Tree
+	points idwPoints
+}
+
+// Fit fits the interpolator to the data points given by the rows of x with
+// values y. It panics if the number of rows of x is not equal to len(y),
+// there are no points, or Power, Neighbors or Radius is negative.
+// Always returns nil.
+func (idw *IDW) Fit(x mat.Matrix, y []float64) error {
+	if idw.Power < 0 || idw.Neighbors < 0 || idw.Radius < 0 {
+		panic("interp: negative IDW parameter")
+	}
+	pts := copyPoints(x, len(y), 1)
+	idw.points = make(idwPoints, len(pts))
+	for i, p := range pts {
+		idw.points[i] = idwPoint{x: p, y: y[i]}
+	}
+	// kdtree.New reorders its input, so build it from a copy.
+	idw.tree = kdtree.New(append(idwPoints(nil), idw.points...), false)
+	return nil
+}
+
+// Predict returns the interpolated value at x. If x coincides with a data
+// point, the value at that point is returned. If no data points are within
+// Radius of x, Predict returns NaN. Predict panics if len(x) is not the
+// dimension of the data.
+func (idw *IDW) Predict(x []float64) float64 {
+	if len(x) != len(idw.points[0].x) {
+		panic(differentLengths)
+	}
+	q := idwPoint{x: x}
+	p := idw.Power
+	if p == 0 {
+		p = 2
+	}
+
+	var sum, wsum float64
+	add := func(c kdtree.Comparable, d2 float64) bool {
+		pt := c.(idwPoint)
+		if d2 == 0 {
+			sum, wsum = pt.y, 1
+			return true
+		}
+		// The distances are squared.
+		w := math.Pow(d2, -p/2)
+		sum += w * pt.y
+		wsum += w
+		return false
+	}
+
+	switch {
+	case idw.Neighbors > 0:
+		keep := kdtree.NewNKeeper(idw.Neighbors)
+		idw.tree.NearestSet(keep, q)
+		for _, c := range keep.Heap {
+			if c.Comparable != nil && add(c.Comparable, c.Dist) {
+				break
+			}
+		}
+	case idw.Radius > 0:
+		keep := kdtree.NewDistKeeper(idw.Radius * idw.Radius)
+		idw.tree.NearestSet(keep, q)
+		for _, c := range keep.Heap {
+			if c.Comparable != nil && add(c.Comparable, c.Dist) {
+				break
+			}
+		}
+		if wsum == 0 {
+			return math.NaN()
+		}
+	default:
+		for _, pt := range idw.points {
+			if add(pt, pt.Distance(q)) {
+				break
+			}
+		}
+	}
+	return sum / wsum
+}
+
+// idwPoint is a data point that satisfies the kdtree.Comparable interface.
+type idwPoint struct {
+	x []float64
+	y float64
+}
+
+func (p idwPoint) Compare(c kdtree.Comparable, d kdtree.Dim) float64 {
+	return p.x[d] - c.(idwPoint).x[d]
+}
+
+func (p idwPoint) Dims() int { return len(p.x) }
+
+// Distance returns the squared Euclidean distance between p and c.
+func (p idwPoint) Distance(c kdtree.Comparable) float64 {
+	q := c.(idwPoint)
+	var sum float64
+	for i, v := range p.x {
+		d := v - q.x[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// idwPoints is a collection of data points that satisfies the
+// kdtree.Interface.
+type idwPoints []idwPoint
+
+func (p idwPoints) Index(i int) kdtree.Comparable         { return p[i] }
+func (p idwPoints) Len() int                              { return len(p) }
+func (p idwPoints) Pivot(d kdtree.Dim) int                { return idwPlane{idwPoints: p, Dim: d}.Pivot() }
+func (p idwPoints) Slice(start, end int) kdtree.Interface { return p[start:end] }
+
+// idwPlane allows idwPoints to be pivoted on a dimension.
+type idwPlane struct {
+	kdtree.Dim
+	idwPoints
+}
+
+func (p idwPlane) Less(i, j int) bool {
+	return p.idwPoints[i].x[p.Dim] < p.idwPoints[j].x[p.Dim]
+}
+func (p idwPlane) Pivot() int { return kdtree.Partition(p, kdtree.MedianOfMedians(p)) }
+func (p idwPlane) Slice(start, end int) kdtree.SortSlicer {
+	p.idwPoints = p.idwPoints[start:end]
+	return p
+}
+func (p idwPlane) Swap(i, j int) {
+	p.idwPoints[i], p.idwPoints[j] = p.idwPoints[j], p.idwPoints[i]
+}
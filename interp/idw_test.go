@@ -0,0 +1,107 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestIDW(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	const n = 100
+	x := scatteredPoints(rnd, n, 3)
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = rnd.NormFloat64()
+	}
+	for _, test := range []IDW{
+		{},
+		{Power: 3},
+		{Neighbors: 5},
+		{Neighbors: 1},
+		{Radius: 0.3},
+		{Power: 1, Radius: 0.5},
+	} {
+		idw := test
+		if err := idw.Fit(x, y); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i, want := range y {
+			if got := idw.Predict(mat.Row(nil, i, x)); got != want {
+				t.Errorf("%+v: unexpected value at data point %d: got %v, want %v", test, i, got, want)
+			}
+		}
+		for k := 0; k < 20; k++ {
+			q := []float64{rnd.Float64(), rnd.Float64(), rnd.Float64()}
+			got := idw.Predict(q)
+			want := naiveIDW(x, y, q, test)
+			if math.IsNaN(want) {
+				if !math.IsNaN(got) {
+					t.Errorf("%+v: expected NaN at %v, got %v", test, q, got)
+				}
+				continue
+			}
+			if !floats.EqualWithinAbsOrRel(got, want, 1e-12, 1e-12) {
+				t.Errorf("%+v: unexpected value at %v: got %v, want %v", test, q, got, want)
+			}
+		}
+	}
+}
+
+// naiveIDW computes the inverse distance weighted mean by brute force.
+func naiveIDW(x mat.Matrix, y, q []float64, idw IDW) float64 {
+	p := idw.Power
+	if p == 0 {
+		p = 2
+	}
+	type neighbor struct {
+		d, y float64
+	}
+	var nbs []neighbor
+	for i := range y {
+		d := floats.Distance(mat.Row(nil, i, x), q, 2)
+		if idw.Neighbors == 0 && idw.Radius > 0 && d > idw.Radius {
+			continue
+		}
+		nbs = append(nbs, neighbor{d: d, y: y[i]})
+	}
+	if idw.Neighbors > 0 {
+		sort.Slice(nbs, func(i, j int) bool { return nbs[i].d < nbs[j].d })
+		nbs = nbs[:idw.Neighbors]
+	}
+	if len(nbs) == 0 {
+		return math.NaN()
+	}
+	var sum, wsum float64
+	for _, nb := range nbs {
+		w := math.Pow(nb.d, -p)
+		sum += w * nb.y
+		wsum += w
+	}
+	return sum / wsum
+}
+
+func TestIDWRadiusEmpty(t *testing.T) {
+	t.Parallel()
+	x := mat.NewDense(2, 2, []float64{0, 0, 1, 0})
+	idw := IDW{Radius: 0.1}
+	if err := idw.Fit(x, []float64{1, 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := idw.Predict([]float64{0.5, 0.5}); !math.IsNaN(got) {
+		t.Errorf("expected NaN with no points within radius, got %v", got)
+	}
+	if got := idw.Predict([]float64{0.95, 0}); math.Abs(got-2) > 1e-14 {
+		t.Errorf("unexpected value with one point within radius: got %v, want 2", got)
+	}
+}
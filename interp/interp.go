@@ -0,0 +1,90 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import "sort"
+
+const (
+	differentLengths = "interp: input slices have different lengths"
+	tooFewPoints     = "interp: too few points for interpolation"
+	xsNotIncreasing  = "interp: xs values not strictly increasing"
+)
+
+// Predictor predicts the value of a function. It handles both
+// interpolation and extrapolation.
+type Predictor interface {
+	// Predict returns the predicted value at x.
+	Predict(x float64) float64
+}
+
+// Fitter fits a predictor to data.
+type Fitter interface {
+	// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+	// It panics if len(xs) < 2, elements of xs are not strictly increasing
+	// or len(xs) != len(ys). Returns an error if fitting fails.
+	Fit(xs, ys []float64) error
+}
+
+// FittablePredictor is a Predictor which can fit itself to data.
+type FittablePredictor interface {
+	Fitter
+	Predictor
+}
+
+// DerivativePredictor predicts both the value and the derivative of
+// a function. It handles both interpolation and extrapolation.
+type DerivativePredictor interface {
+	Predictor
+
+	// PredictDerivative returns the predicted derivative at x.
+	PredictDerivative(x float64) float64
+}
+
+// Integrator computes definite integrals of a predicted function.
+type Integrator interface {
+	// Integrate returns the integral of the predicted function from a to b.
+	// If a > b, the integral is negative.
+	Integrate(a, b float64) float64
+}
+
+// findSegment returns 0 <= i < len(xs) such that xs[i] <= x < xs[i + 1],
+// where xs[len(xs)] is assumed to be +Inf. If no such i is found, it
+// returns -1. It assumes that len(xs) >= 2 without checking.
+func findSegment(xs []float64, x float64) int {
+	return sort.Search(len(xs), func(i int) bool { return xs[i] > x }) - 1
+}
+
+// checkXs panics if len(xs) < n, len(xs) != len(ys) or the elements of xs
+// are not strictly increasing.
+func checkXs(xs, ys []float64, n int) {
+	if len(xs) != len(ys) {
+		panic(differentLengths)
+	}
+	if len(xs) < n {
+		panic(tooFewPoints)
+	}
+	for i := 1; i < len(xs); i++ {
+		if !(xs[i] > xs[i-1]) {
+			panic(xsNotIncreasing)
+		}
+	}
+}
+
+// integrateExtended returns the integral from a to b of a function with
+// antiderivative F on [lo, hi] that is extended by the constant values
+// flo and fhi below lo and above hi.
+func integrateExtended(F func(x float64) float64, lo, hi, flo, fhi, a, b float64) float64 {
+	g := func(x float64) float64 {
+		switch {
+		case x < lo:
+			return F(lo) + (x-lo)*flo
+		case x > hi:
+			return F(hi) + (x-hi)*fhi
+		default:
+			return F(x)
+		}
+	}
+	return g(b) - g(a)
+}
@@ -0,0 +1,120 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestFindSegment(t *testing.T) {
+	t.Parallel()
+	xs := []float64{0, 1, 2}
+	for _, test := range []struct {
+		x    float64
+		want int
+	}{
+		{x: -0.5, want: -1},
+		{x: 0, want: 0},
+		{x: 0.5, want: 0},
+		{x: 1, want: 1},
+		{x: 1.5, want: 1},
+		{x: 2, want: 2},
+		{x: 2.5, want: 2},
+	} {
+		if got := findSegment(xs, test.x); got != test.want {
+			t.Errorf("unexpected segment for x=%v: got %d, want %d", test.x, got, test.want)
+		}
+	}
+}
+
+func TestCheckXs(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		xs, ys []float64
+		panics bool
+	}{
+		{xs: []float64{0, 1}, ys: []float64{0, 1}},
+		{xs: []float64{0}, ys: []float64{0}, panics: true},
+		{xs: []float64{0, 1}, ys: []float64{0}, panics: true},
+		{xs: []float64{0, 1, 1}, ys: []float64{0, 1, 2}, panics: true},
+		{xs: []float64{0, 2, 1}, ys: []float64{0, 1, 2}, panics: true},
+	} {
+		if got := panics(func() { checkXs(test.xs, test.ys, 2) }); got != test.panics {
+			t.Errorf("unexpected panic status for xs=%v ys=%v: got %t, want %t", test.xs, test.ys, got, test.panics)
+		}
+	}
+}
+
+// interpolator is a fittable predictor of values, derivatives and integrals.
+type interpolator interface {
+	Fitter
+	DerivativePredictor
+	Integrator
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+// testPredictor checks the values, derivatives and integrals of p against
+// those of the function f with derivative df and antiderivative F, on a
+// grid spanning [lo, hi].
+func testPredictor(t *testing.T, name string, p interface {
+	DerivativePredictor
+	Integrator
+}, f, df, F func(float64) float64, lo, hi, tol float64) {
+	t.Helper()
+	const n = 37
+	for i := 0; i <= n; i++ {
+		x := lo + float64(i)/n*(hi-lo)
+		if got, want := p.Predict(x), f(x); !floats.EqualWithinAbsOrRel(got, want, tol, tol) {
+			t.Errorf("%s: unexpected value at x=%v: got %v, want %v", name, x, got, want)
+		}
+		if df != nil {
+			// Avoid the end points, where the one-sided derivative of
+			// the extension is zero.
+			if x > lo && x < hi {
+				if got, want := p.PredictDerivative(x), df(x); !floats.EqualWithinAbsOrRel(got, want, tol, tol) {
+					t.Errorf("%s: unexpected derivative at x=%v: got %v, want %v", name, x, got, want)
+				}
+			}
+		}
+		if F != nil {
+			if got, want := p.Integrate(lo, x), F(x)-F(lo); !floats.EqualWithinAbsOrRel(got, want, tol, tol) {
+				t.Errorf("%s: unexpected integral over [%v, %v]: got %v, want %v", name, lo, x, got, want)
+			}
+			if got, want := p.Integrate(x, lo), F(lo)-F(x); !floats.EqualWithinAbsOrRel(got, want, tol, tol) {
+				t.Errorf("%s: unexpected integral over [%v, %v]: got %v, want %v", name, x, lo, got, want)
+			}
+		}
+	}
+	if got, want := p.Predict(lo-1), p.Predict(lo); got != want {
+		t.Errorf("%s: unexpected extrapolation below range: got %v, want %v", name, got, want)
+	}
+	if got, want := p.Predict(hi+1), p.Predict(hi); got != want {
+		t.Errorf("%s: unexpected extrapolation above range: got %v, want %v", name, got, want)
+	}
+	if got := p.PredictDerivative(hi + 1); got != 0 {
+		t.Errorf("%s: unexpected derivative above range: got %v", name, got)
+	}
+	if got, want := p.Integrate(lo-1, hi+1), p.Integrate(lo, hi)+p.Predict(lo)+p.Predict(hi); !floats.EqualWithinAbsOrRel(got, want, tol, tol) {
+		t.Errorf("%s: unexpected integral of extension: got %v, want %v", name, got, want)
+	}
+}
+
+// sample returns the values of f at xs.
+func sample(f func(float64) float64, xs []float64) []float64 {
+	ys := make([]float64, len(xs))
+	for i, x := range xs {
+		ys[i] = f(x)
+	}
+	return ys
+}
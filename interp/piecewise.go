@@ -0,0 +1,130 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+var (
+	_ FittablePredictor   = (*PiecewiseConstant)(nil)
+	_ DerivativePredictor = (*PiecewiseConstant)(nil)
+	_ Integrator          = (*PiecewiseConstant)(nil)
+
+	_ FittablePredictor   = (*PiecewiseLinear)(nil)
+	_ DerivativePredictor = (*PiecewiseLinear)(nil)
+	_ Integrator          = (*PiecewiseLinear)(nil)
+)
+
+// PiecewiseConstant is a piecewise constant 1-dimensional interpolator.
+// The interpolant takes the value ys[i] on the interval [xs[i], xs[i+1]).
+type PiecewiseConstant struct {
+	xs []float64
+	ys []float64
+	// cum[i] is the integral from xs[0] to xs[i].
+	cum []float64
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Always returns nil.
+func (pc *PiecewiseConstant) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	n := len(xs)
+	pc.xs = append(pc.xs[:0], xs...)
+	pc.ys = append(pc.ys[:0], ys...)
+	pc.cum = append(pc.cum[:0], 0)
+	for i := 1; i < n; i++ {
+		pc.cum = append(pc.cum, pc.cum[i-1]+ys[i-1]*(xs[i]-xs[i-1]))
+	}
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (pc *PiecewiseConstant) Predict(x float64) float64 {
+	i := findSegment(pc.xs, x)
+	if i < 0 {
+		return pc.ys[0]
+	}
+	return pc.ys[i]
+}
+
+// PredictDerivative returns the derivative of the interpolant at x, which
+// is zero everywhere except at the nodes, where it is undefined and
+// returned as zero.
+func (pc *PiecewiseConstant) PredictDerivative(x float64) float64 {
+	return 0
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (pc *PiecewiseConstant) Integrate(a, b float64) float64 {
+	n := len(pc.xs)
+	return integrateExtended(func(x float64) float64 {
+		i := findSegment(pc.xs, x)
+		if i == n-1 {
+			return pc.cum[i]
+		}
+		return pc.cum[i] + pc.ys[i]*(x-pc.xs[i])
+	}, pc.xs[0], pc.xs[n-1], pc.ys[0], pc.ys[n-1], a, b)
+}
+
+// PiecewiseLinear is a piecewise linear 1-dimensional interpolator.
+type PiecewiseLinear struct {
+	xs     []float64
+	ys     []float64
+	slopes []float64
+	// cum[i] is the integral from xs[0] to xs[i].
+	cum []float64
+}
+
+// Fit fits a predictor to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing
+// or len(xs) != len(ys). Always returns nil.
+func (pl *PiecewiseLinear) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	n := len(xs)
+	pl.xs = append(pl.xs[:0], xs...)
+	pl.ys = append(pl.ys[:0], ys...)
+	pl.slopes = pl.slopes[:0]
+	pl.cum = append(pl.cum[:0], 0)
+	for i := 1; i < n; i++ {
+		h := xs[i] - xs[i-1]
+		pl.slopes = append(pl.slopes, (ys[i]-ys[i-1])/h)
+		pl.cum = append(pl.cum, pl.cum[i-1]+h*(ys[i]+ys[i-1])/2)
+	}
+	return nil
+}
+
+// Predict returns the interpolation value at x.
+func (pl *PiecewiseLinear) Predict(x float64) float64 {
+	i := findSegment(pl.xs, x)
+	switch {
+	case i < 0:
+		return pl.ys[0]
+	case i == len(pl.xs)-1:
+		return pl.ys[i]
+	}
+	return pl.ys[i] + pl.slopes[i]*(x-pl.xs[i])
+}
+
+// PredictDerivative returns the derivative of the interpolant at x.
+// At the interior nodes, the derivative of the interval to the right
+// is returned.
+func (pl *PiecewiseLinear) PredictDerivative(x float64) float64 {
+	i := findSegment(pl.xs, x)
+	if i < 0 || i == len(pl.xs)-1 {
+		return 0
+	}
+	return pl.slopes[i]
+}
+
+// Integrate returns the integral of the interpolant from a to b.
+func (pl *PiecewiseLinear) Integrate(a, b float64) float64 {
+	n := len(pl.xs)
+	return integrateExtended(func(x float64) float64 {
+		i := findSegment(pl.xs, x)
+		if i == n-1 {
+			return pl.cum[i]
+		}
+		dx := x - pl.xs[i]
+		return pl.cum[i] + dx*(pl.ys[i]+pl.slopes[i]*dx/2)
+	}, pl.xs[0], pl.xs[n-1], pl.ys[0], pl.ys[n-1], a, b)
+}
@@ -0,0 +1,89 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPiecewiseConstant(t *testing.T) {
+	t.Parallel()
+	xs := []float64{0, 1, 3, 4}
+	ys := []float64{2, -1, 0.5, 7}
+	var pc PiecewiseConstant
+	if err := pc.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		x, want float64
+	}{
+		{x: -1, want: 2},
+		{x: 0, want: 2},
+		{x: 0.5, want: 2},
+		{x: 1, want: -1},
+		{x: 2.9, want: -1},
+		{x: 3, want: 0.5},
+		{x: 4, want: 7},
+		{x: 5, want: 7},
+	} {
+		if got := pc.Predict(test.x); got != test.want {
+			t.Errorf("unexpected value at x=%v: got %v, want %v", test.x, got, test.want)
+		}
+		if got := pc.PredictDerivative(test.x); got != 0 {
+			t.Errorf("unexpected derivative at x=%v: got %v", test.x, got)
+		}
+	}
+	for _, test := range []struct {
+		a, b, want float64
+	}{
+		{a: 0, b: 4, want: 2 - 2 + 0.5},
+		{a: 0.5, b: 2, want: 1 - 1},
+		{a: 2, b: 0.5, want: 0},
+		{a: -1, b: 5, want: 2 + 0.5 + 7},
+		{a: 3.5, b: 3.5, want: 0},
+	} {
+		if got := pc.Integrate(test.a, test.b); math.Abs(got-test.want) > 1e-14 {
+			t.Errorf("unexpected integral over [%v, %v]: got %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestPiecewiseLinear(t *testing.T) {
+	t.Parallel()
+	// A linear function is reproduced exactly.
+	f := func(x float64) float64 { return 3*x - 1 }
+	df := func(float64) float64 { return 3 }
+	F := func(x float64) float64 { return 1.5*x*x - x }
+	xs := []float64{-2, -1.5, 0, 0.25, 1, 3}
+	var pl PiecewiseLinear
+	if err := pl.Fit(xs, sample(f, xs)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	testPredictor(t, "PiecewiseLinear", &pl, f, df, F, -2, 3, 1e-13)
+
+	xs = []float64{0, 1, 3}
+	ys := []float64{0, 2, 1}
+	if err := pl.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range []struct {
+		x, want, deriv float64
+	}{
+		{x: 0.5, want: 1, deriv: 2},
+		{x: 1, want: 2, deriv: -0.5},
+		{x: 2, want: 1.5, deriv: -0.5},
+	} {
+		if got := pl.Predict(test.x); got != test.want {
+			t.Errorf("unexpected value at x=%v: got %v, want %v", test.x, got, test.want)
+		}
+		if got := pl.PredictDerivative(test.x); got != test.deriv {
+			t.Errorf("unexpected derivative at x=%v: got %v, want %v", test.x, got, test.deriv)
+		}
+	}
+	if got, want := pl.Integrate(0, 3), 1+3.0; got != want {
+		t.Errorf("unexpected integral: got %v, want %v", got, want)
+	}
+}
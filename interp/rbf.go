@@ -0,0 +1,238 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ FittableMultiPredictor = (*RBF)(nil)
+
+// Kernel is a radial basis function φ(r) of the distance r between points.
+type Kernel interface {
+	// Eval returns the value of the kernel at r >= 0.
+	Eval(r float64) float64
+
+	// PolynomialDegree returns the minimum degree of the polynomial tail
+	// needed for the interpolation problem to have a unique solution, or
+	// -1 if no polynomial is needed.
+	PolynomialDegree() int
+}
+
+// Gaussian is the Gaussian kernel
+//  φ(r) = exp(-(εr)^2),
+// with shape parameter ε = Epsilon. If Epsilon is zero, one is used.
+type Gaussian struct {
+	Epsilon float64
+}
+
+// Eval returns the value of the kernel at r.
+func (g Gaussian) Eval(r float64) float64 {
+	e := shape(g.Epsilon) * r
+	return math.Exp(-e * e)
+}
+
+// PolynomialDegree returns -1, since the Gaussian kernel is positive definite.
+func (Gaussian) PolynomialDegree() int { return -1 }
+
+// Multiquadric is the multiquadric kernel
+//  φ(r) = sqrt(1 + (εr)^2),
+// with shape parameter ε = Epsilon. If Epsilon is zero, one is used.
+type Multiquadric struct {
+	Epsilon float64
+}
+
+// Eval returns the value of the kernel at r.
+func (m Multiquadric) Eval(r float64) float64 {
+	e := shape(m.Epsilon) * r
+	return math.Sqrt(1 + e*e)
+}
+
+// PolynomialDegree returns 0, since the multiquadric kernel is conditionally
+// negative definite of order one.
+func (Multiquadric) PolynomialDegree() int { return 0 }
+
+// InverseMultiquadric is the inverse multiquadric kernel
+//  φ(r) = 1 / sqrt(1 + (εr)^2),
+// with shape parameter ε = Epsilon. If Epsilon is zero, one is used.
+type InverseMultiquadric struct {
+	Epsilon float64
+}
+
+// Eval returns the value of the kernel at r.
+func (m InverseMultiquadric) Eval(r float64) float64 {
+	e := shape(m.Epsilon) * r
+	return 1 / math.Sqrt(1+e*e)
+}
+
+// PolynomialDegree returns -1, since the inverse multiquadric kernel is
+// positive definite.
+func (InverseMultiquadric) PolynomialDegree() int { return -1 }
+
+// ThinPlate is the thin-plate spline kernel
+//  φ(r) = r^2 log(r),
+// which minimizes the bending energy of the interpolant in two dimensions.
+type ThinPlate struct{}
+
+// Eval returns the value of the kernel at r.
+func (ThinPlate) Eval(r float64) float64 {
+	if r == 0 {
+		return 0
+	}
+	return r * r * math.Log(r)
+}
+
+// PolynomialDegree returns 1, since the thin-plate kernel is conditionally
+// positive definite of order two.
+func (ThinPlate) PolynomialDegree() int { return 1 }
+
+func shape(eps float64) float64 {
+	if eps == 0 {
+		return 1
+	}
+	return eps
+}
+
+// RBF is a radial basis function interpolator of scattered data in any
+// number of dimensions. The interpolant is
+//  s(x) = \sum_i w_i φ(|x - x_i|) + p(x),
+// where φ is the kernel and p is a polynomial of total degree at most
+// Degree. The weights and polynomial coefficients are determined by the
+// interpolation conditions s(x_i) = y_i and the orthogonality conditions
+// \sum_i w_i q(x_i) = 0 for all polynomials q of degree at most Degree.
+type RBF struct {
+	// Kernel is the radial basis function. If Kernel is nil, ThinPlate
+	// is used.
+	Kernel Kernel
+
+	// Degree is the degree of the polynomial tail. If Degree is less than
+	// the minimum degree required by the kernel, the minimum degree is
+	// used. A negative Degree with a positive definite kernel omits the
+	// polynomial tail.
+	Degree int
+
+	// Smoothing, if positive, is added to the diagonal of the kernel matrix
+	// so that the data are approximated rather than interpolated.
+	Smoothing float64
+
+	kernel Kernel
+	points [][]float64
+	// powers holds the exponents of the monomials of the polynomial tail.
+	powers  [][]int
+	weights []float64
+	coeffs  []float64
+}
+
+// Fit fits the interpolant to the data points given by the rows of x with
+// values y. It panics if the number of rows of x is not equal to len(y) or
+// Smoothing is negative. Fit returns an error if the interpolation system
+// is singular, which may happen if points are repeated or there are too
+// few points to determine the polynomial tail.
+func (r *RBF) Fit(x mat.Matrix, y []float64) error {
+	if r.Smoothing < 0 {
+		panic("interp: negative smoothing")
+	}
+	pts := copyPoints(x, len(y), 1)
+	n := len(pts)
+	dim := len(pts[0])
+
+	r.kernel = r.Kernel
+	if r.kernel == nil {
+		r.kernel = ThinPlate{}
+	}
+	degree := r.Degree
+	if min := r.kernel.PolynomialDegree(); degree < min {
+		degree = min
+	}
+	r.powers = monomials(dim, degree)
+	m := len(r.powers)
+
+	a := mat.NewSymDense(n+m, nil)
+	for i := 0; i < n; i++ {
+		a.SetSym(i, i, r.kernel.Eval(0)+r.Smoothing)
+		for j := i + 1; j < n; j++ {
+			a.SetSym(i, j, r.kernel.Eval(floats.Distance(pts[i], pts[j], 2)))
+		}
+		for k, p := range r.powers {
+			a.SetSym(i, n+k, monomial(pts[i], p))
+		}
+	}
+	b := mat.NewVecDense(n+m, nil)
+	for i, v := range y {
+		b.SetVec(i, v)
+	}
+
+	// The system is symmetric but indefinite when a polynomial tail
+	// is present.
+	var lu mat.LU
+	lu.Factorize(a)
+	if lu.Det() == 0 {
+		return errSingular
+	}
+	var sol mat.VecDense
+	err := lu.SolveVecTo(&sol, false, b)
+	if err != nil {
+		return err
+	}
+	r.points = pts
+	r.weights = append(r.weights[:0], sol.RawVector().Data[:n]...)
+	r.coeffs = append(r.coeffs[:0], sol.RawVector().Data[n:]...)
+	return nil
+}
+
+// Predict returns the value of the interpolant at x. It panics if len(x)
+// is not the dimension of the data.
+func (r *RBF) Predict(x []float64) float64 {
+	if len(x) != len(r.points[0]) {
+		panic(differentLengths)
+	}
+	var v float64
+	for i, p := range r.points {
+		v += r.weights[i] * r.kernel.Eval(floats.Distance(x, p, 2))
+	}
+	for k, p := range r.powers {
+		v += r.coeffs[k] * monomial(x, p)
+	}
+	return v
+}
+
+// monomials returns the exponents of all monomials in dim variables of
+// total degree at most degree, in order of increasing degree.
+func monomials(dim, degree int) [][]int {
+	var powers [][]int
+	for d := 0; d <= degree; d++ {
+		powers = appendMonomials(powers, make([]int, dim), 0, d)
+	}
+	return powers
+}
+
+// appendMonomials appends the exponents of the monomials of total degree d
+// in the variables from index i on, with the lower variables fixed by p.
+func appendMonomials(powers [][]int, p []int, i, d int) [][]int {
+	if i == len(p)-1 {
+		p[i] = d
+		return append(powers, append([]int(nil), p...))
+	}
+	for e := d; e >= 0; e-- {
+		p[i] = e
+		powers = appendMonomials(powers, p, i+1, d-e)
+	}
+	p[i] = 0
+	return powers
+}
+
+// monomial returns the value of the monomial with exponents p at x.
+func monomial(x []float64, p []int) float64 {
+	v := 1.0
+	for i, e := range p {
+		for ; e > 0; e-- {
+			v *= x[i]
+		}
+	}
+	return v
+}
@@ -0,0 +1,164 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// scatteredPoints returns n random points in the unit hypercube of
+// dimension dim as the rows of a matrix.
+func scatteredPoints(rnd *rand.Rand, n, dim int) *mat.Dense {
+	x := mat.NewDense(n, dim, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < dim; j++ {
+			x.Set(i, j, rnd.Float64())
+		}
+	}
+	return x
+}
+
+// values returns the values of f at the rows of x.
+func values(x mat.Matrix, f func([]float64) float64) []float64 {
+	n, _ := x.Dims()
+	y := make([]float64, n)
+	for i := range y {
+		y[i] = f(mat.Row(nil, i, x))
+	}
+	return y
+}
+
+func TestRBFInterpolates(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	f := func(x []float64) float64 {
+		return math.Sin(3*x[0]) + x[1]*x[1]
+	}
+	for _, kernel := range []Kernel{
+		nil,
+		ThinPlate{},
+		Gaussian{Epsilon: 3},
+		Multiquadric{Epsilon: 2},
+		InverseMultiquadric{Epsilon: 2},
+	} {
+		x := scatteredPoints(rnd, 30, 2)
+		y := values(x, f)
+		r := RBF{Kernel: kernel}
+		if err := r.Fit(x, y); err != nil {
+			t.Fatalf("kernel %T: unexpected error: %v", kernel, err)
+		}
+		for i, want := range y {
+			if got := r.Predict(mat.Row(nil, i, x)); math.Abs(got-want) > 1e-8 {
+				t.Errorf("kernel %T: unexpected value at data point %d: got %v, want %v", kernel, i, got, want)
+			}
+		}
+	}
+}
+
+func TestRBFPolynomialReproduction(t *testing.T) {
+	t.Parallel()
+	// An RBF interpolant with a polynomial tail of degree d reproduces
+	// polynomials of degree at most d.
+	rnd := rand.New(rand.NewSource(1))
+	for _, test := range []struct {
+		kernel Kernel
+		degree int
+		dim    int
+		f      func([]float64) float64
+	}{
+		{
+			kernel: ThinPlate{}, degree: 1, dim: 2,
+			f: func(x []float64) float64 { return 1 + 2*x[0] - 3*x[1] },
+		},
+		{
+			kernel: Multiquadric{}, degree: 0, dim: 3,
+			f: func(x []float64) float64 { return 4 },
+		},
+		{
+			kernel: Gaussian{Epsilon: 2}, degree: 2, dim: 2,
+			f: func(x []float64) float64 { return x[0]*x[1] - x[0]*x[0] + 0.5 },
+		},
+		{
+			kernel: Multiquadric{}, degree: 1, dim: 3,
+			f: func(x []float64) float64 { return x[0] - x[1] + 2*x[2] },
+		},
+	} {
+		x := scatteredPoints(rnd, 25, test.dim)
+		r := RBF{Kernel: test.kernel, Degree: test.degree}
+		if err := r.Fit(x, values(x, test.f)); err != nil {
+			t.Fatalf("kernel %T: unexpected error: %v", test.kernel, err)
+		}
+		for i := 0; i < 20; i++ {
+			q := make([]float64, test.dim)
+			for j := range q {
+				q[j] = rnd.Float64()
+			}
+			if got, want := r.Predict(q), test.f(q); math.Abs(got-want) > 1e-8 {
+				t.Errorf("kernel %T degree %d: unexpected value at %v: got %v, want %v", test.kernel, test.degree, q, got, want)
+			}
+		}
+	}
+}
+
+func TestRBFSmoothing(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	x := scatteredPoints(rnd, 40, 2)
+	y := make([]float64, 40)
+	for i := range y {
+		y[i] = rnd.NormFloat64()
+	}
+	// With heavy smoothing, the thin-plate interpolant tends to the least
+	// squares plane, which is close to the mean of the data.
+	r := RBF{Smoothing: 1e8}
+	if err := r.Fit(x, y); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var mean float64
+	for _, v := range y {
+		mean += v / float64(len(y))
+	}
+	var maxDev float64
+	for i := range y {
+		maxDev = math.Max(maxDev, math.Abs(r.Predict(mat.Row(nil, i, x))-mean))
+	}
+	if maxDev > 1 {
+		t.Errorf("smoothed interpolant too far from data mean: %v", maxDev)
+	}
+}
+
+func TestRBFSingular(t *testing.T) {
+	t.Parallel()
+	// Two points cannot determine a linear polynomial tail in 2-D.
+	x := mat.NewDense(2, 2, []float64{0, 0, 1, 1})
+	var r RBF
+	if err := r.Fit(x, []float64{0, 1}); err == nil {
+		t.Errorf("expected error for singular system")
+	}
+}
+
+func TestMonomials(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		dim, degree, want int
+	}{
+		{dim: 1, degree: 0, want: 1},
+		{dim: 2, degree: -1, want: 0},
+		{dim: 2, degree: 1, want: 3},
+		{dim: 2, degree: 2, want: 6},
+		{dim: 3, degree: 2, want: 10},
+		{dim: 3, degree: 3, want: 20},
+	} {
+		if got := len(monomials(test.dim, test.degree)); got != test.want {
+			t.Errorf("unexpected number of monomials for dim=%d degree=%d: got %d, want %d",
+				test.dim, test.degree, got, test.want)
+		}
+	}
+}
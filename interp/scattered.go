@@ -0,0 +1,48 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import "gonum.org/v1/gonum/mat"
+
+// MultiPredictor predicts the value of a multivariate function.
+type MultiPredictor interface {
+	// Predict returns the predicted value at x.
+	Predict(x []float64) float64
+}
+
+// MultiFitter fits a predictor to scattered multivariate data.
+type MultiFitter interface {
+	// Fit fits a predictor to the data points given by the rows of x with
+	// values y. It panics if the number of rows of x is not equal to
+	// len(y). Returns an error if fitting fails.
+	Fit(x mat.Matrix, y []float64) error
+}
+
+// FittableMultiPredictor is a MultiPredictor which can fit itself to data.
+type FittableMultiPredictor interface {
+	MultiFitter
+	MultiPredictor
+}
+
+// copyPoints returns the rows of x as a slice of points, panicking if the
+// number of rows differs from n or there are fewer than min points.
+func copyPoints(x mat.Matrix, n, min int) [][]float64 {
+	r, c := x.Dims()
+	if r != n {
+		panic(differentLengths)
+	}
+	if r < min {
+		panic(tooFewPoints)
+	}
+	data := make([]float64, r*c)
+	pts := make([][]float64, r)
+	for i := range pts {
+		pts[i] = data[i*c : (i+1)*c : (i+1)*c]
+		for j := range pts[i] {
+			pts[i][j] = x.At(i, j)
+		}
+	}
+	return pts
+}
@@ -0,0 +1,142 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+var (
+	_ FittablePredictor   = (*SmoothingSpline)(nil)
+	_ DerivativePredictor = (*SmoothingSpline)(nil)
+	_ Integrator          = (*SmoothingSpline)(nil)
+)
+
+// SmoothingSpline is a cubic smoothing spline, the natural cubic spline g
+// with knots at the data abscissae that minimizes
+//  \sum_i w_i (y_i - g(x_i))^2 + λ \int g''(x)^2 dx.
+// When λ is zero, the spline interpolates the data, and as λ tends to
+// infinity it approaches the weighted least squares straight line.
+//
+// The spline is computed by the Reinsch algorithm, which requires the
+// solution of a symmetric positive definite pentadiagonal system, which is
+// solved by Gaussian elimination without pivoting.
+//
+// References:
+//  P. J. Green and B. W. Silverman, "Nonparametric Regression and
+//  Generalized Linear Models", Chapman and Hall, 1994.
+type SmoothingSpline struct {
+	// Lambda is the smoothing parameter λ. Lambda must not be negative.
+	Lambda float64
+
+	// Weights holds the weights w_i of the data points. If Weights is nil,
+	// all weights are one. Otherwise len(Weights) must equal the number of
+	// data points and all weights must be positive.
+	Weights []float64
+
+	cubic PiecewiseCubic
+}
+
+// Fit fits a smoothing spline to (X, Y) value pairs provided as two slices.
+// It panics if len(xs) < 2, elements of xs are not strictly increasing,
+// len(xs) != len(ys), Lambda is negative or the weights are invalid.
+// Returns an error if solving the required system of linear equations fails.
+func (ss *SmoothingSpline) Fit(xs, ys []float64) error {
+	checkXs(xs, ys, 2)
+	if ss.Lambda < 0 {
+		panic("interp: negative smoothing parameter")
+	}
+	n := len(xs)
+	winv := make([]float64, n)
+	for i := range winv {
+		winv[i] = 1
+	}
+	if ss.Weights != nil {
+		if len(ss.Weights) != n {
+			panic(differentLengths)
+		}
+		for i, w := range ss.Weights {
+			if !(w > 0) {
+				panic("interp: non-positive weight")
+			}
+			winv[i] = 1 / w
+		}
+	}
+
+	g := append([]float64(nil), ys...)
+	gamma := make([]float64, n)
+	if n > 2 {
+		h := make([]float64, n-1)
+		for i := range h {
+			h[i] = xs[i+1] - xs[i]
+		}
+
+		// The n×(n-2) matrix Q has three non-zero elements in each column,
+		// q[i-1][i] = 1/h[i-1], q[i][i] = -1/h[i-1] - 1/h[i] and
+		// q[i+1][i] = 1/h[i], indexing the columns from 1.
+		q := func(j int) [3]float64 {
+			return [3]float64{1 / h[j-1], -1/h[j-1] - 1/h[j], 1 / h[j]}
+		}
+
+		// Form R + λ Qᵀ W⁻¹ Q, which is pentadiagonal, in the band storage
+		// of solveBand.
+		m := n - 2
+		k := min(2, m-1)
+		w := 2*k + 1
+		a := make([]float64, m*w)
+		set := func(r, c int, v float64) {
+			a[r*w+c-r+k] = v
+			a[c*w+r-c+k] = v
+		}
+		rhs := gamma[1 : m+1]
+		lambda := ss.Lambda
+		for r := 0; r < m; r++ {
+			j := r + 1
+			qj := q(j)
+			var v float64
+			for l := 0; l < 3; l++ {
+				v += qj[l] * qj[l] * winv[j-1+l]
+			}
+			set(r, r, (h[j-1]+h[j])/3+lambda*v)
+			if r+1 < m {
+				qk := q(j + 1)
+				v = qj[1]*qk[0]*winv[j] + qj[2]*qk[1]*winv[j+1]
+				set(r, r+1, h[j]/6+lambda*v)
+			}
+			if r+2 < m {
+				qk := q(j + 2)
+				set(r, r+2, lambda*qj[2]*qk[0]*winv[j+1])
+			}
+			rhs[r] = qj[0]*ys[j-1] + qj[1]*ys[j] + qj[2]*ys[j+1]
+		}
+
+		// The matrix is positive definite, so elimination without
+		// pivoting is stable. The solution γ overwrites rhs.
+		if !solveBand(a, m, k, rhs) {
+			return errSingular
+		}
+
+		// g = y - λ W⁻¹ Q γ.
+		for j := 1; j <= m; j++ {
+			qj := q(j)
+			for l := 0; l < 3; l++ {
+				g[j-1+l] -= lambda * winv[j-1+l] * qj[l] * gamma[j]
+			}
+		}
+	}
+	ss.cubic.fitWithSecondDerivatives(xs, g, gamma)
+	return nil
+}
+
+// Predict returns the value of the smoothing spline at x.
+func (ss *SmoothingSpline) Predict(x float64) float64 {
+	return ss.cubic.Predict(x)
+}
+
+// PredictDerivative returns the derivative of the smoothing spline at x.
+func (ss *SmoothingSpline) PredictDerivative(x float64) float64 {
+	return ss.cubic.PredictDerivative(x)
+}
+
+// Integrate returns the integral of the smoothing spline from a to b.
+func (ss *SmoothingSpline) Integrate(a, b float64) float64 {
+	return ss.cubic.Integrate(a, b)
+}
@@ -0,0 +1,106 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package interp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/stat"
+)
+
+func TestSmoothingSplineInterpolates(t *testing.T) {
+	t.Parallel()
+	// With λ = 0 the smoothing spline is the natural cubic spline.
+	xs := []float64{0, 0.5, 1.5, 2, 4, 4.5}
+	ys := []float64{1, -1, 2, 0.5, 3, 2}
+	for n := 2; n <= len(xs); n++ {
+		var ss SmoothingSpline
+		if err := ss.Fit(xs[:n], ys[:n]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var nc NaturalCubic
+		if err := nc.Fit(xs[:n], ys[:n]); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := 0; i <= 50; i++ {
+			x := xs[n-1] * float64(i) / 50
+			if got, want := ss.Predict(x), nc.Predict(x); math.Abs(got-want) > 1e-12 {
+				t.Errorf("n=%d: unexpected value at x=%v: got %v, want %v", n, x, got, want)
+			}
+		}
+		want := nc.Integrate(0, xs[n-1])
+		if got := ss.Integrate(0, xs[n-1]); math.Abs(got-want) > 1e-12 {
+			t.Errorf("n=%d: unexpected integral: got %v, want %v", n, got, want)
+		}
+	}
+}
+
+func TestSmoothingSplineLimit(t *testing.T) {
+	t.Parallel()
+	// As λ tends to infinity, the smoothing spline tends to the weighted
+	// least squares line.
+	rnd := rand.New(rand.NewSource(1))
+	n := 20
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	weights := make([]float64, n)
+	for i := range xs {
+		xs[i] = float64(i) + 0.5*rnd.Float64()
+		ys[i] = 2 - 0.3*xs[i] + rnd.NormFloat64()
+		weights[i] = 0.5 + rnd.Float64()
+	}
+	alpha, beta := stat.LinearRegression(xs, ys, weights, false)
+	ss := SmoothingSpline{Lambda: 1e12, Weights: weights}
+	if err := ss.Fit(xs, ys); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, x := range xs {
+		if got, want := ss.Predict(x), alpha+beta*x; math.Abs(got-want) > 1e-5 {
+			t.Errorf("unexpected value at x=%v: got %v, want %v", x, got, want)
+		}
+		if got := ss.PredictDerivative(x); x < xs[n-1] && math.Abs(got-beta) > 1e-5 {
+			t.Errorf("unexpected derivative at x=%v: got %v, want %v", x, got, beta)
+		}
+	}
+
+	// The weighted residual sum of squares increases with λ.
+	prev := -1.0
+	for _, lambda := range []float64{0, 0.1, 1, 10, 100} {
+		ss := SmoothingSpline{Lambda: lambda, Weights: weights}
+		if err := ss.Fit(xs, ys); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var rss float64
+		for i, x := range xs {
+			r := ys[i] - ss.Predict(x)
+			rss += weights[i] * r * r
+		}
+		if rss <= prev {
+			t.Errorf("residual sum of squares not increasing at λ=%v: %v <= %v", lambda, rss, prev)
+		}
+		prev = rss
+	}
+}
+
+func TestSmoothingSplinePanics(t *testing.T) {
+	t.Parallel()
+	xs := []float64{0, 1, 2}
+	ys := []float64{0, 1, 0}
+	for _, test := range []struct {
+		name string
+		ss   SmoothingSpline
+	}{
+		{name: "negative lambda", ss: SmoothingSpline{Lambda: -1}},
+		{name: "weight length", ss: SmoothingSpline{Weights: []float64{1, 1}}},
+		{name: "zero weight", ss: SmoothingSpline{Weights: []float64{1, 0, 1}}},
+	} {
+		if !panics(func() { test.ss.Fit(xs, ys) }) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
@@ -352,9 +352,9 @@ func (lu *LU) SolveTo(dst *Dense, trans bool, b Matrix) error {
 	if br != n {
 		panic(ErrShape)
 	}
-	// TODO(btracey): Should test the condition number instead of testing that
-	// the determinant is exactly zero.
-	if lu.Det() == 0 {
+	// The determinant of a large well-conditioned matrix may underflow, so
+	// test for an exactly zero pivot in log space.
+	if det, _ := lu.LogDet(); math.IsInf(det, -1) {
 		return Condition(math.Inf(1))
 	}
 
@@ -407,9 +407,9 @@ func (lu *LU) SolveVecTo(dst *VecDense, trans bool, b Vector) error {
 		if dst != b {
 			dst.checkOverlap(rv.RawVector())
 		}
-		// TODO(btracey): Should test the condition number instead of testing that
-		// the determinant is exactly zero.
-		if lu.Det() == 0 {
+		// The determinant of a large well-conditioned matrix may underflow, so
+		// test for an exactly zero pivot in log space.
+		if det, _ := lu.LogDet(); math.IsInf(det, -1) {
 			return Condition(math.Inf(1))
 		}
 
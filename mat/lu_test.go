@@ -163,6 +163,39 @@ func TestLUSolveToCond(t *testing.T) {
 	}
 }
 
+func TestLUSolveToTinyDet(t *testing.T) {
+	// The determinant of 0.01*I underflows, but the matrix is perfectly
+	// conditioned.
+	const n = 200
+	a := NewDiagDense(n, nil)
+	for i := 0; i < n; i++ {
+		a.SetDiag(i, 0.01)
+	}
+	var lu LU
+	lu.Factorize(a)
+	if lu.Det() != 0 {
+		t.Fatalf("determinant did not underflow")
+	}
+	b := NewDense(n, 1, nil)
+	for i := 0; i < n; i++ {
+		b.Set(i, 0, float64(i))
+	}
+	var x Dense
+	if err := lu.SolveTo(&x, false, b); err != nil {
+		t.Errorf("unexpected error in matrix solve: %v", err)
+	}
+	var xvec VecDense
+	if err := lu.SolveVecTo(&xvec, false, b.ColView(0)); err != nil {
+		t.Errorf("unexpected error in vector solve: %v", err)
+	}
+	for i := 0; i < n; i++ {
+		if x.At(i, 0) != 100*float64(i) || xvec.AtVec(i) != 100*float64(i) {
+			t.Errorf("unexpected solution at %d: got %v and %v, want %v", i, x.At(i, 0), xvec.AtVec(i), 100*float64(i))
+			break
+		}
+	}
+}
+
 func TestLUSolveVecTo(t *testing.T) {
 	for _, n := range []int{5, 10} {
 		a := NewDense(n, n, nil)
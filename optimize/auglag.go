@@ -0,0 +1,189 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	defaultAugLagPenalty  = 10
+	defaultAugLagIncrease = 10
+	augLagMaxPenalty      = 1e20
+)
+
+// AugmentedLagrangian is an augmented Lagrangian method for constrained
+// minimization, which solves a sequence of unconstrained subproblems
+// with an unconstrained Method.
+//
+// Each subproblem minimizes the augmented Lagrangian
+//  L_A(x) = f(x) - λ_Eᵀ c_E(x) + μ/2 ||c_E(x)||²
+//           + 1/(2μ) Σ_i (max(0, λ_I,i - μ c_I,i(x))² - λ_I,i²)
+// for fixed multiplier estimates λ and penalty parameter μ. If the
+// constraint violation at the subproblem minimum has decreased
+// sufficiently, the multiplier estimates are updated and the subproblem
+// tolerance is tightened, otherwise the penalty parameter is increased.
+//
+// References:
+//  - Conn, A., Gould, N., Toint, P.: A globally convergent augmented
+//    Lagrangian algorithm for optimization with general constraints and
+//    simple bounds. SIAM J. Numer. Anal. 28(2), 545-572 (1991)
+//  - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer
+//    (2006), Chapter 17
+type AugmentedLagrangian struct {
+	// Method is the gradient-based method used to minimize the augmented
+	// Lagrangian. If Method is nil, LBFGS is used. Method must not
+	// require the Hessian.
+	Method Method
+
+	// InitialPenalty is the initial value of the penalty parameter μ.
+	// If InitialPenalty is zero, a default of 10 is used.
+	InitialPenalty float64
+
+	// PenaltyIncrease is the factor by which μ is increased when the
+	// constraint violation does not decrease sufficiently. It must be
+	// greater than one. If PenaltyIncrease is zero, a default of 10 is
+	// used.
+	PenaltyIncrease float64
+}
+
+func (al AugmentedLagrangian) solveConstrained(s *conState) {
+	method := al.Method
+	if method == nil {
+		method = &LBFGS{}
+	}
+	mu := al.InitialPenalty
+	if mu == 0 {
+		mu = defaultAugLagPenalty
+	}
+	increase := al.PenaltyIncrease
+	if increase == 0 {
+		increase = defaultAugLagIncrease
+	}
+	if mu < 0 || increase <= 1 {
+		panic("auglag: invalid penalty parameter")
+	}
+
+	var (
+		lamE = make([]float64, s.me)
+		lamI = make([]float64, s.mi)
+		newE = make([]float64, s.me)
+		newI = make([]float64, s.mi)
+		last = make([]float64, s.n)
+		// evaluated reports whether the state holds the evaluation at
+		// last.
+		evaluated bool
+	)
+	// evalAt evaluates the objective and the constraints at x into the
+	// state unless they are already there.
+	evalAt := func(x []float64) bool {
+		if evaluated && floats.Equal(x, last) {
+			return true
+		}
+		copy(s.x, x)
+		copy(last, x)
+		f, done := s.eval(s.x, s.ce, s.ci)
+		s.f = f
+		evaluated = !done
+		return evaluated
+	}
+	// multipliers stores the first-order multiplier estimates at the
+	// current point in dstE and dstI.
+	multipliers := func(dstE, dstI []float64) {
+		for i, c := range s.ce {
+			dstE[i] = lamE[i] - mu*c
+		}
+		for i, c := range s.ci {
+			dstI[i] = math.Max(0, lamI[i]-mu*c)
+		}
+	}
+
+	p := Problem{
+		Func: func(x []float64) float64 {
+			if !evalAt(x) {
+				return math.NaN()
+			}
+			v := s.f
+			for i, c := range s.ce {
+				v += -lamE[i]*c + mu/2*c*c
+			}
+			for i, c := range s.ci {
+				t := math.Max(0, lamI[i]-mu*c)
+				v += (t*t - lamI[i]*lamI[i]) / (2 * mu)
+			}
+			return v
+		},
+		Grad: func(grad, x []float64) {
+			if !evalAt(x) || s.gradient() {
+				for i := range grad {
+					grad[i] = math.NaN()
+				}
+				return
+			}
+			multipliers(newE, newI)
+			s.lagrangianGrad(grad, newE, newI)
+		},
+		Status: func() (Status, error) {
+			if s.result.Status == FunctionEvaluationLimit {
+				return FunctionEvaluationLimit, nil
+			}
+			return NotTerminated, nil
+		},
+	}
+
+	omega := 1 / mu
+	eta := 1 / math.Pow(mu, 0.1)
+	x := make([]float64, s.n)
+	copy(x, s.x)
+	for {
+		if s.iterate() {
+			return
+		}
+		settings := &Settings{
+			GradientThreshold: math.Max(omega, s.settings.OptimalityTol),
+		}
+		res, err := Minimize(p, x, settings, method)
+		if s.result.Status == FunctionEvaluationLimit {
+			return
+		}
+		if res == nil {
+			s.fail(err)
+			return
+		}
+		// Subproblem failures, typically of the linesearch close to the
+		// minimum, are not fatal since the outer iteration continues.
+		step := make([]float64, s.n)
+		floats.SubTo(step, res.X, x)
+		copy(x, res.X)
+		if !evalAt(x) || s.gradient() {
+			return
+		}
+
+		viol := violation(s.ce, s.ci)
+		if viol <= math.Max(eta, s.settings.FeasibilityTol) {
+			multipliers(lamE, lamI)
+			if s.converged(lamE, lamI) {
+				return
+			}
+			if s.stepConverged(floats.Norm(step, math.Inf(1))) {
+				return
+			}
+			eta = math.Max(eta/math.Pow(mu, 0.9), s.settings.FeasibilityTol/10)
+			omega = math.Max(omega/mu, s.settings.OptimalityTol/10)
+		} else {
+			copy(s.lamE, lamE)
+			copy(s.lamI, lamI)
+			mu *= increase
+			if mu > augLagMaxPenalty {
+				s.fail(nil)
+				return
+			}
+			eta = 1 / math.Pow(mu, 0.1)
+			omega = 1 / mu
+		}
+	}
+}
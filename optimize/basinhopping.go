@@ -0,0 +1,344 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+var (
+	_ Method        = (*BasinHopping)(nil)
+	_ BoundedMethod = (*BasinHopping)(nil)
+)
+
+// BasinHopping implements the basin-hopping global optimization method
+// described in
+//  Wales, D. J., Doye, J. P. K.: Global optimization by basin-hopping and
+//  the lowest energy structures of Lennard-Jones clusters containing up to
+//  110 atoms. The Journal of Physical Chemistry A 101, 5111–5116 (1997)
+// Basin hopping alternates random perturbations of the current location
+// with local minimizations. The best local minimum of an iteration is
+// accepted as the new current location if its function value is not
+// larger, and otherwise with probability
+//  exp(-(f_new - f_current) / Temperature)
+//
+// One local minimization is started for every concurrent task, so the local
+// minimizations of an iteration run concurrently if Settings.Concurrent is
+// larger than one. The optimization is reproducible for a given Src and
+// Settings.Concurrent, unless it is stopped by an evaluation limit.
+// Perturbed locations outside the bounds are projected onto them.
+//
+// The MajorIterations sent by BasinHopping hold the best location found so
+// far. BasinHopping does not have its own convergence criterion, so
+// Settings.Converger or the evaluation limits should be used to stop it.
+type BasinHopping struct {
+	// NewLocal returns the method used for the local minimizations. It is
+	// called once for every concurrent task. The method must be one of the
+	// local methods of this package, such as LBFGS or NelderMead, and it
+	// must implement BoundedMethod if the problem has bounds, otherwise
+	// BasinHopping will panic. If NewLocal is nil, LBFGS is used if the
+	// gradient is available and NelderMead otherwise, and LBFGSB replaces
+	// LBFGS if the problem has bounds.
+	NewLocal func() Method
+	// StepSize is the maximum perturbation of every coordinate. If StepSize
+	// is 0, a default value of 0.5 is used. StepSize must not be negative,
+	// or BasinHopping will panic.
+	StepSize float64
+	// Temperature is the temperature of the acceptance test. If Temperature
+	// is 0, a default value of 1 is used. Temperature must not be negative,
+	// or BasinHopping will panic.
+	Temperature float64
+	// LocalIterations is the maximum number of major iterations of a local
+	// minimization. If LocalIterations is 0, a default value of 1000 is
+	// used. LocalIterations must not be negative, or BasinHopping will
+	// panic.
+	LocalIterations int
+	// Src is the source of random numbers. If Src is nil, the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	lower, upper []float64
+
+	grad       bool // Whether the gradient is available.
+	step       float64
+	temp       float64
+	iterations int
+	rnd        *rand.Rand
+
+	searches []basinSearch
+	x        []float64 // Current local minimum.
+	f        float64   // Function value at the current local minimum.
+}
+
+// basinSearch is a local minimization of BasinHopping.
+type basinSearch struct {
+	method    localMethod
+	needsGrad bool
+	conv      FunctionConverge
+	started   bool
+	iter      int
+
+	x []float64 // Best location of the search.
+	f float64   // Function value at x.
+}
+
+func (bh *BasinHopping) Uses(has Available) (uses Available, err error) {
+	bh.grad = has.Grad
+	if bh.NewLocal != nil {
+		return bh.NewLocal().Uses(has)
+	}
+	if has.Grad {
+		return has.gradient()
+	}
+	return has.function()
+}
+
+func (bh *BasinHopping) InitBounds(lower, upper []float64) {
+	bh.lower = lower
+	bh.upper = upper
+}
+
+func (bh *BasinHopping) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	bh.step = bh.StepSize
+	switch {
+	case bh.step == 0:
+		bh.step = 0.5
+	case bh.step < 0:
+		panic("basin hopping: negative step size")
+	}
+	bh.temp = bh.Temperature
+	switch {
+	case bh.temp == 0:
+		bh.temp = 1
+	case bh.temp < 0:
+		panic("basin hopping: negative temperature")
+	}
+	bh.iterations = bh.LocalIterations
+	switch {
+	case bh.iterations == 0:
+		bh.iterations = 1000
+	case bh.iterations < 0:
+		panic("basin hopping: negative local iterations")
+	}
+	bh.rnd = newRand(bh.Src)
+
+	if cap(bh.searches) < tasks {
+		bh.searches = make([]basinSearch, tasks)
+	}
+	bh.searches = bh.searches[:tasks]
+	for i := range bh.searches {
+		s := &bh.searches[i]
+		m := bh.newLocal()
+		lm, ok := m.(localMethod)
+		if !ok {
+			panic("basin hopping: local method not supported")
+		}
+		if bh.lower != nil {
+			bm, ok := m.(BoundedMethod)
+			if !ok {
+				panic("basin hopping: local method does not support bounds")
+			}
+			bm.InitBounds(bh.lower, bh.upper)
+		}
+		m.Init(dim, 1)
+		s.method = lm
+		s.needsGrad = lm.needs().Gradient
+		s.x = resize(s.x, dim)
+	}
+	bh.x = resize(bh.x, dim)
+	return tasks
+}
+
+// newLocal returns a new local method.
+func (bh *BasinHopping) newLocal() Method {
+	switch {
+	case bh.NewLocal != nil:
+		return bh.NewLocal()
+	case !bh.grad:
+		return &NelderMead{}
+	case bh.lower != nil:
+		return &LBFGSB{}
+	default:
+		return &LBFGS{}
+	}
+}
+
+func (bh *BasinHopping) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	dim := len(tasks[0].X)
+	copy(bh.x, tasks[0].X)
+	projectBounds(bh.x, bh.lower, bh.upper)
+	bh.f = math.Inf(1)
+
+	bestX := make([]float64, dim)
+	copy(bestX, bh.x)
+	bestF := math.Inf(1)
+	var bestGrad []float64
+	// record updates the best location with the evaluation in r and
+	// returns whether it has improved.
+	record := func(r Task) bool {
+		if r.Op&FuncEvaluation == 0 || !(r.F < bestF) {
+			return false
+		}
+		bestF = r.F
+		copy(bestX, r.X)
+		if r.Op&GradEvaluation != 0 {
+			bestGrad = resize(bestGrad, dim)
+			copy(bestGrad, r.Gradient)
+		} else {
+			bestGrad = nil
+		}
+		return true
+	}
+	// major sends a MajorIteration with the best location.
+	major := func() {
+		task := tasks[0]
+		task.ID = -1
+		task.Op = MajorIteration
+		task.F = bestF
+		copy(task.X, bestX)
+		if bestGrad == nil {
+			task.Gradient = nil
+		} else {
+			task.Gradient = resize(task.Gradient, dim)
+			copy(task.Gradient, bestGrad)
+		}
+		task.Hessian = nil
+		operation <- task
+	}
+
+	first := true
+Loop:
+	for {
+		// Start the local minimizations of the iteration. The first
+		// iteration is a single minimization from the initial location.
+		n := len(bh.searches)
+		if first {
+			n = 1
+		}
+		for k, s := range bh.searches[:n] {
+			task := tasks[k]
+			task.ID = k
+			task.Op = FuncEvaluation
+			if s.needsGrad {
+				task.Op |= GradEvaluation
+			}
+			for j := range task.X {
+				task.X[j] = bh.x[j]
+				if !first {
+					task.X[j] += bh.step * (2*bh.rnd.Float64() - 1)
+				}
+			}
+			projectBounds(task.X, bh.lower, bh.upper)
+			s.started = false
+			s.iter = 0
+			s.f = math.Inf(1)
+			s.conv = FunctionConverge{Absolute: 1e-10, Relative: 1e-10, Iterations: 20}
+			s.conv.Init(dim)
+			bh.searches[k] = s
+			operation <- task
+		}
+
+		// Run the local minimizations until all have finished.
+		for active := n; active > 0; {
+			r := <-result
+			switch r.Op {
+			case PostIteration:
+				break Loop
+			case MajorIteration:
+				panic("basin hopping: unexpected major iteration")
+			}
+			record(r)
+			s := &bh.searches[r.ID]
+			if r.Op&FuncEvaluation != 0 && r.F < s.f {
+				s.f = r.F
+				copy(s.x, r.X)
+			}
+			if bh.iterate(s, &r) {
+				operation <- r
+			} else {
+				active--
+			}
+		}
+
+		// Accept the best local minimum of the iteration.
+		best := 0
+		for k := range bh.searches[:n] {
+			if bh.searches[k].f < bh.searches[best].f {
+				best = k
+			}
+		}
+		s := bh.searches[best]
+		if first || s.f <= bh.f || bh.rnd.Float64() < math.Exp(-(s.f-bh.f)/bh.temp) {
+			bh.f = s.f
+			copy(bh.x, s.x)
+		}
+		first = false
+
+		major()
+		r := <-result
+		switch r.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+		}
+	}
+
+	// PostIteration was sent. Collect the outstanding evaluations and send
+	// the best location if it has improved.
+	var improved bool
+	for r := range result {
+		if r.Op != MajorIteration && record(r) {
+			improved = true
+		}
+	}
+	if improved {
+		major()
+	}
+	close(operation)
+}
+
+// iterate advances the local minimization s after the evaluation in r. It
+// stores the next evaluation in r.Op and returns whether the minimization
+// continues.
+func (bh *BasinHopping) iterate(s *basinSearch, r *Task) bool {
+	var (
+		op  Operation
+		err error
+	)
+	if !s.started {
+		if math.IsInf(r.F, 0) || math.IsNaN(r.F) {
+			return false
+		}
+		s.started = true
+		op, err = s.method.initLocal(r.Location)
+	} else {
+		op, err = s.method.iterateLocal(r.Location)
+	}
+	for err == nil && op == MajorIteration {
+		s.iter++
+		if s.iter >= bh.iterations || s.conv.Converged(r.Location) != NotTerminated {
+			return false
+		}
+		if s.needsGrad && r.Gradient != nil && projectedGradNorm(r.X, r.Gradient, bh.lower, bh.upper) < defaultGradientAbsTol {
+			return false
+		}
+		op, err = s.method.iterateLocal(r.Location)
+	}
+	if err != nil || !op.isEvaluation() {
+		return false
+	}
+	r.Op = op
+	return true
+}
@@ -0,0 +1,90 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+var (
+	_ Method        = (*BOBYQA)(nil)
+	_ BoundedMethod = (*BOBYQA)(nil)
+	_ Statuser      = (*BOBYQA)(nil)
+)
+
+// BOBYQA implements Powell's BOBYQA method for gradient-free optimization
+// subject to bounds on the variables, described in
+//  Powell, M. J. D.: The BOBYQA algorithm for bound constrained
+//  optimization without derivatives. Technical report DAMTP 2009/NA06,
+//  University of Cambridge (2009)
+// BOBYQA extends NEWUOA to bounds. The function is only evaluated within
+// the bounds, and the trust-region steps are computed by the truncated
+// conjugate gradient method with the variables that reach a bound fixed.
+// Without bounds, BOBYQA is equivalent to NEWUOA.
+//
+// The difference between the upper and the lower bound of each variable
+// must be at least twice InitRadius. Initial coordinates closer than
+// InitRadius to a bound are moved onto the bound or away from it to a
+// distance of InitRadius.
+//
+// The trust-region radius is bounded below by a lower bound ρ that is
+// decreased from InitRadius to FinalRadius. The MajorIterations sent by
+// BOBYQA hold the best location found after each evaluation, and BOBYQA
+// terminates with MethodConverge status when ρ has reached FinalRadius and
+// no further progress is made.
+type BOBYQA struct {
+	// InitRadius is the initial trust-region radius, which should be
+	// about a tenth of the largest expected change of a variable. If
+	// InitRadius is 0, a default value of 0.5 is used, or half the
+	// smallest difference between the bounds if that is smaller.
+	// InitRadius must not be negative or larger than half the difference
+	// between the bounds of a variable, or BOBYQA will panic.
+	InitRadius float64
+	// FinalRadius is the final trust-region radius, which determines the
+	// accuracy of the location of the minimum. If FinalRadius is 0, a
+	// default value of 1e-6 is used, or InitRadius if that is smaller.
+	// FinalRadius must not be negative or larger than InitRadius, or
+	// BOBYQA will panic.
+	FinalRadius float64
+	// Points is the number of interpolation points. If Points is 0, a
+	// default value of 2*dim+1 is used. Points must be between dim+2 and
+	// (dim+1)*(dim+2)/2, or BOBYQA will panic.
+	Points int
+
+	status Status
+	err    error
+
+	lower, upper []float64
+
+	model quadraticModel
+}
+
+func (b *BOBYQA) Status() (Status, error) {
+	return b.status, b.err
+}
+
+func (*BOBYQA) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (b *BOBYQA) InitBounds(lower, upper []float64) {
+	b.lower = lower
+	b.upper = upper
+}
+
+func (b *BOBYQA) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	b.status = NotTerminated
+	b.err = nil
+	b.model.init("bobyqa", dim, b.Points, b.InitRadius, b.FinalRadius, b.lower, b.upper)
+	return 1
+}
+
+func (b *BOBYQA) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	s := newSerialRun(operation, result, tasks[0])
+	b.status, b.err = b.model.run(s)
+	s.finish()
+}
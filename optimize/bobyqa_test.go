@@ -0,0 +1,80 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestBOBYQA(t *testing.T) {
+	t.Parallel()
+	// Without bounds BOBYQA is the same method as NEWUOA.
+	testDerivativeFree(t, func(int) Method { return &BOBYQA{} })
+
+Tests:
+	for _, test := range boundedTests {
+		p := test.p
+		p.Grad = nil
+		lower := make([]float64, len(test.x))
+		upper := make([]float64, len(test.x))
+		checkBounds(lower, upper, p.Lower, p.Upper)
+		for i := range lower {
+			if lower[i] == upper[i] {
+				// BOBYQA does not allow variables fixed by the bounds.
+				continue Tests
+			}
+		}
+
+		// Check that every evaluation is within the bounds.
+		f := p.Func
+		p.Func = func(x []float64) float64 {
+			for i, v := range x {
+				if v < lower[i] || upper[i] < v {
+					t.Errorf("%s: evaluation outside bounds at %v", test.name, x)
+					break
+				}
+			}
+			return f(x)
+		}
+
+		result, err := Minimize(p, test.x, &Settings{Converger: NeverTerminate{}}, &BOBYQA{})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != MethodConverge {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, MethodConverge)
+		}
+		if !floats.EqualApprox(result.X, test.want, 1e-5) {
+			t.Errorf("%s: unexpected minimum: got %v, want %v", test.name, result.X, test.want)
+		}
+		if math.Abs(result.F-test.wantF) > 1e-5 {
+			t.Errorf("%s: unexpected minimum value: got %v, want %v", test.name, result.F, test.wantF)
+		}
+	}
+}
+
+func TestBOBYQAPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Lower: []float64{0, 0},
+		Upper: []float64{1, 0.5},
+	}
+	for _, method := range []*BOBYQA{
+		{Points: 3},
+		{InitRadius: -1},
+		{InitRadius: 0.5},
+		{FinalRadius: 1},
+	} {
+		if !panics(func() { Minimize(p, []float64{1, 0}, nil, method) }) {
+			t.Errorf("%+v: expected panic", *method)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+// BoundedMethod is a Method that can minimize a function subject to the
+// bounds on the variables given by the Lower and Upper fields of Problem.
+type BoundedMethod interface {
+	Method
+	// InitBounds is called by Minimize before Init with the lower and upper
+	// bounds on the variables. The bounds have the dimension of the problem
+	// and may contain infinite values. If the problem is unbounded, lower
+	// and upper are nil. A BoundedMethod must not modify the bounds and
+	// must only request evaluations at locations within them.
+	InitBounds(lower, upper []float64)
+}
+
+// problemBounds returns the bounds of p with dimension dim, using infinite
+// values for missing bounds. It returns nil slices if p is unbounded.
+func problemBounds(p *Problem, dim int) (lower, upper []float64) {
+	if p.Lower == nil && p.Upper == nil {
+		return nil, nil
+	}
+	lower = make([]float64, dim)
+	upper = make([]float64, dim)
+	checkBounds(lower, upper, p.Lower, p.Upper)
+	return lower, upper
+}
+
+// checkBounds copies the bounds lo and up into lower and upper, using
+// infinite bounds if lo or up is nil, and panics if they are invalid.
+func checkBounds(lower, upper, lo, up []float64) {
+	n := len(lower)
+	if (lo != nil && len(lo) != n) || (up != nil && len(up) != n) {
+		panic("optimize: bounds length mismatch")
+	}
+	for i := range lower {
+		lower[i] = math.Inf(-1)
+		if lo != nil {
+			lower[i] = lo[i]
+		}
+		upper[i] = math.Inf(1)
+		if up != nil {
+			upper[i] = up[i]
+		}
+		if !(lower[i] <= upper[i]) {
+			panic("optimize: invalid bounds")
+		}
+	}
+}
+
+// projectBounds projects x onto the bounds and reports whether x was
+// changed. projectBounds does nothing if lower and upper are nil.
+func projectBounds(x, lower, upper []float64) (changed bool) {
+	if lower == nil {
+		return false
+	}
+	for i, v := range x {
+		p := math.Max(lower[i], math.Min(v, upper[i]))
+		if p != v {
+			x[i] = p
+			changed = true
+		}
+	}
+	return changed
+}
+
+// projectedGradNorm returns the infinity norm of the projected gradient at x,
+// that is the gradient with the components that point out of the bounds set
+// to zero. The bounds may be nil.
+func projectedGradNorm(x, grad, lower, upper []float64) float64 {
+	var norm float64
+	for i, g := range grad {
+		if lower != nil && ((x[i] <= lower[i] && g > 0) || (x[i] >= upper[i] && g < 0)) {
+			continue
+		}
+		norm = math.Max(norm, math.Abs(g))
+	}
+	return norm
+}
@@ -0,0 +1,338 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// shiftedSphere is the function
+//  f(x) = \sum_i (x_i - c_i)^2,
+// whose minimum subject to bounds is c projected onto the bounds.
+type shiftedSphere []float64
+
+func (c shiftedSphere) Func(x []float64) float64 {
+	var f float64
+	for i, v := range x {
+		f += (v - c[i]) * (v - c[i])
+	}
+	return f
+}
+
+func (c shiftedSphere) Grad(grad, x []float64) {
+	for i, v := range x {
+		grad[i] = 2 * (v - c[i])
+	}
+}
+
+var inf = math.Inf(1)
+
+var boundedTests = []struct {
+	name       string
+	p          Problem
+	x          []float64
+	want       []float64
+	wantF      float64
+	nelderMead bool // Whether the test is also run with NelderMead.
+}{
+	{
+		name: "RosenbrockUpper",
+		p: Problem{
+			Func:  functions.ExtendedRosenbrock{}.Func,
+			Grad:  functions.ExtendedRosenbrock{}.Grad,
+			Upper: []float64{0.5, inf},
+		},
+		x:          []float64{-1.2, 1},
+		want:       []float64{0.5, 0.25},
+		wantF:      0.25,
+		nelderMead: true,
+	},
+	{
+		name: "RosenbrockInactive",
+		p: Problem{
+			Func:  functions.ExtendedRosenbrock{}.Func,
+			Grad:  functions.ExtendedRosenbrock{}.Grad,
+			Lower: []float64{-5, -5, -5, -5},
+			Upper: []float64{5, 5, 5, 5},
+		},
+		x:    []float64{-1.2, 1, -1.2, 1},
+		want: []float64{1, 1, 1, 1},
+	},
+	{
+		name: "RosenbrockLowerStart",
+		p: Problem{
+			Func:  functions.ExtendedRosenbrock{}.Func,
+			Grad:  functions.ExtendedRosenbrock{}.Grad,
+			Lower: []float64{1.5, -inf},
+		},
+		// The initial location is projected onto the bounds.
+		x:          []float64{-1.2, 1},
+		want:       []float64{1.5, 2.25},
+		wantF:      0.25,
+		nelderMead: true,
+	},
+	{
+		name: "ShiftedSphere",
+		p: Problem{
+			Func:  shiftedSphere{-2, 0.5, 3, 1, -4}.Func,
+			Grad:  shiftedSphere{-2, 0.5, 3, 1, -4}.Grad,
+			Lower: []float64{-1, -1, -1, -inf, -4},
+			Upper: []float64{1, 1, 1, inf, 4},
+		},
+		x:          []float64{0, 0, 0, 0, 0},
+		want:       []float64{-1, 0.5, 1, 1, -4},
+		wantF:      5,
+		nelderMead: true,
+	},
+	{
+		name: "ShiftedSphereFixed",
+		p: Problem{
+			Func:  shiftedSphere{1, 2, 3}.Func,
+			Grad:  shiftedSphere{1, 2, 3}.Grad,
+			Lower: []float64{0, 5, 0},
+			Upper: []float64{10, 5, 10},
+		},
+		x:     []float64{4, 5, 4},
+		want:  []float64{1, 5, 3},
+		wantF: 9,
+	},
+}
+
+func TestBounded(t *testing.T) {
+	t.Parallel()
+	for _, test := range boundedTests {
+		for _, method := range []Method{nil, &LBFGSB{}, &ProjectedGradient{}, &NelderMead{}} {
+			nm := false
+			p := test.p
+			if _, ok := method.(*NelderMead); ok {
+				if !test.nelderMead {
+					continue
+				}
+				nm = true
+				p.Grad = nil
+			}
+			lower := make([]float64, len(test.x))
+			upper := make([]float64, len(test.x))
+			checkBounds(lower, upper, p.Lower, p.Upper)
+
+			// Check that every evaluation is within the bounds.
+			f := p.Func
+			p.Func = func(x []float64) float64 {
+				for i, v := range x {
+					if v < lower[i] || upper[i] < v {
+						t.Errorf("%s %T: evaluation outside bounds at %v", test.name, method, x)
+						break
+					}
+				}
+				return f(x)
+			}
+
+			x := make([]float64, len(test.x))
+			copy(x, test.x)
+			settings := &Settings{Converger: NeverTerminate{}}
+			if nm {
+				settings.Converger = &FunctionConverge{Absolute: 1e-12, Iterations: 100}
+			}
+			result, err := Minimize(p, x, settings, method)
+			if err != nil {
+				t.Errorf("%s %T: unexpected error: %v", test.name, method, err)
+				continue
+			}
+			if !floats.Equal(x, test.x) {
+				t.Errorf("%s %T: initial location modified", test.name, method)
+			}
+			tol := 1e-6
+			if nm {
+				tol = 1e-4
+			}
+			if !floats.EqualApprox(result.X, test.want, tol) {
+				t.Errorf("%s %T: unexpected minimum: got %v, want %v", test.name, method, result.X, test.want)
+			}
+			if math.Abs(result.F-test.wantF) > tol {
+				t.Errorf("%s %T: unexpected minimum value: got %v, want %v", test.name, method, result.F, test.wantF)
+			}
+			if !nm && result.Status != GradientThreshold {
+				t.Errorf("%s %T: unexpected status: got %v, want %v", test.name, method, result.Status, GradientThreshold)
+			}
+		}
+	}
+}
+
+func TestBoundedGradientThreshold(t *testing.T) {
+	t.Parallel()
+	// The gradient at the constrained minimum is not zero, so the
+	// GradientThreshold test must use the projected gradient.
+	p := Problem{
+		Func:  shiftedSphere{-2, 3}.Func,
+		Grad:  shiftedSphere{-2, 3}.Grad,
+		Lower: []float64{0, 0},
+		Upper: []float64{1, 1},
+	}
+	settings := &Settings{
+		GradientThreshold: 1e-6,
+		Converger:         NeverTerminate{},
+	}
+	for _, method := range []Method{&LBFGSB{GradStopThreshold: math.NaN()}, &ProjectedGradient{GradStopThreshold: math.NaN()}} {
+		result, err := Minimize(p, []float64{0.5, 0.5}, settings, method)
+		if err != nil {
+			t.Errorf("%T: unexpected error: %v", method, err)
+			continue
+		}
+		if result.Status != GradientThreshold {
+			t.Errorf("%T: unexpected status: got %v, want %v", method, result.Status, GradientThreshold)
+		}
+		if want := []float64{0, 1}; !floats.Equal(result.X, want) {
+			t.Errorf("%T: unexpected minimum: got %v, want %v", method, result.X, want)
+		}
+	}
+}
+
+func TestBoundedRosenbrock(t *testing.T) {
+	t.Parallel()
+	// With the last variable bounded below away from the unconstrained
+	// minimum, the minimization must stop cleanly at a stationary point
+	// instead of failing in the linesearch.
+	p := Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Grad:  functions.ExtendedRosenbrock{}.Grad,
+		Lower: []float64{-inf, -inf, -inf, 1.5},
+	}
+	upper := []float64{inf, inf, inf, inf}
+	for _, x := range [][]float64{
+		{-1.2, 1, -1.2, 2},
+		{2, 2, 2, 2},
+		{0, 0, 0, 1.5},
+		{1, 1, 1, 3},
+		{3, -2, 1, 4},
+	} {
+		for _, method := range []Method{&LBFGSB{}, &ProjectedGradient{}} {
+			result, err := Minimize(p, x, nil, method)
+			if err != nil {
+				t.Errorf("%T %v: unexpected error: %v", method, x, err)
+				continue
+			}
+			if result.Status.Early() {
+				t.Errorf("%T %v: unexpected status: %v", method, x, result.Status)
+			}
+			grad := make([]float64, len(x))
+			p.Grad(grad, result.X)
+			if norm := projectedGradNorm(result.X, grad, p.Lower, upper); norm > 1e-6 {
+				t.Errorf("%T %v: projected gradient too large at %v: %v", method, x, result.X, norm)
+			}
+		}
+	}
+}
+
+func TestBoundedPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Grad:  functions.ExtendedRosenbrock{}.Grad,
+		Lower: []float64{0, 0},
+	}
+	for _, test := range []struct {
+		name     string
+		p        Problem
+		x        []float64
+		settings *Settings
+		method   Method
+	}{
+		{
+			name:   "unbounded method",
+			p:      p,
+			x:      []float64{1, 1},
+			method: &BFGS{},
+		},
+		{
+			name: "length mismatch",
+			p: Problem{
+				Func:  p.Func,
+				Lower: []float64{0},
+			},
+			x: []float64{1, 1},
+		},
+		{
+			name: "invalid bounds",
+			p: Problem{
+				Func:  p.Func,
+				Lower: []float64{0, 2},
+				Upper: []float64{1, 1},
+			},
+			x: []float64{1, 1},
+		},
+		{
+			name:     "initial values outside bounds",
+			p:        p,
+			x:        []float64{-1, 1},
+			settings: &Settings{InitValues: &Location{F: 4}},
+			method:   &LBFGSB{},
+		},
+	} {
+		if !panics(func() { Minimize(test.p, test.x, test.settings, test.method) }) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
+
+func TestBoundedRecorder(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Grad:  functions.ExtendedRosenbrock{}.Grad,
+		Upper: []float64{0.5, inf},
+	}
+	var rec boundsRecorder
+	result, err := Minimize(p, []float64{-1.2, 1}, &Settings{Recorder: &rec}, &LBFGSB{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.major != result.MajorIterations {
+		t.Errorf("unexpected number of recorded major iterations: got %d, want %d", rec.major, result.MajorIterations)
+	}
+	if rec.evals != result.FuncEvaluations {
+		t.Errorf("unexpected number of recorded evaluations: got %d, want %d", rec.evals, result.FuncEvaluations)
+	}
+	if rec.maxX0 > 0.5 {
+		t.Errorf("recorded location outside bounds: x[0] = %v", rec.maxX0)
+	}
+}
+
+// boundsRecorder counts the recorded iterations and keeps the largest
+// recorded value of x[0].
+type boundsRecorder struct {
+	major, evals int
+	maxX0        float64
+}
+
+func (r *boundsRecorder) Init() error {
+	r.maxX0 = math.Inf(-1)
+	return nil
+}
+
+func (r *boundsRecorder) Record(loc *Location, op Operation, _ *Stats) error {
+	switch {
+	case op == MajorIteration:
+		r.major++
+	case op.isEvaluation():
+		r.evals++
+	default:
+		return nil
+	}
+	r.maxX0 = math.Max(r.maxX0, loc.X[0])
+	return nil
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		r := recover()
+		panicked = r != nil
+	}()
+	fn()
+	return
+}
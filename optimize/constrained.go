@@ -0,0 +1,451 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultConstrainedOptimalityTol  = 1e-8
+	defaultConstrainedFeasibilityTol = 1e-8
+	defaultConstrainedStepTol        = 1e-12
+	defaultConstrainedIterations     = 1000
+)
+
+var (
+	_ ConstrainedMethod = SQP{}
+	_ ConstrainedMethod = AugmentedLagrangian{}
+	_ ConstrainedMethod = InteriorPoint{}
+)
+
+// ConstrainedProblem is the problem of minimizing a function f subject to
+// nonlinear equality and inequality constraints,
+//  minimize f(x)
+//  s.t.     c_E(x) = 0
+//           c_I(x) ≥ 0.
+// The Lagrangian of the problem is
+//  L(x, λ_E, λ_I) = f(x) - λ_Eᵀ c_E(x) - λ_Iᵀ c_I(x),
+// with λ_I ≥ 0 at a solution.
+type ConstrainedProblem struct {
+	// Func evaluates the objective function at x. Func must not modify x.
+	Func func(x []float64) float64
+
+	// Grad evaluates the gradient of the objective function at x into
+	// grad. If Grad is nil, the gradient is approximated by forward
+	// differences of Func. Grad must not modify x.
+	Grad func(grad, x []float64)
+
+	// NumEquality is the number of equality constraints, and Equality
+	// evaluates c_E(x) into dst, which has length NumEquality. Equality
+	// must not be nil if NumEquality is positive.
+	NumEquality int
+	Equality    func(dst, x []float64)
+
+	// EqualityJac evaluates the NumEquality×n Jacobian of c_E at x into
+	// dst. If EqualityJac is nil, the Jacobian is approximated by forward
+	// differences of Equality.
+	EqualityJac func(dst *mat.Dense, x []float64)
+
+	// NumInequality is the number of inequality constraints, and
+	// Inequality evaluates c_I(x) into dst, which has length
+	// NumInequality. Inequality must not be nil if NumInequality is
+	// positive.
+	NumInequality int
+	Inequality    func(dst, x []float64)
+
+	// InequalityJac evaluates the NumInequality×n Jacobian of c_I at x
+	// into dst. If InequalityJac is nil, the Jacobian is approximated by
+	// forward differences of Inequality.
+	InequalityJac func(dst *mat.Dense, x []float64)
+}
+
+// ConstrainedSettings holds the settings for constrained minimization.
+type ConstrainedSettings struct {
+	// OptimalityTol and FeasibilityTol stop the method with Success
+	// status when the first-order optimality conditions hold at the
+	// current point, that is when the infinity norm of the gradient of
+	// the Lagrangian is at most OptimalityTol*max(1, ||∇f||), the
+	// products of the inequality multipliers with the inequality
+	// constraints are at most OptimalityTol in magnitude, and the
+	// constraint violation is at most FeasibilityTol. If they are zero,
+	// a default of 1e-8 is used.
+	OptimalityTol  float64
+	FeasibilityTol float64
+
+	// StepTol stops the method with StepConvergence status when the
+	// current point is feasible and the step is no larger than
+	// StepTol*(1 + ||x||) in the infinity norm. If StepTol is zero, a
+	// default of 1e-12 is used.
+	StepTol float64
+
+	// MajorIterations is the maximum number of iterations allowed.
+	// IterationLimit status is returned if the number of iterations
+	// equals or exceeds this value. If it equals zero, a default of 1000
+	// is used.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum allowed number of evaluations of
+	// the objective function, including those made to approximate the
+	// gradient. FunctionEvaluationLimit status is returned if the total
+	// number of evaluations equals or exceeds this number.
+	// If it equals zero, this setting has no effect.
+	FuncEvaluations int
+}
+
+// ConstrainedResult holds the result of a constrained minimization.
+type ConstrainedResult struct {
+	// X is the location of the minimum and F is the value of the
+	// objective function at X.
+	X []float64
+	F float64
+
+	// EqualityMultipliers and InequalityMultipliers are the estimates of
+	// the Lagrange multipliers λ_E and λ_I at X.
+	EqualityMultipliers   []float64
+	InequalityMultipliers []float64
+
+	// Violation is the constraint violation at X, the largest of |c_E(x)|
+	// and -c_I(x) or zero if all constraints are satisfied.
+	Violation float64
+
+	// Stats holds the statistics of the minimization. FuncEvaluations
+	// counts the evaluations of the objective function and
+	// GradEvaluations the evaluations of its gradient and the constraint
+	// Jacobians.
+	Stats
+	Status Status
+}
+
+// ConstrainedMethod is a method for constrained minimization.
+// ConstrainedMethod is implemented by SQP, AugmentedLagrangian and
+// InteriorPoint.
+type ConstrainedMethod interface {
+	// solveConstrained iterates from the initial state of s until s
+	// reports that the method must terminate.
+	solveConstrained(s *conState)
+}
+
+// MinimizeConstrained minimizes the constrained problem p, starting from
+// x0, using the given method. If method is nil, SQP is used. If settings
+// is nil, the defaults described for ConstrainedSettings are used. x0 does
+// not need to satisfy the constraints.
+//
+// MinimizeConstrained panics if p.Func is nil or if a constraint function
+// is nil while the corresponding number of constraints is positive. It
+// returns ErrFunc if f(x0) is not finite. Otherwise it returns the final
+// point of the method with the multiplier estimates there, and an error if
+// the method ended early as indicated by Status.Err.
+func MinimizeConstrained(p ConstrainedProblem, x0 []float64, method ConstrainedMethod, settings *ConstrainedSettings) (*ConstrainedResult, error) {
+	if p.Func == nil {
+		panic(badProblem)
+	}
+	if p.NumEquality < 0 || p.NumInequality < 0 {
+		panic("optimize: negative number of constraints")
+	}
+	if (p.NumEquality > 0 && p.Equality == nil) || (p.NumInequality > 0 && p.Inequality == nil) {
+		panic("optimize: missing constraint function")
+	}
+	if len(x0) == 0 {
+		return nil, ErrZeroDimensional
+	}
+	if method == nil {
+		method = SQP{}
+	}
+	s := newConState(p, x0, settings)
+	f, done := s.eval(s.x, s.ce, s.ci)
+	if done {
+		return s.finish()
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, ErrFunc(f)
+	}
+	s.f = f
+	method.solveConstrained(s)
+	return s.finish()
+}
+
+// conState holds the state of a constrained minimization and checks for
+// termination.
+type conState struct {
+	p        ConstrainedProblem
+	settings ConstrainedSettings
+	start    time.Time
+
+	n, me, mi int
+
+	// x is the current point, f and g the value and gradient of the
+	// objective at x, ce and ci the values of the constraints at x and je
+	// and ji their Jacobians.
+	x      []float64
+	f      float64
+	g      []float64
+	ce, ci []float64
+	je, ji *mat.Dense
+
+	// lamE and lamI are the current multiplier estimates.
+	lamE, lamI []float64
+
+	result ConstrainedResult
+	err    error
+}
+
+func newConState(p ConstrainedProblem, x0 []float64, settings *ConstrainedSettings) *conState {
+	n := len(x0)
+	s := &conState{
+		p:     p,
+		start: time.Now(),
+		n:     n,
+		me:    p.NumEquality,
+		mi:    p.NumInequality,
+		x:     make([]float64, n),
+		g:     make([]float64, n),
+		ce:    make([]float64, p.NumEquality),
+		ci:    make([]float64, p.NumInequality),
+		lamE:  make([]float64, p.NumEquality),
+		lamI:  make([]float64, p.NumInequality),
+	}
+	if s.me > 0 {
+		s.je = mat.NewDense(s.me, n, nil)
+	}
+	if s.mi > 0 {
+		s.ji = mat.NewDense(s.mi, n, nil)
+	}
+	if settings != nil {
+		s.settings = *settings
+	}
+	if s.settings.OptimalityTol < 0 || s.settings.FeasibilityTol < 0 || s.settings.StepTol < 0 {
+		panic("optimize: negative tolerance")
+	}
+	if s.settings.OptimalityTol == 0 {
+		s.settings.OptimalityTol = defaultConstrainedOptimalityTol
+	}
+	if s.settings.FeasibilityTol == 0 {
+		s.settings.FeasibilityTol = defaultConstrainedFeasibilityTol
+	}
+	if s.settings.StepTol == 0 {
+		s.settings.StepTol = defaultConstrainedStepTol
+	}
+	if s.settings.MajorIterations == 0 {
+		s.settings.MajorIterations = defaultConstrainedIterations
+	}
+	copy(s.x, x0)
+	return s
+}
+
+// eval evaluates the objective at x and the constraints into ce and ci.
+// It returns f as +Inf if it is NaN, and done as true if the evaluation
+// limit has been reached.
+func (s *conState) eval(x, ce, ci []float64) (f float64, done bool) {
+	if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations >= s.settings.FuncEvaluations {
+		s.result.Status = FunctionEvaluationLimit
+		return math.Inf(1), true
+	}
+	f = s.p.Func(x)
+	s.result.FuncEvaluations++
+	if s.me > 0 {
+		s.p.Equality(ce, x)
+	}
+	if s.mi > 0 {
+		s.p.Inequality(ci, x)
+	}
+	if math.IsNaN(f) {
+		f = math.Inf(1)
+	}
+	return f, false
+}
+
+// gradient evaluates the gradient of the objective and the constraint
+// Jacobians at the current point. It returns done as true if the
+// evaluations needed to approximate the gradient would exceed the
+// evaluation limit.
+func (s *conState) gradient() (done bool) {
+	if s.p.Grad != nil {
+		s.p.Grad(s.g, s.x)
+	} else {
+		if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations+s.n > s.settings.FuncEvaluations {
+			s.result.Status = FunctionEvaluationLimit
+			return true
+		}
+		fd.Gradient(s.g, s.p.Func, s.x, &fd.Settings{
+			Formula:     fd.Forward,
+			OriginKnown: true,
+			OriginValue: s.f,
+		})
+		s.result.FuncEvaluations += s.n
+	}
+	if s.me > 0 {
+		if s.p.EqualityJac != nil {
+			s.p.EqualityJac(s.je, s.x)
+		} else {
+			fd.Jacobian(s.je, s.p.Equality, s.x, &fd.JacobianSettings{
+				Formula:     fd.Forward,
+				OriginValue: s.ce,
+			})
+		}
+	}
+	if s.mi > 0 {
+		if s.p.InequalityJac != nil {
+			s.p.InequalityJac(s.ji, s.x)
+		} else {
+			fd.Jacobian(s.ji, s.p.Inequality, s.x, &fd.JacobianSettings{
+				Formula:     fd.Forward,
+				OriginValue: s.ci,
+			})
+		}
+	}
+	s.result.GradEvaluations++
+	return false
+}
+
+// lagrangianGrad stores the gradient of the Lagrangian at the current
+// point with multipliers lamE and lamI in dst.
+func (s *conState) lagrangianGrad(dst, lamE, lamI []float64) {
+	copy(dst, s.g)
+	d := mat.NewVecDense(s.n, dst)
+	var tmp mat.VecDense
+	if s.me > 0 {
+		tmp.MulVec(s.je.T(), mat.NewVecDense(s.me, lamE))
+		d.SubVec(d, &tmp)
+	}
+	if s.mi > 0 {
+		tmp.MulVec(s.ji.T(), mat.NewVecDense(s.mi, lamI))
+		d.SubVec(d, &tmp)
+	}
+}
+
+// violation returns the constraint violation for constraint values ce and
+// ci.
+func violation(ce, ci []float64) float64 {
+	var v float64
+	for _, c := range ce {
+		v = math.Max(v, math.Abs(c))
+	}
+	for _, c := range ci {
+		v = math.Max(v, -c)
+	}
+	return v
+}
+
+// violationL1 returns the ℓ₁ norm of the constraint violation for
+// constraint values ce and ci.
+func violationL1(ce, ci []float64) float64 {
+	var v float64
+	for _, c := range ce {
+		v += math.Abs(c)
+	}
+	for _, c := range ci {
+		v += math.Max(0, -c)
+	}
+	return v
+}
+
+// converged sets the multiplier estimates to lamE and lamI and returns
+// whether the first-order optimality conditions hold at the current
+// point.
+func (s *conState) converged(lamE, lamI []float64) bool {
+	copy(s.lamE, lamE)
+	copy(s.lamI, lamI)
+	if violation(s.ce, s.ci) > s.settings.FeasibilityTol {
+		return false
+	}
+	tol := s.settings.OptimalityTol
+	for i, l := range lamI {
+		if l < -tol || math.Abs(l*s.ci[i]) > tol {
+			return false
+		}
+	}
+	gl := make([]float64, s.n)
+	s.lagrangianGrad(gl, lamE, lamI)
+	if floats.Norm(gl, math.Inf(1)) > tol*math.Max(1, floats.Norm(s.g, math.Inf(1))) {
+		return false
+	}
+	s.result.Status = Success
+	return true
+}
+
+// stepConverged returns whether the current point is feasible and a step
+// with infinity norm norm is small enough for the method to terminate.
+func (s *conState) stepConverged(norm float64) bool {
+	if violation(s.ce, s.ci) > s.settings.FeasibilityTol {
+		return false
+	}
+	if norm <= s.settings.StepTol*(1+floats.Norm(s.x, math.Inf(1))) {
+		s.result.Status = StepConvergence
+		return true
+	}
+	return false
+}
+
+// iterate starts a new iteration, returning done as true if the iteration
+// limit has been reached.
+func (s *conState) iterate() (done bool) {
+	if s.result.MajorIterations >= s.settings.MajorIterations {
+		s.result.Status = IterationLimit
+		return true
+	}
+	s.result.MajorIterations++
+	return false
+}
+
+// accept moves to the point xt with objective value ft and constraint
+// values cet and cit.
+func (s *conState) accept(xt []float64, ft float64, cet, cit []float64) {
+	copy(s.x, xt)
+	s.f = ft
+	copy(s.ce, cet)
+	copy(s.ci, cit)
+}
+
+// fail terminates the method with Failure status and the error err.
+func (s *conState) fail(err error) {
+	s.result.Status = Failure
+	s.err = err
+}
+
+// finish returns the result of the method.
+func (s *conState) finish() (*ConstrainedResult, error) {
+	s.result.X = s.x
+	s.result.F = s.f
+	s.result.EqualityMultipliers = s.lamE
+	s.result.InequalityMultipliers = s.lamI
+	s.result.Violation = violation(s.ce, s.ci)
+	s.result.Runtime = time.Since(s.start)
+	if s.err != nil {
+		return &s.result, s.err
+	}
+	return &s.result, s.result.Status.Err()
+}
+
+// dampedBFGS updates the positive definite matrix b with the damped BFGS
+// update for the step sk and the change in the gradient of the Lagrangian
+// yk, which keeps b positive definite even if skᵀyk is not positive.
+func dampedBFGS(b *mat.SymDense, sk, yk []float64) {
+	n := len(sk)
+	sv := mat.NewVecDense(n, sk)
+	var bs mat.VecDense
+	bs.MulVec(b, sv)
+	sBs := mat.Dot(sv, &bs)
+	if sBs <= 0 {
+		return
+	}
+	r := mat.NewVecDense(n, nil)
+	r.CopyVec(mat.NewVecDense(n, yk))
+	sy := mat.Dot(sv, r)
+	if sy < 0.2*sBs {
+		// Replace yk by θ yk + (1-θ) B sk.
+		theta := 0.8 * sBs / (sBs - sy)
+		r.ScaleVec(theta, r)
+		r.AddScaledVec(r, 1-theta, &bs)
+		sy = mat.Dot(sv, r)
+	}
+	b.SymRankOne(b, -1/sBs, &bs)
+	b.SymRankOne(b, 1/sy, r)
+}
@@ -0,0 +1,48 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleMinimizeConstrained() {
+	// Find the point of the disk x² + y² ≤ 2 closest to (2, 2).
+	p := optimize.ConstrainedProblem{
+		Func: func(x []float64) float64 {
+			return (x[0]-2)*(x[0]-2) + (x[1]-2)*(x[1]-2)
+		},
+		Grad: func(grad, x []float64) {
+			grad[0] = 2 * (x[0] - 2)
+			grad[1] = 2 * (x[1] - 2)
+		},
+		Inequality: func(dst, x []float64) {
+			dst[0] = 2 - x[0]*x[0] - x[1]*x[1]
+		},
+		InequalityJac: func(dst *mat.Dense, x []float64) {
+			dst.Set(0, 0, -2*x[0])
+			dst.Set(0, 1, -2*x[1])
+		},
+		NumInequality: 1,
+	}
+
+	result, err := optimize.MinimizeConstrained(p, []float64{0, 0}, optimize.SQP{}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X: %.4f\n", result.X)
+	fmt.Printf("result.F: %.4f\n", result.F)
+	fmt.Printf("result.InequalityMultipliers: %.4f\n", result.InequalityMultipliers)
+	// Output:
+	// result.Status: Success
+	// result.X: [1.0000 1.0000]
+	// result.F: 2.0000
+	// result.InequalityMultipliers: [1.0000]
+}
@@ -0,0 +1,366 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var constrainedTests = []struct {
+	name  string
+	p     ConstrainedProblem
+	x     []float64
+	want  []float64
+	wantF float64
+	// wantEq and wantIneq are the multipliers at the solution, which are
+	// not checked if nil.
+	wantEq, wantIneq []float64
+}{
+	{
+		name: "EqualityQuadratic",
+		p: ConstrainedProblem{
+			Func: shiftedSphere{1, 2}.Func,
+			Grad: shiftedSphere{1, 2}.Grad,
+			Equality: func(dst, x []float64) {
+				dst[0] = x[0] + x[1] - 1
+			},
+			EqualityJac: func(dst *mat.Dense, x []float64) {
+				dst.Set(0, 0, 1)
+				dst.Set(0, 1, 1)
+			},
+			NumEquality: 1,
+		},
+		x:      []float64{3, 3},
+		want:   []float64{0, 1},
+		wantF:  2,
+		wantEq: []float64{-2},
+	},
+	{
+		name: "Circle",
+		p: ConstrainedProblem{
+			Func: func(x []float64) float64 {
+				return x[0] + x[1]
+			},
+			Grad: func(grad, x []float64) {
+				grad[0] = 1
+				grad[1] = 1
+			},
+			Equality: func(dst, x []float64) {
+				dst[0] = x[0]*x[0] + x[1]*x[1] - 2
+			},
+			EqualityJac: func(dst *mat.Dense, x []float64) {
+				dst.Set(0, 0, 2*x[0])
+				dst.Set(0, 1, 2*x[1])
+			},
+			NumEquality: 1,
+		},
+		x:      []float64{-0.5, -1.5},
+		want:   []float64{-1, -1},
+		wantF:  -2,
+		wantEq: []float64{-0.5},
+	},
+	{
+		name: "Disk",
+		p: ConstrainedProblem{
+			Func: shiftedSphere{2, 2}.Func,
+			Grad: shiftedSphere{2, 2}.Grad,
+			Inequality: func(dst, x []float64) {
+				dst[0] = 2 - x[0]*x[0] - x[1]*x[1]
+				dst[1] = x[0] + 5
+			},
+			InequalityJac: func(dst *mat.Dense, x []float64) {
+				dst.Set(0, 0, -2*x[0])
+				dst.Set(0, 1, -2*x[1])
+				dst.Set(1, 0, 1)
+				dst.Set(1, 1, 0)
+			},
+			NumInequality: 2,
+		},
+		x:        []float64{0, 0},
+		want:     []float64{1, 1},
+		wantF:    2,
+		wantIneq: []float64{1, 0},
+	},
+	{
+		// Hock and Schittkowski problem 71.
+		name: "HS071",
+		p: ConstrainedProblem{
+			Func: func(x []float64) float64 {
+				return x[0]*x[3]*(x[0]+x[1]+x[2]) + x[2]
+			},
+			Grad: func(grad, x []float64) {
+				grad[0] = x[3]*(x[0]+x[1]+x[2]) + x[0]*x[3]
+				grad[1] = x[0] * x[3]
+				grad[2] = x[0]*x[3] + 1
+				grad[3] = x[0] * (x[0] + x[1] + x[2])
+			},
+			Equality: func(dst, x []float64) {
+				dst[0] = floats.Dot(x, x) - 40
+			},
+			EqualityJac: func(dst *mat.Dense, x []float64) {
+				for j, v := range x {
+					dst.Set(0, j, 2*v)
+				}
+			},
+			NumEquality: 1,
+			Inequality: func(dst, x []float64) {
+				dst[0] = x[0]*x[1]*x[2]*x[3] - 25
+				for j, v := range x {
+					dst[1+j] = v - 1
+					dst[5+j] = 5 - v
+				}
+			},
+			InequalityJac: func(dst *mat.Dense, x []float64) {
+				dst.Zero()
+				dst.Set(0, 0, x[1]*x[2]*x[3])
+				dst.Set(0, 1, x[0]*x[2]*x[3])
+				dst.Set(0, 2, x[0]*x[1]*x[3])
+				dst.Set(0, 3, x[0]*x[1]*x[2])
+				for j := range x {
+					dst.Set(1+j, j, 1)
+					dst.Set(5+j, j, -1)
+				}
+			},
+			NumInequality: 9,
+		},
+		x:     []float64{1, 5, 5, 1},
+		want:  []float64{1, 4.742999637, 3.821149984, 1.379408292},
+		wantF: 17.014017289,
+	},
+	{
+		// Hock and Schittkowski problem 43, the Rosen-Suzuki problem.
+		name: "RosenSuzuki",
+		p: ConstrainedProblem{
+			Func: func(x []float64) float64 {
+				return x[0]*x[0] + x[1]*x[1] + 2*x[2]*x[2] + x[3]*x[3] - 5*x[0] - 5*x[1] - 21*x[2] + 7*x[3]
+			},
+			Grad: func(grad, x []float64) {
+				grad[0] = 2*x[0] - 5
+				grad[1] = 2*x[1] - 5
+				grad[2] = 4*x[2] - 21
+				grad[3] = 2*x[3] + 7
+			},
+			Inequality: func(dst, x []float64) {
+				dst[0] = 8 - x[0]*x[0] - x[1]*x[1] - x[2]*x[2] - x[3]*x[3] - x[0] + x[1] - x[2] + x[3]
+				dst[1] = 10 - x[0]*x[0] - 2*x[1]*x[1] - x[2]*x[2] - 2*x[3]*x[3] + x[0] + x[3]
+				dst[2] = 5 - 2*x[0]*x[0] - x[1]*x[1] - x[2]*x[2] - 2*x[0] + x[1] + x[3]
+			},
+			InequalityJac: func(dst *mat.Dense, x []float64) {
+				dst.SetRow(0, []float64{-2*x[0] - 1, -2*x[1] + 1, -2*x[2] - 1, -2*x[3] + 1})
+				dst.SetRow(1, []float64{-2*x[0] + 1, -4 * x[1], -2 * x[2], -4*x[3] + 1})
+				dst.SetRow(2, []float64{-4*x[0] - 2, -2*x[1] + 1, -2 * x[2], 1})
+			},
+			NumInequality: 3,
+		},
+		x:        []float64{0, 0, 0, 0},
+		want:     []float64{0, 1, 2, -1},
+		wantF:    -44,
+		wantIneq: []float64{1, 0, 2},
+	},
+}
+
+func TestMinimizeConstrained(t *testing.T) {
+	t.Parallel()
+	for _, test := range constrainedTests {
+		for _, method := range []ConstrainedMethod{nil, SQP{}, AugmentedLagrangian{}, InteriorPoint{}} {
+			x := make([]float64, len(test.x))
+			copy(x, test.x)
+			result, err := MinimizeConstrained(test.p, x, method, nil)
+			if err != nil {
+				t.Errorf("%s %T: unexpected error: %v", test.name, method, err)
+				continue
+			}
+			if !floats.Equal(x, test.x) {
+				t.Errorf("%s %T: initial location modified", test.name, method)
+			}
+			if result.Status != Success && result.Status != StepConvergence {
+				t.Errorf("%s %T: unexpected status: %v", test.name, method, result.Status)
+			}
+			const tol = 1e-6
+			if !floats.EqualApprox(result.X, test.want, tol) {
+				t.Errorf("%s %T: unexpected minimum: got %v, want %v", test.name, method, result.X, test.want)
+			}
+			if math.Abs(result.F-test.wantF) > tol {
+				t.Errorf("%s %T: unexpected minimum value: got %v, want %v", test.name, method, result.F, test.wantF)
+			}
+			if result.Violation > tol {
+				t.Errorf("%s %T: unexpected constraint violation: %v", test.name, method, result.Violation)
+			}
+			if len(result.EqualityMultipliers) != test.p.NumEquality || len(result.InequalityMultipliers) != test.p.NumInequality {
+				t.Errorf("%s %T: unexpected number of multipliers", test.name, method)
+				continue
+			}
+			if test.wantEq != nil && !floats.EqualApprox(result.EqualityMultipliers, test.wantEq, tol) {
+				t.Errorf("%s %T: unexpected equality multipliers: got %v, want %v", test.name, method, result.EqualityMultipliers, test.wantEq)
+			}
+			if test.wantIneq != nil && !floats.EqualApprox(result.InequalityMultipliers, test.wantIneq, tol) {
+				t.Errorf("%s %T: unexpected inequality multipliers: got %v, want %v", test.name, method, result.InequalityMultipliers, test.wantIneq)
+			}
+		}
+	}
+}
+
+func TestMinimizeConstrainedFiniteDifference(t *testing.T) {
+	t.Parallel()
+	for _, test := range constrainedTests {
+		p := test.p
+		p.Grad = nil
+		p.EqualityJac = nil
+		p.InequalityJac = nil
+		for _, method := range []ConstrainedMethod{SQP{}, AugmentedLagrangian{}, InteriorPoint{}} {
+			result, err := MinimizeConstrained(p, test.x, method, &ConstrainedSettings{OptimalityTol: 1e-6})
+			if err != nil {
+				t.Errorf("%s %T: unexpected error: %v", test.name, method, err)
+				continue
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-4) {
+				t.Errorf("%s %T: unexpected minimum: got %v, want %v", test.name, method, result.X, test.want)
+			}
+		}
+	}
+}
+
+func TestMinimizeConstrainedLimits(t *testing.T) {
+	t.Parallel()
+	test := constrainedTests[3]
+	for _, method := range []ConstrainedMethod{SQP{}, AugmentedLagrangian{}, InteriorPoint{}} {
+		result, err := MinimizeConstrained(test.p, test.x, method, &ConstrainedSettings{FuncEvaluations: 5})
+		if result == nil || result.Status != FunctionEvaluationLimit || err == nil {
+			t.Errorf("%T: expected FunctionEvaluationLimit, got %v", method, err)
+		} else if result.FuncEvaluations > 5 {
+			t.Errorf("%T: evaluation limit exceeded: %d", method, result.FuncEvaluations)
+		}
+
+		result, err = MinimizeConstrained(test.p, test.x, method, &ConstrainedSettings{MajorIterations: 1})
+		if result == nil || result.Status != IterationLimit || err == nil {
+			t.Errorf("%T: expected IterationLimit, got %v", method, err)
+		}
+	}
+}
+
+func TestMinimizeConstrainedErrors(t *testing.T) {
+	t.Parallel()
+	p := constrainedTests[0].p
+	if _, err := MinimizeConstrained(p, nil, nil, nil); err != ErrZeroDimensional {
+		t.Errorf("unexpected error for zero dimension: got %v, want %v", err, ErrZeroDimensional)
+	}
+	bad := p
+	bad.Func = func(x []float64) float64 { return math.Inf(1) }
+	if _, err := MinimizeConstrained(bad, []float64{0, 0}, nil, nil); err == nil {
+		t.Error("expected error for infinite initial value")
+	}
+	for _, test := range []struct {
+		name string
+		p    ConstrainedProblem
+	}{
+		{name: "nil Func", p: ConstrainedProblem{Grad: p.Grad}},
+		{name: "missing equality", p: ConstrainedProblem{Func: p.Func, NumEquality: 1}},
+		{name: "missing inequality", p: ConstrainedProblem{Func: p.Func, NumInequality: 1}},
+	} {
+		if !panics(func() { MinimizeConstrained(test.p, []float64{0, 0}, nil, nil) }) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+
+	// The linearized constraints x = 1 and x = 2 are inconsistent.
+	infeasible := ConstrainedProblem{
+		Func: p.Func,
+		Grad: p.Grad,
+		Equality: func(dst, x []float64) {
+			dst[0] = x[0] - 1
+			dst[1] = x[0] - 2
+		},
+		NumEquality: 2,
+	}
+	result, err := MinimizeConstrained(infeasible, []float64{0, 0}, SQP{}, nil)
+	if err == nil || result.Status != Failure {
+		t.Errorf("expected failure for infeasible problem, got status %v", result.Status)
+	}
+}
+
+func TestSolveQP(t *testing.T) {
+	t.Parallel()
+	// The objective is x² + y² - 4x - 2y with unconstrained minimum (2, 1).
+	g := mat.NewSymDense(2, []float64{2, 0, 0, 2})
+	a := []float64{-4, -2}
+	for _, test := range []struct {
+		name     string
+		aeq      *mat.Dense
+		beq      []float64
+		ain      *mat.Dense
+		bin      []float64
+		want     []float64
+		wantEq   []float64
+		wantIneq []float64
+		err      error
+	}{
+		{
+			name: "unconstrained",
+			want: []float64{2, 1},
+		},
+		{
+			name:   "equality",
+			aeq:    mat.NewDense(1, 2, []float64{1, 1}),
+			beq:    []float64{1},
+			want:   []float64{1, 0},
+			wantEq: []float64{-2},
+		},
+		{
+			name:     "inactive inequality",
+			ain:      mat.NewDense(1, 2, []float64{1, 0}),
+			bin:      []float64{-1},
+			want:     []float64{2, 1},
+			wantIneq: []float64{0},
+		},
+		{
+			name:     "active inequalities",
+			ain:      mat.NewDense(2, 2, []float64{-1, 0, 0, -1}),
+			bin:      []float64{-1, -0.5},
+			want:     []float64{1, 0.5},
+			wantIneq: []float64{2, 1},
+		},
+		{
+			name:     "mixed",
+			aeq:      mat.NewDense(1, 2, []float64{1, 1}),
+			beq:      []float64{1},
+			ain:      mat.NewDense(1, 2, []float64{0, 1}),
+			bin:      []float64{0.5},
+			want:     []float64{0.5, 0.5},
+			wantEq:   []float64{-3},
+			wantIneq: []float64{2},
+		},
+		{
+			name: "infeasible",
+			aeq:  mat.NewDense(1, 2, []float64{1, 1}),
+			beq:  []float64{1},
+			ain:  mat.NewDense(2, 2, []float64{1, 0, 0, 1}),
+			bin:  []float64{0.75, 0.5},
+			err:  errQPInfeasible,
+		},
+	} {
+		x := make([]float64, 2)
+		lamEq, lamIn, err := solveQP(x, g, a, test.aeq, test.beq, test.ain, test.bin)
+		if err != test.err {
+			t.Errorf("%s: unexpected error: got %v, want %v", test.name, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if !floats.EqualApprox(x, test.want, 1e-12) {
+			t.Errorf("%s: unexpected solution: got %v, want %v", test.name, x, test.want)
+		}
+		if test.wantEq != nil && !floats.EqualApprox(lamEq, test.wantEq, 1e-12) {
+			t.Errorf("%s: unexpected equality multipliers: got %v, want %v", test.name, lamEq, test.wantEq)
+		}
+		if test.wantIneq != nil && !floats.EqualApprox(lamIn, test.wantIneq, 1e-12) {
+			t.Errorf("%s: unexpected inequality multipliers: got %v, want %v", test.name, lamIn, test.wantIneq)
+		}
+	}
+}
@@ -0,0 +1,479 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// boundedFeasTol and boundedOptTol are the relative tolerances on
+	// the bounds of the basic variables and on the signs of the reduced
+	// costs, and boundedPivotTol the smallest magnitude of a pivot.
+	boundedFeasTol  = 1e-9
+	boundedOptTol   = 1e-9
+	boundedPivotTol = 1e-9
+
+	// boundedRefactor is the number of pivots after which the tableau is
+	// computed again from the basis.
+	boundedRefactor = 50
+
+	// boundedBland is the number of consecutive degenerate pivots after
+	// which the primal simplex method uses Bland's rule to avoid cycling.
+	boundedBland = 50
+)
+
+// boundedSimplex is a dense bounded-variable simplex method for the linear
+// program
+//  minimize	cᵀ x
+//  s.t. 		A*x = b
+//  			lo <= x <= up
+// where lo is finite.
+//
+// An artificial variable is added to each row. The artificial variables
+// form the initial basis of the primal simplex method and are free in its
+// first phase, and are fixed at zero otherwise, so A does not need to have
+// full row rank. The basis is kept between solves, so after the bounds are
+// changed or rows are added, the dual simplex method restores optimality
+// starting from the previous optimal basis.
+type boundedSimplex struct {
+	m, n   int // Number of rows and variables, including the artificials.
+	a      *mat.Dense
+	b, c   []float64
+	lo, up []float64
+	art    []int // Artificial variable of each row.
+
+	basis   []int  // Basic variable of each row.
+	row     []int  // Row of each basic variable, or -1.
+	atUpper []bool // Whether a non-basic variable is at its upper bound.
+	x       []float64
+	tab     *mat.Dense // B⁻¹ A for the basis matrix B.
+	d       []float64  // Reduced costs.
+	pivots  int        // Number of pivots since the last refactorization.
+}
+
+// newBoundedSimplex returns the bounded simplex method for the linear
+// program in standard form with the artificial variables as the basis.
+func newBoundedSimplex(c []float64, A mat.Matrix, b []float64) *boundedSimplex {
+	m, n := A.Dims()
+	s := &boundedSimplex{
+		m:       m,
+		n:       n + m,
+		a:       mat.NewDense(m, n+m, nil),
+		b:       make([]float64, m),
+		c:       make([]float64, n+m),
+		lo:      make([]float64, n+m),
+		up:      make([]float64, n+m),
+		art:     make([]int, m),
+		basis:   make([]int, m),
+		row:     make([]int, n+m),
+		atUpper: make([]bool, n+m),
+		x:       make([]float64, n+m),
+		d:       make([]float64, n+m),
+	}
+	s.a.Slice(0, m, 0, n).(*mat.Dense).Copy(A)
+	copy(s.b, b)
+	copy(s.c, c)
+	for j := 0; j < n; j++ {
+		s.up[j] = math.Inf(1)
+		s.row[j] = -1
+	}
+	for i := 0; i < m; i++ {
+		s.art[i] = n + i
+		s.a.Set(i, n+i, 1)
+		s.basis[i] = n + i
+		s.row[n+i] = i
+	}
+	return s
+}
+
+// addCut adds the constraint αᵀ x ≥ 1 as the row αᵀ x - t = 1 with a
+// basic slack variable t ≥ 0 and a fixed artificial variable. The basis
+// stays dual feasible.
+func (s *boundedSimplex) addCut(alpha []float64) {
+	m, n := s.m, s.n
+	a := mat.NewDense(m+1, n+2, nil)
+	a.Slice(0, m, 0, n).(*mat.Dense).Copy(s.a)
+	r := a.RawRowView(m)
+	copy(r, alpha)
+	r[n] = -1
+	r[n+1] = 1
+	s.a = a
+	s.m, s.n = m+1, n+2
+	s.b = append(s.b, 1)
+	s.c = append(s.c, 0, 0)
+	s.lo = append(s.lo, 0, 0)
+	s.up = append(s.up, math.Inf(1), 0)
+	s.art = append(s.art, n+1)
+	s.basis = append(s.basis, n)
+	s.row = append(s.row, m, -1)
+	s.atUpper = append(s.atUpper, false, false)
+	s.x = append(s.x, 0, 0)
+	s.d = append(s.d, 0, 0)
+	s.tab = nil
+}
+
+// setBasis sets the basis and the bounds at which the non-basic variables
+// are.
+func (s *boundedSimplex) setBasis(basis []int, atUpper []bool) {
+	copy(s.basis, basis)
+	copy(s.atUpper, atUpper)
+	for j := range s.row {
+		s.row[j] = -1
+	}
+	for i, j := range s.basis {
+		s.row[j] = i
+	}
+	s.tab = nil
+}
+
+// solve solves the linear program from the current basis. It uses the
+// dual simplex method if the basis is dual feasible, and the two phase
+// primal simplex method otherwise.
+func (s *boundedSimplex) solve() error {
+	maxIter := 50*(s.m+s.n) + 1000
+	if s.refactor() && s.dualFeasible() {
+		switch err := s.dual(maxIter); err {
+		case nil:
+			// Remove any loss of dual feasibility.
+			if err := s.primal(maxIter); err == nil {
+				return nil
+			}
+		case ErrInfeasible:
+			return err
+		}
+	}
+	return s.twoPhase(maxIter)
+}
+
+// twoPhase solves the linear program with the two phase primal simplex
+// method, starting from the basis of artificial variables.
+func (s *boundedSimplex) twoPhase(maxIter int) error {
+	// Start with the non-basic variables at their lower bounds and the
+	// artificial variables equal to the absolute residuals.
+	for j := range s.x {
+		s.x[j] = s.lo[j]
+		s.atUpper[j] = false
+		s.row[j] = -1
+	}
+	res := make([]float64, s.m)
+	s.residual(res)
+	for i, j := range s.art {
+		sign := 1.0
+		if res[i] < 0 {
+			sign = -1
+		}
+		s.a.Set(i, j, sign)
+		s.basis[i] = j
+		s.row[j] = i
+		s.up[j] = math.Inf(1)
+	}
+
+	// Phase 1 minimizes the sum of the artificial variables.
+	c := s.c
+	s.c = make([]float64, s.n)
+	for _, j := range s.art {
+		s.c[j] = 1
+	}
+	if !s.refactor() {
+		panic("lp: singular artificial basis")
+	}
+	err := s.primal(maxIter)
+	s.c = c
+	for _, j := range s.art {
+		s.up[j] = 0
+	}
+	if err != nil {
+		return err
+	}
+	var sum float64
+	for _, j := range s.art {
+		sum += s.x[j]
+	}
+	if sum > boundedFeasTol*(1+floats.Norm(s.b, math.Inf(1))) {
+		return ErrInfeasible
+	}
+
+	// Phase 2 minimizes the objective with the artificial variables
+	// fixed at zero.
+	if !s.refactor() {
+		return ErrSingular
+	}
+	return s.primal(maxIter)
+}
+
+// residual stores b - A x for the non-basic variables x in dst.
+func (s *boundedSimplex) residual(dst []float64) {
+	copy(dst, s.b)
+	for j, x := range s.x {
+		if s.row[j] >= 0 || x == 0 {
+			continue
+		}
+		for i := range dst {
+			dst[i] -= s.a.At(i, j) * x
+		}
+	}
+}
+
+// refactor computes the values of the variables, the tableau and the
+// reduced costs from the basis. It returns false if the basis is singular.
+func (s *boundedSimplex) refactor() bool {
+	for j := range s.x {
+		if s.row[j] >= 0 {
+			continue
+		}
+		s.x[j] = s.lo[j]
+		if s.atUpper[j] && !math.IsInf(s.up[j], 1) {
+			s.x[j] = s.up[j]
+		} else {
+			s.atUpper[j] = false
+		}
+	}
+	bm := mat.NewDense(s.m, s.m, nil)
+	col := make([]float64, s.m)
+	for i, j := range s.basis {
+		bm.SetCol(i, mat.Col(col, j, s.a))
+	}
+	var lu mat.LU
+	lu.Factorize(bm)
+	if lu.Det() == 0 {
+		return false
+	}
+	tab := mat.NewDense(s.m, s.n, nil)
+	if err := lu.SolveTo(tab, false, s.a); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return false
+		}
+	}
+	res := make([]float64, s.m)
+	s.residual(res)
+	xb := mat.NewVecDense(s.m, nil)
+	if err := lu.SolveVecTo(xb, false, mat.NewVecDense(s.m, res)); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return false
+		}
+	}
+	for i, j := range s.basis {
+		s.x[j] = xb.AtVec(i)
+	}
+	s.tab = tab
+	copy(s.d, s.c)
+	for i, j := range s.basis {
+		if cb := s.c[j]; cb != 0 {
+			floats.AddScaled(s.d, -cb, tab.RawRowView(i))
+		}
+	}
+	s.pivots = 0
+	return true
+}
+
+// dualFeasible returns whether the reduced costs of the non-basic
+// variables have the signs required at their bounds.
+func (s *boundedSimplex) dualFeasible() bool {
+	tol := s.optTol()
+	for j, d := range s.d {
+		if s.row[j] >= 0 || s.lo[j] == s.up[j] {
+			continue
+		}
+		if (!s.atUpper[j] && d < -tol) || (s.atUpper[j] && d > tol) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *boundedSimplex) optTol() float64 {
+	return boundedOptTol * math.Max(1, floats.Norm(s.c, math.Inf(1)))
+}
+
+// primal runs the primal simplex method from a primal feasible basis.
+func (s *boundedSimplex) primal(maxIter int) error {
+	tol := s.optTol()
+	var degenerate int
+	for iter := 0; ; iter++ {
+		if iter >= maxIter {
+			return ErrIterationLimit
+		}
+
+		// Select the entering variable by the largest reduced cost, or
+		// the first eligible variable under Bland's rule.
+		bland := degenerate >= boundedBland
+		q := -1
+		var best float64
+		for j, d := range s.d {
+			if s.row[j] >= 0 || s.lo[j] == s.up[j] {
+				continue
+			}
+			if s.atUpper[j] {
+				d = -d
+			}
+			if d < -tol && -d > best {
+				q = j
+				best = -d
+				if bland {
+					break
+				}
+			}
+		}
+		if q < 0 {
+			return nil
+		}
+
+		// The basic variables change by -dir t tab[:, q] when x[q]
+		// moves by dir t.
+		dir := 1.0
+		if s.atUpper[q] {
+			dir = -1
+		}
+		t := s.up[q] - s.lo[q]
+		r := -1
+		for i, k := range s.basis {
+			alpha := dir * s.tab.At(i, q)
+			var lim float64
+			switch {
+			case alpha > boundedPivotTol:
+				lim = (s.x[k] - s.lo[k]) / alpha
+			case alpha < -boundedPivotTol && !math.IsInf(s.up[k], 1):
+				lim = (s.up[k] - s.x[k]) / -alpha
+			default:
+				continue
+			}
+			lim = math.Max(lim, 0)
+			if lim < t || (lim == t && r >= 0 && math.Abs(alpha) > math.Abs(s.tab.At(r, q))) {
+				t = lim
+				r = i
+			}
+		}
+		if math.IsInf(t, 1) {
+			return ErrUnbounded
+		}
+		if t == 0 {
+			degenerate++
+		} else {
+			degenerate = 0
+		}
+
+		for i, k := range s.basis {
+			s.x[k] -= dir * t * s.tab.At(i, q)
+		}
+		if r < 0 {
+			// x[q] moves to its other bound.
+			s.atUpper[q] = !s.atUpper[q]
+			if s.atUpper[q] {
+				s.x[q] = s.up[q]
+			} else {
+				s.x[q] = s.lo[q]
+			}
+			continue
+		}
+		s.x[q] += dir * t
+		k := s.basis[r]
+		if dir*s.tab.At(r, q) > 0 {
+			s.x[k] = s.lo[k]
+			s.atUpper[k] = false
+		} else {
+			s.x[k] = s.up[k]
+			s.atUpper[k] = true
+		}
+		s.pivot(r, q)
+	}
+}
+
+// dual runs the dual simplex method from a dual feasible basis.
+func (s *boundedSimplex) dual(maxIter int) error {
+	for iter := 0; ; iter++ {
+		if iter >= maxIter {
+			return ErrIterationLimit
+		}
+
+		// Select the leaving variable with the largest bound violation.
+		r := -1
+		var worst float64
+		for i, k := range s.basis {
+			v := s.x[k]
+			var viol float64
+			switch {
+			case v < s.lo[k]:
+				viol = (s.lo[k] - v) / (1 + math.Abs(s.lo[k]))
+			case v > s.up[k]:
+				viol = (v - s.up[k]) / (1 + math.Abs(s.up[k]))
+			}
+			if viol > boundedFeasTol && viol > worst {
+				r = i
+				worst = viol
+			}
+		}
+		if r < 0 {
+			return nil
+		}
+		k := s.basis[r]
+		below := s.x[k] < s.lo[k]
+
+		// Select the entering variable that keeps the reduced costs
+		// dual feasible. x[k] changes by -tab[r, j] Δx[j].
+		q := -1
+		ratio := math.Inf(1)
+		tr := s.tab.RawRowView(r)
+		for j, alpha := range tr {
+			if s.row[j] >= 0 || s.lo[j] == s.up[j] || math.Abs(alpha) <= boundedPivotTol {
+				continue
+			}
+			increase := !s.atUpper[j]
+			if (alpha < 0) != (below == increase) {
+				continue
+			}
+			rt := math.Abs(s.d[j] / alpha)
+			if rt < ratio || (rt == ratio && math.Abs(alpha) > math.Abs(tr[q])) {
+				q = j
+				ratio = rt
+			}
+		}
+		if q < 0 {
+			return ErrInfeasible
+		}
+
+		target := s.up[k]
+		if below {
+			target = s.lo[k]
+		}
+		theta := (s.x[k] - target) / tr[q]
+		for i, j := range s.basis {
+			s.x[j] -= s.tab.At(i, q) * theta
+		}
+		s.x[q] += theta
+		s.x[k] = target
+		s.atUpper[k] = !below
+		s.pivot(r, q)
+	}
+}
+
+// pivot replaces the basic variable of row r with x[q].
+func (s *boundedSimplex) pivot(r, q int) {
+	tr := s.tab.RawRowView(r)
+	floats.Scale(1/tr[q], tr)
+	for i := 0; i < s.m; i++ {
+		if i == r {
+			continue
+		}
+		ti := s.tab.RawRowView(i)
+		if f := ti[q]; f != 0 {
+			floats.AddScaled(ti, -f, tr)
+		}
+	}
+	floats.AddScaled(s.d, -s.d[q], tr)
+	s.row[s.basis[r]] = -1
+	s.basis[r] = q
+	s.row[q] = r
+
+	// Keep the updated tableau if the basis matrix is numerically
+	// singular.
+	s.pivots++
+	if s.pivots >= boundedRefactor && !s.refactor() {
+		s.pivots = 0
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// randomLP returns a random standard form LP with no empty columns. If
+// feasible is true, b = A x for a positive x.
+func randomLP(rnd *rand.Rand, feasible bool) (c []float64, a *mat.Dense, b []float64) {
+	n := rnd.Intn(15) + 2
+	m := rnd.Intn(n-1) + 1
+	a = mat.NewDense(m, n, nil)
+	for i := 0; i < m; i++ {
+		for j := 0; j < n; j++ {
+			if rnd.Float64() < 0.5 {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+	}
+	for j := 0; j < n; j++ {
+		if floats.Norm(mat.Col(nil, j, a), 1) == 0 {
+			a.Set(rnd.Intn(m), j, rnd.NormFloat64())
+		}
+	}
+	b = make([]float64, m)
+	if feasible {
+		x := make([]float64, n)
+		for j := range x {
+			x[j] = rnd.Float64()
+		}
+		mat.NewVecDense(m, b).MulVec(a, mat.NewVecDense(n, x))
+	} else {
+		for i := range b {
+			b[i] = rnd.NormFloat64()
+		}
+	}
+	c = make([]float64, n)
+	for j := range c {
+		c[j] = rnd.NormFloat64()
+	}
+	return c, a, b
+}
+
+func TestBoundedSimplex(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		c, a, b := randomLP(rnd, i%2 == 1)
+		wantF, _, errSimplex := Simplex(c, a, b, convergenceTol, nil)
+		if errSimplex != nil && errSimplex != ErrInfeasible && errSimplex != ErrUnbounded {
+			continue
+		}
+		s := newBoundedSimplex(c, a, b)
+		err := s.solve()
+		if err != errSimplex {
+			t.Errorf("test %d: error mismatch: Simplex %v, bounded %v", i, errSimplex, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		_, n := a.Dims()
+		if f := floats.Dot(c, s.x[:n]); math.Abs(f-wantF) > 1e-8*math.Max(1, math.Abs(wantF)) {
+			t.Errorf("test %d: objective mismatch: Simplex %v, bounded %v", i, wantF, f)
+		}
+		checkBounded(t, i, s)
+	}
+}
+
+func TestBoundedSimplexRankDeficient(t *testing.T) {
+	t.Parallel()
+	c := []float64{-1, -2, 0, 0}
+	a := mat.NewDense(3, 4, []float64{
+		-1, 2, 1, 0,
+		3, 1, 0, 1,
+		2, 3, 1, 1,
+	})
+	b := []float64{4, 9, 13}
+	s := newBoundedSimplex(c, a, b)
+	if err := s.solve(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []float64{2, 3, 0, 0}; !floats.EqualApprox(s.x[:4], want, 1e-10) {
+		t.Errorf("unexpected solution: got %v, want %v", s.x[:4], want)
+	}
+	checkBounded(t, 0, s)
+}
+
+func TestBoundedSimplexWarmStart(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		c, a, b := randomLP(rnd, true)
+		s := newBoundedSimplex(c, a, b)
+		if err := s.solve(); err != nil {
+			continue
+		}
+		_, n := a.Dims()
+
+		// Change the bounds of some variables, or add a cut that the
+		// solution violates, and compare the warm started solution with
+		// a solution from the artificial basis.
+		if i%2 == 0 {
+			lo := make([]float64, s.n)
+			up := make([]float64, s.n)
+			copy(lo, s.lo)
+			copy(up, s.up)
+			for j := 0; j < n; j++ {
+				switch rnd.Intn(3) {
+				case 0:
+					up[j] = s.x[j] * (1 - 0.5*rnd.Float64())
+				case 1:
+					lo[j] = s.x[j] + 0.1*rnd.Float64()
+				}
+			}
+			s.lo, s.up = lo, up
+		} else {
+			alpha := make([]float64, s.n)
+			for j := 0; j < n; j++ {
+				if s.row[j] < 0 {
+					alpha[j] = rnd.Float64()
+				}
+			}
+			s.addCut(alpha)
+		}
+		err := s.solve()
+
+		cold := &boundedSimplex{}
+		*cold = *s
+		cold.a = mat.DenseCopyOf(s.a)
+		cold.basis = append([]int(nil), s.basis...)
+		cold.row = append([]int(nil), s.row...)
+		cold.atUpper = append([]bool(nil), s.atUpper...)
+		cold.x = append([]float64(nil), s.x...)
+		cold.d = append([]float64(nil), s.d...)
+		errCold := cold.twoPhase(50*(s.m+s.n) + 1000)
+		if err != errCold {
+			t.Errorf("test %d: error mismatch: warm %v, cold %v", i, err, errCold)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		f, fCold := floats.Dot(s.c, s.x), floats.Dot(cold.c, cold.x)
+		if math.Abs(f-fCold) > 1e-8*math.Max(1, math.Abs(fCold)) {
+			t.Errorf("test %d: objective mismatch: warm %v, cold %v", i, f, fCold)
+		}
+		checkBounded(t, i, s)
+	}
+}
+
+// checkBounded checks that the solution of s is feasible and that the
+// reduced costs are optimal.
+func checkBounded(t *testing.T, test int, s *boundedSimplex) {
+	t.Helper()
+	var ax mat.VecDense
+	ax.MulVec(s.a, mat.NewVecDense(s.n, s.x))
+	for i := 0; i < s.m; i++ {
+		if math.Abs(ax.AtVec(i)-s.b[i]) > 1e-8*(1+math.Abs(s.b[i])) {
+			t.Errorf("test %d: constraint %d violated: got %v, want %v", test, i, ax.AtVec(i), s.b[i])
+		}
+	}
+	for j, x := range s.x {
+		if x < s.lo[j]-1e-8 || x > s.up[j]+1e-8 {
+			t.Errorf("test %d: bound %d violated: %v not in [%v, %v]", test, j, x, s.lo[j], s.up[j])
+		}
+	}
+	if !s.dualFeasible() {
+		t.Errorf("test %d: reduced costs not optimal", test)
+	}
+}
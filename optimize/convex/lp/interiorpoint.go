@@ -0,0 +1,383 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrIterationLimit is returned when InteriorPoint, or the simplex method
+// used by MILP, reaches the maximum number of iterations.
+var ErrIterationLimit = errors.New("lp: iteration limit reached")
+
+const (
+	defaultInteriorPointTol        = 1e-8
+	defaultInteriorPointIterations = 200
+
+	// stepFraction is the fraction of the step to the boundary of the
+	// positive orthant that is taken.
+	stepFraction = 0.99995
+)
+
+// InteriorPointSettings holds the settings for InteriorPoint.
+type InteriorPointSettings struct {
+	// Tol is the tolerance on the relative primal and dual residuals and
+	// the relative duality gap. If Tol is zero, a default of 1e-8 is
+	// used.
+	Tol float64
+
+	// MaxIterations is the maximum number of iterations. If it is zero,
+	// a default of 200 is used.
+	MaxIterations int
+
+	// NoPresolve disables the presolve reductions.
+	NoPresolve bool
+}
+
+// Result holds the solution of a linear program in standard form.
+type Result struct {
+	// X is the optimal solution and F the optimal objective value.
+	X []float64
+	F float64
+
+	// Dual holds the optimal multipliers y of the equality constraints
+	// and ReducedCosts the reduced costs s = c - Aᵀ y of the variables,
+	// which satisfy s ≥ 0, xᵀ s = 0 and bᵀ y = cᵀ x at the solution.
+	Dual         []float64
+	ReducedCosts []float64
+
+	// Iterations is the number of interior point iterations.
+	Iterations int
+}
+
+// InteriorPoint solves a linear program in standard form
+//  minimize	cᵀ x
+//  s.t. 		A*x = b
+//  			x >= 0
+// using the Mehrotra predictor-corrector interior point method on the
+// homogeneous self-dual formulation of the problem, which detects
+// infeasible and unbounded problems without a feasible starting point.
+//
+// Before the interior point iterations, presolve removes empty rows,
+// fixes the variables of rows with a single non-zero element and removes
+// those rows, and fixes variables that appear in no row at zero.
+//
+// Only the non-zero elements of A are used, and if A implements
+// mat.ColNonZeroDoer or mat.NonZeroDoer they are found without visiting
+// the zero elements, so A may be a sparse matrix. Each iteration solves
+// the normal equations with a dense Cholesky factorization of order the
+// number of rows of A. A does not need to have full row rank.
+//
+// InteriorPoint returns ErrInfeasible if the problem has no feasible point,
+// ErrUnbounded if the objective is unbounded below and ErrIterationLimit,
+// with the final iterate, if the maximum number of iterations is reached.
+//
+// References:
+//  - Mehrotra, S.: On the implementation of a primal-dual interior point
+//    method. SIAM J. Optim. 2(4), 575-601 (1992)
+//  - Andersen, E., Andersen, K.: The MOSEK interior point optimizer for
+//    linear programming: an implementation of the homogeneous algorithm.
+//    High Performance Optimization, 197-232. Springer (2000)
+func InteriorPoint(c []float64, A mat.Matrix, b []float64, settings *InteriorPointSettings) (*Result, error) {
+	m, n := A.Dims()
+	if len(c) != n || len(b) != m {
+		panic(badShape)
+	}
+	tol := defaultInteriorPointTol
+	maxIter := defaultInteriorPointIterations
+	presolveLP := true
+	if settings != nil {
+		if settings.Tol < 0 || settings.MaxIterations < 0 {
+			panic("lp: invalid settings")
+		}
+		if settings.Tol != 0 {
+			tol = settings.Tol
+		}
+		if settings.MaxIterations != 0 {
+			maxIter = settings.MaxIterations
+		}
+		presolveLP = !settings.NoPresolve
+	}
+
+	a := newCSC(A)
+	var p *presolved
+	if presolveLP {
+		var err error
+		p, err = presolve(c, a, b, tol)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		p = &presolved{c: c, a: a, b: b, fixed: map[int]float64{}}
+		for i := 0; i < m; i++ {
+			p.rows = append(p.rows, i)
+		}
+		for j := 0; j < n; j++ {
+			p.cols = append(p.cols, j)
+		}
+	}
+
+	if p.unbounded {
+		return nil, feasibility(p, tol, maxIter)
+	}
+	xr, yr, iter, err := homogeneous(p.c, p.a, p.b, tol, maxIter)
+	if err == ErrUnbounded {
+		// The certificate of dual infeasibility only shows that the LP
+		// is unbounded if it is also feasible.
+		return nil, feasibility(p, tol, maxIter)
+	}
+	if err != nil && err != ErrIterationLimit {
+		return nil, err
+	}
+	x, y, s := p.postsolve(c, a, xr, yr)
+	return &Result{
+		X:            x,
+		F:            floats.Dot(c, x),
+		Dual:         y,
+		ReducedCosts: s,
+		Iterations:   iter,
+	}, err
+}
+
+// feasibility returns ErrUnbounded if the presolved LP p, whose dual is
+// infeasible, has a feasible point and ErrInfeasible otherwise.
+func feasibility(p *presolved, tol float64, maxIter int) error {
+	_, _, _, err := homogeneous(make([]float64, len(p.c)), p.a, p.b, tol, maxIter)
+	switch err {
+	case nil:
+		return ErrUnbounded
+	case ErrIterationLimit:
+		return err
+	}
+	return ErrInfeasible
+}
+
+// homogeneous solves the standard form LP with the homogeneous self-dual
+// predictor-corrector method, returning the primal and dual solutions.
+func homogeneous(c []float64, a *cscMatrix, b []float64, tol float64, maxIter int) (x, y []float64, iter int, err error) {
+	m, n := a.r, a.c
+	x = make([]float64, n)
+	z := make([]float64, n)
+	y = make([]float64, m)
+	for j := range x {
+		x[j] = 1
+		z[j] = 1
+	}
+	tau, kappa := 1.0, 1.0
+	if n == 0 {
+		return x, y, 0, nil
+	}
+
+	var (
+		rp     = make([]float64, m)
+		rd     = make([]float64, n)
+		d      = make([]float64, n)
+		tmpM   = make([]float64, m)
+		tmpN   = make([]float64, n)
+		rhatd  = make([]float64, n)
+		rhatp  = make([]float64, m)
+		rhatxs = make([]float64, n)
+		pv     = make([]float64, n)
+		qv     = make([]float64, m)
+		uv     = make([]float64, n)
+		vv     = make([]float64, m)
+		dx     = make([]float64, n)
+		dy     = make([]float64, m)
+		dz     = make([]float64, n)
+		gram   = mat.NewSymDense(max(m, 1), nil)
+		chol   mat.Cholesky
+	)
+
+	// Residual norms at the starting point x = z = e, y = 0, τ = κ = 1
+	// scale the stopping criteria.
+	a.mulVec(rp, x)
+	floats.SubTo(rp, b, rp)
+	rp0 := math.Max(1, floats.Norm(rp, 2))
+	floats.SubTo(rd, c, z)
+	rd0 := math.Max(1, floats.Norm(rd, 2))
+	rg0 := math.Max(1, math.Abs(floats.Sum(c)+kappa))
+	mu0 := (floats.Dot(x, z) + tau*kappa) / float64(n+1)
+
+	// solve solves the normal equations A D Aᵀ v = r2 + A D r1 and sets
+	// u = D (Aᵀ v - r1).
+	solve := func(u, v, r1, r2 []float64) {
+		for j := range tmpN {
+			tmpN[j] = d[j] * r1[j]
+		}
+		a.mulVec(tmpM, tmpN)
+		floats.Add(tmpM, r2)
+		if m > 0 {
+			vec := mat.NewVecDense(m, v)
+			if err := chol.SolveVecTo(vec, mat.NewVecDense(m, tmpM)); err != nil {
+				if _, ok := err.(mat.Condition); !ok {
+					panic(err)
+				}
+			}
+		}
+		a.mulTransVec(u, v)
+		for j := range u {
+			u[j] = d[j] * (u[j] - r1[j])
+		}
+	}
+
+	for iter = 0; ; iter++ {
+		// Residuals of the homogeneous model.
+		a.mulVec(rp, x)
+		for i := range rp {
+			rp[i] = b[i]*tau - rp[i]
+		}
+		a.mulTransVec(rd, y)
+		for j := range rd {
+			rd[j] = c[j]*tau - rd[j] - z[j]
+		}
+		cx := floats.Dot(c, x)
+		by := floats.Dot(b, y)
+		rg := cx - by + kappa
+		mu := (floats.Dot(x, z) + tau*kappa) / float64(n+1)
+
+		rhoP := floats.Norm(rp, 2) / rp0
+		rhoD := floats.Norm(rd, 2) / rd0
+		rhoG := math.Abs(rg) / rg0
+		rhoA := math.Abs(cx-by) / (tau + math.Abs(by))
+		rhoMu := mu / mu0
+		if rhoP <= tol && rhoD <= tol && rhoA <= tol {
+			break
+		}
+		if (rhoP < tol && rhoD < tol && rhoG < tol && tau < tol*math.Max(1, kappa)) ||
+			(rhoMu < tol && tau < tol*math.Min(1, kappa)) {
+			// With τ at zero, bᵀy - cᵀx = κ is positive. If cᵀx is
+			// negative the dual is infeasible and the caller decides
+			// whether the LP is unbounded, otherwise bᵀy is positive
+			// and y shows that the LP is infeasible.
+			if cx < 0 {
+				return nil, nil, iter, ErrUnbounded
+			}
+			return nil, nil, iter, ErrInfeasible
+		}
+		if iter >= maxIter {
+			err = ErrIterationLimit
+			break
+		}
+
+		// Factorize the normal equations matrix A D Aᵀ with D = X Z⁻¹,
+		// regularizing it if A is rank deficient.
+		for j := range d {
+			d[j] = x[j] / z[j]
+		}
+		if m > 0 {
+			a.scaledGram(gram, d)
+			if !factorizeRegularized(&chol, gram) {
+				return nil, nil, iter, ErrSingular
+			}
+		}
+
+		// The solution with right-hand sides c and b is common to the
+		// predictor and the corrector.
+		solve(pv, qv, c, b)
+		denom := -floats.Dot(c, pv) + floats.Dot(b, qv)
+
+		var dtau, dkappa, alpha, gamma float64
+		for corrector := 0; corrector < 2; corrector++ {
+			eta := 1 - gamma
+			floats.ScaleTo(rhatp, eta, rp)
+			rhatg := eta * rg
+			for j := range rhatxs {
+				rhatxs[j] = gamma*mu - x[j]*z[j]
+			}
+			rhattk := gamma*mu - tau*kappa
+			if corrector == 1 {
+				for j := range rhatxs {
+					rhatxs[j] -= dx[j] * dz[j]
+				}
+				rhattk -= dtau * dkappa
+			}
+			for j := range rhatd {
+				rhatd[j] = eta*rd[j] - rhatxs[j]/x[j]
+			}
+			solve(uv, vv, rhatd, rhatp)
+			dtau = (rhatg + rhattk/tau + floats.Dot(c, uv) - floats.Dot(b, vv)) / (kappa/tau + denom)
+			floats.AddScaledTo(dx, uv, dtau, pv)
+			floats.AddScaledTo(dy, vv, dtau, qv)
+			for j := range dz {
+				dz[j] = (rhatxs[j] - z[j]*dx[j]) / x[j]
+			}
+			dkappa = (rhattk - kappa*dtau) / tau
+
+			if corrector == 0 {
+				alpha = stepLength(x, dx, z, dz, tau, dtau, kappa, dkappa, 1)
+				gamma = (1 - alpha) * (1 - alpha) * math.Min(0.1, 1-alpha)
+			}
+		}
+		alpha = stepLength(x, dx, z, dz, tau, dtau, kappa, dkappa, stepFraction)
+		floats.AddScaled(x, alpha, dx)
+		floats.AddScaled(y, alpha, dy)
+		floats.AddScaled(z, alpha, dz)
+		tau += alpha * dtau
+		kappa += alpha * dkappa
+	}
+	floats.Scale(1/tau, x)
+	floats.Scale(1/tau, y)
+	return x, y, iter, err
+}
+
+// stepLength returns the largest step in (0, 1] along the direction
+// that keeps x, z, τ and κ positive, scaled by fraction.
+func stepLength(x, dx, z, dz []float64, tau, dtau, kappa, dkappa, fraction float64) float64 {
+	alpha := math.Inf(1)
+	for j, d := range dx {
+		if d < 0 {
+			alpha = math.Min(alpha, -x[j]/d)
+		}
+	}
+	for j, d := range dz {
+		if d < 0 {
+			alpha = math.Min(alpha, -z[j]/d)
+		}
+	}
+	if dtau < 0 {
+		alpha = math.Min(alpha, -tau/dtau)
+	}
+	if dkappa < 0 {
+		alpha = math.Min(alpha, -kappa/dkappa)
+	}
+	return math.Min(1, fraction*alpha)
+}
+
+// factorizeRegularized computes the Cholesky factorization of a, adding
+// an increasing multiple of the identity to the diagonal if a is not
+// numerically positive definite. It reports whether the factorization
+// succeeded.
+func factorizeRegularized(chol *mat.Cholesky, a *mat.SymDense) bool {
+	if chol.Factorize(a) {
+		return true
+	}
+	n := a.Symmetric()
+	var maxDiag float64
+	for i := 0; i < n; i++ {
+		maxDiag = math.Max(maxDiag, a.At(i, i))
+	}
+	reg := mat.NewSymDense(n, nil)
+	for delta := 1e-14 * math.Max(1, maxDiag); delta < 1e-4*math.Max(1, maxDiag); delta *= 100 {
+		reg.CopySym(a)
+		for i := 0; i < n; i++ {
+			reg.SetSym(i, i, reg.At(i, i)+delta)
+		}
+		if chol.Factorize(reg) {
+			return true
+		}
+	}
+	return false
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,317 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestInteriorPoint(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name  string
+		c     []float64
+		A     mat.Matrix
+		b     []float64
+		want  []float64
+		wantF float64
+	}{
+		{
+			name: "Basic",
+			c:    []float64{-1, -2, 0, 0},
+			A: mat.NewDense(2, 4, []float64{
+				-1, 2, 1, 0,
+				3, 1, 0, 1,
+			}),
+			b:     []float64{4, 9},
+			want:  []float64{2, 3, 0, 0},
+			wantF: -8,
+		},
+		{
+			name: "RankDeficient",
+			c:    []float64{-1, -2, 0, 0},
+			A: mat.NewDense(3, 4, []float64{
+				-1, 2, 1, 0,
+				3, 1, 0, 1,
+				2, 3, 1, 1,
+			}),
+			b:     []float64{4, 9, 13},
+			want:  []float64{2, 3, 0, 0},
+			wantF: -8,
+		},
+		{
+			name: "Presolve",
+			// The second row fixes x[2] = 1, the third row is then
+			// empty and x[4] appears in no row.
+			c: []float64{1, 2, -1, 0, 3},
+			A: mat.NewDense(3, 5, []float64{
+				1, 1, 1, 1, 0,
+				0, 0, 2, 0, 0,
+				0, 0, 3, 0, 0,
+			}),
+			b:     []float64{3, 2, 3},
+			want:  []float64{0, 0, 1, 2, 0},
+			wantF: -1,
+		},
+	} {
+		for _, noPresolve := range []bool{false, true} {
+			result, err := InteriorPoint(test.c, test.A, test.b, &InteriorPointSettings{NoPresolve: noPresolve})
+			if err != nil {
+				t.Errorf("%s (no presolve %t): unexpected error: %v", test.name, noPresolve, err)
+				continue
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-6) {
+				t.Errorf("%s (no presolve %t): unexpected solution: got %v, want %v", test.name, noPresolve, result.X, test.want)
+			}
+			if math.Abs(result.F-test.wantF) > 1e-6 {
+				t.Errorf("%s (no presolve %t): unexpected objective: got %v, want %v", test.name, noPresolve, result.F, test.wantF)
+			}
+			checkOptimality(t, test.name, test.c, test.A, test.b, result, 1e-6)
+		}
+	}
+}
+
+func TestInteriorPointErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		c    []float64
+		A    mat.Matrix
+		b    []float64
+		err  error
+	}{
+		{
+			name: "Infeasible",
+			c:    []float64{1, 1, 1},
+			A:    mat.NewDense(2, 3, []float64{1, 1, 1, 1, -1, 0}),
+			b:    []float64{-1, 0},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "InfeasibleSingleton",
+			c:    []float64{1, 1},
+			A:    mat.NewDense(2, 2, []float64{1, 1, 0, 2}),
+			b:    []float64{1, -2},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "InfeasibleEmptyRow",
+			c:    []float64{1, 1},
+			A:    mat.NewDense(2, 2, []float64{1, 1, 0, 0}),
+			b:    []float64{1, 1},
+			err:  ErrInfeasible,
+		},
+		{
+			name: "Unbounded",
+			c:    []float64{-1, 0, 0},
+			A:    mat.NewDense(2, 3, []float64{1, -1, 0, 0, 1, -1}),
+			b:    []float64{0, 1},
+			err:  ErrUnbounded,
+		},
+		{
+			name: "UnboundedEmptyColumn",
+			c:    []float64{1, -1},
+			A:    mat.NewDense(1, 2, []float64{1, 0}),
+			b:    []float64{1},
+			err:  ErrUnbounded,
+		},
+	} {
+		for _, noPresolve := range []bool{false, true} {
+			_, err := InteriorPoint(test.c, test.A, test.b, &InteriorPointSettings{NoPresolve: noPresolve})
+			if err != test.err {
+				t.Errorf("%s (no presolve %t): unexpected error: got %v, want %v", test.name, noPresolve, err, test.err)
+			}
+		}
+	}
+}
+
+func TestInteriorPointRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		n := rnd.Intn(20) + 2
+		m := rnd.Intn(n-1) + 1
+		a := mat.NewDense(m, n, nil)
+		for i := 0; i < m; i++ {
+			for j := 0; j < n; j++ {
+				if rnd.Float64() < 0.5 {
+					a.Set(i, j, rnd.NormFloat64())
+				}
+			}
+		}
+		// Simplex reports an LP with an empty column of negative cost as
+		// unbounded without checking feasibility, so fill empty columns.
+		for j := 0; j < n; j++ {
+			if floats.Norm(mat.Col(nil, j, a), 1) == 0 {
+				a.Set(rnd.Intn(m), j, rnd.NormFloat64())
+			}
+		}
+		b := make([]float64, m)
+		if i%2 == 0 {
+			for i := range b {
+				b[i] = rnd.NormFloat64()
+			}
+		} else {
+			// Make the LP feasible with b = A x for a positive x.
+			x := make([]float64, n)
+			for j := range x {
+				x[j] = rnd.Float64()
+			}
+			mat.NewVecDense(m, b).MulVec(a, mat.NewVecDense(n, x))
+		}
+		c := make([]float64, n)
+		for i := range c {
+			c[i] = rnd.NormFloat64()
+		}
+
+		wantF, _, errSimplex := Simplex(c, a, b, convergenceTol, nil)
+		if errSimplex != nil && errSimplex != ErrInfeasible && errSimplex != ErrUnbounded {
+			continue
+		}
+		result, err := InteriorPoint(c, a, b, nil)
+		if errSimplex == ErrInfeasible || errSimplex == ErrUnbounded {
+			if err != errSimplex {
+				t.Errorf("test %d: error mismatch: Simplex %v, InteriorPoint %v", i, errSimplex, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+		if !floats.EqualWithinAbsOrRel(result.F, wantF, 1e-6, 1e-6) {
+			t.Errorf("test %d: objective mismatch: Simplex %v, InteriorPoint %v", i, wantF, result.F)
+		}
+		checkOptimality(t, "random", c, a, b, result, 1e-6)
+	}
+}
+
+func TestInteriorPointSparse(t *testing.T) {
+	t.Parallel()
+	// Solve a transportation problem with many supplies and demands,
+	// whose constraint matrix has two non-zero elements per column.
+	rnd := rand.New(rand.NewSource(1))
+	const supplies, demands = 20, 30
+	n := supplies * demands
+	a := &cooMatrix{r: supplies + demands, c: n}
+	c := make([]float64, n)
+	b := make([]float64, supplies+demands)
+	var total float64
+	for i := 0; i < demands; i++ {
+		b[supplies+i] = 1 + rnd.Float64()
+		total += b[supplies+i]
+	}
+	for i := 0; i < supplies; i++ {
+		b[i] = total / supplies
+	}
+	for i := 0; i < supplies; i++ {
+		for j := 0; j < demands; j++ {
+			k := i*demands + j
+			a.set(i, k, 1)
+			a.set(supplies+j, k, 1)
+			c[k] = 1 + 10*rnd.Float64()
+		}
+	}
+
+	result, err := InteriorPoint(c, a, b, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkOptimality(t, "transportation", c, a.dense(), b, result, 1e-6)
+
+	want, err := InteriorPoint(c, a.dense(), b, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if math.Abs(result.F-want.F) > 1e-8*math.Abs(want.F) {
+		t.Errorf("sparse and dense objective mismatch: got %v, want %v", result.F, want.F)
+	}
+}
+
+func TestInteriorPointIterationLimit(t *testing.T) {
+	t.Parallel()
+	c := []float64{-1, -2, 0, 0}
+	a := mat.NewDense(2, 4, []float64{-1, 2, 1, 0, 3, 1, 0, 1})
+	b := []float64{4, 9}
+	result, err := InteriorPoint(c, a, b, &InteriorPointSettings{MaxIterations: 2})
+	if err != ErrIterationLimit {
+		t.Fatalf("unexpected error: got %v, want %v", err, ErrIterationLimit)
+	}
+	if result == nil || result.Iterations != 2 || len(result.X) != 4 {
+		t.Errorf("unexpected result at iteration limit: %+v", result)
+	}
+}
+
+// checkOptimality checks the primal feasibility, dual feasibility and
+// complementarity of result.
+func checkOptimality(t *testing.T, name string, c []float64, a mat.Matrix, b []float64, result *Result, tol float64) {
+	t.Helper()
+	m, n := a.Dims()
+	if len(result.Dual) != m || len(result.ReducedCosts) != n {
+		t.Errorf("%s: unexpected dual lengths", name)
+		return
+	}
+	var ax mat.VecDense
+	ax.MulVec(a, mat.NewVecDense(n, result.X))
+	for i, v := range b {
+		if math.Abs(ax.AtVec(i)-v) > tol*(1+math.Abs(v)) {
+			t.Errorf("%s: constraint %d violated: got %v, want %v", name, i, ax.AtVec(i), v)
+		}
+	}
+	var aty mat.VecDense
+	aty.MulVec(a.T(), mat.NewVecDense(m, result.Dual))
+	for j, x := range result.X {
+		s := result.ReducedCosts[j]
+		if math.Abs(s-(c[j]-aty.AtVec(j))) > tol {
+			t.Errorf("%s: reduced cost %d mismatch: got %v, want %v", name, j, s, c[j]-aty.AtVec(j))
+		}
+		if x < -tol || s < -tol || math.Abs(x*s) > tol*(1+math.Abs(result.F)) {
+			t.Errorf("%s: complementarity violated for variable %d: x=%v, s=%v", name, j, x, s)
+		}
+	}
+	if by := floats.Dot(b, result.Dual); math.Abs(by-result.F) > tol*(1+math.Abs(result.F)) {
+		t.Errorf("%s: duality gap: primal %v, dual %v", name, result.F, by)
+	}
+}
+
+// cooMatrix is a sparse matrix in coordinate format that can only be
+// accessed through DoNonZero.
+type cooMatrix struct {
+	r, c int
+	i, j []int
+	v    []float64
+}
+
+func (m *cooMatrix) set(i, j int, v float64) {
+	m.i = append(m.i, i)
+	m.j = append(m.j, j)
+	m.v = append(m.v, v)
+}
+
+func (m *cooMatrix) Dims() (r, c int) { return m.r, m.c }
+
+func (m *cooMatrix) At(i, j int) float64 { panic("cooMatrix: At called") }
+
+func (m *cooMatrix) T() mat.Matrix { return mat.Transpose{Matrix: m} }
+
+func (m *cooMatrix) DoNonZero(fn func(i, j int, v float64)) {
+	for k, v := range m.v {
+		fn(m.i[k], m.j[k], v)
+	}
+}
+
+func (m *cooMatrix) dense() *mat.Dense {
+	d := mat.NewDense(m.r, m.c, nil)
+	m.DoNonZero(func(i, j int, v float64) {
+		d.Set(i, j, d.At(i, j)+v)
+	})
+	return d
+}
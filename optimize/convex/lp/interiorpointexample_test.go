@@ -0,0 +1,31 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+func ExampleInteriorPoint() {
+	c := []float64{-1, -2, 0, 0}
+	A := mat.NewDense(2, 4, []float64{-1, 2, 1, 0, 3, 1, 0, 1})
+	b := []float64{4, 9}
+
+	result, err := lp.InteriorPoint(c, A, b, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("opt: %.4f\n", result.F)
+	fmt.Printf("x: %.4f\n", result.X)
+	fmt.Printf("dual: %.4f\n", result.Dual)
+	// Output:
+	// opt: -8.0000
+	// x: [2.0000 3.0000 0.0000 0.0000]
+	// dual: [-0.7143 -0.5714]
+}
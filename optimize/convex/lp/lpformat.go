@@ -0,0 +1,637 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type lpTokenKind int
+
+const (
+	lpNumber lpTokenKind = iota
+	lpName
+	lpOp    // "<=", ">=" or "=".
+	lpColon // ":".
+	lpSign  // "+" or "-".
+)
+
+type lpToken struct {
+	kind lpTokenKind
+	text string
+	val  float64
+	line int
+}
+
+// lpKeywords holds the section keywords of the LP format, with longer
+// keywords before their prefixes.
+var lpKeywords = []struct {
+	word, section string
+}{
+	{"minimize", "min"}, {"minimise", "min"}, {"minimum", "min"}, {"min", "min"},
+	{"maximize", "max"}, {"maximise", "max"}, {"maximum", "max"}, {"max", "max"},
+	{"subject to", "st"}, {"such that", "st"}, {"s.t.", "st"}, {"st.", "st"}, {"st", "st"},
+	{"bounds", "bounds"}, {"bound", "bounds"},
+	{"generals", "general"}, {"general", "general"}, {"gen", "general"},
+	{"binaries", "binary"}, {"binary", "binary"}, {"bin", "binary"},
+	{"semi-continuous", "semi"}, {"semis", "semi"}, {"semi", "semi"},
+	{"sos", "sos"},
+	{"end", "end"},
+}
+
+// lpSection returns the section started by a line beginning with a
+// keyword, and the rest of the line.
+func lpSection(line string) (section, rest string, ok bool) {
+	f := strings.Fields(line)
+	for _, k := range lpKeywords {
+		n := strings.Count(k.word, " ") + 1
+		if len(f) >= n && strings.ToLower(strings.Join(f[:n], " ")) == k.word {
+			return k.section, strings.Join(f[n:], " "), true
+		}
+	}
+	return "", "", false
+}
+
+// isLPNameChar returns whether c may appear in an LP format name.
+func isLPNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		strings.IndexByte("!\"#$%&()/,.;?@_`'{}|~", c) >= 0
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// lpTokens appends the tokens of a line to toks.
+func lpTokens(toks []lpToken, s string, line int) ([]lpToken, error) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case c == '<' || c == '>' || c == '=':
+			op := "="
+			switch {
+			case c == '<' || (c == '=' && i+1 < len(s) && s[i+1] == '<'):
+				op = "<="
+			case c == '>' || (c == '=' && i+1 < len(s) && s[i+1] == '>'):
+				op = ">="
+			}
+			i++
+			if i < len(s) && (s[i] == '=' || (c == '=' && (s[i] == '<' || s[i] == '>'))) {
+				i++
+			}
+			toks = append(toks, lpToken{kind: lpOp, text: op, line: line})
+		case c == ':':
+			toks = append(toks, lpToken{kind: lpColon, text: ":", line: line})
+			i++
+		case c == '+' || c == '-':
+			toks = append(toks, lpToken{kind: lpSign, text: s[i : i+1], line: line})
+			i++
+		case isDigit(c) || c == '.':
+			j := i
+			for j < len(s) && (isDigit(s[j]) || s[j] == '.') {
+				j++
+			}
+			if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+				k := j + 1
+				if k < len(s) && (s[k] == '+' || s[k] == '-') {
+					k++
+				}
+				if k < len(s) && isDigit(s[k]) {
+					for j = k; j < len(s) && isDigit(s[j]); j++ {
+					}
+				}
+			}
+			v, err := strconv.ParseFloat(s[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("lp: LP line %d: invalid number %q", line, s[i:j])
+			}
+			toks = append(toks, lpToken{kind: lpNumber, text: s[i:j], val: v, line: line})
+			i = j
+		case isLPNameChar(c):
+			j := i
+			for j < len(s) && isLPNameChar(s[j]) {
+				j++
+			}
+			toks = append(toks, lpToken{kind: lpName, text: s[i:j], line: line})
+			i = j
+		case c == '[' || c == '^':
+			return nil, fmt.Errorf("lp: LP line %d: quadratic terms not supported", line)
+		default:
+			return nil, fmt.Errorf("lp: LP line %d: invalid character %q", line, c)
+		}
+	}
+	return toks, nil
+}
+
+// lpParser parses the tokens of a section of an LP file.
+type lpParser struct {
+	mb   *modelBuilder
+	toks []lpToken
+	pos  int
+	line int
+}
+
+func (p *lpParser) errorf(format string, args ...interface{}) error {
+	line := p.line
+	if p.pos < len(p.toks) {
+		line = p.toks[p.pos].line
+	}
+	return fmt.Errorf("lp: LP line %d: %s", line, fmt.Sprintf(format, args...))
+}
+
+// peek returns the token k places after the current token, or nil.
+func (p *lpParser) peek(k int) *lpToken {
+	if p.pos+k < len(p.toks) {
+		return &p.toks[p.pos+k]
+	}
+	return nil
+}
+
+func (p *lpParser) is(k int, kind lpTokenKind) bool {
+	t := p.peek(k)
+	return t != nil && t.kind == kind
+}
+
+// isInf returns whether a name denotes infinity.
+func isInf(name string) bool {
+	name = strings.ToLower(name)
+	return name == "inf" || name == "infinity"
+}
+
+// value parses a signed number or infinity. If beforeOp is true, the value
+// must be followed by an operator. If no value is parsed, the position is
+// unchanged.
+func (p *lpParser) value(beforeOp bool) (float64, bool) {
+	start := p.pos
+	sign := 1.0
+	for p.is(0, lpSign) {
+		if p.peek(0).text == "-" {
+			sign = -sign
+		}
+		p.pos++
+	}
+	var v float64
+	switch t := p.peek(0); {
+	case t != nil && t.kind == lpNumber:
+		v = t.val
+	case t != nil && t.kind == lpName && isInf(t.text):
+		v = math.Inf(1)
+	default:
+		p.pos = start
+		return 0, false
+	}
+	p.pos++
+	if beforeOp && !p.is(0, lpOp) {
+		p.pos = start
+		return 0, false
+	}
+	return sign * v, true
+}
+
+// expr parses a linear expression, returning its terms and its constant.
+func (p *lpParser) expr() (terms []entry, constant float64, err error) {
+	for first := true; ; first = false {
+		sign := 1.0
+		signed := false
+		for p.is(0, lpSign) {
+			if p.peek(0).text == "-" {
+				sign = -sign
+			}
+			signed = true
+			p.pos++
+		}
+		if !first && !signed {
+			return terms, constant, nil
+		}
+		coef, hasCoef := 1.0, false
+		if t := p.peek(0); t != nil && t.kind == lpNumber {
+			coef, hasCoef = t.val, true
+			p.pos++
+		}
+		switch t := p.peek(0); {
+		case t != nil && t.kind == lpName:
+			terms = append(terms, entry{col: p.mb.variable(t.text), val: sign * coef})
+			p.pos++
+		case hasCoef:
+			constant += sign * coef
+		default:
+			return nil, 0, p.errorf("expected term")
+		}
+	}
+}
+
+// setBound sets the bounds lo and up from the relation x op v.
+func setBound(lo, up *float64, op string, v float64) {
+	switch op {
+	case "<=":
+		*up = v
+	case ">=":
+		*lo = v
+	default:
+		*lo, *up = v, v
+	}
+}
+
+// reverse returns the operator op with its operands swapped.
+func reverse(op string) string {
+	switch op {
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	}
+	return op
+}
+
+func (p *lpParser) objective() error {
+	if p.is(0, lpName) && p.is(1, lpColon) {
+		p.mb.m.Objective = p.peek(0).text
+		p.pos += 2
+	}
+	if p.pos == len(p.toks) {
+		return nil
+	}
+	terms, constant, err := p.expr()
+	if err != nil {
+		return err
+	}
+	for _, t := range terms {
+		p.mb.m.C[t.col] += t.val
+	}
+	p.mb.m.Offset = constant
+	return nil
+}
+
+func (p *lpParser) constraint() error {
+	name := "c" + strconv.Itoa(len(p.mb.m.Rows)+1)
+	if p.is(0, lpName) && p.is(1, lpColon) {
+		name = p.peek(0).text
+		p.pos += 2
+	}
+	if _, ok := p.mb.rowIdx[name]; ok {
+		return p.errorf("duplicate constraint %q", name)
+	}
+	lo, up := math.Inf(-1), math.Inf(1)
+	ranged := false
+	if v, ok := p.value(true); ok {
+		setBound(&lo, &up, reverse(p.peek(0).text), v)
+		p.pos++
+		ranged = true
+	}
+	terms, constant, err := p.expr()
+	if err != nil {
+		return err
+	}
+	if p.is(0, lpOp) {
+		op := p.peek(0).text
+		p.pos++
+		v, ok := p.value(false)
+		if !ok {
+			return p.errorf("expected right-hand side")
+		}
+		setBound(&lo, &up, op, v)
+	} else if !ranged {
+		return p.errorf("expected operator")
+	}
+	i := p.mb.row(name, lo-constant, up-constant)
+	for _, t := range terms {
+		t.row = i
+		p.mb.entries = append(p.mb.entries, t)
+	}
+	return nil
+}
+
+func (p *lpParser) bound() error {
+	m := &p.mb.m
+	if p.is(0, lpName) && p.is(1, lpName) && strings.ToLower(p.peek(1).text) == "free" {
+		j := p.mb.variable(p.peek(0).text)
+		m.Lower[j], m.Upper[j] = math.Inf(-1), math.Inf(1)
+		p.pos += 2
+		return nil
+	}
+	if v, ok := p.value(true); ok {
+		op := p.peek(0).text
+		p.pos++
+		if !p.is(0, lpName) {
+			return p.errorf("expected variable")
+		}
+		j := p.mb.variable(p.peek(0).text)
+		p.pos++
+		setBound(&m.Lower[j], &m.Upper[j], reverse(op), v)
+		if !p.is(0, lpOp) {
+			return nil
+		}
+		op = p.peek(0).text
+		p.pos++
+		v, ok = p.value(false)
+		if !ok {
+			return p.errorf("expected bound")
+		}
+		setBound(&m.Lower[j], &m.Upper[j], op, v)
+		return nil
+	}
+	if !p.is(0, lpName) || !p.is(1, lpOp) {
+		return p.errorf("invalid bound")
+	}
+	j := p.mb.variable(p.peek(0).text)
+	op := p.peek(1).text
+	p.pos += 2
+	v, ok := p.value(false)
+	if !ok {
+		return p.errorf("expected bound")
+	}
+	setBound(&m.Lower[j], &m.Upper[j], op, v)
+	return nil
+}
+
+// parse parses the tokens of a section.
+func (p *lpParser) parse(section string, toks []lpToken) error {
+	p.toks, p.pos = toks, 0
+	if section == "min" || section == "max" {
+		if err := p.objective(); err != nil {
+			return err
+		}
+	}
+	for p.pos < len(p.toks) {
+		var err error
+		switch section {
+		case "st":
+			err = p.constraint()
+		case "bounds":
+			err = p.bound()
+		case "general", "binary":
+			t := p.peek(0)
+			if t.kind != lpName {
+				return p.errorf("expected variable")
+			}
+			j := p.mb.variable(t.text)
+			p.mb.m.Integer[j] = true
+			if section == "binary" {
+				p.mb.m.Lower[j], p.mb.m.Upper[j] = 0, 1
+			}
+			p.pos++
+		default:
+			err = p.errorf("unexpected %q", p.peek(0).text)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadLP reads a linear program in the CPLEX LP format from r.
+//
+// The objective, Subject To, Bounds, Generals, Binaries and End sections
+// are supported, and the objective may have a constant term. Constraints
+// may be ranged, as in
+//  c1: -3 <= x + y <= 8
+// and constraints without names are named c1, c2, and so on. Variables
+// default to the bounds [0, ∞). Semi-continuous variables, SOS constraints
+// and quadratic terms are not supported.
+func ReadLP(r io.Reader) (*Model, error) {
+	p := &lpParser{mb: newModelBuilder()}
+	var (
+		section string
+		toks    []lpToken
+		ended   bool
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() && !ended {
+		p.line++
+		text := sc.Text()
+		if i := strings.IndexByte(text, '\\'); i >= 0 {
+			text = text[:i]
+		}
+		if s, rest, ok := lpSection(text); ok {
+			switch {
+			case s == "semi" || s == "sos":
+				return nil, fmt.Errorf("lp: LP line %d: unsupported section %q", p.line, strings.Fields(text)[0])
+			case section == "" && s != "min" && s != "max":
+				return nil, fmt.Errorf("lp: LP line %d: missing objective", p.line)
+			}
+			if section != "" {
+				if err := p.parse(section, toks); err != nil {
+					return nil, err
+				}
+			}
+			if s == "max" {
+				p.mb.m.Maximize = true
+			}
+			section, toks, text = s, nil, rest
+			ended = s == "end"
+		}
+		if section == "" && strings.TrimSpace(text) != "" {
+			return nil, fmt.Errorf("lp: LP line %d: missing objective", p.line)
+		}
+		var err error
+		toks, err = lpTokens(toks, text, p.line)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, fmt.Errorf("lp: LP line %d: missing End", p.line)
+	}
+	return p.mb.model(), nil
+}
+
+// lpReserved holds the names that cannot be written in the LP format.
+var lpReserved = map[string]bool{"inf": true, "infinity": true, "free": true}
+
+func init() {
+	for _, k := range lpKeywords {
+		if !strings.Contains(k.word, " ") {
+			lpReserved[k.word] = true
+		}
+	}
+}
+
+func checkLPName(name string) error {
+	if name == "" || len(name) > 255 || isDigit(name[0]) || name[0] == '.' || lpReserved[strings.ToLower(name)] {
+		return fmt.Errorf("lp: invalid LP name %q", name)
+	}
+	for i := 0; i < len(name); i++ {
+		if !isLPNameChar(name[i]) {
+			return fmt.Errorf("lp: invalid LP name %q", name)
+		}
+	}
+	return nil
+}
+
+// formatLPNumber formats v for the LP format.
+func formatLPNumber(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "inf"
+	case math.IsInf(v, -1):
+		return "-inf"
+	}
+	return formatFloat(v)
+}
+
+// lpTermsPerLine is the number of terms of an expression written on each
+// line of an LP file.
+const lpTermsPerLine = 8
+
+// writeLPExpr writes the linear expression with the given coefficients and
+// constant, or zero if the expression is empty. Terms with zero coefficients
+// are written only for the variables with keep set.
+func writeLPExpr(w *bufio.Writer, vars []string, coef []float64, keep []bool, constant float64, zero string) {
+	var n int
+	term := func(v float64, name string) {
+		if n > 0 && n%lpTermsPerLine == 0 {
+			w.WriteString("\n  ")
+		}
+		switch {
+		case v < 0:
+			w.WriteString(" - ")
+		case n > 0:
+			w.WriteString(" + ")
+		default:
+			w.WriteByte(' ')
+		}
+		v = math.Abs(v)
+		switch {
+		case name == "":
+			w.WriteString(formatLPNumber(v))
+		case v != 1:
+			w.WriteString(formatLPNumber(v))
+			w.WriteByte(' ')
+			fallthrough
+		default:
+			w.WriteString(name)
+		}
+		n++
+	}
+	for j, v := range coef {
+		if v != 0 || (keep != nil && keep[j]) {
+			term(v, vars[j])
+		}
+	}
+	if constant != 0 {
+		term(constant, "")
+	}
+	if n == 0 {
+		w.WriteByte(' ')
+		w.WriteString(zero)
+	}
+}
+
+// WriteLP writes the model m to w in the CPLEX LP format. Names that are
+// missing are generated. Names may only contain letters, digits and the
+// characters !"#$%&()/,.;?@_`'{}|~, may not begin with a digit or a period,
+// and may not be a section keyword, free or infinity.
+func WriteLP(w io.Writer, m *Model) error {
+	obj, vars, rows, err := modelNames(m)
+	if err != nil {
+		return err
+	}
+	for _, names := range [][]string{{obj}, vars, rows} {
+		for _, name := range names {
+			if err := checkLPName(name); err != nil {
+				return err
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	if m.Name != "" {
+		fmt.Fprintf(bw, "\\ Problem name: %s\n", m.Name)
+	}
+	if m.Maximize {
+		bw.WriteString("Maximize\n")
+	} else {
+		bw.WriteString("Minimize\n")
+	}
+	// Variables that appear nowhere else are written with a zero
+	// coefficient in the objective so that they are not lost.
+	keep := make([]bool, len(vars))
+	for j := range vars {
+		keep[j] = m.Lower[j] == 0 && math.IsInf(m.Upper[j], 1) && !m.isInteger(j) && !hasEntries(m, j)
+	}
+	fmt.Fprintf(bw, " %s:", obj)
+	writeLPExpr(bw, vars, m.C, keep, m.Offset, "0")
+	bw.WriteByte('\n')
+
+	bw.WriteString("Subject To\n")
+	var zero string
+	if len(vars) > 0 {
+		zero = "0 " + vars[0]
+	}
+	for i, name := range rows {
+		lo, up := m.RowLower[i], m.RowUpper[i]
+		fmt.Fprintf(bw, " %s:", name)
+		ranged := lo != up && !math.IsInf(lo, -1) && !math.IsInf(up, 1)
+		if ranged {
+			fmt.Fprintf(bw, " %s <=", formatLPNumber(lo))
+		}
+		writeLPExpr(bw, vars, m.R.RawRowView(i), nil, 0, zero)
+		switch {
+		case lo == up:
+			fmt.Fprintf(bw, " = %s\n", formatLPNumber(lo))
+		case ranged || !math.IsInf(up, 1):
+			fmt.Fprintf(bw, " <= %s\n", formatLPNumber(up))
+		default:
+			fmt.Fprintf(bw, " >= %s\n", formatLPNumber(lo))
+		}
+	}
+
+	var bounded bool
+	for j, name := range vars {
+		lo, up := m.Lower[j], m.Upper[j]
+		if lo == 0 && math.IsInf(up, 1) {
+			continue
+		}
+		if !bounded {
+			bw.WriteString("Bounds\n")
+			bounded = true
+		}
+		switch {
+		case lo == up:
+			fmt.Fprintf(bw, " %s = %s\n", name, formatLPNumber(lo))
+		case math.IsInf(lo, -1) && math.IsInf(up, 1):
+			fmt.Fprintf(bw, " %s free\n", name)
+		case lo == 0:
+			fmt.Fprintf(bw, " %s <= %s\n", name, formatLPNumber(up))
+		case math.IsInf(up, 1):
+			fmt.Fprintf(bw, " %s >= %s\n", name, formatLPNumber(lo))
+		default:
+			fmt.Fprintf(bw, " %s <= %s <= %s\n", formatLPNumber(lo), name, formatLPNumber(up))
+		}
+	}
+
+	var n int
+	for j, name := range vars {
+		if !m.isInteger(j) {
+			continue
+		}
+		switch {
+		case n == 0:
+			bw.WriteString("Generals\n")
+		case n%lpTermsPerLine == 0:
+			bw.WriteByte('\n')
+		}
+		bw.WriteByte(' ')
+		bw.WriteString(name)
+		n++
+	}
+	if n > 0 {
+		bw.WriteByte('\n')
+	}
+	bw.WriteString("End\n")
+	return bw.Flush()
+}
@@ -0,0 +1,159 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+const testLP = `\ Problem name: TESTLP
+\ The test model in the LP format.
+Maximize
+ COST: X1 + 2 X2 - X3 + X4 + 2.5
+Subject To
+ LIM1: 1.5 <= X1 + X2 <= 4
+ LIM2: X1 >= 1
+ MYEQN: - X2 + X3 = 7
+ RNGE: 1 <= X3 + X4 <= 2
+Bounds
+ X1 <= 4
+ -1 <= X2 <= 1
+ X3 free
+ -inf <= X4 <= -3
+Generals
+ X2
+End
+`
+
+func TestReadLP(t *testing.T) {
+	t.Parallel()
+	m, err := ReadLP(strings.NewReader(testLP))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The name is held in a comment.
+	want := testModel()
+	want.Name = ""
+	checkModel(t, "test model", m, want)
+}
+
+func TestReadLPSyntax(t *testing.T) {
+	t.Parallel()
+	const text = `minimize obj: 3x + 2 y
+  - 2.5e1 z - z + 1e+1
+st
+ x + y
+   + z >= 2 \ A constraint spanning lines.
+ -x + y =< 4
+ c4: 2 x - y > -INFINITY
+ lim: 8 >= x + 3 + y
+ 9 = y
+ c7: 1 <= z
+BOUNDS
+ x >= -inf
+ 3 >= z
+ w <= 5
+Binary
+ y
+General w
+END
+ ignored
+`
+	m, err := ReadLP(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inf := math.Inf(1)
+	want := &Model{
+		Objective: "obj",
+		C:         []float64{3, 2, -26, 0},
+		Offset:    10,
+		Variables: []string{"x", "y", "z", "w"},
+		Lower:     []float64{-inf, 0, 0, 0},
+		Upper:     []float64{inf, 1, 3, 5},
+		Integer:   []bool{false, true, false, true},
+		Rows:      []string{"c1", "c2", "c4", "lim", "c5", "c7"},
+		R: mat.NewDense(6, 4, []float64{
+			1, 1, 1, 0,
+			-1, 1, 0, 0,
+			2, -1, 0, 0,
+			1, 1, 0, 0,
+			0, 1, 0, 0,
+			0, 0, 1, 0,
+		}),
+		RowLower: []float64{2, -inf, -inf, -inf, 9, 1},
+		RowUpper: []float64{inf, 4, inf, 5, 9, inf},
+	}
+	checkModel(t, "syntax", m, want)
+}
+
+func TestReadLPErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name, text string
+	}{
+		{name: "missing End", text: "min\n x\n"},
+		{name: "missing objective", text: "subject to\n x >= 1\nend\n"},
+		{name: "text before objective", text: "x\nmin\n x\nend\n"},
+		{name: "unsupported section", text: "min\n x\nsos\nend\n"},
+		{name: "quadratic", text: "min\n obj: [ x ^ 2 ]\nend\n"},
+		{name: "invalid character", text: "min\n x * y\nend\n"},
+		{name: "invalid objective", text: "min\n x y\nend\n"},
+		{name: "missing operator", text: "min\n x\nst\n c1: x + y\nend\n"},
+		{name: "missing rhs", text: "min\n x\nst\n c1: x + y <=\nend\n"},
+		{name: "duplicate constraint", text: "min\n x\nst\n c1: x >= 1\n c1: x <= 2\nend\n"},
+		{name: "invalid bound", text: "min\n x\nbounds\n x y\nend\n"},
+		{name: "missing bound", text: "min\n x\nbounds\n x <= y\nend\n"},
+		{name: "invalid general", text: "min\n x\ngenerals\n 1\nend\n"},
+	} {
+		_, err := ReadLP(strings.NewReader(test.text))
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestWriteLP(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		want := randomModel(rnd)
+		var buf bytes.Buffer
+		if err := WriteLP(&buf, want); err != nil {
+			t.Fatalf("test %d: unexpected error writing: %v", i, err)
+		}
+		got, err := ReadLP(&buf)
+		if err != nil {
+			t.Fatalf("test %d: unexpected error reading: %v\n%s", i, err, buf.String())
+		}
+		// The name is written as a comment.
+		got.Name = want.Name
+		checkModel(t, "test "+strconv.Itoa(i), got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLP(&buf, testModel()); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+	if buf.String() != testLP[:strings.Index(testLP, "\n")+1]+testLP[strings.Index(testLP, "Maximize"):] {
+		t.Errorf("unexpected LP file:\n%s", buf.String())
+	}
+
+	m := testModel()
+	for _, name := range []string{"1x", ".x", "x y", "x+y", "free", "Inf", "st", "END"} {
+		m.Variables[0] = name
+		if err := WriteLP(&bytes.Buffer{}, m); err == nil {
+			t.Errorf("expected error for name %q", name)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"container/heap"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	// ErrNodeLimit is returned when MILP reaches the maximum number of
+	// nodes.
+	ErrNodeLimit = errors.New("lp: node limit reached")
+	// ErrTimeLimit is returned when MILP reaches the time limit.
+	ErrTimeLimit = errors.New("lp: time limit reached")
+	// ErrStopped is returned when the incumbent callback stops MILP.
+	ErrStopped = errors.New("lp: search stopped by callback")
+)
+
+// NodeSelection is the rule used by MILP to select the next node of the
+// branch and bound tree.
+type NodeSelection int
+
+const (
+	// BestBound selects the open node with the lowest bound on the
+	// objective, which minimizes the number of nodes solved.
+	BestBound NodeSelection = iota
+	// DepthFirst selects the most recently created node, which finds
+	// feasible solutions quickly and keeps few nodes open.
+	DepthFirst
+)
+
+const (
+	defaultIntegralityTol = 1e-6
+	defaultMIPGap         = 1e-6
+
+	// maxCutRounds is the maximum number of rounds of Gomory cuts at
+	// the root node, and maxCutsPerRound the maximum number of cuts
+	// added in each round.
+	maxCutRounds    = 10
+	maxCutsPerRound = 20
+
+	// minCutFrac is the minimum distance of a basic integer variable
+	// from an integer for its row to generate a cut, and maxCutDynamism
+	// the maximum ratio of the largest to the smallest non-zero
+	// coefficient of a cut.
+	minCutFrac     = 0.01
+	maxCutDynamism = 1e6
+)
+
+// MILPSettings holds the settings for MILP.
+type MILPSettings struct {
+	// IntegralityTol is the largest distance of an integer variable
+	// from an integer at which it is considered integral. If it is zero,
+	// a default of 1e-6 is used.
+	IntegralityTol float64
+
+	// Gap is the relative gap between the objective value of the best
+	// solution and the lower bound at which the search stops. If it is
+	// zero, a default of 1e-6 is used.
+	Gap float64
+
+	// NodeSelection is the rule used to select the next node.
+	NodeSelection NodeSelection
+
+	// MaxNodes is the maximum number of nodes solved. If it is zero,
+	// the number of nodes is not limited.
+	MaxNodes int
+
+	// TimeLimit is the maximum run time. If it is zero, the run time is
+	// not limited.
+	TimeLimit time.Duration
+
+	// NoCuts disables the Gomory cuts at the root node.
+	NoCuts bool
+
+	// Incumbent, if not nil, is called with each new best solution and
+	// its objective value. x must not be retained or modified. If
+	// Incumbent returns false, the search stops and MILP returns
+	// ErrStopped.
+	Incumbent func(x []float64, f float64) bool
+}
+
+// MILPResult holds the result of a mixed-integer linear program.
+type MILPResult struct {
+	// X is the best solution found and F its objective value. X is nil
+	// and F is +Inf if no solution was found before the search stopped.
+	X []float64
+	F float64
+
+	// Bound is a lower bound on the optimal objective value and Gap the
+	// relative gap (F - Bound) / max(1, |F|).
+	Bound float64
+	Gap   float64
+
+	// Nodes is the number of nodes solved and Cuts the number of Gomory
+	// cuts added at the root node.
+	Nodes int
+	Cuts  int
+}
+
+// MILP solves a mixed-integer linear program in standard form
+//  minimize	cᵀ x
+//  s.t. 		A*x = b
+//  			x >= 0
+//  			xⱼ integer if integer[j]
+// by branch and bound.
+//
+// The LP relaxations are solved by a bounded-variable simplex method. The
+// relaxation of the problem is first strengthened by rounds of Gomory
+// mixed-integer cuts derived from the optimal simplex tableau. Each node
+// of the branch and bound tree then solves the relaxation with the bounds
+// on the integer variables of the node, starting the dual simplex method
+// from the optimal basis of its parent, and branches on the most
+// fractional integer variable xⱼ = v by creating nodes with xⱼ ≤ ⌊v⌋ and
+// xⱼ ≥ ⌈v⌉. Nodes whose relaxation is not better than the best solution,
+// within the gap, are pruned. The integer variables of the returned
+// solution are rounded to integers.
+//
+// The Convert function can be used to transform a general problem into
+// standard form; the variables added by Convert for the negative parts of
+// integer variables should also be integer, and the slack variables should
+// be integer only if the coefficients of the inequality are integers.
+//
+// MILP returns ErrInfeasible if the problem has no feasible point and
+// ErrUnbounded if its LP relaxation is unbounded. If the node or time
+// limit is reached or the incumbent callback stops the search, MILP
+// returns the best solution found with ErrNodeLimit, ErrTimeLimit or
+// ErrStopped.
+//
+// References:
+//  - Wolsey, L.: Integer Programming. Wiley (1998)
+//  - Cornuéjols, G.: Valid inequalities for mixed integer linear programs.
+//    Math. Program. 112(1), 3-44 (2008)
+func MILP(c []float64, A mat.Matrix, b []float64, integer []bool, settings *MILPSettings) (*MILPResult, error) {
+	m, n := A.Dims()
+	if len(c) != n || len(b) != m || len(integer) != n {
+		panic(badShape)
+	}
+	tol := defaultIntegralityTol
+	gap := defaultMIPGap
+	var s MILPSettings
+	if settings != nil {
+		s = *settings
+		if s.IntegralityTol < 0 || s.Gap < 0 || s.MaxNodes < 0 || s.TimeLimit < 0 {
+			panic("lp: invalid settings")
+		}
+		if s.NodeSelection != BestBound && s.NodeSelection != DepthFirst {
+			panic("lp: unknown node selection")
+		}
+		if s.IntegralityTol != 0 {
+			tol = s.IntegralityTol
+		}
+		if s.Gap != 0 {
+			gap = s.Gap
+		}
+	}
+	start := time.Now()
+
+	// Solve the root relaxation and strengthen it with Gomory cuts.
+	rel := newBoundedSimplex(c, A, b)
+	if err := rel.solve(); err != nil {
+		return nil, err
+	}
+	isInt := make([]bool, rel.n)
+	copy(isInt, integer)
+	var cuts int
+	for round := 0; round < maxCutRounds && !s.NoCuts; round++ {
+		f := floats.Dot(rel.c, rel.x)
+		alphas := gomoryCuts(rel, isInt)
+		if len(alphas) == 0 {
+			break
+		}
+		for _, alpha := range alphas {
+			rel.addCut(alpha)
+			isInt = append(isInt, false, false)
+		}
+		cuts += len(alphas)
+		if err := rel.solve(); err != nil {
+			return nil, err
+		}
+		if floats.Dot(rel.c, rel.x)-f <= 1e-6*math.Max(1, math.Abs(f)) {
+			break
+		}
+	}
+
+	var (
+		best   []float64
+		bestF  = math.Inf(1)
+		pruned = math.Inf(1) // Lowest bound of the nodes pruned within the gap.
+		nodes  int
+		id     int
+	)
+	open := &nodeQueue{depthFirst: s.NodeSelection == DepthFirst}
+	root := &bbNode{
+		lo:      make([]float64, rel.n),
+		up:      make([]float64, rel.n),
+		basis:   make([]int, rel.m),
+		atUpper: make([]bool, rel.n),
+		bound:   math.Inf(-1),
+	}
+	copy(root.lo, rel.lo)
+	copy(root.up, rel.up)
+	copy(root.basis, rel.basis)
+	copy(root.atUpper, rel.atUpper)
+	heap.Push(open, root)
+	cutoff := func() float64 {
+		if best == nil {
+			return math.Inf(1)
+		}
+		return bestF - gap*math.Max(1, math.Abs(bestF))
+	}
+	prune := func(bound float64) bool {
+		if bound < cutoff() {
+			return false
+		}
+		if bound < bestF {
+			pruned = math.Min(pruned, bound)
+		}
+		return true
+	}
+	result := func() *MILPResult {
+		bound := math.Min(bestF, pruned)
+		for _, nd := range open.nodes {
+			bound = math.Min(bound, nd.bound)
+		}
+		r := &MILPResult{F: bestF, Bound: bound, Gap: math.Inf(1), Nodes: nodes, Cuts: cuts}
+		if best != nil {
+			r.X = make([]float64, n)
+			copy(r.X, best)
+			r.Gap = (bestF - bound) / math.Max(1, math.Abs(bestF))
+		}
+		return r
+	}
+
+	for open.Len() > 0 {
+		if s.MaxNodes > 0 && nodes >= s.MaxNodes {
+			return result(), ErrNodeLimit
+		}
+		if s.TimeLimit > 0 && time.Since(start) >= s.TimeLimit {
+			return result(), ErrTimeLimit
+		}
+		nd := heap.Pop(open).(*bbNode)
+		if prune(nd.bound) {
+			continue
+		}
+		rel.lo, rel.up = nd.lo, nd.up
+		rel.setBasis(nd.basis, nd.atUpper)
+		err := rel.solve()
+		nodes++
+		if err == ErrInfeasible {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		f := floats.Dot(rel.c, rel.x)
+		if prune(f) {
+			continue
+		}
+
+		// Branch on the most fractional integer variable.
+		x := rel.x
+		branch := -1
+		var maxFrac float64
+		for j := 0; j < n; j++ {
+			if !integer[j] {
+				continue
+			}
+			frac := math.Abs(x[j] - math.Round(x[j]))
+			if frac > tol && frac > maxFrac {
+				branch = j
+				maxFrac = frac
+			}
+		}
+		if branch < 0 {
+			xn := make([]float64, n)
+			for j := range xn {
+				xn[j] = x[j]
+				if integer[j] {
+					xn[j] = math.Round(xn[j])
+				}
+			}
+			f = floats.Dot(c, xn)
+			if f < bestF {
+				best = xn
+				bestF = f
+				if s.Incumbent != nil && !s.Incumbent(best, bestF) {
+					return result(), ErrStopped
+				}
+			}
+			continue
+		}
+
+		v := x[branch]
+		basis := make([]int, rel.m)
+		copy(basis, rel.basis)
+		atUpper := make([]bool, rel.n)
+		copy(atUpper, rel.atUpper)
+		down := &bbNode{lo: nd.lo, up: make([]float64, rel.n), basis: basis, atUpper: atUpper, bound: f}
+		copy(down.up, nd.up)
+		down.up[branch] = math.Floor(v)
+		upper := &bbNode{lo: make([]float64, rel.n), up: nd.up, basis: basis, atUpper: atUpper, bound: f}
+		copy(upper.lo, nd.lo)
+		upper.lo[branch] = math.Ceil(v)
+		// The child created last is selected first among nodes with
+		// the same bound, so create the child nearer to v last.
+		children := [2]*bbNode{upper, down}
+		if v-math.Floor(v) > 0.5 {
+			children = [2]*bbNode{down, upper}
+		}
+		for _, child := range children {
+			id++
+			child.id = id
+			heap.Push(open, child)
+		}
+	}
+	if best == nil {
+		return nil, ErrInfeasible
+	}
+	return result(), nil
+}
+
+// gomoryCuts returns the coefficients α of Gomory mixed-integer cuts
+//  αᵀ x ≥ 1
+// that are violated by the optimal basic solution of rel, where the
+// variables are non-negative and the non-basic variables are zero. The
+// cuts are derived from the rows of the simplex tableau of the basic
+// integer variables furthest from an integer.
+func gomoryCuts(rel *boundedSimplex, integer []bool) [][]float64 {
+	var rows []int
+	for i, k := range rel.basis {
+		if f0 := rel.x[k] - math.Floor(rel.x[k]); integer[k] && minCutFrac < f0 && f0 < 1-minCutFrac {
+			rows = append(rows, i)
+		}
+	}
+	frac := func(i int) float64 {
+		v := rel.x[rel.basis[i]]
+		return math.Abs(v - math.Floor(v) - 0.5)
+	}
+	sort.Slice(rows, func(i, j int) bool { return frac(rows[i]) < frac(rows[j]) })
+
+	var cuts [][]float64
+	for _, i := range rows {
+		if len(cuts) == maxCutsPerRound {
+			break
+		}
+		xk := rel.x[rel.basis[i]]
+		f0 := xk - math.Floor(xk)
+		alpha := make([]float64, rel.n)
+		amin, amax := math.Inf(1), 0.0
+		for j, v := range rel.tab.RawRowView(i) {
+			// Fixed variables, such as the artificial variables, are
+			// zero at every feasible point and need no coefficient.
+			if rel.row[j] >= 0 || rel.lo[j] == rel.up[j] {
+				continue
+			}
+			switch {
+			case integer[j]:
+				if f := v - math.Floor(v); f <= f0 {
+					alpha[j] = f / f0
+				} else {
+					alpha[j] = (1 - f) / (1 - f0)
+				}
+			case v > 0:
+				alpha[j] = v / f0
+			default:
+				alpha[j] = -v / (1 - f0)
+			}
+			if alpha[j] != 0 {
+				amin = math.Min(amin, alpha[j])
+				amax = math.Max(amax, alpha[j])
+			}
+		}
+		if amax == 0 || amax > maxCutDynamism*amin {
+			continue
+		}
+		cuts = append(cuts, alpha)
+	}
+	return cuts
+}
+
+// bbNode is a node of the branch and bound tree.
+type bbNode struct {
+	// lo and up are the bounds on the variables at the node.
+	lo, up []float64
+	// basis and atUpper are the optimal basis of the parent node and the
+	// bounds at which its non-basic variables are.
+	basis   []int
+	atUpper []bool
+	// bound is a lower bound on the objective at the node.
+	bound float64
+	// id is the order of creation of the node.
+	id int
+}
+
+// nodeQueue is a priority queue of the open nodes, ordered by their bound
+// or, for depth first selection, by their order of creation.
+type nodeQueue struct {
+	nodes      []*bbNode
+	depthFirst bool
+}
+
+func (q *nodeQueue) Len() int { return len(q.nodes) }
+
+func (q *nodeQueue) Less(i, j int) bool {
+	a, b := q.nodes[i], q.nodes[j]
+	if !q.depthFirst && a.bound != b.bound {
+		return a.bound < b.bound
+	}
+	return a.id > b.id
+}
+
+func (q *nodeQueue) Swap(i, j int) { q.nodes[i], q.nodes[j] = q.nodes[j], q.nodes[i] }
+
+func (q *nodeQueue) Push(x interface{}) { q.nodes = append(q.nodes, x.(*bbNode)) }
+
+func (q *nodeQueue) Pop() interface{} {
+	n := len(q.nodes) - 1
+	nd := q.nodes[n]
+	q.nodes[n] = nil
+	q.nodes = q.nodes[:n]
+	return nd
+}
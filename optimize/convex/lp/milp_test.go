@@ -0,0 +1,462 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var milpSettings = []struct {
+	name     string
+	settings MILPSettings
+}{
+	{name: "BestBound", settings: MILPSettings{}},
+	{name: "DepthFirst", settings: MILPSettings{NodeSelection: DepthFirst}},
+	{name: "NoCuts", settings: MILPSettings{NoCuts: true}},
+	{name: "DepthFirstNoCuts", settings: MILPSettings{NodeSelection: DepthFirst, NoCuts: true}},
+}
+
+func TestMILP(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name    string
+		c       []float64
+		A       mat.Matrix
+		b       []float64
+		integer []bool
+		want    []float64
+		wantF   float64
+	}{
+		{
+			// maximize x1 s.t. 3x0 + 2x1 ≤ 6, -3x0 + 2x1 ≤ 0, whose LP
+			// relaxation has the solution (1, 1.5).
+			name: "Gomory",
+			c:    []float64{0, -1, 0, 0},
+			A: mat.NewDense(2, 4, []float64{
+				3, 2, 1, 0,
+				-3, 2, 0, 1,
+			}),
+			b:       []float64{6, 0},
+			integer: []bool{true, true, true, true},
+			want:    []float64{1, 1, 1, 1},
+			wantF:   -1,
+		},
+		{
+			// A knapsack problem with binary variables.
+			name: "Knapsack",
+			c:    []float64{-8, -11, -6, -4, 0, 0, 0, 0, 0},
+			A: mat.NewDense(5, 9, []float64{
+				5, 7, 4, 3, 1, 0, 0, 0, 0,
+				1, 0, 0, 0, 0, 1, 0, 0, 0,
+				0, 1, 0, 0, 0, 0, 1, 0, 0,
+				0, 0, 1, 0, 0, 0, 0, 1, 0,
+				0, 0, 0, 1, 0, 0, 0, 0, 1,
+			}),
+			b:       []float64{14, 1, 1, 1, 1},
+			integer: []bool{true, true, true, true, true, true, true, true, true},
+			want:    []float64{0, 1, 1, 1, 0, 1, 0, 0, 0},
+			wantF:   -21,
+		},
+		{
+			// x0 is continuous, and the integer x1 limits it through
+			// x0 ≤ 1.5 x1, x1 ≤ 2.5.
+			name: "Mixed",
+			c:    []float64{-1, 0.5, 0, 0},
+			A: mat.NewDense(2, 4, []float64{
+				1, -1.5, 1, 0,
+				0, 1, 0, 1,
+			}),
+			b:       []float64{0, 2.5},
+			integer: []bool{false, true, false, false},
+			want:    []float64{3, 2, 0, 0.5},
+			wantF:   -2,
+		},
+	} {
+		for _, s := range milpSettings {
+			settings := s.settings
+			result, err := MILP(test.c, test.A, test.b, test.integer, &settings)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", test.name, s.name, err)
+				continue
+			}
+			if !floats.EqualApprox(result.X, test.want, 1e-8) {
+				t.Errorf("%s %s: unexpected solution: got %v, want %v", test.name, s.name, result.X, test.want)
+			}
+			if math.Abs(result.F-test.wantF) > 1e-8 {
+				t.Errorf("%s %s: unexpected objective: got %v, want %v", test.name, s.name, result.F, test.wantF)
+			}
+			checkMILPResult(t, test.name+" "+s.name, test.c, test.A, test.b, test.integer, result)
+		}
+	}
+}
+
+func TestMILPCuts(t *testing.T) {
+	t.Parallel()
+	c := []float64{0, -1, 0, 0}
+	A := mat.NewDense(2, 4, []float64{3, 2, 1, 0, -3, 2, 0, 1})
+	b := []float64{6, 0}
+	integer := []bool{true, true, true, true}
+	result, err := MILP(c, A, b, integer, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Cuts == 0 {
+		t.Errorf("no cuts added")
+	}
+	noCuts, err := MILP(c, A, b, integer, &MILPSettings{NoCuts: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if noCuts.Cuts != 0 {
+		t.Errorf("unexpected cuts with NoCuts: %d", noCuts.Cuts)
+	}
+	if result.Nodes > noCuts.Nodes {
+		t.Errorf("cuts increased the number of nodes: %d > %d", result.Nodes, noCuts.Nodes)
+	}
+}
+
+func TestMILPRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		// Generate an integer program
+		//  minimize cᵀ x s.t. G x ≤ h, 0 ≤ x ≤ u, x integer
+		// with integer G and h, so that the slack variables are
+		// integer, and find its solution by enumeration.
+		n := rnd.Intn(3) + 1
+		mg := rnd.Intn(3) + 1
+		g := mat.NewDense(mg, n, nil)
+		h := make([]float64, mg)
+		for k := 0; k < mg; k++ {
+			for j := 0; j < n; j++ {
+				g.Set(k, j, float64(rnd.Intn(11)-5))
+			}
+			h[k] = float64(rnd.Intn(15) - 3)
+		}
+		u := make([]float64, n)
+		for j := range u {
+			u[j] = float64(rnd.Intn(4) + 1)
+		}
+		cx := make([]float64, n)
+		for j := range cx {
+			cx[j] = rnd.NormFloat64()
+		}
+		wantF, feasible := enumerateIP(cx, g, h, u)
+
+		// Form the standard form problem with the slacks of G x ≤ h and
+		// x ≤ u.
+		m := mg + n
+		nv := n + m
+		a := mat.NewDense(m, nv, nil)
+		b := make([]float64, m)
+		c := make([]float64, nv)
+		copy(c, cx)
+		for k := 0; k < mg; k++ {
+			for j := 0; j < n; j++ {
+				a.Set(k, j, g.At(k, j))
+			}
+			a.Set(k, n+k, 1)
+			b[k] = h[k]
+		}
+		for j := 0; j < n; j++ {
+			a.Set(mg+j, j, 1)
+			a.Set(mg+j, n+mg+j, 1)
+			b[mg+j] = u[j]
+		}
+		integer := make([]bool, nv)
+		for j := range integer {
+			integer[j] = true
+		}
+
+		for _, s := range milpSettings {
+			settings := s.settings
+			result, err := MILP(c, a, b, integer, &settings)
+			if !feasible {
+				if err != ErrInfeasible {
+					t.Errorf("test %d %s: unexpected error for infeasible problem: %v", i, s.name, err)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("test %d %s: unexpected error: %v", i, s.name, err)
+				continue
+			}
+			if math.Abs(result.F-wantF) > 1e-8*math.Max(1, math.Abs(wantF)) {
+				t.Errorf("test %d %s: objective mismatch: got %v, want %v", i, s.name, result.F, wantF)
+			}
+			checkMILPResult(t, "random", c, a, b, integer, result)
+		}
+	}
+}
+
+// enumerateIP returns the optimal objective value of the integer program
+//  minimize cᵀ x s.t. G x ≤ h, 0 ≤ x ≤ u, x integer
+// and whether it is feasible by enumerating its points.
+func enumerateIP(c []float64, g mat.Matrix, h, u []float64) (float64, bool) {
+	n := len(c)
+	x := make([]float64, n)
+	gx := make([]float64, len(h))
+	best := math.Inf(1)
+	for {
+		mat.NewVecDense(len(h), gx).MulVec(g, mat.NewVecDense(n, x))
+		ok := true
+		for k, v := range gx {
+			if v > h[k] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			best = math.Min(best, floats.Dot(c, x))
+		}
+		j := 0
+		for ; j < n; j++ {
+			if x[j] < u[j] {
+				x[j]++
+				break
+			}
+			x[j] = 0
+		}
+		if j == n {
+			return best, !math.IsInf(best, 1)
+		}
+	}
+}
+
+func TestMILPErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name    string
+		c       []float64
+		A       mat.Matrix
+		b       []float64
+		integer []bool
+		err     error
+	}{
+		{
+			// 2 x0 + 2 x1 = 1 has no integer solution.
+			name:    "Infeasible",
+			c:       []float64{1, 1},
+			A:       mat.NewDense(1, 2, []float64{2, 2}),
+			b:       []float64{1},
+			integer: []bool{true, true},
+			err:     ErrInfeasible,
+		},
+		{
+			name:    "InfeasibleRelaxation",
+			c:       []float64{1, 1},
+			A:       mat.NewDense(1, 2, []float64{1, 1}),
+			b:       []float64{-1},
+			integer: []bool{true, false},
+			err:     ErrInfeasible,
+		},
+		{
+			name:    "Unbounded",
+			c:       []float64{-1, 0},
+			A:       mat.NewDense(1, 2, []float64{1, -1}),
+			b:       []float64{0},
+			integer: []bool{true, true},
+			err:     ErrUnbounded,
+		},
+	} {
+		for _, s := range milpSettings {
+			settings := s.settings
+			_, err := MILP(test.c, test.A, test.b, test.integer, &settings)
+			if err != test.err {
+				t.Errorf("%s %s: unexpected error: got %v, want %v", test.name, s.name, err, test.err)
+			}
+		}
+	}
+}
+
+// knapsack returns a knapsack problem with n binary variables that needs
+// many nodes to solve.
+func knapsack(n int) (c []float64, A *mat.Dense, b []float64, integer []bool) {
+	rnd := rand.New(rand.NewSource(1))
+	c = make([]float64, 2*n+1)
+	A = mat.NewDense(n+1, 2*n+1, nil)
+	b = make([]float64, n+1)
+	integer = make([]bool, 2*n+1)
+	var total float64
+	for j := 0; j < n; j++ {
+		w := float64(20 + rnd.Intn(20))
+		c[j] = -w - float64(rnd.Intn(5))
+		A.Set(0, j, w)
+		total += w
+		A.Set(j+1, j, 1)
+		A.Set(j+1, n+1+j, 1)
+		b[j+1] = 1
+	}
+	A.Set(0, n, 1)
+	b[0] = math.Floor(total / 2)
+	for j := range integer {
+		integer[j] = true
+	}
+	return c, A, b, integer
+}
+
+func TestMILPLimits(t *testing.T) {
+	t.Parallel()
+	c, A, b, integer := knapsack(12)
+	want, err := MILP(c, A, b, integer, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want.Nodes < 5 {
+		t.Fatalf("problem too easy: %d nodes", want.Nodes)
+	}
+	if want.Bound > want.F || want.Gap > defaultMIPGap {
+		t.Errorf("unexpected bound at optimum: F=%v, Bound=%v, Gap=%v", want.F, want.Bound, want.Gap)
+	}
+
+	for _, s := range milpSettings {
+		settings := s.settings
+		settings.MaxNodes = 3
+		result, err := MILP(c, A, b, integer, &settings)
+		if err != ErrNodeLimit {
+			t.Errorf("%s: unexpected error: got %v, want %v", s.name, err, ErrNodeLimit)
+			continue
+		}
+		if result.Nodes != 3 {
+			t.Errorf("%s: unexpected number of nodes: got %d, want 3", s.name, result.Nodes)
+		}
+		if result.Bound > want.F+1e-8 {
+			t.Errorf("%s: bound %v above optimum %v", s.name, result.Bound, want.F)
+		}
+		if result.X != nil {
+			if result.F < want.F-1e-8 {
+				t.Errorf("%s: objective %v below optimum %v", s.name, result.F, want.F)
+			}
+			checkMILPResult(t, s.name, c, A, b, integer, result)
+		} else if !math.IsInf(result.F, 1) || !math.IsInf(result.Gap, 1) {
+			t.Errorf("%s: unexpected result without solution: %+v", s.name, result)
+		}
+	}
+
+	result, err := MILP(c, A, b, integer, &MILPSettings{TimeLimit: 1})
+	if err != ErrTimeLimit {
+		t.Errorf("unexpected error: got %v, want %v", err, ErrTimeLimit)
+	}
+	if result == nil || result.Nodes != 0 {
+		t.Errorf("unexpected result at time limit: %+v", result)
+	}
+
+	// A large gap stops the search at the first solution whose
+	// objective is within the gap of the bound.
+	loose, err := MILP(c, A, b, integer, &MILPSettings{Gap: 0.5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loose.Gap > 0.5 || loose.Nodes > want.Nodes {
+		t.Errorf("unexpected result with loose gap: %+v", loose)
+	}
+}
+
+func TestMILPIncumbent(t *testing.T) {
+	t.Parallel()
+	c, A, b, integer := knapsack(12)
+	for _, s := range milpSettings {
+		var fs []float64
+		settings := s.settings
+		settings.Incumbent = func(x []float64, f float64) bool {
+			if math.Abs(floats.Dot(c, x)-f) > 1e-8 {
+				t.Errorf("%s: incumbent objective mismatch", s.name)
+			}
+			fs = append(fs, f)
+			return true
+		}
+		result, err := MILP(c, A, b, integer, &settings)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", s.name, err)
+			continue
+		}
+		if len(fs) == 0 || fs[len(fs)-1] != result.F {
+			t.Errorf("%s: last incumbent is not the solution: %v", s.name, fs)
+		}
+		for k := 1; k < len(fs); k++ {
+			if fs[k] >= fs[k-1] {
+				t.Errorf("%s: incumbent objectives not decreasing: %v", s.name, fs)
+			}
+		}
+
+		settings.Incumbent = func([]float64, float64) bool { return false }
+		result, err = MILP(c, A, b, integer, &settings)
+		if err != ErrStopped {
+			t.Errorf("%s: unexpected error: got %v, want %v", s.name, err, ErrStopped)
+			continue
+		}
+		if result.X == nil || result.F != fs[0] {
+			t.Errorf("%s: unexpected result when stopped: %+v", s.name, result)
+		}
+	}
+}
+
+func TestMILPPanics(t *testing.T) {
+	t.Parallel()
+	c := []float64{1, 1}
+	A := mat.NewDense(1, 2, []float64{1, 1})
+	b := []float64{1}
+	integer := []bool{true, true}
+	for _, test := range []struct {
+		name string
+		fn   func()
+	}{
+		{"IntegerLength", func() { MILP(c, A, b, integer[:1], nil) }},
+		{"CostLength", func() { MILP(c[:1], A, b, integer, nil) }},
+		{"NegativeGap", func() { MILP(c, A, b, integer, &MILPSettings{Gap: -1}) }},
+		{"NegativeNodes", func() { MILP(c, A, b, integer, &MILPSettings{MaxNodes: -1}) }},
+		{"NodeSelection", func() { MILP(c, A, b, integer, &MILPSettings{NodeSelection: -1}) }},
+	} {
+		if !panics(test.fn) {
+			t.Errorf("%s: expected panic", test.name)
+		}
+	}
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	fn()
+	return false
+}
+
+// checkMILPResult checks that result is a feasible point of the MILP with
+// a consistent objective value and bound.
+func checkMILPResult(t *testing.T, name string, c []float64, a mat.Matrix, b []float64, integer []bool, result *MILPResult) {
+	t.Helper()
+	m, n := a.Dims()
+	if len(result.X) != n {
+		t.Errorf("%s: unexpected solution length %d", name, len(result.X))
+		return
+	}
+	var ax mat.VecDense
+	ax.MulVec(a, mat.NewVecDense(n, result.X))
+	for i := 0; i < m; i++ {
+		if math.Abs(ax.AtVec(i)-b[i]) > 1e-8*(1+math.Abs(b[i])) {
+			t.Errorf("%s: constraint %d violated: got %v, want %v", name, i, ax.AtVec(i), b[i])
+		}
+	}
+	for j, x := range result.X {
+		if x < -1e-10 {
+			t.Errorf("%s: negative variable %d: %v", name, j, x)
+		}
+		if integer[j] && x != math.Round(x) {
+			t.Errorf("%s: integer variable %d not integral: %v", name, j, x)
+		}
+	}
+	if f := floats.Dot(c, result.X); math.Abs(f-result.F) > 1e-10*math.Max(1, math.Abs(f)) {
+		t.Errorf("%s: objective mismatch: got %v, want %v", name, result.F, f)
+	}
+	if result.Bound > result.F+1e-10 || result.Gap < 0 {
+		t.Errorf("%s: inconsistent bound: F=%v, Bound=%v, Gap=%v", name, result.F, result.Bound, result.Gap)
+	}
+}
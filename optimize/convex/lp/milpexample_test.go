@@ -0,0 +1,42 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+func ExampleMILP() {
+	// Maximize the profit 5x + 4y of producing whole units of two
+	// products subject to the limits 6x + 4y ≤ 24 and x + 2y ≤ 6 on the
+	// resources used.
+	c := []float64{-5, -4}
+	G := mat.NewDense(2, 2, []float64{6, 4, 1, 2})
+	h := []float64{24, 6}
+	cNew, aNew, bNew := lp.Convert(c, G, h, nil, nil)
+
+	// The variables of the standard form problem are the positive and
+	// negative parts of x and y and the slacks of the limits, which are
+	// integers since the coefficients of the limits are integers.
+	integer := make([]bool, len(cNew))
+	for j := range integer {
+		integer[j] = true
+	}
+	result, err := lp.MILP(cNew, aNew, bNew, integer, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("profit: %v\n", -result.F)
+	fmt.Printf("x: %v, y: %v\n", result.X[0]-result.X[2], result.X[1]-result.X[3])
+	fmt.Printf("gap: %v\n", result.Gap)
+	// Output:
+	// profit: 20
+	// x: 4, y: 0
+	// gap: 0
+}
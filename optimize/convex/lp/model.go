@@ -0,0 +1,256 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// Model is a linear program
+//  minimize	cᵀ x + Offset
+//  s.t.		RowLower <= R*x <= RowUpper
+//  			Lower <= x <= Upper
+//  			xⱼ integer if Integer[j]
+// in the form used by the MPS and LP file formats. If Maximize is true,
+// the objective is maximized instead. Absent bounds are infinite.
+type Model struct {
+	// Name is the name of the model.
+	Name string
+
+	// Maximize is true if the objective is maximized.
+	Maximize bool
+
+	// Objective is the name of the objective, C its coefficients and
+	// Offset its constant term.
+	Objective string
+	C         []float64
+	Offset    float64
+
+	// Variables holds the names of the variables, and Lower, Upper and
+	// Integer their bounds and whether they are integer.
+	Variables    []string
+	Lower, Upper []float64
+	Integer      []bool
+
+	// Rows holds the names of the constraints, R their coefficients and
+	// RowLower and RowUpper their bounds. R is nil if there are no
+	// constraints.
+	Rows               []string
+	R                  *mat.Dense
+	RowLower, RowUpper []float64
+}
+
+// dims returns the number of constraints and variables of m, and panics
+// if the fields of m have inconsistent lengths.
+func (m *Model) dims() (rows, vars int) {
+	vars = len(m.C)
+	rows = len(m.RowLower)
+	if len(m.Lower) != vars || len(m.Upper) != vars || len(m.RowUpper) != rows {
+		panic(badShape)
+	}
+	if (m.Variables != nil && len(m.Variables) != vars) || (m.Integer != nil && len(m.Integer) != vars) {
+		panic(badShape)
+	}
+	if m.Rows != nil && len(m.Rows) != rows {
+		panic(badShape)
+	}
+	if m.R == nil {
+		if rows != 0 {
+			panic(badShape)
+		}
+	} else if r, c := m.R.Dims(); r != rows || c != vars {
+		panic(badShape)
+	}
+	return rows, vars
+}
+
+// General returns the model as the general form linear program
+//  minimize	cᵀ x
+//  s.t.		G * x <= h
+//  			A * x = b
+// accepted by Convert. Constraints with equal bounds and fixed variables
+// give equality constraints, and the other finite bounds on the
+// constraints and the variables give inequality constraints. The
+// objective is negated if Maximize is true, and Offset is not included.
+// g or a is nil if there are no constraints of its type.
+func (m *Model) General() (c []float64, g mat.Matrix, h []float64, a mat.Matrix, b []float64) {
+	rows, vars := m.dims()
+	c = make([]float64, vars)
+	copy(c, m.C)
+	if m.Maximize {
+		for j := range c {
+			c[j] = -c[j]
+		}
+	}
+
+	var gRows, aRows [][]float64
+	addIneq := func(row []float64, scale, rhs float64) {
+		r := make([]float64, vars)
+		for j, v := range row {
+			r[j] = scale * v
+		}
+		gRows = append(gRows, r)
+		h = append(h, scale*rhs)
+	}
+	for i := 0; i < rows; i++ {
+		row := m.R.RawRowView(i)
+		lo, up := m.RowLower[i], m.RowUpper[i]
+		if lo == up {
+			aRows = append(aRows, append([]float64(nil), row...))
+			b = append(b, lo)
+			continue
+		}
+		if !math.IsInf(up, 1) {
+			addIneq(row, 1, up)
+		}
+		if !math.IsInf(lo, -1) {
+			addIneq(row, -1, lo)
+		}
+	}
+	unit := make([]float64, vars)
+	for j := 0; j < vars; j++ {
+		unit[j] = 1
+		lo, up := m.Lower[j], m.Upper[j]
+		if lo == up {
+			aRows = append(aRows, append([]float64(nil), unit...))
+			b = append(b, lo)
+		} else {
+			if !math.IsInf(up, 1) {
+				addIneq(unit, 1, up)
+			}
+			if !math.IsInf(lo, -1) {
+				addIneq(unit, -1, lo)
+			}
+		}
+		unit[j] = 0
+	}
+	if len(gRows) != 0 {
+		g = denseRows(gRows)
+	}
+	if len(aRows) != 0 {
+		a = denseRows(aRows)
+	}
+	return c, g, h, a, b
+}
+
+// denseRows returns a matrix with the given non-empty rows.
+func denseRows(rows [][]float64) *mat.Dense {
+	d := mat.NewDense(len(rows), len(rows[0]), nil)
+	for i, r := range rows {
+		d.SetRow(i, r)
+	}
+	return d
+}
+
+// modelNames returns the names of the objective, the variables and the
+// constraints of m for writing, generating the names that are missing.
+// It returns an error if a variable name or a row name is used twice.
+// Variables and rows may share names.
+func modelNames(m *Model) (obj string, vars, rows []string, err error) {
+	nr, nv := m.dims()
+	obj = m.Objective
+	if obj == "" {
+		obj = "obj"
+	}
+	vars = make([]string, nv)
+	for j := range vars {
+		if m.Variables != nil && m.Variables[j] != "" {
+			vars[j] = m.Variables[j]
+		} else {
+			vars[j] = "x" + strconv.Itoa(j+1)
+		}
+	}
+	rows = make([]string, nr)
+	for i := range rows {
+		if m.Rows != nil && m.Rows[i] != "" {
+			rows[i] = m.Rows[i]
+		} else {
+			rows[i] = "c" + strconv.Itoa(i+1)
+		}
+	}
+	for _, names := range [][]string{append([]string{obj}, rows...), vars} {
+		seen := make(map[string]bool)
+		for _, name := range names {
+			if seen[name] {
+				return "", nil, nil, fmt.Errorf("lp: duplicate name %q", name)
+			}
+			seen[name] = true
+		}
+	}
+	return obj, vars, rows, nil
+}
+
+// isInteger returns whether variable j of m is integer.
+func (m *Model) isInteger(j int) bool {
+	return m.Integer != nil && m.Integer[j]
+}
+
+// modelBuilder accumulates the rows and variables of a model read from a
+// file.
+type modelBuilder struct {
+	m       Model
+	varIdx  map[string]int
+	rowIdx  map[string]int
+	entries []entry
+}
+
+type entry struct {
+	row, col int
+	val      float64
+}
+
+func newModelBuilder() *modelBuilder {
+	return &modelBuilder{varIdx: make(map[string]int), rowIdx: make(map[string]int)}
+}
+
+// variable returns the index of the named variable, adding it with the
+// default bounds [0, ∞) if it is new.
+func (mb *modelBuilder) variable(name string) int {
+	if j, ok := mb.varIdx[name]; ok {
+		return j
+	}
+	j := len(mb.m.Variables)
+	mb.varIdx[name] = j
+	mb.m.Variables = append(mb.m.Variables, name)
+	mb.m.C = append(mb.m.C, 0)
+	mb.m.Lower = append(mb.m.Lower, 0)
+	mb.m.Upper = append(mb.m.Upper, math.Inf(1))
+	mb.m.Integer = append(mb.m.Integer, false)
+	return j
+}
+
+// row adds a constraint with the given bounds and returns its index.
+func (mb *modelBuilder) row(name string, lo, up float64) int {
+	i := len(mb.m.Rows)
+	mb.rowIdx[name] = i
+	mb.m.Rows = append(mb.m.Rows, name)
+	mb.m.RowLower = append(mb.m.RowLower, lo)
+	mb.m.RowUpper = append(mb.m.RowUpper, up)
+	return i
+}
+
+// model returns the model with the constraint matrix formed from the
+// accumulated entries. Repeated entries are summed.
+func (mb *modelBuilder) model() *Model {
+	m := mb.m
+	if len(m.Rows) > 0 && len(m.Variables) > 0 {
+		m.R = mat.NewDense(len(m.Rows), len(m.Variables), nil)
+		for _, e := range mb.entries {
+			m.R.Set(e.row, e.col, m.R.At(e.row, e.col)+e.val)
+		}
+	} else {
+		m.Rows, m.RowLower, m.RowUpper = nil, nil, nil
+	}
+	return &m
+}
+
+// formatFloat returns the shortest representation of v that parses to v.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
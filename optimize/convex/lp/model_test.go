@@ -0,0 +1,198 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// testModel returns the model that is written in the MPS and LP formats
+// by the tests.
+func testModel() *Model {
+	inf := math.Inf(1)
+	return &Model{
+		Name:      "TESTLP",
+		Maximize:  true,
+		Objective: "COST",
+		C:         []float64{1, 2, -1, 1},
+		Offset:    2.5,
+		Variables: []string{"X1", "X2", "X3", "X4"},
+		Lower:     []float64{0, -1, -inf, -inf},
+		Upper:     []float64{4, 1, inf, -3},
+		Integer:   []bool{false, true, false, false},
+		Rows:      []string{"LIM1", "LIM2", "MYEQN", "RNGE"},
+		R: mat.NewDense(4, 4, []float64{
+			1, 1, 0, 0,
+			1, 0, 0, 0,
+			0, -1, 1, 0,
+			0, 0, 1, 1,
+		}),
+		RowLower: []float64{1.5, 1, 7, 1},
+		RowUpper: []float64{4, inf, 7, 2},
+	}
+}
+
+func TestModelGeneral(t *testing.T) {
+	t.Parallel()
+	m := testModel()
+	c, g, h, a, b := m.General()
+	if g.(*mat.Dense).RawMatrix().Cols != 4 || a.(*mat.Dense).RawMatrix().Cols != 4 {
+		t.Fatalf("unexpected number of columns")
+	}
+	// The equality constraint, and four inequality constraints for the
+	// ranged constraints, one for LIM2 and five for the variable bounds.
+	if len(b) != 1 || len(h) != 10 {
+		t.Fatalf("unexpected number of constraints: got %d equality and %d inequality, want 1 and 10", len(b), len(h))
+	}
+	cNew, aNew, bNew := Convert(c, g, h, a, b)
+	opt, _, err := Simplex(cNew, aNew, bNew, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := -opt + m.Offset; math.Abs(got-(-5.5)) > 1e-10 {
+		t.Errorf("unexpected optimum: got %v, want -5.5", got)
+	}
+
+	m = &Model{C: []float64{1}, Lower: []float64{2}, Upper: []float64{2}}
+	_, g, h, a, b = m.General()
+	if g != nil || h != nil || a == nil || len(b) != 1 || b[0] != 2 {
+		t.Errorf("unexpected general form for a fixed variable: g=%v h=%v a=%v b=%v", g, h, a, b)
+	}
+}
+
+// randomModel returns a random model with values that are exactly
+// representable in the fixed MPS format.
+func randomModel(rnd *rand.Rand) *Model {
+	value := func() float64 {
+		return float64(rnd.Intn(41)-20) / 4
+	}
+	n := rnd.Intn(8) + 1
+	r := rnd.Intn(6)
+	m := &Model{
+		Name:      "random",
+		Maximize:  rnd.Intn(2) == 0,
+		Objective: "obj",
+		C:         make([]float64, n),
+		Variables: make([]string, n),
+		Lower:     make([]float64, n),
+		Upper:     make([]float64, n),
+		Integer:   make([]bool, n),
+	}
+	if rnd.Intn(2) == 0 {
+		m.Offset = value()
+	}
+	for j := 0; j < n; j++ {
+		m.Variables[j] = "x" + strconv.Itoa(j+1)
+		if rnd.Intn(3) != 0 {
+			m.C[j] = value()
+		}
+		m.Integer[j] = rnd.Intn(4) == 0
+		lo, up := value(), value()
+		if lo > up {
+			lo, up = up, lo
+		}
+		switch rnd.Intn(6) {
+		case 0:
+			lo, up = 0, math.Inf(1)
+		case 1:
+			lo, up = math.Inf(-1), math.Inf(1)
+		case 2:
+			lo = math.Inf(-1)
+		case 3:
+			up = math.Inf(1)
+		case 4:
+			lo = up
+		}
+		if m.Integer[j] && rnd.Intn(2) == 0 {
+			lo, up = 0, 1
+		}
+		m.Lower[j], m.Upper[j] = lo, up
+	}
+	if r == 0 {
+		return m
+	}
+	m.Rows = make([]string, r)
+	m.R = mat.NewDense(r, n, nil)
+	m.RowLower = make([]float64, r)
+	m.RowUpper = make([]float64, r)
+	for i := 0; i < r; i++ {
+		m.Rows[i] = "r" + strconv.Itoa(i+1)
+		for j := 0; j < n; j++ {
+			if rnd.Intn(2) == 0 {
+				m.R.Set(i, j, value())
+			}
+		}
+		lo, up := value(), value()
+		if lo > up {
+			lo, up = up, lo
+		}
+		switch rnd.Intn(5) {
+		case 0:
+			lo = math.Inf(-1)
+		case 1:
+			up = math.Inf(1)
+		case 2:
+			lo = up
+		case 3:
+			lo, up = math.Inf(-1), math.Inf(1)
+		}
+		m.RowLower[i], m.RowUpper[i] = lo, up
+	}
+	return m
+}
+
+// checkModel checks that got and want are the same model, allowing the
+// variables to be in a different order.
+func checkModel(t *testing.T, test string, got, want *Model) {
+	t.Helper()
+	if got.Name != want.Name || got.Maximize != want.Maximize || got.Objective != want.Objective || got.Offset != want.Offset {
+		t.Errorf("%s: unexpected header: got %q %t %q %v, want %q %t %q %v", test,
+			got.Name, got.Maximize, got.Objective, got.Offset, want.Name, want.Maximize, want.Objective, want.Offset)
+	}
+	if len(got.Variables) != len(want.Variables) {
+		t.Errorf("%s: unexpected variables: got %v, want %v", test, got.Variables, want.Variables)
+		return
+	}
+	if len(got.Rows) != len(want.Rows) {
+		t.Errorf("%s: unexpected rows: got %v, want %v", test, got.Rows, want.Rows)
+		return
+	}
+	idx := make(map[string]int)
+	for j, name := range got.Variables {
+		idx[name] = j
+	}
+	for j, name := range want.Variables {
+		k, ok := idx[name]
+		if !ok {
+			t.Errorf("%s: missing variable %q", test, name)
+			continue
+		}
+		if got.C[k] != want.C[j] || got.Lower[k] != want.Lower[j] || got.Upper[k] != want.Upper[j] || got.Integer[k] != want.Integer[j] {
+			t.Errorf("%s: variable %q mismatch: got c=%v [%v, %v] integer=%t, want c=%v [%v, %v] integer=%t", test, name,
+				got.C[k], got.Lower[k], got.Upper[k], got.Integer[k], want.C[j], want.Lower[j], want.Upper[j], want.Integer[j])
+		}
+		for i, row := range want.Rows {
+			if got.Rows[i] != row {
+				t.Errorf("%s: row %d mismatch: got %q, want %q", test, i, got.Rows[i], row)
+				return
+			}
+			if got.R.At(i, k) != want.R.At(i, j) {
+				t.Errorf("%s: coefficient of %q in %q mismatch: got %v, want %v", test, name, row, got.R.At(i, k), want.R.At(i, j))
+			}
+		}
+	}
+	for i, row := range want.Rows {
+		if got.RowLower[i] != want.RowLower[i] || got.RowUpper[i] != want.RowUpper[i] {
+			t.Errorf("%s: bounds of %q mismatch: got [%v, %v], want [%v, %v]", test, row,
+				got.RowLower[i], got.RowUpper[i], want.RowLower[i], want.RowUpper[i])
+		}
+	}
+}
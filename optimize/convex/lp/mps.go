@@ -0,0 +1,647 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MPSFormat is a variant of the MPS file format.
+type MPSFormat int
+
+const (
+	// FixedMPS is the fixed MPS format, in which the fields of a line are
+	// in fixed columns and names may contain spaces.
+	FixedMPS MPSFormat = iota
+	// FreeMPS is the free MPS format, in which the fields of a line are
+	// separated by spaces.
+	FreeMPS
+)
+
+// mpsInfinity is the magnitude at and above which MPS values are
+// infinite.
+const mpsInfinity = 1e30
+
+// mpsReader holds the state of an MPS file being read.
+type mpsReader struct {
+	format MPSFormat
+	line   int
+
+	mb *modelBuilder
+
+	// objective is the name of the objective row, and free holds the
+	// names of the other free rows, which are discarded.
+	objective string
+	free      map[string]bool
+
+	// kind, rhs and rng hold the type, right-hand side and range of each
+	// constraint.
+	kind     []byte
+	rhs, rng []float64
+	hasRng   []bool
+
+	// lowerSet records the variables with an explicit lower bound.
+	lowerSet []bool
+	integer  bool
+
+	// rhsSet, rngSet and bndSet are the names of the vectors that are
+	// read. Other vectors are ignored.
+	rhsSet, rngSet, bndSet *string
+}
+
+// ReadMPS reads a linear program in the given MPS format from r.
+//
+// The first N row is the objective and the other N rows are discarded. The
+// right-hand side of the objective is the negated Offset of the model. Only
+// the first RHS, RANGES and BOUNDS vectors are used. Variables default to
+// the bounds [0, ∞), including the integer variables within INTORG and
+// INTEND markers, and an UP bound with a negative value sets the lower bound
+// of the variable to -∞ unless a lower bound is given. Values with magnitude
+// at or above 1e30 are infinite. The OBJSENSE section is supported. SOS
+// constraints, semi-continuous variables and quadratic sections are not.
+func ReadMPS(r io.Reader, format MPSFormat) (*Model, error) {
+	if format != FixedMPS && format != FreeMPS {
+		panic("lp: unknown MPS format")
+	}
+	p := &mpsReader{
+		format: format,
+		mb:     newModelBuilder(),
+		free:   make(map[string]bool),
+	}
+	var (
+		section string
+		ended   bool
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		p.line++
+		line := strings.TrimRight(sc.Text(), " \t\r")
+		if line == "" || line[0] == '*' {
+			continue
+		}
+		if line[0] != ' ' && line[0] != '\t' {
+			f := strings.Fields(line)
+			section = strings.ToUpper(f[0])
+			switch section {
+			case "NAME":
+				p.mb.m.Name = strings.TrimSpace(line[len(f[0]):])
+			case "OBJSENSE":
+				if len(f) > 1 {
+					if err := p.sense(f[1]); err != nil {
+						return nil, err
+					}
+				}
+			case "ROWS", "COLUMNS", "RHS", "RANGES", "BOUNDS":
+			case "ENDATA":
+				ended = true
+			default:
+				return nil, p.errorf("unsupported section %q", f[0])
+			}
+			if ended {
+				break
+			}
+			continue
+		}
+
+		var err error
+		switch section {
+		case "OBJSENSE":
+			err = p.sense(strings.TrimSpace(line))
+		case "ROWS":
+			err = p.rows(line)
+		case "COLUMNS":
+			err = p.columns(line)
+		case "RHS", "RANGES":
+			err = p.vector(section, line)
+		case "BOUNDS":
+			err = p.bounds(line)
+		default:
+			err = p.errorf("data outside a section")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if !ended {
+		return nil, p.errorf("missing ENDATA")
+	}
+
+	m := &p.mb.m
+	m.Objective = p.objective
+	for i, kind := range p.kind {
+		rhs, r := p.rhs[i], math.Abs(p.rng[i])
+		switch {
+		case kind == 'E' && p.hasRng[i] && p.rng[i] > 0:
+			m.RowLower[i], m.RowUpper[i] = rhs, rhs+r
+		case kind == 'E' && p.hasRng[i] && p.rng[i] < 0:
+			m.RowLower[i], m.RowUpper[i] = rhs-r, rhs
+		case kind == 'E':
+			m.RowLower[i], m.RowUpper[i] = rhs, rhs
+		case kind == 'L':
+			m.RowUpper[i] = rhs
+			if p.hasRng[i] {
+				m.RowLower[i] = rhs - r
+			}
+		case kind == 'G':
+			m.RowLower[i] = rhs
+			if p.hasRng[i] {
+				m.RowUpper[i] = rhs + r
+			}
+		}
+	}
+	return p.mb.model(), nil
+}
+
+func (p *mpsReader) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("lp: mps line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// fields returns the fields of a data line. The fields of fixed MPS lines
+// are in columns 2-3, 5-12, 15-22, 25-36, 40-47 and 50-61, and trailing
+// empty fields are removed.
+func (p *mpsReader) fields(line string) []string {
+	if p.format == FreeMPS {
+		return strings.Fields(line)
+	}
+	cols := [...][2]int{{1, 3}, {4, 12}, {14, 22}, {24, 36}, {39, 47}, {49, 61}}
+	f := make([]string, 0, len(cols))
+	for _, c := range cols {
+		if c[0] >= len(line) {
+			break
+		}
+		end := c[1]
+		if end > len(line) {
+			end = len(line)
+		}
+		f = append(f, strings.TrimSpace(line[c[0]:end]))
+	}
+	for len(f) > 0 && f[len(f)-1] == "" {
+		f = f[:len(f)-1]
+	}
+	return f
+}
+
+func (p *mpsReader) sense(s string) error {
+	switch strings.ToUpper(s) {
+	case "MAX", "MAXIMIZE":
+		p.mb.m.Maximize = true
+	case "MIN", "MINIMIZE":
+		p.mb.m.Maximize = false
+	default:
+		return p.errorf("unknown objective sense %q", s)
+	}
+	return nil
+}
+
+func (p *mpsReader) number(s string) (float64, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, p.errorf("invalid number %q", s)
+	}
+	if v >= mpsInfinity {
+		v = math.Inf(1)
+	} else if v <= -mpsInfinity {
+		v = math.Inf(-1)
+	}
+	return v, nil
+}
+
+func (p *mpsReader) rows(line string) error {
+	f := p.fields(line)
+	if len(f) != 2 || f[1] == "" {
+		return p.errorf("invalid ROWS line")
+	}
+	name := f[1]
+	if _, ok := p.mb.rowIdx[name]; ok || name == p.objective || p.free[name] {
+		return p.errorf("duplicate row %q", name)
+	}
+	kind := strings.ToUpper(f[0])
+	switch kind {
+	case "N":
+		if p.objective == "" {
+			p.objective = name
+		} else {
+			p.free[name] = true
+		}
+		return nil
+	case "L", "G", "E":
+		p.mb.row(name, math.Inf(-1), math.Inf(1))
+		p.kind = append(p.kind, kind[0])
+		p.rhs = append(p.rhs, 0)
+		p.rng = append(p.rng, 0)
+		p.hasRng = append(p.hasRng, false)
+		return nil
+	}
+	return p.errorf("unknown row type %q", f[0])
+}
+
+func (p *mpsReader) columns(line string) error {
+	f := p.fields(line)
+	if p.format == FixedMPS && len(f) > 0 {
+		f = f[1:]
+	}
+	if len(f) >= 3 && f[1] == "'MARKER'" {
+		for _, s := range f[2:] {
+			switch s {
+			case "'INTORG'":
+				p.integer = true
+				return nil
+			case "'INTEND'":
+				p.integer = false
+				return nil
+			}
+		}
+		return p.errorf("invalid marker")
+	}
+	if len(f) != 3 && len(f) != 5 {
+		return p.errorf("invalid COLUMNS line")
+	}
+	name := f[0]
+	_, seen := p.mb.varIdx[name]
+	j := p.mb.variable(name)
+	if !seen {
+		p.lowerSet = append(p.lowerSet, false)
+		p.mb.m.Integer[j] = p.integer
+	}
+	for k := 1; k < len(f); k += 2 {
+		v, err := p.number(f[k+1])
+		if err != nil {
+			return err
+		}
+		row := f[k]
+		switch {
+		case row == p.objective:
+			p.mb.m.C[j] += v
+		case p.free[row]:
+		default:
+			i, ok := p.mb.rowIdx[row]
+			if !ok {
+				return p.errorf("unknown row %q", row)
+			}
+			p.mb.entries = append(p.mb.entries, entry{row: i, col: j, val: v})
+		}
+	}
+	return nil
+}
+
+// vector reads a line of the RHS or RANGES section.
+func (p *mpsReader) vector(section, line string) error {
+	f := p.fields(line)
+	if p.format == FixedMPS {
+		if len(f) > 0 {
+			f = f[1:]
+		}
+	} else if len(f) == 2 || len(f) == 4 {
+		// The vector name is omitted.
+		f = append([]string{""}, f...)
+	}
+	if len(f) != 3 && len(f) != 5 {
+		return p.errorf("invalid %s line", section)
+	}
+	set := &p.rhsSet
+	if section == "RANGES" {
+		set = &p.rngSet
+	}
+	if *set == nil {
+		*set = &f[0]
+	} else if !sameSet(**set, f[0]) {
+		return nil
+	}
+	for k := 1; k < len(f); k += 2 {
+		v, err := p.number(f[k+1])
+		if err != nil {
+			return err
+		}
+		row := f[k]
+		if p.free[row] {
+			continue
+		}
+		if row == p.objective {
+			if section == "RHS" {
+				p.mb.m.Offset = -v
+			}
+			continue
+		}
+		i, ok := p.mb.rowIdx[row]
+		if !ok {
+			return p.errorf("unknown row %q", row)
+		}
+		if section == "RHS" {
+			p.rhs[i] = v
+		} else {
+			p.rng[i] = v
+			p.hasRng[i] = true
+		}
+	}
+	return nil
+}
+
+func (p *mpsReader) bounds(line string) error {
+	f := p.fields(line)
+	if len(f) == 0 {
+		return p.errorf("invalid BOUNDS line")
+	}
+	kind := strings.ToUpper(f[0])
+	var valued bool
+	switch kind {
+	case "UP", "LO", "FX", "LI", "UI":
+		valued = true
+	case "FR", "MI", "PL", "BV":
+	default:
+		return p.errorf("unsupported bound type %q", f[0])
+	}
+	if p.format == FreeMPS && ((valued && len(f) == 3) || (!valued && len(f) == 2)) {
+		// The vector name is omitted.
+		f = append([]string{f[0], ""}, f[1:]...)
+	}
+	if len(f) < 3 || (valued && len(f) != 4) {
+		return p.errorf("invalid BOUNDS line")
+	}
+	if p.bndSet == nil {
+		p.bndSet = &f[1]
+	} else if !sameSet(*p.bndSet, f[1]) {
+		return nil
+	}
+	j, ok := p.mb.varIdx[f[2]]
+	if !ok {
+		return p.errorf("unknown column %q", f[2])
+	}
+	var v float64
+	if valued {
+		var err error
+		v, err = p.number(f[3])
+		if err != nil {
+			return err
+		}
+	}
+	m := &p.mb.m
+	switch kind {
+	case "UP", "UI":
+		m.Upper[j] = v
+		if v < 0 && m.Lower[j] == 0 && !p.lowerSet[j] {
+			m.Lower[j] = math.Inf(-1)
+		}
+	case "LO", "LI":
+		m.Lower[j] = v
+		p.lowerSet[j] = true
+	case "FX":
+		m.Lower[j], m.Upper[j] = v, v
+		p.lowerSet[j] = true
+	case "FR":
+		m.Lower[j], m.Upper[j] = math.Inf(-1), math.Inf(1)
+		p.lowerSet[j] = true
+	case "MI":
+		m.Lower[j] = math.Inf(-1)
+		p.lowerSet[j] = true
+	case "PL":
+		m.Upper[j] = math.Inf(1)
+	case "BV":
+		m.Lower[j], m.Upper[j] = 0, 1
+		p.lowerSet[j] = true
+	}
+	if kind == "LI" || kind == "UI" || kind == "BV" {
+		m.Integer[j] = true
+	}
+	return nil
+}
+
+// sameSet returns whether the vector names a and b refer to the same
+// vector. An omitted name refers to any vector.
+func sameSet(a, b string) bool {
+	return a == b || a == "" || b == ""
+}
+
+// WriteMPS writes the model m to w in the given MPS format. Names that are
+// missing are generated. In the fixed format names may have at most 8
+// characters and numbers are rounded to 12 characters, and in the free
+// format names may not contain spaces.
+func WriteMPS(w io.Writer, m *Model, format MPSFormat) error {
+	if format != FixedMPS && format != FreeMPS {
+		panic("lp: unknown MPS format")
+	}
+	obj, vars, rows, err := modelNames(m)
+	if err != nil {
+		return err
+	}
+	for _, names := range [][]string{{obj}, vars, rows} {
+		for _, name := range names {
+			if err := checkMPSName(name, format); err != nil {
+				return err
+			}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	line := func(f ...string) {
+		bw.WriteString(mpsLine(f, format))
+		bw.WriteByte('\n')
+	}
+	if m.Name != "" {
+		fmt.Fprintf(bw, "NAME          %s\n", m.Name)
+	} else {
+		bw.WriteString("NAME\n")
+	}
+	if m.Maximize {
+		bw.WriteString("OBJSENSE\n    MAX\n")
+	}
+
+	bw.WriteString("ROWS\n")
+	line("N", obj)
+	kind := make([]string, len(rows))
+	for i, name := range rows {
+		lo, up := m.RowLower[i], m.RowUpper[i]
+		switch {
+		case lo == up:
+			kind[i] = "E"
+		case math.IsInf(up, 1):
+			// Free constraints are written as G rows with an infinite
+			// right-hand side, since N rows other than the objective
+			// are discarded when read.
+			kind[i] = "G"
+		default:
+			kind[i] = "L"
+		}
+		line(kind[i], name)
+	}
+
+	bw.WriteString("COLUMNS\n")
+	var integer bool
+	for j, name := range vars {
+		if m.isInteger(j) != integer {
+			integer = !integer
+			marker := "'INTEND'"
+			if integer {
+				marker = "'INTORG'"
+			}
+			line("", "MARKER", "'MARKER'", "", marker)
+		}
+		pending := []string{"", name}
+		add := func(row string, v float64) {
+			pending = append(pending, row, formatMPSNumber(v, format))
+			if len(pending) == 6 {
+				line(pending...)
+				pending = pending[:2]
+			}
+		}
+		if m.C[j] != 0 {
+			add(obj, m.C[j])
+		}
+		for i, row := range rows {
+			if v := m.R.At(i, j); v != 0 {
+				add(row, v)
+			}
+		}
+		if len(pending) > 2 {
+			line(pending...)
+		} else if m.C[j] == 0 && !hasEntries(m, j) {
+			add(obj, 0)
+			line(pending...)
+		}
+	}
+	if integer {
+		line("", "MARKER", "'MARKER'", "", "'INTEND'")
+	}
+
+	bw.WriteString("RHS\n")
+	if m.Offset != 0 {
+		line("", "RHS", obj, formatMPSNumber(-m.Offset, format))
+	}
+	for i, row := range rows {
+		var v float64
+		switch kind[i] {
+		case "E", "G":
+			v = m.RowLower[i]
+		case "L":
+			v = m.RowUpper[i]
+		}
+		if v != 0 {
+			line("", "RHS", row, formatMPSNumber(v, format))
+		}
+	}
+
+	var ranged bool
+	for i, row := range rows {
+		if kind[i] != "L" || math.IsInf(m.RowLower[i], -1) {
+			continue
+		}
+		if !ranged {
+			bw.WriteString("RANGES\n")
+			ranged = true
+		}
+		line("", "RNG", row, formatMPSNumber(m.RowUpper[i]-m.RowLower[i], format))
+	}
+
+	var bounded bool
+	bound := func(kind, name string, v ...float64) {
+		if !bounded {
+			bw.WriteString("BOUNDS\n")
+			bounded = true
+		}
+		f := []string{kind, "BND", name}
+		if len(v) != 0 {
+			f = append(f, formatMPSNumber(v[0], format))
+		}
+		line(f...)
+	}
+	for j, name := range vars {
+		lo, up := m.Lower[j], m.Upper[j]
+		switch {
+		case m.isInteger(j) && lo == 0 && up == 1:
+			bound("BV", name)
+		case lo == up:
+			bound("FX", name, lo)
+		case math.IsInf(lo, -1) && math.IsInf(up, 1):
+			bound("FR", name)
+		case math.IsInf(lo, -1):
+			bound("MI", name)
+			bound("UP", name, up)
+		default:
+			// The upper bound is written first, since a negative upper
+			// bound otherwise sets the lower bound to -∞.
+			if !math.IsInf(up, 1) {
+				bound("UP", name, up)
+			}
+			if lo != 0 || up < 0 {
+				bound("LO", name, lo)
+			}
+		}
+	}
+	bw.WriteString("ENDATA\n")
+	return bw.Flush()
+}
+
+// hasEntries returns whether column j of the constraints of m has a
+// non-zero entry.
+func hasEntries(m *Model, j int) bool {
+	for i := range m.RowLower {
+		if m.R.At(i, j) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func checkMPSName(name string, format MPSFormat) error {
+	if name == "" || name[0] == '*' || strings.TrimSpace(name) != name {
+		return fmt.Errorf("lp: invalid MPS name %q", name)
+	}
+	if format == FixedMPS && len(name) > 8 {
+		return fmt.Errorf("lp: name %q too long for fixed MPS", name)
+	}
+	if format == FreeMPS && strings.ContainsAny(name, " \t") {
+		return fmt.Errorf("lp: invalid free MPS name %q", name)
+	}
+	return nil
+}
+
+// formatMPSNumber formats v for the given MPS format. Numbers in the fixed format
+// are rounded to fit in 12 characters.
+func formatMPSNumber(v float64, format MPSFormat) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "1e30"
+	case math.IsInf(v, -1):
+		return "-1e30"
+	}
+	s := formatFloat(v)
+	for prec := 12; format == FixedMPS && len(s) > 12; prec-- {
+		s = strconv.FormatFloat(v, 'g', prec, 64)
+	}
+	return s
+}
+
+// mpsLine returns a data line with the given fields. The first field is the
+// type of a row or a bound, and is empty in the other sections.
+func mpsLine(f []string, format MPSFormat) string {
+	if format == FreeMPS {
+		var b strings.Builder
+		b.WriteByte(' ')
+		for _, s := range f {
+			if s != "" {
+				b.WriteByte(' ')
+				b.WriteString(s)
+			}
+		}
+		return b.String()
+	}
+	start := [...]int{1, 4, 14, 24, 39, 49}
+	var b strings.Builder
+	for k, s := range f {
+		for b.Len() < start[k] {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
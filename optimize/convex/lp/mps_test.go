@@ -0,0 +1,248 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+const fixedMPS = `* The test model in fixed MPS, with a row name containing a space.
+NAME          TESTLP
+OBJSENSE
+    MAX
+ROWS
+ N  COST
+ L  LIM1
+ G  LIM2
+ E  MY EQN
+ E  RNGE
+ N  FREE
+COLUMNS
+    X1        COST                 1   LIM1                 1
+    X1        LIM2                 1
+    MARKER    'MARKER'                 'INTORG'
+    X2        COST                 2   LIM1                 1
+    X2        MY EQN              -1
+    MARKER    'MARKER'                 'INTEND'
+    X3        COST                -1   MY EQN               1
+    X3        RNGE                 1   FREE                 3
+    X4        COST                 1   RNGE                 1
+RHS
+    RHS       COST              -2.5
+    RHS       LIM1                 4   LIM2                 1
+    RHS       MY EQN               7   RNGE                 2
+    RHS2      LIM1               100
+RANGES
+    RNG       LIM1               2.5   RNGE                -1
+BOUNDS
+ UP BND       X1                   4
+ LO BND       X2                  -1
+ UP BND       X2                   1
+ MI BND       X3
+ UP BND       X4                  -3
+ENDATA
+`
+
+const freeMPS = `NAME TESTLP
+OBJSENSE MAXIMIZE
+ROWS
+ N COST
+ L LIM1
+ G LIM2
+ E MYEQN
+ E RNGE
+ N FREE
+COLUMNS
+ X1 COST 1 LIM1 1
+ X1 LIM2 1
+ MARKER 'MARKER' 'INTORG'
+ X2 COST 2 LIM1 1
+ X2 MYEQN -1
+ MARKER 'MARKER' 'INTEND'
+ X3 COST -1 MYEQN 1
+ X3 RNGE 1 FREE 3
+ X4 COST 1 RNGE 1
+RHS
+ COST -2.5
+ LIM1 4 LIM2 1
+ MYEQN 7 RNGE 2
+RANGES
+ RNG LIM1 2.5 RNGE -1
+BOUNDS
+ UP BND X1 4
+ LO BND X2 -1
+ UP BND X2 1
+ MI X3
+ UP BND X4 -3
+ENDATA
+`
+
+func TestReadMPS(t *testing.T) {
+	t.Parallel()
+	fixed := testModel()
+	fixed.Rows[2] = "MY EQN"
+	for _, test := range []struct {
+		name   string
+		text   string
+		format MPSFormat
+		want   *Model
+	}{
+		{name: "fixed", text: fixedMPS, format: FixedMPS, want: fixed},
+		{name: "free", text: freeMPS, format: FreeMPS, want: testModel()},
+	} {
+		m, err := ReadMPS(strings.NewReader(test.text), test.format)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		checkModel(t, test.name, m, test.want)
+	}
+}
+
+func TestReadMPSBounds(t *testing.T) {
+	t.Parallel()
+	const text = `NAME
+ROWS
+ N obj
+ L c1
+COLUMNS
+ a obj 1 c1 1
+ b c1 1
+ c c1 1
+ d c1 1
+ e c1 1
+ f c1 1
+ g c1 1
+ h c1 1e30
+RHS
+ RHS c1 -1e30
+BOUNDS
+ BV BND a
+ LI BND b 2
+ UI BND b 5
+ FX BND c 3
+ FR BND d
+ PL BND d
+ LO BND e -2
+ UP BND e -1
+ UP BND f -1
+ LO BND f 0
+ MI BND g
+ UP BND g 1e30
+ENDATA
+`
+	m, err := ReadMPS(strings.NewReader(text), FreeMPS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	inf := math.Inf(1)
+	wantLower := []float64{0, 2, 3, -inf, -2, 0, -inf, 0}
+	wantUpper := []float64{1, 5, 3, inf, -1, -1, inf, inf}
+	wantInteger := []bool{true, true, false, false, false, false, false, false}
+	for j, name := range m.Variables {
+		if m.Lower[j] != wantLower[j] || m.Upper[j] != wantUpper[j] || m.Integer[j] != wantInteger[j] {
+			t.Errorf("variable %s: got [%v, %v] integer=%t, want [%v, %v] integer=%t", name,
+				m.Lower[j], m.Upper[j], m.Integer[j], wantLower[j], wantUpper[j], wantInteger[j])
+		}
+	}
+	if m.R.At(0, 7) != inf || m.RowLower[0] != -inf || m.RowUpper[0] != -inf {
+		t.Errorf("unexpected infinite values: got coefficient %v and bounds [%v, %v]", m.R.At(0, 7), m.RowLower[0], m.RowUpper[0])
+	}
+}
+
+func TestReadMPSErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name, text string
+	}{
+		{name: "missing ENDATA", text: "NAME\nROWS\n N obj\n"},
+		{name: "unknown section", text: "NAME\nSOS\nENDATA\n"},
+		{name: "data outside section", text: " N obj\nENDATA\n"},
+		{name: "unknown row type", text: "ROWS\n X obj\nENDATA\n"},
+		{name: "duplicate row", text: "ROWS\n N obj\n L obj\nENDATA\n"},
+		{name: "unknown row", text: "ROWS\n N obj\nCOLUMNS\n x c1 1\nENDATA\n"},
+		{name: "invalid number", text: "ROWS\n N obj\nCOLUMNS\n x obj one\nENDATA\n"},
+		{name: "invalid columns", text: "ROWS\n N obj\nCOLUMNS\n x obj 1 obj\nENDATA\n"},
+		{name: "invalid marker", text: "ROWS\n N obj\nCOLUMNS\n M 'MARKER' 'START'\nENDATA\n"},
+		{name: "unknown rhs row", text: "ROWS\n N obj\nRHS\n RHS c1 1\nENDATA\n"},
+		{name: "unknown column", text: "ROWS\n N obj\nBOUNDS\n UP BND x 1\nENDATA\n"},
+		{name: "unsupported bound", text: "ROWS\n N obj\nCOLUMNS\n x obj 1\nBOUNDS\n SC BND x 1\nENDATA\n"},
+		{name: "missing bound value", text: "ROWS\n N obj\nCOLUMNS\n x obj 1\nBOUNDS\n UP x\nENDATA\n"},
+		{name: "unknown sense", text: "OBJSENSE\n    UP\nENDATA\n"},
+	} {
+		_, err := ReadMPS(strings.NewReader(test.text), FreeMPS)
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
+
+func TestWriteMPS(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		want := randomModel(rnd)
+		for _, format := range []MPSFormat{FixedMPS, FreeMPS} {
+			var buf bytes.Buffer
+			if err := WriteMPS(&buf, want, format); err != nil {
+				t.Fatalf("test %d: unexpected error writing: %v", i, err)
+			}
+			got, err := ReadMPS(&buf, format)
+			if err != nil {
+				t.Fatalf("test %d: unexpected error reading: %v\n%s", i, err, buf.String())
+			}
+			checkModel(t, "test "+strconv.Itoa(i), got, want)
+		}
+	}
+
+	for _, format := range []MPSFormat{FixedMPS, FreeMPS} {
+		var buf bytes.Buffer
+		if err := WriteMPS(&buf, testModel(), format); err != nil {
+			t.Fatalf("unexpected error writing: %v", err)
+		}
+		got, err := ReadMPS(&buf, format)
+		if err != nil {
+			t.Fatalf("unexpected error reading: %v", err)
+		}
+		checkModel(t, "test model", got, testModel())
+	}
+
+	m := testModel()
+	m.Variables[0] = "LONGNAME1"
+	if err := WriteMPS(&bytes.Buffer{}, m, FixedMPS); err == nil {
+		t.Errorf("expected error for long fixed MPS name")
+	}
+	if err := WriteMPS(&bytes.Buffer{}, m, FreeMPS); err != nil {
+		t.Errorf("unexpected error for long free MPS name: %v", err)
+	}
+	m.Variables[0] = "X 1"
+	if err := WriteMPS(&bytes.Buffer{}, m, FreeMPS); err == nil {
+		t.Errorf("expected error for free MPS name with a space")
+	}
+	m.Variables[0] = "X2"
+	if err := WriteMPS(&bytes.Buffer{}, m, FreeMPS); err == nil {
+		t.Errorf("expected error for duplicate name")
+	}
+}
+
+func TestFormatMPSNumber(t *testing.T) {
+	t.Parallel()
+	for _, v := range []float64{1, -0.1, 1.0 / 3, -2.0 / 3, 123456789.123456, 1e-300, -1.2345678901234e200} {
+		s := formatMPSNumber(v, FixedMPS)
+		if len(s) > 12 {
+			t.Errorf("%v formatted as %q with more than 12 characters", v, s)
+		}
+		got, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.Abs(got-v) > 1e-4*math.Abs(v) {
+			t.Errorf("%v formatted as %q", v, s)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp_test
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+func ExampleReadMPS() {
+	const model = `NAME          EXAMPLE
+OBJSENSE
+    MAX
+ROWS
+ N  PROFIT
+ L  LIM1
+ L  LIM2
+COLUMNS
+    X         PROFIT               1   LIM1                -1
+    X         LIM2                 3
+    Y         PROFIT               2   LIM1                 2
+    Y         LIM2                 1
+RHS
+    RHS       LIM1                 4   LIM2                 9
+ENDATA
+`
+	m, err := lp.ReadMPS(strings.NewReader(model), lp.FixedMPS)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Solve the model with the simplex method. The objective is
+	// maximized, so the minimum of the negated objective is found.
+	c, G, h, A, b := m.General()
+	cStd, aStd, bStd := lp.Convert(c, G, h, A, b)
+	opt, _, err := lp.Simplex(cStd, aStd, bStd, 0, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s: %s = %v\n", m.Name, m.Objective, -opt+m.Offset)
+	// Output:
+	// EXAMPLE: PROFIT = 8
+}
@@ -0,0 +1,210 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import "math"
+
+// presolved is a standard form LP reduced by presolve, with the
+// information needed to recover the solution of the original LP.
+type presolved struct {
+	// c, a and b define the reduced LP.
+	c []float64
+	a *cscMatrix
+	b []float64
+
+	// rows and cols are the indices in the original LP of the rows and
+	// columns of the reduced LP.
+	rows, cols []int
+
+	// fixed holds the values of the variables removed by presolve.
+	fixed map[int]float64
+
+	// ops holds the removed rows in the order of removal. A singleton
+	// row that fixed a variable has col set to the variable, and an empty
+	// row has col -1.
+	ops []presolveOp
+
+	// unbounded is true if a variable with negative cost appears in no
+	// row, so the LP is unbounded if it is feasible.
+	unbounded bool
+}
+
+type presolveOp struct {
+	row, col int
+	val      float64 // Coefficient of col in row.
+}
+
+// presolve reduces the standard form LP
+//  minimize cᵀ x  s.t. A x = b, x ≥ 0
+// by repeatedly removing empty rows, fixing the variable of singleton rows
+// and removing the rows, and fixing variables that appear in no row at
+// zero. It returns ErrInfeasible if the reductions show that the LP is
+// infeasible.
+func presolve(c []float64, a *cscMatrix, b []float64, tol float64) (*presolved, error) {
+	m, n := a.r, a.c
+
+	// Form the row-wise structure of A.
+	rowPtr := make([]int, m+1)
+	for _, i := range a.rowIdx {
+		rowPtr[i+1]++
+	}
+	for i := 0; i < m; i++ {
+		rowPtr[i+1] += rowPtr[i]
+	}
+	colIdx := make([]int, len(a.rowIdx))
+	rowVal := make([]float64, len(a.rowIdx))
+	next := make([]int, m)
+	copy(next, rowPtr)
+	for j := 0; j < n; j++ {
+		for k := a.colPtr[j]; k < a.colPtr[j+1]; k++ {
+			i := a.rowIdx[k]
+			colIdx[next[i]] = j
+			rowVal[next[i]] = a.val[k]
+			next[i]++
+		}
+	}
+
+	p := &presolved{fixed: make(map[int]float64)}
+	rowCount := make([]int, m)
+	for i := range rowCount {
+		rowCount[i] = rowPtr[i+1] - rowPtr[i]
+	}
+	rowActive := make([]bool, m)
+	for i := range rowActive {
+		rowActive[i] = true
+	}
+	colActive := make([]bool, n)
+	for j := range colActive {
+		colActive[j] = true
+	}
+	bCur := make([]float64, m)
+	copy(bCur, b)
+
+	// Variables in no row are at their lower bound if their cost is
+	// nonnegative, and make the LP unbounded otherwise.
+	for j := 0; j < n; j++ {
+		if a.colPtr[j] == a.colPtr[j+1] {
+			if c[j] < 0 {
+				p.unbounded = true
+			}
+			colActive[j] = false
+			p.fixed[j] = 0
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < m; i++ {
+			if !rowActive[i] {
+				continue
+			}
+			switch rowCount[i] {
+			case 0:
+				if math.Abs(bCur[i]) > tol*(1+math.Abs(b[i])) {
+					return nil, ErrInfeasible
+				}
+				rowActive[i] = false
+				p.ops = append(p.ops, presolveOp{row: i, col: -1})
+				changed = true
+			case 1:
+				j, v := -1, 0.0
+				for k := rowPtr[i]; k < rowPtr[i+1]; k++ {
+					if colActive[colIdx[k]] {
+						j, v = colIdx[k], rowVal[k]
+						break
+					}
+				}
+				xj := bCur[i] / v
+				if xj < -tol*(1+math.Abs(xj)) {
+					return nil, ErrInfeasible
+				}
+				xj = math.Max(xj, 0)
+				p.fixed[j] = xj
+				colActive[j] = false
+				for k := a.colPtr[j]; k < a.colPtr[j+1]; k++ {
+					r := a.rowIdx[k]
+					bCur[r] -= a.val[k] * xj
+					rowCount[r]--
+				}
+				rowActive[i] = false
+				p.ops = append(p.ops, presolveOp{row: i, col: j, val: v})
+				changed = true
+			}
+		}
+	}
+
+	// Form the reduced LP.
+	newRow := make([]int, m)
+	for i := 0; i < m; i++ {
+		newRow[i] = -1
+		if rowActive[i] {
+			newRow[i] = len(p.rows)
+			p.rows = append(p.rows, i)
+			p.b = append(p.b, bCur[i])
+		}
+	}
+	red := &cscMatrix{r: len(p.rows), colPtr: []int{0}}
+	for j := 0; j < n; j++ {
+		if !colActive[j] {
+			continue
+		}
+		p.cols = append(p.cols, j)
+		p.c = append(p.c, c[j])
+		for k := a.colPtr[j]; k < a.colPtr[j+1]; k++ {
+			if r := newRow[a.rowIdx[k]]; r >= 0 {
+				red.rowIdx = append(red.rowIdx, r)
+				red.val = append(red.val, a.val[k])
+			}
+		}
+		red.colPtr = append(red.colPtr, len(red.rowIdx))
+	}
+	red.c = len(p.cols)
+	p.a = red
+	if p.b == nil {
+		p.b = []float64{}
+	}
+	return p, nil
+}
+
+// postsolve returns the primal solution, the dual solution and the
+// reduced costs of the original LP given the primal and dual solutions xr
+// and yr of the reduced LP. c, a and b are the original LP.
+func (p *presolved) postsolve(c []float64, a *cscMatrix, xr, yr []float64) (x, y, s []float64) {
+	x = make([]float64, a.c)
+	for k, j := range p.cols {
+		x[j] = xr[k]
+	}
+	for j, v := range p.fixed {
+		x[j] = v
+	}
+
+	y = make([]float64, a.r)
+	for k, i := range p.rows {
+		y[i] = yr[k]
+	}
+	// Set the multiplier of each singleton row so that the reduced cost
+	// of the variable it fixed is zero, in reverse order of removal.
+	// Empty rows have zero multipliers.
+	for k := len(p.ops) - 1; k >= 0; k-- {
+		op := p.ops[k]
+		if op.col < 0 {
+			continue
+		}
+		sum := c[op.col]
+		for l := a.colPtr[op.col]; l < a.colPtr[op.col+1]; l++ {
+			if i := a.rowIdx[l]; i != op.row {
+				sum -= a.val[l] * y[i]
+			}
+		}
+		y[op.row] = sum / op.val
+	}
+
+	s = make([]float64, a.c)
+	a.mulTransVec(s, y)
+	for j := range s {
+		s[j] = c[j] - s[j]
+	}
+	return x, y, s
+}
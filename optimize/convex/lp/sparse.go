@@ -0,0 +1,129 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package lp
+
+import "gonum.org/v1/gonum/mat"
+
+// cscMatrix is a sparse matrix in compressed sparse column format.
+type cscMatrix struct {
+	r, c int
+	// The non-zero elements of column j are val[colPtr[j]:colPtr[j+1]]
+	// in rows rowIdx[colPtr[j]:colPtr[j+1]], in increasing row order.
+	colPtr []int
+	rowIdx []int
+	val    []float64
+}
+
+// newCSC returns the non-zero elements of a in compressed sparse column
+// format. If a is a mat.ColNonZeroDoer or mat.NonZeroDoer, only its non-zero
+// elements are visited.
+func newCSC(a mat.Matrix) *cscMatrix {
+	r, c := a.Dims()
+	m := &cscMatrix{r: r, c: c, colPtr: make([]int, c+1)}
+	switch a := a.(type) {
+	case mat.ColNonZeroDoer:
+		for j := 0; j < c; j++ {
+			a.DoColNonZero(j, func(i, _ int, v float64) {
+				if v != 0 {
+					m.rowIdx = append(m.rowIdx, i)
+					m.val = append(m.val, v)
+				}
+			})
+			m.colPtr[j+1] = len(m.rowIdx)
+		}
+		m.sortColumns()
+	case mat.NonZeroDoer:
+		// Count the elements of each column, then fill the columns.
+		a.DoNonZero(func(_, j int, v float64) {
+			if v != 0 {
+				m.colPtr[j+1]++
+			}
+		})
+		for j := 0; j < c; j++ {
+			m.colPtr[j+1] += m.colPtr[j]
+		}
+		m.rowIdx = make([]int, m.colPtr[c])
+		m.val = make([]float64, m.colPtr[c])
+		next := make([]int, c)
+		copy(next, m.colPtr)
+		a.DoNonZero(func(i, j int, v float64) {
+			if v != 0 {
+				m.rowIdx[next[j]] = i
+				m.val[next[j]] = v
+				next[j]++
+			}
+		})
+		m.sortColumns()
+	default:
+		for j := 0; j < c; j++ {
+			for i := 0; i < r; i++ {
+				if v := a.At(i, j); v != 0 {
+					m.rowIdx = append(m.rowIdx, i)
+					m.val = append(m.val, v)
+				}
+			}
+			m.colPtr[j+1] = len(m.rowIdx)
+		}
+	}
+	return m
+}
+
+// sortColumns sorts the elements of each column by row.
+func (m *cscMatrix) sortColumns() {
+	for j := 0; j < m.c; j++ {
+		lo, hi := m.colPtr[j], m.colPtr[j+1]
+		// Insertion sort, since columns are usually short and sorted.
+		for k := lo + 1; k < hi; k++ {
+			for l := k; l > lo && m.rowIdx[l-1] > m.rowIdx[l]; l-- {
+				m.rowIdx[l-1], m.rowIdx[l] = m.rowIdx[l], m.rowIdx[l-1]
+				m.val[l-1], m.val[l] = m.val[l], m.val[l-1]
+			}
+		}
+	}
+}
+
+// mulVec stores A x in dst.
+func (m *cscMatrix) mulVec(dst, x []float64) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	for j, xj := range x {
+		if xj == 0 {
+			continue
+		}
+		for k := m.colPtr[j]; k < m.colPtr[j+1]; k++ {
+			dst[m.rowIdx[k]] += m.val[k] * xj
+		}
+	}
+}
+
+// mulTransVec stores Aᵀ y in dst.
+func (m *cscMatrix) mulTransVec(dst, y []float64) {
+	for j := range dst {
+		var v float64
+		for k := m.colPtr[j]; k < m.colPtr[j+1]; k++ {
+			v += m.val[k] * y[m.rowIdx[k]]
+		}
+		dst[j] = v
+	}
+}
+
+// scaledGram stores A diag(d) Aᵀ in dst.
+func (m *cscMatrix) scaledGram(dst *mat.SymDense, d []float64) {
+	dst.Zero()
+	raw := dst.RawSymmetric()
+	for j, dj := range d {
+		lo, hi := m.colPtr[j], m.colPtr[j+1]
+		for k := lo; k < hi; k++ {
+			// The rows of a column are sorted, so the elements are
+			// accumulated in the upper triangle.
+			row := raw.Data[m.rowIdx[k]*raw.Stride:]
+			v := dj * m.val[k]
+			for l := k; l < hi; l++ {
+				row[m.rowIdx[l]] += v * m.val[l]
+			}
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/lp"
+)
+
+const defaultActiveSetTol = 1e-10
+
+// ActiveSetSettings holds the settings for ActiveSet.
+type ActiveSetSettings struct {
+	// Tol is the tolerance on the feasibility of the initial point, on
+	// the size of a step in the working set and on the sign of the
+	// multipliers. If Tol is zero, a default of 1e-10 is used.
+	Tol float64
+
+	// MaxIterations is the maximum number of iterations. If it is zero,
+	// a default of 100 + 10*(n + number of constraints) is used.
+	MaxIterations int
+}
+
+// ActiveSet solves the quadratic program p with a primal active set
+// method. It is suited to small dense problems.
+//
+// The method keeps a working set of inequality constraints that hold with
+// equality, and at each iteration minimizes the objective on the subspace
+// defined by the working set and the equality constraints. The step stops
+// at the first blocking constraint, which is added to the working set, and
+// constraints with negative multipliers are removed once the minimum on
+// the subspace has been reached. Directions of zero curvature are
+// followed, so Q only needs to be positive semidefinite.
+//
+// If warm is not nil and warm.X is feasible, the method starts from
+// warm.X with the constraints of warm.Active that are active there as the
+// initial working set. This allows the solution of a similar problem to
+// be used to start the method. Otherwise an initial feasible point is
+// computed with lp.Simplex.
+//
+// ActiveSet returns ErrInfeasible if the constraints cannot be satisfied,
+// ErrUnbounded if the objective is unbounded below on the feasible set and
+// ErrNotConvex if Q is found to have negative curvature. If the iteration
+// limit is reached, the final iterate is returned with ErrIterationLimit.
+//
+// References:
+//  - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer
+//    (2006), Section 16.5
+func ActiveSet(p Problem, warm *Result, settings *ActiveSetSettings) (*Result, error) {
+	n, me, mi := p.dims()
+	tol := defaultActiveSetTol
+	maxIter := 100 + 10*(n+me+mi)
+	if settings != nil {
+		if settings.Tol < 0 || settings.MaxIterations < 0 {
+			panic("qp: invalid settings")
+		}
+		if settings.Tol != 0 {
+			tol = settings.Tol
+		}
+		if settings.MaxIterations != 0 {
+			maxIter = settings.MaxIterations
+		}
+	}
+
+	a := rows(p.A, me)
+	g := rows(p.G, mi)
+
+	var (
+		x       []float64
+		working []int
+	)
+	if warm != nil && len(warm.X) == n && feasible(p, warm.X, me, mi, tol) {
+		x = make([]float64, n)
+		copy(x, warm.X)
+		for _, i := range warm.Active {
+			if 0 <= i && i < mi && !contains(working, i) && math.Abs(floats.Dot(g[i], x)-p.H[i]) <= tol*(1+math.Abs(p.H[i])) {
+				working = append(working, i)
+			}
+		}
+	} else {
+		var err error
+		x, err = feasiblePoint(p, n)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	q := mat.NewSymDense(n, nil)
+	q.CopySym(p.Q)
+	grad := mat.NewVecDense(n, nil)
+	step := make([]float64, n)
+	for iter := 0; ; iter++ {
+		if iter >= maxIter {
+			return newResult(p, x, make([]float64, me), make([]float64, mi), sortedCopy(working), iter), ErrIterationLimit
+		}
+		grad.MulVec(q, mat.NewVecDense(n, x))
+		floats.Add(grad.RawVector().Data, p.C)
+
+		w := make([][]float64, 0, me+len(working))
+		w = append(w, a...)
+		for _, i := range working {
+			w = append(w, g[i])
+		}
+		ws := newWorkingSpace(w, n)
+
+		unbounded, ok := ws.step(step, q, grad.RawVector().Data, tol)
+		if !ok {
+			return nil, ErrNotConvex
+		}
+		if !unbounded && floats.Norm(step, math.Inf(1)) <= tol*(1+floats.Norm(x, math.Inf(1))) {
+			// x minimizes the objective on the working set. Stop if
+			// the multipliers of the working inequality constraints
+			// are nonnegative, or drop the most negative one.
+			lam := ws.multipliers(grad.RawVector().Data)
+			k := -1
+			minLam := -tol * math.Max(1, floats.Norm(grad.RawVector().Data, math.Inf(1)))
+			for j := me; j < len(lam); j++ {
+				if lam[j] < minLam {
+					minLam = lam[j]
+					k = j - me
+				}
+			}
+			if k < 0 {
+				y := make([]float64, me)
+				copy(y, lam[:me])
+				z := make([]float64, mi)
+				for j, i := range working {
+					z[i] = math.Max(0, lam[me+j])
+				}
+				return newResult(p, x, y, z, sortedCopy(working), iter), nil
+			}
+			working = append(working[:k], working[k+1:]...)
+			continue
+		}
+
+		// Take the longest step up to the first blocking constraint.
+		alpha := 1.0
+		if unbounded {
+			alpha = math.Inf(1)
+		}
+		block := -1
+		stepNorm := floats.Norm(step, 2)
+		for i, gi := range g {
+			if contains(working, i) {
+				continue
+			}
+			gp := floats.Dot(gi, step)
+			if gp <= 1e-14*floats.Norm(gi, 2)*stepNorm {
+				continue
+			}
+			t := math.Max(0, (p.H[i]-floats.Dot(gi, x))/gp)
+			if t < alpha {
+				alpha = t
+				block = i
+			}
+		}
+		if math.IsInf(alpha, 1) {
+			return nil, ErrUnbounded
+		}
+		floats.AddScaled(x, alpha, step)
+		if block >= 0 {
+			working = append(working, block)
+		}
+	}
+}
+
+// workingSpace holds the singular value decomposition of the matrix of
+// working constraint normals.
+type workingSpace struct {
+	n, q, rank int
+	s          []float64
+	u, v       mat.Dense
+}
+
+func newWorkingSpace(w [][]float64, n int) *workingSpace {
+	ws := &workingSpace{n: n, q: len(w)}
+	if ws.q == 0 {
+		return ws
+	}
+	m := mat.NewDense(ws.q, n, nil)
+	for i, r := range w {
+		m.SetRow(i, r)
+	}
+	var svd mat.SVD
+	if ok := svd.Factorize(m, mat.SVDFull); !ok {
+		panic("qp: SVD failed")
+	}
+	ws.s = svd.Values(nil)
+	svd.UTo(&ws.u)
+	svd.VTo(&ws.v)
+	rankTol := float64(max(ws.q, n)) * 0x1p-52 * ws.s[0]
+	for _, v := range ws.s {
+		if v > rankTol {
+			ws.rank++
+		}
+	}
+	return ws
+}
+
+// nullSpace returns a basis for the null space of the working constraint
+// normals as the columns of a matrix, or nil if the null space is
+// trivial.
+func (ws *workingSpace) nullSpace() mat.Matrix {
+	switch {
+	case ws.q == 0:
+		eye := mat.NewDiagDense(ws.n, nil)
+		for i := 0; i < ws.n; i++ {
+			eye.SetDiag(i, 1)
+		}
+		return eye
+	case ws.rank == ws.n:
+		return nil
+	}
+	return ws.v.Slice(0, ws.n, ws.rank, ws.n)
+}
+
+// step stores in dst the step from the current point to the minimum of the
+// objective with Hessian q and gradient grad on the null space of the
+// working constraints. If the objective is not bounded below on the null
+// space, a descent direction of zero curvature is stored and unbounded is
+// true. ok is false if q has negative curvature on the null space.
+func (ws *workingSpace) step(dst []float64, q mat.Symmetric, grad []float64, tol float64) (unbounded, ok bool) {
+	for i := range dst {
+		dst[i] = 0
+	}
+	z := ws.nullSpace()
+	if z == nil {
+		return false, true
+	}
+	_, k := z.Dims()
+
+	// Eigendecompose the reduced Hessian Zᵀ Q Z.
+	var qz, h mat.Dense
+	qz.Mul(q, z)
+	h.Mul(z.T(), &qz)
+	hs := mat.NewSymDense(k, nil)
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			hs.SetSym(i, j, 0.5*(h.At(i, j)+h.At(j, i)))
+		}
+	}
+	var eig mat.EigenSym
+	if ok := eig.Factorize(hs, true); !ok {
+		panic("qp: eigendecomposition failed")
+	}
+	vals := eig.Values(nil)
+	var vecs mat.Dense
+	eig.VectorsTo(&vecs)
+	scale := math.Max(1, math.Max(math.Abs(vals[0]), math.Abs(vals[k-1])))
+	if vals[0] < -1e-8*scale {
+		return false, false
+	}
+
+	var r mat.VecDense
+	r.MulVec(z.T(), mat.NewVecDense(len(grad), grad))
+	pz := mat.NewVecDense(k, nil)
+	rn := mat.NewVecDense(k, nil)
+	for j, val := range vals {
+		u := vecs.ColView(j)
+		c := mat.Dot(u, &r)
+		if val > 1e-10*scale {
+			pz.AddScaledVec(pz, -c/val, u)
+		} else {
+			rn.AddScaledVec(rn, -c, u)
+		}
+	}
+	d := mat.NewVecDense(len(dst), dst)
+	if mat.Norm(rn, math.Inf(1)) > tol*(1+floats.Norm(grad, math.Inf(1))) {
+		d.MulVec(z, rn)
+		return true, true
+	}
+	d.MulVec(z, pz)
+	return false, true
+}
+
+// multipliers returns the least squares solution λ of Wᵀ λ = -grad, where
+// the rows of W are the working constraint normals.
+func (ws *workingSpace) multipliers(grad []float64) []float64 {
+	lam := make([]float64, ws.q)
+	if ws.q == 0 {
+		return lam
+	}
+	var vg mat.VecDense
+	vg.MulVec(ws.v.T(), mat.NewVecDense(ws.n, grad))
+	l := mat.NewVecDense(ws.q, lam)
+	for j := 0; j < ws.rank; j++ {
+		l.AddScaledVec(l, -vg.AtVec(j)/ws.s[j], ws.u.ColView(j))
+	}
+	return lam
+}
+
+// feasiblePoint returns a point satisfying the constraints of p, found by
+// solving a linear program with zero objective.
+func feasiblePoint(p Problem, n int) ([]float64, error) {
+	x := make([]float64, n)
+	if len(p.B) == 0 && len(p.H) == 0 {
+		return x, nil
+	}
+	_, a, b := lp.Convert(make([]float64, n), p.G, p.H, p.A, p.B)
+
+	// Variables that do not appear in the constraints are left at zero,
+	// since lp.Simplex does not allow zero columns.
+	r, c := a.Dims()
+	var cols []int
+	for j := 0; j < c; j++ {
+		for i := 0; i < r; i++ {
+			if a.At(i, j) != 0 {
+				cols = append(cols, j)
+				break
+			}
+		}
+	}
+	sub := mat.NewDense(r, len(cols), nil)
+	for k, j := range cols {
+		sub.SetCol(k, mat.Col(nil, j, a))
+	}
+	_, xt, err := lp.Simplex(make([]float64, len(cols)), sub, b, 0, nil)
+	if err != nil {
+		if err == lp.ErrInfeasible {
+			return nil, ErrInfeasible
+		}
+		return nil, err
+	}
+	for k, j := range cols {
+		switch {
+		case j < n:
+			x[j] += xt[k]
+		case j < 2*n:
+			x[j-n] -= xt[k]
+		}
+	}
+	return x, nil
+}
+
+// rows returns the rows of the r×n matrix m, which may be nil if r is zero.
+func rows(m mat.Matrix, r int) [][]float64 {
+	out := make([][]float64, r)
+	for i := range out {
+		out[i] = mat.Row(nil, i, m)
+	}
+	return out
+}
+
+func contains(s []int, v int) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedCopy(s []int) []int {
+	c := make([]int, len(s))
+	copy(c, s)
+	sort.Ints(c)
+	return c
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,439 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultADMMTol           = 1e-6
+	defaultADMMInfeasibleTol = 1e-5
+	defaultADMMRho           = 0.1
+	defaultADMMSigma         = 1e-6
+	defaultADMMAlpha         = 1.6
+	defaultADMMIterations    = 10000
+
+	// admmEqualityScale is the factor by which the penalty parameter
+	// of the equality constraints exceeds that of the inequalities.
+	admmEqualityScale = 1e3
+	// admmRhoInterval is the number of iterations between updates of
+	// the penalty parameter, and admmRhoRatio the relative change that
+	// triggers a new factorization.
+	admmRhoInterval = 25
+	admmRhoRatio    = 5
+	admmMinRho      = 1e-6
+	admmMaxRho      = 1e6
+)
+
+// ADMMSettings holds the settings for ADMM.
+type ADMMSettings struct {
+	// AbsTol and RelTol are the absolute and relative tolerances on the
+	// primal and dual residuals. If they are zero, a default of 1e-6 is
+	// used.
+	AbsTol float64
+	RelTol float64
+
+	// InfeasibleTol is the tolerance for the certificates of primal and
+	// dual infeasibility. If InfeasibleTol is zero, a default of 1e-5 is
+	// used.
+	InfeasibleTol float64
+
+	// Rho is the initial penalty parameter, which is adapted during the
+	// iterations. If Rho is zero, a default of 0.1 is used.
+	Rho float64
+
+	// Sigma is the regularization of the linear systems in x. If Sigma is
+	// zero, a default of 1e-6 is used.
+	Sigma float64
+
+	// Alpha is the relaxation parameter in (0, 2). If Alpha is zero, a
+	// default of 1.6 is used.
+	Alpha float64
+
+	// MaxIterations is the maximum number of iterations. If it is zero,
+	// a default of 10000 is used.
+	MaxIterations int
+}
+
+// ADMM solves the quadratic program p with the alternating direction
+// method of multipliers in the operator splitting form of OSQP.
+//
+// The constraints are written as l ≤ C x ≤ u with C = [A; G], and each
+// iteration solves one linear system with the matrix
+//  Q + σ I + Cᵀ diag(ρ) C,
+// whose Cholesky factorization is reused until the penalty parameter ρ
+// is adapted to balance the primal and dual residuals. The solution is
+// accurate to the given tolerances rather than to working precision.
+//
+// If warm is not nil, the iterations start from warm.X and the multipliers
+// warm.Y and warm.Z, which allows the solution of a similar problem to be
+// used to start the method.
+//
+// ADMM returns ErrInfeasible or ErrUnbounded if the differences between
+// successive iterates give a certificate of primal or dual infeasibility.
+// It returns ErrNotConvex if the linear system matrix is not positive
+// definite, which can only happen if Q is not positive semidefinite. If
+// the iteration limit is reached, the final iterate is returned with
+// ErrIterationLimit.
+//
+// References:
+//  - Stellato, B., Banjac, G., Goulart, P., Bemporad, A., Boyd, S.: OSQP:
+//    an operator splitting solver for quadratic programs. Math. Program.
+//    Comput. 12, 637-672 (2020)
+//  - Banjac, G., Goulart, P., Stellato, B., Boyd, S.: Infeasibility
+//    detection in the alternating direction method of multipliers for
+//    convex optimization. J. Optim. Theory Appl. 183, 490-519 (2019)
+func ADMM(p Problem, warm *Result, settings *ADMMSettings) (*Result, error) {
+	n, me, mi := p.dims()
+	s := ADMMSettings{}
+	if settings != nil {
+		s = *settings
+	}
+	if s.AbsTol < 0 || s.RelTol < 0 || s.InfeasibleTol < 0 || s.Rho < 0 || s.Sigma < 0 || s.Alpha < 0 || 2 <= s.Alpha || s.MaxIterations < 0 {
+		panic("qp: invalid settings")
+	}
+	if s.AbsTol == 0 {
+		s.AbsTol = defaultADMMTol
+	}
+	if s.RelTol == 0 {
+		s.RelTol = defaultADMMTol
+	}
+	if s.InfeasibleTol == 0 {
+		s.InfeasibleTol = defaultADMMInfeasibleTol
+	}
+	if s.Rho == 0 {
+		s.Rho = defaultADMMRho
+	}
+	if s.Sigma == 0 {
+		s.Sigma = defaultADMMSigma
+	}
+	if s.Alpha == 0 {
+		s.Alpha = defaultADMMAlpha
+	}
+	if s.MaxIterations == 0 {
+		s.MaxIterations = defaultADMMIterations
+	}
+
+	// Form C = [A; G] and the bounds l ≤ C x ≤ u.
+	m := me + mi
+	c := mat.NewDense(max(m, 1), n, nil)
+	if me > 0 {
+		c.Slice(0, me, 0, n).(*mat.Dense).Copy(p.A)
+	}
+	if mi > 0 {
+		c.Slice(me, m, 0, n).(*mat.Dense).Copy(p.G)
+	}
+	if m == 0 {
+		c = nil
+	}
+	l := make([]float64, m)
+	u := make([]float64, m)
+	copy(l, p.B)
+	copy(u, p.B)
+	for i, h := range p.H {
+		l[me+i] = math.Inf(-1)
+		u[me+i] = h
+	}
+
+	ad := &admm{
+		p:     p,
+		s:     s,
+		n:     n,
+		m:     m,
+		c:     c,
+		l:     l,
+		u:     u,
+		rho:   s.Rho,
+		rhoV:  make([]float64, m),
+		x:     make([]float64, n),
+		z:     make([]float64, m),
+		y:     make([]float64, m),
+		cx:    make([]float64, m),
+		qx:    make([]float64, n),
+		cty:   make([]float64, n),
+		xt:    make([]float64, n),
+		zt:    make([]float64, m),
+		rhs:   make([]float64, n),
+		dx:    make([]float64, n),
+		dy:    make([]float64, m),
+		tmpN:  make([]float64, n),
+		tmpM:  make([]float64, m),
+		chol:  &mat.Cholesky{},
+		q:     mat.NewSymDense(n, nil),
+		sigma: s.Sigma,
+	}
+	ad.q.CopySym(p.Q)
+	if warm != nil {
+		if len(warm.X) == n {
+			copy(ad.x, warm.X)
+		}
+		if len(warm.Y) == me {
+			copy(ad.y, warm.Y)
+		}
+		if len(warm.Z) == mi {
+			copy(ad.y[me:], warm.Z)
+		}
+	}
+	ad.mulC(ad.z, ad.x)
+	ad.project(ad.z)
+	if !ad.factorize() {
+		return nil, ErrNotConvex
+	}
+
+	for iter := 1; ; iter++ {
+		ad.iterate()
+		if ad.converged() {
+			return ad.result(iter), nil
+		}
+		if ad.primalInfeasible() {
+			return nil, ErrInfeasible
+		}
+		if ad.dualInfeasible() {
+			return nil, ErrUnbounded
+		}
+		if iter >= s.MaxIterations {
+			return ad.result(iter), ErrIterationLimit
+		}
+		if iter%admmRhoInterval == 0 && !ad.updateRho() {
+			return nil, ErrNotConvex
+		}
+	}
+}
+
+// admm holds the state of the ADMM iterations.
+type admm struct {
+	p    Problem
+	s    ADMMSettings
+	n, m int
+	c    *mat.Dense
+	l, u []float64
+
+	rho   float64
+	rhoV  []float64
+	sigma float64
+	q     *mat.SymDense
+	chol  *mat.Cholesky
+
+	x, z, y []float64
+	cx      []float64 // C x
+	qx      []float64 // Q x
+	cty     []float64 // Cᵀ y
+	xt, zt  []float64
+	rhs     []float64
+	dx, dy  []float64
+	tmpN    []float64
+	tmpM    []float64
+
+	primRes, dualRes     float64
+	primScale, dualScale float64
+}
+
+// mulC stores C x in dst.
+func (ad *admm) mulC(dst, x []float64) {
+	if ad.m == 0 {
+		return
+	}
+	mat.NewVecDense(ad.m, dst).MulVec(ad.c, mat.NewVecDense(ad.n, x))
+}
+
+// mulCT stores Cᵀ y in dst.
+func (ad *admm) mulCT(dst, y []float64) {
+	if ad.m == 0 {
+		for i := range dst {
+			dst[i] = 0
+		}
+		return
+	}
+	mat.NewVecDense(ad.n, dst).MulVec(ad.c.T(), mat.NewVecDense(ad.m, y))
+}
+
+// project projects v onto the box [l, u].
+func (ad *admm) project(v []float64) {
+	for i := range v {
+		v[i] = math.Max(ad.l[i], math.Min(v[i], ad.u[i]))
+	}
+}
+
+// factorize sets the penalty parameters of the constraints and factorizes
+// Q + σ I + Cᵀ diag(ρ) C, returning whether the matrix is positive
+// definite.
+func (ad *admm) factorize() bool {
+	for i := range ad.rhoV {
+		switch {
+		case ad.l[i] == ad.u[i]:
+			ad.rhoV[i] = admmEqualityScale * ad.rho
+		case math.IsInf(ad.l[i], -1) && math.IsInf(ad.u[i], 1):
+			ad.rhoV[i] = admmMinRho
+		default:
+			ad.rhoV[i] = ad.rho
+		}
+	}
+	k := mat.NewSymDense(ad.n, nil)
+	k.CopySym(ad.q)
+	for i := 0; i < ad.n; i++ {
+		k.SetSym(i, i, k.At(i, i)+ad.sigma)
+	}
+	for i := 0; i < ad.m; i++ {
+		k.SymRankOne(k, ad.rhoV[i], ad.c.RowView(i))
+	}
+	return ad.chol.Factorize(k)
+}
+
+// iterate performs one ADMM iteration.
+func (ad *admm) iterate() {
+	alpha := ad.s.Alpha
+
+	// Solve (Q + σ I + Cᵀ diag(ρ) C) x̃ = σ x - c + Cᵀ (ρ z - y).
+	for i := range ad.tmpM {
+		ad.tmpM[i] = ad.rhoV[i]*ad.z[i] - ad.y[i]
+	}
+	ad.mulCT(ad.rhs, ad.tmpM)
+	for i := range ad.rhs {
+		ad.rhs[i] += ad.sigma*ad.x[i] - ad.p.C[i]
+	}
+	xt := mat.NewVecDense(ad.n, ad.xt)
+	if err := ad.chol.SolveVecTo(xt, mat.NewVecDense(ad.n, ad.rhs)); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			panic(err)
+		}
+	}
+	ad.mulC(ad.zt, ad.xt)
+
+	// Relaxed updates of x, z and y.
+	for i := range ad.x {
+		xNew := alpha*ad.xt[i] + (1-alpha)*ad.x[i]
+		ad.dx[i] = xNew - ad.x[i]
+		ad.x[i] = xNew
+	}
+	for i := range ad.z {
+		zr := alpha*ad.zt[i] + (1-alpha)*ad.z[i]
+		zNew := math.Max(ad.l[i], math.Min(zr+ad.y[i]/ad.rhoV[i], ad.u[i]))
+		ad.dy[i] = ad.rhoV[i] * (zr - zNew)
+		ad.y[i] += ad.dy[i]
+		ad.z[i] = zNew
+	}
+}
+
+// converged computes the residuals at the current iterate and returns
+// whether they are within the tolerances.
+func (ad *admm) converged() bool {
+	ad.mulC(ad.cx, ad.x)
+	mat.NewVecDense(ad.n, ad.qx).MulVec(ad.q, mat.NewVecDense(ad.n, ad.x))
+	ad.mulCT(ad.cty, ad.y)
+
+	ad.primRes = 0
+	for i, v := range ad.cx {
+		ad.primRes = math.Max(ad.primRes, math.Abs(v-ad.z[i]))
+	}
+	ad.dualRes = 0
+	for i, v := range ad.qx {
+		ad.dualRes = math.Max(ad.dualRes, math.Abs(v+ad.p.C[i]+ad.cty[i]))
+	}
+	inf := math.Inf(1)
+	ad.primScale = math.Max(normInf(ad.cx), normInf(ad.z))
+	ad.dualScale = math.Max(math.Max(floats.Norm(ad.qx, inf), floats.Norm(ad.cty, inf)), floats.Norm(ad.p.C, inf))
+	return ad.primRes <= ad.s.AbsTol+ad.s.RelTol*ad.primScale &&
+		ad.dualRes <= ad.s.AbsTol+ad.s.RelTol*ad.dualScale
+}
+
+// primalInfeasible returns whether the last change in y is a certificate
+// of primal infeasibility, that is whether
+//  Cᵀ δy ≈ 0,  uᵀ max(δy, 0) + lᵀ min(δy, 0) < 0.
+func (ad *admm) primalInfeasible() bool {
+	norm := normInf(ad.dy)
+	if norm == 0 {
+		return false
+	}
+	eps := ad.s.InfeasibleTol * norm
+	var support float64
+	for i, d := range ad.dy {
+		switch {
+		case d > eps:
+			support += ad.u[i] * d
+		case d < -eps:
+			support += ad.l[i] * d
+		}
+	}
+	if math.IsNaN(support) || math.IsInf(support, 1) || support >= -eps {
+		return false
+	}
+	ad.mulCT(ad.tmpN, ad.dy)
+	return normInf(ad.tmpN) <= eps
+}
+
+// dualInfeasible returns whether the last change in x is a certificate of
+// dual infeasibility, that is whether
+//  Q δx ≈ 0,  cᵀ δx < 0,  C δx ∈ recession cone of [l, u].
+func (ad *admm) dualInfeasible() bool {
+	norm := normInf(ad.dx)
+	if norm == 0 {
+		return false
+	}
+	eps := ad.s.InfeasibleTol * norm
+	if floats.Dot(ad.p.C, ad.dx) >= -eps {
+		return false
+	}
+	mat.NewVecDense(ad.n, ad.tmpN).MulVec(ad.q, mat.NewVecDense(ad.n, ad.dx))
+	if normInf(ad.tmpN) > eps {
+		return false
+	}
+	ad.mulC(ad.tmpM, ad.dx)
+	for i, v := range ad.tmpM {
+		if (!math.IsInf(ad.u[i], 1) && v > eps) || (!math.IsInf(ad.l[i], -1) && v < -eps) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateRho adapts the penalty parameter to balance the relative primal
+// and dual residuals, refactorizing if it has changed significantly. It
+// returns false if the new factorization fails.
+func (ad *admm) updateRho() bool {
+	if ad.m == 0 {
+		return true
+	}
+	prim := ad.primRes / math.Max(ad.primScale, 1e-12)
+	dual := ad.dualRes / math.Max(ad.dualScale, 1e-12)
+	if prim == 0 || dual == 0 {
+		return true
+	}
+	rho := ad.rho * math.Sqrt(prim/dual)
+	rho = math.Max(admmMinRho, math.Min(rho, admmMaxRho))
+	if rho > admmRhoRatio*ad.rho || rho < ad.rho/admmRhoRatio {
+		ad.rho = rho
+		return ad.factorize()
+	}
+	return true
+}
+
+// result returns the Result at the current iterate.
+func (ad *admm) result(iter int) *Result {
+	me := len(ad.p.B)
+	y := make([]float64, me)
+	copy(y, ad.y[:me])
+	z := make([]float64, ad.m-me)
+	var active []int
+	tol := ad.s.AbsTol + ad.s.RelTol*ad.primScale
+	for i := range z {
+		z[i] = math.Max(0, ad.y[me+i])
+		if ad.u[me+i]-ad.cx[me+i] <= tol {
+			active = append(active, i)
+		}
+	}
+	return newResult(ad.p, ad.x, y, z, active, iter)
+}
+
+// normInf returns the infinity norm of v, or zero if v is empty.
+func normInf(v []float64) float64 {
+	if len(v) == 0 {
+		return 0
+	}
+	return floats.Norm(v, math.Inf(1))
+}
@@ -0,0 +1,129 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	ErrInfeasible     = errors.New("qp: problem is infeasible")
+	ErrUnbounded      = errors.New("qp: problem is unbounded")
+	ErrNotConvex      = errors.New("qp: Q is not positive semidefinite")
+	ErrIterationLimit = errors.New("qp: iteration limit reached")
+)
+
+const badShape = "qp: size mismatch"
+
+// Problem is a convex quadratic program
+//  minimize ½ xᵀ Q x + cᵀ x
+//  s.t.     A x = b
+//           G x ≤ h,
+// where Q is positive semidefinite. If there are no constraints of a given
+// type, the corresponding matrix and vector may be nil.
+type Problem struct {
+	Q mat.Symmetric
+	C []float64
+
+	A mat.Matrix
+	B []float64
+
+	G mat.Matrix
+	H []float64
+}
+
+// dims returns the number of variables and of equality and inequality
+// constraints of p, panicking if the sizes are inconsistent.
+func (p Problem) dims() (n, me, mi int) {
+	n = len(p.C)
+	if p.Q == nil || p.Q.Symmetric() != n {
+		panic(badShape)
+	}
+	me = len(p.B)
+	if p.A == nil {
+		if me != 0 {
+			panic(badShape)
+		}
+	} else if r, c := p.A.Dims(); r != me || c != n {
+		panic(badShape)
+	}
+	mi = len(p.H)
+	if p.G == nil {
+		if mi != 0 {
+			panic(badShape)
+		}
+	} else if r, c := p.G.Dims(); r != mi || c != n {
+		panic(badShape)
+	}
+	return n, me, mi
+}
+
+// objective returns the value of the objective function of p at x.
+func (p Problem) objective(x []float64) float64 {
+	xv := mat.NewVecDense(len(x), x)
+	return 0.5*mat.Inner(xv, p.Q, xv) + floats.Dot(p.C, x)
+}
+
+// Result is the solution of a quadratic program.
+type Result struct {
+	// X is the minimizer and F the minimum value of the objective.
+	X []float64
+	F float64
+
+	// Y and Z are the multipliers of the equality and the inequality
+	// constraints, which satisfy
+	//  Q x + c + Aᵀ y + Gᵀ z = 0,  z ≥ 0
+	// at the solution.
+	Y []float64
+	Z []float64
+
+	// Active holds the indices of the inequality constraints that are
+	// active at X, in increasing order.
+	Active []int
+
+	// Iterations is the number of iterations of the solver.
+	Iterations int
+}
+
+// newResult returns the Result for p at the final iterate.
+func newResult(p Problem, x, y, z []float64, active []int, iter int) *Result {
+	return &Result{
+		X:          x,
+		F:          p.objective(x),
+		Y:          y,
+		Z:          z,
+		Active:     active,
+		Iterations: iter,
+	}
+}
+
+// feasible returns whether x satisfies the constraints of p to within
+// tol, scaled by the magnitude of the right-hand sides.
+func feasible(p Problem, x []float64, me, mi int, tol float64) bool {
+	xv := mat.NewVecDense(len(x), x)
+	if me > 0 {
+		var r mat.VecDense
+		r.MulVec(p.A, xv)
+		for i, b := range p.B {
+			if math.Abs(r.AtVec(i)-b) > tol*(1+math.Abs(b)) {
+				return false
+			}
+		}
+	}
+	if mi > 0 {
+		var r mat.VecDense
+		r.MulVec(p.G, xv)
+		for i, h := range p.H {
+			if r.AtVec(i)-h > tol*(1+math.Abs(h)) {
+				return false
+			}
+		}
+	}
+	return true
+}
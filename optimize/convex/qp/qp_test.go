@@ -0,0 +1,399 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var qpTests = []struct {
+	name   string
+	p      Problem
+	want   []float64
+	wantF  float64
+	wantY  []float64
+	wantZ  []float64
+	active []int
+}{
+	{
+		name: "Unconstrained",
+		p: Problem{
+			Q: mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			C: []float64{-4, -2},
+		},
+		want:  []float64{2, 1},
+		wantF: -5,
+		wantY: []float64{},
+		wantZ: []float64{},
+	},
+	{
+		name: "Equality",
+		p: Problem{
+			Q: mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			C: []float64{-4, -2},
+			A: mat.NewDense(1, 2, []float64{1, 1}),
+			B: []float64{1},
+		},
+		want:  []float64{1, 0},
+		wantF: -3,
+		wantY: []float64{2},
+		wantZ: []float64{},
+	},
+	{
+		name: "Inequality",
+		p: Problem{
+			Q: mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			C: []float64{-4, -2},
+			G: mat.NewDense(3, 2, []float64{1, 0, 0, 1, -1, -1}),
+			H: []float64{1, 0.5, 10},
+		},
+		want:   []float64{1, 0.5},
+		wantF:  -3.75,
+		wantY:  []float64{},
+		wantZ:  []float64{2, 1, 0},
+		active: []int{0, 1},
+	},
+	{
+		name: "Mixed",
+		p: Problem{
+			Q: mat.NewSymDense(2, []float64{2, 0, 0, 2}),
+			C: []float64{-4, -2},
+			A: mat.NewDense(1, 2, []float64{1, 1}),
+			B: []float64{1},
+			G: mat.NewDense(1, 2, []float64{0, -1}),
+			H: []float64{-0.5},
+		},
+		want:   []float64{0.5, 0.5},
+		wantF:  -2.5,
+		wantY:  []float64{3},
+		wantZ:  []float64{2},
+		active: []int{0},
+	},
+	{
+		name: "Semidefinite",
+		p: Problem{
+			Q: mat.NewSymDense(2, []float64{1, 0, 0, 0}),
+			C: []float64{0, -1},
+			G: mat.NewDense(2, 2, []float64{0, 1, 1, 1}),
+			H: []float64{2, 3},
+		},
+		want:   []float64{0, 2},
+		wantF:  -2,
+		wantY:  []float64{},
+		wantZ:  []float64{1, 0},
+		active: []int{0},
+	},
+}
+
+// solver is the common signature of ActiveSet and ADMM with default
+// settings.
+type solver func(p Problem, warm *Result) (*Result, error)
+
+var solvers = []struct {
+	name  string
+	solve solver
+	tol   float64
+}{
+	{
+		name: "ActiveSet",
+		solve: func(p Problem, warm *Result) (*Result, error) {
+			return ActiveSet(p, warm, nil)
+		},
+		tol: 1e-10,
+	},
+	{
+		name: "ADMM",
+		solve: func(p Problem, warm *Result) (*Result, error) {
+			return ADMM(p, warm, &ADMMSettings{AbsTol: 1e-9, RelTol: 1e-9})
+		},
+		tol: 1e-6,
+	},
+}
+
+func TestSolve(t *testing.T) {
+	t.Parallel()
+	for _, s := range solvers {
+		for _, test := range qpTests {
+			result, err := s.solve(test.p, nil)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", s.name, test.name, err)
+				continue
+			}
+			if !floats.EqualApprox(result.X, test.want, s.tol) {
+				t.Errorf("%s %s: unexpected solution: got %v, want %v", s.name, test.name, result.X, test.want)
+			}
+			if math.Abs(result.F-test.wantF) > s.tol {
+				t.Errorf("%s %s: unexpected minimum: got %v, want %v", s.name, test.name, result.F, test.wantF)
+			}
+			if !floats.EqualApprox(result.Y, test.wantY, s.tol) {
+				t.Errorf("%s %s: unexpected equality multipliers: got %v, want %v", s.name, test.name, result.Y, test.wantY)
+			}
+			if !floats.EqualApprox(result.Z, test.wantZ, s.tol) {
+				t.Errorf("%s %s: unexpected inequality multipliers: got %v, want %v", s.name, test.name, result.Z, test.wantZ)
+			}
+			if !intsEqual(result.Active, test.active) {
+				t.Errorf("%s %s: unexpected active set: got %v, want %v", s.name, test.name, result.Active, test.active)
+			}
+		}
+	}
+}
+
+func TestSolveRandom(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		n := 2 + rnd.Intn(6)
+		me := rnd.Intn(n)
+		mi := rnd.Intn(2 * n)
+		p := randomProblem(rnd, n, me, mi)
+		var results [2]*Result
+		for k, s := range solvers {
+			result, err := s.solve(p, nil)
+			if err != nil {
+				t.Errorf("trial %d %s: unexpected error: %v", trial, s.name, err)
+				continue
+			}
+			checkKKT(t, p, result, 1e3*s.tol)
+			results[k] = result
+		}
+		if results[0] != nil && results[1] != nil && math.Abs(results[0].F-results[1].F) > 1e-5*math.Max(1, math.Abs(results[0].F)) {
+			t.Errorf("trial %d: minimum mismatch: ActiveSet %v, ADMM %v", trial, results[0].F, results[1].F)
+		}
+	}
+}
+
+func TestSolveWarmStart(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(2))
+	p := randomProblem(rnd, 8, 2, 12)
+	for _, s := range solvers {
+		cold, err := s.solve(p, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+		// Resolving from the solution must be immediate.
+		warm, err := s.solve(p, cold)
+		if err != nil {
+			t.Errorf("%s: unexpected error with warm start: %v", s.name, err)
+			continue
+		}
+		if warm.Iterations >= cold.Iterations {
+			t.Errorf("%s: warm start did not reduce iterations: cold %d, warm %d", s.name, cold.Iterations, warm.Iterations)
+		}
+		if !floats.EqualApprox(warm.X, cold.X, 1e2*s.tol) {
+			t.Errorf("%s: warm start solution mismatch: got %v, want %v", s.name, warm.X, cold.X)
+		}
+
+		// A perturbed problem is solved from the previous solution.
+		pp := p
+		pp.C = make([]float64, len(p.C))
+		for i, v := range p.C {
+			pp.C[i] = v + 0.01*rnd.NormFloat64()
+		}
+		want, err := s.solve(pp, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", s.name, err)
+		}
+		got, err := s.solve(pp, cold)
+		if err != nil {
+			t.Errorf("%s: unexpected error with warm start: %v", s.name, err)
+			continue
+		}
+		if math.Abs(got.F-want.F) > 1e2*s.tol*math.Max(1, math.Abs(want.F)) {
+			t.Errorf("%s: warm started minimum mismatch: got %v, want %v", s.name, got.F, want.F)
+		}
+	}
+}
+
+func TestSolveErrors(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		p    Problem
+		err  error
+	}{
+		{
+			name: "Infeasible",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{1, 1},
+				G: mat.NewDense(2, 2, []float64{1, 0, -1, 0}),
+				H: []float64{0, -1},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "InfeasibleEquality",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 1}),
+				C: []float64{1, 1},
+				A: mat.NewDense(1, 2, []float64{1, 1}),
+				B: []float64{1},
+				G: mat.NewDense(2, 2, []float64{1, 0, 0, 1}),
+				H: []float64{0.25, 0.25},
+			},
+			err: ErrInfeasible,
+		},
+		{
+			name: "Unbounded",
+			p: Problem{
+				Q: mat.NewSymDense(2, []float64{1, 0, 0, 0}),
+				C: []float64{0, -1},
+				G: mat.NewDense(1, 2, []float64{0, -1}),
+				H: []float64{0},
+			},
+			err: ErrUnbounded,
+		},
+		{
+			name: "NotConvex",
+			p: Problem{
+				Q: mat.NewSymDense(1, []float64{-1}),
+				C: []float64{0.5},
+				G: mat.NewDense(2, 1, []float64{1, -1}),
+				H: []float64{1, 1},
+			},
+			err: ErrNotConvex,
+		},
+	} {
+		for _, s := range solvers {
+			_, err := s.solve(test.p, nil)
+			if err != test.err {
+				t.Errorf("%s %s: unexpected error: got %v, want %v", s.name, test.name, err, test.err)
+			}
+		}
+	}
+}
+
+func TestSolvePanics(t *testing.T) {
+	t.Parallel()
+	q := mat.NewSymDense(2, []float64{1, 0, 0, 1})
+	for _, p := range []Problem{
+		{C: []float64{1, 1}},
+		{Q: q, C: []float64{1}},
+		{Q: q, C: []float64{1, 1}, B: []float64{1}},
+		{Q: q, C: []float64{1, 1}, A: mat.NewDense(1, 3, nil), B: []float64{1}},
+		{Q: q, C: []float64{1, 1}, G: mat.NewDense(2, 2, nil), H: []float64{1}},
+	} {
+		for _, s := range solvers {
+			if !panics(func() { s.solve(p, nil) }) {
+				t.Errorf("%s: expected panic for %+v", s.name, p)
+			}
+		}
+	}
+}
+
+// randomProblem returns a random strictly convex problem with n variables,
+// me equality and mi inequality constraints that is feasible by
+// construction.
+func randomProblem(rnd *rand.Rand, n, me, mi int) Problem {
+	l := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			l.Set(i, j, rnd.NormFloat64())
+		}
+	}
+	q := mat.NewSymDense(n, nil)
+	q.SymOuterK(1, l)
+	for i := 0; i < n; i++ {
+		q.SetSym(i, i, q.At(i, i)+0.1)
+	}
+	c := make([]float64, n)
+	x0 := make([]float64, n)
+	for i := range c {
+		c[i] = 5 * rnd.NormFloat64()
+		x0[i] = rnd.NormFloat64()
+	}
+	p := Problem{Q: q, C: c}
+	if me > 0 {
+		a := mat.NewDense(me, n, nil)
+		for i := 0; i < me; i++ {
+			for j := 0; j < n; j++ {
+				a.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		p.A = a
+		p.B = mat.Col(nil, 0, mulVec(a, x0))
+	}
+	if mi > 0 {
+		g := mat.NewDense(mi, n, nil)
+		for i := 0; i < mi; i++ {
+			for j := 0; j < n; j++ {
+				g.Set(i, j, rnd.NormFloat64())
+			}
+		}
+		p.G = g
+		p.H = mat.Col(nil, 0, mulVec(g, x0))
+		for i := range p.H {
+			p.H[i] += rnd.Float64()
+		}
+	}
+	return p
+}
+
+func mulVec(a mat.Matrix, x []float64) *mat.VecDense {
+	var v mat.VecDense
+	v.MulVec(a, mat.NewVecDense(len(x), x))
+	return &v
+}
+
+// checkKKT checks the first-order optimality conditions of result for p.
+func checkKKT(t *testing.T, p Problem, result *Result, tol float64) {
+	t.Helper()
+	n, me, mi := p.dims()
+	grad := mulVec(p.Q, result.X)
+	grad.AddVec(grad, mat.NewVecDense(n, p.C))
+	scale := math.Max(1, mat.Norm(grad, math.Inf(1)))
+	if me > 0 {
+		grad.AddVec(grad, mulVec(p.A.T(), result.Y))
+		r := mulVec(p.A, result.X)
+		for i, b := range p.B {
+			if math.Abs(r.AtVec(i)-b) > tol {
+				t.Errorf("equality constraint %d violated by %v", i, r.AtVec(i)-b)
+			}
+		}
+	}
+	if mi > 0 {
+		grad.AddVec(grad, mulVec(p.G.T(), result.Z))
+		r := mulVec(p.G, result.X)
+		for i, h := range p.H {
+			if r.AtVec(i)-h > tol {
+				t.Errorf("inequality constraint %d violated by %v", i, r.AtVec(i)-h)
+			}
+			if result.Z[i] < 0 || math.Abs(result.Z[i]*(r.AtVec(i)-h)) > tol*math.Max(1, result.Z[i]) {
+				t.Errorf("complementarity of inequality constraint %d violated: z=%v, slack=%v", i, result.Z[i], h-r.AtVec(i))
+			}
+		}
+	}
+	if mat.Norm(grad, math.Inf(1)) > tol*scale {
+		t.Errorf("stationarity violated: %v", mat.Norm(grad, math.Inf(1)))
+	}
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i, v := range a {
+		if v != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func panics(fn func()) (panicked bool) {
+	defer func() {
+		r := recover()
+		panicked = r != nil
+	}()
+	fn()
+	return
+}
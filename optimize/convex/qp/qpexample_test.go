@@ -0,0 +1,77 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package qp_test
+
+import (
+	"fmt"
+	"log"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/convex/qp"
+)
+
+func ExampleActiveSet() {
+	// Find the minimum variance portfolio of three assets with
+	// covariance Q and an expected return of at least 0.1, without
+	// short selling.
+	p := qp.Problem{
+		Q: mat.NewSymDense(3, []float64{
+			0.04, 0.006, 0,
+			0.006, 0.09, 0,
+			0, 0, 0.01,
+		}),
+		C: []float64{0, 0, 0},
+		A: mat.NewDense(1, 3, []float64{1, 1, 1}),
+		B: []float64{1},
+		G: mat.NewDense(4, 3, []float64{
+			-0.12, -0.15, -0.05,
+			-1, 0, 0,
+			0, -1, 0,
+			0, 0, -1,
+		}),
+		H: []float64{-0.1, 0, 0, 0},
+	}
+
+	result, err := qp.ActiveSet(p, nil, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x: %.4f\n", result.X)
+	fmt.Printf("variance: %.6f\n", 2*result.F)
+	fmt.Printf("active: %v\n", result.Active)
+	// Output:
+	// x: [0.3916 0.2258 0.3825]
+	// variance: 0.013251
+	// active: [0]
+}
+
+func ExampleADMM() {
+	// Solve the same problem twice with a warm start for the second
+	// solve.
+	p := qp.Problem{
+		Q: mat.NewSymDense(2, []float64{2, 0.5, 0.5, 1}),
+		C: []float64{-1, -1},
+		G: mat.NewDense(2, 2, []float64{1, 1, -1, 0}),
+		H: []float64{0.5, 0},
+	}
+
+	result, err := qp.ADMM(p, nil, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x: %.4f\n", result.X)
+	fmt.Printf("z: %.4f\n", result.Z)
+
+	p.C = []float64{-1, -1.1}
+	result, err = qp.ADMM(p, result, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x: %.4f\n", result.X)
+	// Output:
+	// x: [0.1250 0.3750]
+	// z: [0.5625 0.0000]
+	// x: [0.0750 0.4250]
+}
@@ -0,0 +1,200 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"golang.org/x/exp/rand"
+)
+
+var (
+	_ Method        = (*DifferentialEvolution)(nil)
+	_ BoundedMethod = (*DifferentialEvolution)(nil)
+)
+
+// DifferentialEvolution implements the DE/rand/1/bin differential evolution
+// method for global optimization, described in
+//  Storn, R., Price, K.: Differential evolution – a simple and efficient
+//  heuristic for global optimization over continuous spaces. Journal of
+//  Global Optimization 11, 341–359 (1997)
+// Differential evolution evolves a population of locations. In each
+// iteration, a trial location is created for every member of the
+// population by adding the scaled difference of two random members to a
+// third, and crossing the result over with the member. The member is
+// replaced by the trial location if the function value is not larger.
+//
+// The trial locations of an iteration are evaluated concurrently if
+// Settings.Concurrent is larger than one. The optimization is reproducible
+// for a given Src, independent of the number of concurrent evaluations,
+// unless it is stopped by an evaluation limit.
+//
+// The initial population consists of the initial location and random
+// locations. Coordinates with finite bounds are sampled uniformly within
+// them, and the other coordinates from a normal distribution around the
+// initial location with standard deviation InitStepSize. Trial coordinates
+// outside the bounds are moved halfway between the member and the bound.
+//
+// DifferentialEvolution does not have its own convergence criterion, so
+// Settings.Converger or the evaluation limits should be used to stop it.
+type DifferentialEvolution struct {
+	// Population is the number of members of the population. If Population
+	// is 0, a default value of 10*dim is used. Population must not be
+	// negative or between 1 and 3, or DifferentialEvolution will panic.
+	Population int
+	// Mutation is the factor that scales the difference of two members.
+	// If Mutation is 0, a default value of 0.8 is used. Mutation must not
+	// be negative or larger than 2, or DifferentialEvolution will panic.
+	Mutation float64
+	// Crossover is the probability that a coordinate of the trial location
+	// is taken from the mutated location. If Crossover is 0, a default
+	// value of 0.9 is used. Crossover must not be negative or larger than
+	// 1, or DifferentialEvolution will panic.
+	Crossover float64
+	// InitStepSize is the standard deviation of the unbounded coordinates
+	// of the initial population. If InitStepSize is 0, a default value of
+	// 1 is used. InitStepSize must not be negative, or
+	// DifferentialEvolution will panic.
+	InitStepSize float64
+	// Src is the source of random numbers. If Src is nil, the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	lower, upper []float64
+
+	dim       int
+	pop       int
+	mutation  float64
+	crossover float64
+	step      float64
+	rnd       *rand.Rand
+
+	initialized bool
+	x0          []float64
+	xs          [][]float64 // Population.
+	fs          []float64   // Function values of the population.
+	trials      [][]float64
+}
+
+func (*DifferentialEvolution) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (de *DifferentialEvolution) InitBounds(lower, upper []float64) {
+	de.lower = lower
+	de.upper = upper
+}
+
+func (de *DifferentialEvolution) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	de.dim = dim
+	de.pop = de.Population
+	switch {
+	case de.pop == 0:
+		de.pop = 10 * dim
+	case de.pop < 4:
+		panic("differential evolution: population too small")
+	}
+	de.mutation = de.Mutation
+	switch {
+	case de.mutation == 0:
+		de.mutation = 0.8
+	case de.mutation < 0 || de.mutation > 2:
+		panic("differential evolution: mutation out of range")
+	}
+	de.crossover = de.Crossover
+	switch {
+	case de.crossover == 0:
+		de.crossover = 0.9
+	case de.crossover < 0 || de.crossover > 1:
+		panic("differential evolution: crossover out of range")
+	}
+	de.step = de.InitStepSize
+	switch {
+	case de.step == 0:
+		de.step = 1
+	case de.step < 0:
+		panic("differential evolution: negative initial step size")
+	}
+	de.rnd = newRand(de.Src)
+	de.initialized = false
+	de.x0 = resize(de.x0, dim)
+	de.fs = resize(de.fs, de.pop)
+	return min(tasks, de.pop)
+}
+
+func (de *DifferentialEvolution) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	copy(de.x0, tasks[0].X)
+	runGenerations(de, operation, result, tasks)
+}
+
+func (de *DifferentialEvolution) generation() [][]float64 {
+	if !de.initialized {
+		de.xs = newPopulation(de.xs, de.pop, de.x0, de.lower, de.upper, de.step, de.rnd)
+		return de.xs
+	}
+	if cap(de.trials) < de.pop {
+		de.trials = make([][]float64, de.pop)
+	}
+	de.trials = de.trials[:de.pop]
+	for i, x := range de.xs {
+		// Choose three distinct members other than i.
+		var r [3]int
+		for k := range r {
+		Choose:
+			for {
+				r[k] = de.rnd.Intn(de.pop)
+				if r[k] == i {
+					continue
+				}
+				for _, v := range r[:k] {
+					if r[k] == v {
+						continue Choose
+					}
+				}
+				break
+			}
+		}
+		a, b, c := de.xs[r[0]], de.xs[r[1]], de.xs[r[2]]
+
+		trial := resize(de.trials[i], de.dim)
+		jr := de.rnd.Intn(de.dim)
+		for j := range trial {
+			if j == jr || de.rnd.Float64() < de.crossover {
+				trial[j] = a[j] + de.mutation*(b[j]-c[j])
+			} else {
+				trial[j] = x[j]
+			}
+			if de.lower != nil {
+				switch {
+				case trial[j] < de.lower[j]:
+					trial[j] = (x[j] + de.lower[j]) / 2
+				case trial[j] > de.upper[j]:
+					trial[j] = (x[j] + de.upper[j]) / 2
+				}
+			}
+		}
+		de.trials[i] = trial
+	}
+	return de.trials
+}
+
+func (de *DifferentialEvolution) update(f []float64) bool {
+	if !de.initialized {
+		copy(de.fs, f)
+		de.initialized = true
+		return false
+	}
+	for i, v := range f {
+		if v <= de.fs[i] {
+			de.fs[i] = v
+			de.xs[i], de.trials[i] = de.trials[i], de.xs[i]
+		}
+	}
+	return false
+}
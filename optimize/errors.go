@@ -39,6 +39,21 @@ var (
 	// ErrMissingHess signifies that a Method requires a Hessian function that
 	// is not supplied by Problem.
 	ErrMissingHess = errors.New("optimize: problem does not provide needed Hess function")
+
+	// ErrModelFailure signifies that NEWUOA or BOBYQA could not build their
+	// quadratic model, because the function value at an interpolation point
+	// is not finite or the interpolation points are degenerate.
+	ErrModelFailure = errors.New("optimize: quadratic model failure")
+
+	// ErrTrustRegionFailure signifies that TrustRegion could not find a step
+	// that decreases the model and changes the location, because of rounding
+	// errors or because the trust region has become too small.
+	ErrTrustRegionFailure = errors.New("optimize: no acceptable trust-region step")
+
+	// ErrDivergence signifies that MinimizeStochastic stopped because a
+	// function value is not finite. This usually occurs if the learning
+	// rate is too large.
+	ErrDivergence = errors.New("optimize: stochastic minimization diverged")
 )
 
 // ErrFunc is returned when an initial function value is invalid. The error
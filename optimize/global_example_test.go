@@ -0,0 +1,59 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+// rastrigin is a function with many local minima and its global minimum
+// of 0 at the origin.
+func rastrigin(x []float64) float64 {
+	f := 10 * float64(len(x))
+	for _, v := range x {
+		f += v*v - 10*math.Cos(2*math.Pi*v)
+	}
+	return f
+}
+
+func ExampleDifferentialEvolution() {
+	p := optimize.Problem{
+		Func:  rastrigin,
+		Lower: []float64{-5.12, -5.12},
+		Upper: []float64{5.12, 5.12},
+	}
+
+	// Stop when the function value has not improved by more than 1e-12
+	// in 50 iterations.
+	settings := &optimize.Settings{
+		Converger: &optimize.FunctionConverge{
+			Absolute:   1e-12,
+			Iterations: 50,
+		},
+		Concurrent: 4,
+	}
+	method := &optimize.DifferentialEvolution{
+		Population: 40,
+		Src:        rand.NewSource(1),
+	}
+	x := []float64{3.2, -2.9}
+	result, err := optimize.Minimize(p, x, settings, method)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X: %.4g\n", result.X)
+	fmt.Printf("result.F: %.4g\n", result.F)
+	// Output:
+	// result.Status: FunctionConvergence
+	// result.X: [-7.741e-10 1.423e-09]
+	// result.F: 0
+}
@@ -4,6 +4,8 @@
 
 package optimize
 
+import "gonum.org/v1/gonum/mat"
+
 // A localMethod can optimize an objective function.
 //
 // It uses a reverse-communication interface between the optimization method
@@ -41,7 +43,8 @@ type localMethod interface {
 	initLocal(loc *Location) (Operation, error)
 
 	// Iterate retrieves data from loc, performs one iteration of the method,
-	// updates loc and returns the next operation.
+	// updates loc and returns the next operation. Iterate returns MethodDone
+	// if the method has converged at the last MajorIteration.
 	iterateLocal(loc *Location) (Operation, error)
 
 	needser
@@ -58,6 +61,13 @@ type needser interface {
 	}
 }
 
+// defaultConverger is implemented by methods that replace the default
+// FunctionConverge used when Settings.Converger is nil, typically because
+// they have their own stopping rule.
+type defaultConverger interface {
+	defaultConverger() Converger
+}
+
 // Statuser can report the status and any error. It is intended for methods as
 // an additional error reporting mechanism apart from the errors returned from
 // Init and Iterate.
@@ -115,6 +125,17 @@ type NextDirectioner interface {
 	NextDirection(loc *Location, dir []float64) (step float64)
 }
 
+// TrustRegionSolver approximately solves the trust-region subproblem
+//  minimize m(p) := gradᵀp + ½ pᵀ hess p subject to ‖p‖ ≤ radius
+// of the TrustRegion method, where ‖·‖ is the Euclidean norm.
+type TrustRegionSolver interface {
+	// Solve stores in step an approximate solution of the subproblem for
+	// the gradient grad, the Hessian hess and the trust-region radius. The
+	// step must not be longer than radius, and m(step) must be negative
+	// unless grad is zero. Solve must not modify grad or hess.
+	Solve(step, grad []float64, hess *mat.SymDense, radius float64)
+}
+
 // StepSizer can set the next step size of the optimization given the last Location.
 // Returned step size must be positive.
 type StepSizer interface {
@@ -0,0 +1,275 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultInteriorBarrier   = 0.1
+	defaultInteriorReduction = 0.2
+	interiorBarrierTol       = 10
+	interiorDecrease         = 1e-4
+	interiorMultiplierBound  = 1e10
+)
+
+var errInteriorSingular = errors.New("optimize: singular interior point system")
+
+// InteriorPoint is a primal-dual interior point method for constrained
+// minimization.
+//
+// The inequality constraints are written as c_I(x) - s = 0 with slack
+// variables s > 0, and the barrier problem
+//  minimize f(x) - μ Σ_i log s_i
+//  s.t.     c_E(x) = 0
+//           c_I(x) - s = 0
+// is solved approximately by Newton steps on its primal-dual optimality
+// conditions for a decreasing sequence of barrier parameters μ. The
+// Hessian of the Lagrangian is approximated by a damped BFGS update, the
+// steps keep the slacks and the inequality multipliers positive and the
+// step length is chosen by a backtracking linesearch on the ℓ₁ merit
+// function of the barrier problem.
+//
+// References:
+//  - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer
+//    (2006), Chapter 19
+//  - Wächter, A., Biegler, L.: On the implementation of an interior-point
+//    filter line-search algorithm for large-scale nonlinear programming.
+//    Math. Program. 106, 25-57 (2006)
+type InteriorPoint struct {
+	// InitialBarrier is the initial barrier parameter μ. If
+	// InitialBarrier is zero, a default of 0.1 is used.
+	InitialBarrier float64
+
+	// BarrierReduction is the factor by which the barrier parameter is
+	// decreased once the barrier problem has been solved to sufficient
+	// accuracy. It must be in (0, 1). If BarrierReduction is zero, a
+	// default of 0.2 is used.
+	BarrierReduction float64
+}
+
+func (ip InteriorPoint) solveConstrained(s *conState) {
+	mu := ip.InitialBarrier
+	if mu == 0 {
+		mu = defaultInteriorBarrier
+	}
+	sigma := ip.BarrierReduction
+	if sigma == 0 {
+		sigma = defaultInteriorReduction
+	}
+	if mu < 0 || sigma <= 0 || 1 <= sigma {
+		panic("interiorpoint: invalid barrier parameter")
+	}
+
+	n, me, mi := s.n, s.me, s.mi
+	if s.gradient() {
+		return
+	}
+	b := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		b.SetSym(i, i, 1)
+	}
+
+	// Initialize the slacks away from zero and the multipliers from the
+	// barrier centrality condition s_i z_i = μ.
+	sl := make([]float64, mi)
+	z := make([]float64, mi)
+	for i, c := range s.ci {
+		sl[i] = math.Max(c, 1)
+		z[i] = mu / sl[i]
+	}
+	y := make([]float64, me)
+
+	dim := n + mi + me + mi
+	var (
+		kkt  = mat.NewDense(dim, dim, nil)
+		rhs  = mat.NewVecDense(dim, nil)
+		sol  = mat.NewVecDense(dim, nil)
+		gl   = make([]float64, n)
+		gl0  = make([]float64, n)
+		r    = make([]float64, mi) // c_I - s
+		rt   = make([]float64, mi)
+		xt   = make([]float64, n)
+		st   = make([]float64, mi)
+		cet  = make([]float64, me)
+		cit  = make([]float64, mi)
+		dx   = make([]float64, n)
+		ds   = make([]float64, mi)
+		dy   = make([]float64, me)
+		dz   = make([]float64, mi)
+		nu   float64
+		step = make([]float64, n)
+	)
+	for {
+		if s.converged(y, z) {
+			return
+		}
+
+		// Decrease the barrier parameter while the barrier problem is
+		// solved to sufficient accuracy.
+		floats.SubTo(r, s.ci, sl)
+		s.lagrangianGrad(gl, y, z)
+		for {
+			e := floats.Norm(gl, math.Inf(1))
+			e = math.Max(e, violation(s.ce, nil))
+			e = math.Max(e, maxAbs(r))
+			for i := range sl {
+				e = math.Max(e, math.Abs(sl[i]*z[i]-mu))
+			}
+			if e > interiorBarrierTol*mu || mu <= s.settings.OptimalityTol/10 {
+				break
+			}
+			mu = math.Max(sigma*mu, s.settings.OptimalityTol/10)
+		}
+		if s.iterate() {
+			return
+		}
+
+		// Assemble and solve the primal-dual system for the step in
+		// (x, s, y, z),
+		//  [ B    0   -J_Eᵀ -J_Iᵀ ] [dx]   [ -∇L     ]
+		//  [ 0    Σ    0     I    ] [ds] = [ μ/s - z ]
+		//  [ J_E  0    0     0    ] [dy]   [ -c_E    ]
+		//  [ J_I -I    0     0    ] [dz]   [ s - c_I ]
+		// with Σ = diag(z/s).
+		kkt.Zero()
+		kkt.Slice(0, n, 0, n).(*mat.Dense).Copy(b)
+		for i := 0; i < me; i++ {
+			for j := 0; j < n; j++ {
+				v := s.je.At(i, j)
+				kkt.Set(j, n+mi+i, -v)
+				kkt.Set(n+mi+i, j, v)
+			}
+		}
+		for i := 0; i < mi; i++ {
+			for j := 0; j < n; j++ {
+				v := s.ji.At(i, j)
+				kkt.Set(j, n+mi+me+i, -v)
+				kkt.Set(n+mi+me+i, j, v)
+			}
+			kkt.Set(n+i, n+i, z[i]/sl[i])
+			kkt.Set(n+i, n+mi+me+i, 1)
+			kkt.Set(n+mi+me+i, n+i, -1)
+		}
+		for j := 0; j < n; j++ {
+			rhs.SetVec(j, -gl[j])
+		}
+		for i := 0; i < mi; i++ {
+			rhs.SetVec(n+i, mu/sl[i]-z[i])
+			rhs.SetVec(n+mi+me+i, -r[i])
+		}
+		for i := 0; i < me; i++ {
+			rhs.SetVec(n+mi+i, -s.ce[i])
+		}
+		if err := sol.SolveVec(kkt, rhs); err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				s.fail(errInteriorSingular)
+				return
+			}
+		}
+		for j := range dx {
+			dx[j] = sol.AtVec(j)
+		}
+		for i := range ds {
+			ds[i] = sol.AtVec(n + i)
+			dz[i] = sol.AtVec(n + mi + me + i)
+		}
+		for i := range dy {
+			dy[i] = sol.AtVec(n + mi + i)
+		}
+
+		// Keep the slacks and the inequality multipliers positive.
+		tau := math.Max(0.99, 1-mu)
+		alphaS := fractionToBoundary(sl, ds, tau)
+		alphaZ := fractionToBoundary(z, dz, tau)
+
+		// Update the merit penalty so that the step is a descent
+		// direction, and backtrack on the merit function.
+		multMax := 0.0
+		for i := range dy {
+			multMax = math.Max(multMax, math.Abs(y[i]+dy[i]))
+		}
+		for i := range dz {
+			multMax = math.Max(multMax, math.Abs(z[i]+dz[i]))
+		}
+		if nu < multMax {
+			nu = sqpPenaltyMul * multMax
+		}
+		viol := violationL1(s.ce, nil) + floats.Norm(r, 1)
+		phi := s.f + nu*viol
+		deriv := floats.Dot(s.g, dx) - nu*viol
+		for i := range sl {
+			phi -= mu * math.Log(sl[i])
+			deriv -= mu * ds[i] / sl[i]
+		}
+
+		alpha := alphaS
+		dNorm := math.Max(floats.Norm(dx, math.Inf(1)), floats.Norm(ds, math.Inf(1)))
+		for {
+			floats.AddScaledTo(xt, s.x, alpha, dx)
+			floats.AddScaledTo(st, sl, alpha, ds)
+			ft, done := s.eval(xt, cet, cit)
+			if done {
+				return
+			}
+			floats.SubTo(rt, cit, st)
+			phiT := ft + nu*(violationL1(cet, nil)+floats.Norm(rt, 1))
+			for _, v := range st {
+				phiT -= mu * math.Log(v)
+			}
+			if phiT <= phi+interiorDecrease*alpha*math.Min(deriv, 0) {
+				floats.SubTo(step, xt, s.x)
+				s.accept(xt, ft, cet, cit)
+				copy(sl, st)
+				break
+			}
+			alpha /= 2
+			if alpha*dNorm <= sqpMinStep*(1+floats.Norm(s.x, math.Inf(1))) {
+				if s.stepConverged(alpha * dNorm) {
+					return
+				}
+				s.fail(ErrLinesearcherFailure)
+				return
+			}
+		}
+		floats.AddScaled(y, alpha, dy)
+		floats.AddScaled(z, alphaZ, dz)
+		// Keep the multipliers within a bounded distance of the central
+		// path.
+		for i := range z {
+			z[i] = math.Max(mu/(interiorMultiplierBound*sl[i]), math.Min(z[i], interiorMultiplierBound*mu/sl[i]))
+		}
+
+		s.lagrangianGrad(gl0, y, z)
+		if s.gradient() {
+			return
+		}
+		if s.stepConverged(floats.Norm(step, math.Inf(1))) {
+			copy(s.lamE, y)
+			copy(s.lamI, z)
+			return
+		}
+		s.lagrangianGrad(gl, y, z)
+		floats.Sub(gl, gl0)
+		dampedBFGS(b, step, gl)
+	}
+}
+
+// fractionToBoundary returns the largest α in (0, 1] such that
+// v + α dv ≥ (1-τ) v.
+func fractionToBoundary(v, dv []float64, tau float64) float64 {
+	alpha := 1.0
+	for i, d := range dv {
+		if d < 0 {
+			alpha = math.Min(alpha, -tau*v[i]/d)
+		}
+	}
+	return alpha
+}
@@ -0,0 +1,489 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	// lbfgsbEps is the machine epsilon.
+	lbfgsbEps = 0x1p-52
+	// maxLinesearchStep is the maximum linesearch step along a direction
+	// that does not reach any bound.
+	maxLinesearchStep = 1e20
+)
+
+var (
+	_ Method          = (*LBFGSB)(nil)
+	_ BoundedMethod   = (*LBFGSB)(nil)
+	_ localMethod     = (*LBFGSB)(nil)
+	_ NextDirectioner = (*LBFGSB)(nil)
+)
+
+// LBFGSB implements the limited-memory BFGS method for gradient-based
+// minimization subject to bounds on the variables.
+//
+// At each iteration LBFGSB finds the generalized Cauchy point, the first
+// local minimizer of the limited-memory quadratic model along the projected
+// steepest descent path, and then minimizes the model over the variables
+// that are not at a bound at the Cauchy point. The resulting feasible point
+// defines the search direction, along which a More-Thuente linesearch is
+// performed with steps that keep all evaluations within the bounds. If the
+// problem is unbounded, LBFGSB behaves like LBFGS.
+//
+// If the linesearch fails, LBFGSB discards the limited-memory storage and
+// restarts from the projected steepest descent direction. If the linesearch
+// fails along that direction too, or the decrease along it is below the
+// precision of the function, LBFGSB terminates with MethodConverge status.
+//
+// References:
+//  - Byrd, R., Lu, P., Nocedal, J., Zhu, C.: A limited memory algorithm for
+//    bound constrained optimization. SIAM J. Sci. Comput. 16(5), 1190-1208 (1995)
+type LBFGSB struct {
+	// Store is the size of the limited-memory storage.
+	// If Store is 0, it will be defaulted to 10.
+	Store int
+	// GradStopThreshold sets the threshold for stopping if the norm of the
+	// projected gradient gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	ls *LinesearchMethod
+	mt *MoreThuente
+
+	lower, upper []float64 // Bounds from InitBounds, nil if unbounded.
+	lo, up       []float64 // Bounds of the current run, infinite if unbounded.
+
+	x    []float64 // Location at the last major iteration
+	f    float64   // Function value at the last major iteration
+	grad []float64 // Gradient at the last major iteration
+
+	// History, from the oldest to the newest pair.
+	s, y  [][]float64
+	theta float64 // Scaling of the initial Hessian approximation
+
+	w []float64 // W = [Y θS] stored row-wise
+	m mat.Dense // Middle matrix of the compact representation
+
+	xcp []float64 // Generalized Cauchy point
+	d   []float64 // Projected steepest descent direction
+	t   []float64 // Breakpoints
+	c   []float64 // Wᵀ(xcp - x)
+	p   []float64 // Wᵀd
+}
+
+func (l *LBFGSB) Status() (Status, error) {
+	return l.status, l.err
+}
+
+func (*LBFGSB) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (l *LBFGSB) InitBounds(lower, upper []float64) {
+	l.lower = lower
+	l.upper = upper
+}
+
+func (l *LBFGSB) Init(dim, tasks int) int {
+	l.status = NotTerminated
+	l.err = nil
+	l.lo = resize(l.lo, dim)
+	l.up = resize(l.up, dim)
+	checkBounds(l.lo, l.up, l.lower, l.upper)
+	return 1
+}
+
+func (l *LBFGSB) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	l.status, l.err = localOptimizer{lower: l.lo, upper: l.up}.run(l, l.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (l *LBFGSB) initLocal(loc *Location) (Operation, error) {
+	if l.Store == 0 {
+		l.Store = 10
+	}
+	if l.ls == nil {
+		l.ls = &LinesearchMethod{}
+	}
+	if l.mt == nil {
+		l.mt = &MoreThuente{DecreaseFactor: 1e-3}
+	}
+	l.ls.Linesearcher = maxStepLinesearcher{l.mt}
+	l.ls.NextDirectioner = l
+
+	op, err := l.ls.Init(loc)
+	if op.isEvaluation() {
+		projectBounds(loc.X, l.lo, l.up)
+	}
+	return op, err
+}
+
+func (l *LBFGSB) iterateLocal(loc *Location) (Operation, error) {
+	op, err := l.ls.Iterate(loc)
+	if err == ErrNoProgress || err == ErrLinesearcherFailure {
+		pg := projectedGradNorm(l.x, l.grad, l.lo, l.up)
+		if len(l.s) == 0 || pg*pg <= lbfgsbEps*math.Abs(l.f) {
+			// The linesearch failed along the projected steepest descent
+			// direction, or the decrease along it is below the precision
+			// of the function.
+			return MethodDone, nil
+		}
+		// Restart from the last major iteration, which resets the
+		// limited-memory storage.
+		copy(loc.X, l.x)
+		loc.F = l.f
+		copy(loc.Gradient, l.grad)
+		op, err = l.ls.Init(loc)
+	}
+	if op.isEvaluation() {
+		// Guard against rounding errors in the step along the direction.
+		projectBounds(loc.X, l.lo, l.up)
+	}
+	return op, err
+}
+
+func (l *LBFGSB) InitDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	l.x = resize(l.x, dim)
+	copy(l.x, loc.X)
+	l.f = loc.F
+	l.grad = resize(l.grad, dim)
+	copy(l.grad, loc.Gradient)
+
+	l.s = l.s[:0]
+	l.y = l.y[:0]
+	l.theta = 1
+	l.direction(dir, loc.X, loc.Gradient)
+	l.setMaxStep(loc.X, dir)
+	return math.Min(1, 1/floats.Norm(dir, 2))
+}
+
+func (l *LBFGSB) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	if len(loc.Gradient) != dim || len(dir) != dim || len(l.x) != dim {
+		panic("lbfgsb: unexpected size mismatch")
+	}
+
+	// Add the latest correction pair to the history unless the curvature
+	// condition is not sufficiently satisfied.
+	s := make([]float64, dim)
+	y := make([]float64, dim)
+	floats.SubTo(s, loc.X, l.x)
+	floats.SubTo(y, loc.Gradient, l.grad)
+	sDotY := floats.Dot(s, y)
+	if sDotY > -lbfgsbEps*floats.Dot(l.grad, s) {
+		if len(l.s) == l.Store {
+			l.s = l.s[1:]
+			l.y = l.y[1:]
+		}
+		l.s = append(l.s, s)
+		l.y = append(l.y, y)
+		l.theta = floats.Dot(y, y) / sDotY
+	}
+	copy(l.x, loc.X)
+	l.f = loc.F
+	copy(l.grad, loc.Gradient)
+
+	if !l.direction(dir, loc.X, loc.Gradient) || floats.Dot(dir, loc.Gradient) >= 0 {
+		// The limited-memory matrix is not usable, restart from the
+		// projected steepest descent direction.
+		l.s = l.s[:0]
+		l.y = l.y[:0]
+		l.theta = 1
+		l.direction(dir, loc.X, loc.Gradient)
+	}
+	l.setMaxStep(loc.X, dir)
+	if len(l.s) == 0 {
+		// Without curvature information the scale of dir is unknown.
+		return math.Min(1, 1/floats.Norm(dir, 2))
+	}
+	return 1
+}
+
+// setMaxStep sets the maximum step of the linesearch to the largest step
+// from x along dir that stays within the bounds.
+func (l *LBFGSB) setMaxStep(x, dir []float64) {
+	maxStep := maxLinesearchStep
+	for i, d := range dir {
+		switch {
+		case d > 0:
+			maxStep = math.Min(maxStep, (l.up[i]-x[i])/d)
+		case d < 0:
+			maxStep = math.Min(maxStep, (l.lo[i]-x[i])/d)
+		}
+	}
+	// x + dir is within the bounds, so the maximum step is at least one
+	// except for rounding errors.
+	l.mt.MaximumStep = math.Max(maxStep, 1)
+}
+
+// direction computes the search direction dir at x with gradient g as the
+// difference between the approximate minimizer of the quadratic model
+// within the bounds and x. It returns false if the compact representation
+// of the limited-memory matrix could not be formed.
+func (l *LBFGSB) direction(dir, x, g []float64) (ok bool) {
+	if !l.formCompact() {
+		return false
+	}
+	l.cauchyPoint(x, g)
+	l.subspaceMin(dir, x, g)
+	floats.Sub(dir, x)
+	return true
+}
+
+// formCompact forms W = [Y θS] and the middle matrix
+//  M = [-D  Lᵀ ]⁻¹
+//      [ L θSᵀS]
+// of the compact representation B = θI - W M Wᵀ of the limited-memory
+// matrix, where D is the diagonal and L the strictly lower triangle of SᵀY.
+func (l *LBFGSB) formCompact() (ok bool) {
+	n := len(l.x)
+	k := len(l.s)
+	n2 := 2 * k
+	l.w = resize(l.w, n*n2)
+	for i := 0; i < n; i++ {
+		for j := 0; j < k; j++ {
+			l.w[i*n2+j] = l.y[j][i]
+			l.w[i*n2+k+j] = l.theta * l.s[j][i]
+		}
+	}
+	if k == 0 {
+		return true
+	}
+	kkt := mat.NewDense(n2, n2, nil)
+	for i := 0; i < k; i++ {
+		kkt.Set(i, i, -floats.Dot(l.s[i], l.y[i]))
+		for j := 0; j < i; j++ {
+			v := floats.Dot(l.s[i], l.y[j])
+			kkt.Set(k+i, j, v)
+			kkt.Set(j, k+i, v)
+		}
+		for j := 0; j <= i; j++ {
+			v := l.theta * floats.Dot(l.s[i], l.s[j])
+			kkt.Set(k+i, k+j, v)
+			kkt.Set(k+j, k+i, v)
+		}
+	}
+	l.m.Reset()
+	err := l.m.Inverse(kkt)
+	if cond, ok := err.(mat.Condition); ok && !math.IsInf(float64(cond), 1) {
+		// The middle matrix is often badly scaled but still usable.
+		err = nil
+	}
+	return err == nil
+}
+
+// wRow returns the i-th row of W.
+func (l *LBFGSB) wRow(i int) []float64 {
+	n2 := 2 * len(l.s)
+	return l.w[i*n2 : (i+1)*n2]
+}
+
+// mDot returns uᵀ M v.
+func (l *LBFGSB) mDot(u, v []float64) float64 {
+	if len(u) == 0 {
+		return 0
+	}
+	return mat.Inner(mat.NewVecDense(len(u), u), &l.m, mat.NewVecDense(len(v), v))
+}
+
+// cauchyPoint computes the generalized Cauchy point at x with gradient g
+// and stores it in l.xcp, and stores Wᵀ(xcp - x) in l.c.
+func (l *LBFGSB) cauchyPoint(x, g []float64) {
+	n := len(x)
+	n2 := 2 * len(l.s)
+	l.xcp = resize(l.xcp, n)
+	l.d = resize(l.d, n)
+	l.t = resize(l.t, n)
+	l.c = resize(l.c, n2)
+	l.p = resize(l.p, n2)
+	copy(l.xcp, x)
+	for i := range l.c {
+		l.c[i] = 0
+		l.p[i] = 0
+	}
+
+	// Compute the breakpoints of the projected steepest descent path.
+	var order []int
+	var nfree int
+	for i, v := range x {
+		t := math.Inf(1)
+		switch {
+		case g[i] < 0:
+			t = (v - l.up[i]) / g[i]
+		case g[i] > 0:
+			t = (v - l.lo[i]) / g[i]
+		}
+		l.t[i] = t
+		if t == 0 {
+			l.d[i] = 0
+			continue
+		}
+		l.d[i] = -g[i]
+		nfree++
+		if !math.IsInf(t, 1) {
+			order = append(order, i)
+		}
+		floats.AddScaled(l.p, l.d[i], l.wRow(i))
+	}
+	if nfree == 0 {
+		return
+	}
+	sort.Slice(order, func(a, b int) bool { return l.t[order[a]] < l.t[order[b]] })
+
+	fp := -floats.Dot(l.d, l.d)
+	fpp := -l.theta*fp - l.mDot(l.p, l.p)
+	fppMin := lbfgsbEps * fpp
+	dtMin := -fp / fpp
+	var tOld float64
+	for _, b := range order {
+		dt := l.t[b] - tOld
+		if dtMin < dt {
+			break
+		}
+		// Fix the variable b at its bound.
+		if l.d[b] > 0 {
+			l.xcp[b] = l.up[b]
+		} else {
+			l.xcp[b] = l.lo[b]
+		}
+		zb := l.xcp[b] - x[b]
+		floats.AddScaled(l.c, dt, l.p)
+		gb := g[b]
+		wb := l.wRow(b)
+		fp += dt*fpp + gb*gb + l.theta*gb*zb - gb*l.mDot(wb, l.c)
+		fpp += -l.theta*gb*gb - 2*gb*l.mDot(wb, l.p) - gb*gb*l.mDot(wb, wb)
+		fpp = math.Max(fpp, fppMin)
+		floats.AddScaled(l.p, gb, wb)
+		l.d[b] = 0
+		nfree--
+		dtMin = -fp / fpp
+		tOld = l.t[b]
+	}
+	if nfree == 0 {
+		dtMin = 0
+	}
+	dtMin = math.Max(dtMin, 0)
+	tOld += dtMin
+	for i, v := range l.d {
+		if v != 0 {
+			l.xcp[i] = x[i] + tOld*v
+		}
+	}
+	floats.AddScaled(l.c, dtMin, l.p)
+}
+
+// subspaceMin minimizes the quadratic model over the variables that are
+// not at a bound at the Cauchy point by the direct primal method, truncating
+// the step to stay within the bounds, and stores the result in dst.
+func (l *LBFGSB) subspaceMin(dst, x, g []float64) {
+	copy(dst, l.xcp)
+	var free []int
+	for i, v := range l.xcp {
+		if l.lo[i] < v && v < l.up[i] {
+			free = append(free, i)
+		}
+	}
+	if len(free) == 0 {
+		return
+	}
+	n2 := 2 * len(l.s)
+	theta := l.theta
+
+	// Reduced gradient r = Zᵀ(g + θ(xcp - x) - W M c).
+	var mc *mat.VecDense
+	if n2 > 0 {
+		mc = mat.NewVecDense(n2, nil)
+		mc.MulVec(&l.m, mat.NewVecDense(n2, l.c))
+	}
+	r := make([]float64, len(free))
+	for j, i := range free {
+		r[j] = g[i] + theta*(l.xcp[i]-x[i])
+		if n2 > 0 {
+			r[j] -= floats.Dot(l.wRow(i), mc.RawVector().Data)
+		}
+	}
+
+	// Apply the inverse of the reduced matrix θI - ZᵀW M WᵀZ using the
+	// Sherman-Morrison-Woodbury formula.
+	du := make([]float64, len(free))
+	for j := range r {
+		du[j] = -r[j] / theta
+	}
+	if n2 > 0 {
+		v := mat.NewVecDense(n2, nil)
+		wtw := mat.NewDense(n2, n2, nil)
+		for j, i := range free {
+			wi := mat.NewVecDense(n2, l.wRow(i))
+			v.AddScaledVec(v, r[j], wi)
+			wtw.RankOne(wtw, 1, wi, wi)
+		}
+		v.MulVec(&l.m, v)
+		var nmat mat.Dense
+		nmat.Mul(&l.m, wtw)
+		nmat.Scale(-1/theta, &nmat)
+		for i := 0; i < n2; i++ {
+			nmat.Set(i, i, nmat.At(i, i)+1)
+		}
+		var u mat.VecDense
+		err := u.SolveVec(&nmat, v)
+		if err != nil {
+			if _, ok := err.(mat.Condition); !ok {
+				return
+			}
+		}
+		for j, i := range free {
+			du[j] -= floats.Dot(l.wRow(i), u.RawVector().Data) / (theta * theta)
+		}
+	}
+
+	// Truncate the step so that the result is within the bounds.
+	alpha := 1.0
+	for j, i := range free {
+		switch {
+		case du[j] > 0:
+			alpha = math.Min(alpha, (l.up[i]-l.xcp[i])/du[j])
+		case du[j] < 0:
+			alpha = math.Min(alpha, (l.lo[i]-l.xcp[i])/du[j])
+		}
+	}
+	for j, i := range free {
+		dst[i] = l.xcp[i] + alpha*du[j]
+	}
+}
+
+func (*LBFGSB) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
+
+// maxStepLinesearcher is a Linesearcher that accepts the maximum step of
+// the wrapped Linesearcher when the decrease conditions hold there, instead
+// of returning ErrLinesearcherBound.
+type maxStepLinesearcher struct {
+	Linesearcher
+}
+
+func (ls maxStepLinesearcher) Iterate(f, g float64) (Operation, float64, error) {
+	op, step, err := ls.Linesearcher.Iterate(f, g)
+	if err == ErrLinesearcherBound {
+		return MajorIteration, step, nil
+	}
+	return op, step, err
+}
@@ -0,0 +1,468 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultLeastSquaresTol        = 1e-10
+	defaultLeastSquaresIterations = 1000
+)
+
+// LeastSquaresProblem is a nonlinear least squares problem
+//  minimize ½ ||r(x)||^2  subject to  Lower <= x <= Upper,
+// where r maps the n variables x to m residuals.
+type LeastSquaresProblem struct {
+	// Func evaluates the residuals r(x) into dst, which has length
+	// Residuals. Func must not modify x.
+	Func func(dst, x []float64)
+
+	// Jac evaluates the m×n Jacobian of the residuals at x into dst,
+	//  J_{i,j} = ∂r_i/∂x_j.
+	// If Jac is nil, the Jacobian is approximated by forward differences
+	// of Func using fd.Jacobian.
+	Jac func(dst *mat.Dense, x []float64)
+
+	// Residuals is the number of residuals m.
+	Residuals int
+
+	// Lower and Upper, if not nil, are the lower and upper bounds on the
+	// variables. Infinite bounds may be used for unbounded variables.
+	Lower, Upper []float64
+}
+
+// LeastSquaresSettings holds the settings for solving nonlinear least
+// squares problems.
+type LeastSquaresSettings struct {
+	// FunctionThreshold stops the solver with FunctionThreshold status
+	// when the cost ½ ||r(x)||^2 is less than or equal to this value.
+	FunctionThreshold float64
+
+	// FunctionTol stops the solver with FunctionConvergence status when
+	// both the actual and predicted relative reductions of the cost in
+	// an iteration are at most FunctionTol. If FunctionTol is zero, a
+	// default of 1e-10 is used.
+	FunctionTol float64
+
+	// StepTol stops the solver with StepConvergence status when the step
+	// is no longer than StepTol*(StepTol + ||x||). If StepTol is zero, a
+	// default of 1e-10 is used.
+	StepTol float64
+
+	// GradientThreshold stops the solver with GradientThreshold status
+	// when the infinity norm of the projected gradient Jᵀr is at most
+	// GradientThreshold. If GradientThreshold is zero, a default of 1e-10
+	// is used.
+	GradientThreshold float64
+
+	// MajorIterations is the maximum number of iterations allowed.
+	// IterationLimit status is returned if the number of iterations
+	// equals or exceeds this value. If it equals zero, a default of 1000
+	// is used.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum allowed number of evaluations of the
+	// residuals, including those made to approximate the Jacobian.
+	// FunctionEvaluationLimit status is returned if the total number of
+	// evaluations equals or exceeds this number.
+	// If it equals zero, this setting has no effect.
+	FuncEvaluations int
+}
+
+// LeastSquaresResult holds the result of solving a nonlinear least squares
+// problem.
+type LeastSquaresResult struct {
+	// X is the solution, Residuals are the residuals at X and Cost is
+	// ½ ||r(X)||^2.
+	X         []float64
+	Residuals []float64
+	Cost      float64
+
+	// Jacobian is the Jacobian of the residuals at X. It is nil if the
+	// evaluation limit prevented its evaluation.
+	Jacobian *mat.Dense
+
+	// Stats holds the statistics of the solution. FuncEvaluations counts
+	// the evaluations of the residuals and GradEvaluations the evaluations
+	// of the Jacobian.
+	Stats
+	Status Status
+}
+
+// CovarianceTo stores into dst an estimate of the covariance matrix of the
+// parameters X of a least squares fit,
+//  C = s^2 (JᵀJ)^{-1},  s^2 = ||r||^2 / (m - n),
+// where J is the Jacobian at X. (JᵀJ)^{-1} is computed from the QR
+// factorization of J. Bounds on the variables are not taken into account.
+//
+// If dst is empty, it is resized to n×n, otherwise CovarianceTo panics if
+// dst is not n×n. CovarianceTo returns an error if there are no more
+// residuals than variables, and a mat.Condition error if J is singular or
+// near singular.
+func (r *LeastSquaresResult) CovarianceTo(dst *mat.SymDense) error {
+	m, n := r.Jacobian.Dims()
+	if dst.IsEmpty() {
+		dst.ReuseAsSym(n)
+	} else if dst.Symmetric() != n {
+		panic(mat.ErrShape)
+	}
+	if m <= n {
+		return errors.New("optimize: too few residuals for covariance estimate")
+	}
+	var qr mat.QR
+	qr.Factorize(r.Jacobian)
+	var qrR mat.Dense
+	qr.RTo(&qrR)
+	rt := mat.NewTriDense(n, mat.Upper, nil)
+	rt.Copy(qrR.Slice(0, n, 0, n))
+	var rinv mat.TriDense
+	err := rinv.InverseTri(rt)
+	if cond, ok := err.(mat.Condition); ok && math.IsInf(float64(cond), 1) {
+		return err
+	}
+	// (JᵀJ)^{-1} = (RᵀR)^{-1} = R^{-1} R^{-T}.
+	dst.SymOuterK(2*r.Cost/float64(m-n), &rinv)
+	return err
+}
+
+// LeastSquaresMethod is a method for solving nonlinear least squares
+// problems. LeastSquaresMethod is implemented by LevenbergMarquardt, Dogleg
+// and GaussNewton.
+type LeastSquaresMethod interface {
+	// solveLeastSquares iterates from the initial state of s until s
+	// reports that the solver must terminate.
+	solveLeastSquares(s *lsqState)
+}
+
+// LeastSquares minimizes ½ ||r(x)||^2 for the problem p, starting from x0,
+// using the given method. If x0 is outside the bounds of p, it is first
+// projected onto them. If method is nil, LevenbergMarquardt is used. If
+// settings is nil, the defaults described for LeastSquaresSettings are used.
+//
+// LeastSquares panics if p.Func is nil, p.Residuals is not positive, or the
+// bounds have the wrong length or are inconsistent. It returns ErrFunc if
+// the residuals at the initial point are not finite. Otherwise it returns
+// the best point found and an error if the solver ended early as indicated
+// by Status.Err.
+func LeastSquares(p LeastSquaresProblem, x0 []float64, method LeastSquaresMethod, settings *LeastSquaresSettings) (*LeastSquaresResult, error) {
+	if p.Func == nil || p.Residuals <= 0 {
+		panic(badProblem)
+	}
+	n := len(x0)
+	if n == 0 {
+		return nil, ErrZeroDimensional
+	}
+	if method == nil {
+		method = LevenbergMarquardt{}
+	}
+	s := newLSQState(p, x0, settings)
+	cost, done := s.residuals(s.r, s.x)
+	if done {
+		return s.finish()
+	}
+	if math.IsInf(cost, 1) {
+		return nil, ErrFunc(floats.Dot(s.r, s.r))
+	}
+	s.cost = cost
+	if cost <= s.settings.FunctionThreshold {
+		s.result.Status = FunctionThreshold
+		return s.finish()
+	}
+	if !s.jacobian() {
+		method.solveLeastSquares(s)
+	}
+	return s.finish()
+}
+
+// lsqState holds the state of a nonlinear least squares solver and checks
+// for termination.
+type lsqState struct {
+	p            LeastSquaresProblem
+	settings     LeastSquaresSettings
+	lower, upper []float64
+	start        time.Time
+
+	// x is the current point, r and cost the residuals and cost at x,
+	// and jac and grad the Jacobian and gradient Jᵀr at x if current
+	// is true.
+	x, r    []float64
+	cost    float64
+	jac     *mat.Dense
+	grad    []float64
+	current bool
+
+	// free holds whether each variable is free to move, rather than held
+	// at a bound by the gradient.
+	free []bool
+
+	result LeastSquaresResult
+}
+
+func newLSQState(p LeastSquaresProblem, x0 []float64, settings *LeastSquaresSettings) *lsqState {
+	n := len(x0)
+	s := &lsqState{
+		p:     p,
+		lower: make([]float64, n),
+		upper: make([]float64, n),
+		start: time.Now(),
+		x:     make([]float64, n),
+		r:     make([]float64, p.Residuals),
+		jac:   mat.NewDense(p.Residuals, n, nil),
+		grad:  make([]float64, n),
+		free:  make([]bool, n),
+	}
+	if settings != nil {
+		s.settings = *settings
+	}
+	if s.settings.FunctionThreshold < 0 || s.settings.FunctionTol < 0 || s.settings.StepTol < 0 || s.settings.GradientThreshold < 0 {
+		panic("optimize: negative tolerance")
+	}
+	if s.settings.FunctionTol == 0 {
+		s.settings.FunctionTol = defaultLeastSquaresTol
+	}
+	if s.settings.StepTol == 0 {
+		s.settings.StepTol = defaultLeastSquaresTol
+	}
+	if s.settings.GradientThreshold == 0 {
+		s.settings.GradientThreshold = defaultLeastSquaresTol
+	}
+	if s.settings.MajorIterations == 0 {
+		s.settings.MajorIterations = defaultLeastSquaresIterations
+	}
+	checkBounds(s.lower, s.upper, p.Lower, p.Upper)
+	copy(s.x, x0)
+	s.project(s.x)
+	return s
+}
+
+// project projects x onto the bounds.
+func (s *lsqState) project(x []float64) {
+	for i, v := range x {
+		x[i] = math.Max(s.lower[i], math.Min(v, s.upper[i]))
+	}
+}
+
+// residuals evaluates the residuals at x into dst and returns the cost,
+// or +Inf if it is not finite. It returns done as true if the evaluation
+// limit has been reached.
+func (s *lsqState) residuals(dst, x []float64) (cost float64, done bool) {
+	if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations >= s.settings.FuncEvaluations {
+		s.result.Status = FunctionEvaluationLimit
+		return math.Inf(1), true
+	}
+	s.p.Func(dst, x)
+	s.result.FuncEvaluations++
+	cost = floats.Dot(dst, dst) / 2
+	if math.IsNaN(cost) {
+		cost = math.Inf(1)
+	}
+	return cost, false
+}
+
+// jacobian evaluates the Jacobian and gradient at the current point and
+// updates the set of free variables. It returns done as true if the
+// evaluations needed to approximate the Jacobian would exceed the
+// evaluation limit.
+func (s *lsqState) jacobian() (done bool) {
+	if s.p.Jac != nil {
+		s.p.Jac(s.jac, s.x)
+	} else {
+		if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations+len(s.x) > s.settings.FuncEvaluations {
+			s.result.Status = FunctionEvaluationLimit
+			return true
+		}
+		fd.Jacobian(s.jac, s.p.Func, s.x, &fd.JacobianSettings{
+			Formula:     fd.Forward,
+			OriginValue: s.r,
+		})
+		s.result.FuncEvaluations += len(s.x)
+	}
+	s.result.GradEvaluations++
+	s.current = true
+	g := mat.NewVecDense(len(s.grad), s.grad)
+	g.MulVec(s.jac.T(), mat.NewVecDense(len(s.r), s.r))
+	for i, v := range s.x {
+		s.free[i] = !(v <= s.lower[i] && s.grad[i] > 0) && !(v >= s.upper[i] && s.grad[i] < 0)
+	}
+	return false
+}
+
+// freeGrad stores the gradient with respect to the free variables into dst,
+// with zeros for the fixed variables.
+func (s *lsqState) freeGrad(dst []float64) {
+	for i, g := range s.grad {
+		dst[i] = 0
+		if s.free[i] {
+			dst[i] = g
+		}
+	}
+}
+
+// iterate starts a new iteration, returning done as true if the projected
+// gradient is small enough or the iteration limit has been reached.
+func (s *lsqState) iterate() (done bool) {
+	var norm float64
+	for i, v := range s.x {
+		p := math.Max(s.lower[i], math.Min(v-s.grad[i], s.upper[i]))
+		norm = math.Max(norm, math.Abs(p-v))
+	}
+	if norm <= s.settings.GradientThreshold {
+		s.result.Status = GradientThreshold
+		return true
+	}
+	if s.result.MajorIterations >= s.settings.MajorIterations {
+		s.result.Status = IterationLimit
+		return true
+	}
+	s.result.MajorIterations++
+	return false
+}
+
+// step stores into dst the solution p of the regularized linear least
+// squares problem
+//  minimize ||J p + r||^2 + mu ||D p||^2
+// over the free variables, with zeros for the fixed variables, where D is
+// the diagonal matrix with elements d. It returns whether a finite solution
+// was found; if mu is zero this requires that the free columns of J have
+// full rank.
+func (s *lsqState) step(dst []float64, mu float64, d []float64) bool {
+	var cols []int
+	for i, free := range s.free {
+		dst[i] = 0
+		if free {
+			cols = append(cols, i)
+		}
+	}
+	m, nf := len(s.r), len(cols)
+	if nf == 0 {
+		return false
+	}
+	rows := m
+	if mu > 0 {
+		rows += nf
+	}
+	if rows < nf {
+		return false
+	}
+	a := mat.NewDense(rows, nf, nil)
+	b := mat.NewVecDense(rows, nil)
+	for i, v := range s.r {
+		for k, j := range cols {
+			a.Set(i, k, s.jac.At(i, j))
+		}
+		b.SetVec(i, -v)
+	}
+	if mu > 0 {
+		sqrtMu := math.Sqrt(mu)
+		for k, j := range cols {
+			a.Set(m+k, k, sqrtMu*d[j])
+		}
+	}
+	var qr mat.QR
+	qr.Factorize(a)
+	var sol mat.VecDense
+	if err := qr.SolveVecTo(&sol, false, b); err != nil {
+		return false
+	}
+	for k, j := range cols {
+		v := sol.AtVec(k)
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return false
+		}
+		dst[j] = v
+	}
+	return true
+}
+
+// gaussNewton stores into dst the Gauss-Newton step over the free variables.
+// If the free columns of the Jacobian are rank deficient, a slightly
+// regularized step is used instead.
+func (s *lsqState) gaussNewton(dst []float64) bool {
+	const sqrtEps = 0x1p-26
+	if s.step(dst, 0, nil) {
+		return true
+	}
+	d := make([]float64, len(s.x))
+	var scale float64
+	for j := range d {
+		d[j] = 1
+		col := mat.Col(nil, j, s.jac)
+		scale = math.Max(scale, floats.Dot(col, col))
+	}
+	if scale == 0 {
+		return false
+	}
+	return s.step(dst, sqrtEps*scale, d)
+}
+
+// trial stores the projection of x + p onto the bounds into xt, replaces p
+// with the projected step xt - x and returns the reduction in cost predicted
+// by the linear model of the residuals.
+func (s *lsqState) trial(xt, p []float64) (pred float64) {
+	for i, v := range s.x {
+		xt[i] = v + p[i]
+	}
+	s.project(xt)
+	floats.SubTo(p, xt, s.x)
+	var jp mat.VecDense
+	jp.MulVec(s.jac, mat.NewVecDense(len(p), p))
+	return -floats.Dot(s.grad, p) - mat.Dot(&jp, &jp)/2
+}
+
+// accept moves to the trial point xt with residuals rt and cost ct, reached
+// by the step p with predicted reduction pred. It returns done as true if
+// the solver has converged, and otherwise updates the Jacobian.
+func (s *lsqState) accept(xt, rt []float64, ct float64, p []float64, pred float64) (done bool) {
+	act := s.cost - ct
+	tol := s.settings.FunctionTol * s.cost
+	copy(s.x, xt)
+	copy(s.r, rt)
+	s.cost = ct
+	s.current = false
+	switch {
+	case ct <= s.settings.FunctionThreshold:
+		s.result.Status = FunctionThreshold
+	case act <= tol && pred <= tol && act <= 2*pred:
+		s.result.Status = FunctionConvergence
+	case s.stepConverged(p):
+	default:
+		return s.jacobian()
+	}
+	return true
+}
+
+// stepConverged returns whether the step p is small enough relative to x
+// for the solver to terminate.
+func (s *lsqState) stepConverged(p []float64) bool {
+	tol := s.settings.StepTol
+	if floats.Norm(p, 2) <= tol*(tol+floats.Norm(s.x, 2)) {
+		s.result.Status = StepConvergence
+		return true
+	}
+	return false
+}
+
+// finish returns the result of the solver.
+func (s *lsqState) finish() (*LeastSquaresResult, error) {
+	if !s.current && !math.IsInf(s.cost, 1) && s.result.Status != FunctionEvaluationLimit {
+		s.jacobian()
+	}
+	s.result.X = s.x
+	s.result.Residuals = s.r
+	s.result.Cost = s.cost
+	if s.current {
+		s.result.Jacobian = s.jac
+	}
+	s.result.Runtime = time.Since(s.start)
+	return &s.result, s.result.Status.Err()
+}
@@ -0,0 +1,66 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleLeastSquares() {
+	// Fit the model y = a exp(-b t) to measured data.
+	ts := []float64{0, 1, 2, 3, 4, 5, 6, 7}
+	ys := []float64{4.02, 2.72, 1.83, 1.24, 0.81, 0.57, 0.36, 0.26}
+	p := optimize.LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			for i, t := range ts {
+				dst[i] = x[0]*math.Exp(-x[1]*t) - ys[i]
+			}
+		},
+		Residuals: len(ts),
+		// The decay rate must not be negative.
+		Lower: []float64{math.Inf(-1), 0},
+	}
+	result, err := optimize.LeastSquares(p, []float64{1, 1}, optimize.LevenbergMarquardt{}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var cov mat.SymDense
+	err = result.CovarianceTo(&cov)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("a = %.3f ± %.3f\n", result.X[0], math.Sqrt(cov.At(0, 0)))
+	fmt.Printf("b = %.4f ± %.4f\n", result.X[1], math.Sqrt(cov.At(1, 1)))
+
+	// Output:
+	// a = 4.025 ± 0.012
+	// b = 0.3948 ± 0.0020
+}
+
+func ExampleSolveNonlinear() {
+	// Find an intersection of the circle x² + y² = 4 and the curve
+	// y = 1 - exp(x).
+	p := optimize.NonlinearSystem{
+		Func: func(dst, x []float64) {
+			dst[0] = x[0]*x[0] + x[1]*x[1] - 4
+			dst[1] = math.Exp(x[0]) + x[1] - 1
+		},
+	}
+	result, err := optimize.SolveNonlinear(p, []float64{1, -1}, optimize.PowellHybrid{}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = %.6f, y = %.6f\n", result.X[0], result.X[1])
+	fmt.Println("status:", result.Status)
+
+	// Output:
+	// x = 1.004169, y = -1.729637
+	// status: FunctionThreshold
+}
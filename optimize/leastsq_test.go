@@ -0,0 +1,225 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var lsqMethods = []struct {
+	name   string
+	method LeastSquaresMethod
+}{
+	{name: "LevenbergMarquardt", method: LevenbergMarquardt{}},
+	{name: "Dogleg", method: Dogleg{}},
+	{name: "GaussNewton", method: GaussNewton{}},
+}
+
+// expDecay returns a least squares problem fitting the model
+//  y = a exp(-b t) + c
+// to exact data generated with a = 5, b = 0.7 and c = 1.
+func expDecay() LeastSquaresProblem {
+	ts := make([]float64, 20)
+	ys := make([]float64, len(ts))
+	for i := range ts {
+		ts[i] = float64(i) / 2
+		ys[i] = 5*math.Exp(-0.7*ts[i]) + 1
+	}
+	return LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			for i, t := range ts {
+				dst[i] = x[0]*math.Exp(-x[1]*t) + x[2] - ys[i]
+			}
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			for i, t := range ts {
+				e := math.Exp(-x[1] * t)
+				dst.Set(i, 0, e)
+				dst.Set(i, 1, -x[0]*t*e)
+				dst.Set(i, 2, 1)
+			}
+		},
+		Residuals: len(ts),
+	}
+}
+
+// rosenbrockResiduals returns the Rosenbrock function as a least squares
+// problem with residuals 10(x_1 - x_0^2) and 1 - x_0.
+func rosenbrockResiduals() LeastSquaresProblem {
+	return LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			dst[0] = 10 * (x[1] - x[0]*x[0])
+			dst[1] = 1 - x[0]
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			dst.Set(0, 0, -20*x[0])
+			dst.Set(0, 1, 10)
+			dst.Set(1, 0, -1)
+			dst.Set(1, 1, 0)
+		},
+		Residuals: 2,
+	}
+}
+
+func TestLeastSquares(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		p    LeastSquaresProblem
+		x0   []float64
+		want []float64
+	}{
+		{
+			name: "expDecay",
+			p:    expDecay(),
+			x0:   []float64{1, 1, 0},
+			want: []float64{5, 0.7, 1},
+		},
+		{
+			name: "Rosenbrock",
+			p:    rosenbrockResiduals(),
+			x0:   []float64{-1.2, 1},
+			want: []float64{1, 1},
+		},
+		{
+			name: "boundedRosenbrock",
+			p: func() LeastSquaresProblem {
+				p := rosenbrockResiduals()
+				p.Upper = []float64{0.5, math.Inf(1)}
+				return p
+			}(),
+			x0:   []float64{-1.2, 1},
+			want: []float64{0.5, 0.25},
+		},
+		{
+			name: "boundedExpDecay",
+			p: func() LeastSquaresProblem {
+				p := expDecay()
+				p.Lower = []float64{0, 0, 0}
+				p.Upper = []float64{10, 10, 10}
+				return p
+			}(),
+			x0:   []float64{20, -1, 5},
+			want: []float64{5, 0.7, 1},
+		},
+	} {
+		for _, m := range lsqMethods {
+			for _, numJac := range []bool{false, true} {
+				p := test.p
+				tol := 1e-7
+				if numJac {
+					p.Jac = nil
+					tol = 1e-5
+				}
+				res, err := LeastSquares(p, test.x0, m.method, nil)
+				if err != nil {
+					t.Errorf("%s %s numJac=%t: unexpected error: %v", test.name, m.name, numJac, err)
+					continue
+				}
+				if !floats.EqualApprox(res.X, test.want, tol) {
+					t.Errorf("%s %s numJac=%t: unexpected solution: got %v, want %v (status %v)",
+						test.name, m.name, numJac, res.X, test.want, res.Status)
+				}
+				if p.Lower != nil || p.Upper != nil {
+					for i, v := range res.X {
+						if (p.Lower != nil && v < p.Lower[i]) || (p.Upper != nil && v > p.Upper[i]) {
+							t.Errorf("%s %s numJac=%t: solution out of bounds: %v", test.name, m.name, numJac, res.X)
+						}
+					}
+				}
+				want := make([]float64, p.Residuals)
+				p.Func(want, res.X)
+				if !floats.Equal(res.Residuals, want) {
+					t.Errorf("%s %s numJac=%t: residuals mismatch", test.name, m.name, numJac)
+				}
+				if numJac && res.FuncEvaluations < (len(test.x0)+1)*res.GradEvaluations {
+					t.Errorf("%s %s numJac=%t: finite difference evaluations not counted", test.name, m.name, numJac)
+				}
+			}
+		}
+	}
+}
+
+func TestLeastSquaresLimits(t *testing.T) {
+	t.Parallel()
+	for _, m := range lsqMethods {
+		res, err := LeastSquares(rosenbrockResiduals(), []float64{-1.2, 1}, m.method, &LeastSquaresSettings{MajorIterations: 2})
+		if err == nil || res.Status != IterationLimit {
+			t.Errorf("%s: unexpected status with iteration limit: %v", m.name, res.Status)
+		}
+		if res.MajorIterations != 2 {
+			t.Errorf("%s: unexpected number of iterations: got %d, want 2", m.name, res.MajorIterations)
+		}
+
+		p := rosenbrockResiduals()
+		p.Jac = nil
+		res, err = LeastSquares(p, []float64{-1.2, 1}, m.method, &LeastSquaresSettings{FuncEvaluations: 10})
+		if err == nil || res.Status != FunctionEvaluationLimit {
+			t.Errorf("%s: unexpected status with evaluation limit: %v", m.name, res.Status)
+		}
+		if res.FuncEvaluations > 10 {
+			t.Errorf("%s: evaluation limit exceeded: %d", m.name, res.FuncEvaluations)
+		}
+	}
+
+	_, err := LeastSquares(LeastSquaresProblem{
+		Func:      func(dst, x []float64) { dst[0] = math.NaN() },
+		Residuals: 1,
+	}, []float64{1}, nil, nil)
+	if _, ok := err.(ErrFunc); !ok {
+		t.Errorf("unexpected error for NaN residuals: %v", err)
+	}
+}
+
+func TestLeastSquaresCovariance(t *testing.T) {
+	t.Parallel()
+	// For the linear model y = a + b t the covariance of the parameters
+	// is s^2 (XᵀX)^{-1}, where X is the design matrix.
+	ts := []float64{0, 1, 2, 3, 4, 5}
+	ys := []float64{1.1, 2.9, 5.2, 6.8, 9.1, 11.0}
+	p := LeastSquaresProblem{
+		Func: func(dst, x []float64) {
+			for i, t := range ts {
+				dst[i] = x[0] + x[1]*t - ys[i]
+			}
+		},
+		Residuals: len(ts),
+	}
+	res, err := LeastSquares(p, []float64{0, 0}, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cov mat.SymDense
+	err = res.CovarianceTo(&cov)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	x := mat.NewDense(len(ts), 2, nil)
+	for i, t := range ts {
+		x.Set(i, 0, 1)
+		x.Set(i, 1, t)
+	}
+	var xtx, want mat.Dense
+	xtx.Mul(x.T(), x)
+	err = want.Inverse(&xtx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s2 := floats.Dot(res.Residuals, res.Residuals) / float64(len(ts)-2)
+	want.Scale(s2, &want)
+	if !mat.EqualApprox(&cov, &want, 1e-8) {
+		t.Errorf("unexpected covariance:\ngot  %v\nwant %v", mat.Formatted(&cov), mat.Formatted(&want))
+	}
+
+	res.Jacobian = mat.NewDense(2, 2, []float64{1, 0, 0, 1})
+	if err := res.CovarianceTo(&cov); err == nil {
+		t.Errorf("expected error for too few residuals")
+	}
+}
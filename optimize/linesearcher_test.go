@@ -134,3 +134,36 @@ func testLinesearcher(t *testing.T, ls Linesearcher, decrease, curvature float64
 		}
 	}
 }
+
+func TestNonmonotoneLinesearch(t *testing.T) {
+	t.Parallel()
+	var ls nonmonotoneLinesearch
+
+	// A step is accepted if it decreases the function sufficiently relative
+	// to the largest function value at the latest major iterations.
+	ls.Init(3, -1, 1)
+	if op, step, err := ls.Iterate(2, math.NaN()); op != MajorIteration || step != 1 || err != nil {
+		t.Errorf("unexpected result of first linesearch: %v %v %v", op, step, err)
+	}
+	ls.Init(2, -1, 1)
+	if op, step, err := ls.Iterate(2.5, math.NaN()); op != MajorIteration || step != 1 || err != nil {
+		t.Errorf("nonmonotone step not accepted: %v %v %v", op, step, err)
+	}
+
+	// Without previous major iterations the linesearch is monotone, and the
+	// step is reduced to the minimizer of the interpolating quadratic.
+	ls.reset()
+	ls.Init(2, -1, 1)
+	if op, step, err := ls.Iterate(2.5, math.NaN()); op != FuncEvaluation || math.Abs(step-1.0/3) > 1e-15 || err != nil {
+		t.Errorf("unexpected result after reset: %v %v %v", op, step, err)
+	}
+
+	// Only the latest nonmonotoneMemory function values are used.
+	ls.Init(3, -1, 1)
+	for i := 0; i < nonmonotoneMemory; i++ {
+		ls.Init(1, -1, 1)
+	}
+	if op, _, err := ls.Iterate(2, math.NaN()); op != FuncEvaluation || err != nil {
+		t.Errorf("step accepted relative to a discarded function value: %v %v", op, err)
+	}
+}
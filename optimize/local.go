@@ -4,14 +4,14 @@
 
 package optimize
 
-import (
-	"math"
-
-	"gonum.org/v1/gonum/floats"
-)
+import "math"
 
 // localOptimizer is a helper type for running an optimization using a LocalMethod.
-type localOptimizer struct{}
+// If lower and upper are not nil, the gradient convergence test uses the
+// gradient projected onto the bounds.
+type localOptimizer struct {
+	lower, upper []float64
+}
 
 // run controls the optimization run for a localMethod. The calling method
 // must close the operation channel at the conclusion of the optimization. This
@@ -55,7 +55,7 @@ Loop:
 		case MajorIteration:
 			// The last operation was a MajorIteration. Check if the gradient
 			// is below the threshold.
-			if status := l.checkGradientConvergence(r.Gradient, gradThresh); status != NotTerminated {
+			if status := l.checkGradientConvergence(r.X, r.Gradient, gradThresh); status != NotTerminated {
 				l.finishMethodDone(operation, result, task)
 				return GradientThreshold, nil
 			}
@@ -66,6 +66,10 @@ Loop:
 				l.finishMethodDone(operation, result, r)
 				return Failure, err
 			}
+			if op == MethodDone {
+				l.finishMethodDone(operation, result, r)
+				return MethodConverge, nil
+			}
 			r.Op = op
 			operation <- r
 		}
@@ -109,18 +113,18 @@ func (l localOptimizer) checkStartingLocation(task Task, gradThresh float64) (St
 			return Failure, ErrGrad{Grad: v, Index: i}
 		}
 	}
-	status := l.checkGradientConvergence(task.Gradient, gradThresh)
+	status := l.checkGradientConvergence(task.X, task.Gradient, gradThresh)
 	return status, nil
 }
 
-func (localOptimizer) checkGradientConvergence(gradient []float64, gradThresh float64) Status {
+func (l localOptimizer) checkGradientConvergence(x, gradient []float64, gradThresh float64) Status {
 	if gradient == nil || math.IsNaN(gradThresh) {
 		return NotTerminated
 	}
 	if gradThresh == 0 {
 		gradThresh = defaultGradientAbsTol
 	}
-	if norm := floats.Norm(gradient, math.Inf(1)); norm < gradThresh {
+	if norm := projectedGradNorm(x, gradient, l.lower, l.upper); norm < gradThresh {
 		return GradientThreshold
 	}
 	return NotTerminated
@@ -7,6 +7,7 @@ package optimize_test
 import (
 	"fmt"
 	"log"
+	"math"
 
 	"gonum.org/v1/gonum/optimize"
 	"gonum.org/v1/gonum/optimize/functions"
@@ -36,3 +37,89 @@ func ExampleMinimize() {
 	// result.F: 4.98e-30
 	// result.Stats.FuncEvaluations: 31
 }
+
+func ExampleMinimize_bounds() {
+	// Minimize the Rosenbrock function subject to x₀ ≤ 0.5.
+	p := optimize.Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Grad:  functions.ExtendedRosenbrock{}.Grad,
+		Upper: []float64{0.5, math.Inf(1)},
+	}
+
+	x := []float64{-1.2, 1}
+	result, err := optimize.Minimize(p, x, nil, &optimize.LBFGSB{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = result.Status.Err(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X: %0.4g\n", result.X)
+	fmt.Printf("result.F: %0.4g\n", result.F)
+	// Output:
+	// result.Status: GradientThreshold
+	// result.X: [0.5 0.25]
+	// result.F: 0.25
+}
+
+func ExampleBOBYQA() {
+	// Minimize the Rosenbrock function without derivatives subject to
+	// -2 ≤ x₀ ≤ 0.5 and -2 ≤ x₁ ≤ 2.
+	p := optimize.Problem{
+		Func:  functions.ExtendedRosenbrock{}.Func,
+		Lower: []float64{-2, -2},
+		Upper: []float64{0.5, 2},
+	}
+
+	x := []float64{-1.2, 1}
+	result, err := optimize.Minimize(p, x, nil, &optimize.BOBYQA{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = result.Status.Err(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X: %0.4g\n", result.X)
+	fmt.Printf("result.F: %0.4g\n", result.F)
+	// Output:
+	// result.Status: MethodConverge
+	// result.X: [0.5 0.25]
+	// result.F: 0.25
+}
+
+func ExampleNewtonCG() {
+	// Minimize the 1000-dimensional Rosenbrock function without forming
+	// its Hessian, using products of the Hessian with vectors instead.
+	hessVec := func(dst, x, v []float64) {
+		for i := range dst {
+			dst[i] = 0
+		}
+		for i := 0; i < len(x)-1; i++ {
+			b := x[i+1] - x[i]*x[i]
+			dst[i] += (2+800*x[i]*x[i]-400*b)*v[i] - 400*x[i]*v[i+1]
+			dst[i+1] += -400*x[i]*v[i] + 200*v[i+1]
+		}
+	}
+	p := optimize.Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+	}
+
+	x := make([]float64, 1000)
+	result, err := optimize.Minimize(p, x, nil, &optimize.NewtonCG{HessVec: hessVec})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err = result.Status.Err(); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("result.Status: %v\n", result.Status)
+	fmt.Printf("result.X[:4]: %0.4g\n", result.X[:4])
+	fmt.Printf("result.F: %0.4g\n", result.F)
+	// Output:
+	// result.Status: GradientThreshold
+	// result.X[:4]: [1 1 1 1]
+	// result.F: 0
+}
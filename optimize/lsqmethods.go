@@ -0,0 +1,231 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ LeastSquaresMethod = LevenbergMarquardt{}
+	_ LeastSquaresMethod = Dogleg{}
+	_ LeastSquaresMethod = GaussNewton{}
+)
+
+// LevenbergMarquardt is the Levenberg-Marquardt method for nonlinear least
+// squares. Each iteration solves the damped linear least squares problem
+//  minimize ||J p + r||^2 + λ ||D p||^2
+// by QR factorization, where the diagonal scaling D holds the largest norms
+// of the columns of the Jacobian seen so far. The damping parameter λ is
+// decreased after successful steps and increased after unsuccessful ones.
+// Bounds are handled by projecting each step onto the feasible region and
+// holding variables that are at a bound and would move out of it.
+//
+// The damping strategy is described in
+//  H. B. Nielsen, "Damping parameter in Marquardt's method",
+//  Technical Report IMM-REP-1999-05, Technical University of Denmark, 1999.
+type LevenbergMarquardt struct {
+	// InitialDamping is the initial value of the damping parameter λ.
+	// If InitialDamping is zero, a default of 1e-3 is used.
+	InitialDamping float64
+}
+
+func (lm LevenbergMarquardt) solveLeastSquares(s *lsqState) {
+	mu := lm.InitialDamping
+	if mu == 0 {
+		mu = 1e-3
+	}
+	nu := 2.0
+	n := len(s.x)
+	d := make([]float64, n)
+	p := make([]float64, n)
+	xt := make([]float64, n)
+	rt := make([]float64, len(s.r))
+	for {
+		if s.iterate() {
+			return
+		}
+		for j := range d {
+			d[j] = math.Max(d[j], mat.Norm(s.jac.ColView(j), 2))
+			if d[j] == 0 {
+				d[j] = 1
+			}
+		}
+		if !s.step(p, mu, d) {
+			s.result.Status = Failure
+			return
+		}
+		pred := s.trial(xt, p)
+		ct, done := s.residuals(rt, xt)
+		if done {
+			return
+		}
+		if ct < s.cost {
+			rho := (s.cost - ct) / pred
+			if s.accept(xt, rt, ct, p, pred) {
+				return
+			}
+			mu *= math.Max(1.0/3, 1-math.Pow(2*rho-1, 3))
+			nu = 2
+			continue
+		}
+		if s.stepConverged(p) {
+			return
+		}
+		mu *= nu
+		nu *= 2
+	}
+}
+
+// Dogleg is Powell's dogleg trust region method for nonlinear least squares.
+// Each iteration takes the Gauss-Newton step if it lies within the trust
+// region, and otherwise a step along the dogleg path from the Cauchy point
+// in the steepest descent direction towards the Gauss-Newton step. Bounds
+// are handled by projecting each step onto the feasible region and holding
+// variables that are at a bound and would move out of it.
+//
+// The method is described in
+//  J. Nocedal and S. J. Wright, "Numerical Optimization", 2nd ed.,
+//  section 4.1. Springer, 2006.
+type Dogleg struct {
+	// InitialRadius is the initial radius of the trust region. If
+	// InitialRadius is zero, max(1, ||x0||) is used.
+	InitialRadius float64
+}
+
+func (dl Dogleg) solveLeastSquares(s *lsqState) {
+	delta := dl.InitialRadius
+	if delta == 0 {
+		delta = math.Max(1, floats.Norm(s.x, 2))
+	}
+	n := len(s.x)
+	g := make([]float64, n)
+	pgn := make([]float64, n)
+	p := make([]float64, n)
+	xt := make([]float64, n)
+	rt := make([]float64, len(s.r))
+	for {
+		if s.iterate() {
+			return
+		}
+		s.freeGrad(g)
+		ok := s.gaussNewton(pgn)
+		doglegStep(p, pgn, ok, g, s.jac, delta)
+		pred := s.trial(xt, p)
+		ct, done := s.residuals(rt, xt)
+		if done {
+			return
+		}
+		rho := -1.0
+		if pred > 0 {
+			rho = (s.cost - ct) / pred
+		}
+		norm := floats.Norm(p, 2)
+		switch {
+		case rho < 0.25:
+			delta = norm / 4
+		case rho > 0.75 && norm >= 0.99*delta:
+			delta *= 2
+		}
+		if rho > 1e-4 && ct < s.cost {
+			if s.accept(xt, rt, ct, p, pred) {
+				return
+			}
+			continue
+		}
+		if s.stepConverged(p) {
+			return
+		}
+	}
+}
+
+// doglegStep stores into dst the minimizer of the model ½ ||J p + r||^2
+// along the dogleg path within a trust region of radius delta, where g is
+// the gradient Jᵀr of the model at p = 0 and pgn the Gauss-Newton step.
+// If haveGN is false, the step along the steepest descent direction is used.
+func doglegStep(dst, pgn []float64, haveGN bool, g []float64, j mat.Matrix, delta float64) {
+	if haveGN && floats.Norm(pgn, 2) <= delta {
+		copy(dst, pgn)
+		return
+	}
+	gnorm := floats.Norm(g, 2)
+	if gnorm == 0 {
+		for i := range dst {
+			dst[i] = 0
+		}
+		return
+	}
+	var jg mat.VecDense
+	jg.MulVec(j, mat.NewVecDense(len(g), g))
+	jgnorm := mat.Norm(&jg, 2)
+	// The Cauchy point minimizes the model along -g.
+	alpha := math.Inf(1)
+	if jgnorm > 0 {
+		alpha = gnorm * gnorm / (jgnorm * jgnorm)
+	}
+	if !haveGN || alpha*gnorm >= delta {
+		floats.ScaleTo(dst, -math.Min(alpha, delta/gnorm), g)
+		return
+	}
+	// Find τ in [0, 1] such that ||pc + τ(pgn - pc)|| = delta.
+	floats.ScaleTo(dst, -alpha, g)
+	diff := make([]float64, len(dst))
+	floats.SubTo(diff, pgn, dst)
+	a := floats.Dot(diff, diff)
+	b := 2 * floats.Dot(dst, diff)
+	c := floats.Dot(dst, dst) - delta*delta
+	tau := (-b + math.Sqrt(b*b-4*a*c)) / (2 * a)
+	floats.AddScaled(dst, tau, diff)
+}
+
+// GaussNewton is the Gauss-Newton method for nonlinear least squares with a
+// backtracking line search. Each iteration solves the linear least squares
+// problem
+//  minimize ||J p + r||^2
+// by QR factorization and halves the step until the cost decreases
+// sufficiently. GaussNewton converges quickly when the residuals at the
+// solution are small, but may fail on problems with large residuals,
+// for which LevenbergMarquardt or Dogleg should be preferred. Bounds are
+// handled by projecting the points along the search direction onto the
+// feasible region.
+type GaussNewton struct{}
+
+func (GaussNewton) solveLeastSquares(s *lsqState) {
+	const decrease = 1e-4
+	n := len(s.x)
+	dir := make([]float64, n)
+	p := make([]float64, n)
+	xt := make([]float64, n)
+	rt := make([]float64, len(s.r))
+	for {
+		if s.iterate() {
+			return
+		}
+		if !s.gaussNewton(dir) {
+			s.result.Status = Failure
+			return
+		}
+		for t := 1.0; ; t /= 2 {
+			floats.ScaleTo(p, t, dir)
+			pred := s.trial(xt, p)
+			ct, done := s.residuals(rt, xt)
+			if done {
+				return
+			}
+			if ct < s.cost && ct <= s.cost+decrease*math.Min(floats.Dot(s.grad, p), 0) {
+				if s.accept(xt, rt, ct, p, pred) {
+					return
+				}
+				break
+			}
+			if s.stepConverged(p) {
+				return
+			}
+		}
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"math"
 	"time"
 
-	"gonum.org/v1/gonum/floats"
 	"gonum.org/v1/gonum/mat"
 )
 
@@ -148,6 +147,14 @@ type Method interface {
 // Minimize panics if the Problem is not consistent with the Method (Uses
 // returns an error).
 //
+// If p.Lower or p.Upper is not nil, the minimum is sought within the bounds
+// on the variables, and method must be a BoundedMethod or Minimize will panic.
+// The default method for a bounded problem is LBFGSB if the gradient is
+// available and NelderMead otherwise. An initial location outside the bounds
+// is projected onto them, unless settings.InitValues is specified, in which
+// case Minimize panics. The GradientThreshold convergence test uses the
+// gradient projected onto the bounds.
+//
 // Minimize returns a Result struct and any error that occurred. See the
 // documentation of Result for more information.
 //
@@ -171,15 +178,27 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 	if err != nil {
 		return nil, err
 	}
+	lower, upper := problemBounds(&p, dim)
+	if lower != nil {
+		if _, ok := method.(BoundedMethod); !ok {
+			panic("optimize: method does not support bounds")
+		}
+	}
 
 	optLoc := newLocation(dim) // This must have an allocated X field.
 	optLoc.F = math.Inf(1)
 
 	initOp, initLoc := getInitLocation(dim, initX, settings.InitValues)
+	if projectBounds(initLoc.X, lower, upper) && settings.InitValues != nil {
+		panic("optimize: initial location outside bounds")
+	}
 
 	converger := settings.Converger
 	if converger == nil {
 		converger = defaultFunctionConverge()
+		if d, ok := method.(defaultConverger); ok {
+			converger = d.defaultConverger()
+		}
 	}
 	converger.Init(dim)
 
@@ -195,7 +214,7 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 
 	// Run optimization
 	var status Status
-	status, err = minimize(&p, method, settings, converger, stats, initOp, initLoc, optLoc, startTime)
+	status, err = minimize(&p, method, settings, converger, stats, initOp, initLoc, optLoc, lower, upper, startTime)
 
 	// Cleanup and collect results
 	if settings.Recorder != nil && err == nil {
@@ -211,14 +230,18 @@ func Minimize(p Problem, initX []float64, settings *Settings, method Method) (*R
 
 func getDefaultMethod(p *Problem) Method {
 	if p.Grad != nil {
+		if p.Lower != nil || p.Upper != nil {
+			return &LBFGSB{}
+		}
 		return &LBFGS{}
 	}
 	return &NelderMead{}
 }
 
 // minimize performs an optimization. minimize updates the settings and optLoc,
-// and returns the final Status and error.
-func minimize(prob *Problem, method Method, settings *Settings, converger Converger, stats *Stats, initOp Operation, initLoc, optLoc *Location, startTime time.Time) (Status, error) {
+// and returns the final Status and error. lower and upper are the bounds on
+// the variables, or nil if the problem is unbounded.
+func minimize(prob *Problem, method Method, settings *Settings, converger Converger, stats *Stats, initOp Operation, initLoc, optLoc *Location, lower, upper []float64, startTime time.Time) (Status, error) {
 	dim := len(optLoc.X)
 	nTasks := settings.Concurrent
 	if nTasks == 0 {
@@ -229,6 +252,9 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 	if initErr != nil {
 		panic(fmt.Sprintf("optimize: specified method inconsistent with Problem: %v", initErr))
 	}
+	if bm, ok := method.(BoundedMethod); ok {
+		bm.InitBounds(lower, upper)
+	}
 	newNTasks := method.Init(dim, nTasks)
 	if newNTasks > nTasks {
 		panic("optimize: too many tasks returned by Method")
@@ -356,7 +382,7 @@ func minimize(prob *Problem, method Method, settings *Settings, converger Conver
 		case NoOperation:
 			// Just send the task back.
 		case MajorIteration:
-			status = performMajorIteration(optLoc, task.Location, stats, converger, startTime, settings)
+			status = performMajorIteration(optLoc, task.Location, stats, converger, startTime, settings, lower, upper)
 		case MethodDone:
 			methodDone = true
 			status = MethodConverge
@@ -532,12 +558,13 @@ func updateEvaluationStats(stats *Stats, op Operation) {
 // the convergence criteria given by settings. Otherwise a corresponding status is
 // returned.
 // Unlike checkLimits, checkConvergence is called only at MajorIterations.
-func checkLocationConvergence(loc *Location, settings *Settings, converger Converger) Status {
+// If lower and upper are not nil, the gradient is projected onto the bounds.
+func checkLocationConvergence(loc *Location, settings *Settings, converger Converger, lower, upper []float64) Status {
 	if math.IsInf(loc.F, -1) {
 		return FunctionNegativeInfinity
 	}
 	if loc.Gradient != nil && settings.GradientThreshold > 0 {
-		norm := floats.Norm(loc.Gradient, math.Inf(1))
+		norm := projectedGradNorm(loc.X, loc.Gradient, lower, upper)
 		if norm < settings.GradientThreshold {
 			return GradientThreshold
 		}
@@ -581,7 +608,7 @@ func checkIterationLimits(loc *Location, stats *Stats, settings *Settings) Statu
 // performMajorIteration does all of the steps needed to perform a MajorIteration.
 // It increments the iteration count, updates the optimal location, and checks
 // the necessary convergence criteria.
-func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Converger, startTime time.Time, settings *Settings) Status {
+func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Converger, startTime time.Time, settings *Settings, lower, upper []float64) Status {
 	optLoc.F = loc.F
 	copy(optLoc.X, loc.X)
 	if loc.Gradient == nil {
@@ -594,7 +621,7 @@ func performMajorIteration(optLoc, loc *Location, stats *Stats, converger Conver
 	}
 	stats.MajorIterations++
 	stats.Runtime = time.Since(startTime)
-	status := checkLocationConvergence(optLoc, settings, converger)
+	status := checkLocationConvergence(optLoc, settings, converger, lower, upper)
 	if status != NotTerminated {
 		return status
 	}
@@ -0,0 +1,228 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ TrustRegionSolver = (*MoreSorensen)(nil)
+
+// MoreSorensen solves the trust-region subproblem nearly exactly by the
+// method described in
+//  Moré, J. J., Sorensen, D. C.: Computing a trust region step. SIAM
+//  Journal on Scientific and Statistical Computing 4, 553–572 (1983)
+// The solution of the subproblem is p(λ) = -(H + λI)⁻¹g for λ ≥ 0 such
+// that H + λI is positive semidefinite and λ(radius - ‖p(λ)‖) = 0.
+// MoreSorensen finds λ by safeguarded Newton iterations on the equation
+//  1/‖p(λ)‖ = 1/radius
+// with a Cholesky factorization of H + λI in every iteration. In the hard
+// case, when g is orthogonal to the eigenvectors of the smallest eigenvalue
+// of an indefinite H, the step is completed along an eigenvector to the
+// boundary of the trust region, which is computed from the eigendecomposition
+// of H.
+//
+// Each iteration costs O(n³) operations for n dimensions, so MoreSorensen
+// is suitable for problems of moderate size where the number of function
+// evaluations matters more than the cost of the steps.
+type MoreSorensen struct {
+	// Tolerance is the relative accuracy of the norm of the steps on the
+	// boundary of the trust region. If Tolerance is 0, a default value of
+	// 0.01 is used. Tolerance must be in [0, 1), or MoreSorensen will panic.
+	Tolerance float64
+	// MaxIterations is the maximum number of Newton iterations. If
+	// MaxIterations is 0, a default value of 50 is used.
+	MaxIterations int
+
+	shifted *mat.SymDense
+	chol    mat.Cholesky
+	eigen   mat.EigenSym
+	vecs    mat.Dense
+	work    []float64
+}
+
+func (ms *MoreSorensen) Solve(step, grad []float64, hess *mat.SymDense, radius float64) {
+	tol := ms.Tolerance
+	switch {
+	case tol == 0:
+		tol = 0.01
+	case tol < 0 || tol >= 1:
+		panic("more sorensen: tolerance out of range")
+	}
+	maxIter := ms.MaxIterations
+	if maxIter == 0 {
+		maxIter = 50
+	}
+	dim := len(grad)
+	ms.shifted = resizeSymDense(ms.shifted, dim)
+	ms.work = resize(ms.work, dim)
+	p := mat.NewVecDense(dim, step)
+	g := mat.NewVecDense(dim, grad)
+	w := mat.NewVecDense(dim, ms.work)
+
+	// Bound λ using the Gershgorin bounds on the eigenvalues of H.
+	gNorm := floats.Norm(grad, 2)
+	var hNorm float64
+	minDiag := math.Inf(1)
+	minGersh := math.Inf(1)
+	for i := 0; i < dim; i++ {
+		var off float64
+		for j := 0; j < dim; j++ {
+			if j != i {
+				off += math.Abs(hess.At(i, j))
+			}
+		}
+		d := hess.At(i, i)
+		hNorm = math.Max(hNorm, math.Abs(d)+off)
+		minDiag = math.Min(minDiag, d)
+		minGersh = math.Min(minGersh, d-off)
+	}
+	lower := math.Max(0, math.Max(-minDiag, gNorm/radius-hNorm))
+	upper := math.Max(0, gNorm/radius+math.Min(hNorm, -minGersh))
+	if upper <= lower {
+		upper = lower + math.Max(hNorm, 1)
+	}
+
+	lambda := lower
+	for iter := 0; iter < maxIter; iter++ {
+		// The solutions of ill-conditioned systems are accurate enough for
+		// the iterations, so Condition errors are ignored.
+		if !ms.factorize(hess, lambda) {
+			// H + λI is not positive definite.
+			lower = math.Max(lower, lambda)
+			lambda = safeguardLambda(lower, upper)
+			continue
+		}
+		ms.chol.SolveVecTo(p, g)
+		p.ScaleVec(-1, p)
+		pNorm := floats.Norm(step, 2)
+		if math.Abs(pNorm-radius) <= tol*radius || (lambda == 0 && pNorm <= radius) {
+			if pNorm > radius {
+				floats.Scale(radius/pNorm, step)
+			}
+			return
+		}
+		if pNorm < radius {
+			upper = lambda
+		} else {
+			lower = lambda
+		}
+		if upper-lower <= 1e-12*upper {
+			break
+		}
+
+		// Newton step for 1/‖p(λ)‖ = 1/radius, using
+		//  d‖p(λ)‖/dλ = -pᵀ(H + λI)⁻¹p / ‖p(λ)‖
+		ms.chol.SolveVecTo(w, p)
+		next := lambda + (pNorm*pNorm/floats.Dot(step, ms.work))*(pNorm-radius)/radius
+		if next <= lower || next >= upper {
+			next = safeguardLambda(lower, upper)
+		}
+		lambda = next
+	}
+	ms.solveEigen(step, grad, hess, radius)
+}
+
+// factorize computes the Cholesky factorization of hess + lambda*I and
+// returns whether it is positive definite.
+func (ms *MoreSorensen) factorize(hess *mat.SymDense, lambda float64) bool {
+	ms.shifted.CopySym(hess)
+	for i := 0; i < hess.Symmetric(); i++ {
+		ms.shifted.SetSym(i, i, hess.At(i, i)+lambda)
+	}
+	return ms.chol.Factorize(ms.shifted)
+}
+
+// safeguardLambda returns a trial λ in the interval (lower, upper).
+func safeguardLambda(lower, upper float64) float64 {
+	return math.Max(math.Sqrt(lower*upper), lower+0.001*(upper-lower))
+}
+
+// solveEigen solves the subproblem using the eigendecomposition of hess. It
+// is used in the hard case and if the Newton iterations fail to converge.
+func (ms *MoreSorensen) solveEigen(step, grad []float64, hess *mat.SymDense, radius float64) {
+	dim := len(grad)
+	if !ms.eigen.Factorize(hess, true) {
+		// Fall back to the minimizer along the negative gradient.
+		gNorm := floats.Norm(grad, 2)
+		if gNorm == 0 {
+			for i := range step {
+				step[i] = 0
+			}
+			return
+		}
+		gv := mat.NewVecDense(dim, grad)
+		t := radius / gNorm
+		if gHg := mat.Inner(gv, hess, gv); gHg > 0 {
+			t = math.Min(t, gNorm*gNorm/gHg)
+		}
+		floats.ScaleTo(step, -t, grad)
+		return
+	}
+	vals := ms.eigen.Values(nil)
+	ms.eigen.VectorsTo(&ms.vecs)
+
+	// Components of the gradient in the eigenbasis.
+	gq := make([]float64, dim)
+	mat.NewVecDense(dim, gq).MulVec(ms.vecs.T(), mat.NewVecDense(dim, grad))
+
+	// The eigenvalues are in ascending order.
+	scale := math.Max(math.Abs(vals[0]), math.Abs(vals[dim-1]))
+
+	// pNorm returns ‖p(λ)‖, which is infinite if λ_i + λ is not positive
+	// for a component of the gradient that is not negligible.
+	gNorm := floats.Norm(grad, 2)
+	pNorm := func(lambda float64) float64 {
+		var s float64
+		for i, v := range vals {
+			d := v + lambda
+			switch {
+			case d > 1e-12*scale:
+				s += (gq[i] / d) * (gq[i] / d)
+			case math.Abs(gq[i]) > 1e-8*gNorm:
+				return math.Inf(1)
+			}
+		}
+		return math.Sqrt(s)
+	}
+	// In the hard case, the gradient has no components along the
+	// eigenvectors of the smallest eigenvalue, and the step for λ = -λ_1 is
+	// inside the trust region.
+	lo := math.Max(0, -vals[0])
+	lambda := lo
+	hardCase := vals[0] <= 0 && pNorm(lo) <= radius
+	if !hardCase && (lo > 0 || pNorm(0) > radius) {
+		// Find the root of ‖p(λ)‖ = radius, which is decreasing in λ, by
+		// bisection.
+		hi := lo + 1
+		for pNorm(hi) > radius {
+			hi = lo + 2*(hi-lo)
+		}
+		for i := 0; i < 200 && hi-lo > 1e-15*hi; i++ {
+			mid := lo + (hi-lo)/2
+			if pNorm(mid) > radius {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+		lambda = hi
+	}
+	for i := range ms.work {
+		ms.work[i] = 0
+		if d := vals[i] + lambda; d > 1e-12*scale {
+			ms.work[i] = -gq[i] / d
+		}
+	}
+	if hardCase {
+		// Complete the step along the eigenvector of the smallest
+		// eigenvalue to the boundary.
+		ms.work[0] = math.Sqrt(math.Max(radius*radius-floats.Dot(ms.work, ms.work), 0))
+	}
+	mat.NewVecDense(dim, step).MulVec(&ms.vecs, mat.NewVecDense(dim, ms.work))
+}
@@ -42,7 +42,10 @@ func (n nmVertexSorter) Swap(i, j int) {
 	n.vertices[i], n.vertices[j] = n.vertices[j], n.vertices[i]
 }
 
-var _ Method = (*NelderMead)(nil)
+var (
+	_ Method        = (*NelderMead)(nil)
+	_ BoundedMethod = (*NelderMead)(nil)
+)
 
 // NelderMead is an implementation of the Nelder-Mead simplex algorithm for
 // gradient-free nonlinear optimization (not to be confused with Danzig's
@@ -61,6 +64,12 @@ var _ Method = (*NelderMead)(nil)
 // the recommendations in
 //
 //  http://www.webpages.uidaho.edu/~fuchang/res/ANMS.pdf
+//
+// If the problem has bounds on the variables, the trial points of the
+// reflection, expansion and contraction steps are projected onto the bounds,
+// and the automatically constructed initial simplex steps away from any upper
+// bound that would be exceeded. A provided initial simplex must be within the
+// bounds.
 type NelderMead struct {
 	InitialVertices [][]float64
 	InitialValues   []float64
@@ -73,6 +82,8 @@ type NelderMead struct {
 	status Status
 	err    error
 
+	lower, upper []float64 // Bounds from InitBounds, nil if unbounded.
+
 	reflection  float64
 	expansion   float64
 	contraction float64
@@ -96,6 +107,11 @@ func (*NelderMead) Uses(has Available) (uses Available, err error) {
 	return has.function()
 }
 
+func (n *NelderMead) InitBounds(lower, upper []float64) {
+	n.lower = lower
+	n.upper = upper
+}
+
 func (n *NelderMead) Init(dim, tasks int) int {
 	n.status = NotTerminated
 	n.err = nil
@@ -164,6 +180,9 @@ func (n *NelderMead) initLocal(loc *Location) (Operation, error) {
 			if len(n.InitialVertices[i]) != dim {
 				panic("neldermead: vertex size mismatch")
 			}
+			if n.outside(n.InitialVertices[i]) {
+				panic("neldermead: initial vertex outside bounds")
+			}
 			copy(n.vertices[i], n.InitialVertices[i])
 		}
 		copy(n.values, n.InitialValues)
@@ -177,7 +196,7 @@ func (n *NelderMead) initLocal(loc *Location) (Operation, error) {
 	copy(n.vertices[dim], loc.X)
 	n.values[dim] = loc.F
 	n.fillIdx = 0
-	loc.X[n.fillIdx] += n.SimplexSize
+	n.stepVertex(loc.X, n.fillIdx)
 	n.lastIter = nmInitialize
 	return FuncEvaluation, nil
 }
@@ -214,7 +233,7 @@ func (n *NelderMead) iterateLocal(loc *Location) (Operation, error) {
 			return n.returnNext(nmMajor, loc)
 		}
 		copy(loc.X, n.vertices[dim])
-		loc.X[n.fillIdx] += n.SimplexSize
+		n.stepVertex(loc.X, n.fillIdx)
 		return FuncEvaluation, nil
 	case nmMajor:
 		// Nelder Mead iterations start with Reflection step
@@ -297,6 +316,7 @@ func (n *NelderMead) returnNext(iter nmIterType, loc *Location) (Operation, erro
 		floats.SubTo(loc.X, n.centroid, n.vertices[dim])
 		floats.Scale(scale, loc.X)
 		floats.Add(loc.X, n.centroid)
+		projectBounds(loc.X, n.lower, n.upper)
 		if iter == nmReflected {
 			copy(n.reflectedPoint, loc.X)
 		}
@@ -312,6 +332,31 @@ func (n *NelderMead) returnNext(iter nmIterType, loc *Location) (Operation, erro
 	}
 }
 
+// stepVertex moves x by SimplexSize along dimension i to construct a vertex
+// of the initial simplex, stepping in the negative direction if the upper
+// bound would be exceeded.
+func (n *NelderMead) stepVertex(x []float64, i int) {
+	if n.upper != nil && x[i]+n.SimplexSize > n.upper[i] {
+		x[i] -= n.SimplexSize
+		projectBounds(x, n.lower, n.upper)
+		return
+	}
+	x[i] += n.SimplexSize
+}
+
+// outside returns whether x is outside the bounds.
+func (n *NelderMead) outside(x []float64) bool {
+	if n.lower == nil {
+		return false
+	}
+	for i, v := range x {
+		if v < n.lower[i] || n.upper[i] < v {
+			return true
+		}
+	}
+	return false
+}
+
 // replaceWorst removes the worst location in the simplex and adds the new
 // {x, f} pair maintaining sorting.
 func (n *NelderMead) replaceWorst(x []float64, f float64) {
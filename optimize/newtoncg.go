@@ -0,0 +1,124 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method          = (*NewtonCG)(nil)
+	_ localMethod     = (*NewtonCG)(nil)
+	_ NextDirectioner = (*NewtonCG)(nil)
+)
+
+// NewtonCG implements the line-search Newton-CG method, also known as the
+// truncated Newton method, for unconstrained minimization, described in
+// section 7.1 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+// The search direction is an approximate solution of the Newton equations
+//  H_k d_k = -∇f_k
+// computed by the conjugate gradient method, which is terminated early when
+// the residual is small relative to the gradient or when a direction of
+// negative curvature is found. The conjugate gradient method only needs
+// products of the Hessian with vectors, which can be computed by HessVec
+// without forming the Hessian.
+type NewtonCG struct {
+	// HessVec stores in dst the product of the Hessian at x with v. HessVec
+	// must not modify x or v. The calls to HessVec are not counted in the
+	// Stats of the optimization. If HessVec is nil, the Hessian of the
+	// problem is evaluated and multiplied with v, so Problem.Hess must not
+	// be nil.
+	HessVec func(dst, x, v []float64)
+	// Linesearcher is used for selecting suitable steps along the descent
+	// direction d. Accepted steps should satisfy at least one of the Wolfe,
+	// Goldstein or Armijo conditions.
+	// If Linesearcher == nil, an appropriate default is chosen.
+	Linesearcher Linesearcher
+	// MaxIterations is the maximum number of conjugate gradient iterations
+	// for each search direction. If MaxIterations is 0, it is defaulted to
+	// ten times the dimension of the problem.
+	MaxIterations int
+	// GradStopThreshold sets the threshold for stopping if the gradient norm
+	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
+	// if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	ls *LinesearchMethod
+	cg truncatedCG
+}
+
+func (n *NewtonCG) Status() (Status, error) {
+	return n.status, n.err
+}
+
+func (n *NewtonCG) Uses(has Available) (uses Available, err error) {
+	if n.HessVec != nil {
+		return has.gradient()
+	}
+	return has.hessian()
+}
+
+func (n *NewtonCG) Init(dim, tasks int) int {
+	n.status = NotTerminated
+	n.err = nil
+	return 1
+}
+
+func (n *NewtonCG) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	n.status, n.err = localOptimizer{}.run(n, n.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (n *NewtonCG) initLocal(loc *Location) (Operation, error) {
+	if n.Linesearcher == nil {
+		n.Linesearcher = &Bisection{}
+	}
+	if n.ls == nil {
+		n.ls = &LinesearchMethod{}
+	}
+	n.ls.Linesearcher = n.Linesearcher
+	n.ls.NextDirectioner = n
+	return n.ls.Init(loc)
+}
+
+func (n *NewtonCG) iterateLocal(loc *Location) (Operation, error) {
+	return n.ls.Iterate(loc)
+}
+
+func (n *NewtonCG) InitDirection(loc *Location, dir []float64) (stepSize float64) {
+	return n.NextDirection(loc, dir)
+}
+
+func (n *NewtonCG) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	var hessVec func(dst, v []float64)
+	if n.HessVec != nil {
+		hessVec = func(dst, v []float64) {
+			n.HessVec(dst, loc.X, v)
+		}
+	} else {
+		hessVec = func(dst, v []float64) {
+			d := mat.NewVecDense(len(dst), dst)
+			d.MulVec(loc.Hessian, mat.NewVecDense(len(v), v))
+		}
+	}
+	n.cg.solve(dir, loc.Gradient, hessVec, math.Inf(1), n.MaxIterations)
+	return 1
+}
+
+func (n *NewtonCG) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, n.HessVec == nil}
+}
@@ -0,0 +1,82 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ TrustRegionSolver = (*NewtonDogleg)(nil)
+
+// NewtonDogleg solves the trust-region subproblem approximately by the
+// dogleg method, described in section 4.1 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+// The step is the point where the path from 0 to the minimizer of the model
+// along the negative gradient, and from there to the Newton step -H⁻¹g,
+// leaves the trust region, or the Newton step if it lies within the trust
+// region. It is the same path as that of the Dogleg method for nonlinear
+// least squares, with the Newton step in place of the Gauss-Newton step.
+//
+// The dogleg path requires a positive definite Hessian. If the Cholesky
+// factorization of the Hessian fails, NewtonDogleg returns the Cauchy point,
+// the minimizer of the model along the negative gradient within the trust
+// region.
+type NewtonDogleg struct {
+	chol   mat.Cholesky
+	newton []float64
+	cauchy []float64
+}
+
+func (dl *NewtonDogleg) Solve(step, grad []float64, hess *mat.SymDense, radius float64) {
+	dim := len(grad)
+	dl.cauchy = resize(dl.cauchy, dim)
+	dl.newton = resize(dl.newton, dim)
+
+	gNorm := floats.Norm(grad, 2)
+	if gNorm == 0 {
+		for i := range step {
+			step[i] = 0
+		}
+		return
+	}
+	gv := mat.NewVecDense(dim, grad)
+	gHg := mat.Inner(gv, hess, gv)
+
+	// Minimize the model along the negative gradient.
+	if gHg <= 0 || gNorm*gNorm*gNorm >= radius*gHg {
+		// The minimizer along the negative gradient is outside the trust
+		// region.
+		floats.ScaleTo(step, -radius/gNorm, grad)
+		return
+	}
+	floats.ScaleTo(dl.cauchy, -gNorm*gNorm/gHg, grad)
+
+	if !dl.chol.Factorize(hess) {
+		copy(step, dl.cauchy)
+		return
+	}
+	nv := mat.NewVecDense(dim, dl.newton)
+	// An ill-conditioned Hessian still gives a useful Newton step.
+	if err := dl.chol.SolveVecTo(nv, gv); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			copy(step, dl.cauchy)
+			return
+		}
+	}
+	floats.Scale(-1, dl.newton)
+	if floats.Norm(dl.newton, 2) <= radius {
+		copy(step, dl.newton)
+		return
+	}
+
+	// Follow the second leg of the path from the minimizer along the
+	// negative gradient towards the Newton step to the boundary.
+	floats.SubTo(step, dl.newton, dl.cauchy)
+	tau := math.Min(boundaryStep(dl.cauchy, step, radius), 1)
+	floats.AddScaledTo(step, dl.cauchy, tau, step)
+}
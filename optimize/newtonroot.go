@@ -0,0 +1,95 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+)
+
+// NewtonRoot finds a root of f by Newton's method starting from x0. The
+// derivative of f is computed by forward-mode automatic differentiation,
+// so f must be written in terms of the functions of the num/dual package.
+// If settings is nil, the defaults described for ScalarSettings are used.
+//
+// Each evaluation of f is counted as a function and a gradient evaluation.
+// NewtonRoot returns ErrZeroDerivative if it reaches a point where the
+// derivative is zero, and otherwise an error if the search ended early as
+// indicated by Status.Err.
+func NewtonRoot(f func(x dual.Number) dual.Number, x0 float64, settings *ScalarSettings) (*ScalarResult, error) {
+	if f == nil {
+		panic(badProblem)
+	}
+	return newtonRoot(func(x float64) (fx, step float64) {
+		v := f(dual.Number{Real: x, Emag: 1})
+		return v.Real, v.Real / v.Emag
+	}, x0, settings, false)
+}
+
+// HalleyRoot finds a root of f by Halley's method starting from x0. The
+// first and second derivatives of f are computed by forward-mode automatic
+// differentiation, so f must be written in terms of the functions of the
+// num/hyperdual package. Halley's method converges cubically to simple
+// roots. If settings is nil, the defaults described for ScalarSettings are
+// used.
+//
+// Each evaluation of f is counted as a function, a gradient and a Hessian
+// evaluation. HalleyRoot returns ErrZeroDerivative if it reaches a point
+// where the derivative is zero, and otherwise an error if the search ended
+// early as indicated by Status.Err.
+func HalleyRoot(f func(x hyperdual.Number) hyperdual.Number, x0 float64, settings *ScalarSettings) (*ScalarResult, error) {
+	if f == nil {
+		panic(badProblem)
+	}
+	return newtonRoot(func(x float64) (fx, step float64) {
+		v := f(hyperdual.Number{Real: x, E1mag: 1, E2mag: 1})
+		d1, d2 := v.E1mag, v.E1E2mag
+		if d1 == 0 {
+			return v.Real, math.Inf(1)
+		}
+		return v.Real, 2 * v.Real * d1 / (2*d1*d1 - v.Real*d2)
+	}, x0, settings, true)
+}
+
+// newtonRoot iterates x -= step from x0, where eval returns the function
+// value and the step at x.
+func newtonRoot(eval func(x float64) (fx, step float64), x0 float64, settings *ScalarSettings, hess bool) (*ScalarResult, error) {
+	s := newScalarSearch(nil, settings, true)
+	x := x0
+	lastStep := math.Inf(1)
+	for {
+		if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations >= s.settings.FuncEvaluations {
+			s.result.Status = FunctionEvaluationLimit
+			break
+		}
+		fx, step := eval(x)
+		s.result.FuncEvaluations++
+		s.result.GradEvaluations++
+		if hess {
+			s.result.HessEvaluations++
+		}
+		if s.evaluated(x, fx) {
+			break
+		}
+		s.Record(x, fx)
+		if math.Abs(lastStep) <= s.Tol(x) {
+			s.result.Status = StepConvergence
+			break
+		}
+		if math.IsInf(step, 0) || math.IsNaN(step) {
+			s.result.Status = Failure
+			res, _ := s.finish()
+			return res, ErrZeroDerivative
+		}
+		if s.nextIteration() {
+			break
+		}
+		x -= step
+		lastStep = step
+	}
+	return s.finish()
+}
@@ -0,0 +1,747 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method   = (*NEWUOA)(nil)
+	_ Statuser = (*NEWUOA)(nil)
+)
+
+// NEWUOA implements Powell's NEWUOA method for gradient-free unconstrained
+// optimization, described in
+//  Powell, M. J. D.: The NEWUOA software for unconstrained optimization
+//  without derivatives. Large-Scale Nonlinear Optimization, 255–297 (2006)
+// NEWUOA is a trust-region method that minimizes a quadratic model of the
+// function within a trust region around the best location found. The model
+// interpolates the function at a set of points, and when a point is
+// replaced, the model is updated so that the Frobenius norm of the change
+// in its Hessian is least. The method needs far fewer function evaluations
+// than NelderMead in more than a few dimensions, and it copes well with
+// noise on a scale smaller than the final trust-region radius.
+//
+// The trust-region radius is bounded below by a lower bound ρ that is
+// decreased from InitRadius to FinalRadius. The MajorIterations sent by
+// NEWUOA hold the best location found after each evaluation, and NEWUOA
+// terminates with MethodConverge status when ρ has reached FinalRadius and
+// no further progress is made. The quadratic model is rebuilt from the
+// interpolation conditions in each iteration, which costs O((m+n)³)
+// operations for n dimensions and m points.
+type NEWUOA struct {
+	// InitRadius is the initial trust-region radius, which should be
+	// about a tenth of the largest expected change of a variable. If
+	// InitRadius is 0, a default value of 0.5 is used. InitRadius must not
+	// be negative, or NEWUOA will panic.
+	InitRadius float64
+	// FinalRadius is the final trust-region radius, which determines the
+	// accuracy of the location of the minimum. If FinalRadius is 0, a
+	// default value of 1e-6 is used, or InitRadius if that is smaller.
+	// FinalRadius must not be negative or larger than InitRadius, or
+	// NEWUOA will panic.
+	FinalRadius float64
+	// Points is the number of interpolation points. If Points is 0, a
+	// default value of 2*dim+1 is used. Points must be between dim+2 and
+	// (dim+1)*(dim+2)/2, or NEWUOA will panic.
+	Points int
+
+	status Status
+	err    error
+
+	model quadraticModel
+}
+
+func (n *NEWUOA) Status() (Status, error) {
+	return n.status, n.err
+}
+
+func (*NEWUOA) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (n *NEWUOA) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	n.status = NotTerminated
+	n.err = nil
+	n.model.init("newuoa", dim, n.Points, n.InitRadius, n.FinalRadius, nil, nil)
+	return 1
+}
+
+func (n *NEWUOA) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	s := newSerialRun(operation, result, tasks[0])
+	n.status, n.err = n.model.run(s)
+	s.finish()
+}
+
+// quadraticModel is the model-based trust-region method of NEWUOA and
+// BOBYQA. The function is approximated by the quadratic
+//  Q(base + s) = c + gᵀs + ½ sᵀHs
+// which interpolates the function at the points. The base of the model is
+// kept at the best point, so that g is the gradient of the model there.
+//
+// The interpolation conditions and the minimum Frobenius norm condition on
+// the change of the Hessian lead to the linear system
+//  [A  Yᵀ] [λ]   [r]
+//  [Y  0 ] [μ] = [0]
+// where A_ij = ½((y_i-base)ᵀ(y_j-base))², the columns of Y are (1, y_j-base)
+// and r holds the residuals of the model at the points. The change of the
+// model has constant and gradient μ and Hessian Σ_j λ_j (y_j-base)(y_j-base)ᵀ.
+// The rows of the inverse of the system matrix hold the coefficients of
+// the Lagrange functions of the points, which are used to choose the point
+// to replace and to keep the points well poised. The system is built in
+// coordinates scaled by the trust-region radius to keep it well
+// conditioned.
+type quadraticModel struct {
+	name           string
+	dim, npt       int
+	rhoBeg, rhoEnd float64
+	lower, upper   []float64 // Bounds on the variables, nil if unbounded.
+
+	pts  [][]float64 // Interpolation points.
+	fs   []float64   // Function values at the points.
+	kopt int         // Index of the best point.
+
+	base  []float64
+	c     float64
+	g     []float64
+	h     *mat.SymDense
+	scale float64     // Scale of the coordinates of the system.
+	ys    [][]float64 // Scaled points relative to base.
+	w     *mat.Dense
+	lu    mat.LU
+	winv  *mat.Dense // Inverse of w.
+}
+
+// init checks the parameters of the method called name and initializes
+// the model. lower and upper are nil if the problem is unbounded.
+func (m *quadraticModel) init(name string, dim, npt int, rhoBeg, rhoEnd float64, lower, upper []float64) {
+	switch {
+	case npt == 0:
+		npt = 2*dim + 1
+	case npt < dim+2 || npt > (dim+1)*(dim+2)/2:
+		panic(name + ": invalid number of interpolation points")
+	}
+	switch {
+	case rhoBeg == 0:
+		rhoBeg = 0.5
+		for i := range lower {
+			rhoBeg = math.Min(rhoBeg, (upper[i]-lower[i])/2)
+		}
+		if rhoBeg == 0 {
+			panic(name + ": bounds too close")
+		}
+	case rhoBeg < 0:
+		panic(name + ": negative initial radius")
+	}
+	for i := range lower {
+		if upper[i]-lower[i] < 2*rhoBeg {
+			panic(name + ": bounds closer than twice the initial radius")
+		}
+	}
+	switch {
+	case rhoEnd == 0:
+		rhoEnd = math.Min(1e-6, rhoBeg)
+	case rhoEnd < 0 || rhoEnd > rhoBeg:
+		panic(name + ": final radius out of range")
+	}
+	m.name = name
+	m.dim = dim
+	m.npt = npt
+	m.rhoBeg = rhoBeg
+	m.rhoEnd = rhoEnd
+	m.lower = lower
+	m.upper = upper
+
+	m.pts = resizeRows(m.pts, npt, dim)
+	m.fs = resize(m.fs, npt)
+	m.ys = resizeRows(m.ys, npt, dim)
+	m.base = resize(m.base, dim)
+	m.g = resize(m.g, dim)
+	if m.h == nil {
+		m.h = mat.NewSymDense(dim, nil)
+		m.w = &mat.Dense{}
+		m.winv = &mat.Dense{}
+	} else {
+		m.h.Reset()
+		m.h.ReuseAsSym(dim)
+	}
+}
+
+// run runs the trust-region method.
+func (m *quadraticModel) run(s *serialRun) (Status, error) {
+	dim := m.dim
+	x0 := make([]float64, dim)
+	f0, ok := s.initial(x0)
+	if !ok {
+		return NotTerminated, nil
+	}
+	if math.IsInf(f0, 1) || math.IsNaN(f0) {
+		return Failure, ErrFunc(f0)
+	}
+	if status, err := m.initPoints(s, x0, f0); status != NotTerminated || s.stopped {
+		return status, err
+	}
+	rho := m.rhoBeg
+	delta := rho
+	if err := m.refresh(delta); err != nil {
+		return Failure, err
+	}
+	if !s.iterate() {
+		return NotTerminated, nil
+	}
+
+	d := make([]float64, dim)
+	x := make([]float64, dim)
+	for {
+		m.trustRegionStep(d, delta)
+		dnorm := floats.Norm(d, 2)
+		if dnorm < rho/2 {
+			// The step is too short to be worth an evaluation. Improve
+			// the geometry of the points if some are far away, and
+			// otherwise decrease ρ.
+			delta /= 10
+			if delta <= 1.5*rho {
+				delta = rho
+			}
+			if k, dist := m.farthest(); dist > 2*delta {
+				replaced := m.improveGeometry(s, k, dist, rho, delta)
+				if s.stopped {
+					return NotTerminated, nil
+				}
+				if replaced {
+					continue
+				}
+			}
+			if rho <= m.rhoEnd {
+				return MethodConverge, nil
+			}
+			rho, delta = m.reduceRho(rho)
+			continue
+		}
+
+		floats.AddTo(x, m.base, d)
+		projectBounds(x, m.lower, m.upper)
+		f, ok := s.eval(x)
+		if !ok {
+			return NotTerminated, nil
+		}
+		fopt := m.fs[m.kopt]
+		pred := -floats.Dot(m.g, d) - 0.5*mat.Inner(mat.NewVecDense(dim, d), m.h, mat.NewVecDense(dim, d))
+		ratio := -1.0
+		if pred > 0 && !math.IsNaN(f) {
+			ratio = (fopt - f) / pred
+		}
+		switch {
+		case ratio <= 0.1:
+			delta = math.Min(delta/2, dnorm)
+		case ratio <= 0.7:
+			delta = math.Max(delta/2, dnorm)
+		default:
+			delta = math.Max(delta/2, 2*dnorm)
+		}
+		if delta <= 1.5*rho {
+			delta = rho
+		}
+		if !math.IsInf(f, 0) && !math.IsNaN(f) {
+			if k := m.replaceIndex(x, f < fopt, delta); k >= 0 {
+				if _, err := m.replace(k, x, f, delta); err != nil {
+					return Failure, err
+				}
+			}
+		}
+		if !s.iterate() {
+			return NotTerminated, nil
+		}
+		if ratio >= 0.1 {
+			continue
+		}
+
+		// The step was not successful. Improve the geometry of the points
+		// if some are far away, try a smaller trust region or decrease ρ.
+		if k, dist := m.farthest(); dist > 2*delta {
+			replaced := m.improveGeometry(s, k, dist, rho, delta)
+			if s.stopped {
+				return NotTerminated, nil
+			}
+			if replaced {
+				continue
+			}
+		}
+		if math.Max(delta, dnorm) > rho {
+			continue
+		}
+		if rho <= m.rhoEnd {
+			return MethodConverge, nil
+		}
+		rho, delta = m.reduceRho(rho)
+	}
+}
+
+// reduceRho returns the next lower bound on the trust-region radius and
+// the trust-region radius.
+func (m *quadraticModel) reduceRho(rho float64) (newRho, delta float64) {
+	switch ratio := rho / m.rhoEnd; {
+	case ratio <= 16:
+		newRho = m.rhoEnd
+	case ratio <= 250:
+		newRho = math.Sqrt(ratio) * m.rhoEnd
+	default:
+		newRho = rho / 10
+	}
+	return newRho, math.Max(rho/2, newRho)
+}
+
+// initPoints evaluates the function at the initial interpolation points
+// around x0, where the function value is f0. The points are displaced by
+// ρ from x0 along each coordinate direction, in both directions if there
+// are enough points, and along pairs of coordinate directions if there are
+// more than 2*dim+1 points. Within the bounds, x0 is first moved to a bound
+// or to a distance of at least ρ from it, and the points next to a bound
+// are displaced by ρ and 2ρ away from it.
+func (m *quadraticModel) initPoints(s *serialRun, x0 []float64, f0 float64) (Status, error) {
+	dim := m.dim
+	rho := m.rhoBeg
+	// a and b are the displacements along each coordinate direction.
+	a := make([]float64, dim)
+	b := make([]float64, dim)
+	moved := false
+	for i := range x0 {
+		a[i], b[i] = rho, -rho
+		if m.lower == nil {
+			continue
+		}
+		lo, up := m.lower[i], m.upper[i]
+		switch {
+		case x0[i] == lo:
+			b[i] = 2 * rho
+		case x0[i] == up:
+			a[i], b[i] = -rho, -2*rho
+		case x0[i] < lo+rho:
+			x0[i] = lo + rho
+			moved = true
+		case x0[i] > up-rho:
+			x0[i] = up - rho
+			moved = true
+		}
+	}
+	if moved {
+		var ok bool
+		f0, ok = s.eval(x0)
+		if !ok {
+			return NotTerminated, nil
+		}
+		if math.IsInf(f0, 0) || math.IsNaN(f0) {
+			return Failure, ErrModelFailure
+		}
+	}
+
+	copy(m.pts[0], x0)
+	m.fs[0] = f0
+	k := 1
+	eval := func(x []float64) (Status, error) {
+		f, ok := s.eval(x)
+		if !ok {
+			return NotTerminated, nil
+		}
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return Failure, ErrModelFailure
+		}
+		m.fs[k] = f
+		k++
+		return NotTerminated, nil
+	}
+	for k < m.npt && k <= 2*dim {
+		x := m.pts[k]
+		copy(x, x0)
+		if k <= dim {
+			x[k-1] += a[k-1]
+		} else {
+			x[k-dim-1] += b[k-dim-1]
+		}
+		if status, err := eval(x); status != NotTerminated || s.stopped {
+			return status, err
+		}
+	}
+	// Displace the remaining points along pairs of coordinate directions,
+	// choosing the displacements with the lower function values.
+	for r := 1; r < dim && k < m.npt; r++ {
+		for p := 0; p < dim && k < m.npt; p++ {
+			q := (p + r) % dim
+			if r > dim-r || (r == dim-r && p > q) {
+				// The pair has already been used.
+				continue
+			}
+			x := m.pts[k]
+			copy(x, x0)
+			for _, i := range []int{p, q} {
+				if m.fs[i+dim+1] < m.fs[i+1] {
+					x[i] += b[i]
+				} else {
+					x[i] += a[i]
+				}
+			}
+			if status, err := eval(x); status != NotTerminated || s.stopped {
+				return status, err
+			}
+		}
+	}
+	m.kopt = floats.MinIdx(m.fs)
+
+	m.c = 0
+	for i := range m.g {
+		m.g[i] = 0
+	}
+	m.h.Zero()
+	copy(m.base, x0)
+	return NotTerminated, nil
+}
+
+// refresh moves the base of the model to the best point, factorizes the
+// system of the interpolation conditions with coordinates scaled by delta
+// and updates the model to interpolate the function at the points.
+func (m *quadraticModel) refresh(delta float64) error {
+	dim, npt := m.dim, m.npt
+
+	// Move the base to the best point.
+	shift := make([]float64, dim)
+	floats.SubTo(shift, m.pts[m.kopt], m.base)
+	hs := mat.NewVecDense(dim, nil)
+	hs.MulVec(m.h, mat.NewVecDense(dim, shift))
+	m.c += floats.Dot(m.g, shift) + 0.5*floats.Dot(shift, hs.RawVector().Data)
+	floats.Add(m.g, hs.RawVector().Data)
+	copy(m.base, m.pts[m.kopt])
+
+	// Build the system in scaled coordinates.
+	m.scale = delta
+	for k, y := range m.ys {
+		floats.SubTo(y, m.pts[k], m.base)
+		floats.Scale(1/delta, y)
+	}
+	size := npt + dim + 1
+	m.w.Reset()
+	m.w.ReuseAs(size, size)
+	for i, yi := range m.ys {
+		for j := i; j < npt; j++ {
+			v := floats.Dot(yi, m.ys[j])
+			m.w.Set(i, j, v*v/2)
+			m.w.Set(j, i, v*v/2)
+		}
+		m.w.Set(i, npt, 1)
+		m.w.Set(npt, i, 1)
+		for a, v := range yi {
+			m.w.Set(i, npt+1+a, v)
+			m.w.Set(npt+1+a, i, v)
+		}
+	}
+	ones := make([]float64, size)
+	for i := range ones {
+		ones[i] = 1
+	}
+	m.lu.Factorize(m.w)
+	m.winv.Reset()
+	err := m.lu.SolveTo(m.winv, false, mat.NewDiagDense(size, ones))
+	if cond, ok := err.(mat.Condition); err != nil && (!ok || math.IsInf(float64(cond), 1)) {
+		return ErrModelFailure
+	}
+
+	// Update the model with the residuals at the points.
+	r := make([]float64, size)
+	for k := range m.ys {
+		r[k] = m.fs[k] - m.value(m.pts[k])
+	}
+	sol := mat.NewVecDense(size, nil)
+	sol.MulVec(m.winv, mat.NewVecDense(size, r))
+	m.c += sol.AtVec(npt)
+	for i := range m.g {
+		m.g[i] += sol.AtVec(npt+1+i) / delta
+	}
+	for k, y := range m.ys {
+		m.h.SymRankOne(m.h, sol.AtVec(k)/(delta*delta), mat.NewVecDense(dim, y))
+	}
+	return nil
+}
+
+// value returns the value of the model at x.
+func (m *quadraticModel) value(x []float64) float64 {
+	dim := len(x)
+	s := make([]float64, dim)
+	floats.SubTo(s, x, m.base)
+	sv := mat.NewVecDense(dim, s)
+	return m.c + floats.Dot(m.g, s) + 0.5*mat.Inner(sv, m.h, sv)
+}
+
+// denominators stores in sigma the denominators of the updating formula
+// of the inverse of the system matrix when a point is replaced by the point
+// at the scaled displacement y from the base. The system remains
+// nonsingular if the denominator of the replaced point is not zero.
+// Following Powell, the denominator of point k is
+//  σ_k = α_k β + τ_k²
+// where τ_k is the value of the Lagrange function of point k at y,
+// α_k is the diagonal element k of the inverse and β = ½|y|⁴ - vᵀW⁻¹v,
+// with v the column of the system matrix for y.
+func (m *quadraticModel) denominators(sigma, y []float64) {
+	npt := m.npt
+	size := npt + m.dim + 1
+	v := make([]float64, size)
+	for j, yj := range m.ys {
+		d := floats.Dot(yj, y)
+		v[j] = d * d / 2
+	}
+	v[npt] = 1
+	copy(v[npt+1:], y)
+	hv := mat.NewVecDense(size, nil)
+	hv.MulVec(m.winv, mat.NewVecDense(size, v))
+	yy := floats.Dot(y, y)
+	beta := yy*yy/2 - floats.Dot(v, hv.RawVector().Data)
+	for k := range sigma {
+		tau := hv.AtVec(k)
+		sigma[k] = m.winv.At(k, k)*beta + tau*tau
+	}
+}
+
+// farthest returns the index of the point farthest from the best point
+// and its distance.
+func (m *quadraticModel) farthest() (k int, dist float64) {
+	for i, x := range m.pts {
+		if d := floats.Distance(x, m.pts[m.kopt], 2); d > dist {
+			k, dist = i, d
+		}
+	}
+	return k, dist
+}
+
+// replaceIndex returns the index of the point that is replaced by x, or -1
+// if no point should be replaced. The point is chosen to keep the points
+// well poised, weighted by their distance from the best point relative to
+// delta. The best point is only replaced if better is true.
+func (m *quadraticModel) replaceIndex(x []float64, better bool, delta float64) int {
+	y := make([]float64, m.dim)
+	floats.SubTo(y, x, m.base)
+	floats.Scale(1/m.scale, y)
+	sigma := make([]float64, m.npt)
+	m.denominators(sigma, y)
+	best := -1
+	var maxWeight float64
+	for k, v := range sigma {
+		if k == m.kopt && !better {
+			continue
+		}
+		d := floats.Distance(m.pts[k], m.pts[m.kopt], 2) / delta
+		if weight := math.Abs(v) * math.Max(1, d*d*d*d); weight > maxWeight {
+			best, maxWeight = k, weight
+		}
+	}
+	if maxWeight < 1e-8 {
+		return -1
+	}
+	return best
+}
+
+// replace replaces the point k by x, where the function value is f, and
+// updates the model. If the system of the interpolation conditions becomes
+// singular, the point is restored and replace returns false.
+func (m *quadraticModel) replace(k int, x []float64, f, delta float64) (bool, error) {
+	old := make([]float64, m.dim)
+	copy(old, m.pts[k])
+	oldF, oldOpt := m.fs[k], m.kopt
+	copy(m.pts[k], x)
+	m.fs[k] = f
+	if f < m.fs[m.kopt] {
+		m.kopt = k
+	}
+	if m.refresh(delta) == nil {
+		return true, nil
+	}
+	copy(m.pts[k], old)
+	m.fs[k] = oldF
+	m.kopt = oldOpt
+	return false, m.refresh(delta)
+}
+
+// improveGeometry replaces the point k, which is at the distance dist from
+// the best point, by a point near the best point where the magnitude of the
+// Lagrange function of point k is large. It returns whether the point was
+// replaced. The caller must check whether the optimization must stop.
+func (m *quadraticModel) improveGeometry(s *serialRun, k int, dist, rho, delta float64) bool {
+	dim, npt := m.dim, m.npt
+	// radius is the scaled distance of the new point from the best point.
+	radius := math.Max(math.Min(dist/10, delta), rho) / m.scale
+
+	// Try steps along the gradient of the Lagrange function and towards
+	// the other points, and keep the step with the largest magnitude of
+	// the Lagrange function.
+	var lo, up []float64
+	if m.lower != nil {
+		lo = make([]float64, dim)
+		up = make([]float64, dim)
+		for i := range lo {
+			lo[i] = (m.lower[i] - m.base[i]) / m.scale
+			up[i] = (m.upper[i] - m.base[i]) / m.scale
+		}
+	}
+	best := make([]float64, dim)
+	var maxSigma float64
+	y := make([]float64, dim)
+	sigma := make([]float64, npt)
+	try := func(dir []float64) {
+		norm := floats.Norm(dir, 2)
+		if norm == 0 {
+			return
+		}
+	Steps:
+		for _, sign := range []float64{1, -1} {
+			floats.ScaleTo(y, sign*radius/norm, dir)
+			projectBounds(y, lo, up)
+			for _, yj := range m.ys {
+				if floats.Distance(y, yj, 2) < radius/10 {
+					// The step is too close to a point.
+					continue Steps
+				}
+			}
+			m.denominators(sigma, y)
+			if v := math.Abs(sigma[k]); v > maxSigma {
+				maxSigma = v
+				copy(best, y)
+			}
+		}
+	}
+	try(m.winv.RawRowView(k)[npt+1:])
+	for j, yj := range m.ys {
+		if j != m.kopt {
+			try(yj)
+		}
+	}
+	if maxSigma == 0 {
+		return false
+	}
+
+	x := make([]float64, dim)
+	floats.AddScaledTo(x, m.base, m.scale, best)
+	projectBounds(x, m.lower, m.upper)
+	f, ok := s.eval(x)
+	if !ok || math.IsInf(f, 0) || math.IsNaN(f) {
+		return false
+	}
+	if ok, err := m.replace(k, x, f, delta); !ok || err != nil {
+		return false
+	}
+	return s.iterate()
+}
+
+// trustRegionStep stores in d an approximate minimizer of the model from
+// the base within the trust region of radius delta and the bounds, using
+// the truncated conjugate gradient method. A variable that reaches a bound
+// is fixed there and the conjugate gradient iterations are restarted.
+func (m *quadraticModel) trustRegionStep(d []float64, delta float64) {
+	const tol = 1e-20
+
+	dim := m.dim
+	var lo, up []float64
+	free := make([]bool, dim)
+	for i := range d {
+		d[i] = 0
+		free[i] = true
+	}
+	if m.lower != nil {
+		lo = make([]float64, dim)
+		up = make([]float64, dim)
+		for i := range lo {
+			lo[i] = m.lower[i] - m.base[i]
+			up[i] = m.upper[i] - m.base[i]
+			if (lo[i] >= 0 && m.g[i] >= 0) || (up[i] <= 0 && m.g[i] <= 0) {
+				free[i] = false
+			}
+		}
+	}
+
+	r := make([]float64, dim)
+	p := make([]float64, dim)
+	q := mat.NewVecDense(dim, nil)
+	gg := floats.Dot(m.g, m.g)
+	for restart := 0; restart <= dim; restart++ {
+		// Compute the residual r = -(g + H*d) of the free variables.
+		q.MulVec(m.h, mat.NewVecDense(dim, d))
+		for i := range r {
+			r[i] = 0
+			if free[i] {
+				r[i] = -m.g[i] - q.AtVec(i)
+			}
+		}
+		copy(p, r)
+		rr := floats.Dot(r, r)
+		if rr <= tol*gg {
+			return
+		}
+		fixed := false
+		for iter := 0; iter < dim && !fixed; iter++ {
+			q.MulVec(m.h, mat.NewVecDense(dim, p))
+			qd := q.RawVector().Data
+			for i, ok := range free {
+				if !ok {
+					qd[i] = 0
+				}
+			}
+			// Find the step to the trust-region boundary, to the minimum
+			// along p and to the nearest bound.
+			pp := floats.Dot(p, p)
+			dp := floats.Dot(d, p)
+			dd := floats.Dot(d, d)
+			alpha := (math.Sqrt(dp*dp+pp*math.Max(delta*delta-dd, 0)) - dp) / pp
+			boundary := true
+			if pq := floats.Dot(p, qd); pq > 0 && rr/pq < alpha {
+				alpha = rr / pq
+				boundary = false
+			}
+			bound := -1
+			for i, ok := range free {
+				if !ok || lo == nil || p[i] == 0 {
+					continue
+				}
+				a := (lo[i] - d[i]) / p[i]
+				if p[i] > 0 {
+					a = (up[i] - d[i]) / p[i]
+				}
+				if a < alpha {
+					alpha = a
+					bound = i
+				}
+			}
+			floats.AddScaled(d, alpha, p)
+			switch {
+			case bound >= 0:
+				if p[bound] > 0 {
+					d[bound] = up[bound]
+				} else {
+					d[bound] = lo[bound]
+				}
+				free[bound] = false
+				fixed = true
+				continue
+			case boundary:
+				return
+			}
+			floats.AddScaled(r, -alpha, qd)
+			rrNew := floats.Dot(r, r)
+			if rrNew <= tol*gg {
+				return
+			}
+			floats.AddScaledTo(p, r, rrNew/rr, p)
+			rr = rrNew
+		}
+		if !fixed {
+			return
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// derivativeFreeTests are problems with a known minimum for the methods
+// that only use function values and have their own convergence criterion.
+var derivativeFreeTests = []struct {
+	name string
+	f    func([]float64) float64
+	x    []float64
+	want []float64
+	tol  float64 // Relative tolerance of the minimum location.
+}{
+	{
+		name: "Beale",
+		f:    functions.Beale{}.Func,
+		x:    []float64{1, 1},
+		want: []float64{3, 0.5},
+		tol:  1e-5,
+	},
+	{
+		name: "BrownBadlyScaled",
+		f:    functions.BrownBadlyScaled{}.Func,
+		x:    []float64{1, 1},
+		want: []float64{1e6, 2e-6},
+		tol:  1e-4,
+	},
+	{
+		name: "ExtendedPowellSingular",
+		f:    functions.ExtendedPowellSingular{}.Func,
+		x:    []float64{3, -1, 0, 3},
+		want: []float64{0, 0, 0, 0},
+		tol:  1e-2,
+	},
+	{
+		name: "ExtendedRosenbrock",
+		f:    functions.ExtendedRosenbrock{}.Func,
+		x:    []float64{-1.2, 1},
+		want: []float64{1, 1},
+		tol:  1e-5,
+	},
+	{
+		name: "ExtendedRosenbrock",
+		f:    functions.ExtendedRosenbrock{}.Func,
+		x:    []float64{0, 0, 0, 0},
+		want: []float64{1, 1, 1, 1},
+		tol:  1e-5,
+	},
+	{
+		name: "Wood",
+		f:    functions.Wood{}.Func,
+		x:    []float64{-3, -1, -3, -1},
+		want: []float64{1, 1, 1, 1},
+		tol:  1e-5,
+	},
+}
+
+func TestNEWUOA(t *testing.T) {
+	t.Parallel()
+	testDerivativeFree(t, func(int) Method { return &NEWUOA{} })
+	testDerivativeFree(t, func(dim int) Method {
+		return &NEWUOA{Points: (dim + 1) * (dim + 2) / 2, FinalRadius: 1e-8}
+	})
+}
+
+// testDerivativeFree runs the derivativeFreeTests with the methods returned
+// by newMethod for the dimension of the problem.
+func testDerivativeFree(t *testing.T, newMethod func(dim int) Method) {
+	for _, test := range derivativeFreeTests {
+		method := newMethod(len(test.x))
+		var evals int
+		p := Problem{
+			Func: func(x []float64) float64 {
+				evals++
+				return test.f(x)
+			},
+		}
+		x := make([]float64, len(test.x))
+		copy(x, test.x)
+		settings := &Settings{Converger: NeverTerminate{}}
+		result, err := Minimize(p, x, settings, method)
+		if err != nil {
+			t.Errorf("%s %T: unexpected error: %v", test.name, method, err)
+			continue
+		}
+		if !floats.Equal(x, test.x) {
+			t.Errorf("%s %T: initial location modified", test.name, method)
+		}
+		if result.Status != MethodConverge {
+			t.Errorf("%s %T: unexpected status: got %v, want %v", test.name, method, result.Status, MethodConverge)
+		}
+		if result.FuncEvaluations != evals {
+			t.Errorf("%s %T: unexpected number of evaluations: got %d, want %d", test.name, method, result.FuncEvaluations, evals)
+		}
+		if f := test.f(result.X); f != result.F {
+			t.Errorf("%s %T: function value at the minimum %v not equal to the returned value %v", test.name, method, f, result.F)
+		}
+		for i, v := range result.X {
+			if math.Abs(v-test.want[i]) > test.tol*math.Max(1, math.Abs(test.want[i])) {
+				t.Errorf("%s %T: unexpected minimum: got %v, want %v", test.name, method, result.X, test.want)
+				break
+			}
+		}
+
+		// Check that providing the initial function value reduces the
+		// number of evaluations by one without changing the result, also
+		// when concurrent evaluations are allowed.
+		settings.InitValues = &Location{F: test.f(test.x)}
+		settings.Concurrent = 4
+		result2, err := Minimize(p, x, settings, newMethod(len(test.x)))
+		if err != nil {
+			t.Errorf("%s %T: unexpected error with initial values: %v", test.name, method, err)
+			continue
+		}
+		if result2.F != result.F || !floats.Equal(result2.X, result.X) {
+			t.Errorf("%s %T: different minimum with initial values", test.name, method)
+		}
+		if result2.FuncEvaluations != result.FuncEvaluations-1 {
+			t.Errorf("%s %T: providing initial values does not reduce the number of evaluations", test.name, method)
+		}
+	}
+}
+
+func TestNEWUOAPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	for _, method := range []*NEWUOA{
+		{Points: 3},
+		{Points: 7},
+		{InitRadius: -1},
+		{FinalRadius: -1},
+		{InitRadius: 0.1, FinalRadius: 1},
+	} {
+		if !panics(func() { Minimize(p, []float64{1, 1}, nil, method) }) {
+			t.Errorf("%+v: expected panic", *method)
+		}
+	}
+}
+
+func TestDerivativeFreeEvaluationLimit(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	for _, method := range []Method{&Powell{}, &NEWUOA{}, &BOBYQA{}} {
+		for _, limit := range []int{1, 2, 10} {
+			settings := &Settings{FuncEvaluations: limit}
+			result, err := Minimize(p, []float64{-1.2, 1}, settings, method)
+			if err != nil {
+				t.Errorf("%T: unexpected error: %v", method, err)
+				continue
+			}
+			if result.Status != FunctionEvaluationLimit {
+				t.Errorf("%T: unexpected status: got %v, want %v", method, result.Status, FunctionEvaluationLimit)
+			}
+			if result.FuncEvaluations != limit {
+				t.Errorf("%T: unexpected number of evaluations: got %d, want %d", method, result.FuncEvaluations, limit)
+			}
+			if f := p.Func(result.X); f != result.F {
+				t.Errorf("%T: function value at the minimum %v not equal to the returned value %v", method, f, result.F)
+			}
+		}
+	}
+}
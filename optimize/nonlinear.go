@@ -0,0 +1,576 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"gonum.org/v1/gonum/diff/fd"
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+const (
+	defaultNonlinearThreshold  = 1e-10
+	defaultNonlinearStepTol    = 1e-10
+	defaultNonlinearIterations = 1000
+)
+
+var (
+	_ NonlinearSolver = PowellHybrid{}
+	_ NonlinearSolver = NewtonKrylov{}
+)
+
+// NonlinearSystem is a system of n nonlinear equations F(x) = 0 in n
+// variables.
+type NonlinearSystem struct {
+	// Func evaluates F(x) into dst. Func must not modify x.
+	Func func(dst, x []float64)
+
+	// Jac evaluates the n×n Jacobian of F at x into dst,
+	//  J_{i,j} = ∂F_i/∂x_j.
+	// If Jac is nil, PowellHybrid approximates the Jacobian by forward
+	// differences of Func using fd.Jacobian, and NewtonKrylov approximates
+	// products of the Jacobian with vectors by directional differences.
+	Jac func(dst *mat.Dense, x []float64)
+}
+
+// NonlinearSettings holds the settings for solving systems of nonlinear
+// equations.
+type NonlinearSettings struct {
+	// FunctionThreshold stops the solver with FunctionThreshold status
+	// when the infinity norm of F(x) is at most this value. If
+	// FunctionThreshold is zero, a default of 1e-10 is used.
+	FunctionThreshold float64
+
+	// StepTol stops the solver with StepConvergence status when the step,
+	// or the trust region radius, is no larger than StepTol*(StepTol + ||x||).
+	// The solver may then have stopped at a local minimum of ||F|| that is
+	// not a solution, so F should be checked by the caller. If StepTol is
+	// zero, a default of 1e-10 is used.
+	StepTol float64
+
+	// MajorIterations is the maximum number of iterations allowed.
+	// IterationLimit status is returned if the number of iterations
+	// equals or exceeds this value. If it equals zero, a default of 1000
+	// is used.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum allowed number of evaluations of F,
+	// including those made to approximate the Jacobian.
+	// FunctionEvaluationLimit status is returned if the total number of
+	// evaluations equals or exceeds this number.
+	// If it equals zero, this setting has no effect.
+	FuncEvaluations int
+}
+
+// NonlinearResult holds the result of solving a system of nonlinear
+// equations.
+type NonlinearResult struct {
+	// X is the solution and F is the value of the system at X.
+	X, F []float64
+
+	// Stats holds the statistics of the solution. FuncEvaluations counts
+	// the evaluations of F and GradEvaluations the evaluations of the
+	// Jacobian.
+	Stats
+	Status Status
+}
+
+// NonlinearSolver is a method for solving systems of nonlinear equations.
+// NonlinearSolver is implemented by PowellHybrid and NewtonKrylov.
+type NonlinearSolver interface {
+	// solveNonlinear iterates from the initial state of s until s
+	// reports that the solver must terminate.
+	solveNonlinear(s *nlState)
+}
+
+// SolveNonlinear solves the system of nonlinear equations p, starting from
+// x0, using the given method. If method is nil, PowellHybrid is used. If
+// settings is nil, the defaults described for NonlinearSettings are used.
+//
+// SolveNonlinear panics if p.Func is nil. It returns ErrFunc if F(x0) is
+// not finite. Otherwise it returns the point with the smallest ||F|| found
+// and an error if the solver ended early as indicated by Status.Err.
+func SolveNonlinear(p NonlinearSystem, x0 []float64, method NonlinearSolver, settings *NonlinearSettings) (*NonlinearResult, error) {
+	if p.Func == nil {
+		panic(badProblem)
+	}
+	if len(x0) == 0 {
+		return nil, ErrZeroDimensional
+	}
+	if method == nil {
+		method = PowellHybrid{}
+	}
+	s := newNLState(p, x0, settings)
+	norm, done := s.eval(s.f, s.x)
+	if done {
+		return s.finish()
+	}
+	if math.IsInf(norm, 1) {
+		return nil, ErrFunc(floats.Dot(s.f, s.f))
+	}
+	s.norm = norm
+	method.solveNonlinear(s)
+	return s.finish()
+}
+
+// nlState holds the state of a nonlinear equation solver and checks for
+// termination.
+type nlState struct {
+	p        NonlinearSystem
+	settings NonlinearSettings
+	start    time.Time
+
+	// x is the current point, f the value of F at x and norm its
+	// Euclidean norm.
+	x, f []float64
+	norm float64
+
+	result NonlinearResult
+}
+
+func newNLState(p NonlinearSystem, x0 []float64, settings *NonlinearSettings) *nlState {
+	n := len(x0)
+	s := &nlState{
+		p:     p,
+		start: time.Now(),
+		x:     make([]float64, n),
+		f:     make([]float64, n),
+	}
+	if settings != nil {
+		s.settings = *settings
+	}
+	if s.settings.FunctionThreshold < 0 || s.settings.StepTol < 0 {
+		panic("optimize: negative tolerance")
+	}
+	if s.settings.FunctionThreshold == 0 {
+		s.settings.FunctionThreshold = defaultNonlinearThreshold
+	}
+	if s.settings.StepTol == 0 {
+		s.settings.StepTol = defaultNonlinearStepTol
+	}
+	if s.settings.MajorIterations == 0 {
+		s.settings.MajorIterations = defaultNonlinearIterations
+	}
+	copy(s.x, x0)
+	return s
+}
+
+// eval evaluates F at x into dst and returns its norm, or +Inf if it is
+// not finite. It returns done as true if the evaluation limit has been
+// reached.
+func (s *nlState) eval(dst, x []float64) (norm float64, done bool) {
+	if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations >= s.settings.FuncEvaluations {
+		s.result.Status = FunctionEvaluationLimit
+		return math.Inf(1), true
+	}
+	s.p.Func(dst, x)
+	s.result.FuncEvaluations++
+	norm = floats.Norm(dst, 2)
+	if math.IsNaN(norm) {
+		norm = math.Inf(1)
+	}
+	return norm, false
+}
+
+// jacobian evaluates the Jacobian at the current point into dst. It returns
+// done as true if the evaluations needed to approximate the Jacobian would
+// exceed the evaluation limit.
+func (s *nlState) jacobian(dst *mat.Dense) (done bool) {
+	if s.p.Jac != nil {
+		s.p.Jac(dst, s.x)
+	} else {
+		if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations+len(s.x) > s.settings.FuncEvaluations {
+			s.result.Status = FunctionEvaluationLimit
+			return true
+		}
+		fd.Jacobian(dst, s.p.Func, s.x, &fd.JacobianSettings{
+			Formula:     fd.Forward,
+			OriginValue: s.f,
+		})
+		s.result.FuncEvaluations += len(s.x)
+	}
+	s.result.GradEvaluations++
+	return false
+}
+
+// iterate starts a new iteration, returning done as true if F is small
+// enough or the iteration limit has been reached.
+func (s *nlState) iterate() (done bool) {
+	if floats.Norm(s.f, math.Inf(1)) <= s.settings.FunctionThreshold {
+		s.result.Status = FunctionThreshold
+		return true
+	}
+	if s.result.MajorIterations >= s.settings.MajorIterations {
+		s.result.Status = IterationLimit
+		return true
+	}
+	s.result.MajorIterations++
+	return false
+}
+
+// accept moves to the point xt with value ft of F and norm normt.
+func (s *nlState) accept(xt, ft []float64, normt float64) {
+	copy(s.x, xt)
+	copy(s.f, ft)
+	s.norm = normt
+}
+
+// stepConverged returns whether a step of length norm is small enough
+// relative to x for the solver to terminate.
+func (s *nlState) stepConverged(norm float64) bool {
+	tol := s.settings.StepTol
+	if norm <= tol*(tol+floats.Norm(s.x, 2)) {
+		s.result.Status = StepConvergence
+		return true
+	}
+	return false
+}
+
+// finish returns the result of the solver.
+func (s *nlState) finish() (*NonlinearResult, error) {
+	s.result.X = s.x
+	s.result.F = s.f
+	s.result.Runtime = time.Since(s.start)
+	return &s.result, s.result.Status.Err()
+}
+
+// PowellHybrid is Powell's hybrid method for systems of nonlinear
+// equations. Each iteration takes a dogleg step between the Newton step
+// and the steepest descent direction for ||F||^2 within a trust region.
+// The Jacobian is evaluated at the starting point and then updated with
+// Broyden's rank-one formula, and is re-evaluated only when the updated
+// Jacobian fails to give progress in two successive iterations.
+//
+// The method is described in
+//  M. J. D. Powell, "A hybrid method for nonlinear equations", in
+//  Numerical Methods for Nonlinear Algebraic Equations, P. Rabinowitz, ed.,
+//  87-114. Gordon and Breach, 1970.
+// and is the method used by the hybrd and hybrj routines of MINPACK.
+type PowellHybrid struct {
+	// InitialRadius is the initial radius of the trust region. If
+	// InitialRadius is zero, 100*||x0||, or 100 if x0 is zero, is used.
+	InitialRadius float64
+}
+
+func (ph PowellHybrid) solveNonlinear(s *nlState) {
+	n := len(s.x)
+	delta := ph.InitialRadius
+	if delta == 0 {
+		delta = 100 * floats.Norm(s.x, 2)
+		if delta == 0 {
+			delta = 100
+		}
+	}
+	jac := mat.NewDense(n, n, nil)
+	if s.jacobian(jac) {
+		return
+	}
+
+	pn := mat.NewVecDense(n, nil)
+	p := make([]float64, n)
+	g := make([]float64, n)
+	xt := make([]float64, n)
+	ft := make([]float64, n)
+	y := make([]float64, n)
+	var lu mat.LU
+	var fails int
+	for {
+		if s.iterate() {
+			return
+		}
+		lu.Factorize(jac)
+		err := lu.SolveVecTo(pn, false, mat.NewVecDense(n, s.f))
+		haveNewton := err == nil && !floats.HasNaN(pn.RawVector().Data)
+		if haveNewton {
+			pn.ScaleVec(-1, pn)
+		}
+		gv := mat.NewVecDense(n, g)
+		gv.MulVec(jac.T(), mat.NewVecDense(n, s.f))
+		doglegStep(p, pn.RawVector().Data, haveNewton, g, jac, delta)
+
+		floats.AddTo(xt, s.x, p)
+		normt, done := s.eval(ft, xt)
+		if done {
+			return
+		}
+		// y holds the value of the linear model F + J p.
+		yv := mat.NewVecDense(n, y)
+		yv.MulVec(jac, mat.NewVecDense(n, p))
+		floats.Add(y, s.f)
+		pred := s.norm*s.norm - floats.Dot(y, y)
+		rho := -1.0
+		if pred > 0 {
+			rho = (s.norm*s.norm - normt*normt) / pred
+		}
+		pnorm := floats.Norm(p, 2)
+		if rho < 0.1 {
+			fails++
+			delta = pnorm / 2
+		} else {
+			fails = 0
+			if rho >= 0.5 || math.Abs(rho-1) <= 0.1 {
+				delta = math.Max(delta, 2*pnorm)
+			}
+		}
+
+		// Broyden's update uses the secant information from the step
+		// whether or not it is accepted.
+		if !math.IsInf(normt, 1) && pnorm > 0 {
+			floats.SubTo(y, ft, y)
+			jac.RankOne(jac, 1/(pnorm*pnorm), mat.NewVecDense(n, y), mat.NewVecDense(n, p))
+		}
+		if rho >= 1e-4 && normt < s.norm {
+			s.accept(xt, ft, normt)
+		}
+		if s.stepConverged(delta) {
+			return
+		}
+		if fails == 2 {
+			if s.jacobian(jac) {
+				return
+			}
+			fails = 0
+		}
+	}
+}
+
+// NewtonKrylov is an inexact Newton method for systems of nonlinear
+// equations. Each Newton step J p = -F is solved approximately with the
+// restarted GMRES method, which requires only products of the Jacobian with
+// vectors. If the system does not provide the Jacobian, these products are
+// approximated by directional differences of F, so the Jacobian is never
+// formed. The accuracy of the linear solves is chosen by the method of
+// Eisenstat and Walker, and the steps are globalized by a backtracking line
+// search on ||F||. NewtonKrylov is suited to large systems.
+//
+// The method is described in
+//  C. T. Kelley, "Solving Nonlinear Equations with Newton's Method",
+//  chapter 3. SIAM, 2003.
+type NewtonKrylov struct {
+	// Restart is the number of GMRES iterations between restarts. If
+	// Restart is zero, min(n, 30) is used.
+	Restart int
+
+	// MaxLinearIterations is the maximum number of GMRES iterations in
+	// each Newton step. If MaxLinearIterations is zero, a default of
+	// 10*Restart is used.
+	MaxLinearIterations int
+
+	// Forcing, if positive, is the fixed relative tolerance of the
+	// linear solves. If Forcing is zero, the tolerance is chosen
+	// adaptively.
+	Forcing float64
+}
+
+func (nk NewtonKrylov) solveNonlinear(s *nlState) {
+	const (
+		sqrtEps   = 0x1p-26
+		decrease  = 1e-4
+		maxForce  = 0.9
+		gamma     = 0.9
+		minLength = 1e-10
+	)
+	n := len(s.x)
+	restart := nk.Restart
+	if restart == 0 {
+		restart = n
+		if restart > 30 {
+			restart = 30
+		}
+	}
+	maxIter := nk.MaxLinearIterations
+	if maxIter == 0 {
+		maxIter = 10 * restart
+	}
+	eta := nk.Forcing
+	if eta == 0 {
+		eta = 0.5
+	}
+
+	var jac *mat.Dense
+	if s.p.Jac != nil {
+		jac = mat.NewDense(n, n, nil)
+	}
+	fh := make([]float64, n)
+	xh := make([]float64, n)
+	mul := func(dst, v []float64) (done bool) {
+		if jac != nil {
+			d := mat.NewVecDense(n, dst)
+			d.MulVec(jac, mat.NewVecDense(n, v))
+			return false
+		}
+		vnorm := floats.Norm(v, 2)
+		if vnorm == 0 {
+			for i := range dst {
+				dst[i] = 0
+			}
+			return false
+		}
+		h := sqrtEps * math.Max(1, floats.Norm(s.x, 2)) / vnorm
+		floats.AddScaledTo(xh, s.x, h, v)
+		if _, done := s.eval(fh, xh); done {
+			return true
+		}
+		floats.SubTo(dst, fh, s.f)
+		floats.Scale(1/h, dst)
+		return false
+	}
+
+	b := make([]float64, n)
+	p := make([]float64, n)
+	xt := make([]float64, n)
+	ft := make([]float64, n)
+	for {
+		if s.iterate() {
+			return
+		}
+		if jac != nil && s.jacobian(jac) {
+			return
+		}
+		floats.ScaleTo(b, -1, s.f)
+		if gmres(p, b, mul, eta*s.norm, restart, maxIter) {
+			return
+		}
+
+		// Backtrack until ||F|| decreases sufficiently.
+		t := 1.0
+		var normt float64
+		for {
+			floats.AddScaledTo(xt, s.x, t, p)
+			var done bool
+			normt, done = s.eval(ft, xt)
+			if done {
+				return
+			}
+			if normt <= (1-decrease*t)*s.norm {
+				break
+			}
+			t /= 2
+			if t < minLength {
+				if !s.stepConverged(t * floats.Norm(p, 2)) {
+					s.result.Status = Failure
+				}
+				return
+			}
+		}
+		normOld := s.norm
+		s.accept(xt, ft, normt)
+		if s.stepConverged(t * floats.Norm(p, 2)) {
+			return
+		}
+
+		if nk.Forcing == 0 {
+			// Choice 2 of Eisenstat and Walker with safeguards.
+			etaOld := eta
+			ratio := normt / normOld
+			eta = gamma * ratio * ratio
+			if safe := gamma * etaOld * etaOld; safe > 0.1 {
+				eta = math.Max(eta, safe)
+			}
+			eta = math.Min(eta, maxForce)
+		}
+	}
+}
+
+// gmres approximately solves the linear system A x = b for x, starting from
+// zero, with the restarted generalized minimal residual method, where the
+// product of A with a vector is computed by mul. The iteration stops when
+// ||b - A x|| <= tol or after maxIter iterations. gmres returns done as true
+// if mul reports that the solver must terminate.
+func gmres(x, b []float64, mul func(dst, v []float64) bool, tol float64, restart, maxIter int) (done bool) {
+	n := len(b)
+	for i := range x {
+		x[i] = 0
+	}
+	r := make([]float64, n)
+	copy(r, b)
+	v := make([][]float64, restart+1)
+	for i := range v {
+		v[i] = make([]float64, n)
+	}
+	h := mat.NewDense(restart+1, restart, nil)
+	cs := make([]float64, restart)
+	sn := make([]float64, restart)
+	g := make([]float64, restart+1)
+	y := make([]float64, restart)
+	var iter int
+	for {
+		beta := floats.Norm(r, 2)
+		if beta <= tol || iter >= maxIter {
+			return false
+		}
+		floats.ScaleTo(v[0], 1/beta, r)
+		for i := range g {
+			g[i] = 0
+		}
+		g[0] = beta
+
+		var k int
+		for k < restart && iter < maxIter {
+			iter++
+			w := v[k+1]
+			if mul(w, v[k]) {
+				return true
+			}
+			// Arnoldi process with modified Gram-Schmidt.
+			for i := 0; i <= k; i++ {
+				hik := floats.Dot(w, v[i])
+				h.Set(i, k, hik)
+				floats.AddScaled(w, -hik, v[i])
+			}
+			hk := floats.Norm(w, 2)
+			if hk != 0 {
+				floats.Scale(1/hk, w)
+			}
+			// Apply the previous Givens rotations to the new column
+			// and compute the rotation that eliminates hk.
+			for i := 0; i < k; i++ {
+				a, c := h.At(i, k), h.At(i+1, k)
+				h.Set(i, k, cs[i]*a+sn[i]*c)
+				h.Set(i+1, k, -sn[i]*a+cs[i]*c)
+			}
+			a := h.At(k, k)
+			rr := math.Hypot(a, hk)
+			if rr == 0 {
+				cs[k], sn[k] = 1, 0
+			} else {
+				cs[k], sn[k] = a/rr, hk/rr
+			}
+			h.Set(k, k, rr)
+			h.Set(k+1, k, 0)
+			g[k+1] = -sn[k] * g[k]
+			g[k] *= cs[k]
+			k++
+			if math.Abs(g[k]) <= tol || hk == 0 {
+				break
+			}
+		}
+
+		// Solve the triangular least squares problem and update x.
+		for i := k - 1; i >= 0; i-- {
+			sum := g[i]
+			for j := i + 1; j < k; j++ {
+				sum -= h.At(i, j) * y[j]
+			}
+			y[i] = 0
+			if d := h.At(i, i); d != 0 {
+				y[i] = sum / d
+			}
+		}
+		for i := 0; i < k; i++ {
+			floats.AddScaled(x, y[i], v[i])
+		}
+		if math.Abs(g[k]) <= tol || iter >= maxIter {
+			return false
+		}
+		if mul(r, x) {
+			return true
+		}
+		floats.SubTo(r, b, r)
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// broydenTridiagonal returns Broyden's tridiagonal system of n equations,
+//  F_i(x) = (3 - 2x_i) x_i - x_{i-1} - 2x_{i+1} + 1,
+// with x_0 = x_{n+1} = 0.
+func broydenTridiagonal(n int) NonlinearSystem {
+	at := func(x []float64, i int) float64 {
+		if i < 0 || i >= len(x) {
+			return 0
+		}
+		return x[i]
+	}
+	return NonlinearSystem{
+		Func: func(dst, x []float64) {
+			for i, v := range x {
+				dst[i] = (3-2*v)*v - at(x, i-1) - 2*at(x, i+1) + 1
+			}
+		},
+		Jac: func(dst *mat.Dense, x []float64) {
+			dst.Zero()
+			for i, v := range x {
+				dst.Set(i, i, 3-4*v)
+				if i > 0 {
+					dst.Set(i, i-1, -1)
+				}
+				if i < n-1 {
+					dst.Set(i, i+1, -2)
+				}
+			}
+		},
+	}
+}
+
+func constant(n int, v float64) []float64 {
+	x := make([]float64, n)
+	for i := range x {
+		x[i] = v
+	}
+	return x
+}
+
+func TestSolveNonlinear(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		p    NonlinearSystem
+		x0   []float64
+		want []float64
+	}{
+		{
+			name: "circleExp",
+			p: NonlinearSystem{
+				Func: func(dst, x []float64) {
+					dst[0] = x[0]*x[0] + x[1]*x[1] - 4
+					dst[1] = math.Exp(x[0]) + x[1] - 1
+				},
+				Jac: func(dst *mat.Dense, x []float64) {
+					dst.Set(0, 0, 2*x[0])
+					dst.Set(0, 1, 2*x[1])
+					dst.Set(1, 0, math.Exp(x[0]))
+					dst.Set(1, 1, 1)
+				},
+			},
+			x0:   []float64{1, -1},
+			want: []float64{1.0041687384746638, -1.7296372870999186},
+		},
+		{
+			name: "Rosenbrock",
+			p: NonlinearSystem{
+				Func: func(dst, x []float64) {
+					dst[0] = 10 * (x[1] - x[0]*x[0])
+					dst[1] = 1 - x[0]
+				},
+				Jac: func(dst *mat.Dense, x []float64) {
+					dst.Set(0, 0, -20*x[0])
+					dst.Set(0, 1, 10)
+					dst.Set(1, 0, -1)
+					dst.Set(1, 1, 0)
+				},
+			},
+			x0:   []float64{-1.2, 1},
+			want: []float64{1, 1},
+		},
+		{
+			name: "BroydenTridiagonal",
+			p:    broydenTridiagonal(20),
+			x0:   constant(20, -1),
+		},
+	} {
+		for _, m := range []struct {
+			name   string
+			method NonlinearSolver
+		}{
+			{name: "PowellHybrid", method: PowellHybrid{}},
+			{name: "NewtonKrylov", method: NewtonKrylov{}},
+		} {
+			for _, numJac := range []bool{false, true} {
+				p := test.p
+				if numJac {
+					p.Jac = nil
+				}
+				res, err := SolveNonlinear(p, test.x0, m.method, nil)
+				if err != nil {
+					t.Errorf("%s %s numJac=%t: unexpected error: %v", test.name, m.name, numJac, err)
+					continue
+				}
+				if res.Status != FunctionThreshold {
+					t.Errorf("%s %s numJac=%t: unexpected status: %v", test.name, m.name, numJac, res.Status)
+				}
+				f := make([]float64, len(test.x0))
+				p.Func(f, res.X)
+				if !floats.Equal(f, res.F) {
+					t.Errorf("%s %s numJac=%t: F mismatch", test.name, m.name, numJac)
+				}
+				if floats.Norm(f, math.Inf(1)) > 1e-10 {
+					t.Errorf("%s %s numJac=%t: F not zero at solution: %v", test.name, m.name, numJac, f)
+				}
+				if test.want != nil && !floats.EqualApprox(res.X, test.want, 1e-8) {
+					t.Errorf("%s %s numJac=%t: unexpected solution: got %v, want %v", test.name, m.name, numJac, res.X, test.want)
+				}
+			}
+		}
+	}
+}
+
+func TestSolveNonlinearLimits(t *testing.T) {
+	t.Parallel()
+	p := broydenTridiagonal(10)
+	p.Jac = nil
+	x0 := constant(10, -1)
+	for _, method := range []NonlinearSolver{PowellHybrid{}, NewtonKrylov{}} {
+		res, err := SolveNonlinear(p, x0, method, &NonlinearSettings{FuncEvaluations: 15})
+		if err == nil || res.Status != FunctionEvaluationLimit {
+			t.Errorf("%T: unexpected status with evaluation limit: %v", method, res.Status)
+		}
+		if res.FuncEvaluations > 15 {
+			t.Errorf("%T: evaluation limit exceeded: %d", method, res.FuncEvaluations)
+		}
+	}
+
+	// x^2 + 1 has no real root, so the solvers must stop without
+	// reaching the function threshold.
+	res, err := SolveNonlinear(NonlinearSystem{
+		Func: func(dst, x []float64) { dst[0] = x[0]*x[0] + 1 },
+	}, []float64{1}, nil, &NonlinearSettings{MajorIterations: 200})
+	if err == nil && res.Status == FunctionThreshold {
+		t.Errorf("unexpected convergence for a system with no solution")
+	}
+}
+
+func TestGMRES(t *testing.T) {
+	t.Parallel()
+	const n = 30
+	a := mat.NewDense(n, n, nil)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			a.Set(i, j, 1/float64(i+2*j+1))
+		}
+		a.Set(i, i, a.At(i, i)+float64(i+1))
+	}
+	want := make([]float64, n)
+	for i := range want {
+		want[i] = math.Sin(float64(i))
+	}
+	b := mat.NewVecDense(n, nil)
+	b.MulVec(a, mat.NewVecDense(n, want))
+	mul := func(dst, v []float64) bool {
+		d := mat.NewVecDense(n, dst)
+		d.MulVec(a, mat.NewVecDense(n, v))
+		return false
+	}
+	for _, restart := range []int{5, 10, n} {
+		x := make([]float64, n)
+		gmres(x, b.RawVector().Data, mul, 1e-12, restart, 1000)
+		if !floats.EqualApprox(x, want, 1e-10) {
+			t.Errorf("restart=%d: unexpected solution: got %v, want %v", restart, x, want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+var (
+	_ Method        = (*ParticleSwarm)(nil)
+	_ BoundedMethod = (*ParticleSwarm)(nil)
+)
+
+// ParticleSwarm implements particle swarm optimization with a global best
+// topology, a global optimization method described in
+//  Kennedy, J., Eberhart, R.: Particle swarm optimization. Proceedings of
+//  ICNN'95 - International Conference on Neural Networks, 1942–1948 (1995)
+// The particles of the swarm move with velocities that are updated in each
+// iteration by
+//  v = w*v + c₁*r₁∘(p - x) + c₂*r₂∘(g - x)
+// where x is the location of the particle, p the best location found by
+// the particle, g the best location found by the swarm, w the Inertia, c₁
+// and c₂ the Cognitive and Social coefficients, and r₁ and r₂ random
+// vectors with elements uniform in [0, 1). The default parameters are from
+//  Clerc, M.: Standard particle swarm optimisation (2012)
+//
+// The particles of an iteration are evaluated concurrently if
+// Settings.Concurrent is larger than one. The optimization is reproducible
+// for a given Src, independent of the number of concurrent evaluations,
+// unless it is stopped by an evaluation limit.
+//
+// The first particle starts at the initial location and the others at
+// random locations. Coordinates with finite bounds are sampled uniformly
+// within them, and the other coordinates from a normal distribution around
+// the initial location with standard deviation InitStepSize. Particles that
+// leave the bounds are moved onto them and stop in that coordinate.
+//
+// ParticleSwarm does not have its own convergence criterion, so
+// Settings.Converger or the evaluation limits should be used to stop it.
+type ParticleSwarm struct {
+	// Population is the number of particles. If Population is 0, a default
+	// value of 10 + floor(2*sqrt(dim)) is used. Population must not be
+	// negative, or ParticleSwarm will panic.
+	Population int
+	// Inertia is the weight of the previous velocity. If Inertia is 0, a
+	// default value of 1/(2*ln(2)) is used. Inertia must not be negative,
+	// or ParticleSwarm will panic.
+	Inertia float64
+	// Cognitive and Social are the weights of the attraction to the best
+	// location of the particle and of the swarm. If they are 0, default
+	// values of 0.5 + ln(2) are used. They must not be negative, or
+	// ParticleSwarm will panic.
+	Cognitive, Social float64
+	// InitStepSize is the standard deviation of the unbounded coordinates
+	// of the initial particles and the magnitude of their initial velocity.
+	// If InitStepSize is 0, a default value of 1 is used. InitStepSize must
+	// not be negative, or ParticleSwarm will panic.
+	InitStepSize float64
+	// Src is the source of random numbers. If Src is nil, the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	lower, upper []float64
+
+	dim                        int
+	pop                        int
+	inertia, cognitive, social float64
+	step                       float64
+	rnd                        *rand.Rand
+
+	initialized bool
+	x0          []float64
+	xs          [][]float64 // Locations of the particles.
+	vs          [][]float64 // Velocities of the particles.
+	ps          [][]float64 // Best locations of the particles.
+	fs          []float64   // Function values at the best locations.
+	best        int         // Index of the best particle.
+}
+
+func (*ParticleSwarm) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (ps *ParticleSwarm) InitBounds(lower, upper []float64) {
+	ps.lower = lower
+	ps.upper = upper
+}
+
+func (ps *ParticleSwarm) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	ps.dim = dim
+	ps.pop = ps.Population
+	switch {
+	case ps.pop == 0:
+		ps.pop = 10 + int(2*math.Sqrt(float64(dim)))
+	case ps.pop < 0:
+		panic("particle swarm: negative population size")
+	}
+	ps.inertia = ps.Inertia
+	switch {
+	case ps.inertia == 0:
+		ps.inertia = 1 / (2 * math.Ln2)
+	case ps.inertia < 0:
+		panic("particle swarm: negative inertia")
+	}
+	ps.cognitive = ps.Cognitive
+	switch {
+	case ps.cognitive == 0:
+		ps.cognitive = 0.5 + math.Ln2
+	case ps.cognitive < 0:
+		panic("particle swarm: negative cognitive coefficient")
+	}
+	ps.social = ps.Social
+	switch {
+	case ps.social == 0:
+		ps.social = 0.5 + math.Ln2
+	case ps.social < 0:
+		panic("particle swarm: negative social coefficient")
+	}
+	ps.step = ps.InitStepSize
+	switch {
+	case ps.step == 0:
+		ps.step = 1
+	case ps.step < 0:
+		panic("particle swarm: negative initial step size")
+	}
+	ps.rnd = newRand(ps.Src)
+	ps.initialized = false
+	ps.best = 0
+	ps.x0 = resize(ps.x0, dim)
+	ps.fs = resize(ps.fs, ps.pop)
+	return min(tasks, ps.pop)
+}
+
+func (ps *ParticleSwarm) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	copy(ps.x0, tasks[0].X)
+	runGenerations(ps, operation, result, tasks)
+}
+
+func (ps *ParticleSwarm) generation() [][]float64 {
+	if !ps.initialized {
+		ps.xs = newPopulation(ps.xs, ps.pop, ps.x0, ps.lower, ps.upper, ps.step, ps.rnd)
+		ps.vs = resizeRows(ps.vs, ps.pop, ps.dim)
+		for _, v := range ps.vs {
+			for j := range v {
+				v[j] = ps.step * (2*ps.rnd.Float64() - 1)
+			}
+		}
+		return ps.xs
+	}
+	g := ps.ps[ps.best]
+	for i, x := range ps.xs {
+		v, p := ps.vs[i], ps.ps[i]
+		for j := range x {
+			v[j] = ps.inertia*v[j] + ps.cognitive*ps.rnd.Float64()*(p[j]-x[j]) + ps.social*ps.rnd.Float64()*(g[j]-x[j])
+			x[j] += v[j]
+			if ps.lower != nil && (x[j] < ps.lower[j] || x[j] > ps.upper[j]) {
+				x[j] = math.Max(ps.lower[j], math.Min(x[j], ps.upper[j]))
+				v[j] = 0
+			}
+		}
+	}
+	return ps.xs
+}
+
+func (ps *ParticleSwarm) update(f []float64) bool {
+	if !ps.initialized {
+		ps.ps = resizeRows(ps.ps, ps.pop, ps.dim)
+		for i, x := range ps.xs {
+			copy(ps.ps[i], x)
+		}
+		copy(ps.fs, f)
+		ps.initialized = true
+	} else {
+		for i, v := range f {
+			if v < ps.fs[i] {
+				ps.fs[i] = v
+				copy(ps.ps[i], ps.xs[i])
+			}
+		}
+	}
+	for i, v := range ps.fs {
+		if v < ps.fs[ps.best] {
+			ps.best = i
+		}
+	}
+	return false
+}
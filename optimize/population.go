@@ -0,0 +1,198 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+// generationMethod is a method that evaluates the function at a batch of
+// locations in each major iteration. The locations of a batch are
+// evaluated concurrently by runGenerations.
+type generationMethod interface {
+	// generation returns the locations of the next batch. The method must
+	// not modify the locations until update is called.
+	generation() [][]float64
+
+	// update updates the method with the function values at the locations
+	// returned by the last call to generation. NaN values are replaced by
+	// +∞. update returns whether the method has converged, in which case
+	// the method must be a Statuser that returns the convergence status.
+	update(f []float64) (converged bool)
+}
+
+// runGenerations runs a generationMethod. After each batch it sends a
+// MajorIteration with the best location found so far, followed by MethodDone
+// if the method has converged. The batches are
+// evaluated in order, so the optimization does not depend on the order in
+// which the evaluations complete.
+func runGenerations(m generationMethod, operation chan<- Task, result <-chan Task, tasks []Task) {
+	dim := len(tasks[0].X)
+	bestX := make([]float64, dim)
+	copy(bestX, tasks[0].X)
+	bestF := math.Inf(1)
+
+	idle := make([]Task, len(tasks))
+	copy(idle, tasks)
+	var (
+		xs [][]float64
+		fs []float64
+	)
+	send := func(k int) {
+		task := idle[len(idle)-1]
+		idle = idle[:len(idle)-1]
+		task.ID = k
+		task.Op = FuncEvaluation
+		copy(task.X, xs[k])
+		operation <- task
+	}
+	// record stores the function value at the k-th location of the batch
+	// and returns whether it is the best value found so far.
+	record := func(k int, f float64) bool {
+		if math.IsNaN(f) {
+			f = math.Inf(1)
+		}
+		fs[k] = f
+		if f < bestF {
+			bestF = f
+			copy(bestX, xs[k])
+			return true
+		}
+		return false
+	}
+
+Loop:
+	for {
+		xs = m.generation()
+		fs = resize(fs, len(xs))
+		var sent, received int
+		for ; sent < len(xs) && len(idle) > 0; sent++ {
+			send(sent)
+		}
+		for received < len(xs) {
+			r := <-result
+			switch r.Op {
+			default:
+				panic("unknown operation")
+			case PostIteration:
+				break Loop
+			case FuncEvaluation:
+			}
+			idle = append(idle, r)
+			record(r.ID, r.F)
+			received++
+			if sent < len(xs) {
+				send(sent)
+				sent++
+			}
+		}
+		converged := m.update(fs)
+
+		task := idle[0]
+		task.ID = -1
+		task.Op = MajorIteration
+		task.F = bestF
+		copy(task.X, bestX)
+		task.Gradient = nil
+		task.Hessian = nil
+		operation <- task
+		r := <-result
+		switch r.Op {
+		default:
+			panic("unknown operation")
+		case PostIteration:
+			break Loop
+		case MajorIteration:
+		}
+		if converged {
+			r.Op = MethodDone
+			operation <- r
+			if r = <-result; r.Op != PostIteration {
+				panic("optimize: task should have returned post iteration")
+			}
+			break Loop
+		}
+	}
+
+	// PostIteration was sent. Collect the outstanding evaluations and send
+	// the best location if it has improved.
+	var improved bool
+	for r := range result {
+		switch r.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			if record(r.ID, r.F) {
+				improved = true
+			}
+		}
+	}
+	if improved {
+		task := tasks[0]
+		task.ID = -1
+		task.Op = MajorIteration
+		task.F = bestF
+		copy(task.X, bestX)
+		task.Gradient = nil
+		task.Hessian = nil
+		operation <- task
+	}
+	close(operation)
+}
+
+// newRand returns a random number generator using src. If src is nil, the
+// generator is seeded from the global source of golang.org/x/exp/rand.
+func newRand(src rand.Source) *rand.Rand {
+	if src == nil {
+		src = rand.NewSource(rand.Uint64())
+	}
+	return rand.New(src)
+}
+
+// samplePoint stores a random location in x. Coordinates with finite lower
+// and upper bounds are sampled uniformly within the bounds, and the other
+// coordinates are sampled from a normal distribution with mean x0 and
+// standard deviation step and projected onto the bounds. lower and upper
+// may be nil.
+func samplePoint(x, x0, lower, upper []float64, step float64, rnd *rand.Rand) {
+	for i := range x {
+		if lower != nil && !math.IsInf(lower[i], 0) && !math.IsInf(upper[i], 0) {
+			x[i] = lower[i] + (upper[i]-lower[i])*rnd.Float64()
+		} else {
+			x[i] = x0[i] + step*rnd.NormFloat64()
+		}
+	}
+	projectBounds(x, lower, upper)
+}
+
+// newPopulation returns a population of n locations, the first at x0 and
+// the others sampled by samplePoint.
+func newPopulation(xs [][]float64, n int, x0, lower, upper []float64, step float64, rnd *rand.Rand) [][]float64 {
+	xs = resizeRows(xs, n, len(x0))
+	for i := range xs {
+		if i == 0 {
+			copy(xs[i], x0)
+			projectBounds(xs[i], lower, upper)
+			continue
+		}
+		samplePoint(xs[i], x0, lower, upper, step, rnd)
+	}
+	return xs
+}
+
+// resizeRows returns xs with n rows of length dim.
+func resizeRows(xs [][]float64, n, dim int) [][]float64 {
+	if cap(xs) < n {
+		xs = make([][]float64, n)
+	}
+	xs = xs[:n]
+	for i := range xs {
+		xs[i] = resize(xs[i], dim)
+	}
+	return xs
+}
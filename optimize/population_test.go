@@ -0,0 +1,322 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+// rastrigin is the Rastrigin function
+//  f(x) = 10*n + \sum_i (x_i^2 - 10*cos(2*π*x_i)),
+// which has many local minima and its global minimum of 0 at the origin.
+func rastrigin(x []float64) float64 {
+	f := 10 * float64(len(x))
+	for _, v := range x {
+		f += v*v - 10*math.Cos(2*math.Pi*v)
+	}
+	return f
+}
+
+// rastriginGrad is the gradient of rastrigin.
+func rastriginGrad(grad, x []float64) {
+	for i, v := range x {
+		grad[i] = 2*v + 20*math.Pi*math.Sin(2*math.Pi*v)
+	}
+}
+
+// seededMethod is a global Method that uses a random source.
+type seededMethod interface {
+	Method
+	setSrc(rand.Source)
+}
+
+func (de *DifferentialEvolution) setSrc(src rand.Source) { de.Src = src }
+func (ps *ParticleSwarm) setSrc(src rand.Source)         { ps.Src = src }
+func (sa *SimulatedAnnealing) setSrc(src rand.Source)    { sa.Src = src }
+func (bh *BasinHopping) setSrc(src rand.Source)          { bh.Src = src }
+
+var globalTests = []struct {
+	name    string
+	method  func() seededMethod
+	p       Problem
+	x       []float64
+	want    []float64
+	tol     float64
+	evals   int
+	bounded bool
+	// concurrent is whether the result is independent of
+	// Settings.Concurrent.
+	concurrent bool
+}{
+	{
+		name:       "DifferentialEvolution",
+		method:     func() seededMethod { return &DifferentialEvolution{Population: 40} },
+		p:          Problem{Func: rastrigin},
+		x:          []float64{3.2, -2.9},
+		want:       []float64{0, 0},
+		tol:        1e-3,
+		evals:      20000,
+		concurrent: true,
+	},
+	{
+		name:   "DifferentialEvolutionBounded",
+		method: func() seededMethod { return &DifferentialEvolution{} },
+		p: Problem{
+			Func:  rastrigin,
+			Lower: []float64{-5.12, -5.12, 0.5},
+			Upper: []float64{5.12, 5.12, 5.12},
+		},
+		x:          []float64{3.2, -2.9, 4},
+		want:       []float64{0, 0, 0.995},
+		tol:        1e-2,
+		evals:      50000,
+		bounded:    true,
+		concurrent: true,
+	},
+	{
+		name:       "ParticleSwarm",
+		method:     func() seededMethod { return &ParticleSwarm{Population: 30} },
+		p:          Problem{Func: rastrigin},
+		x:          []float64{3.2, -2.9},
+		want:       []float64{0, 0},
+		tol:        1e-3,
+		evals:      20000,
+		concurrent: true,
+	},
+	{
+		name:   "ParticleSwarmBounded",
+		method: func() seededMethod { return &ParticleSwarm{Population: 40} },
+		p: Problem{
+			Func:  rastrigin,
+			Lower: []float64{-5.12, -5.12, 0.5},
+			Upper: []float64{5.12, 5.12, 5.12},
+		},
+		x:          []float64{3.2, -2.9, 4},
+		want:       []float64{0, 0, 0.995},
+		tol:        1e-2,
+		evals:      50000,
+		bounded:    true,
+		concurrent: true,
+	},
+	{
+		name:   "SimulatedAnnealing",
+		method: func() seededMethod { return &SimulatedAnnealing{} },
+		p:      Problem{Func: shiftedSphere{1, -2}.Func},
+		x:      []float64{-3, 4},
+		want:   []float64{1, -2},
+		tol:    1e-2,
+		evals:  20000,
+	},
+	{
+		name:   "SimulatedAnnealingBounded",
+		method: func() seededMethod { return &SimulatedAnnealing{StepSize: 0.5} },
+		p: Problem{
+			Func:  shiftedSphere{1, -2}.Func,
+			Lower: []float64{-4, -1},
+			Upper: []float64{4, 4},
+		},
+		x:       []float64{-3, 4},
+		want:    []float64{1, -1},
+		tol:     1e-2,
+		evals:   20000,
+		bounded: true,
+	},
+	{
+		name:   "BasinHopping",
+		method: func() seededMethod { return &BasinHopping{StepSize: 1} },
+		p:      Problem{Func: rastrigin, Grad: rastriginGrad},
+		x:      []float64{3.2, -2.9},
+		want:   []float64{0, 0},
+		tol:    1e-6,
+		evals:  20000,
+	},
+	{
+		name:   "BasinHoppingNelderMead",
+		method: func() seededMethod { return &BasinHopping{StepSize: 1} },
+		p:      Problem{Func: rastrigin},
+		x:      []float64{3.2, -2.9},
+		want:   []float64{0, 0},
+		tol:    1e-3,
+		evals:  20000,
+	},
+	{
+		name:   "BasinHoppingBounded",
+		method: func() seededMethod { return &BasinHopping{StepSize: 1} },
+		p: Problem{
+			Func:  rastrigin,
+			Grad:  rastriginGrad,
+			Lower: []float64{-5.12, -5.12, 0.5},
+			Upper: []float64{5.12, 5.12, 5.12},
+		},
+		x:       []float64{3.2, -2.9, 4},
+		want:    []float64{0, 0, 0.995},
+		tol:     1e-3,
+		evals:   20000,
+		bounded: true,
+	},
+}
+
+func TestGlobalMethods(t *testing.T) {
+	for _, test := range globalTests {
+		fmin := test.p.Func(test.want)
+		for _, concurrent := range []int{0, 5} {
+			method := test.method()
+			method.setSrc(rand.NewSource(1))
+			p := test.p
+			if test.bounded {
+				// Check that the function is only evaluated within the
+				// bounds.
+				f := p.Func
+				p.Func = func(x []float64) float64 {
+					for i, v := range x {
+						if v < p.Lower[i] || v > p.Upper[i] {
+							t.Errorf("%s: evaluation outside the bounds at %v", test.name, x)
+							break
+						}
+					}
+					return f(x)
+				}
+			}
+			settings := &Settings{
+				Converger:       functionThresholdConverger{fmin + test.tol*test.tol},
+				FuncEvaluations: test.evals,
+				Concurrent:      concurrent,
+			}
+			result, err := Minimize(p, test.x, settings, method)
+			if err != nil {
+				t.Errorf("%s, concurrent %d: unexpected error: %v", test.name, concurrent, err)
+				continue
+			}
+			if result.Status != FunctionThreshold {
+				t.Errorf("%s, concurrent %d: unexpected status %v at %v", test.name, concurrent, result.Status, result.X)
+				continue
+			}
+			if !floats.EqualApprox(result.X, test.want, 10*test.tol) {
+				t.Errorf("%s, concurrent %d: unexpected minimum: got %v, want %v", test.name, concurrent, result.X, test.want)
+			}
+			if result.F != test.p.Func(result.X) {
+				t.Errorf("%s, concurrent %d: function value does not match location", test.name, concurrent)
+			}
+		}
+	}
+}
+
+func TestGlobalMethodsReproducible(t *testing.T) {
+	for _, test := range globalTests {
+		concurrent := []int{0, 0}
+		if test.concurrent {
+			concurrent[1] = 5
+		}
+		var results [2]*Result
+		for i, c := range concurrent {
+			method := test.method()
+			method.setSrc(rand.NewSource(2))
+			settings := &Settings{
+				Converger:       NeverTerminate{},
+				MajorIterations: 20,
+				Concurrent:      c,
+			}
+			result, err := Minimize(test.p, test.x, settings, method)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			if result.Status != IterationLimit {
+				t.Errorf("%s: unexpected status %v", test.name, result.Status)
+			}
+			results[i] = result
+		}
+		if results[0].F != results[1].F || !floats.Equal(results[0].X, results[1].X) {
+			t.Errorf("%s: results differ: %v at %v and %v at %v", test.name,
+				results[0].F, results[0].X, results[1].F, results[1].X)
+		}
+	}
+}
+
+func TestSimulatedAnnealingStopTemp(t *testing.T) {
+	t.Parallel()
+	// With the default settings SimulatedAnnealing runs until the
+	// temperature has decreased below StopTemp times the initial
+	// temperature, which takes ceil(log(1e-6)/log(0.99)) iterations after
+	// the initial evaluation.
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	x := make([]float64, 3)
+	f0 := p.Func(x)
+	for _, concurrent := range []int{0, 1, 4} {
+		method := &SimulatedAnnealing{Src: rand.NewSource(1)}
+		result, err := Minimize(p, x, &Settings{Concurrent: concurrent}, method)
+		if err != nil {
+			t.Errorf("concurrent %d: unexpected error: %v", concurrent, err)
+			continue
+		}
+		if result.Status != MethodConverge {
+			t.Errorf("concurrent %d: unexpected status: got %v, want %v", concurrent, result.Status, MethodConverge)
+		}
+		if want := 1376; result.MajorIterations != want {
+			t.Errorf("concurrent %d: unexpected number of iterations: got %d, want %d", concurrent, result.MajorIterations, want)
+		}
+		if !(result.F < 0.1*f0) {
+			t.Errorf("concurrent %d: function not decreased: got %v, initial %v", concurrent, result.F, f0)
+		}
+	}
+
+	// A NaN StopTemp disables the criterion.
+	settings := &Settings{MajorIterations: 2000}
+	result, err := Minimize(p, x, settings, &SimulatedAnnealing{StopTemp: math.NaN(), Src: rand.NewSource(1)})
+	if err != nil || result.Status != IterationLimit {
+		t.Errorf("unexpected termination with NaN StopTemp: %v %v", result.Status, err)
+	}
+}
+
+func TestGlobalMethodsPanic(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		method Method
+		p      Problem
+	}{
+		{name: "DE population", method: &DifferentialEvolution{Population: 3}},
+		{name: "DE mutation", method: &DifferentialEvolution{Mutation: 2.5}},
+		{name: "DE crossover", method: &DifferentialEvolution{Crossover: -0.1}},
+		{name: "DE step", method: &DifferentialEvolution{InitStepSize: -1}},
+		{name: "PSO population", method: &ParticleSwarm{Population: -1}},
+		{name: "PSO inertia", method: &ParticleSwarm{Inertia: -1}},
+		{name: "PSO social", method: &ParticleSwarm{Social: -1}},
+		{name: "SA temperature", method: &SimulatedAnnealing{InitTemp: -1}},
+		{name: "SA cooling", method: &SimulatedAnnealing{Cooling: 1}},
+		{name: "SA step", method: &SimulatedAnnealing{StepSize: -1}},
+		{name: "SA stop temperature", method: &SimulatedAnnealing{StopTemp: 1}},
+		{name: "BH step", method: &BasinHopping{StepSize: -1}},
+		{name: "BH temperature", method: &BasinHopping{Temperature: -1}},
+		{name: "BH iterations", method: &BasinHopping{LocalIterations: -1}},
+		{
+			name:   "BH unsupported local method",
+			method: &BasinHopping{NewLocal: func() Method { return &CmaEsChol{} }},
+		},
+		{
+			name:   "BH unbounded local method",
+			method: &BasinHopping{NewLocal: func() Method { return &BFGS{} }},
+			p: Problem{
+				Grad:  rastriginGrad,
+				Lower: []float64{-1, -1},
+			},
+		},
+	} {
+		p := test.p
+		p.Func = rastrigin
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected panic", test.name)
+				}
+			}()
+			Minimize(p, []float64{1, 1}, nil, test.method)
+		}()
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+var (
+	_ Method   = (*Powell)(nil)
+	_ Statuser = (*Powell)(nil)
+)
+
+// Powell implements Powell's conjugate direction method for gradient-free
+// nonlinear optimization, described in
+//  Powell, M. J. D.: An efficient method for finding the minimum of a
+//  function of several variables without calculating derivatives. The
+//  Computer Journal 7, 155–162 (1964)
+// The function is minimized along each of a set of directions in turn,
+// starting with the coordinate directions. After each sweep, the overall
+// displacement of the sweep replaces the direction of largest decrease,
+// unless this would make the directions nearly linearly dependent. The
+// line minimizations use BracketMinimum and BrentMinimizer.
+//
+// The MajorIterations sent by Powell hold the location at the end of each
+// sweep. Powell terminates with MethodConverge status when the relative
+// decrease of the function in a sweep is less than FunctionTol.
+type Powell struct {
+	// InitStepSize is the initial trial step of the line minimizations. If
+	// InitStepSize is 0, a default value of 1 is used. InitStepSize must
+	// not be negative, or Powell will panic.
+	InitStepSize float64
+	// FunctionTol is the relative decrease of the function in a sweep
+	// below which Powell terminates. If FunctionTol is 0, a default value
+	// of 1e-10 is used. FunctionTol must not be negative, or Powell will
+	// panic.
+	FunctionTol float64
+
+	status Status
+	err    error
+
+	step float64
+	tol  float64
+
+	dirs  [][]float64 // Search directions of unit length.
+	steps []float64   // Trial steps along the directions.
+}
+
+func (p *Powell) Status() (Status, error) {
+	return p.status, p.err
+}
+
+func (*Powell) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (p *Powell) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	p.step = p.InitStepSize
+	switch {
+	case p.step == 0:
+		p.step = 1
+	case p.step < 0:
+		panic("powell: negative initial step size")
+	}
+	p.tol = p.FunctionTol
+	switch {
+	case p.tol == 0:
+		p.tol = 1e-10
+	case p.tol < 0:
+		panic("powell: negative function tolerance")
+	}
+	p.status = NotTerminated
+	p.err = nil
+	p.dirs = resizeRows(p.dirs, dim, dim)
+	for i, d := range p.dirs {
+		for j := range d {
+			d[j] = 0
+		}
+		d[i] = 1
+	}
+	p.steps = resize(p.steps, dim)
+	for i := range p.steps {
+		p.steps[i] = p.step
+	}
+	return 1
+}
+
+func (p *Powell) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	s := newSerialRun(operation, result, tasks[0])
+	p.status, p.err = p.run(s)
+	s.finish()
+}
+
+func (p *Powell) run(s *serialRun) (Status, error) {
+	dim := len(p.dirs)
+	x := make([]float64, dim)
+	f, ok := s.initial(x)
+	if !ok {
+		return NotTerminated, nil
+	}
+	if math.IsInf(f, 1) || math.IsNaN(f) {
+		return Failure, ErrFunc(f)
+	}
+	x0 := make([]float64, dim)
+	xe := make([]float64, dim)
+	d := make([]float64, dim)
+	for {
+		copy(x0, x)
+		f0 := f
+
+		// Minimize along each direction, recording the direction of
+		// largest decrease.
+		var big int
+		var bigDecrease float64
+		for i, u := range p.dirs {
+			fPrev := f
+			t, ft, ok := p.lineMinimize(s, x, f, u, &p.steps[i])
+			if !ok {
+				return NotTerminated, nil
+			}
+			floats.AddScaled(x, t, u)
+			f = ft
+			if fPrev-f > bigDecrease {
+				big = i
+				bigDecrease = fPrev - f
+			}
+		}
+		if !s.iterate() {
+			return NotTerminated, nil
+		}
+		if 2*(f0-f) <= p.tol*(math.Abs(f0)+math.Abs(f))+1e-300 {
+			return MethodConverge, nil
+		}
+
+		// Evaluate the function at the extrapolated point 2*x - x0 to decide
+		// whether to replace the direction of largest decrease by the
+		// displacement of the sweep.
+		floats.SubTo(d, x, x0)
+		norm := floats.Norm(d, 2)
+		if norm == 0 {
+			continue
+		}
+		floats.AddTo(xe, x, d)
+		fe, ok := s.eval(xe)
+		if !ok {
+			return NotTerminated, nil
+		}
+		if fe >= f0 {
+			continue
+		}
+		a := f0 - f - bigDecrease
+		b := f0 - fe
+		if 2*(f0-2*f+fe)*a*a >= bigDecrease*b*b {
+			continue
+		}
+		floats.Scale(1/norm, d)
+		last := dim - 1
+		p.dirs[big], p.dirs[last] = p.dirs[last], p.dirs[big]
+		p.steps[big] = p.steps[last]
+		copy(p.dirs[last], d)
+		p.steps[last] = norm
+		t, ft, ok := p.lineMinimize(s, x, f, p.dirs[last], &p.steps[last])
+		if !ok {
+			return NotTerminated, nil
+		}
+		floats.AddScaled(x, t, p.dirs[last])
+		f = ft
+	}
+}
+
+// lineMinimize minimizes the function along the direction u from x, where
+// the function value is f, and returns the step t to the minimum and the
+// function value there. step holds the trial step, which is updated with
+// the length of the step to the minimum. lineMinimize returns ok as false
+// if the optimization must stop.
+func (p *Powell) lineMinimize(s *serialRun, x []float64, f float64, u []float64, step *float64) (t, ft float64, ok bool) {
+	const (
+		maxBracketEvals = 50
+		lineTol         = 1e-4
+	)
+	xt := make([]float64, len(x))
+	t, ft, ok = 0, f, true
+	phi := func(v float64) float64 {
+		if v == 0 {
+			return f
+		}
+		if !ok {
+			// NaN stops the scalar minimizers.
+			return math.NaN()
+		}
+		floats.AddScaledTo(xt, x, v, u)
+		var fv float64
+		fv, ok = s.eval(xt)
+		if !ok {
+			return math.NaN()
+		}
+		if math.IsNaN(fv) {
+			fv = math.Inf(1)
+		}
+		if fv < ft {
+			t, ft = v, fv
+		}
+		return fv
+	}
+
+	// Ensure that the trial step changes x.
+	h := math.Max(*step, 1e-8*(1+floats.Norm(x, math.Inf(1))))
+	lo, _, hi, err := BracketMinimum(phi, 0, h, maxBracketEvals)
+	if !ok {
+		return 0, f, false
+	}
+	if err == nil {
+		settings := &ScalarSettings{
+			XAbsTol: lineTol * h,
+			XRelTol: lineTol,
+		}
+		MinimizeScalar(phi, lo, hi, BrentMinimizer{}, settings)
+		if !ok {
+			return 0, f, false
+		}
+	}
+	if t != 0 {
+		*step = math.Abs(t)
+	} else {
+		*step = h / 10
+	}
+	return t, ft, true
+}
@@ -0,0 +1,30 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"testing"
+
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestPowell(t *testing.T) {
+	t.Parallel()
+	testDerivativeFree(t, func(int) Method { return &Powell{} })
+	testDerivativeFree(t, func(int) Method { return &Powell{InitStepSize: 0.1, FunctionTol: 1e-12} })
+}
+
+func TestPowellPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{Func: functions.ExtendedRosenbrock{}.Func}
+	for _, method := range []*Powell{
+		{InitStepSize: -1},
+		{FunctionTol: -1},
+	} {
+		if !panics(func() { Minimize(p, []float64{1, 1}, nil, method) }) {
+			t.Errorf("%+v: expected panic", *method)
+		}
+	}
+}
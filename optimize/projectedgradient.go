@@ -0,0 +1,270 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	minSpectralStep = 1e-10
+	maxSpectralStep = 1e10
+
+	// nonmonotoneMemory is the number of function values at the latest
+	// major iterations that are used as the reference of the nonmonotone
+	// linesearch.
+	nonmonotoneMemory   = 10
+	nonmonotoneDecrease = 1e-4
+	// The interpolated step of the nonmonotone linesearch is safeguarded
+	// to lie between these fractions of the previous step.
+	nonmonotoneMinContraction = 0.1
+	nonmonotoneMaxContraction = 0.9
+)
+
+var (
+	_ Method          = (*ProjectedGradient)(nil)
+	_ BoundedMethod   = (*ProjectedGradient)(nil)
+	_ localMethod     = (*ProjectedGradient)(nil)
+	_ NextDirectioner = (*ProjectedGradient)(nil)
+
+	_ Linesearcher = (*nonmonotoneLinesearch)(nil)
+)
+
+// ProjectedGradient implements the spectral projected gradient method for
+// gradient-based minimization subject to bounds on the variables.
+//
+// At each iteration the negative gradient step with the Barzilai-Borwein
+// step size is projected onto the bounds, and a linesearch is performed
+// along the direction from the current location to the projected point.
+// If the problem is unbounded, ProjectedGradient is a gradient descent
+// method with the Barzilai-Borwein step size.
+//
+// By default the linesearch is the nonmonotone backtracking of Grippo,
+// Lampariello and Lucidi, which accepts a step if the function value
+// decreases sufficiently relative to the largest function value at the
+// latest 10 major iterations, so that the function value may increase
+// between major iterations. If the linesearch fails because the function
+// cannot be decreased further within its precision, ProjectedGradient
+// terminates with MethodConverge status.
+//
+// References:
+//  - Birgin, E., Martínez, J., Raydan, M.: Nonmonotone spectral projected
+//    gradient methods on convex sets. SIAM J. Optim. 10(4), 1196-1211 (2000)
+//  - Grippo, L., Lampariello, F., Lucidi, S.: A nonmonotone line search
+//    technique for Newton's method. SIAM J. Numer. Anal. 23(4), 707-716 (1986)
+type ProjectedGradient struct {
+	// Linesearcher selects suitable steps along the descent direction.
+	// Steps longer than one leave the bounds and are projected back onto
+	// them, so Linesearcher should only decrease the initial step of one.
+	// If Linesearcher is nil, the nonmonotone linesearch described above
+	// will be used.
+	Linesearcher Linesearcher
+	// GradStopThreshold sets the threshold for stopping if the norm of the
+	// projected gradient gets too small. If GradStopThreshold is 0 it is
+	// defaulted to 1e-12, and if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	ls *LinesearchMethod
+	nm nonmonotoneLinesearch
+
+	lower, upper []float64 // Bounds from InitBounds, nil if unbounded.
+	lo, up       []float64 // Bounds of the current run, infinite if unbounded.
+
+	x     []float64 // Location at the last major iteration
+	grad  []float64 // Gradient at the last major iteration
+	alpha float64   // Spectral step size
+}
+
+func (pg *ProjectedGradient) Status() (Status, error) {
+	return pg.status, pg.err
+}
+
+func (*ProjectedGradient) Uses(has Available) (uses Available, err error) {
+	return has.gradient()
+}
+
+func (pg *ProjectedGradient) InitBounds(lower, upper []float64) {
+	pg.lower = lower
+	pg.upper = upper
+}
+
+func (pg *ProjectedGradient) Init(dim, tasks int) int {
+	pg.status = NotTerminated
+	pg.err = nil
+	pg.lo = resize(pg.lo, dim)
+	pg.up = resize(pg.up, dim)
+	checkBounds(pg.lo, pg.up, pg.lower, pg.upper)
+	return 1
+}
+
+func (pg *ProjectedGradient) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	pg.status, pg.err = localOptimizer{lower: pg.lo, upper: pg.up}.run(pg, pg.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (pg *ProjectedGradient) initLocal(loc *Location) (Operation, error) {
+	if pg.ls == nil {
+		pg.ls = &LinesearchMethod{}
+	}
+	if pg.Linesearcher != nil {
+		pg.ls.Linesearcher = pg.Linesearcher
+	} else {
+		pg.nm.reset()
+		pg.ls.Linesearcher = &pg.nm
+	}
+	pg.ls.NextDirectioner = pg
+
+	op, err := pg.ls.Init(loc)
+	if op.isEvaluation() {
+		projectBounds(loc.X, pg.lo, pg.up)
+	}
+	return op, err
+}
+
+func (pg *ProjectedGradient) iterateLocal(loc *Location) (Operation, error) {
+	op, err := pg.ls.Iterate(loc)
+	if err == ErrNoProgress || err == ErrLinesearcherFailure {
+		// The search direction is always the projected gradient direction,
+		// so the function cannot be decreased within its precision.
+		return MethodDone, nil
+	}
+	if op.isEvaluation() {
+		projectBounds(loc.X, pg.lo, pg.up)
+	}
+	return op, err
+}
+
+func (pg *ProjectedGradient) InitDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	pg.x = resize(pg.x, dim)
+	copy(pg.x, loc.X)
+	pg.grad = resize(pg.grad, dim)
+	copy(pg.grad, loc.Gradient)
+
+	pg.alpha = 1
+	pg.direction(dir, loc.X, loc.Gradient)
+	if norm := floats.Norm(dir, math.Inf(1)); norm > 0 {
+		pg.alpha = math.Max(minSpectralStep, math.Min(1/norm, maxSpectralStep))
+		pg.direction(dir, loc.X, loc.Gradient)
+	}
+	return 1
+}
+
+func (pg *ProjectedGradient) NextDirection(loc *Location, dir []float64) (stepSize float64) {
+	dim := len(loc.X)
+	if len(loc.Gradient) != dim || len(dir) != dim || len(pg.x) != dim {
+		panic("projectedgradient: unexpected size mismatch")
+	}
+
+	// Compute the Barzilai-Borwein step size sᵀs/sᵀy.
+	var sDotS, sDotY float64
+	for i, x := range loc.X {
+		s := x - pg.x[i]
+		sDotS += s * s
+		sDotY += s * (loc.Gradient[i] - pg.grad[i])
+	}
+	if sDotY <= 0 {
+		pg.alpha = maxSpectralStep
+	} else {
+		pg.alpha = math.Max(minSpectralStep, math.Min(sDotS/sDotY, maxSpectralStep))
+	}
+	copy(pg.x, loc.X)
+	copy(pg.grad, loc.Gradient)
+
+	pg.direction(dir, loc.X, loc.Gradient)
+	return 1
+}
+
+// direction stores P(x - α g) - x in dir, where P is the projection onto
+// the bounds.
+func (pg *ProjectedGradient) direction(dir, x, g []float64) {
+	floats.AddScaledTo(dir, x, -pg.alpha, g)
+	projectBounds(dir, pg.lo, pg.up)
+	floats.Sub(dir, x)
+}
+
+func (*ProjectedGradient) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, false}
+}
+
+// nonmonotoneLinesearch is the nonmonotone backtracking Linesearcher of
+// Grippo, Lampariello and Lucidi with the safeguarded quadratic interpolation
+// of the step used in the spectral projected gradient method. A step is
+// accepted if it satisfies the Armijo condition relative to the largest of
+// the function values passed to Init in the latest nonmonotoneMemory calls.
+type nonmonotoneLinesearch struct {
+	hist []float64 // Function values at the latest major iterations
+
+	stepSize float64
+	initF    float64
+	initG    float64
+	maxF     float64
+
+	lastOp Operation
+}
+
+// reset discards the function values of previous major iterations.
+func (n *nonmonotoneLinesearch) reset() {
+	n.hist = n.hist[:0]
+}
+
+func (n *nonmonotoneLinesearch) Init(f, g float64, step float64) Operation {
+	if step <= 0 {
+		panic("projectedgradient: bad step size")
+	}
+	if g >= 0 {
+		panic("projectedgradient: initial derivative is non-negative")
+	}
+
+	if len(n.hist) == nonmonotoneMemory {
+		copy(n.hist, n.hist[1:])
+		n.hist = n.hist[:len(n.hist)-1]
+	}
+	n.hist = append(n.hist, f)
+	n.maxF = floats.Max(n.hist)
+
+	n.stepSize = step
+	n.initF = f
+	n.initG = g
+
+	n.lastOp = FuncEvaluation
+	return n.lastOp
+}
+
+func (n *nonmonotoneLinesearch) Iterate(f, _ float64) (Operation, float64, error) {
+	if n.lastOp != FuncEvaluation {
+		panic("projectedgradient: Init has not been called")
+	}
+
+	step := n.stepSize
+	if ArmijoConditionMet(f, n.maxF, n.initG, step, nonmonotoneDecrease) {
+		n.lastOp = MajorIteration
+		return n.lastOp, step, nil
+	}
+	// Minimize the quadratic that interpolates the function value and the
+	// derivative at zero and the function value at step, and halve the
+	// step if the minimizer is outside the safeguards.
+	n.stepSize = -0.5 * n.initG * step * step / (f - n.initF - n.initG*step)
+	if !(nonmonotoneMinContraction*step <= n.stepSize && n.stepSize <= nonmonotoneMaxContraction*step) {
+		n.stepSize = step / 2
+	}
+	if n.stepSize < minimumBacktrackingStepSize {
+		n.lastOp = NoOperation
+		return n.lastOp, n.stepSize, ErrLinesearcherFailure
+	}
+	n.lastOp = FuncEvaluation
+	return n.lastOp, n.stepSize, nil
+}
@@ -0,0 +1,237 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	errQPNotPositiveDefinite = errors.New("optimize: quadratic subproblem not positive definite")
+	errQPInfeasible          = errors.New("optimize: quadratic subproblem infeasible")
+	errQPSingular            = errors.New("optimize: quadratic subproblem singular")
+)
+
+// solveQP solves the strictly convex quadratic program
+//  minimize ½ xᵀ G x + aᵀ x
+//  s.t.     Aeq x = beq
+//           Ain x ≥ bin
+// using the dual active set method of Goldfarb and Idnani, storing the
+// solution in x. aeq and ain may be nil if there are no constraints of the
+// corresponding type. solveQP returns the multipliers of the constraints,
+// which satisfy
+//  G x + a = Aeqᵀ λ_eq + Ainᵀ λ_in,  λ_in ≥ 0.
+//
+// References:
+//  - Goldfarb, D., Idnani, A.: A numerically stable dual method for solving
+//    strictly convex quadratic programs. Math. Program. 27, 1-33 (1983)
+func solveQP(x []float64, g *mat.SymDense, a []float64, aeq *mat.Dense, beq []float64, ain *mat.Dense, bin []float64) (lamEq, lamIn []float64, err error) {
+	n := len(x)
+	lamEq = make([]float64, len(beq))
+	lamIn = make([]float64, len(bin))
+
+	// Start from the unconstrained minimum x = -G⁻¹ a.
+	var chol mat.Cholesky
+	if ok := chol.Factorize(g); !ok {
+		return lamEq, lamIn, errQPNotPositiveDefinite
+	}
+	xv := mat.NewVecDense(n, x)
+	if err := chol.SolveVecTo(xv, mat.NewVecDense(n, a)); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return lamEq, lamIn, errQPNotPositiveDefinite
+		}
+	}
+	xv.ScaleVec(-1, xv)
+
+	var (
+		// active holds the active constraints with their signed normals
+		// and multipliers. Equality constraints are never removed.
+		active []qpConstraint
+		added  = make([]bool, len(beq))
+		z      = make([]float64, n)
+	)
+	row := func(c qpConstraint) []float64 {
+		if c.eq {
+			return aeq.RawRowView(c.idx)
+		}
+		return ain.RawRowView(c.idx)
+	}
+	// slack returns the signed residual of constraint c at x, which is
+	// negative if c is violated.
+	slack := func(c qpConstraint) float64 {
+		if c.eq {
+			return c.sign * (floats.Dot(row(c), x) - beq[c.idx])
+		}
+		return floats.Dot(row(c), x) - bin[c.idx]
+	}
+	tolerance := func(c qpConstraint) float64 {
+		b := bin
+		if c.eq {
+			b = beq
+		}
+		return 1e-12 * math.Max(1, math.Max(math.Abs(b[c.idx]), floats.Norm(row(c), math.Inf(1))*floats.Norm(x, math.Inf(1))))
+	}
+
+	maxIter := 10 * (n + len(beq) + len(bin) + 10)
+	for iter := 0; ; iter++ {
+		if iter > maxIter {
+			return lamEq, lamIn, errQPSingular
+		}
+		// Choose a violated constraint to add, the equality constraints
+		// first and then the most violated inequality constraint.
+		p := qpConstraint{idx: -1}
+		for i := range beq {
+			if !added[i] {
+				p = qpConstraint{idx: i, eq: true, sign: 1}
+				break
+			}
+		}
+		if p.idx >= 0 {
+			added[p.idx] = true
+			if slack(p) > 0 {
+				p.sign = -1
+			}
+		} else {
+			worst := 0.0
+			for i := range bin {
+				c := qpConstraint{idx: i, sign: 1}
+				if isActive(active, c) {
+					continue
+				}
+				if s := slack(c); s < -tolerance(c) && s < worst {
+					worst = s
+					p = c
+				}
+			}
+			if p.idx < 0 {
+				break
+			}
+		}
+
+		var uPlus float64
+		for {
+			np := make([]float64, n)
+			floats.ScaleTo(np, p.sign, row(p))
+			r, err := qpStep(z, g, np, active, row)
+			if err != nil {
+				return lamEq, lamIn, err
+			}
+
+			// Find the largest dual step that keeps the multipliers of
+			// the active inequality constraints nonnegative.
+			t1 := math.Inf(1)
+			k := -1
+			for j, c := range active {
+				if c.eq || r[j] <= 0 {
+					continue
+				}
+				if t := c.u / r[j]; t < t1 {
+					t1 = t
+					k = j
+				}
+			}
+			// Find the primal step that satisfies constraint p.
+			t2 := math.Inf(1)
+			zn := floats.Dot(z, np)
+			if floats.Norm(z, math.Inf(1)) > 1e-14*math.Max(1, floats.Norm(np, math.Inf(1))) && zn > 0 {
+				t2 = -slack(p) / zn
+			}
+			if math.IsInf(t1, 1) && math.IsInf(t2, 1) {
+				if p.eq && math.Abs(slack(p)) <= tolerance(p) {
+					// The equality constraint is redundant.
+					break
+				}
+				return lamEq, lamIn, errQPInfeasible
+			}
+			if math.IsInf(t2, 1) {
+				// Partial step in the dual space only.
+				for j := range active {
+					active[j].u -= t1 * r[j]
+				}
+				uPlus += t1
+				active = append(active[:k], active[k+1:]...)
+				continue
+			}
+			t := math.Min(t1, t2)
+			floats.AddScaled(x, t, z)
+			for j := range active {
+				active[j].u -= t * r[j]
+			}
+			uPlus += t
+			if t2 <= t1 {
+				p.u = uPlus
+				active = append(active, p)
+				break
+			}
+			active = append(active[:k], active[k+1:]...)
+		}
+	}
+
+	for _, c := range active {
+		if c.eq {
+			lamEq[c.idx] = c.sign * c.u
+		} else {
+			lamIn[c.idx] = c.u
+		}
+	}
+	return lamEq, lamIn, nil
+}
+
+// qpConstraint is a constraint in the active set of solveQP.
+type qpConstraint struct {
+	idx  int
+	eq   bool
+	sign float64 // Sign of the normal of an equality constraint.
+	u    float64 // Multiplier of the signed normal.
+}
+
+func isActive(active []qpConstraint, c qpConstraint) bool {
+	for _, a := range active {
+		if a.eq == c.eq && a.idx == c.idx {
+			return true
+		}
+	}
+	return false
+}
+
+// qpStep computes the primal step direction z and the negative dual step
+// direction r for adding the constraint with normal np to the active set,
+// by solving
+//  [ G  -N ] [ z ]   [ np ]
+//  [ -Nᵀ 0 ] [-r ] = [ 0  ],
+// where the columns of N are the signed normals of the active constraints.
+func qpStep(z []float64, g *mat.SymDense, np []float64, active []qpConstraint, row func(qpConstraint) []float64) (r []float64, err error) {
+	n := len(z)
+	q := len(active)
+	k := mat.NewDense(n+q, n+q, nil)
+	k.Slice(0, n, 0, n).(*mat.Dense).Copy(g)
+	for j, c := range active {
+		for i, v := range row(c) {
+			v *= c.sign
+			k.Set(i, n+j, -v)
+			k.Set(n+j, i, -v)
+		}
+	}
+	rhs := make([]float64, n+q)
+	copy(rhs, np)
+	sol := mat.NewVecDense(n+q, nil)
+	if err := sol.SolveVec(k, mat.NewVecDense(n+q, rhs)); err != nil {
+		if _, ok := err.(mat.Condition); !ok {
+			return nil, errQPSingular
+		}
+	}
+	for i := range z {
+		z[i] = sol.AtVec(i)
+	}
+	r = make([]float64, q)
+	for j := range r {
+		r[j] = -sol.AtVec(n + j)
+	}
+	return r, nil
+}
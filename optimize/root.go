@@ -0,0 +1,398 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+var (
+	_ RootFinder = BrentRoot{}
+	_ RootFinder = Illinois{}
+	_ RootFinder = Ridders{}
+	_ RootFinder = TOMS748{}
+)
+
+// BrentRoot is Brent's method for finding a root of a function of one
+// variable. It combines inverse quadratic interpolation and secant steps
+// with bisection, so that it converges superlinearly for smooth functions
+// while never taking many more evaluations than bisection.
+//
+// The algorithm is described in
+//  R. P. Brent, "Algorithms for Minimization without Derivatives",
+//  chapter 4. Prentice-Hall, 1973.
+type BrentRoot struct{}
+
+func (BrentRoot) FindRoot(s *ScalarSearch, a, fa, b, fb float64) {
+	c, fc := a, fa
+	d := b - a
+	e := d
+	for {
+		if math.Signbit(fb) == math.Signbit(fc) {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+		if s.Iterate(math.Min(b, c), math.Max(b, c), b, fb) {
+			return
+		}
+		tol := s.Tol(b)
+		m := (c - b) / 2
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			// Attempt inverse quadratic interpolation, or a secant
+			// step if only two distinct points are available.
+			var p, q float64
+			sr := fb / fa
+			if a == c {
+				p = 2 * m * sr
+				q = 1 - sr
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = sr * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (sr - 1)
+			}
+			if p > 0 {
+				q = -q
+			} else {
+				p = -p
+			}
+			if 2*p < 3*m*q-math.Abs(tol*q) && p < math.Abs(e*q/2) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = m
+			}
+		} else {
+			d = m
+			e = m
+		}
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, m)
+		}
+		var done bool
+		fb, done = s.Eval(b)
+		if done {
+			return
+		}
+	}
+}
+
+// Illinois is the Illinois variant of the method of false position for
+// finding a root of a function of one variable. When the same end of the
+// bracketing interval is retained twice in succession, its function value
+// is halved, which avoids the slow one-sided convergence of the method of
+// false position.
+//
+// The algorithm is described in
+//  M. Dowell and P. Jarratt, "A modified regula falsi method for computing
+//  the root of an equation", BIT 11, 168-174 (1971).
+type Illinois struct{}
+
+func (Illinois) FindRoot(s *ScalarSearch, a, fa, b, fb float64) {
+	// fa and fb hold the possibly scaled function values used for
+	// interpolation, and ra and rb the true values.
+	ra, rb := fa, fb
+	var side int
+	for {
+		x, fx := a, ra
+		if math.Abs(rb) < math.Abs(ra) {
+			x, fx = b, rb
+		}
+		if s.Iterate(a, b, x, fx) {
+			return
+		}
+		c := (a*fb - b*fa) / (fb - fa)
+		if !(a < c && c < b) {
+			c = a + (b-a)/2
+		}
+		fc, done := s.Eval(c)
+		if done {
+			return
+		}
+		if math.Signbit(fc) == math.Signbit(rb) {
+			b, fb, rb = c, fc, fc
+			if side == -1 {
+				fa /= 2
+			}
+			side = -1
+		} else {
+			a, fa, ra = c, fc, fc
+			if side == 1 {
+				fb /= 2
+			}
+			side = 1
+		}
+	}
+}
+
+// Ridders is Ridders' method for finding a root of a function of one
+// variable. Each iteration evaluates the function at the midpoint of the
+// bracketing interval and at a point found by exponential interpolation,
+// giving quadratic convergence per iteration for smooth functions. The
+// search also terminates with StepConvergence when successive interpolated
+// points differ by no more than XAbsTol + XRelTol*|x|.
+//
+// The algorithm is described in
+//  C. Ridders, "A new algorithm for computing a single root of a real
+//  continuous function", IEEE Transactions on Circuits and Systems 26,
+//  979-980 (1979).
+type Ridders struct{}
+
+func (Ridders) FindRoot(s *ScalarSearch, a, fa, b, fb float64) {
+	prev, fprev := math.NaN(), math.NaN()
+	for {
+		x, fx := a, fa
+		if math.Abs(fb) < math.Abs(fa) {
+			x, fx = b, fb
+		}
+		if s.Iterate(a, b, x, fx) {
+			return
+		}
+		m := a + (b-a)/2
+		fm, done := s.Eval(m)
+		if done {
+			return
+		}
+		// fa and fb have opposite signs, so the square root is real
+		// and at least |fm|.
+		sq := math.Sqrt(fm*fm - fa*fb)
+		c := m + (m-a)*math.Copysign(1, fa-fb)*fm/sq
+		// The new bracket is often only half as wide as the old one, so
+		// test for convergence on the change of the estimate as well.
+		if math.Abs(c-prev) <= s.Tol(c) {
+			s.Record(prev, fprev)
+			s.Terminate(StepConvergence)
+			return
+		}
+		if !(a < c && c < b) {
+			c = m
+		}
+		fc := fm
+		if c != m {
+			fc, done = s.Eval(c)
+			if done {
+				return
+			}
+		}
+		prev, fprev = c, fc
+		lo, flo, hi, fhi := m, fm, c, fc
+		if c < m {
+			lo, flo, hi, fhi = c, fc, m, fm
+		}
+		switch {
+		case math.Signbit(flo) != math.Signbit(fhi):
+			a, fa, b, fb = lo, flo, hi, fhi
+		case math.Signbit(fa) != math.Signbit(flo):
+			b, fb = lo, flo
+		default:
+			a, fa = hi, fhi
+		}
+	}
+}
+
+// TOMS748 is the root finding method of Alefeld, Potra and Shi, published
+// as algorithm 748 of ACM Transactions on Mathematical Software. Each
+// iteration takes two steps of inverse cubic interpolation, a double-length
+// secant step and, if the bracketing interval has not shrunk enough, a
+// bisection step. The method has the best asymptotic efficiency among
+// known bracketing methods for smooth functions.
+//
+// The algorithm is described in
+//  G. E. Alefeld, F. A. Potra and Y. Shi, "Algorithm 748: Enclosing zeros
+//  of continuous functions", ACM Transactions on Mathematical Software 21,
+//  327-344 (1995).
+type TOMS748 struct{}
+
+func (TOMS748) FindRoot(s *ScalarSearch, a, fa, b, fb float64) {
+	const mu = 0.5
+	t := toms748{s: s, a: a, fa: fa, b: b, fb: fb}
+
+	// The first step is a secant step and the second a quadratic
+	// interpolation step, which provide the points needed for cubic
+	// interpolation.
+	if t.done() || t.bracket(secantStep(t.a, t.b, t.fa, t.fb)) {
+		return
+	}
+	t.e, t.fe = t.d, t.fd
+	if t.done() || t.bracket(newtonQuadratic(t.a, t.b, t.d, t.fa, t.fb, t.fd, 2)) {
+		return
+	}
+	for {
+		if t.done() {
+			return
+		}
+		a0, b0 := t.a, t.b
+
+		// Two interpolation steps.
+		for _, k := range []int{2, 3} {
+			c := t.interpolate(k)
+			t.e, t.fe = t.d, t.fd
+			if t.bracket(c) || t.converged() {
+				return
+			}
+		}
+
+		// A double-length secant step from the end with the smaller
+		// function value.
+		u, fu := t.a, t.fa
+		if math.Abs(t.fb) < math.Abs(t.fa) {
+			u, fu = t.b, t.fb
+		}
+		c := u - 2*fu/(t.fb-t.fa)*(t.b-t.a)
+		if math.Abs(c-u) > (t.b-t.a)/2 {
+			c = t.a + (t.b-t.a)/2
+		}
+		t.e, t.fe = t.d, t.fd
+		if t.bracket(c) || t.converged() {
+			return
+		}
+
+		// Bisect if the interval has not shrunk enough.
+		if t.b-t.a < mu*(b0-a0) {
+			continue
+		}
+		t.e, t.fe = t.d, t.fd
+		if t.bracket(t.a + (t.b-t.a)/2) {
+			return
+		}
+	}
+}
+
+// toms748 holds the state of the TOMS748 method. The root is in [a, b],
+// d is the point most recently removed from the interval and e the point
+// removed before d.
+type toms748 struct {
+	s              *ScalarSearch
+	a, b, d, e     float64
+	fa, fb, fd, fe float64
+}
+
+// best returns the end of the interval with the smaller function value.
+func (t *toms748) best() (x, fx float64) {
+	if math.Abs(t.fb) < math.Abs(t.fa) {
+		return t.b, t.fb
+	}
+	return t.a, t.fa
+}
+
+// done records the current estimate and starts a new iteration.
+func (t *toms748) done() bool {
+	x, fx := t.best()
+	return t.s.Iterate(t.a, t.b, x, fx)
+}
+
+// converged records the current estimate and checks for convergence
+// within an iteration.
+func (t *toms748) converged() bool {
+	x, fx := t.best()
+	t.s.Record(x, fx)
+	return t.s.Converged(t.a, t.b, x)
+}
+
+// bracket evaluates the function at c, adjusted to lie inside the interval,
+// and shrinks the interval to the part containing the root. It returns
+// whether the search must terminate.
+func (t *toms748) bracket(c float64) (done bool) {
+	delta := t.s.Tol(c) / 2
+	switch {
+	case t.b-t.a < 4*delta:
+		c = t.a + (t.b-t.a)/2
+	case c <= t.a+delta:
+		c = t.a + delta
+	case c >= t.b-delta:
+		c = t.b - delta
+	}
+	fc, done := t.s.Eval(c)
+	if done {
+		return true
+	}
+	if math.Signbit(t.fa) != math.Signbit(fc) {
+		t.d, t.fd = t.b, t.fb
+		t.b, t.fb = c, fc
+	} else {
+		t.d, t.fd = t.a, t.fa
+		t.a, t.fa = c, fc
+	}
+	return false
+}
+
+// interpolate returns the next interpolation point, using inverse cubic
+// interpolation through a, b, d and e if their function values are
+// distinct, and k steps of Newton's method on the quadratic through a, b
+// and d otherwise.
+func (t *toms748) interpolate(k int) float64 {
+	const minDiff = 32 * math.SmallestNonzeroFloat64
+	fs := [4]float64{t.fa, t.fb, t.fd, t.fe}
+	for i := range fs {
+		for j := i + 1; j < len(fs); j++ {
+			if math.Abs(fs[i]-fs[j]) < minDiff {
+				return newtonQuadratic(t.a, t.b, t.d, t.fa, t.fb, t.fd, k)
+			}
+		}
+	}
+	a, b, d, e := t.a, t.b, t.d, t.e
+	fa, fb, fd, fe := t.fa, t.fb, t.fd, t.fe
+	q11 := (d - e) * fd / (fe - fd)
+	q21 := (b - d) * fb / (fd - fb)
+	q31 := (a - b) * fa / (fb - fa)
+	d21 := (b - d) * fd / (fd - fb)
+	d31 := (a - b) * fb / (fb - fa)
+	q22 := (d21 - q11) * fb / (fe - fb)
+	q32 := (d31 - q21) * fa / (fd - fa)
+	d32 := (d31 - q21) * fd / (fd - fa)
+	q33 := (d32 - q22) * fa / (fe - fa)
+	c := a + q31 + q32 + q33
+	if !(a < c && c < b) {
+		return newtonQuadratic(a, b, d, fa, fb, fd, 3)
+	}
+	return c
+}
+
+// secantStep returns the secant step in [a, b], or the midpoint if the
+// step is too close to either end.
+func secantStep(a, b, fa, fb float64) float64 {
+	const tol = 5 * 0x1p-52
+	c := a - fa/(fb-fa)*(b-a)
+	if c <= a+math.Abs(a)*tol || c >= b-math.Abs(b)*tol {
+		return a + (b-a)/2
+	}
+	return c
+}
+
+// newtonQuadratic returns the result of k Newton steps on the quadratic
+// interpolating f at a, b and d, falling back to a secant step if the
+// result is outside [a, b].
+func newtonQuadratic(a, b, d, fa, fb, fd float64, k int) float64 {
+	safeDiv := func(num, den, r float64) float64 {
+		if math.Abs(den) < 1 && math.Abs(den*math.MaxFloat64) <= math.Abs(num) {
+			return r
+		}
+		return num / den
+	}
+	bb := safeDiv(fb-fa, b-a, math.MaxFloat64)
+	aa := safeDiv(fd-fb, d-b, math.MaxFloat64)
+	aa = safeDiv(aa-bb, d-a, 0)
+	if aa == 0 {
+		return secantStep(a, b, fa, fb)
+	}
+	c := b
+	if math.Signbit(aa) == math.Signbit(fa) {
+		c = a
+	}
+	for i := 0; i < k; i++ {
+		c -= safeDiv(fa+(bb+aa*(c-b))*(c-a), bb+aa*(2*c-a-b), 1+c-a)
+	}
+	if !(a < c && c < b) {
+		return secantStep(a, b, fa, fb)
+	}
+	return c
+}
@@ -0,0 +1,348 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/num/hyperdual"
+)
+
+// rootTests are test functions for root finding with a bracketing
+// interval and the root in that interval. The functions include several
+// of those used by Alefeld, Potra and Shi.
+var rootTests = []struct {
+	name string
+	f    func(float64) float64
+	a, b float64
+	root float64
+}{
+	{
+		name: "cubic",
+		f:    func(x float64) float64 { return x*x*x - 2*x - 5 },
+		a:    2, b: 3,
+		root: 2.0945514815423265,
+	},
+	{
+		name: "cos",
+		f:    func(x float64) float64 { return math.Cos(x) - x },
+		a:    0, b: 1,
+		root: 0.7390851332151607,
+	},
+	{
+		name: "sin",
+		f:    func(x float64) float64 { return math.Sin(x) - x/2 },
+		a:    math.Pi / 2, b: math.Pi,
+		root: 1.895494267033981,
+	},
+	{
+		name: "exp",
+		f:    func(x float64) float64 { return math.Exp(x) - 1e4 },
+		a:    -5, b: 20,
+		root: math.Log(1e4),
+	},
+	{
+		name: "steep",
+		f:    func(x float64) float64 { return math.Pow(x, 19) - 0.5 },
+		a:    0, b: 2,
+		root: math.Pow(0.5, 1.0/19),
+	},
+	{
+		name: "flat",
+		f:    func(x float64) float64 { return math.Pow(x-1, 3) },
+		a:    0, b: 3.5,
+		root: 1,
+	},
+	{
+		name: "exponential",
+		f:    func(x float64) float64 { return 2*x*math.Exp(-5) - 2*math.Exp(-5*x) + 1 },
+		a:    0, b: 1,
+		root: 0.1382571550568241,
+	},
+	{
+		name: "step",
+		f: func(x float64) float64 {
+			if x < 1.0/3 {
+				return -1
+			}
+			return 1
+		},
+		a: 0, b: 1,
+		root: 1.0 / 3,
+	},
+	{
+		name: "negative",
+		f:    func(x float64) float64 { return math.Atan(x + 1e3) },
+		a:    -2e3, b: 5,
+		root: -1e3,
+	},
+}
+
+func TestFindRoot(t *testing.T) {
+	t.Parallel()
+	for _, method := range []struct {
+		name   string
+		finder RootFinder
+	}{
+		{name: "default"},
+		{name: "Brent", finder: BrentRoot{}},
+		{name: "Illinois", finder: Illinois{}},
+		{name: "Ridders", finder: Ridders{}},
+		{name: "TOMS748", finder: TOMS748{}},
+	} {
+		for _, test := range rootTests {
+			for _, tol := range []float64{0, 1e-6} {
+				// Convergence to the triple root is only linear.
+				settings := &ScalarSettings{XAbsTol: tol, MajorIterations: 1000}
+				res, err := FindRoot(test.f, test.a, test.b, method.finder, settings)
+				if err != nil {
+					t.Errorf("%s %s: unexpected error: %v", method.name, test.name, err)
+					continue
+				}
+				if res.Status != StepConvergence && res.Status != FunctionThreshold {
+					t.Errorf("%s %s: unexpected status: %v", method.name, test.name, res.Status)
+				}
+				want := 2 * (tol + 1e-11 + 1e-14*math.Abs(test.root))
+				if tol == 0 && test.name == "flat" {
+					// A triple root is only determined to the cube root
+					// of machine precision by the function values.
+					want = 1e-5
+				}
+				if math.Abs(res.X-test.root) > want {
+					t.Errorf("%s %s tol=%v: unexpected root: got %v, want %v", method.name, test.name, tol, res.X, test.root)
+				}
+				if res.F != test.f(res.X) {
+					t.Errorf("%s %s: function value mismatch at root", method.name, test.name)
+				}
+				if res.FuncEvaluations > 200 {
+					t.Errorf("%s %s: too many evaluations: %d", method.name, test.name, res.FuncEvaluations)
+				}
+			}
+		}
+	}
+}
+
+func TestFindRootEfficiency(t *testing.T) {
+	t.Parallel()
+	// The superlinear methods must need far fewer evaluations than
+	// bisection on a smooth function.
+	cubic := func(x float64) float64 { return x*x*x - 2*x - 5 }
+	fast := []RootFinder{BrentRoot{}, Ridders{}, TOMS748{}}
+	for _, test := range []struct {
+		name    string
+		f       func(float64) float64
+		a, b    float64
+		methods []RootFinder
+		evals   int
+	}{
+		{
+			name:    "exp",
+			f:       func(x float64) float64 { return math.Exp(x) - 2 - x },
+			a:       0,
+			b:       3,
+			methods: append(fast, Illinois{}),
+			evals:   15,
+		},
+		{
+			name:    "cubic",
+			f:       cubic,
+			a:       2,
+			b:       3,
+			methods: fast,
+			evals:   15,
+		},
+		{
+			name:    "cubic wide",
+			f:       cubic,
+			a:       -1,
+			b:       20,
+			methods: fast,
+			evals:   20,
+		},
+	} {
+		for _, method := range test.methods {
+			res, err := FindRoot(test.f, test.a, test.b, method, nil)
+			if err != nil {
+				t.Fatalf("%s %T: unexpected error: %v", test.name, method, err)
+			}
+			if res.FuncEvaluations > test.evals {
+				t.Errorf("%s %T: too many function evaluations: %d", test.name, method, res.FuncEvaluations)
+			}
+		}
+	}
+}
+
+// bisection is a RootFinder implemented outside the package's methods.
+type bisection struct{}
+
+func (bisection) FindRoot(s *ScalarSearch, a, fa, b, fb float64) {
+	for {
+		x, fx := a, fa
+		if math.Abs(fb) < math.Abs(fa) {
+			x, fx = b, fb
+		}
+		if s.Iterate(a, b, x, fx) {
+			return
+		}
+		m := a + (b-a)/2
+		fm, done := s.Eval(m)
+		if done {
+			return
+		}
+		if math.Signbit(fm) == math.Signbit(fa) {
+			a, fa = m, fm
+		} else {
+			b, fb = m, fm
+		}
+	}
+}
+
+func TestFindRootUserMethod(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return x*x - 2 }
+	res, err := FindRoot(f, 0, 2, bisection{}, nil)
+	if err != nil || res.Status != StepConvergence {
+		t.Fatalf("unexpected termination: %v, %v", res.Status, err)
+	}
+	if math.Abs(res.X-math.Sqrt2) > 1e-11 {
+		t.Errorf("unexpected root: got %v, want %v", res.X, math.Sqrt2)
+	}
+}
+
+func TestFindRootTermination(t *testing.T) {
+	t.Parallel()
+	f := func(x float64) float64 { return x*x - 2 }
+
+	if _, err := FindRoot(f, 2, 3, nil, nil); err != ErrNoSignChange {
+		t.Errorf("unexpected error for interval without sign change: %v", err)
+	}
+
+	res, err := FindRoot(f, -1, 2, Illinois{}, &ScalarSettings{MajorIterations: 2})
+	if res.Status != IterationLimit || err == nil {
+		t.Errorf("unexpected status with iteration limit: %v, %v", res.Status, err)
+	}
+
+	res, _ = FindRoot(f, 0, 2, nil, &ScalarSettings{FuncEvaluations: 4})
+	if res.Status != FunctionEvaluationLimit || res.FuncEvaluations != 4 {
+		t.Errorf("unexpected result with evaluation limit: %v after %d evaluations", res.Status, res.FuncEvaluations)
+	}
+	if math.Abs(res.X-math.Sqrt2) > 1 {
+		t.Errorf("unexpected estimate with evaluation limit: %v", res.X)
+	}
+
+	res, err = FindRoot(f, 0, 2, nil, &ScalarSettings{FunctionThreshold: 1e-3})
+	if err != nil || res.Status != FunctionThreshold || math.Abs(res.F) > 1e-3 {
+		t.Errorf("unexpected result with function threshold: %+v, %v", res, err)
+	}
+
+	res, err = FindRoot(func(x float64) float64 { return x - 1 }, 1, 2, nil, nil)
+	if err != nil || res.X != 1 || res.Status != FunctionThreshold {
+		t.Errorf("unexpected result with root at interval end: %+v, %v", res, err)
+	}
+}
+
+func TestBracketRoot(t *testing.T) {
+	t.Parallel()
+	for _, test := range rootTests {
+		if test.name == "step" {
+			// The expansion cannot be guided by a piecewise constant
+			// function.
+			continue
+		}
+		// Start from a small interval away from the root.
+		a := test.root + 0.1*math.Abs(test.root) + 0.01
+		lo, hi, err := BracketRoot(test.f, a, a+0.01, 100)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Signbit(test.f(lo)) == math.Signbit(test.f(hi)) {
+			t.Errorf("%s: no sign change in [%v, %v]", test.name, lo, hi)
+		}
+	}
+	if _, _, err := BracketRoot(func(x float64) float64 { return x*x + 1 }, 0, 1, 50); err != ErrNoBracket {
+		t.Errorf("unexpected error for function without roots: %v", err)
+	}
+}
+
+func TestNewtonRoot(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		f      func(dual.Number) dual.Number
+		fh     func(hyperdual.Number) hyperdual.Number
+		x0     float64
+		root   float64
+		newton int // Maximum number of Newton evaluations.
+		halley int // Maximum number of Halley evaluations.
+	}{
+		{
+			name: "cubic",
+			f: func(x dual.Number) dual.Number {
+				return dual.Sub(dual.Mul(x, dual.Mul(x, x)), dual.Add(dual.Scale(2, x), dual.Number{Real: 5}))
+			},
+			fh: func(x hyperdual.Number) hyperdual.Number {
+				return hyperdual.Sub(hyperdual.Mul(x, hyperdual.Mul(x, x)), hyperdual.Add(hyperdual.Scale(2, x), hyperdual.Number{Real: 5}))
+			},
+			x0: 2, root: 2.0945514815423265,
+			newton: 7, halley: 5,
+		},
+		{
+			name: "cos",
+			f: func(x dual.Number) dual.Number {
+				return dual.Sub(dual.Cos(x), x)
+			},
+			fh: func(x hyperdual.Number) hyperdual.Number {
+				return hyperdual.Sub(hyperdual.Cos(x), x)
+			},
+			x0: 1, root: 0.7390851332151607,
+			newton: 7, halley: 5,
+		},
+		{
+			name: "exp",
+			f: func(x dual.Number) dual.Number {
+				return dual.Sub(dual.Exp(x), dual.Number{Real: 10})
+			},
+			fh: func(x hyperdual.Number) hyperdual.Number {
+				return hyperdual.Sub(hyperdual.Exp(x), hyperdual.Number{Real: 10})
+			},
+			x0: 0, root: math.Log(10),
+			newton: 20, halley: 10,
+		},
+	} {
+		res, err := NewtonRoot(test.f, test.x0, nil)
+		if err != nil {
+			t.Errorf("Newton %s: unexpected error: %v", test.name, err)
+		} else {
+			if math.Abs(res.X-test.root) > 1e-12 {
+				t.Errorf("Newton %s: unexpected root: got %v, want %v", test.name, res.X, test.root)
+			}
+			if res.FuncEvaluations > test.newton || res.GradEvaluations != res.FuncEvaluations {
+				t.Errorf("Newton %s: unexpected evaluations: %+v", test.name, res.Stats)
+			}
+		}
+		res, err = HalleyRoot(test.fh, test.x0, nil)
+		if err != nil {
+			t.Errorf("Halley %s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if math.Abs(res.X-test.root) > 1e-12 {
+			t.Errorf("Halley %s: unexpected root: got %v, want %v", test.name, res.X, test.root)
+		}
+		if res.FuncEvaluations > test.halley || res.HessEvaluations != res.FuncEvaluations {
+			t.Errorf("Halley %s: unexpected evaluations: %+v", test.name, res.Stats)
+		}
+	}
+
+	// The derivative of x² + 1 is zero at 0.
+	_, err := NewtonRoot(func(x dual.Number) dual.Number {
+		return dual.Add(dual.Mul(x, x), dual.Number{Real: 1})
+	}, 0, nil)
+	if err != ErrZeroDerivative {
+		t.Errorf("unexpected error at zero derivative: %v", err)
+	}
+}
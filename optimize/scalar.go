@@ -0,0 +1,415 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	defaultScalarAbsTol     = 2e-12
+	defaultScalarIterations = 100
+
+	// minScalarRelTol is the smallest relative tolerance on the location
+	// of a root or minimum that can be achieved in floating point.
+	minScalarRelTol = 4 * 0x1p-52
+)
+
+var (
+	// ErrNoSignChange signifies that the values of a function at the ends
+	// of an interval given to FindRoot do not have opposite signs.
+	ErrNoSignChange = errors.New("optimize: function values at interval ends do not have opposite signs")
+
+	// ErrNoBracket signifies that BracketRoot or BracketMinimum failed to
+	// find a bracketing interval.
+	ErrNoBracket = errors.New("optimize: no bracketing interval found")
+
+	// ErrZeroDerivative signifies that a Newton-type root finder reached
+	// a point with a zero derivative.
+	ErrZeroDerivative = errors.New("optimize: zero derivative")
+)
+
+// ScalarSettings holds the settings for finding roots and minima of scalar
+// functions of one variable.
+type ScalarSettings struct {
+	// XAbsTol and XRelTol are the absolute and relative tolerances on the
+	// location x of the root or minimum. The search terminates with
+	// StepConvergence status when the interval known to contain the root
+	// or minimum extends no further than 2*(XAbsTol + XRelTol*|x|) from x.
+	// Newton-type methods terminate with StepConvergence when the step is
+	// no larger than XAbsTol + XRelTol*|x|.
+	// If XAbsTol is zero, it defaults to 2e-12. XRelTol is raised to
+	// 4 times machine epsilon if it is smaller.
+	XAbsTol, XRelTol float64
+
+	// FunctionThreshold stops a root search with FunctionThreshold status
+	// when |f(x)| is less than or equal to this value. A search always
+	// stops with this status when f(x) is zero. FunctionThreshold has no
+	// effect on minimization.
+	FunctionThreshold float64
+
+	// MajorIterations is the maximum number of iterations allowed.
+	// IterationLimit status is returned if the number of iterations
+	// equals or exceeds this value. If it equals zero, a default of 100
+	// is used.
+	MajorIterations int
+
+	// FuncEvaluations is the maximum allowed number of function evaluations.
+	// FunctionEvaluationLimit status is returned if the total number of
+	// function evaluations equals or exceeds this number.
+	// If it equals zero, this setting has no effect.
+	FuncEvaluations int
+}
+
+// ScalarResult holds the result of finding a root or minimum of a scalar
+// function of one variable.
+type ScalarResult struct {
+	// X is the location of the root or minimum and F is the function
+	// value at X.
+	X, F float64
+
+	Stats
+	Status Status
+}
+
+// RootFinder is a bracketing method for finding a root of a continuous
+// function of one variable. RootFinder is implemented by BrentRoot,
+// Illinois, Ridders and TOMS748.
+type RootFinder interface {
+	// FindRoot searches for a root in [a, b], where fa and fb are the
+	// function values at a and b, have opposite signs and are not zero.
+	// The function is evaluated with s.Eval, and each iteration is started
+	// with s.Iterate. FindRoot must return as soon as a call to s returns
+	// done as true, and may otherwise terminate the search with s.Record
+	// and s.Terminate.
+	FindRoot(s *ScalarSearch, a, fa, b, fb float64)
+}
+
+// ScalarMinimizer is a method for finding a local minimum of a function of
+// one variable on an interval. ScalarMinimizer is implemented by
+// BrentMinimizer and GoldenSection.
+type ScalarMinimizer interface {
+	// Minimize searches for a minimum in [a, b] with a < b. The function
+	// is evaluated with s.Eval, and each iteration is started with
+	// s.Iterate. Minimize must return as soon as a call to s returns done
+	// as true.
+	Minimize(s *ScalarSearch, a, b float64)
+}
+
+// FindRoot finds a root of the continuous function f in the interval
+// [a, b] using the given method. The values of f at a and b must have
+// opposite signs, otherwise FindRoot returns ErrNoSignChange, unless one
+// of them is zero. If method is nil, BrentRoot is used. If settings is nil,
+// the defaults described for ScalarSettings are used.
+//
+// FindRoot returns the best estimate of the root and an error if the
+// search ended early as indicated by Status.Err.
+func FindRoot(f func(x float64) float64, a, b float64, method RootFinder, settings *ScalarSettings) (*ScalarResult, error) {
+	if f == nil {
+		panic(badProblem)
+	}
+	checkInterval(a, b)
+	if a > b {
+		a, b = b, a
+	}
+	if method == nil {
+		method = BrentRoot{}
+	}
+	s := newScalarSearch(f, settings, true)
+
+	fa, done := s.Eval(a)
+	if done {
+		return s.finish()
+	}
+	fb, done := s.Eval(b)
+	if done {
+		return s.finish()
+	}
+	if math.Signbit(fa) == math.Signbit(fb) {
+		return nil, ErrNoSignChange
+	}
+	s.Record(a, fa)
+	if math.Abs(fb) < math.Abs(fa) {
+		s.Record(b, fb)
+	}
+	method.FindRoot(s, a, fa, b, fb)
+	return s.finish()
+}
+
+// MinimizeScalar finds a local minimum of f in the interval [a, b] using
+// the given method. If method is nil, BrentMinimizer is used. If settings
+// is nil, the defaults described for ScalarSettings are used.
+//
+// The methods converge to the global minimum in [a, b] if f is unimodal
+// on [a, b]. BracketMinimum may be used to find such an interval.
+// MinimizeScalar returns the best location found and an error if the
+// search ended early as indicated by Status.Err.
+func MinimizeScalar(f func(x float64) float64, a, b float64, method ScalarMinimizer, settings *ScalarSettings) (*ScalarResult, error) {
+	if f == nil {
+		panic(badProblem)
+	}
+	checkInterval(a, b)
+	if a > b {
+		a, b = b, a
+	}
+	if method == nil {
+		method = BrentMinimizer{}
+	}
+	s := newScalarSearch(f, settings, false)
+	method.Minimize(s, a, b)
+	return s.finish()
+}
+
+func checkInterval(a, b float64) {
+	if a == b || math.IsNaN(a) || math.IsNaN(b) || math.IsInf(a, 0) || math.IsInf(b, 0) {
+		panic("optimize: invalid interval")
+	}
+}
+
+// ScalarSearch holds the state of a search for a root or minimum of a
+// scalar function by FindRoot or MinimizeScalar, and checks for
+// termination. It is passed to the RootFinder and ScalarMinimizer methods,
+// which evaluate the function and report their progress through it.
+type ScalarSearch struct {
+	f        func(float64) float64
+	settings ScalarSettings
+	root     bool
+	start    time.Time
+
+	result ScalarResult
+	found  bool
+}
+
+func newScalarSearch(f func(float64) float64, settings *ScalarSettings, root bool) *ScalarSearch {
+	s := &ScalarSearch{f: f, root: root, start: time.Now()}
+	if settings != nil {
+		s.settings = *settings
+	}
+	if s.settings.XAbsTol < 0 || s.settings.XRelTol < 0 || s.settings.FunctionThreshold < 0 {
+		panic("optimize: negative tolerance")
+	}
+	if s.settings.XAbsTol == 0 {
+		s.settings.XAbsTol = defaultScalarAbsTol
+	}
+	if s.settings.XRelTol < minScalarRelTol {
+		s.settings.XRelTol = minScalarRelTol
+	}
+	if s.settings.MajorIterations == 0 {
+		s.settings.MajorIterations = defaultScalarIterations
+	}
+	s.result.X = math.NaN()
+	s.result.F = math.NaN()
+	return s
+}
+
+// Tol returns the absolute tolerance on a root or minimum at x.
+func (s *ScalarSearch) Tol(x float64) float64 {
+	return s.settings.XAbsTol + s.settings.XRelTol*math.Abs(x)
+}
+
+// Record sets the current estimate x with value fx of the root or minimum.
+// A minimization records the best point evaluated by Eval automatically.
+func (s *ScalarSearch) Record(x, fx float64) {
+	s.result.X = x
+	s.result.F = fx
+	s.found = true
+}
+
+// Eval returns the value of f at x. It returns done as true if the search
+// must terminate, either before evaluating f because the evaluation limit
+// has been reached, or after because the value is a root to within the
+// function threshold or is NaN.
+func (s *ScalarSearch) Eval(x float64) (fx float64, done bool) {
+	if s.settings.FuncEvaluations > 0 && s.result.FuncEvaluations >= s.settings.FuncEvaluations {
+		s.result.Status = FunctionEvaluationLimit
+		return math.NaN(), true
+	}
+	fx = s.f(x)
+	s.result.FuncEvaluations++
+	return fx, s.evaluated(x, fx)
+}
+
+// evaluated checks the function value fx at x for termination.
+func (s *ScalarSearch) evaluated(x, fx float64) (done bool) {
+	switch {
+	case math.IsNaN(fx):
+		s.result.Status = Failure
+		return true
+	case s.root && math.Abs(fx) <= s.settings.FunctionThreshold:
+		s.Record(x, fx)
+		s.result.Status = FunctionThreshold
+		return true
+	case !s.root && (!s.found || fx < s.result.F):
+		// The best point found is always reported by a minimization.
+		s.Record(x, fx)
+	}
+	return false
+}
+
+// Converged returns whether the interval [lo, hi] is small enough about x
+// for the search to terminate, in which case the search terminates with
+// StepConvergence status.
+func (s *ScalarSearch) Converged(lo, hi, x float64) bool {
+	if math.Max(x-lo, hi-x) <= 2*s.Tol(x) {
+		s.result.Status = StepConvergence
+		return true
+	}
+	return false
+}
+
+// Iterate checks the convergence of the estimate x with value fx of the
+// root or minimum in the interval [lo, hi] and starts a new iteration. A
+// root search also records x as its estimate. Iterate returns done as true
+// if the search has converged or reached the iteration limit.
+func (s *ScalarSearch) Iterate(lo, hi, x, fx float64) (done bool) {
+	if s.root {
+		s.Record(x, fx)
+	}
+	if s.Converged(lo, hi, x) {
+		return true
+	}
+	return s.nextIteration()
+}
+
+// Terminate terminates the search with the given status, which must not be
+// NotTerminated.
+func (s *ScalarSearch) Terminate(status Status) {
+	if status == NotTerminated {
+		panic("optimize: terminate with NotTerminated status")
+	}
+	s.result.Status = status
+}
+
+// nextIteration starts a new iteration, returning done as true if the
+// iteration limit has been reached.
+func (s *ScalarSearch) nextIteration() (done bool) {
+	if s.result.MajorIterations >= s.settings.MajorIterations {
+		s.result.Status = IterationLimit
+		return true
+	}
+	s.result.MajorIterations++
+	return false
+}
+
+// finish returns the result of the search.
+func (s *ScalarSearch) finish() (*ScalarResult, error) {
+	s.result.Runtime = time.Since(s.start)
+	return &s.result, s.result.Status.Err()
+}
+
+// BracketRoot searches for an interval on which f changes sign by
+// geometrically expanding the interval [a, b] at the end where |f| is
+// smaller, and returns the interval. At most maxEvals function evaluations
+// are made, and ErrNoBracket is returned if no sign change is found.
+// BracketRoot panics if a == b.
+func BracketRoot(f func(x float64) float64, a, b float64, maxEvals int) (lo, hi float64, err error) {
+	const grow = 1.6
+	checkInterval(a, b)
+	if a > b {
+		a, b = b, a
+	}
+	fa := f(a)
+	fb := f(b)
+	for evals := 2; evals < maxEvals; evals++ {
+		if math.Signbit(fa) != math.Signbit(fb) || fa == 0 || fb == 0 {
+			return a, b, nil
+		}
+		if math.Abs(fa) < math.Abs(fb) {
+			a += grow * (a - b)
+			fa = f(a)
+		} else {
+			b += grow * (b - a)
+			fb = f(b)
+		}
+	}
+	if math.Signbit(fa) != math.Signbit(fb) || fa == 0 || fb == 0 {
+		return a, b, nil
+	}
+	return a, b, ErrNoBracket
+}
+
+// BracketMinimum searches downhill from the points a and b for a triple of
+// points lo < mid < hi such that f(mid) is less than or equal to both f(lo)
+// and f(hi), so that [lo, hi] contains a local minimum of a continuous f.
+// Steps grow by the golden ratio, accelerated by parabolic extrapolation.
+// The search stops with ErrNoBracket if no such triple is found after
+// maxEvals function evaluations. BracketMinimum panics if a == b.
+//
+// The algorithm is described in
+//  W. H. Press et al., Numerical Recipes, 3rd ed., section 10.1.
+//  Cambridge University Press, 2007.
+func BracketMinimum(f func(x float64) float64, a, b float64, maxEvals int) (lo, mid, hi float64, err error) {
+	const (
+		golden   = 1.618033988749895
+		maxGrow  = 100
+		tinyDiff = 1e-20
+	)
+	checkInterval(a, b)
+	fa := f(a)
+	fb := f(b)
+	if fb > fa {
+		a, b = b, a
+		fa, fb = fb, fa
+	}
+	c := b + golden*(b-a)
+	fc := f(c)
+	evals := 3
+	order := func(a, b, c float64) (lo, mid, hi float64, err error) {
+		if a > c {
+			a, c = c, a
+		}
+		return a, b, c, nil
+	}
+	for fb > fc {
+		if evals >= maxEvals {
+			return a, b, c, ErrNoBracket
+		}
+		// Extrapolate the parabola through a, b and c, limiting the
+		// step to maxGrow times the current interval.
+		r := (b - a) * (fb - fc)
+		q := (b - c) * (fb - fa)
+		den := 2 * math.Copysign(math.Max(math.Abs(q-r), tinyDiff), q-r)
+		u := b - ((b-c)*q-(b-a)*r)/den
+		ulim := b + maxGrow*(c-b)
+		var fu float64
+		switch {
+		case (b-u)*(u-c) > 0:
+			// The parabolic minimum is between b and c.
+			fu = f(u)
+			evals++
+			if fu < fc {
+				return order(b, u, c)
+			}
+			if fu > fb {
+				return order(a, b, u)
+			}
+			u = c + golden*(c-b)
+			fu = f(u)
+			evals++
+		case (c-u)*(u-ulim) > 0:
+			// The parabolic minimum is between c and the limit.
+			fu = f(u)
+			evals++
+			if fu < fc {
+				b, c, u = c, u, u+golden*(u-c)
+				fb, fc, fu = fc, fu, f(u)
+				evals++
+			}
+		case (u-ulim)*(ulim-c) >= 0:
+			u = ulim
+			fu = f(u)
+			evals++
+		default:
+			u = c + golden*(c-b)
+			fu = f(u)
+			evals++
+		}
+		a, b, c = b, c, u
+		fa, fb, fc = fb, fc, fu
+	}
+	return order(a, b, c)
+}
@@ -0,0 +1,70 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+	"math"
+
+	"gonum.org/v1/gonum/num/dual"
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleFindRoot() {
+	// Find the solution of cos(x) = x.
+	f := func(x float64) float64 { return math.Cos(x) - x }
+
+	// Expand an initial guess of the interval until f changes sign.
+	a, b, err := optimize.BracketRoot(f, 2, 3, 50)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result, err := optimize.FindRoot(f, a, b, optimize.TOMS748{}, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = %.12f\n", result.X)
+	fmt.Println("status:", result.Status)
+
+	// Output:
+	// x = 0.739085133215
+	// status: FunctionThreshold
+}
+
+func ExampleNewtonRoot() {
+	// Find the cube root of 2 with the derivative of x³ - 2 computed by
+	// automatic differentiation.
+	f := func(x dual.Number) dual.Number {
+		return dual.Sub(dual.Mul(x, dual.Mul(x, x)), dual.Number{Real: 2})
+	}
+	result, err := optimize.NewtonRoot(f, 1, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = %.12f\n", result.X)
+
+	// Output:
+	// x = 1.259921049895
+}
+
+func ExampleMinimizeScalar() {
+	f := func(x float64) float64 { return x*x*x*x - 3*x*x + x }
+
+	// Find an interval containing a local minimum, starting downhill
+	// from 0.
+	lo, _, hi, err := optimize.BracketMinimum(f, 0, 0.1, 50)
+	if err != nil {
+		log.Fatal(err)
+	}
+	result, err := optimize.MinimizeScalar(f, lo, hi, nil, &optimize.ScalarSettings{XAbsTol: 1e-8})
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("x = %.6f, f(x) = %.6f\n", result.X, result.F)
+
+	// Output:
+	// x = -1.300840, f(x) = -3.513905
+}
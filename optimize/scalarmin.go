@@ -0,0 +1,152 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+var (
+	_ ScalarMinimizer = BrentMinimizer{}
+	_ ScalarMinimizer = GoldenSection{}
+)
+
+// invPhi is the reciprocal of the golden ratio.
+const invPhi = 0.6180339887498949
+
+// GoldenSection is the golden section search for a minimum of a function of
+// one variable. Each iteration reduces the interval containing the minimum
+// by the golden ratio.
+type GoldenSection struct{}
+
+func (GoldenSection) Minimize(s *ScalarSearch, a, b float64) {
+	x1 := b - invPhi*(b-a)
+	x2 := a + invPhi*(b-a)
+	f1, done := s.Eval(x1)
+	if done {
+		return
+	}
+	f2, done := s.Eval(x2)
+	if done {
+		return
+	}
+	for {
+		// Test for convergence about the better interior point, which
+		// always lies in [a, b], unlike the best point found when its
+		// value ties with another due to rounding.
+		x, fx := x2, f2
+		if f1 < f2 {
+			x, fx = x1, f1
+		}
+		if s.Iterate(a, b, x, fx) {
+			return
+		}
+		if f1 < f2 {
+			b = x2
+			x2, f2 = x1, f1
+			x1 = b - invPhi*(b-a)
+			f1, done = s.Eval(x1)
+		} else {
+			a = x1
+			x1, f1 = x2, f2
+			x2 = a + invPhi*(b-a)
+			f2, done = s.Eval(x2)
+		}
+		if done {
+			return
+		}
+	}
+}
+
+// BrentMinimizer is Brent's method for finding a minimum of a function of one
+// variable. It combines parabolic interpolation with golden section steps,
+// converging superlinearly for smooth functions while never being much
+// slower than golden section search.
+//
+// The algorithm is described in
+//  R. P. Brent, "Algorithms for Minimization without Derivatives",
+//  chapter 5. Prentice-Hall, 1973.
+type BrentMinimizer struct{}
+
+func (BrentMinimizer) Minimize(s *ScalarSearch, a, b float64) {
+	const c = 1 - invPhi
+
+	x := a + c*(b-a)
+	fx, done := s.Eval(x)
+	if done {
+		return
+	}
+	v, w := x, x
+	fv, fw := fx, fx
+	var d, e float64
+	for {
+		if s.Iterate(a, b, x, fx) {
+			return
+		}
+		xm := (a + b) / 2
+		tol1 := s.Tol(x)
+		tol2 := 2 * tol1
+		golden := true
+		if math.Abs(e) > tol1 {
+			// Attempt a parabolic fit through x, v and w.
+			r := (x - w) * (fx - fv)
+			q := (x - v) * (fx - fw)
+			p := (x-v)*q - (x-w)*r
+			q = 2 * (q - r)
+			if q > 0 {
+				p = -p
+			} else {
+				q = -q
+			}
+			if math.Abs(p) < math.Abs(q*e/2) && p > q*(a-x) && p < q*(b-x) {
+				e = d
+				d = p / q
+				u := x + d
+				// Do not evaluate too close to the ends.
+				if u-a < tol2 || b-u < tol2 {
+					d = math.Copysign(tol1, xm-x)
+				}
+				golden = false
+			}
+		}
+		if golden {
+			if x >= xm {
+				e = a - x
+			} else {
+				e = b - x
+			}
+			d = c * e
+		}
+		u := x + d
+		if math.Abs(d) < tol1 {
+			u = x + math.Copysign(tol1, d)
+		}
+		fu, done := s.Eval(u)
+		if done {
+			return
+		}
+		if fu <= fx {
+			if u >= x {
+				a = x
+			} else {
+				b = x
+			}
+			v, fv = w, fw
+			w, fw = x, fx
+			x, fx = u, fu
+			continue
+		}
+		if u < x {
+			a = u
+		} else {
+			b = u
+		}
+		switch {
+		case fu <= fw || w == x:
+			v, fv = w, fw
+			w, fw = u, fu
+		case fu <= fv || v == x || v == w:
+			v, fv = u, fu
+		}
+	}
+}
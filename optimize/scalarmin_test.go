@@ -0,0 +1,158 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+)
+
+var scalarMinTests = []struct {
+	name string
+	f    func(float64) float64
+	a, b float64
+	xmin float64
+}{
+	{
+		name: "quadratic",
+		f:    func(x float64) float64 { return (x - 1.5) * (x - 1.5) },
+		a:    -3, b: 4,
+		xmin: 1.5,
+	},
+	{
+		name: "cos",
+		f:    math.Cos,
+		a:    2, b: 5,
+		xmin: math.Pi,
+	},
+	{
+		name: "abs",
+		f:    func(x float64) float64 { return math.Abs(x - 0.3) },
+		a:    -1, b: 1,
+		xmin: 0.3,
+	},
+	{
+		name: "quartic",
+		f:    func(x float64) float64 { return math.Pow(x-2, 4) + x },
+		a:    0, b: 3,
+		xmin: 2 - math.Cbrt(0.25),
+	},
+	{
+		name: "end",
+		f:    func(x float64) float64 { return math.Exp(x) },
+		a:    1, b: 2,
+		xmin: 1,
+	},
+}
+
+func TestMinimizeScalar(t *testing.T) {
+	t.Parallel()
+	for _, method := range []struct {
+		name string
+		m    ScalarMinimizer
+		// evals is the maximum number of evaluations for the
+		// quadratic test.
+		evals int
+	}{
+		{name: "default", evals: 10},
+		{name: "Brent", m: BrentMinimizer{}, evals: 10},
+		{name: "GoldenSection", m: GoldenSection{}, evals: 80},
+	} {
+		for _, test := range scalarMinTests {
+			settings := &ScalarSettings{XAbsTol: 1e-8}
+			res, err := MinimizeScalar(test.f, test.a, test.b, method.m, settings)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", method.name, test.name, err)
+				continue
+			}
+			if res.Status != StepConvergence {
+				t.Errorf("%s %s: unexpected status: %v", method.name, test.name, res.Status)
+			}
+			// Smooth minima are found to within the square root of the
+			// precision of the function values.
+			tol := 2e-7
+			if math.Abs(res.X-test.xmin) > tol {
+				t.Errorf("%s %s: unexpected minimum: got %v, want %v", method.name, test.name, res.X, test.xmin)
+			}
+			if res.F != test.f(res.X) {
+				t.Errorf("%s %s: function value mismatch at minimum", method.name, test.name)
+			}
+			if test.name == "quadratic" && res.FuncEvaluations > method.evals {
+				t.Errorf("%s %s: too many evaluations: %d", method.name, test.name, res.FuncEvaluations)
+			}
+		}
+	}
+}
+
+func TestMinimizeScalarDefaultSettings(t *testing.T) {
+	t.Parallel()
+	// Near the minimum of a non-symmetric function, the values at the
+	// interior points of the golden section search tie due to rounding,
+	// which must not prevent convergence with the default tolerances.
+	f := func(x float64) float64 { return (x-1.3)*(x-1.3) + 0.1*math.Sin(x) }
+	// The minimum is the root of the derivative 2(x - 1.3) + 0.1 cos(x).
+	xmin := 1.3
+	for i := 0; i < 10; i++ {
+		xmin -= (2*(xmin-1.3) + 0.1*math.Cos(xmin)) / (2 - 0.1*math.Sin(xmin))
+	}
+	for _, method := range []ScalarMinimizer{BrentMinimizer{}, GoldenSection{}} {
+		res, err := MinimizeScalar(f, -3, 5, method, nil)
+		if err != nil || res.Status != StepConvergence {
+			t.Errorf("%T: unexpected termination: %v, %v", method, res.Status, err)
+			continue
+		}
+		if math.Abs(res.X-xmin) > 1e-7 {
+			t.Errorf("%T: unexpected minimum: got %v, want %v", method, res.X, xmin)
+		}
+		if res.FuncEvaluations > 80 {
+			t.Errorf("%T: too many evaluations: %d", method, res.FuncEvaluations)
+		}
+	}
+}
+
+func TestMinimizeScalarLimits(t *testing.T) {
+	t.Parallel()
+	res, err := MinimizeScalar(math.Cos, 2, 5, GoldenSection{}, &ScalarSettings{MajorIterations: 5})
+	if res.Status != IterationLimit || err == nil {
+		t.Errorf("unexpected status with iteration limit: %v, %v", res.Status, err)
+	}
+	res, _ = MinimizeScalar(math.Cos, 2, 5, nil, &ScalarSettings{FuncEvaluations: 3})
+	if res.Status != FunctionEvaluationLimit || res.FuncEvaluations != 3 {
+		t.Errorf("unexpected result with evaluation limit: %v after %d evaluations", res.Status, res.FuncEvaluations)
+	}
+}
+
+func TestBracketMinimum(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name string
+		f    func(float64) float64
+		a, b float64
+	}{
+		{name: "quadratic", f: func(x float64) float64 { return (x - 10) * (x - 10) }, a: 0, b: 1},
+		{name: "reversed", f: func(x float64) float64 { return (x + 10) * (x + 10) }, a: 0, b: 1},
+		{name: "cos", f: math.Cos, a: 0.1, b: 0.2},
+		{name: "quartic", f: func(x float64) float64 { return math.Pow(x-50, 4) }, a: 0, b: 0.01},
+	} {
+		lo, mid, hi, err := BracketMinimum(test.f, test.a, test.b, 100)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !(lo < mid && mid < hi) || test.f(mid) > test.f(lo) || test.f(mid) > test.f(hi) {
+			t.Errorf("%s: invalid bracket %v, %v, %v", test.name, lo, mid, hi)
+		}
+		res, err := MinimizeScalar(test.f, lo, hi, nil, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error minimizing in bracket: %v", test.name, err)
+		}
+		if res.F > test.f(mid)+1e-12 {
+			t.Errorf("%s: minimum in bracket higher than bracket midpoint", test.name)
+		}
+	}
+	if _, _, _, err := BracketMinimum(math.Exp, 0, 1, 20); err != ErrNoBracket {
+		t.Errorf("unexpected error for function without minimum: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+var (
+	_ LearningRateSchedule = ConstantRate(0)
+	_ LearningRateSchedule = StepDecay{}
+	_ LearningRateSchedule = ExponentialDecay{}
+	_ LearningRateSchedule = CosineDecay{}
+	_ LearningRateSchedule = Warmup{}
+)
+
+// LearningRateSchedule gives the learning rate of a StochasticMethod.
+type LearningRateSchedule interface {
+	// Rate returns the learning rate for the update with index step,
+	// counted from zero over all epochs, in the epoch with index epoch.
+	Rate(step, epoch int) float64
+}
+
+// ConstantRate is a constant learning rate.
+type ConstantRate float64
+
+func (r ConstantRate) Rate(step, epoch int) float64 {
+	return float64(r)
+}
+
+// StepDecay multiplies the learning rate by Factor every Epochs epochs,
+//  η_k = Initial * Factor^⌊k/Epochs⌋
+// in epoch k. If Epochs is 0, the rate is decayed in every epoch.
+type StepDecay struct {
+	Initial float64
+	Factor  float64
+	Epochs  int
+}
+
+func (s StepDecay) Rate(step, epoch int) float64 {
+	if s.Epochs > 1 {
+		epoch /= s.Epochs
+	}
+	return s.Initial * math.Pow(s.Factor, float64(epoch))
+}
+
+// ExponentialDecay decays the learning rate exponentially in the number of
+// updates,
+//  η_t = Initial * Decay^(t/Steps)
+// for update t. If Steps is 0, it is taken to be 1.
+type ExponentialDecay struct {
+	Initial float64
+	Decay   float64
+	Steps   int
+}
+
+func (e ExponentialDecay) Rate(step, epoch int) float64 {
+	t := float64(step)
+	if e.Steps > 1 {
+		t /= float64(e.Steps)
+	}
+	return e.Initial * math.Pow(e.Decay, t)
+}
+
+// CosineDecay anneals the learning rate from Initial to Final over Steps
+// updates along half a cosine period,
+//  η_t = Final + ½ (Initial - Final) (1 + cos(π t/Steps))
+// as described in
+//  Loshchilov, I., Hutter, F.: SGDR: Stochastic gradient descent with warm
+//  restarts. ICLR (2017)
+// After Steps updates, the rate is Final.
+type CosineDecay struct {
+	Initial float64
+	Final   float64
+	Steps   int
+}
+
+func (c CosineDecay) Rate(step, epoch int) float64 {
+	if step >= c.Steps {
+		return c.Final
+	}
+	return c.Final + 0.5*(c.Initial-c.Final)*(1+math.Cos(math.Pi*float64(step)/float64(c.Steps)))
+}
+
+// Warmup increases the learning rate linearly over the first Steps updates
+// to the rate of Schedule, which is used afterwards,
+//  η_t = (t+1)/Steps * Schedule.Rate(t, k)
+// for update t < Steps in epoch k.
+type Warmup struct {
+	Steps    int
+	Schedule LearningRateSchedule
+}
+
+func (w Warmup) Rate(step, epoch int) float64 {
+	rate := w.Schedule.Rate(step, epoch)
+	if step < w.Steps {
+		rate *= float64(step+1) / float64(w.Steps)
+	}
+	return rate
+}
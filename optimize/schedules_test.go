@@ -0,0 +1,43 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestLearningRateSchedules(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name     string
+		schedule LearningRateSchedule
+		step     int
+		epoch    int
+		want     float64
+	}{
+		{"ConstantRate", ConstantRate(0.1), 100, 10, 0.1},
+		{"StepDecay", StepDecay{Initial: 1, Factor: 0.5, Epochs: 3}, 0, 2, 1},
+		{"StepDecay", StepDecay{Initial: 1, Factor: 0.5, Epochs: 3}, 0, 3, 0.5},
+		{"StepDecay", StepDecay{Initial: 1, Factor: 0.5, Epochs: 3}, 0, 7, 0.25},
+		{"StepDecay", StepDecay{Initial: 1, Factor: 0.5}, 0, 3, 0.125},
+		{"ExponentialDecay", ExponentialDecay{Initial: 2, Decay: 0.5}, 3, 0, 0.25},
+		{"ExponentialDecay", ExponentialDecay{Initial: 2, Decay: 0.5, Steps: 10}, 5, 0, math.Sqrt2},
+		{"CosineDecay", CosineDecay{Initial: 1, Final: 0.1, Steps: 10}, 0, 0, 1},
+		{"CosineDecay", CosineDecay{Initial: 1, Final: 0.1, Steps: 10}, 5, 0, 0.55},
+		{"CosineDecay", CosineDecay{Initial: 1, Final: 0.1, Steps: 10}, 20, 0, 0.1},
+		{"Warmup", Warmup{Steps: 4, Schedule: ConstantRate(1)}, 0, 0, 0.25},
+		{"Warmup", Warmup{Steps: 4, Schedule: ConstantRate(1)}, 3, 0, 1},
+		{"Warmup", Warmup{Steps: 4, Schedule: StepDecay{Initial: 1, Factor: 0.5}}, 10, 2, 0.25},
+	} {
+		got := test.schedule.Rate(test.step, test.epoch)
+		if !floats.EqualWithinAbsOrRel(got, test.want, 1e-14, 1e-14) {
+			t.Errorf("%s %+v: unexpected rate for step %d in epoch %d: got %v, want %v",
+				test.name, test.schedule, test.step, test.epoch, got, test.want)
+		}
+	}
+}
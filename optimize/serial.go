@@ -0,0 +1,141 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+// serialRun runs the iterations of a method that evaluates the function at
+// one location at a time. The method is written as a sequential loop in its
+// Run method that calls eval and iterate, and stops when they return false.
+type serialRun struct {
+	operation chan<- Task
+	result    <-chan Task
+	task      Task
+
+	stopped  bool // Whether PostIteration has been received.
+	improved bool // Whether the best location changed since the last MajorIteration.
+
+	x []float64 // Best location found.
+	f float64   // Function value at x.
+}
+
+func newSerialRun(operation chan<- Task, result <-chan Task, task Task) *serialRun {
+	s := &serialRun{
+		operation: operation,
+		result:    result,
+		task:      task,
+		x:         make([]float64, len(task.X)),
+		f:         math.Inf(1),
+	}
+	copy(s.x, task.X)
+	return s
+}
+
+// initial returns the function value at the initial location, which is only
+// evaluated if it was not supplied by the caller of Minimize.
+func (s *serialRun) initial(x []float64) (f float64, ok bool) {
+	if s.task.Op&FuncEvaluation != 0 {
+		s.record(s.task.X, s.task.F)
+		copy(x, s.task.X)
+		return s.task.F, true
+	}
+	copy(x, s.task.X)
+	return s.eval(x)
+}
+
+// eval returns the function value at x. It returns ok as false if the
+// optimization must stop.
+func (s *serialRun) eval(x []float64) (f float64, ok bool) {
+	if s.stopped {
+		return math.NaN(), false
+	}
+	s.task.ID = 0
+	s.task.Op = FuncEvaluation
+	copy(s.task.X, x)
+	s.operation <- s.task
+	r := <-s.result
+	switch r.Op {
+	default:
+		panic("unknown operation")
+	case PostIteration:
+		s.stopped = true
+		return math.NaN(), false
+	case FuncEvaluation:
+	}
+	s.task = r
+	s.record(r.X, r.F)
+	return r.F, true
+}
+
+// record updates the best location with the function value f at x.
+func (s *serialRun) record(x []float64, f float64) {
+	if f < s.f {
+		s.f = f
+		copy(s.x, x)
+		s.improved = true
+	}
+}
+
+// iterate sends a MajorIteration with the best location. It returns false
+// if the optimization must stop.
+func (s *serialRun) iterate() bool {
+	if s.stopped {
+		return false
+	}
+	s.improved = false
+	s.task.ID = -1
+	s.task.Op = MajorIteration
+	s.task.F = s.f
+	copy(s.task.X, s.x)
+	s.task.Gradient = nil
+	s.task.Hessian = nil
+	s.operation <- s.task
+	r := <-s.result
+	switch r.Op {
+	default:
+		panic("unknown operation")
+	case PostIteration:
+		s.stopped = true
+		return false
+	case MajorIteration:
+	}
+	s.task = r
+	return true
+}
+
+// finish completes the channel operations of Run. If the optimization has
+// not been stopped, MethodDone is sent to signal that the method has
+// terminated. The best location is sent in a final MajorIteration if it
+// has not been sent before.
+func (s *serialRun) finish() {
+	if !s.stopped && (!s.improved || s.iterate()) {
+		s.task.Op = MethodDone
+		s.operation <- s.task
+		r := <-s.result
+		if r.Op != PostIteration {
+			panic("optimize: task should have returned post iteration")
+		}
+		s.stopped = true
+	}
+	for r := range s.result {
+		switch r.Op {
+		default:
+			panic("unknown operation")
+		case MajorIteration:
+		case FuncEvaluation:
+			s.record(r.X, r.F)
+		}
+	}
+	if s.improved {
+		s.task.ID = -1
+		s.task.Op = MajorIteration
+		s.task.F = s.f
+		copy(s.task.X, s.x)
+		s.task.Gradient = nil
+		s.task.Hessian = nil
+		s.operation <- s.task
+	}
+	close(s.operation)
+}
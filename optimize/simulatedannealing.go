@@ -0,0 +1,210 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"golang.org/x/exp/rand"
+)
+
+var (
+	_ Method           = (*SimulatedAnnealing)(nil)
+	_ BoundedMethod    = (*SimulatedAnnealing)(nil)
+	_ Statuser         = (*SimulatedAnnealing)(nil)
+	_ defaultConverger = (*SimulatedAnnealing)(nil)
+)
+
+// SimulatedAnnealing implements simulated annealing, a global optimization
+// method described in
+//  Kirkpatrick, S., Gelatt, C. D., Vecchi, M. P.: Optimization by simulated
+//  annealing. Science 220, 671–680 (1983)
+// In each iteration, locations are proposed by adding normally distributed
+// steps to the current location. The best proposal is accepted if its
+// function value is not larger than at the current location, and otherwise
+// with probability
+//  exp(-(f_proposal - f_current) / T)
+// where T is the temperature. The temperature is decreased geometrically
+// after each iteration, and the standard deviation of the steps decreases
+// proportionally to the square root of the temperature.
+//
+// One proposal is made for every concurrent task, so the proposals of an
+// iteration are evaluated concurrently if Settings.Concurrent is larger than
+// one. The optimization is reproducible for a given Src and
+// Settings.Concurrent, unless it is stopped by an evaluation limit.
+// Proposals outside the bounds are projected onto them.
+//
+// SimulatedAnnealing terminates with MethodConverge status when the
+// temperature has decreased below StopTemp times the initial temperature.
+// The best function value often does not improve for many iterations while
+// the temperature is high, so Minimize does not use the default Converger
+// with SimulatedAnnealing.
+type SimulatedAnnealing struct {
+	// InitTemp is the initial temperature. If InitTemp is 0, it is set to
+	// the mean absolute difference between the function values of the
+	// first proposals and of the initial location. InitTemp must not be
+	// negative, or SimulatedAnnealing will panic.
+	InitTemp float64
+	// Cooling is the factor by which the temperature is multiplied after
+	// each iteration. If Cooling is 0, a default value of 0.99 is used.
+	// Cooling must be less than 1 and not negative, or SimulatedAnnealing
+	// will panic.
+	Cooling float64
+	// StepSize is the initial standard deviation of the steps. If StepSize
+	// is 0, a default value of 1 is used. StepSize must not be negative, or
+	// SimulatedAnnealing will panic.
+	StepSize float64
+	// StopTemp sets the threshold for stopping the optimization when the
+	// temperature relative to the initial temperature becomes less than
+	// StopTemp. If StopTemp is 0, a default value of 1e-6 is used, and if
+	// it is NaN the stopping criterion is not used. StopTemp must be less
+	// than 1 and not negative, or SimulatedAnnealing will panic.
+	StopTemp float64
+	// Src is the source of random numbers. If Src is nil, the generator in
+	// golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	lower, upper []float64
+
+	proposals int
+	cooling   float64
+	step      float64
+	stopTemp  float64
+	rnd       *rand.Rand
+	status    Status
+
+	initialized bool
+	temp, temp0 float64
+	x           []float64 // Current location.
+	f           float64   // Function value at the current location.
+	xs          [][]float64
+}
+
+func (sa *SimulatedAnnealing) Status() (Status, error) {
+	return sa.status, nil
+}
+
+// defaultConverger returns NeverTerminate since SimulatedAnnealing stops
+// when the temperature is low enough.
+func (*SimulatedAnnealing) defaultConverger() Converger {
+	return NeverTerminate{}
+}
+
+func (*SimulatedAnnealing) Uses(has Available) (uses Available, err error) {
+	return has.function()
+}
+
+func (sa *SimulatedAnnealing) InitBounds(lower, upper []float64) {
+	sa.lower = lower
+	sa.upper = upper
+}
+
+func (sa *SimulatedAnnealing) Init(dim, tasks int) int {
+	if dim <= 0 {
+		panic(nonpositiveDimension)
+	}
+	if tasks < 0 {
+		panic(negativeTasks)
+	}
+	if sa.InitTemp < 0 {
+		panic("simulated annealing: negative initial temperature")
+	}
+	sa.cooling = sa.Cooling
+	switch {
+	case sa.cooling == 0:
+		sa.cooling = 0.99
+	case sa.cooling < 0 || sa.cooling >= 1:
+		panic("simulated annealing: cooling out of range")
+	}
+	sa.step = sa.StepSize
+	switch {
+	case sa.step == 0:
+		sa.step = 1
+	case sa.step < 0:
+		panic("simulated annealing: negative step size")
+	}
+	sa.stopTemp = sa.StopTemp
+	switch {
+	case sa.stopTemp == 0:
+		sa.stopTemp = 1e-6
+	case sa.stopTemp < 0 || sa.stopTemp >= 1:
+		panic("simulated annealing: stop temperature out of range")
+	}
+	sa.status = NotTerminated
+	sa.proposals = tasks
+	sa.rnd = newRand(sa.Src)
+	sa.initialized = false
+	sa.temp = sa.InitTemp
+	sa.temp0 = sa.InitTemp
+	sa.x = resize(sa.x, dim)
+	return tasks
+}
+
+func (sa *SimulatedAnnealing) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	copy(sa.x, tasks[0].X)
+	projectBounds(sa.x, sa.lower, sa.upper)
+	runGenerations(sa, operation, result, tasks)
+}
+
+func (sa *SimulatedAnnealing) generation() [][]float64 {
+	if !sa.initialized {
+		sa.xs = resizeRows(sa.xs, 1, len(sa.x))
+		copy(sa.xs[0], sa.x)
+		return sa.xs
+	}
+	step := sa.step
+	if sa.temp0 != 0 {
+		step *= math.Sqrt(sa.temp / sa.temp0)
+	}
+	sa.xs = resizeRows(sa.xs, sa.proposals, len(sa.x))
+	for _, x := range sa.xs {
+		for j := range x {
+			x[j] = sa.x[j] + step*sa.rnd.NormFloat64()
+		}
+		projectBounds(x, sa.lower, sa.upper)
+	}
+	return sa.xs
+}
+
+func (sa *SimulatedAnnealing) update(f []float64) bool {
+	if !sa.initialized {
+		sa.f = f[0]
+		sa.initialized = true
+		return false
+	}
+	if sa.temp0 == 0 {
+		// Set the initial temperature from the typical change in the
+		// function value.
+		var sum float64
+		var n int
+		for _, v := range f {
+			if d := math.Abs(v - sa.f); !math.IsInf(d, 0) && !math.IsNaN(d) {
+				sum += d
+				n++
+			}
+		}
+		sa.temp = 1
+		if n > 0 && sum > 0 {
+			sa.temp = sum / float64(n)
+		}
+		sa.temp0 = sa.temp
+	}
+	best := 0
+	for i, v := range f {
+		if v < f[best] {
+			best = i
+		}
+	}
+	if f[best] <= sa.f || sa.rnd.Float64() < math.Exp(-(f[best]-sa.f)/sa.temp) {
+		sa.f = f[best]
+		copy(sa.x, sa.xs[best])
+	}
+	sa.temp *= sa.cooling
+	if sa.temp < sa.stopTemp*sa.temp0 {
+		sa.status = MethodConverge
+		return true
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+// SQP is a sequential quadratic programming method for constrained
+// minimization.
+//
+// At each iteration SQP computes the step d and the new multiplier
+// estimates from the quadratic subproblem
+//  minimize ½ dᵀ B d + ∇fᵀ d
+//  s.t.     c_E + J_E d = 0
+//           c_I + J_I d ≥ 0,
+// where B is a damped BFGS approximation of the Hessian of the Lagrangian
+// and J_E and J_I are the constraint Jacobians. The step length is chosen
+// by a backtracking linesearch on the ℓ₁ merit function
+//  φ(x) = f(x) + μ (||c_E(x)||₁ + ||max(0, -c_I(x))||₁).
+// If the linearized constraints are inconsistent, SQP terminates with
+// Failure status.
+//
+// References:
+//  - Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer
+//    (2006), Chapter 18
+//  - Powell, M.J.D.: A fast algorithm for nonlinearly constrained
+//    optimization calculations. Numerical Analysis, Lecture Notes in
+//    Mathematics 630, 144-157 (1978)
+type SQP struct{}
+
+const (
+	sqpDecrease   = 1e-4
+	sqpMinStep    = 1e-12
+	sqpPenaltyMul = 1.5
+)
+
+func (SQP) solveConstrained(s *conState) {
+	n := s.n
+	if s.gradient() {
+		return
+	}
+	b := mat.NewSymDense(n, nil)
+	for i := 0; i < n; i++ {
+		b.SetSym(i, i, 1)
+	}
+	var (
+		d      = make([]float64, n)
+		xt     = make([]float64, n)
+		cet    = make([]float64, s.me)
+		cit    = make([]float64, s.mi)
+		negCE  = make([]float64, s.me)
+		negCI  = make([]float64, s.mi)
+		gl     = make([]float64, n)
+		yk     = make([]float64, n)
+		mu     float64
+		reset  bool
+		lamE   []float64
+		lamI   []float64
+		qpErr  error
+		stepSz float64
+	)
+	for {
+		floats.ScaleTo(negCE, -1, s.ce)
+		floats.ScaleTo(negCI, -1, s.ci)
+		lamE, lamI, qpErr = solveQP(d, b, s.g, s.je, negCE, s.ji, negCI)
+		if qpErr != nil {
+			if reset {
+				s.fail(qpErr)
+				return
+			}
+			// Retry with the identity in case the Hessian approximation
+			// has become badly conditioned.
+			reset = true
+			b.Zero()
+			for i := 0; i < n; i++ {
+				b.SetSym(i, i, 1)
+			}
+			continue
+		}
+		reset = false
+		if s.converged(lamE, lamI) {
+			return
+		}
+		if s.iterate() {
+			return
+		}
+
+		// Update the penalty parameter so that d is a descent direction
+		// for the merit function.
+		lamMax := math.Max(maxAbs(lamE), maxAbs(lamI))
+		if mu < lamMax {
+			mu = sqpPenaltyMul * lamMax
+		}
+		viol := violationL1(s.ce, s.ci)
+		phi := s.f + mu*viol
+		deriv := floats.Dot(s.g, d) - mu*viol
+
+		dNorm := floats.Norm(d, math.Inf(1))
+		stepSz = 1
+		for {
+			floats.AddScaledTo(xt, s.x, stepSz, d)
+			ft, done := s.eval(xt, cet, cit)
+			if done {
+				return
+			}
+			if ft+mu*violationL1(cet, cit) <= phi+sqpDecrease*stepSz*math.Min(deriv, 0) {
+				// Form the change in the gradient of the Lagrangian with
+				// the new multipliers before moving.
+				s.lagrangianGrad(gl, lamE, lamI)
+				s.accept(xt, ft, cet, cit)
+				break
+			}
+			stepSz /= 2
+			if stepSz*dNorm <= sqpMinStep*(1+floats.Norm(s.x, math.Inf(1))) {
+				if s.stepConverged(stepSz * dNorm) {
+					return
+				}
+				s.fail(ErrLinesearcherFailure)
+				return
+			}
+		}
+		if s.gradient() {
+			return
+		}
+		if s.stepConverged(stepSz * dNorm) {
+			return
+		}
+		s.lagrangianGrad(yk, lamE, lamI)
+		floats.Sub(yk, gl)
+		floats.Scale(stepSz, d)
+		dampedBFGS(b, d, yk)
+	}
+}
+
+// maxAbs returns the largest absolute value in s, or zero if s is empty.
+func maxAbs(s []float64) float64 {
+	var m float64
+	for _, v := range s {
+		m = math.Max(m, math.Abs(v))
+	}
+	return m
+}
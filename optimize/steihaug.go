@@ -0,0 +1,129 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var _ TrustRegionSolver = (*SteihaugCG)(nil)
+
+// SteihaugCG solves the trust-region subproblem approximately by the
+// truncated conjugate gradient method described in
+//  Steihaug, T.: The conjugate gradient method and trust regions in large
+//  scale optimization. SIAM Journal on Numerical Analysis 20, 626–637 (1983)
+// The conjugate gradient iterations for the Newton equations H p = -g start
+// at p = 0 and stop when the residual is small relative to the gradient.
+// If an iterate leaves the trust region or a direction of negative
+// curvature is found, the step is extended along the current direction to
+// the boundary of the trust region.
+//
+// SteihaugCG only uses products of the Hessian with vectors and does not
+// factorize it, so it is suitable for large problems. The steps are less
+// accurate than those of MoreSorensen when the Hessian is indefinite.
+type SteihaugCG struct {
+	// MaxIterations is the maximum number of conjugate gradient
+	// iterations. If MaxIterations is 0, it is defaulted to ten times the
+	// dimension of the problem.
+	MaxIterations int
+
+	cg truncatedCG
+}
+
+func (s *SteihaugCG) Solve(step, grad []float64, hess *mat.SymDense, radius float64) {
+	hessVec := func(dst, v []float64) {
+		d := mat.NewVecDense(len(dst), dst)
+		d.MulVec(hess, mat.NewVecDense(len(v), v))
+	}
+	s.cg.solve(step, grad, hessVec, radius, s.MaxIterations)
+}
+
+// truncatedCG is the truncated conjugate gradient method for the Newton
+// equations, shared by SteihaugCG and NewtonCG.
+type truncatedCG struct {
+	r, d, hd []float64
+}
+
+// solve stores in p an approximate solution of H p = -g computed by the
+// conjugate gradient method, where hessVec stores the product of H with v
+// in dst. The iterations stop when the norm of the residual is less than
+//  min(0.5, sqrt(‖g‖)) ‖g‖
+// which gives superlinear convergence of Newton methods, or after maxIter
+// iterations. If an iterate leaves the ball of the given radius or a
+// direction of negative curvature is found, p is extended to the boundary
+// of the ball, or if radius is infinite, the last iterate is returned or
+// -g if there is none.
+func (cg *truncatedCG) solve(p, g []float64, hessVec func(dst, v []float64), radius float64, maxIter int) {
+	dim := len(g)
+	if maxIter == 0 {
+		maxIter = 10 * dim
+	}
+	cg.r = resize(cg.r, dim)
+	cg.d = resize(cg.d, dim)
+	cg.hd = resize(cg.hd, dim)
+	r, d, hd := cg.r, cg.d, cg.hd
+
+	for i := range p {
+		p[i] = 0
+	}
+	copy(r, g)
+	floats.ScaleTo(d, -1, g)
+	rr := floats.Dot(r, r)
+	gNorm := math.Sqrt(rr)
+	if gNorm == 0 {
+		return
+	}
+	tol := math.Min(0.5, math.Sqrt(gNorm)) * gNorm
+	for i := 0; i < maxIter; i++ {
+		hessVec(hd, d)
+		curv := floats.Dot(d, hd)
+		if curv <= 0 {
+			// d is a direction of negative curvature.
+			switch {
+			case !math.IsInf(radius, 1):
+				floats.AddScaled(p, boundaryStep(p, d, radius), d)
+			case i == 0:
+				copy(p, d)
+			}
+			return
+		}
+		alpha := rr / curv
+		pd := floats.Dot(p, d)
+		if floats.Dot(p, p)+alpha*(2*pd+alpha*floats.Dot(d, d)) >= radius*radius {
+			// The next iterate would leave the trust region, so stop at
+			// the boundary.
+			floats.AddScaled(p, boundaryStep(p, d, radius), d)
+			return
+		}
+		floats.AddScaled(p, alpha, d)
+		floats.AddScaled(r, alpha, hd)
+		rrNew := floats.Dot(r, r)
+		if math.Sqrt(rrNew) < tol {
+			return
+		}
+		beta := rrNew / rr
+		rr = rrNew
+		for j, v := range r {
+			d[j] = beta*d[j] - v
+		}
+	}
+}
+
+// boundaryStep returns the positive τ for which ‖p + τd‖ = radius, where
+// ‖p‖ ≤ radius.
+func boundaryStep(p, d []float64, radius float64) float64 {
+	a := floats.Dot(d, d)
+	b := floats.Dot(p, d)
+	c := floats.Dot(p, p) - radius*radius
+	disc := math.Sqrt(math.Max(b*b-a*c, 0))
+	// Avoid cancellation in the computation of the root.
+	if b > 0 {
+		return -c / (b + disc)
+	}
+	return (disc - b) / a
+}
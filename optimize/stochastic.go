@@ -0,0 +1,265 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"time"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+const (
+	defaultStochasticEpochs = 100
+	defaultLearningRate     = 0.001
+)
+
+// StochasticProblem is the problem of minimizing a function
+//  f(x) = 1/B Σ_b f_b(x)
+// that is the mean of the functions f_b on B minibatches, typically the
+// loss of a model on subsets of a data set, from noisy estimates of the
+// gradient on single minibatches.
+type StochasticProblem struct {
+	// Batches is the number B of minibatches in an epoch.
+	Batches int
+
+	// Grad evaluates the gradient of f_b at x on the minibatch with index
+	// batch in [0, Batches) into grad and returns the value of f_b at x.
+	// Objectives that draw random samples themselves may ignore batch, in
+	// which case Batches is the number of samples in an epoch. Grad must
+	// not modify x.
+	Grad func(grad, x []float64, batch int) float64
+
+	// Func, if not nil, evaluates f, or another measure of the quality of
+	// x such as the loss on a validation set, at x. It is called at the
+	// end of every epoch. If Func is nil, the mean of the values of f_b
+	// returned by Grad during an epoch is used instead.
+	Func func(x []float64) float64
+}
+
+// StochasticSettings holds the settings for stochastic minimization.
+type StochasticSettings struct {
+	// Epochs is the number of passes over all minibatches. The
+	// minimization ends with Success status after Epochs epochs. If Epochs
+	// is zero, a default of 100 is used.
+	Epochs int
+
+	// LearningRate is the schedule of the learning rate. If LearningRate
+	// is nil, a constant rate of 0.001 is used.
+	LearningRate LearningRateSchedule
+
+	// Shuffle specifies whether the minibatches are visited in a random
+	// order in every epoch. Otherwise they are visited in the order of
+	// their indices.
+	Shuffle bool
+	// Src is the source of random numbers for shuffling. If Src is nil,
+	// the generator in golang.org/x/exp/rand is used.
+	Src rand.Source
+
+	// Patience enables early stopping if it is positive. The minimization
+	// ends with FunctionConvergence status when the function value at the
+	// end of an epoch has not decreased by more than MinDelta below the
+	// lowest value for Patience epochs, and the location with the lowest
+	// value is returned.
+	Patience int
+	MinDelta float64
+
+	// Runtime is the maximum runtime allowed. RuntimeLimit status is
+	// returned if the duration of the run is longer than this value.
+	// If it equals zero, this setting has no effect.
+	Runtime time.Duration
+
+	// GradEvaluations is the maximum allowed number of calls to Grad.
+	// GradientEvaluationLimit status is returned if the total number of
+	// calls equals or exceeds this number.
+	// If it equals zero, this setting has no effect.
+	GradEvaluations int
+
+	// Recorder is called at the end of every epoch with MajorIteration
+	// and at the end of the minimization with PostIteration. The
+	// Location holds the function value at the end of the epoch and the
+	// mean of the minibatch gradients during the epoch.
+	Recorder Recorder
+}
+
+// StochasticResult holds the result of a stochastic minimization.
+type StochasticResult struct {
+	// X is the location at the end of the last completed epoch, or of the
+	// epoch with the lowest function value if Patience is positive, and F
+	// is the function value there. If no epoch has been completed, X is
+	// the initial location and F is NaN.
+	X []float64
+	F float64
+
+	// Stats holds the statistics of the minimization. MajorIterations
+	// counts the completed epochs, FuncEvaluations the calls to Func and
+	// GradEvaluations the calls to Grad.
+	Stats
+	Status Status
+}
+
+// StochasticMethod is a method for stochastic minimization that updates
+// the location from noisy gradients. StochasticMethod is implemented by
+// SGD, Adam, AdamW, RMSProp and Adagrad.
+type StochasticMethod interface {
+	// Init initializes the method for a problem of dimension dim.
+	Init(dim int)
+	// Update updates the location x in place using the gradient grad of
+	// a minibatch at x and the learning rate. Update must not modify grad.
+	Update(x, grad []float64, rate float64)
+}
+
+// MinimizeStochastic minimizes the stochastic problem p, starting from x0,
+// using the given method. If method is nil, Adam is used. If settings is
+// nil, the defaults described for StochasticSettings are used.
+//
+// MinimizeStochastic panics if p.Grad is nil, p.Batches is not positive or
+// the settings are negative. It returns ErrDivergence with Failure status
+// if a function value is not finite, which usually indicates a learning
+// rate that is too large. Otherwise it returns an error if the Recorder
+// fails or the minimization ended early as indicated by Status.Err.
+func MinimizeStochastic(p StochasticProblem, x0 []float64, method StochasticMethod, settings *StochasticSettings) (*StochasticResult, error) {
+	if p.Grad == nil || p.Batches <= 0 {
+		panic(badProblem)
+	}
+	dim := len(x0)
+	if dim == 0 {
+		return nil, ErrZeroDimensional
+	}
+	if method == nil {
+		method = &Adam{}
+	}
+	var set StochasticSettings
+	if settings != nil {
+		set = *settings
+	}
+	if set.Epochs < 0 || set.Patience < 0 || set.MinDelta < 0 {
+		panic("optimize: negative stochastic setting")
+	}
+	if set.Epochs == 0 {
+		set.Epochs = defaultStochasticEpochs
+	}
+	schedule := set.LearningRate
+	if schedule == nil {
+		schedule = ConstantRate(defaultLearningRate)
+	}
+	var rnd *rand.Rand
+	if set.Shuffle {
+		rnd = newRand(set.Src)
+	}
+	if set.Recorder != nil {
+		if err := set.Recorder.Init(); err != nil {
+			return nil, err
+		}
+	}
+
+	start := time.Now()
+	x := make([]float64, dim)
+	copy(x, x0)
+	grad := make([]float64, dim)
+	order := make([]int, p.Batches)
+	for i := range order {
+		order[i] = i
+	}
+	// loc is the location at the end of the last completed epoch.
+	loc := &Location{
+		X:        make([]float64, dim),
+		F:        math.NaN(),
+		Gradient: make([]float64, dim),
+	}
+	copy(loc.X, x0)
+	result := &StochasticResult{
+		X: make([]float64, dim),
+		F: math.NaN(),
+	}
+	copy(result.X, x0)
+	stats := &result.Stats
+
+	var err error
+	var step, wait int
+	method.Init(dim)
+	for epoch := 0; result.Status == NotTerminated; epoch++ {
+		if epoch == set.Epochs {
+			result.Status = Success
+			break
+		}
+		if rnd != nil {
+			rnd.Shuffle(len(order), func(i, j int) {
+				order[i], order[j] = order[j], order[i]
+			})
+		}
+		var sum float64
+		for i := range loc.Gradient {
+			loc.Gradient[i] = 0
+		}
+		for _, b := range order {
+			if set.GradEvaluations > 0 && stats.GradEvaluations >= set.GradEvaluations {
+				result.Status = GradientEvaluationLimit
+				break
+			}
+			if set.Runtime > 0 && time.Since(start) >= set.Runtime {
+				result.Status = RuntimeLimit
+				break
+			}
+			f := p.Grad(grad, x, b)
+			stats.GradEvaluations++
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				result.Status = Failure
+				err = ErrDivergence
+				break
+			}
+			sum += f
+			floats.Add(loc.Gradient, grad)
+			method.Update(x, grad, schedule.Rate(step, epoch))
+			step++
+		}
+		if result.Status != NotTerminated {
+			break
+		}
+
+		f := sum / float64(p.Batches)
+		if p.Func != nil {
+			f = p.Func(x)
+			stats.FuncEvaluations++
+			if math.IsInf(f, 0) || math.IsNaN(f) {
+				result.Status = Failure
+				err = ErrDivergence
+				break
+			}
+		}
+		stats.MajorIterations++
+		stats.Runtime = time.Since(start)
+		copy(loc.X, x)
+		loc.F = f
+		floats.Scale(1/float64(p.Batches), loc.Gradient)
+		if set.Recorder != nil {
+			if err = set.Recorder.Record(loc, MajorIteration, stats); err != nil {
+				result.Status = Failure
+				break
+			}
+		}
+
+		if set.Patience == 0 || f < result.F-set.MinDelta || math.IsNaN(result.F) {
+			copy(result.X, x)
+			result.F = f
+			wait = 0
+		} else {
+			wait++
+			if wait >= set.Patience {
+				result.Status = FunctionConvergence
+			}
+		}
+	}
+	stats.Runtime = time.Since(start)
+	if set.Recorder != nil && err == nil {
+		err = set.Recorder.Record(loc, PostIteration, stats)
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, result.Status.Err()
+}
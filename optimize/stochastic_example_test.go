@@ -0,0 +1,72 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize_test
+
+import (
+	"fmt"
+	"log"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/optimize"
+)
+
+func ExampleMinimizeStochastic() {
+	// Fit the line y = a t + b to 1000 data points with minibatches of 50
+	// points.
+	const size = 50
+	ts := make([]float64, 1000)
+	ys := make([]float64, len(ts))
+	for i := range ts {
+		ts[i] = float64(i) / float64(len(ts))
+		ys[i] = 2*ts[i] + 1
+	}
+	// loss returns the mean squared error of the fit on the data with
+	// indices in [lo, hi) and stores its gradient in grad if it is not
+	// nil.
+	loss := func(grad, x []float64, lo, hi int) float64 {
+		if grad != nil {
+			grad[0] = 0
+			grad[1] = 0
+		}
+		var f float64
+		for i := lo; i < hi; i++ {
+			r := x[0]*ts[i] + x[1] - ys[i]
+			f += r * r
+			if grad != nil {
+				grad[0] += 2 * r * ts[i] / float64(hi-lo)
+				grad[1] += 2 * r / float64(hi-lo)
+			}
+		}
+		return f / float64(hi-lo)
+	}
+	p := optimize.StochasticProblem{
+		Batches: len(ts) / size,
+		Grad: func(grad, x []float64, batch int) float64 {
+			return loss(grad, x, batch*size, (batch+1)*size)
+		},
+		Func: func(x []float64) float64 {
+			return loss(nil, x, 0, len(ts))
+		},
+	}
+	settings := &optimize.StochasticSettings{
+		Epochs:       200,
+		LearningRate: optimize.StepDecay{Initial: 0.1, Factor: 0.5, Epochs: 40},
+		Shuffle:      true,
+		Src:          rand.NewSource(1),
+		// Stop if the loss has not decreased for 10 epochs.
+		Patience: 10,
+	}
+	result, err := optimize.MinimizeStochastic(p, []float64{0, 0}, &optimize.Adam{}, settings)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("a = %.4f, b = %.4f\n", result.X[0], result.X[1])
+	fmt.Printf("loss < 1e-8: %t\n", result.F < 1e-8)
+
+	// Output:
+	// a = 2.0000, b = 1.0000
+	// loss < 1e-8: true
+}
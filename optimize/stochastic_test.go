@@ -0,0 +1,335 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+// regression is a linear least squares problem
+//  minimize 1/(2m) Σ_i (a_iᵀx - y_i)²
+// on m data points that are split into minibatches of equal size.
+type regression struct {
+	a    [][]float64
+	y    []float64
+	size int
+	want []float64
+}
+
+// newRegression returns a regression problem in dim variables with the given
+// number of minibatches of the given size, whose data are generated without
+// noise from a known solution.
+func newRegression(dim, batches, size int, src rand.Source) *regression {
+	rnd := rand.New(src)
+	r := &regression{
+		a:    make([][]float64, batches*size),
+		y:    make([]float64, batches*size),
+		size: size,
+		want: make([]float64, dim),
+	}
+	for j := range r.want {
+		r.want[j] = rnd.NormFloat64()
+	}
+	for i := range r.a {
+		r.a[i] = make([]float64, dim)
+		for j := range r.a[i] {
+			r.a[i][j] = rnd.NormFloat64()
+		}
+		r.y[i] = floats.Dot(r.a[i], r.want)
+	}
+	return r
+}
+
+func (r *regression) problem() StochasticProblem {
+	return StochasticProblem{
+		Batches: len(r.y) / r.size,
+		Grad:    r.grad,
+		Func:    r.fun,
+	}
+}
+
+func (r *regression) grad(grad, x []float64, batch int) float64 {
+	for j := range grad {
+		grad[j] = 0
+	}
+	var f float64
+	for i := batch * r.size; i < (batch+1)*r.size; i++ {
+		res := floats.Dot(r.a[i], x) - r.y[i]
+		f += res * res / 2
+		floats.AddScaled(grad, res, r.a[i])
+	}
+	floats.Scale(1/float64(r.size), grad)
+	return f / float64(r.size)
+}
+
+func (r *regression) fun(x []float64) float64 {
+	var f float64
+	for i := range r.y {
+		res := floats.Dot(r.a[i], x) - r.y[i]
+		f += res * res / 2
+	}
+	return f / float64(len(r.y))
+}
+
+func TestMinimizeStochastic(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		method StochasticMethod
+		rate   LearningRateSchedule
+		tol    float64
+	}{
+		{
+			name:   "SGD",
+			method: &SGD{},
+			rate:   ConstantRate(0.05),
+			tol:    1e-6,
+		},
+		{
+			name:   "SGD momentum",
+			method: &SGD{Momentum: 0.9},
+			rate:   ConstantRate(0.01),
+			tol:    1e-6,
+		},
+		{
+			name:   "SGD Nesterov",
+			method: &SGD{Momentum: 0.9, Nesterov: true},
+			rate:   ConstantRate(0.01),
+			tol:    1e-6,
+		},
+		{
+			name:   "SGD cosine",
+			method: &SGD{Momentum: 0.5},
+			rate:   CosineDecay{Initial: 0.05, Final: 0.001, Steps: 2000},
+			tol:    1e-6,
+		},
+		{
+			name:   "Adam",
+			method: &Adam{},
+			rate:   StepDecay{Initial: 0.05, Factor: 0.5, Epochs: 20},
+			tol:    1e-4,
+		},
+		{
+			name:   "AdamW",
+			method: &AdamW{WeightDecay: 1e-6},
+			rate:   Warmup{Steps: 50, Schedule: ExponentialDecay{Initial: 0.05, Decay: 0.5, Steps: 400}},
+			tol:    1e-4,
+		},
+		{
+			name:   "RMSProp",
+			method: &RMSProp{},
+			rate:   StepDecay{Initial: 0.01, Factor: 0.5, Epochs: 10},
+			tol:    1e-4,
+		},
+		{
+			name:   "RMSProp momentum",
+			method: &RMSProp{Momentum: 0.5},
+			rate:   StepDecay{Initial: 0.01, Factor: 0.5, Epochs: 10},
+			tol:    1e-4,
+		},
+		{
+			name:   "Adagrad",
+			method: &Adagrad{},
+			rate:   ConstantRate(0.5),
+			tol:    1e-4,
+		},
+	} {
+		r := newRegression(5, 20, 10, rand.NewSource(1))
+		settings := &StochasticSettings{
+			Epochs:       100,
+			LearningRate: test.rate,
+			Shuffle:      true,
+			Src:          rand.NewSource(1),
+		}
+		result, err := MinimizeStochastic(r.problem(), make([]float64, 5), test.method, settings)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if result.Status != Success {
+			t.Errorf("%s: unexpected status: got %v, want %v", test.name, result.Status, Success)
+		}
+		if result.MajorIterations != 100 || result.GradEvaluations != 2000 || result.FuncEvaluations != 100 {
+			t.Errorf("%s: unexpected stats: %+v", test.name, result.Stats)
+		}
+		if !floats.EqualApprox(result.X, r.want, test.tol) {
+			t.Errorf("%s: unexpected location: got %v, want %v", test.name, result.X, r.want)
+		}
+		if f := r.fun(result.X); f != result.F {
+			t.Errorf("%s: function value mismatch: got %v, want %v", test.name, result.F, f)
+		}
+	}
+}
+
+func TestMinimizeStochasticDefaults(t *testing.T) {
+	t.Parallel()
+	// Without Func, the function values are the means of the minibatch
+	// values during an epoch.
+	r := newRegression(3, 4, 5, rand.NewSource(1))
+	p := r.problem()
+	p.Func = nil
+	var rec stochasticRecorder
+	result, err := MinimizeStochastic(p, make([]float64, 3), nil, &StochasticSettings{Epochs: 2, Recorder: &rec})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != Success || result.MajorIterations != 2 || result.FuncEvaluations != 0 || result.GradEvaluations != 8 {
+		t.Errorf("unexpected result: %v %+v", result.Status, result.Stats)
+	}
+
+	// Repeat the minimization with the default method and the batches in
+	// order to compute the expected values.
+	x := make([]float64, 3)
+	grad := make([]float64, 3)
+	adam := &Adam{}
+	adam.Init(3)
+	var want []float64
+	for epoch := 0; epoch < 2; epoch++ {
+		var sum float64
+		for b := 0; b < 4; b++ {
+			sum += r.grad(grad, x, b)
+			adam.Update(x, grad, defaultLearningRate)
+		}
+		want = append(want, sum/4)
+	}
+	if !floats.Equal(rec.f, want) {
+		t.Errorf("unexpected recorded function values: got %v, want %v", rec.f, want)
+	}
+	if !floats.Equal(result.X, x) || result.F != want[1] {
+		t.Errorf("unexpected result location: got %v %v, want %v %v", result.X, result.F, x, want[1])
+	}
+	if rec.init != 1 || len(rec.ops) != 3 || rec.ops[0] != MajorIteration || rec.ops[2] != PostIteration {
+		t.Errorf("unexpected recorded operations: %v", rec.ops)
+	}
+}
+
+func TestMinimizeStochasticEarlyStopping(t *testing.T) {
+	t.Parallel()
+	r := newRegression(3, 4, 5, rand.NewSource(1))
+	var rec stochasticRecorder
+	settings := &StochasticSettings{
+		Epochs:       100,
+		LearningRate: ConstantRate(1e-4),
+		Patience:     3,
+		MinDelta:     1,
+		Recorder:     &rec,
+	}
+	x0 := []float64{10, 10, 10}
+	result, err := MinimizeStochastic(r.problem(), x0, &SGD{}, settings)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != FunctionConvergence {
+		t.Errorf("unexpected status: got %v, want %v", result.Status, FunctionConvergence)
+	}
+	// The function decreases by less than MinDelta in every epoch, so the
+	// location after the first epoch remains the best.
+	if result.MajorIterations != 4 {
+		t.Errorf("unexpected number of epochs: got %d, want 4", result.MajorIterations)
+	}
+	if !floats.Equal(result.X, rec.x[0]) || result.F != rec.f[0] {
+		t.Errorf("unexpected result: got %v %v, want %v %v", result.X, result.F, rec.x[0], rec.f[0])
+	}
+	if !(rec.f[3] < rec.f[0]) {
+		t.Errorf("function did not decrease: %v", rec.f)
+	}
+}
+
+func TestMinimizeStochasticLimits(t *testing.T) {
+	t.Parallel()
+	r := newRegression(3, 4, 5, rand.NewSource(1))
+	x0 := []float64{1, 2, 3}
+
+	result, err := MinimizeStochastic(r.problem(), x0, &SGD{}, &StochasticSettings{GradEvaluations: 10})
+	if err != GradientEvaluationLimit.Err() || result.Status != GradientEvaluationLimit {
+		t.Errorf("unexpected termination: %v %v", result.Status, err)
+	}
+	if result.GradEvaluations != 10 || result.MajorIterations != 2 {
+		t.Errorf("unexpected stats: %+v", result.Stats)
+	}
+
+	// A learning rate that is too large makes the minimization diverge.
+	result, err = MinimizeStochastic(r.problem(), x0, &SGD{}, &StochasticSettings{LearningRate: ConstantRate(100)})
+	if err != ErrDivergence || result.Status != Failure {
+		t.Errorf("unexpected termination: %v %v", result.Status, err)
+	}
+	for _, v := range result.X {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			t.Errorf("unexpected non-finite location: %v", result.X)
+			break
+		}
+	}
+
+	result, err = MinimizeStochastic(r.problem(), nil, nil, nil)
+	if err != ErrZeroDimensional || result != nil {
+		t.Errorf("unexpected result for zero dimension: %v %v", result, err)
+	}
+}
+
+func TestMinimizeStochasticPrinter(t *testing.T) {
+	t.Parallel()
+	r := newRegression(3, 4, 5, rand.NewSource(1))
+	var buf bytes.Buffer
+	printer := NewPrinter()
+	printer.Writer = &buf
+	printer.ValueInterval = 0
+	_, err := MinimizeStochastic(r.problem(), make([]float64, 3), nil, &StochasticSettings{Epochs: 5, Recorder: printer})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// A heading, one line per epoch and a final line.
+	if len(lines) != 7 || !strings.Contains(lines[0], "GradEvals") {
+		t.Errorf("unexpected printer output:\n%s", buf.String())
+	}
+}
+
+func TestMinimizeStochasticPanics(t *testing.T) {
+	t.Parallel()
+	r := newRegression(3, 4, 5, rand.NewSource(1))
+	for _, test := range []struct {
+		p        StochasticProblem
+		settings *StochasticSettings
+	}{
+		{p: StochasticProblem{Batches: 1}},
+		{p: StochasticProblem{Grad: r.grad}},
+		{p: r.problem(), settings: &StochasticSettings{Epochs: -1}},
+		{p: r.problem(), settings: &StochasticSettings{Patience: -1}},
+		{p: r.problem(), settings: &StochasticSettings{MinDelta: -1}},
+	} {
+		if !panics(func() { MinimizeStochastic(test.p, make([]float64, 3), nil, test.settings) }) {
+			t.Errorf("expected panic for %+v", test.settings)
+		}
+	}
+}
+
+// stochasticRecorder records the calls of MinimizeStochastic to a Recorder.
+type stochasticRecorder struct {
+	init int
+	ops  []Operation
+	x    [][]float64
+	f    []float64
+}
+
+func (r *stochasticRecorder) Init() error {
+	r.init++
+	return nil
+}
+
+func (r *stochasticRecorder) Record(loc *Location, op Operation, stats *Stats) error {
+	r.ops = append(r.ops, op)
+	if op == MajorIteration {
+		r.x = append(r.x, append([]float64(nil), loc.X...))
+		r.f = append(r.f, loc.F)
+	}
+	return nil
+}
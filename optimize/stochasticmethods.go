@@ -0,0 +1,284 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import "math"
+
+var (
+	_ StochasticMethod = (*SGD)(nil)
+	_ StochasticMethod = (*Adam)(nil)
+	_ StochasticMethod = (*AdamW)(nil)
+	_ StochasticMethod = (*RMSProp)(nil)
+	_ StochasticMethod = (*Adagrad)(nil)
+)
+
+// SGD is stochastic gradient descent with optional momentum. With momentum
+// μ and learning rate η, the update for the gradient g is
+//  v ← μv + g
+//  x ← x - η v
+// or, with Nesterov momentum as described in
+//  Sutskever, I., Martens, J., Dahl, G., Hinton, G.: On the importance of
+//  initialization and momentum in deep learning. ICML (2013)
+// the update is
+//  v ← μv + g
+//  x ← x - η (g + μv).
+type SGD struct {
+	// Momentum is the momentum factor μ. Momentum must be in [0, 1), or
+	// SGD will panic. The zero value gives plain stochastic gradient
+	// descent.
+	Momentum float64
+	// Nesterov specifies whether Nesterov momentum is used.
+	Nesterov bool
+	// WeightDecay is the factor of an L2 penalty ½ WeightDecay ‖x‖² added
+	// to the function. WeightDecay must not be negative, or SGD will panic.
+	WeightDecay float64
+
+	v []float64
+}
+
+func (s *SGD) Init(dim int) {
+	if s.Momentum < 0 || s.Momentum >= 1 {
+		panic("sgd: momentum out of range")
+	}
+	if s.WeightDecay < 0 {
+		panic("sgd: negative weight decay")
+	}
+	s.v = resize(s.v, dim)
+	for i := range s.v {
+		s.v[i] = 0
+	}
+}
+
+func (s *SGD) Update(x, grad []float64, rate float64) {
+	for i, g := range grad {
+		g += s.WeightDecay * x[i]
+		s.v[i] = s.Momentum*s.v[i] + g
+		d := s.v[i]
+		if s.Nesterov {
+			d = g + s.Momentum*s.v[i]
+		}
+		x[i] -= rate * d
+	}
+}
+
+// Adam is the adaptive moment estimation method described in
+//  Kingma, D. P., Ba, J.: Adam: A method for stochastic optimization.
+//  ICLR (2015)
+// Adam scales the steps of each variable by running estimates of the first
+// and second moments of its gradient,
+//  m ← β₁m + (1-β₁)g
+//  v ← β₂v + (1-β₂)g²
+//  x ← x - η m̂/(√v̂ + ε)
+// where m̂ and v̂ are the estimates corrected for their initialization bias
+// towards zero.
+type Adam struct {
+	// Beta1 and Beta2 are the decay rates β₁ and β₂ of the moment
+	// estimates. If they are 0, default values of 0.9 and 0.999 are used.
+	// They must be in [0, 1), or Adam will panic.
+	Beta1, Beta2 float64
+	// Epsilon is the term ε added to the denominator for numerical
+	// stability. If Epsilon is 0, a default value of 1e-8 is used. Epsilon
+	// must not be negative, or Adam will panic.
+	Epsilon float64
+	// WeightDecay is the factor of an L2 penalty ½ WeightDecay ‖x‖² added
+	// to the function. Since its gradient is scaled like the gradient of
+	// the function, AdamW is usually preferable for regularization.
+	// WeightDecay must not be negative, or Adam will panic.
+	WeightDecay float64
+
+	beta1, beta2 float64
+	eps          float64
+	pow1, pow2   float64 // β₁ and β₂ to the power of the number of updates.
+	m, v         []float64
+}
+
+func (a *Adam) Init(dim int) {
+	a.beta1 = a.Beta1
+	if a.beta1 == 0 {
+		a.beta1 = 0.9
+	}
+	a.beta2 = a.Beta2
+	if a.beta2 == 0 {
+		a.beta2 = 0.999
+	}
+	if a.beta1 < 0 || a.beta1 >= 1 || a.beta2 < 0 || a.beta2 >= 1 {
+		panic("adam: beta out of range")
+	}
+	a.eps = a.Epsilon
+	switch {
+	case a.eps == 0:
+		a.eps = 1e-8
+	case a.eps < 0:
+		panic("adam: negative epsilon")
+	}
+	if a.WeightDecay < 0 {
+		panic("adam: negative weight decay")
+	}
+	a.pow1 = 1
+	a.pow2 = 1
+	a.m = resize(a.m, dim)
+	a.v = resize(a.v, dim)
+	for i := range a.m {
+		a.m[i] = 0
+		a.v[i] = 0
+	}
+}
+
+func (a *Adam) Update(x, grad []float64, rate float64) {
+	a.pow1 *= a.beta1
+	a.pow2 *= a.beta2
+	// Fold the bias corrections into the step size.
+	step := rate * math.Sqrt(1-a.pow2) / (1 - a.pow1)
+	eps := a.eps * math.Sqrt(1-a.pow2)
+	for i, g := range grad {
+		g += a.WeightDecay * x[i]
+		a.m[i] = a.beta1*a.m[i] + (1-a.beta1)*g
+		a.v[i] = a.beta2*a.v[i] + (1-a.beta2)*g*g
+		x[i] -= step * a.m[i] / (math.Sqrt(a.v[i]) + eps)
+	}
+}
+
+// AdamW is Adam with decoupled weight decay described in
+//  Loshchilov, I., Hutter, F.: Decoupled weight decay regularization.
+//  ICLR (2019)
+// Before each Adam update, the location is shrunk by
+//  x ← (1 - η λ) x
+// for learning rate η and weight decay λ, instead of adding the gradient
+// of an L2 penalty to the gradient of the function.
+type AdamW struct {
+	// Beta1, Beta2 and Epsilon are as described for Adam.
+	Beta1, Beta2 float64
+	Epsilon      float64
+	// WeightDecay is the weight decay factor λ. If WeightDecay is 0, a
+	// default value of 0.01 is used. WeightDecay must not be negative, or
+	// AdamW will panic.
+	WeightDecay float64
+
+	decay float64
+	adam  Adam
+}
+
+func (a *AdamW) Init(dim int) {
+	a.decay = a.WeightDecay
+	switch {
+	case a.decay == 0:
+		a.decay = 0.01
+	case a.decay < 0:
+		panic("adamw: negative weight decay")
+	}
+	a.adam.Beta1 = a.Beta1
+	a.adam.Beta2 = a.Beta2
+	a.adam.Epsilon = a.Epsilon
+	a.adam.Init(dim)
+}
+
+func (a *AdamW) Update(x, grad []float64, rate float64) {
+	shrink := 1 - rate*a.decay
+	for i := range x {
+		x[i] *= shrink
+	}
+	a.adam.Update(x, grad, rate)
+}
+
+// RMSProp scales the steps of each variable by a running estimate of the
+// root mean square of its gradient, as proposed in lecture 6e of
+//  Tieleman, T., Hinton, G.: Neural Networks for Machine Learning.
+//  Coursera (2012)
+// The update with decay rate ρ and momentum μ is
+//  s ← ρs + (1-ρ)g²
+//  v ← μv + g/(√s + ε)
+//  x ← x - η v.
+type RMSProp struct {
+	// Decay is the decay rate ρ of the mean square estimate. If Decay is
+	// 0, a default value of 0.9 is used. Decay must be in [0, 1), or
+	// RMSProp will panic.
+	Decay float64
+	// Momentum is the momentum factor μ. Momentum must be in [0, 1), or
+	// RMSProp will panic.
+	Momentum float64
+	// Epsilon is the term ε added to the denominator for numerical
+	// stability. If Epsilon is 0, a default value of 1e-8 is used. Epsilon
+	// must not be negative, or RMSProp will panic.
+	Epsilon float64
+
+	decay float64
+	eps   float64
+	s, v  []float64
+}
+
+func (r *RMSProp) Init(dim int) {
+	r.decay = r.Decay
+	if r.decay == 0 {
+		r.decay = 0.9
+	}
+	if r.decay < 0 || r.decay >= 1 {
+		panic("rmsprop: decay out of range")
+	}
+	if r.Momentum < 0 || r.Momentum >= 1 {
+		panic("rmsprop: momentum out of range")
+	}
+	r.eps = r.Epsilon
+	switch {
+	case r.eps == 0:
+		r.eps = 1e-8
+	case r.eps < 0:
+		panic("rmsprop: negative epsilon")
+	}
+	r.s = resize(r.s, dim)
+	r.v = resize(r.v, dim)
+	for i := range r.s {
+		r.s[i] = 0
+		r.v[i] = 0
+	}
+}
+
+func (r *RMSProp) Update(x, grad []float64, rate float64) {
+	for i, g := range grad {
+		r.s[i] = r.decay*r.s[i] + (1-r.decay)*g*g
+		r.v[i] = r.Momentum*r.v[i] + g/(math.Sqrt(r.s[i])+r.eps)
+		x[i] -= rate * r.v[i]
+	}
+}
+
+// Adagrad scales the steps of each variable by the root of the sum of its
+// squared gradients, as described in
+//  Duchi, J., Hazan, E., Singer, Y.: Adaptive subgradient methods for
+//  online learning and stochastic optimization. Journal of Machine
+//  Learning Research 12, 2121–2159 (2011)
+// The update is
+//  s ← s + g²
+//  x ← x - η g/(√s + ε).
+// The steps of variables with large gradients decrease fastest, which
+// suits sparse gradients.
+type Adagrad struct {
+	// Epsilon is the term ε added to the denominator for numerical
+	// stability. If Epsilon is 0, a default value of 1e-10 is used.
+	// Epsilon must not be negative, or Adagrad will panic.
+	Epsilon float64
+
+	eps float64
+	s   []float64
+}
+
+func (a *Adagrad) Init(dim int) {
+	a.eps = a.Epsilon
+	switch {
+	case a.eps == 0:
+		a.eps = 1e-10
+	case a.eps < 0:
+		panic("adagrad: negative epsilon")
+	}
+	a.s = resize(a.s, dim)
+	for i := range a.s {
+		a.s[i] = 0
+	}
+}
+
+func (a *Adagrad) Update(x, grad []float64, rate float64) {
+	for i, g := range grad {
+		a.s[i] += g * g
+		x[i] -= rate * g / (math.Sqrt(a.s[i]) + a.eps)
+	}
+}
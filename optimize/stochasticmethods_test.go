@@ -0,0 +1,131 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+	"testing"
+
+	"gonum.org/v1/gonum/floats"
+)
+
+func TestStochasticMethodUpdates(t *testing.T) {
+	t.Parallel()
+	// Two updates from x = (1, -2) with the gradients g1 and g2 and the
+	// learning rate 0.1.
+	g1 := []float64{2, -4}
+	g2 := []float64{1, 1}
+	for _, test := range []struct {
+		name   string
+		method StochasticMethod
+		want   []float64
+	}{
+		{
+			// x - 0.1 g1 - 0.1 g2
+			name:   "SGD",
+			method: &SGD{},
+			want:   []float64{0.7, -1.7},
+		},
+		{
+			// v1 = g1, v2 = 0.5 g1 + g2
+			name:   "SGD momentum",
+			method: &SGD{Momentum: 0.5},
+			want:   []float64{0.6, -1.5},
+		},
+		{
+			// Steps g + 0.5 v with v1 = g1, v2 = 0.5 g1 + g2.
+			name:   "SGD Nesterov",
+			method: &SGD{Momentum: 0.5, Nesterov: true},
+			want:   []float64{0.5, -1.45},
+		},
+		{
+			// The gradients are g + 0.5 x.
+			name:   "SGD weight decay",
+			method: &SGD{WeightDecay: 0.5},
+			want:   []float64{0.6125, -1.525},
+		},
+		{
+			// The first bias-corrected step is -0.1 sign(g1). The second
+			// step is computed from m = 0.09 g1 + 0.1 g2 and
+			// v = 0.000999 g1² + 0.001 g2², corrected by 0.19 and 0.001999.
+			name:   "Adam",
+			method: &Adam{},
+			want: []float64{
+				0.9 - 0.1*(0.28/0.19)/math.Sqrt(0.004996/0.001999),
+				-1.9 - 0.1*(-0.26/0.19)/math.Sqrt(0.016984/0.001999),
+			},
+		},
+		{
+			// Adagrad: s1 = g1², s2 = g1² + g2².
+			name:   "Adagrad",
+			method: &Adagrad{},
+			want:   []float64{0.9 - 0.1/math.Sqrt(5), -1.9 - 0.1/math.Sqrt(17)},
+		},
+		{
+			// RMSProp: s1 = 0.1 g1², s2 = 0.09 g1² + 0.1 g2².
+			name:   "RMSProp",
+			method: &RMSProp{},
+			want: []float64{
+				1 - 0.1*2/math.Sqrt(0.4) - 0.1/math.Sqrt(0.46),
+				-2 + 0.1*4/math.Sqrt(1.6) - 0.1/math.Sqrt(1.54),
+			},
+		},
+	} {
+		x := []float64{1, -2}
+		test.method.Init(2)
+		test.method.Update(x, g1, 0.1)
+		test.method.Update(x, g2, 0.1)
+		if !floats.EqualApprox(x, test.want, 1e-6) {
+			t.Errorf("%s: unexpected location: got %v, want %v", test.name, x, test.want)
+		}
+
+		// Init must reset the state of the method.
+		x = []float64{1, -2}
+		test.method.Init(2)
+		test.method.Update(x, g1, 0.1)
+		test.method.Update(x, g2, 0.1)
+		if !floats.EqualApprox(x, test.want, 1e-6) {
+			t.Errorf("%s: unexpected location after reinitialization: got %v, want %v", test.name, x, test.want)
+		}
+	}
+}
+
+func TestAdamW(t *testing.T) {
+	t.Parallel()
+	// AdamW shrinks the location before the Adam update, while Adam with
+	// weight decay adds the decay to the gradient.
+	grad := []float64{2, -4}
+	x := []float64{1, -2}
+	adamW := &AdamW{WeightDecay: 0.5}
+	adamW.Init(2)
+	adamW.Update(x, grad, 0.1)
+	want := []float64{0.95 - 0.1, -1.9 + 0.1}
+	if !floats.EqualApprox(x, want, 1e-6) {
+		t.Errorf("unexpected AdamW location: got %v, want %v", x, want)
+	}
+}
+
+func TestStochasticMethodPanics(t *testing.T) {
+	t.Parallel()
+	for _, method := range []StochasticMethod{
+		&SGD{Momentum: -0.1},
+		&SGD{Momentum: 1},
+		&SGD{WeightDecay: -1},
+		&Adam{Beta1: 1},
+		&Adam{Beta2: -0.5},
+		&Adam{Epsilon: -1},
+		&Adam{WeightDecay: -1},
+		&AdamW{WeightDecay: -1},
+		&AdamW{Beta1: 1},
+		&RMSProp{Decay: 1},
+		&RMSProp{Momentum: -0.1},
+		&RMSProp{Epsilon: -1},
+		&Adagrad{Epsilon: -1},
+	} {
+		if !panics(func() { method.Init(2) }) {
+			t.Errorf("%T%+v: expected panic", method, method)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"math"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+)
+
+var (
+	_ Method      = (*TrustRegion)(nil)
+	_ localMethod = (*TrustRegion)(nil)
+)
+
+// TrustRegion implements a trust-region Newton method for Hessian-based
+// unconstrained minimization, described in chapter 4 of
+//  Nocedal, J., Wright, S.: Numerical Optimization (2nd ed). Springer (2006)
+// In each iteration, TrustRegion minimizes the quadratic model
+//  m_k(p) = f_k + ∇f_kᵀp + ½ pᵀH_k p
+// of the objective function within a ball of radius Δ_k around the current
+// location x_k, where H_k is the Hessian of f at x_k. The step p_k is
+// accepted if the ratio
+//  ρ_k = (f(x_k) - f(x_k + p_k)) / (m_k(0) - m_k(p_k))
+// of the actual and the predicted reduction is larger than AcceptRatio, and
+// the radius is decreased if ρ_k is small and increased if ρ_k is large and
+// the step reached the boundary of the trust region.
+//
+// Unlike Newton, TrustRegion does not modify the Hessian when it is not
+// positive definite, since the subproblem is well defined for any
+// symmetric Hessian. The Hessian is only evaluated at accepted locations.
+type TrustRegion struct {
+	// Solver solves the trust-region subproblems. If Solver is nil, a
+	// default SteihaugCG is used.
+	Solver TrustRegionSolver
+	// InitRadius is the initial trust-region radius. If InitRadius is 0, a
+	// default value of 1 is used. InitRadius must not be negative, or
+	// TrustRegion will panic.
+	InitRadius float64
+	// MaxRadius is the largest trust-region radius. If MaxRadius is 0, the
+	// radius is not limited. MaxRadius must not be negative or smaller
+	// than InitRadius, or TrustRegion will panic.
+	MaxRadius float64
+	// AcceptRatio is the smallest ratio of the actual and the predicted
+	// reduction of the function for which a step is accepted. AcceptRatio
+	// must be in [0, 0.25), or TrustRegion will panic. The zero value
+	// accepts any step that decreases the function.
+	AcceptRatio float64
+	// GradStopThreshold sets the threshold for stopping if the gradient norm
+	// gets too small. If GradStopThreshold is 0 it is defaulted to 1e-12, and
+	// if it is NaN the setting is not used.
+	GradStopThreshold float64
+
+	status Status
+	err    error
+
+	solver    TrustRegionSolver
+	radius    float64
+	maxRadius float64
+
+	x    []float64     // Current location.
+	f    float64       // Function value at x.
+	grad []float64     // Gradient at x.
+	hess *mat.SymDense // Hessian at x.
+
+	step   []float64 // Trial step from x.
+	pred   float64   // Reduction of the model predicted for step.
+	lastOp Operation // Operation returned from the previous call to iterateLocal.
+}
+
+func (tr *TrustRegion) Status() (Status, error) {
+	return tr.status, tr.err
+}
+
+func (*TrustRegion) Uses(has Available) (uses Available, err error) {
+	return has.hessian()
+}
+
+func (tr *TrustRegion) Init(dim, tasks int) int {
+	tr.radius = tr.InitRadius
+	switch {
+	case tr.radius == 0:
+		tr.radius = 1
+	case tr.radius < 0:
+		panic("trust region: negative initial radius")
+	}
+	tr.maxRadius = tr.MaxRadius
+	switch {
+	case tr.maxRadius == 0:
+		tr.maxRadius = math.Inf(1)
+	case tr.maxRadius < tr.radius:
+		panic("trust region: maximum radius smaller than initial radius")
+	}
+	if tr.AcceptRatio < 0 || tr.AcceptRatio >= 0.25 {
+		panic("trust region: accept ratio out of range")
+	}
+	tr.solver = tr.Solver
+	if tr.solver == nil {
+		tr.solver = &SteihaugCG{}
+	}
+
+	tr.status = NotTerminated
+	tr.err = nil
+	return 1
+}
+
+func (tr *TrustRegion) Run(operation chan<- Task, result <-chan Task, tasks []Task) {
+	tr.status, tr.err = localOptimizer{}.run(tr, tr.GradStopThreshold, operation, result, tasks)
+	close(operation)
+}
+
+func (tr *TrustRegion) initLocal(loc *Location) (Operation, error) {
+	dim := len(loc.X)
+	tr.x = resize(tr.x, dim)
+	tr.grad = resize(tr.grad, dim)
+	tr.hess = resizeSymDense(tr.hess, dim)
+	tr.step = resize(tr.step, dim)
+	tr.update(loc)
+	return tr.nextStep(loc)
+}
+
+func (tr *TrustRegion) iterateLocal(loc *Location) (Operation, error) {
+	switch tr.lastOp {
+	case FuncEvaluation:
+		// The function has been evaluated at the trial location.
+		ratio := (tr.f - loc.F) / tr.pred
+		if math.IsNaN(ratio) {
+			ratio = math.Inf(-1)
+		}
+		if tol := 1e-12 * math.Abs(tr.f); tr.pred <= tol && loc.F <= tr.f+tol {
+			// The reductions are dominated by rounding errors, so accept
+			// the step if the function has not increased significantly.
+			ratio = 1
+		}
+		norm := floats.Norm(tr.step, 2)
+		switch {
+		case ratio < 0.25:
+			tr.radius = norm / 4
+		case ratio > 0.75 && norm >= 0.99*tr.radius:
+			tr.radius = math.Min(2*tr.radius, tr.maxRadius)
+		}
+		if ratio > tr.AcceptRatio {
+			tr.lastOp = GradEvaluation | HessEvaluation
+			return tr.lastOp, nil
+		}
+		// The step has been rejected, try a step within a smaller radius.
+		return tr.nextStep(loc)
+	case GradEvaluation | HessEvaluation:
+		// The derivatives at the accepted trial location have been
+		// evaluated.
+		tr.update(loc)
+		tr.lastOp = MajorIteration
+		return tr.lastOp, nil
+	case MajorIteration:
+		return tr.nextStep(loc)
+	default:
+		panic("trust region: unexpected operation")
+	}
+}
+
+// update stores the complete location loc as the current location.
+func (tr *TrustRegion) update(loc *Location) {
+	copy(tr.x, loc.X)
+	tr.f = loc.F
+	copy(tr.grad, loc.Gradient)
+	tr.hess.CopySym(loc.Hessian)
+}
+
+// nextStep computes the trial step from the current location and stores
+// the trial location in loc.X.
+func (tr *TrustRegion) nextStep(loc *Location) (Operation, error) {
+	tr.solver.Solve(tr.step, tr.grad, tr.hess, tr.radius)
+	tr.pred = -modelValue(tr.step, tr.grad, tr.hess)
+	tr.lastOp = NoOperation
+	if !(tr.pred > 0) {
+		// The model cannot be decreased, which can only be caused by
+		// rounding errors since the gradient is not zero.
+		return tr.lastOp, ErrTrustRegionFailure
+	}
+	floats.AddTo(loc.X, tr.x, tr.step)
+	if floats.Equal(loc.X, tr.x) {
+		// The step is so small that the trial location is indistinguishable
+		// from the current location due to rounding errors.
+		return tr.lastOp, ErrTrustRegionFailure
+	}
+	tr.lastOp = FuncEvaluation
+	return tr.lastOp, nil
+}
+
+func (*TrustRegion) needs() struct {
+	Gradient bool
+	Hessian  bool
+} {
+	return struct {
+		Gradient bool
+		Hessian  bool
+	}{true, true}
+}
+
+// modelValue returns the value gᵀp + ½ pᵀHp of the quadratic model with
+// the gradient g and the Hessian H at the step p.
+func modelValue(p, g []float64, h *mat.SymDense) float64 {
+	pv := mat.NewVecDense(len(p), p)
+	return floats.Dot(g, p) + 0.5*mat.Inner(pv, h, pv)
+}
@@ -0,0 +1,171 @@
+// Copyright ©2020 The Gonum Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package optimize
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"golang.org/x/exp/rand"
+
+	"gonum.org/v1/gonum/floats"
+	"gonum.org/v1/gonum/mat"
+	"gonum.org/v1/gonum/optimize/functions"
+)
+
+func TestTrustRegionSolvers(t *testing.T) {
+	t.Parallel()
+	rnd := rand.New(rand.NewSource(1))
+	for _, dim := range []int{1, 2, 5, 20} {
+		for _, shift := range []float64{-2, 0, 2} {
+			for _, radius := range []float64{1e-3, 0.1, 1, 100} {
+				// Generate a random symmetric matrix whose eigenvalues are
+				// shifted to make it definite or indefinite.
+				a := mat.NewDense(dim, dim, nil)
+				for i := 0; i < dim; i++ {
+					for j := 0; j < dim; j++ {
+						a.Set(i, j, rnd.NormFloat64())
+					}
+				}
+				hess := mat.NewSymDense(dim, nil)
+				hess.SymOuterK(1/float64(dim), a)
+				for i := 0; i < dim; i++ {
+					hess.SetSym(i, i, hess.At(i, i)+shift)
+				}
+				grad := make([]float64, dim)
+				for i := range grad {
+					grad[i] = rnd.NormFloat64()
+				}
+				// The model value at the Cauchy point, which every solver
+				// must improve on.
+				gNorm := floats.Norm(grad, 2)
+				gv := mat.NewVecDense(dim, grad)
+				tau := radius / gNorm
+				if gHg := mat.Inner(gv, hess, gv); gHg > 0 {
+					tau = math.Min(tau, gNorm*gNorm/gHg)
+				}
+				cauchy := make([]float64, dim)
+				floats.ScaleTo(cauchy, -tau, grad)
+				mc := modelValue(cauchy, grad, hess)
+
+				for _, solver := range []TrustRegionSolver{
+					&SteihaugCG{},
+					&MoreSorensen{},
+					&NewtonDogleg{},
+				} {
+					name := fmt.Sprintf("%T dim=%d shift=%v radius=%v", solver, dim, shift, radius)
+					step := make([]float64, dim)
+					solver.Solve(step, grad, hess, radius)
+					if norm := floats.Norm(step, 2); norm > radius*(1+1e-10) {
+						t.Errorf("%s: step outside trust region: |p| = %v", name, norm)
+					}
+					if m := modelValue(step, grad, hess); m > mc+1e-10*math.Abs(mc) {
+						t.Errorf("%s: model value %v larger than at Cauchy point %v", name, m, mc)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMoreSorensen(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		name   string
+		hess   []float64
+		grad   []float64
+		radius float64
+		want   []float64
+	}{
+		{
+			name:   "interior",
+			hess:   []float64{2, 0, 0, 4},
+			grad:   []float64{-2, 4},
+			radius: 10,
+			want:   []float64{1, -1},
+		},
+		{
+			name:   "boundary",
+			hess:   []float64{1, 0, 0, 1},
+			grad:   []float64{-3, -4},
+			radius: 1,
+			want:   []float64{0.6, 0.8},
+		},
+		{
+			// The gradient is orthogonal to the eigenvector of the
+			// negative eigenvalue. The solution has λ = 1 and the step is
+			// completed along the eigenvector to the boundary.
+			name:   "hard case",
+			hess:   []float64{-1, 0, 0, 2},
+			grad:   []float64{0, 1},
+			radius: 2,
+			want:   []float64{math.Sqrt(4 - 1.0/9), -1.0 / 3},
+		},
+	} {
+		hess := mat.NewSymDense(len(test.grad), test.hess)
+		step := make([]float64, len(test.grad))
+		(&MoreSorensen{Tolerance: 1e-8}).Solve(step, test.grad, hess, test.radius)
+		// The sign of the component along the eigenvector in the hard case
+		// is arbitrary.
+		if test.name == "hard case" {
+			step[0] = math.Abs(step[0])
+		}
+		if !floats.EqualApprox(step, test.want, 1e-6) {
+			t.Errorf("%s: unexpected step: got %v, want %v", test.name, step, test.want)
+		}
+	}
+}
+
+func TestNewtonDogleg(t *testing.T) {
+	t.Parallel()
+	hess := mat.NewSymDense(2, []float64{2, 1, 1, 2})
+	grad := []float64{-3, -3}
+	newton := []float64{1, 1}
+	dl := &NewtonDogleg{}
+	step := make([]float64, 2)
+
+	// The Newton step is inside the trust region.
+	dl.Solve(step, grad, hess, 2)
+	if !floats.EqualApprox(step, newton, 1e-14) {
+		t.Errorf("unexpected step inside trust region: got %v, want %v", step, newton)
+	}
+	// The Newton step is along the gradient, so the dogleg path is a
+	// straight line.
+	dl.Solve(step, grad, hess, 1)
+	want := []float64{1 / math.Sqrt2, 1 / math.Sqrt2}
+	if !floats.EqualApprox(step, want, 1e-14) {
+		t.Errorf("unexpected step on boundary: got %v, want %v", step, want)
+	}
+}
+
+func TestTrustRegionPanics(t *testing.T) {
+	t.Parallel()
+	p := Problem{
+		Func: functions.ExtendedRosenbrock{}.Func,
+		Grad: functions.ExtendedRosenbrock{}.Grad,
+		Hess: func(hess *mat.SymDense, x []float64) {
+			hess.SetSym(0, 0, 1)
+			hess.SetSym(1, 1, 1)
+		},
+	}
+	for _, method := range []*TrustRegion{
+		{InitRadius: -1},
+		{InitRadius: 2, MaxRadius: 1},
+		{AcceptRatio: -0.1},
+		{AcceptRatio: 0.25},
+	} {
+		if !panics(func() { Minimize(p, []float64{-1.2, 1}, nil, method) }) {
+			t.Errorf("%+v: expected panic", *method)
+		}
+	}
+	hess := mat.NewSymDense(2, []float64{1, 0, 0, 1})
+	for _, tol := range []float64{-0.1, 1} {
+		ms := &MoreSorensen{Tolerance: tol}
+		if !panics(func() { ms.Solve(make([]float64, 2), []float64{1, 1}, hess, 1) }) {
+			t.Errorf("Tolerance %v: expected panic", tol)
+		}
+	}
+}
@@ -136,6 +136,13 @@ type Problem struct {
 	// not able to evaluate itself. The user can use one of the pre-provided Status
 	// constants, or may call NewStatus to create a custom Status value.
 	Status func() (Status, error)
+
+	// Lower and Upper are the lower and upper bounds on the variables. If
+	// either is non-nil it must have the same length as the initial
+	// location, and infinite bounds are allowed. A nil slice means the
+	// variables are unbounded in that direction. Bound-constrained problems
+	// must be solved with a BoundedMethod.
+	Lower, Upper []float64
 }
 
 // Available describes the functions available to call in Problem.
@@ -211,7 +218,9 @@ type Settings struct {
 	//		Absolute: 1e-10,
 	//		Iterations: 100,
 	//  }
-	// will be used. NeverTerminated can be used to always return a
+	// will be used, unless the method supplies its own default, as
+	// SimulatedAnnealing does to stop only at its final temperature.
+	// NeverTerminated can be used to always return a
 	// NotTerminated status.
 	Converger Converger
 
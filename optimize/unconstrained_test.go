@@ -980,6 +980,75 @@ var newtonTests = []unconstrainedTest{
 	},
 }
 
+var newtonCGTests = []unconstrainedTest{
+	{
+		name: "Beale",
+		p: Problem{
+			Func: functions.Beale{}.Func,
+			Grad: functions.Beale{}.Grad,
+			Hess: functions.Beale{}.Hess,
+		},
+		x: []float64{1, 1},
+	},
+	{
+		name: "BrownAndDennis",
+		p: Problem{
+			Func: functions.BrownAndDennis{}.Func,
+			Grad: functions.BrownAndDennis{}.Grad,
+			Hess: functions.BrownAndDennis{}.Hess,
+		},
+		x:       []float64{25, 5, -5, -1},
+		gradTol: 1e-7,
+	},
+	{
+		name: "BrownBadlyScaled",
+		p: Problem{
+			Func: functions.BrownBadlyScaled{}.Func,
+			Grad: functions.BrownBadlyScaled{}.Grad,
+			Hess: functions.BrownBadlyScaled{}.Hess,
+		},
+		x:       []float64{1, 1},
+		gradTol: 1e-9,
+	},
+	{
+		name: "PowellBadlyScaled",
+		p: Problem{
+			Func: functions.PowellBadlyScaled{}.Func,
+			Grad: functions.PowellBadlyScaled{}.Grad,
+			Hess: functions.PowellBadlyScaled{}.Hess,
+		},
+		x:       []float64{0, 1},
+		gradTol: 1e-10,
+	},
+	{
+		name: "Watson",
+		p: Problem{
+			Func: functions.Watson{}.Func,
+			Grad: functions.Watson{}.Grad,
+			Hess: functions.Watson{}.Hess,
+		},
+		x: []float64{0, 0, 0, 0, 0, 0},
+	},
+	{
+		name: "Watson",
+		p: Problem{
+			Func: functions.Watson{}.Func,
+			Grad: functions.Watson{}.Grad,
+			Hess: functions.Watson{}.Hess,
+		},
+		x: []float64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+	},
+	{
+		name: "Wood",
+		p: Problem{
+			Func: functions.Wood{}.Func,
+			Grad: functions.Wood{}.Grad,
+			Hess: functions.Wood{}.Hess,
+		},
+		x: []float64{-3, -1, -3, -1},
+	},
+}
+
 func newVariablyDimensioned(dim int, gradTol float64) unconstrainedTest {
 	x := make([]float64, dim)
 	for i := range x {
@@ -1153,10 +1222,53 @@ func TestLBFGS(t *testing.T) {
 	testLocal(t, tests, &LBFGS{})
 }
 
+func TestLBFGSB(t *testing.T) {
+	var tests []unconstrainedTest
+	tests = append(tests, gradientDescentTests...)
+	tests = append(tests, lbfgsTests...)
+	testLocal(t, tests, &LBFGSB{})
+}
+
+func TestProjectedGradient(t *testing.T) {
+	testLocal(t, gradientDescentTests, &ProjectedGradient{})
+}
+
 func TestNewton(t *testing.T) {
 	testLocal(t, newtonTests, &Newton{})
 }
 
+func TestTrustRegionSteihaugCG(t *testing.T) {
+	testLocal(t, newtonTests, &TrustRegion{Solver: &SteihaugCG{}})
+}
+
+func TestTrustRegionMoreSorensen(t *testing.T) {
+	testLocal(t, newtonTests, &TrustRegion{Solver: &MoreSorensen{}})
+}
+
+func TestTrustRegionNewtonDogleg(t *testing.T) {
+	testLocal(t, newtonTests, &TrustRegion{Solver: &NewtonDogleg{}})
+}
+
+func TestNewtonCG(t *testing.T) {
+	testLocal(t, newtonCGTests, &NewtonCG{})
+}
+
+func TestNewtonCGHessVec(t *testing.T) {
+	// Replace the Hessian of the problems by Hessian-vector products.
+	tests := make([]unconstrainedTest, len(newtonCGTests))
+	for i, test := range newtonCGTests {
+		hess := test.p.Hess
+		test.p.Hess = nil
+		tests[i] = test
+		hessVec := func(dst, x, v []float64) {
+			h := mat.NewSymDense(len(x), nil)
+			hess(h, x)
+			mat.NewVecDense(len(dst), dst).MulVec(h, mat.NewVecDense(len(v), v))
+		}
+		testLocal(t, tests[i:i+1], &NewtonCG{HessVec: hessVec})
+	}
+}
+
 func testLocal(t *testing.T, tests []unconstrainedTest, method Method) {
 	for cas, test := range tests {
 		if test.long && testing.Short() {